@@ -126,6 +126,15 @@ func NewWithBeadsDir(workDir, beadsDir string) *Beads {
 	return &Beads{workDir: workDir, beadsDir: beadsDir}
 }
 
+// resolvedBeadsDir returns the explicit beads directory if set, otherwise
+// the one resolved from the working directory.
+func (b *Beads) resolvedBeadsDir() string {
+	if b.beadsDir != "" {
+		return b.beadsDir
+	}
+	return ResolveBeadsDir(b.workDir)
+}
+
 // run executes a bd command and returns stdout.
 func (b *Beads) run(args ...string) ([]byte, error) {
 	// Use --no-daemon for faster read operations (avoids daemon IPC overhead)
@@ -139,11 +148,7 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 	// Always explicitly set BEADS_DIR to prevent inherited env vars from
 	// causing prefix mismatches. Use explicit beadsDir if set, otherwise
 	// resolve from working directory.
-	beadsDir := b.beadsDir
-	if beadsDir == "" {
-		beadsDir = ResolveBeadsDir(b.workDir)
-	}
-	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
+	cmd.Env = append(os.Environ(), "BEADS_DIR="+b.resolvedBeadsDir())
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -161,6 +166,7 @@ func (b *Beads) run(args ...string) ([]byte, error) {
 		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
 	}
 
+	b.recordWrittenChecksums(args, stdout.Bytes())
 	return stdout.Bytes(), nil
 }
 
@@ -548,6 +554,21 @@ func (b *Beads) CloseWithReason(reason string, ids ...string) error {
 	return nil
 }
 
+// Delete deletes one or more issues without prompting for confirmation.
+func (b *Beads) Delete(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := append([]string{"delete"}, ids...)
+	args = append(args, "--force")
+
+	if _, err := b.run(args...); err != nil {
+		return fmt.Errorf("deleting bead: %w", err)
+	}
+	return nil
+}
+
 // Release moves an in_progress issue back to open status.
 // This is used to recover stuck steps when a worker dies mid-task.
 // It clears the assignee so the step can be claimed by another worker.
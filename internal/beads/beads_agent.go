@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"unicode/utf8"
 )
 
 // AgentFields holds structured fields for agent beads.
@@ -20,6 +21,25 @@ type AgentFields struct {
 	CleanupStatus     string // ZFC: polecat self-reports git state (clean, has_uncommitted, has_stash, has_unpushed)
 	ActiveMR          string // Currently active merge request bead ID (for traceability)
 	NotificationLevel string // DND mode: verbose, normal, muted (default: normal)
+	WorkSummary       string // One line on what the agent is doing now, for humans (max MaxWorkSummaryLen chars)
+}
+
+// MaxWorkSummaryLen is the longest WorkSummary, in characters.
+const MaxWorkSummaryLen = 256
+
+// ValidateAgentFields checks that fields can be stored in an agent bead's
+// description and read back unchanged.
+func ValidateAgentFields(fields *AgentFields) error {
+	if fields == nil {
+		return nil
+	}
+	if n := utf8.RuneCountInString(fields.WorkSummary); n > MaxWorkSummaryLen {
+		return fmt.Errorf("work summary is %d characters; the limit is %d", n, MaxWorkSummaryLen)
+	}
+	if strings.ContainsAny(fields.WorkSummary, "\r\n") {
+		return fmt.Errorf("work summary must be a single line")
+	}
+	return nil
 }
 
 // Notification level constants
@@ -78,6 +98,12 @@ func FormatAgentDescription(title string, fields *AgentFields) string {
 		lines = append(lines, "notification_level: null")
 	}
 
+	if fields.WorkSummary != "" {
+		lines = append(lines, fmt.Sprintf("work_summary: %s", fields.WorkSummary))
+	} else {
+		lines = append(lines, "work_summary: null")
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -119,6 +145,8 @@ func ParseAgentFields(description string) *AgentFields {
 			fields.ActiveMR = value
 		case "notification_level":
 			fields.NotificationLevel = value
+		case "work_summary":
+			fields.WorkSummary = value
 		}
 	}
 
@@ -130,6 +158,9 @@ func ParseAgentFields(description string) *AgentFields {
 // Use AgentBeadID() helper to generate correct IDs.
 // The created_by field is populated from BD_ACTOR env var for provenance tracking.
 func (b *Beads) CreateAgentBead(id, title string, fields *AgentFields) (*Issue, error) {
+	if err := ValidateAgentFields(fields); err != nil {
+		return nil, err
+	}
 	description := FormatAgentDescription(title, fields)
 
 	args := []string{"create", "--json",
@@ -389,6 +420,29 @@ func (b *Beads) UpdateAgentNotificationLevel(id string, level string) error {
 	return b.Update(id, UpdateOptions{Description: &description})
 }
 
+// UpdateAgentWorkSummary updates the work_summary field in an agent bead.
+// Pass empty string to clear it.
+func (b *Beads) UpdateAgentWorkSummary(id string, summary string) error {
+	if err := ValidateAgentFields(&AgentFields{WorkSummary: summary}); err != nil {
+		return err
+	}
+
+	// First get current issue to preserve other fields
+	issue, err := b.Show(id)
+	if err != nil {
+		return fmt.Errorf("getting agent bead %s: %w", id, err)
+	}
+
+	// Parse existing fields
+	fields := ParseAgentFields(issue.Description)
+	fields.WorkSummary = summary
+
+	// Format new description
+	description := FormatAgentDescription(issue.Title, fields)
+
+	return b.Update(id, UpdateOptions{Description: &description})
+}
+
 // GetAgentNotificationLevel returns the notification level for an agent.
 // Returns "normal" if not set (the default).
 func (b *Beads) GetAgentNotificationLevel(id string) (string, error) {
@@ -59,6 +59,7 @@ func TestFormatAgentDescription(t *testing.T) {
 			CleanupStatus:     "clean",
 			ActiveMR:          "mr-456",
 			NotificationLevel: "verbose",
+			WorkSummary:       "Fixing the flaky merge queue test",
 		}
 		result := FormatAgentDescription("Toast", fields)
 
@@ -72,6 +73,7 @@ func TestFormatAgentDescription(t *testing.T) {
 			"cleanup_status: clean",
 			"active_mr: mr-456",
 			"notification_level: verbose",
+			"work_summary: Fixing the flaky merge queue test",
 		}
 
 		for _, check := range checks {
@@ -244,6 +246,7 @@ func TestFormatAndParseAgentFieldsRoundTrip(t *testing.T) {
 		CleanupStatus:     "has_uncommitted",
 		ActiveMR:          "mr-456",
 		NotificationLevel: "muted",
+		WorkSummary:       "Rebasing onto main: 3 conflicts left",
 	}
 
 	formatted := FormatAgentDescription("Toast", original)
@@ -273,6 +276,51 @@ func TestFormatAndParseAgentFieldsRoundTrip(t *testing.T) {
 	if parsed.NotificationLevel != original.NotificationLevel {
 		t.Errorf("NotificationLevel mismatch: got %q, want %q", parsed.NotificationLevel, original.NotificationLevel)
 	}
+	if parsed.WorkSummary != original.WorkSummary {
+		t.Errorf("WorkSummary mismatch: got %q, want %q", parsed.WorkSummary, original.WorkSummary)
+	}
+}
+
+func TestAgentFieldsWorkSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"short", "Reviewing the refinery backlog", false},
+		{"at limit", strings.Repeat("a", MaxWorkSummaryLen), false},
+		{"at limit in multibyte characters", strings.Repeat("é", MaxWorkSummaryLen), false},
+		{"over limit", strings.Repeat("a", MaxWorkSummaryLen+1), true},
+		{"newline", "line one\nline two", true},
+		{"carriage return", "line one\rline two", true},
+		{"trailing newline", "done\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := &AgentFields{RoleType: "polecat", WorkSummary: tt.summary}
+			err := ValidateAgentFields(fields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateAgentFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				// Invalid fields are refused before bd is run.
+				if _, err := New(t.TempDir()).CreateAgentBead("gt-rig-polecat-Toast", "Toast", fields); err == nil {
+					t.Error("CreateAgentBead() accepted an invalid work summary")
+				}
+				if err := New(t.TempDir()).UpdateAgentWorkSummary("gt-rig-polecat-Toast", tt.summary); err == nil {
+					t.Error("UpdateAgentWorkSummary() accepted an invalid work summary")
+				}
+				return
+			}
+
+			parsed := ParseAgentFields(FormatAgentDescription("Toast", fields))
+			if parsed.WorkSummary != tt.summary {
+				t.Errorf("WorkSummary round trip = %q, want %q", parsed.WorkSummary, tt.summary)
+			}
+		})
+	}
 }
 
 func TestAgentFieldsEmptyRoundTrip(t *testing.T) {
@@ -308,6 +356,9 @@ func TestAgentFieldsEmptyRoundTrip(t *testing.T) {
 	if parsed.NotificationLevel != "" {
 		t.Errorf("NotificationLevel should be empty, got %q", parsed.NotificationLevel)
 	}
+	if parsed.WorkSummary != "" {
+		t.Errorf("WorkSummary should be empty, got %q", parsed.WorkSummary)
+	}
 }
 
 func TestAgentFieldsRoleTypes(t *testing.T) {
@@ -2,9 +2,11 @@
 package beads
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Delegation represents a work delegation relationship between work units.
@@ -45,6 +47,57 @@ type DelegationTerms struct {
 
 	// CreditShare is the percentage of credit that flows to the delegate (0-100)
 	CreditShare int `json:"credit_share,omitempty"`
+
+	// MilestoneList breaks the delegated work into sub-goals, each marked
+	// complete with MarkMilestone
+	MilestoneList []Milestone `json:"milestones,omitempty"`
+}
+
+// Milestone is a named sub-goal within a delegation.
+type Milestone struct {
+	// Name identifies the milestone within its delegation
+	Name string `json:"name"`
+
+	// CompletedAt is when the milestone was completed (RFC 3339), empty until then
+	CompletedAt string `json:"completed_at,omitempty"`
+
+	// CompletedBy is the entity that completed the milestone
+	CompletedBy string `json:"completed_by,omitempty"`
+}
+
+// MilestonesComplete reports whether every milestone in terms has been
+// completed. Terms with no milestones are trivially complete.
+func MilestonesComplete(terms *DelegationTerms) bool {
+	if terms == nil {
+		return true
+	}
+	for _, m := range terms.MilestoneList {
+		if m.CompletedAt == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// completeMilestone marks the milestone named name in terms completed by
+// completedBy at the given time.
+func completeMilestone(terms *DelegationTerms, name, completedBy string, at time.Time) error {
+	if terms == nil {
+		return fmt.Errorf("milestone %q not found: delegation has no terms", name)
+	}
+	for i := range terms.MilestoneList {
+		m := &terms.MilestoneList[i]
+		if m.Name != name {
+			continue
+		}
+		if m.CompletedAt != "" {
+			return fmt.Errorf("milestone %q already completed by %s at %s", name, m.CompletedBy, m.CompletedAt)
+		}
+		m.CompletedAt = at.UTC().Format(time.RFC3339)
+		m.CompletedBy = completedBy
+		return nil
+	}
+	return fmt.Errorf("milestone %q not found", name)
 }
 
 // AddDelegation creates a delegation relationship from parent to child work unit.
@@ -62,16 +115,8 @@ func (b *Beads) AddDelegation(d *Delegation) error {
 		return fmt.Errorf("delegation requires both delegated_by and delegated_to entities")
 	}
 
-	// Store delegation as JSON in the child issue's delegated_from slot
-	delegationJSON, err := json.Marshal(d)
-	if err != nil {
-		return fmt.Errorf("marshaling delegation: %w", err)
-	}
-
-	// Set the delegated_from slot on the child issue
-	_, err = b.run("slot", "set", d.Child, "delegated_from", string(delegationJSON))
-	if err != nil {
-		return fmt.Errorf("setting delegation slot: %w", err)
+	if err := b.setDelegation(d); err != nil {
+		return err
 	}
 
 	// Also add a dependency so child blocks parent (work must complete before parent can close)
@@ -83,20 +128,94 @@ func (b *Beads) AddDelegation(d *Delegation) error {
 	return nil
 }
 
-// RemoveDelegation removes a delegation relationship.
-func (b *Beads) RemoveDelegation(parent, child string) error {
-	// Clear the delegated_from slot on the child
-	_, err := b.run("slot", "clear", child, "delegated_from")
+// setDelegation stores a delegation as JSON in the child issue's
+// delegated_from slot.
+func (b *Beads) setDelegation(d *Delegation) error {
+	delegationJSON, err := json.Marshal(d)
 	if err != nil {
-		return fmt.Errorf("clearing delegation slot: %w", err)
+		return fmt.Errorf("marshaling delegation: %w", err)
+	}
+	if _, err := b.run("slot", "set", d.Child, "delegated_from", string(delegationJSON)); err != nil {
+		return fmt.Errorf("setting delegation slot: %w", err)
+	}
+	return nil
+}
+
+// MarkMilestone marks a milestone of the delegation to child work unit as
+// completed now by completedBy, and writes the child bead back to store
+// with a new updated_at. It is an error if the bead has no delegation, or
+// the delegation has no such milestone or it is already complete.
+func MarkMilestone(store *Store, child, milestoneName, completedBy string) error {
+	data, err := store.Backend().Read(child)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", child, err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing %s: %w", child, err)
+	}
+
+	// The slot is a field of the bead or under "slots", as in issues.jsonl.
+	var slots map[string]json.RawMessage
+	raw, inSlots := doc["delegated_from"], false
+	if len(raw) == 0 && len(doc["slots"]) > 0 {
+		if err := json.Unmarshal(doc["slots"], &slots); err != nil {
+			return fmt.Errorf("parsing slots of %s: %w", child, err)
+		}
+		raw, inSlots = slots["delegated_from"], true
+	}
+	d := decodeDelegation(raw)
+	if d == nil {
+		return fmt.Errorf("%s has no delegation", child)
+	}
+
+	now := time.Now()
+	if err := completeMilestone(d.Terms, milestoneName, completedBy, now); err != nil {
+		return fmt.Errorf("delegation to %s: %w", child, err)
+	}
+	updated, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshaling delegation: %w", err)
+	}
+	if raw = bytes.TrimSpace(raw); raw[0] == '"' {
+		// Keep the slot a string, as bd slot set stores it
+		if updated, err = json.Marshal(string(updated)); err != nil {
+			return fmt.Errorf("marshaling delegation: %w", err)
+		}
+	}
+	if inSlots {
+		slots["delegated_from"] = updated
+		if doc["slots"], err = json.Marshal(slots); err != nil {
+			return fmt.Errorf("marshaling slots: %w", err)
+		}
+	} else {
+		doc["delegated_from"] = updated
+	}
+	if doc["updated_at"], err = json.Marshal(now.UTC().Format(time.RFC3339)); err != nil {
+		return err
 	}
 
-	// Also remove the blocking dependency
-	if err := b.RemoveDependency(parent, child); err != nil {
-		// Log but don't fail
-		fmt.Printf("Warning: could not remove blocking dependency: %v\n", err)
+	if data, err = json.Marshal(doc); err != nil {
+		return fmt.Errorf("marshaling %s: %w", child, err)
 	}
+	return store.Backend().Write(child, data)
+}
 
+// RemoveDelegation removes a delegation relationship: the child's
+// delegated_from slot, then the blocking dependency. If only the dependency
+// can't be removed, the delegation is already gone when the error returns.
+func (b *Beads) RemoveDelegation(parent, child string) error {
+	if err := b.clearDelegation(child); err != nil {
+		return err
+	}
+	return b.RemoveDependency(parent, child)
+}
+
+// clearDelegation clears the delegated_from slot on the child.
+func (b *Beads) clearDelegation(child string) error {
+	if _, err := b.run("slot", "clear", child, "delegated_from"); err != nil {
+		return fmt.Errorf("clearing delegation slot: %w", err)
+	}
 	return nil
 }
 
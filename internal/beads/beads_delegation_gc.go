@@ -0,0 +1,239 @@
+package beads
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// DefaultDelegationGCThreshold is the number of delegations above which
+// GarbageCollectIfNeeded runs a collection pass.
+const DefaultDelegationGCThreshold = 1000
+
+// GCOptions controls a delegation garbage collection pass.
+type GCOptions struct {
+	// DryRun reports orphaned delegations without removing them.
+	DryRun bool
+
+	// Actor is recorded on emitted bead_transition events (defaults to "gt").
+	Actor string
+}
+
+// GCResult describes the outcome of a delegation garbage collection pass.
+type GCResult struct {
+	// Scanned is the number of delegations examined.
+	Scanned int `json:"scanned"`
+
+	// Orphaned lists delegations whose parent or child no longer exists.
+	// In dry-run mode these were reported but not removed.
+	Orphaned []*Delegation `json:"orphaned"`
+
+	// Collected is the number of delegations actually removed.
+	Collected int `json:"collected"`
+
+	// Warnings lists problems that didn't stop a delegation being
+	// removed, such as a blocking dependency bd couldn't remove.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// delegationRecord pairs a delegation with the issue whose slot stores it.
+type delegationRecord struct {
+	host       string
+	delegation *Delegation
+}
+
+// GarbageCollect removes delegations whose parent or child work unit no
+// longer exists. Returns the number of delegations removed.
+func (b *Beads) GarbageCollect() (collected int, err error) {
+	result, err := b.GarbageCollectWithOptions(GCOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return result.Collected, nil
+}
+
+// GarbageCollectWithOptions scans every delegation in the store and removes
+// (or, with DryRun, reports) the ones left orphaned by a deleted parent or
+// child. One bead_transition event is emitted per removed delegation.
+func (b *Beads) GarbageCollectWithOptions(opts GCOptions) (*GCResult, error) {
+	records, err := b.scanDelegations()
+	if err != nil {
+		return nil, err
+	}
+	existing, err := b.existingIssues()
+	if err != nil {
+		return nil, err
+	}
+	return b.collectOrphans(records, existing, opts)
+}
+
+// GarbageCollectIfNeeded runs a collection pass only when the store holds more
+// than threshold delegations. A threshold <= 0 uses DefaultDelegationGCThreshold.
+// Returns an empty result, without asking bd anything, if the threshold was
+// not reached.
+func (b *Beads) GarbageCollectIfNeeded(threshold int) (*GCResult, error) {
+	if threshold <= 0 {
+		threshold = DefaultDelegationGCThreshold
+	}
+
+	records, err := b.scanDelegations()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) <= threshold {
+		return &GCResult{Scanned: len(records)}, nil
+	}
+
+	existing, err := b.existingIssues()
+	if err != nil {
+		return nil, err
+	}
+	return b.collectOrphans(records, existing, GCOptions{})
+}
+
+// delegationLine is the part of an issues.jsonl line scanDelegations reads.
+// bd exports a slot either as its own field or under "slots".
+type delegationLine struct {
+	ID            string                     `json:"id"`
+	DelegatedFrom json.RawMessage            `json:"delegated_from"`
+	Slots         map[string]json.RawMessage `json:"slots"`
+}
+
+// scanDelegations reads the delegation records from the store's
+// issues.jsonl, without going through bd. A store with no issues.jsonl has
+// none.
+func (b *Beads) scanDelegations() ([]delegationRecord, error) {
+	data, err := os.ReadFile(b.jsonlPath()) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading issues.jsonl: %w", err)
+	}
+
+	var records []delegationRecord
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if !bytes.Contains(line, []byte("delegated_from")) {
+			continue
+		}
+		var rec delegationLine
+		if err := json.Unmarshal(line, &rec); err != nil || rec.ID == "" {
+			continue
+		}
+		raw := rec.DelegatedFrom
+		if len(raw) == 0 {
+			raw = rec.Slots["delegated_from"]
+		}
+		if d := decodeDelegation(raw); d != nil {
+			records = append(records, delegationRecord{host: rec.ID, delegation: d})
+		}
+	}
+	return records, nil
+}
+
+// decodeDelegation parses an exported delegated_from slot, which holds the
+// delegation's JSON either as an object or as a string. Returns nil for an
+// empty or unreadable slot.
+func decodeDelegation(raw json.RawMessage) *Delegation {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil
+		}
+		raw = []byte(strings.TrimSpace(s))
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var d Delegation
+	if err := json.Unmarshal(raw, &d); err != nil || d.Parent == "" || d.Child == "" {
+		return nil
+	}
+	return &d
+}
+
+// existingIssues returns the IDs of every issue bd knows, in one bd call,
+// for checking the ends of all the delegations at once.
+func (b *Beads) existingIssues() (map[string]bool, error) {
+	issues, err := b.List(ListOptions{Status: "all", Priority: -1})
+	if err != nil {
+		return nil, fmt.Errorf("listing issues: %w", err)
+	}
+	existing := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		if issue.Status != "tombstone" {
+			existing[issue.ID] = true
+		}
+	}
+	return existing, nil
+}
+
+// collectOrphans removes the orphaned delegations among records.
+func (b *Beads) collectOrphans(records []delegationRecord, existing map[string]bool, opts GCOptions) (*GCResult, error) {
+	result := &GCResult{Scanned: len(records)}
+	orphans := findOrphanedDelegations(records, existing)
+	for _, rec := range orphans {
+		result.Orphaned = append(result.Orphaned, rec.delegation)
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	actor := opts.Actor
+	if actor == "" {
+		actor = "gt"
+	}
+
+	for _, rec := range orphans {
+		d := rec.delegation
+		// A deleted child took its delegated_from slot with it.
+		if existing[rec.host] {
+			if err := b.clearDelegation(rec.host); err != nil {
+				return result, fmt.Errorf("removing delegation %s -> %s: %w", d.Parent, d.Child, err)
+			}
+		}
+		if existing[d.Parent] && existing[d.Child] {
+			if err := b.RemoveDependency(d.Parent, d.Child); err != nil {
+				result.Warnings = append(result.Warnings, err.Error())
+			}
+		}
+		result.Collected++
+
+		_ = events.LogAudit(events.TypeBeadTransition, actor,
+			events.BeadTransitionPayload(rec.host, "delegated", "collected", orphanReason(d, existing)))
+	}
+
+	return result, nil
+}
+
+// findOrphanedDelegations returns the records whose parent or child ID is
+// not present in existing.
+func findOrphanedDelegations(records []delegationRecord, existing map[string]bool) []delegationRecord {
+	var orphans []delegationRecord
+	for _, rec := range records {
+		if orphanReason(rec.delegation, existing) != "" {
+			orphans = append(orphans, rec)
+		}
+	}
+	return orphans
+}
+
+// orphanReason explains why a delegation is orphaned, or returns "" if both
+// ends still exist.
+func orphanReason(d *Delegation, existing map[string]bool) string {
+	switch {
+	case !existing[d.Parent] && !existing[d.Child]:
+		return fmt.Sprintf("parent %s and child %s no longer exist", d.Parent, d.Child)
+	case !existing[d.Parent]:
+		return fmt.Sprintf("parent %s no longer exists", d.Parent)
+	case !existing[d.Child]:
+		return fmt.Sprintf("child %s no longer exists", d.Child)
+	default:
+		return ""
+	}
+}
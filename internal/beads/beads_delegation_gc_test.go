@@ -0,0 +1,161 @@
+package beads
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindOrphanedDelegations(t *testing.T) {
+	existing := map[string]bool{
+		"gt-parent": true,
+		"gt-child1": true,
+		"gt-child2": true,
+		"gt-child3": true,
+	}
+	records := []delegationRecord{
+		{host: "gt-child1", delegation: &Delegation{Parent: "gt-parent", Child: "gt-child1"}},
+		{host: "gt-child2", delegation: &Delegation{Parent: "gt-deleted", Child: "gt-child2"}},
+		{host: "gt-child3", delegation: &Delegation{Parent: "gt-parent", Child: "gt-gone"}},
+	}
+
+	orphans := findOrphanedDelegations(records, existing)
+	if len(orphans) != 2 {
+		t.Fatalf("expected 2 orphans, got %d", len(orphans))
+	}
+	if orphans[0].host != "gt-child2" || orphans[1].host != "gt-child3" {
+		t.Errorf("unexpected orphans: %s, %s", orphans[0].host, orphans[1].host)
+	}
+}
+
+func TestOrphanReason(t *testing.T) {
+	existing := map[string]bool{"a": true, "b": true}
+	tests := []struct {
+		parent, child string
+		want          string
+	}{
+		{"a", "b", ""},
+		{"x", "b", "parent x no longer exists"},
+		{"a", "y", "child y no longer exists"},
+		{"x", "y", "parent x and child y no longer exist"},
+	}
+	for _, tt := range tests {
+		got := orphanReason(&Delegation{Parent: tt.parent, Child: tt.child}, existing)
+		if got != tt.want {
+			t.Errorf("orphanReason(%s, %s) = %q, want %q", tt.parent, tt.child, got, tt.want)
+		}
+	}
+}
+
+func TestScanDelegations(t *testing.T) {
+	beadsDir := t.TempDir()
+	b := NewWithBeadsDir(beadsDir, beadsDir)
+
+	if records, err := b.scanDelegations(); err != nil || len(records) != 0 {
+		t.Fatalf("scanDelegations() with no issues.jsonl = %v, %v; want none", records, err)
+	}
+
+	writeIssuesJSONL(t, beadsDir, `{"id":"gt-parent","title":"Parent"}
+{"id":"gt-child1","delegated_from":"{\"parent\":\"gt-parent\",\"child\":\"gt-child1\"}"}
+{"id":"gt-child2","slots":{"delegated_from":{"parent":"gt-gone","child":"gt-child2"}}}
+{"id":"gt-child3","delegated_from":""}
+{"id":"gt-child4","delegated_from":"not json"}
+`)
+	records, err := b.scanDelegations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("scanDelegations() found %d delegations, want 2: %+v", len(records), records)
+	}
+	if records[0].host != "gt-child1" || records[0].delegation.Parent != "gt-parent" {
+		t.Errorf("records[0] = %s %+v", records[0].host, records[0].delegation)
+	}
+	if records[1].host != "gt-child2" || records[1].delegation.Parent != "gt-gone" {
+		t.Errorf("records[1] = %s %+v", records[1].host, records[1].delegation)
+	}
+
+	// bd no longer has gt-child1, though the export still does.
+	existing := map[string]bool{"gt-parent": true, "gt-child2": true}
+	orphans := findOrphanedDelegations(records, existing)
+	if len(orphans) != 2 {
+		t.Fatalf("expected both delegations orphaned, got %d", len(orphans))
+	}
+	if got := orphanReason(orphans[0].delegation, existing); got != "child gt-child1 no longer exists" {
+		t.Errorf("orphanReason(gt-child1) = %q", got)
+	}
+}
+
+// TestGarbageCollect_Integration exercises GC against a real temp store.
+func TestGarbageCollect_Integration(t *testing.T) {
+	if _, err := exec.LookPath("bd"); err != nil {
+		t.Skip("bd not installed")
+	}
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	tmpDir := t.TempDir()
+	cmd := exec.Command("bd", "--no-daemon", "init", "--prefix", "test", "--quiet")
+	cmd.Dir = tmpDir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("bd init: %v\n%s", err, output)
+	}
+	bd := New(filepath.Join(tmpDir, ".beads"))
+
+	parent, err := bd.Create(CreateOptions{Title: "Parent", Type: "task", Priority: 2})
+	if err != nil {
+		t.Fatalf("create parent: %v", err)
+	}
+	kept, err := bd.Create(CreateOptions{Title: "Kept parent", Type: "task", Priority: 2})
+	if err != nil {
+		t.Fatalf("create kept parent: %v", err)
+	}
+	orphan, err := bd.Create(CreateOptions{Title: "Orphan child", Type: "task", Priority: 2})
+	if err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+	healthy, err := bd.Create(CreateOptions{Title: "Healthy child", Type: "task", Priority: 2})
+	if err != nil {
+		t.Fatalf("create child: %v", err)
+	}
+
+	for _, d := range []*Delegation{
+		{Parent: parent.ID, Child: orphan.ID, DelegatedBy: "mayor", DelegatedTo: "polecat"},
+		{Parent: kept.ID, Child: healthy.ID, DelegatedBy: "mayor", DelegatedTo: "polecat"},
+	} {
+		if err := bd.AddDelegation(d); err != nil {
+			t.Fatalf("AddDelegation: %v", err)
+		}
+	}
+
+	if _, err := bd.run("delete", parent.ID, "--force"); err != nil {
+		t.Fatalf("delete parent: %v", err)
+	}
+
+	dry, err := bd.GarbageCollectWithOptions(GCOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(dry.Orphaned) != 1 || dry.Collected != 0 {
+		t.Fatalf("dry run: orphaned=%d collected=%d, want 1/0", len(dry.Orphaned), dry.Collected)
+	}
+
+	collected, err := bd.GarbageCollect()
+	if err != nil {
+		t.Fatalf("GarbageCollect: %v", err)
+	}
+	if collected != 1 {
+		t.Errorf("collected = %d, want 1", collected)
+	}
+
+	if d, err := bd.GetDelegation(orphan.ID); err != nil && !strings.Contains(err.Error(), "not found") {
+		t.Errorf("GetDelegation(orphan): %v", err)
+	} else if d != nil {
+		t.Error("orphaned delegation should have been removed")
+	}
+	if d, err := bd.GetDelegation(healthy.ID); err != nil || d == nil {
+		t.Errorf("healthy delegation should survive GC (d=%v, err=%v)", d, err)
+	}
+}
@@ -2,7 +2,11 @@ package beads
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDelegationStructFields(t *testing.T) {
@@ -316,3 +320,142 @@ func TestDelegationRoundTrip(t *testing.T) {
 		t.Errorf("Terms.CreditShare mismatch: got %d, want %d", decoded.Terms.CreditShare, original.Terms.CreditShare)
 	}
 }
+
+func TestDelegationMilestonesRoundTrip(t *testing.T) {
+	original := DelegationTerms{
+		Portion: "auth rewrite",
+		MilestoneList: []Milestone{
+			{Name: "schema", CompletedAt: "2024-01-16T09:00:00Z", CompletedBy: "gongshow/polecats/Toast"},
+			{Name: "api"},
+		},
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("json.Marshal error: %v", err)
+	}
+	if !strings.Contains(string(data), `"milestones":[{"name":"schema"`) || !strings.Contains(string(data), `{"name":"api"}`) {
+		t.Errorf("JSON = %s, want milestones with empty completion fields omitted", data)
+	}
+
+	var decoded DelegationTerms
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("round trip = %+v, want %+v", decoded, original)
+	}
+
+	// Terms without milestones leave them out.
+	data, _ = json.Marshal(DelegationTerms{Portion: "docs"})
+	if strings.Contains(string(data), "milestones") {
+		t.Errorf("JSON = %s, want milestones omitted", data)
+	}
+}
+
+func TestCompleteMilestone(t *testing.T) {
+	terms := &DelegationTerms{MilestoneList: []Milestone{{Name: "schema"}, {Name: "api"}}}
+	at := time.Date(2024, 1, 16, 10, 30, 0, 0, time.FixedZone("PST", -8*3600))
+
+	if MilestonesComplete(terms) {
+		t.Error("MilestonesComplete() = true with no milestone completed")
+	}
+
+	if err := completeMilestone(terms, "schema", "gongshow/polecats/Toast", at); err != nil {
+		t.Fatalf("completeMilestone(schema) error: %v", err)
+	}
+	want := Milestone{Name: "schema", CompletedAt: "2024-01-16T18:30:00Z", CompletedBy: "gongshow/polecats/Toast"}
+	if terms.MilestoneList[0] != want {
+		t.Errorf("milestone = %+v, want %+v", terms.MilestoneList[0], want)
+	}
+	if MilestonesComplete(terms) {
+		t.Error("MilestonesComplete() = true with api still open")
+	}
+
+	if err := completeMilestone(terms, "schema", "mayor", at); err == nil || !strings.Contains(err.Error(), "already completed") {
+		t.Errorf("completing schema again: error = %v, want already completed", err)
+	}
+	if terms.MilestoneList[0] != want {
+		t.Errorf("completing again changed the milestone to %+v", terms.MilestoneList[0])
+	}
+	if err := completeMilestone(terms, "deploy", "mayor", at); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("completing deploy: error = %v, want not found", err)
+	}
+	if err := completeMilestone(nil, "schema", "mayor", at); err == nil {
+		t.Error("completing a milestone of nil terms succeeded")
+	}
+
+	if err := completeMilestone(terms, "api", "gongshow/polecats/Toast", at); err != nil {
+		t.Fatalf("completeMilestone(api) error: %v", err)
+	}
+	if !MilestonesComplete(terms) {
+		t.Error("MilestonesComplete() = false with every milestone completed")
+	}
+}
+
+func TestMilestonesCompleteWithoutMilestones(t *testing.T) {
+	if !MilestonesComplete(nil) || !MilestonesComplete(&DelegationTerms{Portion: "docs"}) {
+		t.Error("MilestonesComplete() = false with no milestones")
+	}
+}
+
+func TestMarkMilestone(t *testing.T) {
+	d, _ := json.Marshal(Delegation{
+		Parent: "gt-parent", Child: "gt-child", DelegatedBy: "mayor", DelegatedTo: "gongshow/polecats/Toast",
+		Terms: &DelegationTerms{MilestoneList: []Milestone{{Name: "schema"}, {Name: "api"}}},
+	})
+	asString, _ := json.Marshal(string(d))
+	backend := NewMemoryBackend()
+	for id, bead := range map[string]string{
+		"gt-child": `{"id":"gt-child","title":"Write the API","delegated_from":` + string(asString) + `,"updated_at":"2026-01-01T00:00:00Z"}`,
+		"gt-slots": `{"id":"gt-slots","title":"Write the schema","slots":{"delegated_from":` + string(d) + `}}`,
+		"gt-plain": `{"id":"gt-plain","title":"No delegation"}`,
+	} {
+		if err := backend.Write(id, []byte(bead)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := NewStore(backend)
+
+	if err := MarkMilestone(store, "gt-child", "schema", "gongshow/polecats/Toast"); err != nil {
+		t.Fatalf("MarkMilestone() error = %v", err)
+	}
+	data, _ := backend.Read("gt-child")
+	var got delegationLine
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.DelegatedFrom[0] != '"' {
+		t.Errorf("delegated_from = %s, want it kept a string", got.DelegatedFrom)
+	}
+	saved := decodeDelegation(got.DelegatedFrom)
+	if saved == nil || saved.Terms.MilestoneList[0].CompletedBy != "gongshow/polecats/Toast" || saved.Terms.MilestoneList[1].CompletedAt != "" {
+		t.Errorf("delegation = %+v, want only schema completed", saved)
+	}
+	issue, err := store.Get("gt-child")
+	if err != nil || issue.Title != "Write the API" || issue.UpdatedAt == "2026-01-01T00:00:00Z" {
+		t.Errorf("bead = %+v, %v; want its title kept and updated_at changed", issue, err)
+	}
+
+	if err := MarkMilestone(store, "gt-child", "schema", "mayor"); err == nil || !strings.Contains(err.Error(), "already completed") {
+		t.Errorf("marking schema again: error = %v, want already completed", err)
+	}
+
+	if err := MarkMilestone(store, "gt-slots", "api", "mayor"); err != nil {
+		t.Fatalf("MarkMilestone() of a delegation under slots error = %v", err)
+	}
+	data, _ = backend.Read("gt-slots")
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if saved := decodeDelegation(got.Slots["delegated_from"]); saved == nil || saved.Terms.MilestoneList[1].CompletedBy != "mayor" {
+		t.Errorf("slots = %s, want api completed by mayor", data)
+	}
+
+	if err := MarkMilestone(store, "gt-plain", "api", "mayor"); err == nil || !strings.Contains(err.Error(), "no delegation") {
+		t.Errorf("MarkMilestone() without a delegation: error = %v, want no delegation", err)
+	}
+	if err := MarkMilestone(store, "gt-missing", "api", "mayor"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("MarkMilestone() of a missing bead: error = %v, want ErrNotFound", err)
+	}
+}
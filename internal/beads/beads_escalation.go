@@ -14,20 +14,23 @@ import (
 // EscalationFields holds structured fields for escalation beads.
 // These are stored as "key: value" lines in the description.
 type EscalationFields struct {
-	Severity           string // critical, high, medium, low
-	Reason             string // Why this was escalated
-	Source             string // Source identifier (e.g., plugin:rebuild-gt, patrol:deacon)
-	EscalatedBy        string // Agent address that escalated (e.g., "gongshow/Toast")
-	EscalatedAt        string // ISO 8601 timestamp
-	AckedBy            string // Agent that acknowledged (empty if not acked)
-	AckedAt            string // When acknowledged (empty if not acked)
-	ClosedBy           string // Agent that closed (empty if not closed)
-	ClosedReason       string // Resolution reason (empty if not closed)
-	RelatedBead        string // Optional: related bead ID (task, bug, etc.)
-	OriginalSeverity   string // Original severity before any re-escalation
-	ReescalationCount  int    // Number of times this has been re-escalated
-	LastReescalatedAt  string // When last re-escalated (empty if never)
-	LastReescalatedBy  string // Who last re-escalated (empty if never)
+	Severity          string   // critical, high, medium, low
+	Reason            string   // Why this was escalated
+	Source            string   // Source identifier (e.g., plugin:rebuild-gt, patrol:deacon)
+	EscalatedBy       string   // Agent address that escalated (e.g., "gongshow/Toast")
+	EscalatedAt       string   // ISO 8601 timestamp
+	AckedBy           string   // Agent that acknowledged (empty if not acked)
+	AckedAt           string   // When acknowledged (empty if not acked)
+	ClosedBy          string   // Agent that closed (empty if not closed)
+	ClosedReason      string   // Resolution reason (empty if not closed)
+	RelatedBead       string   // Optional: related bead ID (task, bug, etc.)
+	LinkedBeads       []string // Other related work items, added with LinkBead
+	OriginalSeverity  string   // Original severity before any re-escalation
+	ReescalationCount int      // Number of times this has been re-escalated
+	LastReescalatedAt string   // When last re-escalated (empty if never)
+	LastReescalatedBy string   // Who last re-escalated (empty if never)
+	SlackChannel      string   // Slack channel ID of the posted alert (bot token only)
+	SlackTS           string   // Slack message timestamp of the posted alert (bot token only)
 }
 
 // EscalationState constants for bead status tracking.
@@ -86,6 +89,12 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "related_bead: null")
 	}
 
+	if len(fields.LinkedBeads) > 0 {
+		lines = append(lines, fmt.Sprintf("linked_beads: %s", strings.Join(fields.LinkedBeads, ",")))
+	} else {
+		lines = append(lines, "linked_beads: null")
+	}
+
 	// Reescalation fields
 	if fields.OriginalSeverity != "" {
 		lines = append(lines, fmt.Sprintf("original_severity: %s", fields.OriginalSeverity))
@@ -104,6 +113,12 @@ func FormatEscalationDescription(title string, fields *EscalationFields) string
 		lines = append(lines, "last_reescalated_by: null")
 	}
 
+	// Slack message reference, only present when posted via the Slack API
+	if fields.SlackChannel != "" && fields.SlackTS != "" {
+		lines = append(lines, fmt.Sprintf("slack_channel: %s", fields.SlackChannel))
+		lines = append(lines, fmt.Sprintf("slack_ts: %s", fields.SlackTS))
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -149,6 +164,8 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.ClosedReason = value
 		case "related_bead":
 			fields.RelatedBead = value
+		case "linked_beads":
+			fields.LinkedBeads = parseLinkedBeads(value)
 		case "original_severity":
 			fields.OriginalSeverity = value
 		case "reescalation_count":
@@ -159,12 +176,51 @@ func ParseEscalationFields(description string) *EscalationFields {
 			fields.LastReescalatedAt = value
 		case "last_reescalated_by":
 			fields.LastReescalatedBy = value
+		case "slack_channel":
+			fields.SlackChannel = value
+		case "slack_ts":
+			fields.SlackTS = value
 		}
 	}
 
 	return fields
 }
 
+// parseLinkedBeads splits a comma-separated linked_beads value.
+func parseLinkedBeads(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// AddLinkedBead links beadID to the escalation.
+// Returns false if it was already linked.
+func (f *EscalationFields) AddLinkedBead(beadID string) bool {
+	for _, id := range f.LinkedBeads {
+		if id == beadID {
+			return false
+		}
+	}
+	f.LinkedBeads = append(f.LinkedBeads, beadID)
+	return true
+}
+
+// RemoveLinkedBead unlinks beadID from the escalation.
+// Returns false if it wasn't linked.
+func (f *EscalationFields) RemoveLinkedBead(beadID string) bool {
+	for i, id := range f.LinkedBeads {
+		if id == beadID {
+			f.LinkedBeads = append(f.LinkedBeads[:i:i], f.LinkedBeads[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // CreateEscalationBead creates an escalation bead for tracking escalations.
 // The created_by field is populated from BD_ACTOR env var for provenance tracking.
 func (b *Beads) CreateEscalationBead(title string, fields *EscalationFields) (*Issue, error) {
@@ -228,6 +284,68 @@ func (b *Beads) AckEscalation(id, ackedBy string) error {
 	})
 }
 
+// SetEscalationSlackRef records where the escalation's Slack alert was posted,
+// so ack and close can update that message later.
+func (b *Beads) SetEscalationSlackRef(id, channel, ts string) error {
+	issue, err := b.Show(id)
+	if err != nil {
+		return err
+	}
+
+	if !HasLabel(issue, "gt:escalation") {
+		return fmt.Errorf("issue %s is not an escalation bead (missing gt:escalation label)", id)
+	}
+
+	fields := ParseEscalationFields(issue.Description)
+	fields.SlackChannel = channel
+	fields.SlackTS = ts
+
+	description := FormatEscalationDescription(issue.Title, fields)
+	return b.Update(id, UpdateOptions{Description: &description})
+}
+
+// LinkBead records beadID as a work item related to the escalation.
+// Linking an already-linked bead is a no-op.
+func (b *Beads) LinkBead(escalationID, beadID string) error {
+	return b.updateLinkedBeads(escalationID, func(fields *EscalationFields) bool {
+		return fields.AddLinkedBead(beadID)
+	})
+}
+
+// UnlinkBead removes beadID from the escalation's linked beads.
+func (b *Beads) UnlinkBead(escalationID, beadID string) error {
+	var found bool
+	err := b.updateLinkedBeads(escalationID, func(fields *EscalationFields) bool {
+		found = fields.RemoveLinkedBead(beadID)
+		return found
+	})
+	if err == nil && !found {
+		return fmt.Errorf("bead %s is not linked to escalation %s", beadID, escalationID)
+	}
+	return err
+}
+
+// updateLinkedBeads applies change to the escalation's fields and saves the
+// description if change reports a modification.
+func (b *Beads) updateLinkedBeads(escalationID string, change func(*EscalationFields) bool) error {
+	issue, err := b.Show(escalationID)
+	if err != nil {
+		return err
+	}
+
+	if !HasLabel(issue, "gt:escalation") {
+		return fmt.Errorf("issue %s is not an escalation bead (missing gt:escalation label)", escalationID)
+	}
+
+	fields := ParseEscalationFields(issue.Description)
+	if !change(fields) {
+		return nil
+	}
+
+	description := FormatEscalationDescription(issue.Title, fields)
+	return b.Update(escalationID, UpdateOptions{Description: &description})
+}
+
 // CloseEscalation closes an escalation bead with a resolution reason.
 // Sets closed_by and closed_reason fields, closes the issue.
 func (b *Beads) CloseEscalation(id, closedBy, reason string) error {
@@ -1,6 +1,7 @@
 package beads
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -430,3 +431,88 @@ func TestEscalationEmptyRoundTrip(t *testing.T) {
 		t.Errorf("ReescalationCount should be 0, got %d", parsed.ReescalationCount)
 	}
 }
+
+func TestEscalationSlackRefRoundTrip(t *testing.T) {
+	original := &EscalationFields{
+		Severity:     "high",
+		Reason:       "test",
+		EscalatedBy:  "tester",
+		EscalatedAt:  "2024-01-15T10:00:00Z",
+		SlackChannel: "C0123ABCD",
+		SlackTS:      "1705312800.000200",
+	}
+
+	formatted := FormatEscalationDescription("Test", original)
+	parsed := ParseEscalationFields(formatted)
+
+	if parsed.SlackChannel != original.SlackChannel {
+		t.Errorf("SlackChannel = %q, want %q", parsed.SlackChannel, original.SlackChannel)
+	}
+	if parsed.SlackTS != original.SlackTS {
+		t.Errorf("SlackTS = %q, want %q", parsed.SlackTS, original.SlackTS)
+	}
+
+	// Escalations without a Slack reference don't carry the lines at all.
+	original.SlackChannel, original.SlackTS = "", ""
+	if formatted := FormatEscalationDescription("Test", original); strings.Contains(formatted, "slack_") {
+		t.Errorf("unexpected slack fields in:\n%s", formatted)
+	}
+}
+
+func TestEscalationLinkedBeadsRoundTrip(t *testing.T) {
+	original := &EscalationFields{
+		Severity:    "high",
+		Reason:      "flaky test",
+		EscalatedBy: "tester",
+		EscalatedAt: "2024-01-15T10:00:00Z",
+		LinkedBeads: []string{"gt-test", "gt-ci", "hq-oncall"},
+	}
+
+	formatted := FormatEscalationDescription("Test", original)
+	if !strings.Contains(formatted, "linked_beads: gt-test,gt-ci,hq-oncall") {
+		t.Errorf("missing linked_beads line in:\n%s", formatted)
+	}
+	parsed := ParseEscalationFields(formatted)
+	if !reflect.DeepEqual(parsed.LinkedBeads, original.LinkedBeads) {
+		t.Errorf("LinkedBeads = %v, want %v", parsed.LinkedBeads, original.LinkedBeads)
+	}
+
+	original.LinkedBeads = nil
+	formatted = FormatEscalationDescription("Test", original)
+	if !strings.Contains(formatted, "linked_beads: null") {
+		t.Errorf("empty linked beads should be null in:\n%s", formatted)
+	}
+	if parsed := ParseEscalationFields(formatted); parsed.LinkedBeads != nil {
+		t.Errorf("LinkedBeads = %v, want nil", parsed.LinkedBeads)
+	}
+}
+
+func TestEscalationLinkMultipleBeads(t *testing.T) {
+	fields := &EscalationFields{Severity: "medium", Reason: "test"}
+	for _, id := range []string{"gt-a", "gt-b", "gt-c"} {
+		if !fields.AddLinkedBead(id) {
+			t.Errorf("AddLinkedBead(%s) = false, want true", id)
+		}
+		// Simulate the read-modify-write done by LinkBead.
+		fields = ParseEscalationFields(FormatEscalationDescription("Test", fields))
+	}
+	if fields.AddLinkedBead("gt-b") {
+		t.Error("linking an already-linked bead should be a no-op")
+	}
+
+	want := []string{"gt-a", "gt-b", "gt-c"}
+	if !reflect.DeepEqual(fields.LinkedBeads, want) {
+		t.Fatalf("LinkedBeads = %v, want %v", fields.LinkedBeads, want)
+	}
+
+	if !fields.RemoveLinkedBead("gt-b") {
+		t.Error("RemoveLinkedBead(gt-b) = false, want true")
+	}
+	if fields.RemoveLinkedBead("gt-zzz") {
+		t.Error("RemoveLinkedBead of an unlinked bead should return false")
+	}
+	parsed := ParseEscalationFields(FormatEscalationDescription("Test", fields))
+	if want := []string{"gt-a", "gt-c"}; !reflect.DeepEqual(parsed.LinkedBeads, want) {
+		t.Errorf("after unlink LinkedBeads = %v, want %v", parsed.LinkedBeads, want)
+	}
+}
@@ -0,0 +1,173 @@
+// Package beads provides the merge request review workflow.
+package beads
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// sendReviewMail delivers a review request to a reviewer.
+// It shells out to gt mail (the mail package depends on beads, so it can't be
+// imported here). Replaced in tests.
+var sendReviewMail = func(workDir, to, subject, body string) error {
+	cmd := exec.Command("gt", "mail", "send", to, "-s", subject, "-m", body) //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = workDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// RequestReview adds reviewers to a merge request and mails each of them a
+// REVIEW_REQUEST. Reviewers already on the list are not asked again.
+func (b *Beads) RequestReview(mrID string, reviewers []string) error {
+	issue, fields, err := b.showMR(mrID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var added []string
+	for _, reviewer := range reviewers {
+		reviewer = strings.TrimSpace(reviewer)
+		if reviewer == "" || containsString(fields.ReviewerList, reviewer) {
+			continue
+		}
+		fields.ReviewerList = append(fields.ReviewerList, reviewer)
+		fields.Reviews = append(fields.Reviews, Review{Reviewer: reviewer, Status: ReviewPending, At: now})
+		added = append(added, reviewer)
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	desc := SetMRFields(issue, fields)
+	if err := b.Update(mrID, UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("updating reviewers: %w", err)
+	}
+
+	subject := "REVIEW_REQUEST: " + mrID
+	body := formatReviewRequest(mrID, issue.Title, fields)
+	var failed []string
+	for _, reviewer := range added {
+		if err := sendReviewMail(b.workDir, reviewer, subject, body); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", reviewer, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("sending review request to %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// SubmitReview records a reviewer's verdict on a merge request. When the
+// review completes the quorum (every listed reviewer approved), a
+// merge_started event is emitted.
+func (b *Beads) SubmitReview(mrID, reviewer, status, comment string) error {
+	switch status {
+	case ReviewApproved, ReviewChangesRequested, ReviewPending:
+	default:
+		return fmt.Errorf("invalid review status %q (want %s, %s, or %s)",
+			status, ReviewApproved, ReviewChangesRequested, ReviewPending)
+	}
+
+	issue, fields, err := b.showMR(mrID)
+	if err != nil {
+		return err
+	}
+	if !containsString(fields.ReviewerList, reviewer) {
+		return fmt.Errorf("%s is not a reviewer on %s", reviewer, mrID)
+	}
+
+	wasMet := ReviewQuorumMet(fields)
+	setReview(fields, Review{Reviewer: reviewer, Status: status, Comment: comment, At: time.Now()})
+
+	desc := SetMRFields(issue, fields)
+	if err := b.Update(mrID, UpdateOptions{Description: &desc}); err != nil {
+		return fmt.Errorf("recording review: %w", err)
+	}
+
+	if !wasMet && ReviewQuorumMet(fields) {
+		_ = events.LogFeed(events.TypeMergeStarted, reviewer,
+			events.MergePayload(mrID, fields.Worker, fields.Branch, "all reviewers approved"))
+	}
+	return nil
+}
+
+// ReviewQuorumMet reports whether every required reviewer has approved.
+// A merge request with no reviewers has no quorum to meet and returns false.
+func ReviewQuorumMet(fields *MRFields) bool {
+	if fields == nil || len(fields.ReviewerList) == 0 {
+		return false
+	}
+	for _, reviewer := range fields.ReviewerList {
+		r := latestReview(fields, reviewer)
+		if r == nil || r.Status != ReviewApproved {
+			return false
+		}
+	}
+	return true
+}
+
+// showMR loads a merge-request bead and its fields.
+func (b *Beads) showMR(mrID string) (*Issue, *MRFields, error) {
+	issue, err := b.Show(mrID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading %s: %w", mrID, err)
+	}
+	fields := ParseMRFields(issue)
+	if fields == nil {
+		return nil, nil, fmt.Errorf("%s is not a merge request", mrID)
+	}
+	return issue, fields, nil
+}
+
+// latestReview returns the most recent review by reviewer, or nil.
+func latestReview(fields *MRFields, reviewer string) *Review {
+	var latest *Review
+	for i := range fields.Reviews {
+		r := &fields.Reviews[i]
+		if r.Reviewer == reviewer && (latest == nil || !r.At.Before(latest.At)) {
+			latest = r
+		}
+	}
+	return latest
+}
+
+// setReview replaces any existing review by the same reviewer.
+func setReview(fields *MRFields, review Review) {
+	kept := fields.Reviews[:0]
+	for _, r := range fields.Reviews {
+		if r.Reviewer != review.Reviewer {
+			kept = append(kept, r)
+		}
+	}
+	fields.Reviews = append(kept, review)
+}
+
+func formatReviewRequest(mrID, title string, fields *MRFields) string {
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Review requested for %s: %s", mrID, title))
+	lines = append(lines, "")
+	if fields.Branch != "" {
+		lines = append(lines, fmt.Sprintf("Branch: %s → %s", fields.Branch, fields.Target))
+	}
+	if fields.Worker != "" {
+		lines = append(lines, "Worker: "+fields.Worker)
+	}
+	lines = append(lines, "Reviewers: "+strings.Join(fields.ReviewerList, ", "))
+	return strings.Join(lines, "\n")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package beads
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReviewQuorumMet(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	approved := func(who string, at time.Time) Review {
+		return Review{Reviewer: who, Status: ReviewApproved, At: at}
+	}
+
+	tests := []struct {
+		name   string
+		fields *MRFields
+		want   bool
+	}{
+		{"nil fields", nil, false},
+		{"no reviewers", &MRFields{}, false},
+		{
+			name: "all approved",
+			fields: &MRFields{
+				ReviewerList: []string{"gongshow/witness", "mayor/"},
+				Reviews:      []Review{approved("gongshow/witness", t0), approved("mayor/", t0)},
+			},
+			want: true,
+		},
+		{
+			name: "one pending",
+			fields: &MRFields{
+				ReviewerList: []string{"gongshow/witness", "mayor/"},
+				Reviews: []Review{
+					approved("gongshow/witness", t0),
+					{Reviewer: "mayor/", Status: ReviewPending, At: t0},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "reviewer never responded",
+			fields: &MRFields{
+				ReviewerList: []string{"gongshow/witness", "mayor/"},
+				Reviews:      []Review{approved("gongshow/witness", t0)},
+			},
+			want: false,
+		},
+		{
+			name: "later changes-requested overrides approval",
+			fields: &MRFields{
+				ReviewerList: []string{"mayor/"},
+				Reviews: []Review{
+					approved("mayor/", t0),
+					{Reviewer: "mayor/", Status: ReviewChangesRequested, At: t0.Add(time.Hour)},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "approval from non-reviewer doesn't count",
+			fields: &MRFields{
+				ReviewerList: []string{"mayor/"},
+				Reviews:      []Review{approved("gongshow/Toast", t0)},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := ReviewQuorumMet(tt.fields); got != tt.want {
+			t.Errorf("%s: ReviewQuorumMet = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetReviewReplacesExisting(t *testing.T) {
+	fields := &MRFields{
+		ReviewerList: []string{"a", "b"},
+		Reviews: []Review{
+			{Reviewer: "a", Status: ReviewPending},
+			{Reviewer: "b", Status: ReviewPending},
+		},
+	}
+	setReview(fields, Review{Reviewer: "a", Status: ReviewApproved})
+	setReview(fields, Review{Reviewer: "b", Status: ReviewApproved})
+
+	if len(fields.Reviews) != 2 {
+		t.Fatalf("expected 2 reviews, got %d", len(fields.Reviews))
+	}
+	if !ReviewQuorumMet(fields) {
+		t.Error("quorum should be met once both reviewers approve")
+	}
+}
+
+func TestMRFieldsReviewRoundTrip(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fields := &MRFields{
+		Branch:       "polecat/Nux/gt-xyz",
+		Target:       "main",
+		ReviewerList: []string{"gongshow/witness", "mayor/"},
+		Reviews: []Review{
+			{Reviewer: "gongshow/witness", Status: ReviewApproved, At: at},
+			{Reviewer: "mayor/", Status: ReviewChangesRequested, Comment: "needs a test\nfor the edge case", At: at},
+		},
+	}
+
+	issue := &Issue{Description: "Some prose.\nreviewers: old/"}
+	parsed := ParseMRFields(&Issue{Description: SetMRFields(issue, fields)})
+	if parsed == nil {
+		t.Fatal("ParseMRFields returned nil")
+	}
+
+	if len(parsed.ReviewerList) != 2 || parsed.ReviewerList[1] != "mayor/" {
+		t.Errorf("ReviewerList = %v", parsed.ReviewerList)
+	}
+	if len(parsed.Reviews) != 2 {
+		t.Fatalf("expected 2 reviews, got %d", len(parsed.Reviews))
+	}
+	got := parsed.Reviews[1]
+	if got.Reviewer != "mayor/" || got.Status != ReviewChangesRequested || !got.At.Equal(at) {
+		t.Errorf("unexpected review: %+v", got)
+	}
+	if got.Comment != "needs a test for the edge case" {
+		t.Errorf("Comment = %q", got.Comment)
+	}
+}
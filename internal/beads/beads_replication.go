@@ -0,0 +1,260 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReplicationTarget is a remote store that beads are replicated to, for
+// towns spread over several machines. Data is a bead's JSON as its Store
+// holds it.
+type ReplicationTarget interface {
+	Push(id string, data []byte) error
+	Pull(id string) ([]byte, error)
+	ListModifiedSince(t time.Time) ([]string, error)
+}
+
+// Replicator pushes changed beads to a ReplicationTarget in the
+// background; see Store.EnableReplication.
+type Replicator struct {
+	target   ReplicationTarget
+	store    *Store
+	interval time.Duration
+
+	// pushed is the updated_at of each bead as last pushed. Only the
+	// replicator goroutine touches it.
+	pushed map[string]string
+
+	mu      sync.Mutex
+	lastErr error
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// ContextPusher is a ReplicationTarget whose pushes can be cancelled. The
+// Replicator pushes through it when the target has it, so that Stop
+// doesn't wait for a slow push to time out.
+type ContextPusher interface {
+	PushContext(ctx context.Context, id string, data []byte) error
+}
+
+// EnableReplication starts pushing the store's beads to target: all of
+// them at once, then every interval the ones whose updated_at changed. A
+// bead whose push fails is pushed again on the next round. Call Stop on
+// the returned Replicator to end it.
+func (s *Store) EnableReplication(target ReplicationTarget, interval time.Duration) *Replicator {
+	r := newReplicator(target, interval, s)
+	r.wg.Add(1)
+	go r.run()
+	return r
+}
+
+func newReplicator(target ReplicationTarget, interval time.Duration, store *Store) *Replicator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Replicator{
+		target:   target,
+		store:    store,
+		interval: interval,
+		pushed:   make(map[string]string),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Stop ends replication, cancelling a round in progress.
+func (r *Replicator) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Err returns the error of the last round, or nil if it succeeded.
+func (r *Replicator) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastErr
+}
+
+func (r *Replicator) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.round()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.round()
+		}
+	}
+}
+
+// round pushes the changed beads and records how it went.
+func (r *Replicator) round() {
+	_, err := r.pushChanged()
+	if r.ctx.Err() != nil {
+		return // Stopped; the round didn't fail
+	}
+	r.mu.Lock()
+	r.lastErr = err
+	r.mu.Unlock()
+}
+
+// pushChanged pushes the beads changed since they were last pushed and
+// returns how many it pushed. Every bead is tried; the error reports the
+// failures. A stopped replicator stops between beads.
+func (r *Replicator) pushChanged() (int, error) {
+	ids, err := r.store.List("")
+	if err != nil {
+		return 0, fmt.Errorf("listing beads: %w", err)
+	}
+
+	pushed := 0
+	var failed []string
+	var firstErr error
+	for _, id := range ids {
+		if err := r.ctx.Err(); err != nil {
+			return pushed, err
+		}
+		data, err := r.store.Backend().Read(id)
+		if errors.Is(err, ErrNotFound) {
+			continue // Deleted since listed
+		}
+		var bead struct {
+			UpdatedAt string `json:"updated_at"`
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &bead)
+		}
+		if err == nil {
+			if last, ok := r.pushed[id]; ok && last == bead.UpdatedAt {
+				continue
+			}
+			err = r.push(id, data)
+		}
+		if err != nil {
+			failed = append(failed, id)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		r.pushed[id] = bead.UpdatedAt
+		pushed++
+	}
+	if len(failed) > 0 {
+		return pushed, fmt.Errorf("pushing %s: %w", strings.Join(failed, ", "), firstErr)
+	}
+	return pushed, nil
+}
+
+// push pushes one bead, cancelled by Stop if the target allows it.
+func (r *Replicator) push(id string, data []byte) error {
+	if cp, ok := r.target.(ContextPusher); ok {
+		return cp.PushContext(r.ctx, id, data)
+	}
+	return r.target.Push(id, data)
+}
+
+// HTTPReplicationTarget replicates beads to an HTTP server:
+//
+//	PUT /beads/<id>              stores the bead's JSON
+//	GET /beads/<id>              returns it (404 if unknown)
+//	GET /beads/?since=<rfc3339>  returns a JSON array of the IDs of beads
+//	                             stored since then
+type HTTPReplicationTarget struct {
+	BaseURL string       // e.g. "http://hq.example:8400"
+	Client  *http.Client // nil for a client with a 30s timeout
+}
+
+// NewHTTPReplicationTarget returns a target for the server at baseURL.
+func NewHTTPReplicationTarget(baseURL string) *HTTPReplicationTarget {
+	return &HTTPReplicationTarget{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Push stores a bead's JSON on the server.
+func (h *HTTPReplicationTarget) Push(id string, data []byte) error {
+	return h.PushContext(context.Background(), id, data)
+}
+
+// PushContext is Push, abandoned when ctx is done.
+func (h *HTTPReplicationTarget) PushContext(ctx context.Context, id string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, h.beadURL(id), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	_, err = h.do(req)
+	return err
+}
+
+// Pull returns a bead's JSON from the server, or ErrNotFound.
+func (h *HTTPReplicationTarget) Pull(id string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, h.beadURL(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	return h.do(req)
+}
+
+// ListModifiedSince returns the IDs of the beads stored on the server
+// since t.
+func (h *HTTPReplicationTarget) ListModifiedSince(t time.Time) ([]string, error) {
+	u := h.BaseURL + "/beads/?since=" + url.QueryEscape(t.UTC().Format(time.RFC3339))
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	body, err := h.do(req)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	if err := json.Unmarshal(body, &ids); err != nil {
+		return nil, fmt.Errorf("parsing modified beads: %w", err)
+	}
+	return ids, nil
+}
+
+func (h *HTTPReplicationTarget) beadURL(id string) string {
+	return h.BaseURL + "/beads/" + url.PathEscape(id)
+}
+
+// do sends the request and returns the response body, or an error for a
+// status other than 2xx.
+func (h *HTTPReplicationTarget) do(req *http.Request) ([]byte, error) {
+	client := h.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: reading response: %w", req.Method, req.URL.Path, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,280 @@
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// replicaServer is an in-memory bead replica speaking the
+// HTTPReplicationTarget protocol.
+type replicaServer struct {
+	mu       sync.Mutex
+	beads    map[string][]byte
+	modified map[string]time.Time
+	puts     []string
+	failPut  string // A bead ID whose pushes fail
+	now      func() time.Time
+}
+
+func newReplicaServer(t *testing.T) (*replicaServer, *httptest.Server) {
+	rs := &replicaServer{beads: map[string][]byte{}, modified: map[string]time.Time{}, now: time.Now}
+	srv := httptest.NewServer(rs)
+	t.Cleanup(srv.Close)
+	return rs, srv
+}
+
+func (rs *replicaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	id := strings.TrimPrefix(r.URL.Path, "/beads/")
+	switch {
+	case r.Method == http.MethodPut && id != "":
+		if id == rs.failPut {
+			http.Error(w, "disk full", http.StatusInsufficientStorage)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		rs.beads[id], rs.modified[id] = data, rs.now()
+		rs.puts = append(rs.puts, id)
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && id != "":
+		data, ok := rs.beads[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	case r.Method == http.MethodGet:
+		since, err := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		ids := []string{}
+		for id, at := range rs.modified {
+			if !at.Before(since) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		_ = json.NewEncoder(w).Encode(ids)
+	default:
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}
+}
+
+func (rs *replicaServer) takePuts() []string {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	puts := rs.puts
+	rs.puts = nil
+	return puts
+}
+
+func TestHTTPReplicationTarget(t *testing.T) {
+	rs, srv := newReplicaServer(t)
+	target := NewHTTPReplicationTarget(srv.URL + "/")
+
+	if err := target.Push("gt-abc", []byte(`{"id":"gt-abc"}`)); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+	data, err := target.Pull("gt-abc")
+	if err != nil || string(data) != `{"id":"gt-abc"}` {
+		t.Errorf("Pull() = %s, %v; want the pushed bead", data, err)
+	}
+	if _, err := target.Pull("gt-nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Pull() of an unknown bead error = %v, want ErrNotFound", err)
+	}
+
+	rs.failPut = "gt-full"
+	if err := target.Push("gt-full", []byte("{}")); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Push() error = %v, want the server's error", err)
+	}
+}
+
+func TestHTTPReplicationTargetListModifiedSince(t *testing.T) {
+	rs, srv := newReplicaServer(t)
+	target := NewHTTPReplicationTarget(srv.URL)
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i, id := range []string{"gt-old", "gt-new", "gt-newer"} {
+		at := base.Add(time.Duration(i) * time.Hour)
+		rs.now = func() time.Time { return at }
+		if err := target.Push(id, []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids, err := target.ListModifiedSince(base.Add(30 * time.Minute))
+	if err != nil {
+		t.Fatalf("ListModifiedSince() error = %v", err)
+	}
+	if got := strings.Join(ids, ","); got != "gt-new,gt-newer" {
+		t.Errorf("ListModifiedSince() = %s, want gt-new,gt-newer", got)
+	}
+}
+
+// replicaStore returns a Store holding issues.
+func replicaStore(t *testing.T, issues ...*Issue) *Store {
+	t.Helper()
+	s := NewStore(NewMemoryBackend())
+	for _, issue := range issues {
+		if err := s.Put(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestReplicatorPushChanged(t *testing.T) {
+	rs, srv := newReplicaServer(t)
+	store := replicaStore(t,
+		&Issue{ID: "gt-1", Title: "one", UpdatedAt: "2026-03-01T09:00:00Z"},
+		&Issue{ID: "gt-2", Title: "two", UpdatedAt: "2026-03-01T09:00:00Z"},
+	)
+	r := newReplicator(NewHTTPReplicationTarget(srv.URL), time.Hour, store)
+
+	// The first round pushes everything.
+	if n, err := r.pushChanged(); err != nil || n != 2 {
+		t.Fatalf("first pushChanged() = %d, %v; want both beads pushed", n, err)
+	}
+	var pushed Issue
+	if err := json.Unmarshal(rs.beads["gt-2"], &pushed); err != nil || pushed.Title != "two" {
+		t.Errorf("replica has %s, want gt-2's JSON", rs.beads["gt-2"])
+	}
+	rs.takePuts()
+
+	// Then only what changed.
+	if err := store.Put(&Issue{ID: "gt-2", Title: "two", UpdatedAt: "2026-03-01T10:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(&Issue{ID: "gt-3", UpdatedAt: "2026-03-01T10:00:00Z"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.pushChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(rs.takePuts(), ","); got != "gt-2,gt-3" {
+		t.Errorf("second round pushed %s, want gt-2,gt-3", got)
+	}
+
+	// A failed push is tried again next round; the others still go.
+	rs.failPut = "gt-1"
+	for _, id := range []string{"gt-1", "gt-3"} {
+		if err := store.Put(&Issue{ID: id, UpdatedAt: "2026-03-01T11:00:00Z"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, err := r.pushChanged(); err == nil || n != 1 || !strings.Contains(err.Error(), "gt-1") {
+		t.Errorf("pushChanged() = %d, %v; want gt-3 pushed and gt-1 failed", n, err)
+	}
+	rs.takePuts()
+	rs.failPut = ""
+	if _, err := r.pushChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(rs.takePuts(), ","); got != "gt-1" {
+		t.Errorf("retry round pushed %s, want gt-1", got)
+	}
+}
+
+func TestReplicatorRunsUntilStopped(t *testing.T) {
+	rs, srv := newReplicaServer(t)
+	store := replicaStore(t, &Issue{ID: "gt-1", UpdatedAt: "2026-03-01T09:00:00Z"})
+	r := newReplicator(NewHTTPReplicationTarget(srv.URL), 10*time.Millisecond, store)
+	r.wg.Add(1)
+	go r.run()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rs.mu.Lock()
+		_, ok := rs.beads["gt-1"]
+		rs.mu.Unlock()
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("bead never replicated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	r.Stop()
+	if err := r.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestReplicatorPushesOnStart(t *testing.T) {
+	rs, srv := newReplicaServer(t)
+	r := replicaStore(t, &Issue{ID: "gt-1", UpdatedAt: "2026-03-01T09:00:00Z"}).
+		EnableReplication(NewHTTPReplicationTarget(srv.URL), time.Hour)
+
+	// Long before the first tick.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rs.mu.Lock()
+		_, ok := rs.beads["gt-1"]
+		rs.mu.Unlock()
+		if ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("bead not replicated on start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	r.Stop()
+}
+
+func TestReplicatorStopCancelsPush(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		// A server that doesn't answer until the test ends
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Cleanup(func() { close(release) })
+
+	store := replicaStore(t,
+		&Issue{ID: "gt-1", UpdatedAt: "2026-03-01T09:00:00Z"},
+		&Issue{ID: "gt-2", UpdatedAt: "2026-03-01T09:00:00Z"},
+	)
+	r := store.EnableReplication(NewHTTPReplicationTarget(srv.URL), time.Hour)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("push never started")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		r.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop() waited for the push to time out")
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("Err() after Stop = %v, want nil", err)
+	}
+}
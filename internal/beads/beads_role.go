@@ -4,6 +4,8 @@ package beads
 import (
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 )
 
 // Role bead ID naming convention:
@@ -80,7 +82,13 @@ func (b *Beads) GetRoleConfig(roleBeadID string) (*RoleConfig, error) {
 		return nil, fmt.Errorf("bead %s is not a role bead (missing gt:role label)", roleBeadID)
 	}
 
-	return ParseRoleConfig(issue.Description), nil
+	config := ParseRoleConfig(issue.Description)
+	if config != nil {
+		if unknown := config.UnknownPlaceholders(); len(unknown) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: role bead %s uses unrecognised placeholders: %s\n", roleBeadID, strings.Join(unknown, ", "))
+		}
+	}
+	return config, nil
 }
 
 // HasLabel checks if an issue has a specific label.
@@ -0,0 +1,141 @@
+// Package beads provides fuzzy search over bead titles and descriptions.
+package beads
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SearchIndexTTL is how long a FuzzySearch index is reused before the store
+// is listed again. Beads created or edited within the window may be missed.
+const SearchIndexTTL = 30 * time.Second
+
+// searchDoc is one indexed issue with its lowercased searchable text.
+type searchDoc struct {
+	issue       *Issue
+	title       string
+	description string
+}
+
+// searchIndex is an in-memory snapshot of a store's issues.
+type searchIndex struct {
+	docs    []searchDoc
+	expires time.Time
+}
+
+// searchIndexes caches one index per store, keyed by workDir and beadsDir.
+var searchIndexes = struct {
+	sync.Mutex
+	m map[string]*searchIndex
+}{m: make(map[string]*searchIndex)}
+
+// searchLoader lists the issues to index. Replaced in tests.
+var searchLoader = func(b *Beads) ([]*Issue, error) {
+	return b.List(ListOptions{Status: "all", Priority: -1})
+}
+
+// FuzzySearch returns issues whose title or description contain any of the
+// whitespace-separated tokens in query (case-insensitive substring match),
+// best match first. A token found in the title scores 2, in the description
+// 1; ties are broken by ID. limit <= 0 returns all matches.
+//
+// The index is built on first use and reused for SearchIndexTTL.
+func (b *Beads) FuzzySearch(query string, limit int) ([]*Issue, error) {
+	idx, err := b.searchIndex()
+	if err != nil {
+		return nil, err
+	}
+	return idx.search(query, limit), nil
+}
+
+// searchIndex returns the cached index for this store, rebuilding it if it
+// is missing or expired.
+func (b *Beads) searchIndex() (*searchIndex, error) {
+	key := b.workDir + "\x00" + b.beadsDir
+
+	searchIndexes.Lock()
+	defer searchIndexes.Unlock()
+
+	if idx, ok := searchIndexes.m[key]; ok && time.Now().Before(idx.expires) {
+		return idx, nil
+	}
+
+	issues, err := searchLoader(b)
+	if err != nil {
+		return nil, err
+	}
+	idx := newSearchIndex(issues)
+	idx.expires = time.Now().Add(SearchIndexTTL)
+	searchIndexes.m[key] = idx
+	return idx, nil
+}
+
+func newSearchIndex(issues []*Issue) *searchIndex {
+	idx := &searchIndex{docs: make([]searchDoc, 0, len(issues))}
+	for _, issue := range issues {
+		idx.docs = append(idx.docs, searchDoc{
+			issue:       issue,
+			title:       strings.ToLower(issue.Title),
+			description: strings.ToLower(issue.Description),
+		})
+	}
+	return idx
+}
+
+func (idx *searchIndex) search(query string, limit int) []*Issue {
+	tokens := searchTokens(query)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	type scored struct {
+		issue *Issue
+		score int
+	}
+	var matches []scored
+	for _, doc := range idx.docs {
+		score := 0
+		for _, tok := range tokens {
+			if strings.Contains(doc.title, tok) {
+				score += 2
+			}
+			if strings.Contains(doc.description, tok) {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{doc.issue, score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].issue.ID < matches[j].issue.ID
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	results := make([]*Issue, len(matches))
+	for i, m := range matches {
+		results[i] = m.issue
+	}
+	return results
+}
+
+// searchTokens splits a query into unique lowercase tokens.
+func searchTokens(query string) []string {
+	seen := make(map[string]bool)
+	var tokens []string
+	for _, tok := range strings.Fields(strings.ToLower(query)) {
+		if !seen[tok] {
+			seen[tok] = true
+			tokens = append(tokens, tok)
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,118 @@
+package beads
+
+import (
+	"fmt"
+	"testing"
+)
+
+// stubSearchLoader replaces searchLoader for the duration of a test and
+// counts how many times the store is listed.
+func stubSearchLoader(t testing.TB, issues []*Issue) *int {
+	t.Helper()
+	calls := 0
+	orig := searchLoader
+	searchLoader = func(b *Beads) ([]*Issue, error) {
+		calls++
+		return issues, nil
+	}
+	t.Cleanup(func() {
+		searchLoader = orig
+		searchIndexes.Lock()
+		searchIndexes.m = make(map[string]*searchIndex)
+		searchIndexes.Unlock()
+	})
+	return &calls
+}
+
+func TestFuzzySearch(t *testing.T) {
+	stubSearchLoader(t, []*Issue{
+		{ID: "gt-1", Title: "Fix auth token refresh", Description: "OAuth tokens expire early"},
+		{ID: "gt-2", Title: "Refinery stuck", Description: "merge queue blocked on auth check"},
+		{ID: "gt-3", Title: "Update README"},
+		{ID: "gt-4", Title: "AUTH: rotate keys", Description: "token rotation"},
+	})
+
+	b := New(t.TempDir())
+	results, err := b.FuzzySearch("auth token", 0)
+	if err != nil {
+		t.Fatalf("FuzzySearch: %v", err)
+	}
+
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.ID)
+	}
+	// gt-1: auth+token in title, token in description (5)
+	// gt-4: auth in title, token in description (3)
+	// gt-2: auth in description (1)
+	want := []string{"gt-1", "gt-4", "gt-2"}
+	if fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("FuzzySearch order = %v, want %v", ids, want)
+	}
+
+	limited, _ := b.FuzzySearch("auth", 1)
+	if len(limited) != 1 {
+		t.Errorf("limit 1 returned %d results", len(limited))
+	}
+
+	if empty, _ := b.FuzzySearch("   ", 0); len(empty) != 0 {
+		t.Errorf("blank query returned %d results", len(empty))
+	}
+}
+
+func TestFuzzySearchCachesIndex(t *testing.T) {
+	calls := stubSearchLoader(t, []*Issue{{ID: "gt-1", Title: "auth"}})
+
+	b := New(t.TempDir())
+	for i := 0; i < 3; i++ {
+		if _, err := b.FuzzySearch("auth", 0); err != nil {
+			t.Fatalf("FuzzySearch: %v", err)
+		}
+	}
+	if *calls != 1 {
+		t.Errorf("store listed %d times, want 1", *calls)
+	}
+
+	// A different store gets its own index.
+	if _, err := New(t.TempDir()).FuzzySearch("auth", 0); err != nil {
+		t.Fatalf("FuzzySearch: %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("store listed %d times, want 2", *calls)
+	}
+}
+
+func benchmarkIssues(n int) []*Issue {
+	words := []string{"auth", "refinery", "merge", "queue", "polecat", "witness", "token", "deploy", "cache", "mail"}
+	issues := make([]*Issue, n)
+	for i := range issues {
+		issues[i] = &Issue{
+			ID:          fmt.Sprintf("gt-%d", i),
+			Title:       fmt.Sprintf("%s %s issue %d", words[i%len(words)], words[(i/3)%len(words)], i),
+			Description: fmt.Sprintf("Investigate %s behaviour around %s in rig %d", words[(i/7)%len(words)], words[(i/11)%len(words)], i%5),
+		}
+	}
+	return issues
+}
+
+func BenchmarkFuzzySearch(b *testing.B) {
+	issues := benchmarkIssues(1000)
+
+	b.Run("cached", func(b *testing.B) {
+		stubSearchLoader(b, issues)
+		store := New(b.TempDir())
+		if _, err := store.FuzzySearch("warm", 0); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = store.FuzzySearch("auth token", 20)
+		}
+	})
+
+	b.Run("build", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			newSearchIndex(issues).search("auth token", 20)
+		}
+	})
+}
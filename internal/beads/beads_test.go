@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -581,7 +582,7 @@ func TestMRFieldsRoundTrip(t *testing.T) {
 		t.Fatal("round-trip parse returned nil")
 	}
 
-	if *parsed != *original {
+	if !reflect.DeepEqual(parsed, original) {
 		t.Errorf("round-trip mismatch:\ngot  %+v\nwant %+v", parsed, original)
 	}
 }
@@ -1357,6 +1358,59 @@ func TestExpandRolePattern(t *testing.T) {
 	}
 }
 
+// TestExpandRoleVars tests the extended placeholders.
+func TestExpandRoleVars(t *testing.T) {
+	t.Setenv("GT_TEST_MODEL", "opus")
+	vars := RoleVars{
+		Town:        "/gt",
+		Rig:         "gongshow",
+		Role:        "witness",
+		RigDir:      "/gt/gongshow",
+		ConfigDir:   "/gt/config",
+		BeadsDir:    "/gt/gongshow/.beads",
+		SessionName: "gt-gongshow-witness",
+		Actor:       "gongshow/witness",
+	}
+
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"{town}/{rig} {role}", "/gt/gongshow witness"},
+		{"cd {rigdir} && ls {configdir} {beadsdir}", "cd /gt/gongshow && ls /gt/config /gt/gongshow/.beads"},
+		{"tmux attach -t {sessionname}", "tmux attach -t gt-gongshow-witness"},
+		{"BD_ACTOR={actor}", "BD_ACTOR=gongshow/witness"},
+		{"--model {env:GT_TEST_MODEL}", "--model opus"},
+		{"--x {env:GT_TEST_UNSET}", "--x "},
+		{"keep {bogus} and ${HOME}", "keep {bogus} and ${HOME}"},
+	}
+	for _, tt := range tests {
+		if got := ExpandRoleVars(tt.pattern, vars); got != tt.want {
+			t.Errorf("ExpandRoleVars(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+// TestRoleConfigUnknownPlaceholders tests placeholder validation.
+func TestRoleConfigUnknownPlaceholders(t *testing.T) {
+	config := &RoleConfig{
+		SessionPattern: "gt-{rig}-{role}",
+		WorkDirPattern: "{town}/{rigg}",
+		StartCommand:   "exec claude --dir {rigdir} --actor {actr} {env:HOME} ${PATH} {rigg}",
+		EnvVars:        map[string]string{"X": "{env:}"},
+	}
+	got := config.UnknownPlaceholders()
+	want := []string{"{actr}", "{env:}", "{rigg}"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnknownPlaceholders() = %v, want %v", got, want)
+	}
+
+	clean := &RoleConfig{StartCommand: "exec run {sessionname} {actor} {beadsdir} {configdir}"}
+	if got := clean.UnknownPlaceholders(); len(got) != 0 {
+		t.Errorf("UnknownPlaceholders() = %v, want none", got)
+	}
+}
+
 // TestFormatRoleConfig tests formatting role config to string.
 func TestFormatRoleConfig(t *testing.T) {
 	tests := []struct {
@@ -8,6 +8,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/KeithWyatt/gongshow/internal/events"
 	"github.com/KeithWyatt/gongshow/internal/proc"
 )
 
@@ -111,8 +112,7 @@ func EnsureBdDaemonHealth(workDir string) string {
 // restartBdDaemons restarts all bd daemons.
 func restartBdDaemons() error { //nolint:unparam // error return kept for future use
 	// Stop all daemons first using native signals to avoid auto-start side effects
-	pids := proc.FindByPattern("bd daemon")
-	proc.SignalAll(pids, syscall.SIGTERM)
+	proc.SignalAllVerified(matchingProcs(snapshotProcs(), bdDaemonProcs), syscall.SIGTERM)
 
 	// Give time for cleanup
 	time.Sleep(200 * time.Millisecond)
@@ -139,15 +139,17 @@ func StopAllBdProcesses(dryRun, force bool) (int, int, error) {
 		return 0, 0, nil
 	}
 
-	daemonsBefore := CountBdDaemons()
-	activityBefore := CountBdActivityProcesses()
+	// One snapshot finds both kinds; each is rescanned once it is stopped.
+	table := snapshotProcs()
+	daemons := matchingProcs(table, bdDaemonProcs)
+	activity := matchingProcs(table, bdActivityProcs)
 
 	if dryRun {
-		return daemonsBefore, activityBefore, nil
+		return len(daemons), len(activity), nil
 	}
 
-	daemonsKilled, daemonsRemaining := stopBdDaemons(force)
-	activityKilled, activityRemaining := stopBdActivityProcesses(force)
+	daemonsKilled, daemonsRemaining := stopBdDaemons(daemons, force)
+	activityKilled, activityRemaining := stopBdActivityProcesses(activity, force)
 
 	if daemonsRemaining > 0 {
 		return daemonsKilled, activityKilled, fmt.Errorf("bd daemon shutdown incomplete: %d still running", daemonsRemaining)
@@ -159,32 +161,58 @@ func StopAllBdProcesses(dryRun, force bool) (int, int, error) {
 	return daemonsKilled, activityKilled, nil
 }
 
-// CountBdDaemons returns count of running bd daemons.
+// bdDaemonProcs and bdActivityProcs match bd's long-running processes by
+// their arguments, however bd was run, so that an editor with "bd daemon"
+// in a file name is never taken for one and killed.
+var (
+	bdDaemonProcs   = proc.MatchBasename(proc.MatchExactArgs("bd", "daemon"))
+	bdActivityProcs = proc.MatchBasename(proc.MatchExactArgs("bd", "activity"))
+)
+
+// snapshotProcs takes a snapshot of the running processes, or returns an
+// empty one where none can be taken. Zombies are left out: a daemon that
+// was killed but not yet reaped by its parent is not running.
+func snapshotProcs() *proc.Table {
+	table, err := proc.Snapshot()
+	if err != nil {
+		return proc.NewTable(nil)
+	}
+	return table.WithoutZombies()
+}
+
+// matchingProcs returns the processes in table that m matches, with the
+// start times KillTreeVerified and SignalAllVerified check.
+func matchingProcs(table *proc.Table, m proc.ArgvMatcher) []proc.ProcessInfo {
+	return table.Lookup(table.FindMatching(m))
+}
+
+// CountBdDaemons returns count of running bd daemons, not counting zombies.
 // Uses native /proc scanning instead of shell commands to avoid spawning overhead.
 func CountBdDaemons() int {
-	return proc.CountByPattern("bd daemon")
+	return proc.CountMatching(bdDaemonProcs)
 }
 
-
-func stopBdDaemons(force bool) (int, int) {
-	before := CountBdDaemons()
+// stopBdDaemons stops daemons, found by matchingProcs, and returns how
+// many were stopped and how many are still running.
+func stopBdDaemons(daemons []proc.ProcessInfo, force bool) (int, int) {
+	before := len(daemons)
 	if before == 0 {
 		return 0, 0
 	}
 
 	// Use native /proc scanning and syscalls instead of pkill shell commands.
 	// This avoids shell spawning overhead during shutdown.
-	pids := proc.FindByPattern("bd daemon")
-
+	// KillTree also reaps any children the daemons may have spawned. The
+	// daemons are killed one after another, so each is checked against
+	// its start time in case its PID was reused while others were waited on.
+	grace := proc.SIGTERMGracePeriod
 	if force {
-		proc.SignalAll(pids, syscall.SIGKILL)
-	} else {
-		proc.SignalAll(pids, syscall.SIGTERM)
-		time.Sleep(gracefulTimeout)
-		if remaining := CountBdDaemons(); remaining > 0 {
-			// Re-scan for any remaining and SIGKILL them
-			pids = proc.FindByPattern("bd daemon")
-			proc.SignalAll(pids, syscall.SIGKILL)
+		grace = 0 // SIGKILL straight away
+	}
+	for _, daemon := range daemons {
+		if _, _, err := proc.KillTreeVerified(daemon, grace); err == nil {
+			_ = events.LogAudit(events.TypeKill, "gt",
+				events.KillPayload("", fmt.Sprintf("bd daemon (PID %d)", daemon.PID), "stopping bd daemons"))
 		}
 	}
 
@@ -198,30 +226,31 @@ func stopBdDaemons(force bool) (int, int) {
 	return killed, final
 }
 
-// CountBdActivityProcesses returns count of running `bd activity` processes.
+// CountBdActivityProcesses returns count of running `bd activity` processes,
+// not counting zombies.
 // Uses native /proc scanning instead of shell commands to avoid spawning overhead.
 func CountBdActivityProcesses() int {
-	return proc.CountByPattern("bd activity")
+	return proc.CountMatching(bdActivityProcs)
 }
 
-func stopBdActivityProcesses(force bool) (int, int) {
-	before := CountBdActivityProcesses()
+// stopBdActivityProcesses stops procs, found by matchingProcs, and
+// returns how many were stopped and how many are still running.
+func stopBdActivityProcesses(procs []proc.ProcessInfo, force bool) (int, int) {
+	before := len(procs)
 	if before == 0 {
 		return 0, 0
 	}
 
 	// Use native /proc scanning and syscalls instead of pkill shell commands.
-	pids := proc.FindByPattern("bd activity")
-
+	// Start times guard against PIDs reused during the grace period.
 	if force {
-		proc.SignalAll(pids, syscall.SIGKILL)
+		proc.SignalAllVerified(procs, syscall.SIGKILL)
 	} else {
-		proc.SignalAll(pids, syscall.SIGTERM)
+		proc.SignalAllVerified(procs, syscall.SIGTERM)
 		time.Sleep(gracefulTimeout)
-		if remaining := CountBdActivityProcesses(); remaining > 0 {
-			// Re-scan for any remaining and SIGKILL them
-			pids = proc.FindByPattern("bd activity")
-			proc.SignalAll(pids, syscall.SIGKILL)
+		// Re-scan for any remaining and SIGKILL them
+		if remaining := matchingProcs(snapshotProcs(), bdActivityProcs); len(remaining) > 0 {
+			proc.SignalAllVerified(remaining, syscall.SIGKILL)
 		}
 	}
 
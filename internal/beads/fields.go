@@ -3,7 +3,11 @@ package beads
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 // Note: AgentFields, ParseAgentFields, FormatAgentDescription, and CreateAgentBead are in beads.go
@@ -187,6 +191,26 @@ type MRFields struct {
 	// Convoy tracking (for priority scoring - convoy starvation prevention)
 	ConvoyID        string // Parent convoy ID if part of a convoy
 	ConvoyCreatedAt string // Convoy creation time (ISO 8601) for starvation prevention
+
+	// Review workflow
+	ReviewerList []string // Reviewers whose approval is required
+	Reviews      []Review // Latest review from each reviewer
+}
+
+// Review status values.
+const (
+	ReviewPending          = "pending"
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes-requested"
+)
+
+// Review is one reviewer's verdict on a merge request.
+// Stored as a "review: <reviewer> <status> <at> <comment>" line.
+type Review struct {
+	Reviewer string
+	Status   string // pending, approved, changes-requested
+	Comment  string
+	At       time.Time
 }
 
 // ParseMRFields extracts structured merge-request fields from an issue's description.
@@ -261,6 +285,18 @@ func ParseMRFields(issue *Issue) *MRFields {
 		case "convoy_created_at", "convoy-created-at", "convoycreatedat":
 			fields.ConvoyCreatedAt = value
 			hasFields = true
+		case "reviewers":
+			for _, r := range strings.Split(value, ",") {
+				if r = strings.TrimSpace(r); r != "" {
+					fields.ReviewerList = append(fields.ReviewerList, r)
+				}
+			}
+			hasFields = true
+		case "review":
+			if r, ok := parseReview(value); ok {
+				fields.Reviews = append(fields.Reviews, r)
+				hasFields = true
+			}
 		}
 	}
 
@@ -270,6 +306,33 @@ func ParseMRFields(issue *Issue) *MRFields {
 	return fields
 }
 
+// parseReview parses a "review:" value: "<reviewer> <status> <RFC3339 time> [comment]".
+func parseReview(value string) (Review, bool) {
+	parts := strings.SplitN(value, " ", 4)
+	if len(parts) < 3 {
+		return Review{}, false
+	}
+	at, err := time.Parse(time.RFC3339, parts[2])
+	if err != nil {
+		return Review{}, false
+	}
+	r := Review{Reviewer: parts[0], Status: parts[1], At: at}
+	if len(parts) == 4 {
+		r.Comment = parts[3]
+	}
+	return r, true
+}
+
+// formatReview formats a review as a "review:" value. Newlines in the
+// comment are flattened so the review stays on one line.
+func formatReview(r Review) string {
+	line := fmt.Sprintf("%s %s %s", r.Reviewer, r.Status, r.At.UTC().Format(time.RFC3339))
+	if comment := strings.Join(strings.Fields(r.Comment), " "); comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
 // parseIntField parses an integer from a string, returning 0 on error.
 func parseIntField(s string) (int, error) {
 	var n int
@@ -325,6 +388,12 @@ func FormatMRFields(fields *MRFields) string {
 	if fields.ConvoyCreatedAt != "" {
 		lines = append(lines, "convoy_created_at: "+fields.ConvoyCreatedAt)
 	}
+	if len(fields.ReviewerList) > 0 {
+		lines = append(lines, "reviewers: "+strings.Join(fields.ReviewerList, ", "))
+	}
+	for _, r := range fields.Reviews {
+		lines = append(lines, "review: "+formatReview(r))
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -371,6 +440,8 @@ func SetMRFields(issue *Issue, fields *MRFields) string {
 		"convoy_created_at":  true,
 		"convoy-created-at":  true,
 		"convoycreatedat":    true,
+		"reviewers":          true,
+		"review":             true,
 	}
 
 	// Collect non-MR lines from existing description
@@ -522,6 +593,7 @@ type RoleConfig struct {
 	NeedsPreSync bool
 
 	// StartCommand is the command to run after creating the session.
+	// Supports the ExpandRoleVars placeholders, e.g. {rigdir}, {actor}, {env:HOME}.
 	// Default: "exec claude --dangerously-skip-permissions"
 	StartCommand string
 
@@ -664,3 +736,99 @@ func ExpandRolePattern(pattern, townRoot, rig, name, role string) string {
 	result = strings.ReplaceAll(result, "{role}", role)
 	return result
 }
+
+// RoleVars are the values ExpandRoleVars substitutes into a role pattern.
+type RoleVars struct {
+	Town        string // {town}: town root
+	Rig         string // {rig}: rig name
+	Name        string // {name}: agent name (polecat, crew)
+	Role        string // {role}: role type
+	RigDir      string // {rigdir}: absolute path to the rig directory
+	ConfigDir   string // {configdir}: path to the town's config/ directory
+	BeadsDir    string // {beadsdir}: path to the .beads/ directory the agent uses
+	SessionName string // {sessionname}: tmux session the agent runs in
+	Actor       string // {actor}: BD_ACTOR-style identity, e.g. "gongshow/witness"
+}
+
+var (
+	// rolePlaceholderRe matches {word} and {env:NAME} placeholders.
+	rolePlaceholderRe = regexp.MustCompile(`\{([a-z]+)(?::([A-Za-z_][A-Za-z0-9_]*))?\}`)
+
+	// roleBraceRe matches anything in braces, so UnknownPlaceholders can
+	// catch typos. Shell ${VAR} references are matched to be skipped.
+	roleBraceRe = regexp.MustCompile(`\$?\{[^{}\s]+\}`)
+)
+
+// lookup returns the value for a placeholder key, and whether it is known.
+func (v RoleVars) lookup(key, arg string) (string, bool) {
+	if key == "env" {
+		return os.Getenv(arg), arg != ""
+	}
+	if arg != "" {
+		return "", false
+	}
+	switch key {
+	case "town":
+		return v.Town, true
+	case "rig":
+		return v.Rig, true
+	case "name":
+		return v.Name, true
+	case "role":
+		return v.Role, true
+	case "rigdir":
+		return v.RigDir, true
+	case "configdir":
+		return v.ConfigDir, true
+	case "beadsdir":
+		return v.BeadsDir, true
+	case "sessionname":
+		return v.SessionName, true
+	case "actor":
+		return v.Actor, true
+	}
+	return "", false
+}
+
+// ExpandRoleVars expands placeholders in a pattern string. Besides the
+// ExpandRolePattern placeholders it supports {rigdir}, {configdir},
+// {beadsdir}, {sessionname}, {actor} and {env:VARNAME}. Unrecognised
+// placeholders are left as they are.
+func ExpandRoleVars(pattern string, vars RoleVars) string {
+	return rolePlaceholderRe.ReplaceAllStringFunc(pattern, func(m string) string {
+		sub := rolePlaceholderRe.FindStringSubmatch(m)
+		if value, ok := vars.lookup(sub[1], sub[2]); ok {
+			return value
+		}
+		return m
+	})
+}
+
+// UnknownPlaceholders returns the {...} placeholders in the config's patterns
+// that ExpandRoleVars doesn't recognise, each once.
+func (c *RoleConfig) UnknownPlaceholders() []string {
+	patterns := []string{c.SessionPattern, c.WorkDirPattern, c.StartCommand}
+	for _, v := range c.EnvVars {
+		patterns = append(patterns, v)
+	}
+
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, p := range patterns {
+		for _, m := range roleBraceRe.FindAllString(p, -1) {
+			if seen[m] || strings.HasPrefix(m, "$") {
+				continue
+			}
+			sub := rolePlaceholderRe.FindStringSubmatch(m)
+			if sub != nil && sub[0] == m {
+				if _, ok := (RoleVars{}).lookup(sub[1], sub[2]); ok {
+					continue
+				}
+			}
+			seen[m] = true
+			unknown = append(unknown, m)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
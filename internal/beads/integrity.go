@@ -0,0 +1,326 @@
+package beads
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// ChecksumsFile is the name of the file, in a .beads directory, holding the
+// checksum of each bead in issues.jsonl as gt last wrote it. bd owns the
+// bead JSON and drops fields it doesn't know when it exports, so the
+// checksums are kept beside it rather than in it.
+const ChecksumsFile = "checksums.json"
+
+// ErrCorrupt is returned when a bead's data no longer matches its checksum.
+var ErrCorrupt = errors.New("bead data corrupt")
+
+// ErrNoChecksum is returned when a bead has no recorded checksum to check.
+var ErrNoChecksum = errors.New("no checksum recorded")
+
+// ErrModified is returned when a bead changed, with a new updated_at, since
+// gt recorded its checksum: a bd write made outside gt, not corruption.
+var ErrModified = errors.New("bead modified outside gt")
+
+// BeadChecksum is the recorded checksum of one bead.
+type BeadChecksum struct {
+	Checksum  string `json:"_checksum"`
+	UpdatedAt string `json:"updated_at"` // The bead's updated_at when gt wrote it
+}
+
+// checksumManifest is the content of the checksums file, keyed by bead ID.
+type checksumManifest map[string]BeadChecksum
+
+// ComputeChecksum returns the hex SHA-256 of data.
+func ComputeChecksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// beadRecord is one line of issues.jsonl.
+type beadRecord struct {
+	ID        string
+	UpdatedAt string
+	Checksum  string // Of the line's canonical JSON
+	Err       error  // Set when the line doesn't parse
+	Line      int
+}
+
+// VerifyIntegrity checks bead id in the store's issues.jsonl against the
+// checksum recorded when gt last wrote it, and returns an error wrapping
+// ErrCorrupt if it doesn't match. A bead whose updated_at changed too was
+// written by bd outside gt: its checksum is taken again and an error
+// wrapping ErrModified is returned. Returns ErrNotFound if the bead isn't
+// in the file, and ErrNoChecksum if gt hasn't written it since checksums
+// were kept.
+func (b *Beads) VerifyIntegrity(id string) error {
+	records, err := readBeadRecords(b.jsonlPath(), map[string]bool{id: true})
+	if err != nil {
+		return err
+	}
+	manifest, err := loadChecksumManifest(b.checksumsPath())
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		if rec.ID == id {
+			err := manifest.verify(rec)
+			if errors.Is(err, ErrModified) {
+				if recErr := b.RecordChecksums(id); recErr != nil {
+					return recErr
+				}
+			}
+			return err
+		}
+	}
+	return fmt.Errorf("%s: %w", id, ErrNotFound)
+}
+
+// VerifyAll checks every bead in the store's issues.jsonl as VerifyIntegrity
+// does, and returns how many matched, how many were modified outside gt
+// (whose checksums are taken again), and how many are corrupt. Lines that
+// don't parse count as corrupt; beads with no recorded checksum are
+// skipped.
+func (b *Beads) VerifyAll() (valid, modified, corrupt int, err error) {
+	records, err := readBeadRecords(b.jsonlPath(), nil)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	manifest, err := loadChecksumManifest(b.checksumsPath())
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var refresh []string
+	for _, rec := range records {
+		switch err := manifest.verify(rec); {
+		case err == nil:
+			valid++
+		case errors.Is(err, ErrNoChecksum):
+		case errors.Is(err, ErrModified):
+			modified++
+			refresh = append(refresh, rec.ID)
+		default:
+			corrupt++
+		}
+	}
+	if len(refresh) > 0 {
+		if err := b.RecordChecksums(refresh...); err != nil {
+			return valid, modified, corrupt, err
+		}
+	}
+	return valid, modified, corrupt, nil
+}
+
+// RecordChecksums records the checksums of beads ids as they are now in
+// the store's issues.jsonl, and forgets those of ids no longer in it. gt
+// calls it after each bd command that writes beads; with no ids, every
+// bead in the file is recorded, as gt beads verify --record does.
+func (b *Beads) RecordChecksums(ids ...string) error {
+	var want map[string]bool
+	if len(ids) > 0 {
+		want = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			want[id] = true
+		}
+	}
+	records, err := readBeadRecords(b.jsonlPath(), want)
+	if err != nil {
+		return err
+	}
+
+	lock := flock.New(b.checksumsPath() + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking checksums: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	manifest, err := loadChecksumManifest(b.checksumsPath())
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]beadRecord, len(records))
+	for _, rec := range records {
+		if rec.Err == nil {
+			current[rec.ID] = rec
+		}
+	}
+	all := len(ids) == 0
+	if all {
+		manifest = checksumManifest{}
+		for id := range current {
+			ids = append(ids, id)
+		}
+	}
+
+	changed := false
+	for _, id := range ids {
+		rec, ok := current[id]
+		recorded, had := manifest[id]
+		switch {
+		case ok && (!had || recorded.Checksum != rec.Checksum):
+			manifest[id] = BeadChecksum{Checksum: rec.Checksum, UpdatedAt: rec.UpdatedAt}
+			changed = true
+		case !ok && had:
+			delete(manifest, id)
+			changed = true
+		}
+	}
+	if !changed && !all {
+		return nil
+	}
+	if err := util.AtomicWriteJSON(b.checksumsPath(), manifest); err != nil {
+		return fmt.Errorf("recording checksums: %w", err)
+	}
+	return nil
+}
+
+// checksummedCommands are the bd commands that write beads; run records
+// the checksums of the beads they name after they succeed.
+var checksummedCommands = map[string]bool{
+	"create": true, "update": true, "close": true, "reopen": true,
+	"delete": true, "label": true, "dep": true, "comment": true, "comments": true,
+}
+
+// recordWrittenChecksums records the checksums of the beads a successful
+// bd command wrote: those named in its arguments, and for create the one
+// in its JSON output. Arguments that aren't bead IDs match no bead and are
+// ignored. Best-effort: a store without issues.jsonl has nothing to record.
+func (b *Beads) recordWrittenChecksums(args []string, out []byte) {
+	if len(args) == 0 || !checksummedCommands[args[0]] {
+		return
+	}
+	var ids []string
+	for _, arg := range args[1:] {
+		if !strings.HasPrefix(arg, "-") {
+			ids = append(ids, arg)
+		}
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if args[0] == "create" && json.Unmarshal(out, &created) == nil && created.ID != "" {
+		ids = append(ids, created.ID)
+	}
+	if _, err := os.Stat(b.jsonlPath()); err == nil && len(ids) > 0 {
+		_ = b.RecordChecksums(ids...)
+	}
+}
+
+// verify checks rec against the checksum recorded for it. A bead whose
+// content changed along with its updated_at is modified, not corrupt.
+func (m checksumManifest) verify(rec beadRecord) error {
+	if rec.Err != nil {
+		return fmt.Errorf("line %d: %w: %v", rec.Line, ErrCorrupt, rec.Err)
+	}
+	recorded, ok := m[rec.ID]
+	if !ok {
+		return fmt.Errorf("%s: %w", rec.ID, ErrNoChecksum)
+	}
+	switch {
+	case recorded.Checksum == rec.Checksum:
+		return nil
+	case recorded.UpdatedAt != rec.UpdatedAt:
+		return fmt.Errorf("%s: %w: updated_at %s, %s when gt wrote it", rec.ID, ErrModified, rec.UpdatedAt, recorded.UpdatedAt)
+	default:
+		return fmt.Errorf("%s: %w: checksum %s, recorded %s", rec.ID, ErrCorrupt, shortChecksum(rec.Checksum), shortChecksum(recorded.Checksum))
+	}
+}
+
+// readBeadRecords reads issues.jsonl, checksumming each bead's canonical
+// JSON (keys sorted) so a re-export that only reorders fields still matches.
+// With want set, only those beads are parsed and returned; lines that don't
+// mention any of them are skipped unparsed.
+func readBeadRecords(path string, want map[string]bool) ([]beadRecord, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+
+	var records []beadRecord
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if want != nil && !mentionsAny(line, want) {
+			continue
+		}
+		rec := beadRecord{Line: i + 1}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(line, &fields); err != nil {
+			rec.Err = err
+			records = append(records, rec)
+			continue
+		}
+		rec.ID, _ = fields["id"].(string)
+		rec.UpdatedAt, _ = fields["updated_at"].(string)
+		if rec.ID == "" {
+			rec.Err = errors.New("no id")
+			records = append(records, rec)
+			continue
+		}
+		if want != nil && !want[rec.ID] {
+			continue
+		}
+		canonical, err := json.Marshal(fields)
+		if err != nil {
+			rec.Err = err
+		}
+		rec.Checksum = ComputeChecksum(canonical)
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// mentionsAny reports whether line contains any of ids as a JSON string.
+func mentionsAny(line []byte, ids map[string]bool) bool {
+	for id := range ids {
+		if bytes.Contains(line, []byte(strconv.Quote(id))) {
+			return true
+		}
+	}
+	return false
+}
+
+func loadChecksumManifest(path string) (checksumManifest, error) {
+	manifest := checksumManifest{}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading checksums: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing checksums: %w", err)
+	}
+	return manifest, nil
+}
+
+func (b *Beads) jsonlPath() string {
+	return filepath.Join(b.resolvedBeadsDir(), "issues.jsonl")
+}
+
+func (b *Beads) checksumsPath() string {
+	return filepath.Join(b.resolvedBeadsDir(), ChecksumsFile)
+}
+
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
+}
@@ -0,0 +1,148 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const integrityJSONL = `{"id":"gt-1","title":"Fix login","status":"open","updated_at":"2026-01-02T10:00:00Z"}
+{"id":"gt-2","title":"Add docs","status":"open","updated_at":"2026-01-02T11:00:00Z"}
+`
+
+func writeIssuesJSONL(t *testing.T, beadsDir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(beadsDir, "issues.jsonl"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyIntegrityDetectsCorruption(t *testing.T) {
+	beadsDir := t.TempDir()
+	b := NewWithBeadsDir(beadsDir, beadsDir)
+	writeIssuesJSONL(t, beadsDir, integrityJSONL)
+
+	// Nothing is recorded on sight.
+	if err := b.VerifyIntegrity("gt-1"); !errors.Is(err, ErrNoChecksum) {
+		t.Fatalf("VerifyIntegrity(unrecorded gt-1) = %v, want ErrNoChecksum", err)
+	}
+	if valid, _, corrupt, err := b.VerifyAll(); err != nil || valid != 0 || corrupt != 0 {
+		t.Fatalf("VerifyAll() before recording = %d, %d, %v; want 0, 0, nil", valid, corrupt, err)
+	}
+
+	if err := b.RecordChecksums(); err != nil {
+		t.Fatalf("RecordChecksums() = %v", err)
+	}
+	valid, _, corrupt, err := b.VerifyAll()
+	if err != nil || valid != 2 || corrupt != 0 {
+		t.Fatalf("VerifyAll() = %d, %d, %v; want 2, 0, nil", valid, corrupt, err)
+	}
+	if err := b.VerifyIntegrity("gt-1"); err != nil {
+		t.Errorf("VerifyIntegrity(gt-1) = %v, want nil", err)
+	}
+
+	// Flip a character in gt-1's title on disk, leaving updated_at alone.
+	writeIssuesJSONL(t, beadsDir, strings.Replace(integrityJSONL, "Fix login", "Fix logim", 1))
+	if err := b.VerifyIntegrity("gt-1"); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("VerifyIntegrity(corrupted gt-1) = %v, want ErrCorrupt", err)
+	}
+	if err := b.VerifyIntegrity("gt-2"); err != nil {
+		t.Errorf("VerifyIntegrity(gt-2) = %v, want nil", err)
+	}
+	valid, _, corrupt, err = b.VerifyAll()
+	if err != nil || valid != 1 || corrupt != 1 {
+		t.Errorf("VerifyAll() after corruption = %d, %d, %v; want 1, 1, nil", valid, corrupt, err)
+	}
+
+	// A truncated line is corrupt too.
+	writeIssuesJSONL(t, beadsDir, integrityJSONL[:len(integrityJSONL)-20])
+	if _, _, corrupt, _ := b.VerifyAll(); corrupt != 1 {
+		t.Errorf("VerifyAll() with a truncated line: corrupt = %d, want 1", corrupt)
+	}
+
+	if err := b.VerifyIntegrity("gt-missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("VerifyIntegrity(gt-missing) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestVerifyAllRefreshesModified(t *testing.T) {
+	beadsDir := t.TempDir()
+	b := NewWithBeadsDir(beadsDir, beadsDir)
+	writeIssuesJSONL(t, beadsDir, integrityJSONL)
+	if err := b.RecordChecksums(); err != nil {
+		t.Fatal(err)
+	}
+
+	// bd close run outside gt: new content and a new updated_at.
+	closed := strings.Replace(integrityJSONL, `"open","updated_at":"2026-01-02T10:00:00Z"`, `"closed","updated_at":"2026-01-09T10:00:00Z"`, 1)
+	writeIssuesJSONL(t, beadsDir, closed)
+	valid, modified, corrupt, err := b.VerifyAll()
+	if err != nil || valid != 1 || modified != 1 || corrupt != 0 {
+		t.Fatalf("VerifyAll() after an outside write = %d, %d, %d, %v; want 1, 1, 0, nil", valid, modified, corrupt, err)
+	}
+
+	// The checksum was taken again, so the bead now verifies.
+	if err := b.VerifyIntegrity("gt-1"); err != nil {
+		t.Errorf("VerifyIntegrity(gt-1) after refresh = %v, want nil", err)
+	}
+
+	// Same for a single bead.
+	writeIssuesJSONL(t, beadsDir, strings.Replace(closed, `"open","updated_at":"2026-01-02T11:00:00Z"`, `"closed","updated_at":"2026-01-09T11:00:00Z"`, 1))
+	if err := b.VerifyIntegrity("gt-2"); !errors.Is(err, ErrModified) {
+		t.Errorf("VerifyIntegrity(gt-2 closed outside gt) = %v, want ErrModified", err)
+	}
+	if err := b.VerifyIntegrity("gt-2"); err != nil {
+		t.Errorf("VerifyIntegrity(gt-2) after refresh = %v, want nil", err)
+	}
+}
+
+func TestRecordWrittenChecksums(t *testing.T) {
+	beadsDir := t.TempDir()
+	b := NewWithBeadsDir(beadsDir, beadsDir)
+	writeIssuesJSONL(t, beadsDir, integrityJSONL)
+	if err := b.RecordChecksums(); err != nil {
+		t.Fatal(err)
+	}
+
+	// gt updates gt-1 through bd, which re-exports it (field order may
+	// change) and creates gt-3.
+	updated := `{"updated_at":"2026-01-03T09:00:00Z","id":"gt-1","status":"closed","title":"Fix login"}
+{"status":"open","title":"Add docs","id":"gt-2","updated_at":"2026-01-02T11:00:00Z"}
+{"id":"gt-3","title":"New","status":"open","updated_at":"2026-01-03T09:00:00Z"}
+`
+	writeIssuesJSONL(t, beadsDir, updated)
+	b.recordWrittenChecksums([]string{"close", "gt-1", "--reason=done"}, nil)
+	b.recordWrittenChecksums([]string{"create", "--json", "--title=New"}, []byte(`{"id":"gt-3"}`))
+	valid, _, corrupt, err := b.VerifyAll()
+	if err != nil || valid != 3 || corrupt != 0 {
+		t.Errorf("VerifyAll() after gt's writes = %d, %d, %v; want 3, 0, nil", valid, corrupt, err)
+	}
+
+	// A read doesn't record anything.
+	writeIssuesJSONL(t, beadsDir, strings.Replace(updated, "Add docs", "Add dogs", 1))
+	b.recordWrittenChecksums([]string{"show", "gt-2", "--json"}, nil)
+	if err := b.VerifyIntegrity("gt-2"); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("VerifyIntegrity(gt-2) after a show = %v, want ErrCorrupt", err)
+	}
+
+	// Deleting a bead forgets its checksum.
+	writeIssuesJSONL(t, beadsDir, strings.Split(updated, "\n")[0]+"\n")
+	b.recordWrittenChecksums([]string{"delete", "gt-3", "--force"}, nil)
+	manifest, err := loadChecksumManifest(filepath.Join(beadsDir, ChecksumsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := manifest["gt-3"]; ok {
+		t.Error("checksum of deleted gt-3 still recorded")
+	}
+}
+
+func TestComputeChecksum(t *testing.T) {
+	got := ComputeChecksum([]byte("abc"))
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+	if got != want {
+		t.Errorf("ComputeChecksum(abc) = %s, want %s", got, want)
+	}
+}
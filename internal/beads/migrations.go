@@ -0,0 +1,171 @@
+package beads
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// MigrationsFile is the name of the file, in a .beads directory, that
+// records which schema migrations have been applied to it.
+const MigrationsFile = "migrations.json"
+
+// MigrationFunc converts beads or config from one schema version to the
+// next. It returns the number of items (beads, config entries) it changed.
+type MigrationFunc func(b *Beads) (int, error)
+
+// Migration is a schema migration, registered under the version it
+// migrates to (e.g. "v2").
+type Migration struct {
+	Name string // Short identifier, e.g. "agent-fields"
+	From string // Version migrated from, e.g. "v1"
+	Run  MigrationFunc
+}
+
+// migrations are the registered migrations, keyed by the version they
+// migrate to. RunMigrations applies the ones not yet recorded as applied.
+var migrations = map[string]Migration{}
+
+// RegisterMigration registers m as the migration to version, typically from
+// an init function in the file that defines it. It panics if m has no Run
+// func or a migration to version is already registered.
+func RegisterMigration(version string, m Migration) {
+	if m.Run == nil {
+		panic("beads: RegisterMigration " + version + " has no Run func")
+	}
+	if _, dup := migrations[version]; dup {
+		panic("beads: RegisterMigration called twice for " + version)
+	}
+	migrations[version] = m
+}
+
+// AppliedMigration records one migration in the migrations file.
+type AppliedMigration struct {
+	Version        string    `json:"version"`
+	Name           string    `json:"name"`
+	From           string    `json:"from"`
+	ItemsConverted int       `json:"items_converted"`
+	AppliedAt      time.Time `json:"applied_at"`
+}
+
+// MigrationState is the content of the migrations file.
+type MigrationState struct {
+	Applied []AppliedMigration `json:"applied"`
+}
+
+// MigrationsPath returns the migrations file for a .beads directory.
+func MigrationsPath(beadsDir string) string {
+	return filepath.Join(beadsDir, MigrationsFile)
+}
+
+// LoadMigrationState reads the migrations file at path. A missing file
+// means no migrations have been applied.
+func LoadMigrationState(path string) (*MigrationState, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return &MigrationState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations file: %w", err)
+	}
+	var state MigrationState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing migrations file: %w", err)
+	}
+	return &state, nil
+}
+
+// IsApplied reports whether the migration to version has been applied.
+func (s *MigrationState) IsApplied(version string) bool {
+	for _, m := range s.Applied {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// RunMigrations applies the registered migrations not yet listed in the
+// migrations file at statePath, in version order, and returns how many ran.
+// Each is recorded in the file as soon as it succeeds and logged as a
+// migration event, so a run that fails partway resumes at the failed
+// migration next time.
+func RunMigrations(b *Beads, statePath string) (int, error) {
+	return runMigrations(b, statePath, migrations)
+}
+
+// MigrateBeadsDir runs the pending migrations on the beads in beadsDir,
+// recording them in its migrations file; see RunMigrations.
+func MigrateBeadsDir(workDir, beadsDir string) (int, error) {
+	return RunMigrations(NewWithBeadsDir(workDir, beadsDir), MigrationsPath(beadsDir))
+}
+
+func runMigrations(b *Beads, statePath string, registry map[string]Migration) (int, error) {
+	state, err := LoadMigrationState(statePath)
+	if err != nil {
+		return 0, err
+	}
+
+	versions := make([]string, 0, len(registry))
+	for version := range registry {
+		if !state.IsApplied(version) {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return compareVersions(versions[i], versions[j]) < 0 })
+
+	ran := 0
+	for _, version := range versions {
+		m := registry[version]
+		converted, err := m.Run(b)
+		if err != nil {
+			return ran, fmt.Errorf("migration %s (%s → %s): %w", m.Name, m.From, version, err)
+		}
+
+		state.Applied = append(state.Applied, AppliedMigration{
+			Version:        version,
+			Name:           m.Name,
+			From:           m.From,
+			ItemsConverted: converted,
+			AppliedAt:      time.Now().UTC(),
+		})
+		if err := util.AtomicWriteJSON(statePath, state); err != nil {
+			return ran, fmt.Errorf("recording migration %s: %w", m.Name, err)
+		}
+		ran++
+
+		_ = events.Log(events.TypeMigration, "gt",
+			events.MigrationPayload(m.Name, m.From, version, converted), events.VisibilityBoth)
+	}
+	return ran, nil
+}
+
+// compareVersions orders versions like "v2" and "v1.10" by their numeric
+// parts, so "v10" sorts after "v9". Parts that aren't numbers compare as
+// strings.
+func compareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		na, errA := strconv.Atoi(pa[i])
+		nb, errB := strconv.Atoi(pb[i])
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			if na < nb {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && pa[i] != pb[i]:
+			return strings.Compare(pa[i], pb[i])
+		}
+	}
+	return len(pa) - len(pb)
+}
@@ -0,0 +1,199 @@
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// setupMigrationTown creates a town to run migrations in, so their events
+// land in its audit log. Returns the migrations file path.
+func setupMigrationTown(t *testing.T) (townRoot, statePath string) {
+	t.Helper()
+	townRoot = t.TempDir()
+	beadsDir := filepath.Join(townRoot, ".beads")
+	for _, dir := range []string{filepath.Join(townRoot, "mayor"), beadsDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	return townRoot, MigrationsPath(beadsDir)
+}
+
+func readMigrationEvents(t *testing.T, townRoot string) []events.Event {
+	t.Helper()
+	f, err := os.Open(filepath.Join(townRoot, events.AuditFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var out []events.Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil && e.Type == events.TypeMigration {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestRunMigrations_V1ToV2(t *testing.T) {
+	townRoot, statePath := setupMigrationTown(t)
+
+	// A sample v1 → v2 migration: rename a legacy field on every item.
+	items := []map[string]string{{"assignee": "toast"}, {"assignee": "nux"}, {"owner": "max"}}
+	registry := map[string]Migration{
+		"v2": {Name: "assignee-to-owner", From: "v1", Run: func(*Beads) (int, error) {
+			converted := 0
+			for _, item := range items {
+				if v, ok := item["assignee"]; ok {
+					item["owner"] = v
+					delete(item, "assignee")
+					converted++
+				}
+			}
+			return converted, nil
+		}},
+	}
+
+	ran, err := runMigrations(New(townRoot), statePath, registry)
+	if err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+	if ran != 1 {
+		t.Errorf("ran = %d, want 1", ran)
+	}
+	for _, item := range items {
+		if _, ok := item["assignee"]; ok {
+			t.Errorf("item not migrated: %v", item)
+		}
+	}
+
+	state, err := LoadMigrationState(statePath)
+	if err != nil {
+		t.Fatalf("LoadMigrationState: %v", err)
+	}
+	if len(state.Applied) != 1 || state.Applied[0].Version != "v2" || state.Applied[0].ItemsConverted != 2 {
+		t.Errorf("applied = %+v, want v2 with 2 items", state.Applied)
+	}
+
+	evts := readMigrationEvents(t, townRoot)
+	if len(evts) != 1 {
+		t.Fatalf("got %d migration events, want 1", len(evts))
+	}
+	p := evts[0].Payload
+	if p["name"] != "assignee-to-owner" || p["from"] != "v1" || p["to"] != "v2" || p["items_converted"] != float64(2) {
+		t.Errorf("payload = %v", p)
+	}
+
+	// Already applied: a second run does nothing.
+	ran, err = runMigrations(New(townRoot), statePath, registry)
+	if err != nil || ran != 0 {
+		t.Errorf("second run = %d, %v; want 0, nil", ran, err)
+	}
+	if n := len(readMigrationEvents(t, townRoot)); n != 1 {
+		t.Errorf("got %d migration events after rerun, want 1", n)
+	}
+}
+
+func TestRunMigrations_OrderAndResume(t *testing.T) {
+	townRoot, statePath := setupMigrationTown(t)
+
+	var order []string
+	failV3 := true
+	step := func(version string) MigrationFunc {
+		return func(*Beads) (int, error) {
+			if version == "v3" && failV3 {
+				return 0, errors.New("boom")
+			}
+			order = append(order, version)
+			return 0, nil
+		}
+	}
+	registry := map[string]Migration{
+		"v10": {Name: "ten", From: "v3", Run: step("v10")},
+		"v2":  {Name: "two", From: "v1", Run: step("v2")},
+		"v3":  {Name: "three", From: "v2", Run: step("v3")},
+	}
+
+	ran, err := runMigrations(New(townRoot), statePath, registry)
+	if err == nil {
+		t.Fatal("runMigrations succeeded despite a failing migration")
+	}
+	if ran != 1 || !reflect.DeepEqual(order, []string{"v2"}) {
+		t.Errorf("first run: ran %d, order %v; want 1, [v2]", ran, order)
+	}
+
+	failV3 = false
+	ran, err = runMigrations(New(townRoot), statePath, registry)
+	if err != nil {
+		t.Fatalf("resumed run: %v", err)
+	}
+	if ran != 2 || !reflect.DeepEqual(order, []string{"v2", "v3", "v10"}) {
+		t.Errorf("resumed run: ran %d, order %v; want 2, [v2 v3 v10]", ran, order)
+	}
+	if n := len(readMigrationEvents(t, townRoot)); n != 3 {
+		t.Errorf("got %d migration events, want 3", n)
+	}
+}
+
+func TestRegisterMigration(t *testing.T) {
+	orig := migrations
+	migrations = map[string]Migration{}
+	t.Cleanup(func() { migrations = orig })
+
+	m := Migration{Name: "agent-fields", From: "v1", Run: func(*Beads) (int, error) { return 0, nil }}
+	RegisterMigration("v2", m)
+	if got, ok := migrations["v2"]; !ok || got.Name != "agent-fields" {
+		t.Fatalf("migrations[v2] = %+v, %v; want the registered migration", got, ok)
+	}
+
+	for name, register := range map[string]func(){
+		"duplicate": func() { RegisterMigration("v2", m) },
+		"no Run":    func() { RegisterMigration("v3", Migration{Name: "empty", From: "v2"}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterMigration (%s) did not panic", name)
+				}
+			}()
+			register()
+		}()
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1", "v2", -1},
+		{"v9", "v10", -1},
+		{"v2", "v2", 0},
+		{"v1.10", "v1.9", 1},
+		{"v1", "v1.1", -1},
+		{"2", "v3", -1},
+	}
+	for _, tt := range tests {
+		got := compareVersions(tt.a, tt.b)
+		if (got < 0) != (tt.want < 0) || (got > 0) != (tt.want > 0) {
+			t.Errorf("compareVersions(%q, %q) = %d, want sign of %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package beads
+
+import (
+	"path/filepath"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// LoadRoleConfig returns the config in the town's role bead for role, or
+// nil if there is no role bead.
+func LoadRoleConfig(townRoot, role string) (*RoleConfig, error) {
+	return NewWithBeadsDir(townRoot, ResolveBeadsDir(townRoot)).GetRoleConfig(RoleBeadIDTown(role))
+}
+
+// AgentRoleVars returns the placeholder values for the agent cfg describes,
+// running in sessionName. {beadsdir} is the rig's beads for a rig agent and
+// the town's for a town agent.
+func AgentRoleVars(cfg config.AgentEnvConfig, sessionName string) RoleVars {
+	vars := RoleVars{
+		Town:        cfg.TownRoot,
+		Rig:         cfg.Rig,
+		Name:        cfg.AgentName,
+		Role:        cfg.Role,
+		ConfigDir:   filepath.Join(cfg.TownRoot, "config"),
+		BeadsDir:    ResolveBeadsDir(cfg.TownRoot),
+		SessionName: sessionName,
+		Actor:       config.AgentEnv(cfg)["BD_ACTOR"],
+	}
+	if cfg.Rig != "" {
+		vars.RigDir = filepath.Join(cfg.TownRoot, cfg.Rig)
+		vars.BeadsDir = ResolveBeadsDir(vars.RigDir)
+	}
+	return vars
+}
+
+// AgentEnv returns the environment an agent is started with in sessionName:
+// config.AgentEnv for cfg, overridden by the env vars in roleConfig (which
+// may be nil) expanded for the agent. Every start path sets this, and gt
+// env reports it.
+func AgentEnv(cfg config.AgentEnvConfig, sessionName string, roleConfig *RoleConfig) map[string]string {
+	env := config.AgentEnv(cfg)
+	if roleConfig == nil || len(roleConfig.EnvVars) == 0 {
+		return env
+	}
+	vars := AgentRoleVars(cfg, sessionName)
+	for k, v := range roleConfig.EnvVars {
+		env[k] = ExpandRoleVars(v, vars)
+	}
+	return env
+}
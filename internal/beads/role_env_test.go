@@ -0,0 +1,44 @@
+package beads
+
+import (
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+func TestAgentEnv_RoleConfigOverridesBase(t *testing.T) {
+	roleConfig := &RoleConfig{
+		EnvVars: map[string]string{
+			"GT_SESSION":      "{sessionname}",
+			"GT_WHO":          "{actor}",
+			"GT_HOME":         "{rigdir}/crew/{name}",
+			"GIT_AUTHOR_NAME": "crew-{name}",
+		},
+	}
+	cfg := config.AgentEnvConfig{Role: "crew", Rig: "gongshow", AgentName: "max", TownRoot: "/town", BeadsNoDaemon: true}
+
+	env := AgentEnv(cfg, "gt-gongshow-crew-max", roleConfig)
+	for k, want := range map[string]string{
+		"GT_ROLE":         "crew",
+		"BEADS_NO_DAEMON": "1",
+		"GT_SESSION":      "gt-gongshow-crew-max",
+		"GT_WHO":          "gongshow/crew/max",
+		"GT_HOME":         "/town/gongshow/crew/max",
+		"GIT_AUTHOR_NAME": "crew-max", // Role config wins over the base
+	} {
+		if env[k] != want {
+			t.Errorf("AgentEnv()[%s] = %q, want %q", k, env[k], want)
+		}
+	}
+
+	if got := AgentEnv(cfg, "gt-gongshow-crew-max", nil); got["GIT_AUTHOR_NAME"] != "max" {
+		t.Errorf("AgentEnv() without role config GIT_AUTHOR_NAME = %q, want the base's", got["GIT_AUTHOR_NAME"])
+	}
+}
+
+func TestAgentRoleVars_TownAgent(t *testing.T) {
+	vars := AgentRoleVars(config.AgentEnvConfig{Role: "mayor", TownRoot: "/town"}, "hq-mayor")
+	if vars.RigDir != "" || vars.BeadsDir != "/town/.beads" || vars.Actor != "mayor" {
+		t.Errorf("AgentRoleVars(mayor) = %+v, want no rig dir, the town's beads and actor mayor", vars)
+	}
+}
@@ -0,0 +1,190 @@
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Bead types counted by BeadStats, from a bead's gt: type label.
+var statsTypeLabels = map[string]string{
+	"gt:agent":         "agent",
+	"gt:escalation":    "escalation",
+	"gt:merge-request": "mr",
+}
+
+// BeadStoreStats describes the size and makeup of a bead store.
+type BeadStoreStats struct {
+	Total int `json:"total"`
+
+	// ByState counts beads by state: an agent bead's agent_state, and
+	// every other bead's status.
+	ByState map[string]int `json:"by_state"`
+
+	// ByType counts agent, escalation and merge request ("mr") beads,
+	// and beads delegated from another ("delegation"). The rest count as
+	// "other".
+	ByType map[string]int `json:"by_type"`
+
+	Oldest      *BeadStatsEntry `json:"oldest,omitempty"`
+	LargestBead *BeadStatsEntry `json:"largest_bead,omitempty"` // By bytes in the store
+	LargestFile *BeadStatsFile  `json:"largest_file,omitempty"` // In the .beads directory
+
+	StoreBytes int64 `json:"store_bytes"` // Total size of the .beads directory
+	Unreadable int   `json:"unreadable"`  // Beads (issues.jsonl lines) that don't parse
+}
+
+// BeadStatsEntry identifies one bead in BeadStoreStats.
+type BeadStatsEntry struct {
+	ID        string `json:"id"`
+	CreatedAt string `json:"created_at,omitempty"`
+	Bytes     int    `json:"bytes,omitempty"`
+}
+
+// BeadStatsFile is a file in the store's .beads directory.
+type BeadStatsFile struct {
+	Path  string `json:"path"` // Relative to the .beads directory
+	Bytes int64  `json:"bytes"`
+}
+
+// statsRecord is the part of a bead BeadStats reads.
+type statsRecord struct {
+	ID            string          `json:"id"`
+	Status        string          `json:"status"`
+	Description   string          `json:"description"`
+	CreatedAt     string          `json:"created_at"`
+	Labels        []string        `json:"labels"`
+	AgentState    string          `json:"agent_state"`
+	DelegatedFrom json.RawMessage `json:"delegated_from"`
+	Slots         map[string]any  `json:"slots"`
+}
+
+// BeadStats reads the beads in store, without going through bd, and
+// returns its stats. For a store on disk (such as Beads.Store) the stats
+// also cover the files of its directory and the lines it can't read.
+func BeadStats(store *Store) (*BeadStoreStats, error) {
+	ids, err := store.List("")
+	if err != nil {
+		return nil, fmt.Errorf("listing beads: %w", err)
+	}
+
+	stats := &BeadStoreStats{
+		ByState: make(map[string]int),
+		ByType:  make(map[string]int),
+	}
+	for _, id := range ids {
+		data, err := store.Backend().Read(id)
+		if errors.Is(err, ErrNotFound) {
+			continue // Deleted since listed
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bead %s: %w", id, err)
+		}
+		var rec statsRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			stats.Unreadable++
+			continue
+		}
+		rec.ID = id
+		stats.add(rec, len(data))
+	}
+
+	if u, ok := store.Backend().(interface{ Unreadable() (int, error) }); ok {
+		n, err := u.Unreadable()
+		if err != nil {
+			return nil, err
+		}
+		stats.Unreadable += n
+	}
+	if d, ok := store.Backend().(interface{ Dir() string }); ok {
+		if err := stats.addFiles(d.Dir()); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (s *BeadStoreStats) add(rec statsRecord, size int) {
+	s.Total++
+
+	beadType := "other"
+	for _, label := range rec.Labels {
+		if t, ok := statsTypeLabels[label]; ok {
+			beadType = t
+			break
+		}
+	}
+	if beadType == "other" && rec.delegated() {
+		beadType = "delegation"
+	}
+	s.ByType[beadType]++
+
+	state := rec.Status
+	if beadType == "agent" {
+		if agentState := rec.agentState(); agentState != "" {
+			state = agentState
+		}
+	}
+	if state == "" {
+		state = "unknown"
+	}
+	s.ByState[state]++
+
+	if rec.CreatedAt != "" && (s.Oldest == nil || rec.CreatedAt < s.Oldest.CreatedAt) {
+		s.Oldest = &BeadStatsEntry{ID: rec.ID, CreatedAt: rec.CreatedAt}
+	}
+	if s.LargestBead == nil || size > s.LargestBead.Bytes {
+		s.LargestBead = &BeadStatsEntry{ID: rec.ID, Bytes: size}
+	}
+}
+
+// addFiles adds up the files in the .beads directory.
+func (s *BeadStoreStats) addFiles(beadsDir string) error {
+	err := filepath.WalkDir(beadsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == beadsDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // Removed while walking
+		}
+		s.StoreBytes += info.Size()
+		if s.LargestFile == nil || info.Size() > s.LargestFile.Bytes {
+			rel, _ := filepath.Rel(beadsDir, path)
+			s.LargestFile = &BeadStatsFile{Path: rel, Bytes: info.Size()}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", beadsDir, err)
+	}
+	return nil
+}
+
+// agentState returns the bead's agent_state column, falling back to the
+// agent_state line of its description.
+func (r statsRecord) agentState() string {
+	if r.AgentState != "" {
+		return r.AgentState
+	}
+	return ParseAgentFields(r.Description).AgentState
+}
+
+// delegated reports whether the bead has a delegated_from slot.
+func (r statsRecord) delegated() bool {
+	if v := strings.TrimSpace(string(r.DelegatedFrom)); v != "" && v != "null" && v != `""` {
+		return true
+	}
+	v, ok := r.Slots["delegated_from"]
+	return ok && v != nil && v != ""
+}
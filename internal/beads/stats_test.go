@@ -0,0 +1,94 @@
+package beads
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const statsJSONL = `{"id":"gt-mayor","title":"mayor","status":"open","labels":["gt:agent"],"agent_state":"working","created_at":"2026-01-03T10:00:00Z"}
+{"id":"gt-deacon","title":"deacon","status":"open","labels":["gt:agent"],"description":"role_type: deacon\nagent_state: idle","created_at":"2026-01-02T10:00:00Z"}
+{"id":"gt-esc1","title":"Refinery stuck","status":"open","labels":["gt:escalation"],"created_at":"2026-01-04T10:00:00Z"}
+{"id":"gt-mr1","title":"Merge polecat/toast","status":"closed","labels":["gt:merge-request"],"created_at":"2026-01-05T10:00:00Z"}
+{"id":"gt-task1","title":"Split the auth work","status":"in_progress","delegated_from":"{\"parent\":\"gt-task0\"}","created_at":"2026-01-01T09:00:00Z","description":"A much longer description than any other bead in this store has."}
+{"id":"gt-task2","title":"Write docs","status":"open","created_at":"2026-01-06T10:00:00Z"}
+not json
+`
+
+func TestBeadStats(t *testing.T) {
+	beadsDir := t.TempDir()
+	writeIssuesJSONL(t, beadsDir, statsJSONL)
+	if err := os.WriteFile(filepath.Join(beadsDir, "config.yaml"), []byte("prefix: gt\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := BeadStats(NewWithBeadsDir(beadsDir, beadsDir).Store())
+	if err != nil {
+		t.Fatalf("BeadStats() error = %v", err)
+	}
+
+	if stats.Total != 6 || stats.Unreadable != 1 {
+		t.Errorf("Total, Unreadable = %d, %d; want 6, 1", stats.Total, stats.Unreadable)
+	}
+	wantStates := map[string]int{"working": 1, "idle": 1, "open": 2, "closed": 1, "in_progress": 1}
+	for state, n := range wantStates {
+		if stats.ByState[state] != n {
+			t.Errorf("ByState[%s] = %d, want %d (%v)", state, stats.ByState[state], n, stats.ByState)
+		}
+	}
+	wantTypes := map[string]int{"agent": 2, "escalation": 1, "mr": 1, "delegation": 1, "other": 1}
+	for typ, n := range wantTypes {
+		if stats.ByType[typ] != n {
+			t.Errorf("ByType[%s] = %d, want %d (%v)", typ, stats.ByType[typ], n, stats.ByType)
+		}
+	}
+	if stats.Oldest == nil || stats.Oldest.ID != "gt-task1" {
+		t.Errorf("Oldest = %+v, want gt-task1", stats.Oldest)
+	}
+	if stats.LargestBead == nil || stats.LargestBead.ID != "gt-task1" {
+		t.Errorf("LargestBead = %+v, want gt-task1", stats.LargestBead)
+	}
+	if stats.LargestFile == nil || stats.LargestFile.Path != "issues.jsonl" {
+		t.Errorf("LargestFile = %+v, want issues.jsonl", stats.LargestFile)
+	}
+	if want := int64(len(statsJSONL) + len("prefix: gt\n")); stats.StoreBytes != want {
+		t.Errorf("StoreBytes = %d, want %d", stats.StoreBytes, want)
+	}
+}
+
+func TestBeadStatsEmptyStore(t *testing.T) {
+	beadsDir := filepath.Join(t.TempDir(), ".beads")
+	stats, err := BeadStats(NewWithBeadsDir(beadsDir, beadsDir).Store())
+	if err != nil {
+		t.Fatalf("BeadStats() error = %v", err)
+	}
+	if stats.Total != 0 || stats.StoreBytes != 0 || stats.Oldest != nil {
+		t.Errorf("BeadStats() of a missing store = %+v, want empty", stats)
+	}
+}
+
+func TestBeadStatsMemoryStore(t *testing.T) {
+	store := NewStore(NewMemoryBackend())
+	for _, issue := range []*Issue{
+		{ID: "gt-1", Status: "open", Labels: []string{"gt:escalation"}, CreatedAt: "2026-01-02T10:00:00Z"},
+		{ID: "gt-2", Status: "closed", CreatedAt: "2026-01-01T10:00:00Z"},
+	} {
+		if err := store.Put(issue); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	stats, err := BeadStats(store)
+	if err != nil {
+		t.Fatalf("BeadStats() error = %v", err)
+	}
+	if stats.Total != 2 || stats.ByType["escalation"] != 1 || stats.ByState["closed"] != 1 {
+		t.Errorf("BeadStats() = %+v, want 2 beads, 1 escalation, 1 closed", stats)
+	}
+	if stats.Oldest == nil || stats.Oldest.ID != "gt-2" {
+		t.Errorf("Oldest = %+v, want gt-2", stats.Oldest)
+	}
+	if stats.LargestFile != nil || stats.StoreBytes != 0 {
+		t.Errorf("LargestFile, StoreBytes = %+v, %d; want none for a memory store", stats.LargestFile, stats.StoreBytes)
+	}
+}
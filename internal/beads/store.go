@@ -0,0 +1,129 @@
+package beads
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Backend is where a Store keeps its documents: raw bytes by ID.
+//
+// Read of a missing ID returns an error wrapping ErrNotFound; Delete of one
+// is not an error. List returns the IDs with the given prefix, sorted.
+// Watch returns a channel that receives a value after the document is
+// written or deleted; changes that arrive while one is pending coalesce.
+type Backend interface {
+	Read(id string) ([]byte, error)
+	Write(id string, data []byte) error
+	Delete(id string) error
+	List(prefix string) ([]string, error)
+	Watch(id string) (<-chan struct{}, error)
+}
+
+// ErrWatchUnsupported is returned by Watch on backends that cannot watch.
+var ErrWatchUnsupported = errors.New("watch not supported by this backend")
+
+// Store keeps bead snapshots - the JSON of an Issue - by bead ID in a
+// Backend. OpenStore keeps them in a directory; NewStore takes any
+// Backend, such as a MemoryBackend in tests.
+type Store struct {
+	backend Backend
+}
+
+// NewStore returns a Store over backend.
+func NewStore(backend Backend) *Store {
+	return &Store{backend: backend}
+}
+
+// OpenStore returns a Store keeping one file per bead in dir, which is
+// created on first write.
+func OpenStore(dir string) *Store {
+	return NewStore(NewFileBackend(dir))
+}
+
+// Backend returns the backend the store keeps its beads in.
+func (s *Store) Backend() Backend {
+	return s.backend
+}
+
+// Get returns the bead with the given ID, or an error wrapping ErrNotFound.
+func (s *Store) Get(id string) (*Issue, error) {
+	if err := validateStoreID(id); err != nil {
+		return nil, err
+	}
+	data, err := s.backend.Read(id)
+	if err != nil {
+		return nil, err
+	}
+	var issue Issue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return nil, fmt.Errorf("decoding bead %s: %w", id, err)
+	}
+	return &issue, nil
+}
+
+// Put saves issue under its ID, replacing any earlier snapshot.
+func (s *Store) Put(issue *Issue) error {
+	if err := validateStoreID(issue.ID); err != nil {
+		return err
+	}
+	data, err := json.Marshal(issue)
+	if err != nil {
+		return fmt.Errorf("encoding bead %s: %w", issue.ID, err)
+	}
+	return s.backend.Write(issue.ID, data)
+}
+
+// Delete removes the bead with the given ID. Deleting a bead that isn't
+// there is not an error.
+func (s *Store) Delete(id string) error {
+	if err := validateStoreID(id); err != nil {
+		return err
+	}
+	return s.backend.Delete(id)
+}
+
+// List returns the IDs of the stored beads that start with prefix, sorted.
+func (s *Store) List(prefix string) ([]string, error) {
+	return s.backend.List(prefix)
+}
+
+// Watch returns a channel that receives a value each time the bead with
+// the given ID is put or deleted. It is closed when the store is.
+func (s *Store) Watch(id string) (<-chan struct{}, error) {
+	if err := validateStoreID(id); err != nil {
+		return nil, err
+	}
+	return s.backend.Watch(id)
+}
+
+// Close stops the store's watches, if its backend has any to stop.
+func (s *Store) Close() error {
+	if c, ok := s.backend.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// validateStoreID rejects IDs that can't be used as a file or object name:
+// empty, or containing a path separator, "..", or a NUL.
+func validateStoreID(id string) error {
+	switch {
+	case id == "":
+		return errors.New("empty bead ID")
+	case strings.ContainsAny(id, "/\\\x00"), strings.Contains(id, ".."):
+		return fmt.Errorf("invalid bead ID %q", id)
+	}
+	return nil
+}
+
+// notifyWatch signals ch without blocking; a pending signal already
+// covers this change.
+func notifyWatch(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,147 @@
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// fileWatchInterval is how often a FileBackend watch checks its file.
+// Replaced in tests.
+var fileWatchInterval = time.Second
+
+// FileBackend is a Backend keeping each document in its own file,
+// <dir>/<id>.json, written atomically.
+type FileBackend struct {
+	dir string
+
+	mu     sync.Mutex
+	done   chan struct{}
+	closed bool
+}
+
+// NewFileBackend returns a FileBackend over dir. The directory is created
+// on first write.
+func NewFileBackend(dir string) *FileBackend {
+	return &FileBackend{dir: dir, done: make(chan struct{})}
+}
+
+// Dir returns the directory the backend keeps its files in.
+func (f *FileBackend) Dir() string {
+	return f.dir
+}
+
+func (f *FileBackend) path(id string) string {
+	return filepath.Join(f.dir, id+".json")
+}
+
+// Read returns the contents of id's file.
+func (f *FileBackend) Read(id string) ([]byte, error) {
+	data, err := os.ReadFile(f.path(id)) //nolint:gosec // G304: path is constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return data, err
+}
+
+// Write replaces id's file with data.
+func (f *FileBackend) Write(id string, data []byte) error {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return fmt.Errorf("creating store directory: %w", err)
+	}
+	return util.AtomicWriteFile(f.path(id), data, 0644)
+}
+
+// Delete removes id's file, if any.
+func (f *FileBackend) Delete(id string) error {
+	if err := os.Remove(f.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List returns the IDs of the files in the directory that start with
+// prefix, sorted. A directory that doesn't exist yet holds none.
+func (f *FileBackend) List(prefix string) ([]string, error) {
+	entries, err := os.ReadDir(f.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() || !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Watch polls id's file every fileWatchInterval and signals the returned
+// channel when it is written or removed, by this process or another. The
+// channel is closed when the backend is.
+func (f *FileBackend) Watch(id string) (<-chan struct{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	if f.closed {
+		close(ch)
+		return ch, nil
+	}
+	last := f.stat(id)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(fileWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-f.done:
+				return
+			case <-ticker.C:
+				if cur := f.stat(id); cur != last {
+					last = cur
+					notifyWatch(ch)
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// fileState is what Watch compares to see a file change.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+func (f *FileBackend) stat(id string) fileState {
+	info, err := os.Stat(f.path(id))
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+}
+
+// Close stops the backend's watches and closes their channels.
+func (f *FileBackend) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.closed {
+		f.closed = true
+		close(f.done)
+	}
+	return nil
+}
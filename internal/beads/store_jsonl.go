@@ -0,0 +1,264 @@
+package beads
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// JSONLBackend is a Backend over a bd issues.jsonl export: each line is a
+// bead's JSON, keyed by its "id". It is how gt reads the beads bd holds
+// without running bd (see Beads.Store). bd imports the file again when it
+// changes, as after a git pull, so writes reach bd on its next command.
+type JSONLBackend struct {
+	path string
+
+	mu         sync.Mutex
+	info       os.FileInfo // Of the file as last read; nil if it was missing
+	lines      [][]byte    // In file order, including lines without an id
+	index      map[string]int
+	unreadable int
+
+	done   chan struct{}
+	closed bool
+}
+
+// Store returns a Store over the beads' issues.jsonl export.
+func (b *Beads) Store() *Store {
+	return NewStore(NewJSONLBackend(b.jsonlPath()))
+}
+
+// NewJSONLBackend returns a JSONLBackend over the file at path, which is
+// created on first write.
+func NewJSONLBackend(path string) *JSONLBackend {
+	return &JSONLBackend{path: path, done: make(chan struct{})}
+}
+
+// Dir returns the directory holding the file, the store's .beads directory.
+func (j *JSONLBackend) Dir() string {
+	return filepath.Dir(j.path)
+}
+
+// Unreadable returns how many lines of the file have no bead ID, because
+// they don't parse or have none.
+func (j *JSONLBackend) Unreadable() (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.load(); err != nil {
+		return 0, err
+	}
+	return j.unreadable, nil
+}
+
+// Read returns the line of bead id.
+func (j *JSONLBackend) Read(id string) ([]byte, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	i, ok := j.index[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return append([]byte(nil), j.lines[i]...), nil
+}
+
+// Write replaces the line of bead id with data, or appends it.
+func (j *JSONLBackend) Write(id string, data []byte) error {
+	var line bytes.Buffer
+	if err := json.Compact(&line, data); err != nil {
+		return fmt.Errorf("encoding bead %s: %w", id, err)
+	}
+	return j.rewrite(func() {
+		if i, ok := j.index[id]; ok {
+			j.lines[i] = line.Bytes()
+			return
+		}
+		j.index[id] = len(j.lines)
+		j.lines = append(j.lines, line.Bytes())
+	})
+}
+
+// Delete removes the line of bead id, if any.
+func (j *JSONLBackend) Delete(id string) error {
+	j.mu.Lock()
+	err := j.load()
+	_, ok := j.index[id]
+	j.mu.Unlock()
+	if err != nil || !ok {
+		return err
+	}
+	return j.rewrite(func() {
+		if i, ok := j.index[id]; ok {
+			j.lines = append(j.lines[:i], j.lines[i+1:]...)
+			j.reindex()
+		}
+	})
+}
+
+// List returns the IDs of the beads in the file that start with prefix,
+// sorted. A missing file holds none.
+func (j *JSONLBackend) List(prefix string) ([]string, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	var ids []string
+	for id := range j.index {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Watch polls the file every fileWatchInterval and signals the returned
+// channel when bead id's line changes or goes away. The channel is closed
+// when the backend is.
+func (j *JSONLBackend) Watch(id string) (<-chan struct{}, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	if j.closed {
+		close(ch)
+		return ch, nil
+	}
+	last := j.lineOf(id)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(fileWatchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-j.done:
+				return
+			case <-ticker.C:
+				j.mu.Lock()
+				cur := last
+				if j.load() == nil {
+					cur = j.lineOf(id)
+				}
+				j.mu.Unlock()
+				if cur != last {
+					last = cur
+					notifyWatch(ch)
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Close stops the backend's watches and closes their channels.
+func (j *JSONLBackend) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if !j.closed {
+		j.closed = true
+		close(j.done)
+	}
+	return nil
+}
+
+// lineOf returns bead id's line as a string, "" if it has none. Called
+// with j.mu held.
+func (j *JSONLBackend) lineOf(id string) string {
+	if i, ok := j.index[id]; ok {
+		return string(j.lines[i])
+	}
+	return ""
+}
+
+// load reads the file unless it is unchanged since last read. Called with
+// j.mu held.
+func (j *JSONLBackend) load() error {
+	info, err := os.Stat(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		j.info, j.lines, j.index, j.unreadable = nil, nil, map[string]int{}, 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if j.info != nil && os.SameFile(j.info, info) && j.info.ModTime().Equal(info.ModTime()) && j.info.Size() == info.Size() {
+		return nil
+	}
+
+	data, err := os.ReadFile(j.path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", filepath.Base(j.path), err)
+	}
+	j.lines = j.lines[:0]
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			j.lines = append(j.lines, line)
+		}
+	}
+	j.reindex()
+	j.info = info
+	return nil
+}
+
+// reindex rebuilds the ID index from the lines. Called with j.mu held.
+func (j *JSONLBackend) reindex() {
+	j.index = make(map[string]int, len(j.lines))
+	j.unreadable = 0
+	for i, line := range j.lines {
+		var bead struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(line, &bead) != nil || bead.ID == "" {
+			j.unreadable++
+			continue
+		}
+		j.index[bead.ID] = i
+	}
+}
+
+// rewrite applies change to the file as it is now and writes it back
+// atomically, holding a lock so that gt processes don't lose each other's
+// writes.
+func (j *JSONLBackend) rewrite(change func()) error {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("creating store directory: %w", err)
+	}
+	lock := flock.New(j.path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking %s: %w", filepath.Base(j.path), err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info = nil // Read it again: another process may have written it
+	if err := j.load(); err != nil {
+		return err
+	}
+	change()
+
+	var buf bytes.Buffer
+	for _, line := range j.lines {
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := util.AtomicWriteFile(j.path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	if info, err := os.Stat(j.path); err == nil {
+		j.info = info
+	}
+	return nil
+}
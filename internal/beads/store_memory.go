@@ -0,0 +1,107 @@
+package beads
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryBackend is a Backend held in memory, for tests.
+type MemoryBackend struct {
+	mu       sync.Mutex
+	docs     map[string][]byte
+	watchers map[string][]chan struct{}
+	closed   bool
+}
+
+// NewMemoryBackend returns an empty MemoryBackend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		docs:     make(map[string][]byte),
+		watchers: make(map[string][]chan struct{}),
+	}
+}
+
+// Read returns a copy of the document stored under id.
+func (m *MemoryBackend) Read(id string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.docs[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Write stores a copy of data under id.
+func (m *MemoryBackend) Write(id string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.docs[id] = append([]byte(nil), data...)
+	m.notify(id)
+	return nil
+}
+
+// Delete removes the document stored under id, if any.
+func (m *MemoryBackend) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.docs[id]; !ok {
+		return nil
+	}
+	delete(m.docs, id)
+	m.notify(id)
+	return nil
+}
+
+// List returns the stored IDs that start with prefix, sorted.
+func (m *MemoryBackend) List(prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var ids []string
+	for id := range m.docs {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Watch returns a channel signaled on each Write or Delete of id.
+func (m *MemoryBackend) Watch(id string) (<-chan struct{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch := make(chan struct{}, 1)
+	if m.closed {
+		close(ch)
+		return ch, nil
+	}
+	m.watchers[id] = append(m.watchers[id], ch)
+	return ch, nil
+}
+
+// Close closes every channel returned by Watch.
+func (m *MemoryBackend) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	for _, chans := range m.watchers {
+		for _, ch := range chans {
+			close(ch)
+		}
+	}
+	m.watchers = nil
+	return nil
+}
+
+// notify signals the watchers of id. Called with m.mu held.
+func (m *MemoryBackend) notify(id string) {
+	for _, ch := range m.watchers[id] {
+		notifyWatch(ch)
+	}
+}
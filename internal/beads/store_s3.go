@@ -0,0 +1,110 @@
+//go:build s3
+
+package beads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Backend is a Backend keeping each document as an object,
+// <prefix><id>.json, in an S3 bucket. It is built only with -tags s3.
+// S3 has no change notifications a CLI can wait on, so Watch returns
+// ErrWatchUnsupported.
+type S3Backend struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Backend returns an S3Backend over bucket using client, which the
+// caller configures with credentials and region. Object keys start with
+// prefix, such as "beads/".
+func NewS3Backend(client *s3.Client, bucket, prefix string) *S3Backend {
+	return &S3Backend{client: client, bucket: bucket, prefix: prefix}
+}
+
+func (b *S3Backend) key(id string) string {
+	return b.prefix + id + ".json"
+}
+
+// Read returns the object for id.
+func (b *S3Backend) Read(id string) ([]byte, error) {
+	out, err := b.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(id)),
+	})
+	if err != nil {
+		var noKey *types.NoSuchKey
+		if errors.As(err, &noKey) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("reading %s from s3://%s: %w", id, b.bucket, err)
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+// Write replaces the object for id with data.
+func (b *S3Backend) Write(id string, data []byte) error {
+	_, err := b.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      aws.String(b.bucket),
+		Key:         aws.String(b.key(id)),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("writing %s to s3://%s: %w", id, b.bucket, err)
+	}
+	return nil
+}
+
+// Delete removes the object for id. S3 deletes of a missing key succeed.
+func (b *S3Backend) Delete(id string) error {
+	_, err := b.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(b.key(id)),
+	})
+	if err != nil {
+		return fmt.Errorf("deleting %s from s3://%s: %w", id, b.bucket, err)
+	}
+	return nil
+}
+
+// List returns the IDs of the objects under the backend's prefix that
+// start with prefix, sorted.
+func (b *S3Backend) List(prefix string) ([]string, error) {
+	var ids []string
+	pages := s3.NewListObjectsV2Paginator(b.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(b.bucket),
+		Prefix: aws.String(b.prefix + prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("listing s3://%s: %w", b.bucket, err)
+		}
+		for _, obj := range page.Contents {
+			name := strings.TrimPrefix(aws.ToString(obj.Key), b.prefix)
+			if id, ok := strings.CutSuffix(name, ".json"); ok && !strings.Contains(id, "/") {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Watch is not supported on S3.
+func (b *S3Backend) Watch(id string) (<-chan struct{}, error) {
+	return nil, ErrWatchUnsupported
+}
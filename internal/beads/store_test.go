@@ -0,0 +1,162 @@
+package beads
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// storeBackends returns a fresh instance of each Backend, so that every
+// store test checks they behave the same.
+func storeBackends(t *testing.T) map[string]Backend {
+	t.Helper()
+	orig := fileWatchInterval
+	fileWatchInterval = 5 * time.Millisecond
+	t.Cleanup(func() { fileWatchInterval = orig })
+
+	return map[string]Backend{
+		"file":   NewFileBackend(t.TempDir() + "/store"),
+		"jsonl":  NewJSONLBackend(t.TempDir() + "/.beads/issues.jsonl"),
+		"memory": NewMemoryBackend(),
+	}
+}
+
+func forEachBackend(t *testing.T, fn func(t *testing.T, s *Store)) {
+	for name, backend := range storeBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			s := NewStore(backend)
+			t.Cleanup(func() { _ = s.Close() })
+			fn(t, s)
+		})
+	}
+}
+
+func TestStore_PutGet(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *Store) {
+		want := &Issue{ID: "gt-1", Title: "Fix auth", Status: "open", Labels: []string{"bug"}}
+		if err := s.Put(want); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		got, err := s.Get("gt-1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Get = %+v, want %+v", got, want)
+		}
+
+		want.Status = "closed"
+		if err := s.Put(want); err != nil {
+			t.Fatalf("Put again: %v", err)
+		}
+		if got, _ := s.Get("gt-1"); got.Status != "closed" {
+			t.Errorf("Get after second Put: status = %q, want closed", got.Status)
+		}
+	})
+}
+
+func TestStore_GetMissing(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *Store) {
+		if _, err := s.Get("gt-missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get missing = %v, want ErrNotFound", err)
+		}
+	})
+}
+
+func TestStore_Delete(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *Store) {
+		if err := s.Put(&Issue{ID: "gt-1"}); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		if err := s.Delete("gt-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := s.Get("gt-1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+		}
+		if err := s.Delete("gt-1"); err != nil {
+			t.Errorf("Delete again = %v, want nil", err)
+		}
+	})
+}
+
+func TestStore_List(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *Store) {
+		if ids, err := s.List(""); err != nil || len(ids) != 0 {
+			t.Errorf("List on empty store = %v, %v; want none", ids, err)
+		}
+		for _, id := range []string{"gt-2", "hq-1", "gt-10", "gt-1"} {
+			if err := s.Put(&Issue{ID: id}); err != nil {
+				t.Fatalf("Put %s: %v", id, err)
+			}
+		}
+		ids, err := s.List("gt-")
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if want := []string{"gt-1", "gt-10", "gt-2"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("List(gt-) = %v, want %v", ids, want)
+		}
+		if ids, _ := s.List(""); len(ids) != 4 {
+			t.Errorf("List() = %v, want all 4", ids)
+		}
+	})
+}
+
+func TestStore_InvalidID(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *Store) {
+		for _, id := range []string{"", "../escape", "a/b", `a\b`, "a\x00b"} {
+			if err := s.Put(&Issue{ID: id}); err == nil {
+				t.Errorf("Put(%q) succeeded, want error", id)
+			}
+			if _, err := s.Get(id); err == nil || errors.Is(err, ErrNotFound) {
+				t.Errorf("Get(%q) = %v, want invalid ID error", id, err)
+			}
+		}
+	})
+}
+
+func TestStore_Watch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s *Store) {
+		ch, err := s.Watch("gt-1")
+		if err != nil {
+			t.Fatalf("Watch: %v", err)
+		}
+		other, err := s.Watch("gt-2")
+		if err != nil {
+			t.Fatalf("Watch: %v", err)
+		}
+
+		if err := s.Put(&Issue{ID: "gt-1", Title: "first"}); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		waitWatch(t, ch, "Put")
+
+		if err := s.Delete("gt-1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		waitWatch(t, ch, "Delete")
+
+		select {
+		case <-other:
+			t.Error("watch of gt-2 fired on changes to gt-1")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		for range ch {
+		}
+	})
+}
+
+func waitWatch(t *testing.T, ch <-chan struct{}, after string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("watch not signaled after %s", after)
+	}
+}
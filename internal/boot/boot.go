@@ -4,6 +4,8 @@
 package boot
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,8 +13,12 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/gofrs/flock"
 	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/proc"
 	"github.com/KeithWyatt/gongshow/internal/tmux"
+	"github.com/KeithWyatt/gongshow/internal/util"
 )
 
 // SessionName is the tmux session name for Boot.
@@ -36,6 +42,24 @@ type Status struct {
 	LastAction  string    `json:"last_action,omitempty"` // start/wake/nudge/nothing
 	Target      string    `json:"target,omitempty"`      // deacon, witness, etc.
 	Error       string    `json:"error,omitempty"`
+
+	// Agents are the statuses of the last dependency-ordered startup of the
+	// town's agents (gt up), in start order, updated as each agent's state
+	// changes, and Summary counts them once it has finished. Selection is
+	// the part of the town it started; nil is all of it. StartupPID is the
+	// gt up's process; if it exits while StartupRunning, LoadStatus reports
+	// the startup as abandoned.
+	Agents             []AgentStatus `json:"agents,omitempty"`
+	Summary            *Summary      `json:"summary,omitempty"`
+	Selection          *Selection    `json:"selection,omitempty"`
+	StartupRunning     bool          `json:"startup_running,omitempty"`
+	StartupStartedAt   time.Time     `json:"startup_started_at,omitempty"`
+	StartupCompletedAt time.Time     `json:"startup_completed_at,omitempty"`
+	StartupPID         int           `json:"startup_pid,omitempty"`
+	StartupAbandoned   bool          `json:"startup_abandoned,omitempty"`
+
+	// Hooks are the boot hooks that startup ran, pre hooks first.
+	Hooks []HookResult `json:"hooks,omitempty"`
 }
 
 // Boot manages the Boot watchdog lifecycle.
@@ -73,6 +97,12 @@ func (b *Boot) statusPath() string {
 	return filepath.Join(b.bootDir, StatusFileName)
 }
 
+// statusLockPath returns the path to the lock serializing updates of the
+// status file.
+func (b *Boot) statusLockPath() string {
+	return b.statusPath() + ".lock"
+}
+
 // IsRunning checks if Boot is currently running.
 // Queries tmux directly for observable reality (ZFC principle).
 func (b *Boot) IsRunning() bool {
@@ -85,8 +115,17 @@ func (b *Boot) IsSessionAlive() bool {
 	return err == nil && has
 }
 
+// lockHolder is what the marker file records about the process holding
+// the boot lock.
+type lockHolder struct {
+	PID       int       `json:"pid"`
+	StartedAt time.Time `json:"started_at"`
+}
+
 // AcquireLock creates the marker file to indicate Boot is starting.
-// Returns error if Boot is already running.
+// Returns error if Boot is already running. A marker left behind by a
+// boot that crashed, whose process is gone and whose session isn't
+// running, is reclaimed.
 func (b *Boot) AcquireLock() error {
 	if b.IsRunning() {
 		return fmt.Errorf("boot is already running (session exists)")
@@ -96,20 +135,113 @@ func (b *Boot) AcquireLock() error {
 		return fmt.Errorf("ensuring boot dir: %w", err)
 	}
 
-	// Create marker file
-	f, err := os.Create(b.markerPath())
+	data, err := json.Marshal(lockHolder{PID: os.Getpid(), StartedAt: time.Now()})
 	if err != nil {
-		return fmt.Errorf("creating marker: %w", err)
+		return err
 	}
-	return f.Close()
+	// A second attempt covers a stale marker; if another boot recreates
+	// the marker in between, the lock is theirs.
+	for attempt := 0; attempt < 2; attempt++ {
+		err := b.createMarker(data)
+		if err == nil {
+			return nil
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("creating marker: %w", err)
+		}
+		if err := b.reclaimStaleLock(); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("boot lock %s was taken by another boot", b.markerPath())
 }
 
-// ReleaseLock removes the marker file.
+// createMarker creates the marker file with data, failing if it exists.
+// The marker is linked into place already written, so a reclaim never
+// reads it empty and takes it for a stale marker without a holder.
+func (b *Boot) createMarker(data []byte) error {
+	f, err := os.CreateTemp(b.bootDir, MarkerFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Link(f.Name(), b.markerPath())
+}
+
+// readMarker returns the holder the marker file records. A marker from
+// before holders were recorded has a zero holder.
+func (b *Boot) readMarker() (lockHolder, error) {
+	var holder lockHolder
+	data, err := os.ReadFile(b.markerPath())
+	if err != nil {
+		return holder, err
+	}
+	_ = json.Unmarshal(data, &holder)
+	return holder, nil
+}
+
+// reclaimStaleLock removes the marker file if neither the process it
+// names nor the Boot session is alive, and returns an error naming the
+// holder if either is. Reclaims are serialized, so the marker removed is
+// the one read: while it exists no one can create another, and no other
+// reclaim can remove it in the meantime and let a new boot take the lock.
+func (b *Boot) reclaimStaleLock() error {
+	lock := flock.New(b.markerPath() + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking marker: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	holder, err := b.readMarker()
+	if os.IsNotExist(err) {
+		return nil // Released since
+	}
+	if err != nil {
+		return fmt.Errorf("reading marker: %w", err)
+	}
+
+	if holder.PID > 0 && proc.Exists(holder.PID) {
+		return fmt.Errorf("boot is already running (pid %d, locked %s ago)",
+			holder.PID, time.Since(holder.StartedAt).Round(time.Second))
+	}
+	if b.IsSessionAlive() {
+		return fmt.Errorf("boot is already running (session exists)")
+	}
+
+	var startedAt string
+	if !holder.StartedAt.IsZero() {
+		startedAt = holder.StartedAt.UTC().Format(time.RFC3339)
+	}
+	_ = events.LogFeed(events.TypeBootLockReclaimed, "boot",
+		events.BootLockReclaimedPayload(holder.PID, startedAt))
+	if err := os.Remove(b.markerPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reclaiming stale boot lock: %w", err)
+	}
+	return nil
+}
+
+// ReleaseLock removes the marker file, if this process holds the lock.
 func (b *Boot) ReleaseLock() error {
+	holder, err := b.readMarker()
+	if err != nil {
+		return err
+	}
+	if holder.PID != os.Getpid() {
+		return fmt.Errorf("boot lock is held by pid %d, not this process", holder.PID)
+	}
 	return os.Remove(b.markerPath())
 }
 
-// SaveStatus saves Boot's execution status.
+// SaveStatus saves Boot's execution status. The file is replaced
+// atomically, so a concurrent LoadStatus or FollowStatus never reads a
+// partial write.
 func (b *Boot) SaveStatus(status *Status) error {
 	if err := b.EnsureDir(); err != nil {
 		return err
@@ -120,10 +252,46 @@ func (b *Boot) SaveStatus(status *Status) error {
 		return err
 	}
 
-	return os.WriteFile(b.statusPath(), data, 0644) //nolint:gosec // G306: boot status is non-sensitive operational data
+	return util.AtomicWriteFileUnique(b.statusPath(), data, 0644)
+}
+
+// UpdateStatus applies fn to Boot's status and saves it, holding a lock
+// on the status file throughout, so gt up and Boot triage, which each
+// record their own part of it, don't lose each other's writes.
+func (b *Boot) UpdateStatus(fn func(*Status)) error {
+	if err := b.EnsureDir(); err != nil {
+		return err
+	}
+	lock := flock.New(b.statusLockPath())
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking %s: %w", StatusFileName, err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	status, err := b.LoadStatus()
+	if err != nil {
+		// A corrupt file is replaced.
+		status = &Status{}
+	}
+	fn(status)
+	return b.SaveStatus(status)
+}
+
+// SaveTriageStatus saves the triage fields of status (Running through
+// Error), keeping the startup the file records.
+func (b *Boot) SaveTriageStatus(status *Status) error {
+	return b.UpdateStatus(func(s *Status) {
+		s.Running = status.Running
+		s.StartedAt = status.StartedAt
+		s.CompletedAt = status.CompletedAt
+		s.LastAction = status.LastAction
+		s.Target = status.Target
+		s.Error = status.Error
+	})
 }
 
-// LoadStatus loads Boot's last execution status.
+// LoadStatus loads Boot's last execution status. A startup whose gt up
+// has exited without finishing it is reported as abandoned, not running.
 func (b *Boot) LoadStatus() (*Status, error) {
 	data, err := os.ReadFile(b.statusPath())
 	if err != nil {
@@ -137,10 +305,58 @@ func (b *Boot) LoadStatus() (*Status, error) {
 	if err := json.Unmarshal(data, &status); err != nil {
 		return nil, err
 	}
+	status.checkStartupAlive()
 
 	return &status, nil
 }
 
+// checkStartupAlive marks a running startup whose gt up has exited as
+// abandoned.
+func (s *Status) checkStartupAlive() {
+	if s.StartupRunning && s.StartupPID > 0 && !proc.Exists(s.StartupPID) {
+		s.StartupRunning = false
+		s.StartupAbandoned = true
+	}
+}
+
+// FollowStatus calls fn with Boot's status whenever the status file
+// changes, checking every interval, until a status with no startup running
+// has been passed to fn or ctx is done. A missing file is waited for, and
+// a startup whose gt up has exited is passed to fn as abandoned.
+func (b *Boot) FollowStatus(ctx context.Context, interval time.Duration, fn func(*Status)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		data, err := os.ReadFile(b.statusPath())
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if err == nil {
+			var status Status
+			if err := json.Unmarshal(data, &status); err != nil {
+				return fmt.Errorf("parsing %s: %w", StatusFileName, err)
+			}
+			// An unchanged file is passed again only once its gt up has died.
+			status.checkStartupAlive()
+			if !bytes.Equal(data, last) || status.StartupAbandoned {
+				last = data
+				fn(&status)
+				if !status.StartupRunning {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 // Spawn starts Boot in a fresh tmux session.
 // Boot runs the mol-boot-triage molecule and exits when done.
 // In degraded mode (no tmux), it runs in a subprocess.
@@ -185,7 +401,11 @@ func (b *Boot) spawnTmux(agentOverride string) error {
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gongshow/issues/280
-	if err := b.tmux.NewSessionWithCommand(SessionName, b.bootDir, startCmd); err != nil {
+	// Backoff keeps an agent that crashes on start from being respawned in a
+	// tight loop.
+	startCmd = fmt.Sprintf("cd %s && %s", b.bootDir, startCmd)
+	isReady := func(session string) bool { return b.tmux.IsAgentRunning(session) }
+	if err := b.tmux.EnsureSessionFreshWithBackoff(SessionName, startCmd, tmux.DefaultBackoffPolicy, isReady); err != nil {
 		return fmt.Errorf("creating boot session: %w", err)
 	}
 
@@ -0,0 +1,187 @@
+package boot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// MaxHistoryRecords caps boot-history.jsonl: once it holds more records,
+// the oldest are dropped.
+const MaxHistoryRecords = 500
+
+// HistoryRecord is one boot of a town's agents (gt up), as kept in the
+// boot history.
+type HistoryRecord struct {
+	Timestamp time.Time      `json:"timestamp"` // When the boot finished
+	Profile   string         `json:"profile,omitempty"`
+	WallMs    int64          `json:"wall_ms"`
+	Retries   int            `json:"retries"` // Retry rounds the boot needed
+	Failures  int            `json:"failures"`
+	Agents    []HistoryAgent `json:"agents"`
+}
+
+// HistoryAgent is one agent's start in a HistoryRecord.
+type HistoryAgent struct {
+	ID      string  `json:"id"`
+	Outcome Outcome `json:"outcome,omitempty"`
+	Retry   int     `json:"retry,omitempty"`
+	Error   string  `json:"error,omitempty"`
+
+	// Started is set when the boot started the agent, rather than finding
+	// it already running or failing it before its start, and DurationMs
+	// is then how long it took to come up or fail.
+	Started    bool  `json:"started"`
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// NewHistoryRecord records a boot that started at startedAt and finished
+// at finishedAt with statuses.
+func NewHistoryRecord(profile string, startedAt, finishedAt time.Time, statuses []AgentStatus) *HistoryRecord {
+	rec := &HistoryRecord{
+		Timestamp: finishedAt,
+		Profile:   profile,
+		WallMs:    finishedAt.Sub(startedAt).Milliseconds(),
+		Agents:    make([]HistoryAgent, len(statuses)),
+	}
+	for i, st := range statuses {
+		a := HistoryAgent{
+			ID:      st.ID,
+			Outcome: st.Outcome,
+			Retry:   st.Retry,
+			Error:   st.Error,
+			Started: !st.StartedAt.IsZero() && st.Action != string(ActionSkip),
+		}
+		if end := st.ReadyAt; a.Started {
+			if end.IsZero() && len(st.History) > 0 {
+				end = st.History[len(st.History)-1].At
+			}
+			if !end.IsZero() {
+				a.DurationMs = end.Sub(st.StartedAt).Milliseconds()
+			}
+		}
+		if !st.Ready {
+			rec.Failures++
+		}
+		if st.Retry > rec.Retries {
+			rec.Retries = st.Retry
+		}
+		rec.Agents[i] = a
+	}
+	return rec
+}
+
+// Agent returns the record's entry for an agent, if the boot included it.
+func (r *HistoryRecord) Agent(id string) (HistoryAgent, bool) {
+	for _, a := range r.Agents {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return HistoryAgent{}, false
+}
+
+// HistoryPath returns the boot history file for a town.
+func HistoryPath(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "boot-history.jsonl")
+}
+
+// AppendHistory appends a record to the town's boot history, dropping
+// the oldest records past MaxHistoryRecords. It holds a lock on the file
+// throughout, so concurrent boots don't lose each other's records.
+func AppendHistory(townRoot string, rec *HistoryRecord) error {
+	return appendHistory(HistoryPath(townRoot), rec, MaxHistoryRecords)
+}
+
+func appendHistory(path string, rec *HistoryRecord, maxRecords int) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking boot history: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading boot history: %w", err)
+	}
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	if n := len(lines); n > 0 && len(lines[n-1]) == 0 {
+		lines = lines[:n-1]
+	}
+	lines = append(lines, append(line, '\n'))
+	if len(lines) > maxRecords {
+		lines = lines[len(lines)-maxRecords:]
+	}
+
+	if err := util.AtomicWriteFileUnique(path, bytes.Join(lines, nil), 0644); err != nil {
+		return fmt.Errorf("writing boot history: %w", err)
+	}
+	return nil
+}
+
+// ReadHistory returns the town's boot history, oldest first. Malformed
+// lines are skipped, and a missing file is an empty history.
+func ReadHistory(townRoot string) ([]*HistoryRecord, error) {
+	f, err := os.Open(HistoryPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening boot history: %w", err)
+	}
+	defer f.Close()
+
+	var records []*HistoryRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var rec HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		records = append(records, &rec)
+	}
+	return records, scanner.Err()
+}
+
+// TrendPoint is an agent's start in one boot.
+type TrendPoint struct {
+	Timestamp  time.Time `json:"timestamp"`
+	DurationMs int64     `json:"duration_ms"`
+	Outcome    Outcome   `json:"outcome,omitempty"`
+	Retry      int       `json:"retry,omitempty"`
+}
+
+// AgentTrend returns the agent's start in each boot of records that
+// started it, in the records' order.
+func AgentTrend(records []*HistoryRecord, id string) []TrendPoint {
+	var points []TrendPoint
+	for _, rec := range records {
+		a, ok := rec.Agent(id)
+		if !ok || !a.Started {
+			continue
+		}
+		points = append(points, TrendPoint{
+			Timestamp:  rec.Timestamp,
+			DurationMs: a.DurationMs,
+			Outcome:    a.Outcome,
+			Retry:      a.Retry,
+		})
+	}
+	return points
+}
@@ -0,0 +1,143 @@
+package boot
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewHistoryRecord(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(sec float64) time.Time { return start.Add(time.Duration(sec * float64(time.Second))) }
+
+	rec := NewHistoryRecord("dev", start, at(30), []AgentStatus{
+		{ID: "daemon", StartedAt: at(0), ReadyAt: at(1.5), Ready: true, Outcome: OutcomeReady},
+		{ID: "mayor", StartedAt: at(0), Ready: true, Outcome: OutcomeReady, Action: string(ActionSkip)},
+		{ID: "alpha/witness", StartedAt: at(2), ReadyAt: at(14), Ready: true, Outcome: OutcomeReady, Retry: 1},
+		{ID: "alpha/refinery", StartedAt: at(2), Outcome: OutcomeTimedOut, Error: "not ready after 10s",
+			History: []Transition{{State: StateStarting, At: at(2)}, {State: StateFailed, At: at(12)}}},
+		{ID: "beta/witness", Outcome: OutcomeNotAttempted},
+	})
+
+	if rec.Profile != "dev" || rec.WallMs != 30000 || rec.Retries != 1 || rec.Failures != 2 || !rec.Timestamp.Equal(at(30)) {
+		t.Errorf("record = %+v, want profile dev, 30s wall, 1 retry, 2 failures", rec)
+	}
+	for _, want := range []HistoryAgent{
+		{ID: "daemon", Outcome: OutcomeReady, Started: true, DurationMs: 1500},
+		{ID: "mayor", Outcome: OutcomeReady},
+		{ID: "alpha/witness", Outcome: OutcomeReady, Retry: 1, Started: true, DurationMs: 12000},
+		{ID: "alpha/refinery", Outcome: OutcomeTimedOut, Error: "not ready after 10s", Started: true, DurationMs: 10000},
+		{ID: "beta/witness", Outcome: OutcomeNotAttempted},
+	} {
+		if got, ok := rec.Agent(want.ID); !ok || got != want {
+			t.Errorf("Agent(%q) = %+v, %v; want %+v", want.ID, got, ok, want)
+		}
+	}
+	if _, ok := rec.Agent("gamma/witness"); ok {
+		t.Error("Agent() found an agent the boot didn't include")
+	}
+}
+
+func TestAgentTrend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 9, 0, 0, 0, time.UTC) }
+	records := []*HistoryRecord{
+		{Timestamp: day(1), Agents: []HistoryAgent{{ID: "alpha/witness", Outcome: OutcomeReady, Started: true, DurationMs: 4000}}},
+		{Timestamp: day(2), Agents: []HistoryAgent{{ID: "daemon", Started: true, DurationMs: 900}}},
+		{Timestamp: day(3), Agents: []HistoryAgent{{ID: "alpha/witness", Outcome: OutcomeReady}}}, // Already running
+		{Timestamp: day(4), Agents: []HistoryAgent{{ID: "alpha/witness", Outcome: OutcomeFailed, Retry: 2, Started: true, DurationMs: 9000}}},
+	}
+
+	got := AgentTrend(records, "alpha/witness")
+	want := []TrendPoint{
+		{Timestamp: day(1), DurationMs: 4000, Outcome: OutcomeReady},
+		{Timestamp: day(4), DurationMs: 9000, Outcome: OutcomeFailed, Retry: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("AgentTrend() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if !got[i].Timestamp.Equal(want[i].Timestamp) || got[i].DurationMs != want[i].DurationMs ||
+			got[i].Outcome != want[i].Outcome || got[i].Retry != want[i].Retry {
+			t.Errorf("AgentTrend()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got := AgentTrend(records, "beta/witness"); got != nil {
+		t.Errorf("AgentTrend() of an unknown agent = %+v, want none", got)
+	}
+}
+
+func TestAppendHistoryRetention(t *testing.T) {
+	town := t.TempDir()
+	path := HistoryPath(town)
+	for i := 1; i <= 5; i++ {
+		rec := &HistoryRecord{Timestamp: time.Unix(int64(i), 0), WallMs: int64(i)}
+		if err := appendHistory(path, rec, 3); err != nil {
+			t.Fatalf("appendHistory() error = %v", err)
+		}
+	}
+
+	records, err := ReadHistory(town)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(records) != 3 || records[0].WallMs != 3 || records[2].WallMs != 5 {
+		t.Errorf("ReadHistory() = %+v, want the last 3 of 5 boots", records)
+	}
+}
+
+func TestAppendHistoryConcurrent(t *testing.T) {
+	town := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := AppendHistory(town, &HistoryRecord{WallMs: int64(i)}); err != nil {
+				t.Errorf("AppendHistory() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	records, err := ReadHistory(town)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	if len(records) != 20 {
+		t.Errorf("ReadHistory() has %d records after 20 concurrent appends, want all 20", len(records))
+	}
+}
+
+func TestReadHistory(t *testing.T) {
+	town := t.TempDir()
+	if records, err := ReadHistory(town); err != nil || records != nil {
+		t.Errorf("ReadHistory() without a history = %v, %v; want none", records, err)
+	}
+
+	if err := AppendHistory(town, &HistoryRecord{Profile: "dev", WallMs: 1200}); err != nil {
+		t.Fatalf("AppendHistory() error = %v", err)
+	}
+	f, err := os.OpenFile(HistoryPath(town), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{truncated\n")
+	_ = f.Close()
+	if err := AppendHistory(town, &HistoryRecord{Profile: "full", WallMs: 3400}); err != nil {
+		t.Fatalf("AppendHistory() error = %v", err)
+	}
+
+	records, err := ReadHistory(town)
+	if err != nil {
+		t.Fatalf("ReadHistory() error = %v", err)
+	}
+	var profiles []string
+	for _, rec := range records {
+		profiles = append(profiles, rec.Profile)
+	}
+	if got := strings.Join(profiles, ","); got != "dev,full" {
+		t.Errorf("ReadHistory() profiles = %s, want the malformed line skipped", got)
+	}
+}
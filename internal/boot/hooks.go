@@ -0,0 +1,155 @@
+package boot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Hook stages: pre hooks run before any agent is started and a failing
+// one aborts the boot; post hooks run after the boot, whatever its
+// outcome, and a failing one only warns.
+const (
+	HookPre  = "pre"
+	HookPost = "post"
+)
+
+// DefaultHookTimeout bounds a single boot hook run.
+const DefaultHookTimeout = 60 * time.Second
+
+// hookOutputLimit is how much of a hook's output its HookResult keeps.
+const hookOutputLimit = 4096
+
+// HookResult is how one boot hook script's run went.
+type HookResult struct {
+	Stage      string    `json:"stage"`
+	Name       string    `json:"name"` // File name in the stage's directory
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	OK         bool      `json:"ok"`
+	TimedOut   bool      `json:"timed_out,omitempty"`
+	Error      string    `json:"error,omitempty"`
+
+	// Output is what the script wrote to stdout and stderr; only its
+	// tail is kept.
+	Output string `json:"output,omitempty"`
+}
+
+// HooksDir returns the directory of a stage's boot hooks in a town:
+// config/boot.d/pre or config/boot.d/post.
+func HooksDir(townRoot, stage string) string {
+	return filepath.Join(townRoot, "config", "boot.d", stage)
+}
+
+// ListHooks returns the paths of a stage's boot hooks, in lexical order:
+// the executable files in its directory. Other files, such as a README,
+// are ignored, and a missing directory means no hooks.
+func ListHooks(townRoot, stage string) ([]string, error) {
+	dir := HooksDir(townRoot, stage)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	var paths []string
+	for _, e := range entries { // ReadDir sorts by name
+		info, err := e.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, e.Name()))
+	}
+	return paths, nil
+}
+
+// HookRunner runs a town's boot hooks.
+//
+// Each hook runs in the town root with GT_TOWN_ROOT, GT_BOOT_PROFILE (empty
+// when the boot has no profile) and GT_BOOT_STAGE set. Post hooks also get
+// the boot's result as JSON on stdin, which, unlike the environment, has
+// no size limit.
+type HookRunner struct {
+	TownRoot string
+	Profile  string
+	Timeout  time.Duration // Per hook; 0 means DefaultHookTimeout
+}
+
+// Run runs a stage's hooks in order and returns how each went. result is
+// the post hooks' input, ignored for pre hooks. Pre hooks stop at the
+// first one that fails; post hooks all run. The error is for hooks that
+// couldn't be listed, not for hooks that failed.
+func (r *HookRunner) Run(stage string, result []byte) ([]HookResult, error) {
+	paths, err := ListHooks(r.TownRoot, stage)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []HookResult
+	for _, path := range paths {
+		res := r.run(stage, path, result)
+		results = append(results, res)
+		if !res.OK && stage == HookPre {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (r *HookRunner) run(stage, path string, result []byte) HookResult {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, path) //nolint:gosec // G204: hooks are the town's own config
+	cmd.Dir = r.TownRoot
+	cmd.Env = append(os.Environ(),
+		"GT_TOWN_ROOT="+r.TownRoot,
+		"GT_BOOT_PROFILE="+r.Profile,
+		"GT_BOOT_STAGE="+stage,
+	)
+	if stage == HookPost {
+		cmd.Stdin = bytes.NewReader(result)
+	}
+	cmd.WaitDelay = time.Second // don't hang on pipes held by orphaned children
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	res := HookResult{Stage: stage, Name: filepath.Base(path), StartedAt: time.Now()}
+	err := cmd.Run()
+	res.FinishedAt = time.Now()
+	res.Output = tail(out.String(), hookOutputLimit)
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		res.TimedOut = true
+		res.Error = fmt.Sprintf("timed out after %s", timeout)
+	case err != nil:
+		res.Error = err.Error()
+	default:
+		res.OK = true
+	}
+	return res
+}
+
+// tail returns the last n bytes of s, starting at a line if it can.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[len(s)-n:]
+	if i := strings.IndexByte(s, '\n'); i >= 0 && i < len(s)-1 {
+		s = s[i+1:]
+	}
+	return s
+}
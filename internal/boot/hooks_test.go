@@ -0,0 +1,140 @@
+package boot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeHook writes a shell-script boot hook fixture.
+func writeHook(t *testing.T, townRoot, stage, name, script string, mode os.FileMode) {
+	t.Helper()
+	dir := HooksDir(townRoot, stage)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hookNames(results []HookResult) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Name
+	}
+	return names
+}
+
+func TestListHooks(t *testing.T) {
+	town := t.TempDir()
+	writeHook(t, town, HookPre, "20-second", "exit 0\n", 0755)
+	writeHook(t, town, HookPre, "10-first", "exit 0\n", 0755)
+	writeHook(t, town, HookPre, "README", "not a hook\n", 0644)
+
+	paths, err := ListHooks(town, HookPre)
+	if err != nil {
+		t.Fatalf("ListHooks() error = %v", err)
+	}
+	want := []string{filepath.Join(HooksDir(town, HookPre), "10-first"), filepath.Join(HooksDir(town, HookPre), "20-second")}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("ListHooks() = %v, want %v", paths, want)
+	}
+
+	if paths, err := ListHooks(town, HookPost); err != nil || paths != nil {
+		t.Errorf("ListHooks() of a missing stage = %v, %v; want none", paths, err)
+	}
+}
+
+func TestHookRunnerSuccess(t *testing.T) {
+	town := t.TempDir()
+	out := filepath.Join(t.TempDir(), "seen")
+	writeHook(t, town, HookPost, "10-record", `echo "$GT_TOWN_ROOT $GT_BOOT_PROFILE $GT_BOOT_STAGE $(pwd)" > `+out+`
+cat >> `+out+`
+echo "posted"
+`, 0755)
+
+	r := &HookRunner{TownRoot: town, Profile: "dev", Timeout: 10 * time.Second}
+	results, err := r.Run(HookPost, []byte(`{"success":true}`))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 1 || !results[0].OK || results[0].Output != "posted\n" {
+		t.Fatalf("Run() = %+v, want one successful hook that printed \"posted\"", results)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	realTown, _ := filepath.EvalSymlinks(town)
+	want := town + " dev post " + realTown + "\n" + `{"success":true}`
+	if string(data) != want {
+		t.Errorf("hook saw %q, want %q", data, want)
+	}
+}
+
+func TestHookRunnerPreAbort(t *testing.T) {
+	town := t.TempDir()
+	writeHook(t, town, HookPre, "10-ok", "exit 0\n", 0755)
+	writeHook(t, town, HookPre, "20-vpn", "echo 'VPN is down' >&2\nexit 3\n", 0755)
+	writeHook(t, town, HookPre, "30-never", "exit 0\n", 0755)
+
+	results, err := (&HookRunner{TownRoot: town}).Run(HookPre, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.Join(hookNames(results), ","); got != "10-ok,20-vpn" {
+		t.Fatalf("ran %s, want the pre hooks to stop at the failing 20-vpn", got)
+	}
+	failed := results[1]
+	if failed.OK || !strings.Contains(failed.Error, "exit status 3") || failed.Output != "VPN is down\n" {
+		t.Errorf("failed hook = %+v, want exit status 3 with its output", failed)
+	}
+}
+
+func TestHookRunnerPostFailuresContinue(t *testing.T) {
+	town := t.TempDir()
+	writeHook(t, town, HookPost, "10-fail", "exit 1\n", 0755)
+	writeHook(t, town, HookPost, "20-ok", "exit 0\n", 0755)
+
+	results, err := (&HookRunner{TownRoot: town}).Run(HookPost, []byte("{}"))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(results) != 2 || results[0].OK || !results[1].OK {
+		t.Errorf("Run() = %+v, want the failed post hook followed by the rest", results)
+	}
+}
+
+func TestHookRunnerTimeout(t *testing.T) {
+	town := t.TempDir()
+	writeHook(t, town, HookPre, "10-hang", "echo started\nsleep 30\n", 0755)
+
+	start := time.Now()
+	results, err := (&HookRunner{TownRoot: town, Timeout: 200 * time.Millisecond}).Run(HookPre, nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Run() took %s, want the hook cut off at its timeout", elapsed)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Run() = %+v, want one result", results)
+	}
+	res := results[0]
+	if res.OK || !res.TimedOut || !strings.Contains(res.Error, "timed out after 200ms") || res.Output != "started\n" {
+		t.Errorf("hung hook = %+v, want it timed out with its output", res)
+	}
+}
+
+func TestTail(t *testing.T) {
+	if got := tail("short", 10); got != "short" {
+		t.Errorf("tail() = %q, want it unchanged", got)
+	}
+	if got := tail("line one\nline two\nline three\n", 15); got != "line three\n" {
+		t.Errorf("tail() = %q, want it cut at a line", got)
+	}
+}
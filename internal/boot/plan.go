@@ -0,0 +1,196 @@
+package boot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Action is what booting a town does with one agent.
+type Action string
+
+const (
+	ActionStart    Action = "start"    // Create its session
+	ActionSkip     Action = "skip"     // Already running
+	ActionConflict Action = "conflict" // Can't be started until its conflicts are resolved
+)
+
+// Step is one agent in a Plan and what booting does with it.
+type Step struct {
+	Agent   Agent
+	Action  Action
+	Dir     string // Working directory of the session it is started in
+	Command string // Command the session runs
+	Reason  string // Why it is skipped
+
+	Conflicts []Conflict
+}
+
+// Conflict is a problem found with an agent while planning. A blocking
+// conflict stops the agent from starting and makes its step an
+// ActionConflict; the rest say how its start differs from a clean one,
+// e.g. by replacing a zombie session.
+type Conflict struct {
+	Message  string `json:"message"`
+	Blocking bool   `json:"blocking,omitempty"`
+}
+
+// Block records a conflict that stops the step's agent from starting.
+func (s *Step) Block(message string) {
+	s.Action = ActionConflict
+	s.Conflicts = append(s.Conflicts, Conflict{Message: message, Blocking: true})
+}
+
+// Flag records a conflict that the agent's start works around.
+func (s *Step) Flag(message string) {
+	s.Conflicts = append(s.Conflicts, Conflict{Message: message})
+}
+
+// MarshalJSON flattens the step's agent into it.
+func (s Step) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ID        string     `json:"id"`
+		Role      string     `json:"role"`
+		Rig       string     `json:"rig,omitempty"`
+		Name      string     `json:"name,omitempty"`
+		Session   string     `json:"session,omitempty"`
+		Phase     string     `json:"phase"`
+		DependsOn []string   `json:"depends_on,omitempty"`
+		Action    Action     `json:"action"`
+		Dir       string     `json:"dir,omitempty"`
+		Command   string     `json:"command,omitempty"`
+		Reason    string     `json:"reason,omitempty"`
+		Conflicts []Conflict `json:"conflicts,omitempty"`
+	}{
+		ID:        s.Agent.ID,
+		Role:      s.Agent.Role,
+		Rig:       s.Agent.Rig,
+		Name:      s.Agent.Name,
+		Session:   s.Agent.Session,
+		Phase:     s.Agent.Phase.String(),
+		DependsOn: s.Agent.DependsOn,
+		Action:    s.Action,
+		Dir:       s.Dir,
+		Command:   s.Command,
+		Reason:    s.Reason,
+		Conflicts: s.Conflicts,
+	})
+}
+
+// Plan is what booting a town will do, worked out before anything is
+// started. Sequence.RunPlan carries it out, so what a plan shows is what
+// happens.
+type Plan struct {
+	TownRoot string `json:"town_root"`
+	Steps    []Step `json:"steps"`
+}
+
+// Count returns the number of steps with action.
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, s := range p.Steps {
+		if s.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Agents returns the agents of every step, in order.
+func (p *Plan) Agents() []Agent {
+	agents := make([]Agent, len(p.Steps))
+	for i, s := range p.Steps {
+		agents[i] = s.Agent
+	}
+	return agents
+}
+
+// RunPlan carries out a plan: it starts the agents of ActionStart steps in
+// dependency order, reports skipped agents as ready without starting them,
+// and fails conflicting agents, and with them the agents that depend on
+// them. The statuses are in step order.
+func (s *Sequence) RunPlan(p *Plan) ([]AgentStatus, error) {
+	steps := make(map[string]*Step, len(p.Steps))
+	for i := range p.Steps {
+		steps[p.Steps[i].Agent.ID] = &p.Steps[i]
+	}
+	seq := *s
+	seq.Starter = &planStarter{Starter: s.Starter, steps: steps}
+	withAction := func(fn func(Agent, AgentStatus)) func(Agent, AgentStatus) {
+		if fn == nil {
+			return nil
+		}
+		return func(a Agent, st AgentStatus) {
+			st.Action = string(steps[a.ID].Action)
+			fn(a, st)
+		}
+	}
+	seq.OnChange = withAction(s.OnChange)
+	seq.OnDone = withAction(s.OnDone)
+
+	statuses, err := seq.Run(p.Agents())
+	if err != nil {
+		return nil, err
+	}
+	for i := range statuses {
+		statuses[i].Action = string(p.Steps[i].Action)
+	}
+	return statuses, nil
+}
+
+// planStarter starts only the agents a plan says to.
+type planStarter struct {
+	Starter
+	steps map[string]*Step
+}
+
+func (s *planStarter) Start(ctx context.Context, a Agent) error {
+	step := s.steps[a.ID]
+	switch step.Action {
+	case ActionSkip:
+		return nil
+	case ActionConflict:
+		var blocking []string
+		for _, c := range step.Conflicts {
+			if c.Blocking {
+				blocking = append(blocking, c.Message)
+			}
+		}
+		return errors.New(strings.Join(blocking, "; "))
+	}
+	return s.Starter.Start(ctx, a)
+}
+
+func (s *planStarter) Capture(a Agent) (string, error) {
+	if in, ok := s.Starter.(Inspector); ok {
+		return in.Capture(a)
+	}
+	return "", nil
+}
+
+func (s *planStarter) Stop(a Agent) error {
+	if stopper, ok := s.Starter.(Stopper); ok {
+		return stopper.Stop(a)
+	}
+	return errors.ErrUnsupported
+}
+
+// Refresh refuses to retry agents the plan blocks, which would only fail
+// again.
+func (s *planStarter) Refresh(a Agent) error {
+	if s.steps[a.ID].Action == ActionConflict {
+		return errors.New("blocked by conflicts")
+	}
+	if r, ok := s.Starter.(Refresher); ok {
+		return r.Refresh(a)
+	}
+	return nil
+}
+
+func (s *planStarter) Ready(a Agent) bool {
+	if s.steps[a.ID].Action == ActionSkip {
+		return true
+	}
+	return s.Starter.Ready(a)
+}
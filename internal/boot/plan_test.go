@@ -0,0 +1,88 @@
+package boot
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSequenceRunPlan(t *testing.T) {
+	witness := NewAgent("witness", "alpha", "", "gt-alpha-witness")
+	refinery := NewAgent("refinery", "alpha", "", "gt-alpha-refinery")
+	polecat := NewAgent("polecat", "alpha", "Toast", "gt-alpha-Toast")
+	mayor := NewAgent("mayor", "", "", "hq-mayor")
+	deacon := NewAgent("deacon", "", "", "hq-deacon")
+
+	plan := &Plan{Steps: []Step{
+		{Agent: NewAgent("daemon", "", "", ""), Action: ActionSkip, Reason: "already running (PID 1)"},
+		{Agent: deacon, Action: ActionStart},
+		{Agent: mayor, Action: ActionStart},
+		{Agent: witness, Action: ActionStart},
+		{Agent: refinery, Action: ActionStart},
+		{Agent: polecat, Action: ActionStart},
+	}}
+	plan.Steps[2].Flag("zombie session")
+	plan.Steps[3].Block("worktree missing: /town/alpha/witness")
+
+	starter := newFakeStarter()
+	statuses, err := (&Sequence{Starter: starter, PollInterval: time.Millisecond}).RunPlan(plan)
+	if err != nil {
+		t.Fatalf("RunPlan() error = %v", err)
+	}
+
+	if strings.Join(starter.order, ",") == "" || starter.position("daemon") != -1 || starter.position(witness.ID) != -1 {
+		t.Errorf("started %v, want neither the skipped daemon nor the conflicting witness", starter.order)
+	}
+	want := map[string]struct {
+		ready  bool
+		action Action
+		err    string
+	}{
+		"daemon":    {true, ActionSkip, ""},
+		"deacon":    {true, ActionStart, ""},
+		"mayor":     {true, ActionStart, ""},
+		witness.ID:  {false, ActionConflict, "worktree missing: /town/alpha/witness"},
+		refinery.ID: {false, ActionStart, "dependency alpha/witness not ready"},
+		polecat.ID:  {false, ActionStart, "dependency alpha/witness not ready"},
+	}
+	for _, st := range statuses {
+		w := want[st.ID]
+		if st.Ready != w.ready || st.Action != string(w.action) || st.Error != w.err {
+			t.Errorf("%s = %+v, want ready=%v action=%s error=%q", st.ID, st, w.ready, w.action, w.err)
+		}
+	}
+}
+
+func TestPlanCountAndJSON(t *testing.T) {
+	plan := &Plan{TownRoot: "/town", Steps: []Step{
+		{Agent: NewAgent("daemon", "", "", ""), Action: ActionSkip, Reason: "already running (PID 1)"},
+		{Agent: NewAgent("refinery", "alpha", "", "gt-alpha-refinery"), Action: ActionStart, Dir: "/town/alpha/refinery/rig", Command: "claude"},
+	}}
+	plan.Steps[1].Block("worktree missing")
+
+	if plan.Count(ActionSkip) != 1 || plan.Count(ActionConflict) != 1 || plan.Count(ActionStart) != 0 {
+		t.Errorf("counts = %d skip, %d conflict, %d start", plan.Count(ActionSkip), plan.Count(ActionConflict), plan.Count(ActionStart))
+	}
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded struct {
+		TownRoot string `json:"town_root"`
+		Steps    []map[string]interface{}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	step := decoded.Steps[1]
+	if decoded.TownRoot != "/town" || step["id"] != "alpha/refinery" || step["phase"] != "rig" ||
+		step["action"] != "conflict" || step["dir"] != "/town/alpha/refinery/rig" ||
+		step["depends_on"].([]interface{})[0] != "alpha/witness" || step["conflicts"].([]interface{})[0].(map[string]interface{})["blocking"] != true {
+		t.Errorf("JSON = %s", data)
+	}
+	if _, ok := decoded.Steps[0]["command"]; ok {
+		t.Errorf("skipped daemon has a command in %s", data)
+	}
+}
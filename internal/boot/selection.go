@@ -0,0 +1,92 @@
+package boot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// Selection is the part of a town a boot starts: a boot profile's rigs
+// and roles, or ad hoc ones. The zero Selection is the whole town.
+type Selection struct {
+	Profile string   `json:"profile,omitempty"`
+	Rigs    []string `json:"rigs,omitempty"`  // Empty means every rig
+	Roles   []string `json:"roles,omitempty"` // Empty means every role started
+}
+
+// Partial reports whether the selection leaves part of the town out.
+func (s *Selection) Partial() bool {
+	if s == nil || len(s.Rigs) == 0 && len(s.Roles) == 0 {
+		return false
+	}
+	if len(s.Rigs) > 0 {
+		return true
+	}
+	for _, role := range config.BootRoles {
+		if !contains(s.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRig reports whether the selection includes rig.
+func (s *Selection) HasRig(rig string) bool {
+	return s == nil || len(s.Rigs) == 0 || contains(s.Rigs, rig)
+}
+
+// HasRole reports whether the selection names role. A selection naming
+// no roles has them all.
+func (s *Selection) HasRole(role string) bool {
+	return s == nil || len(s.Roles) == 0 || contains(s.Roles, role)
+}
+
+// NamesRole reports whether the selection lists role explicitly.
+func (s *Selection) NamesRole(role string) bool {
+	return s != nil && contains(s.Roles, role)
+}
+
+// Filter returns the agents in the selection. Town-level agents are in
+// every rig's selection.
+func (s *Selection) Filter(agents []Agent) []Agent {
+	var kept []Agent
+	for _, a := range agents {
+		if s.HasRole(a.Role) && (a.Rig == "" || s.HasRig(a.Rig)) {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// String describes the selection, e.g. "profile dev (rigs: gongshow;
+// roles: mayor, witness)".
+func (s *Selection) String() string {
+	if !s.Partial() {
+		if s != nil && s.Profile != "" {
+			return fmt.Sprintf("profile %s (whole town)", s.Profile)
+		}
+		return "whole town"
+	}
+	var parts []string
+	if len(s.Rigs) > 0 {
+		parts = append(parts, "rigs: "+strings.Join(s.Rigs, ", "))
+	}
+	if len(s.Roles) > 0 {
+		parts = append(parts, "roles: "+strings.Join(s.Roles, ", "))
+	}
+	desc := strings.Join(parts, "; ")
+	if s.Profile != "" {
+		return fmt.Sprintf("profile %s (%s)", s.Profile, desc)
+	}
+	return desc
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
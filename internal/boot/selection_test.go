@@ -0,0 +1,54 @@
+package boot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectionFilter(t *testing.T) {
+	agents := append(townPlan(), NewAgent("crew", "alpha", "max", ""))
+	ids := func(agents []Agent) string {
+		var ids []string
+		for _, a := range agents {
+			ids = append(ids, a.ID)
+		}
+		return strings.Join(ids, " ")
+	}
+
+	tests := []struct {
+		name string
+		sel  *Selection
+		want string
+	}{
+		{"whole town", nil, ids(agents)},
+		{"rig", &Selection{Rigs: []string{"beta"}},
+			"beta/crew/max beta/polecats/Nux beta/refinery beta/witness mayor deacon daemon"},
+		{"roles", &Selection{Roles: []string{"mayor", "witness", "crew"}},
+			"beta/crew/max alpha/witness beta/witness mayor alpha/crew/max"},
+		{"rig and roles", &Selection{Profile: "dev", Rigs: []string{"alpha"}, Roles: []string{"mayor", "witness", "crew"}},
+			"alpha/witness mayor alpha/crew/max"},
+	}
+	for _, tt := range tests {
+		if got := ids(tt.sel.Filter(agents)); got != tt.want {
+			t.Errorf("%s: Filter() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectionString(t *testing.T) {
+	tests := []struct {
+		sel  *Selection
+		want string
+	}{
+		{nil, "whole town"},
+		{&Selection{Profile: "full"}, "profile full (whole town)"},
+		{&Selection{Profile: "all", Roles: []string{"daemon", "deacon", "mayor", "witness", "refinery", "crew", "polecat"}}, "profile all (whole town)"},
+		{&Selection{Profile: "dev", Rigs: []string{"gongshow"}, Roles: []string{"mayor", "crew"}}, "profile dev (rigs: gongshow; roles: mayor, crew)"},
+		{&Selection{Roles: []string{"witness"}}, "roles: witness"},
+	}
+	for _, tt := range tests {
+		if got := tt.sel.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,755 @@
+package boot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+// Phase is a stage of bringing a town up. An agent isn't started until
+// every agent in the earlier phases has either become ready or failed.
+type Phase int
+
+const (
+	PhaseInfrastructure Phase = iota // daemon
+	PhaseTown                        // deacon, mayor
+	PhaseRig                         // witness, refinery
+	PhaseWorkers                     // polecats, crew
+	numPhases
+)
+
+var phaseNames = [numPhases]string{"infrastructure", "town", "rig", "workers"}
+
+func (p Phase) String() string {
+	if p < 0 || p >= numPhases {
+		return fmt.Sprintf("phase-%d", int(p))
+	}
+	return phaseNames[p]
+}
+
+// PhaseForRole returns the phase an agent of role starts in. Unknown roles
+// start with the workers.
+func PhaseForRole(role string) Phase {
+	switch role {
+	case "daemon":
+		return PhaseInfrastructure
+	case "deacon", "mayor":
+		return PhaseTown
+	case "witness", "refinery":
+		return PhaseRig
+	default:
+		return PhaseWorkers
+	}
+}
+
+// RoleDependencies returns the IDs of the agents an agent of role in rig
+// waits for: they must be ready before it is started. Phases already order
+// the roles; these are the dependencies that failing must block, because the
+// agent talks to them as soon as it is up.
+func RoleDependencies(role, rig string) []string {
+	switch role {
+	case "deacon", "mayor":
+		return []string{"daemon"}
+	case "refinery":
+		return []string{rig + "/witness"}
+	case "polecat":
+		// Polecats mail their witness on start; a dead witness means lost mail.
+		return []string{rig + "/witness"}
+	default:
+		return nil
+	}
+}
+
+// DefaultReadyTimeout is how long an agent has to start and become ready.
+const DefaultReadyTimeout = 60 * time.Second
+
+// DefaultBootTimeout is how long a whole Sequence may take. Agents not
+// started by then aren't.
+const DefaultBootTimeout = 10 * time.Minute
+
+// DefaultRetryBackoff is how long a Sequence waits before its first retry
+// of failed agents.
+const DefaultRetryBackoff = 10 * time.Second
+
+// Agent is one agent in a startup sequence.
+type Agent struct {
+	ID           string // Address-style ID, e.g. "deacon", "gongshow/witness", "gongshow/polecats/Toast"
+	Role         string
+	Rig          string
+	Name         string // Crew or polecat name
+	Session      string // tmux session, for Starter.Ready
+	Phase        Phase
+	DependsOn    []string      // IDs of agents that must be ready first
+	ReadyTimeout time.Duration // Zero means Sequence.ReadyTimeout
+}
+
+// NewAgent returns an agent of role with its ID, phase and dependencies
+// filled in from the role.
+func NewAgent(role, rig, name, session string) Agent {
+	id := role
+	switch role {
+	case "witness", "refinery":
+		id = rig + "/" + role
+	case "crew":
+		id = rig + "/crew/" + name
+	case "polecat":
+		id = rig + "/polecats/" + name
+	}
+	return Agent{
+		ID:        id,
+		Role:      role,
+		Rig:       rig,
+		Name:      name,
+		Session:   session,
+		Phase:     PhaseForRole(role),
+		DependsOn: RoleDependencies(role, rig),
+	}
+}
+
+// Outcome is how an agent's start ended.
+type Outcome string
+
+const (
+	OutcomeReady        Outcome = "ready"
+	OutcomeFailed       Outcome = "failed"        // Its start failed, or a dependency's did
+	OutcomeTimedOut     Outcome = "timed_out"     // Not ready in time
+	OutcomeNotAttempted Outcome = "not_attempted" // The boot timed out, or was aborted, before it was started
+)
+
+// AgentState is where an agent is in its start. States only move forward:
+// pending, starting, waiting for ready, then ready or failed, except that
+// a retry starts a failed agent over.
+type AgentState string
+
+const (
+	StatePending      AgentState = "pending"
+	StateStarting     AgentState = "starting"      // Start called
+	StateWaitingReady AgentState = "waiting_ready" // Started, not ready yet
+	StateReady        AgentState = "ready"
+	StateFailed       AgentState = "failed" // See the Outcome for how
+)
+
+// Done reports whether the agent's start is over.
+func (s AgentState) Done() bool {
+	return s == StateReady || s == StateFailed
+}
+
+// Order returns the position of the state in an agent's start, from 0 for
+// pending; ready and failed share the last.
+func (s AgentState) Order() int {
+	switch s {
+	case StateStarting:
+		return 1
+	case StateWaitingReady:
+		return 2
+	case StateReady, StateFailed:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Transition is an agent entering a state.
+type Transition struct {
+	State AgentState `json:"state"`
+	At    time.Time  `json:"at"`
+}
+
+// AgentStatus records how one agent's start went.
+type AgentStatus struct {
+	ID        string    `json:"id"`
+	Phase     string    `json:"phase"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	ReadyAt   time.Time `json:"ready_at,omitempty"`
+	Ready     bool      `json:"ready"`
+	Outcome   Outcome   `json:"outcome,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Action    string    `json:"action,omitempty"` // The plan's action, with Sequence.RunPlan
+
+	// State is where the agent is now, and History every state it has
+	// entered after pending, in order.
+	State   AgentState   `json:"state,omitempty"`
+	History []Transition `json:"history,omitempty"`
+
+	// BootTimeout is set when the whole boot's timeout, rather than the
+	// agent's own, ended its start.
+	BootTimeout bool `json:"boot_timeout,omitempty"`
+
+	// PaneOutput is what the agent's session showed when it timed out.
+	PaneOutput string `json:"pane_output,omitempty"`
+
+	// RolledBack is set when the session of an agent that timed out was
+	// killed (Sequence.RollbackFailed).
+	RolledBack bool `json:"rolled_back,omitempty"`
+
+	// Retry is the retry this status is from; 0 for the first pass.
+	Retry int `json:"retry,omitempty"`
+}
+
+// Summary counts the outcomes of a startup.
+type Summary struct {
+	Ready        int  `json:"ready"`
+	Retried      int  `json:"retried,omitempty"` // Of the ready, those ready on a retry
+	Failed       int  `json:"failed"`            // Including timed out
+	TimedOut     int  `json:"timed_out"`
+	NotAttempted int  `json:"not_attempted"`
+	BootTimedOut bool `json:"boot_timed_out,omitempty"` // The whole boot ran out of time
+}
+
+// Summarize counts the outcomes in statuses.
+func Summarize(statuses []AgentStatus) Summary {
+	var sum Summary
+	for _, st := range statuses {
+		// Ready decides, for statuses saved before outcomes were recorded.
+		switch {
+		case st.Ready:
+			sum.Ready++
+			if st.Retry > 0 {
+				sum.Retried++
+			}
+		case st.Outcome == OutcomeTimedOut:
+			sum.TimedOut++
+			sum.Failed++
+		case st.Outcome == OutcomeNotAttempted:
+			sum.NotAttempted++
+		default:
+			sum.Failed++
+		}
+		if st.BootTimeout {
+			sum.BootTimedOut = true
+		}
+	}
+	return sum
+}
+
+// Starter starts agents and reports when they are ready.
+type Starter interface {
+	// Start starts the agent. An agent that is already running is not an
+	// error. ctx is done once the agent's ready timeout or the boot's has
+	// passed, and Start should give up then: a sequence that rolls back
+	// waits for Start to return before stopping the agent.
+	Start(ctx context.Context, a Agent) error
+
+	// Ready reports whether the agent is up and able to take work.
+	Ready(a Agent) bool
+}
+
+// Inspector is implemented by Starters that can show what an agent's
+// session displays, for diagnosing an agent that didn't become ready.
+type Inspector interface {
+	Capture(a Agent) (string, error)
+}
+
+// Stopper is implemented by Starters that can kill an agent's session.
+// Stop returns ErrNotRunning if there was no session to kill.
+type Stopper interface {
+	Stop(a Agent) error
+}
+
+// ErrNotRunning is returned by Stopper.Stop when the agent has no session,
+// so there was nothing to roll back.
+var ErrNotRunning = errors.New("agent not running")
+
+// Refresher is implemented by Starters that clear what a failed start
+// left behind, e.g. a session with no agent running in it, before the
+// agent is retried. An agent that can't be refreshed isn't retried.
+type Refresher interface {
+	Refresh(a Agent) error
+}
+
+// SessionReady reports whether session exists and an agent runtime, not
+// just a shell, is running in it. Starters use it for tmux-hosted agents.
+func SessionReady(t *tmux.Tmux, session string) bool {
+	has, err := t.HasSession(session)
+	return err == nil && has && t.IsAgentRunning(session)
+}
+
+// DefaultMaxConcurrent is how many agents a Sequence starts at once
+// unless told otherwise.
+const DefaultMaxConcurrent = 4
+
+// Sequence starts agents in dependency order: phase by phase, and within
+// that, each agent only once the agents it depends on are ready. Agents
+// with no path between them start concurrently, at most MaxConcurrent at a
+// time. An agent whose dependency fails, or that doesn't become ready in
+// time, fails without holding up the agents that don't depend on it.
+//
+// Once Timeout has passed, agents still starting time out and the rest
+// aren't started. A timed out agent's session is captured, if the Starter
+// is an Inspector, and left for inspection unless RollbackFailed is set.
+type Sequence struct {
+	Starter       Starter
+	MaxConcurrent int           // Agents starting at once; zero means DefaultMaxConcurrent, negative no limit
+	ReadyTimeout  time.Duration // Per agent; zero means DefaultReadyTimeout
+	Timeout       time.Duration // The whole sequence; zero means DefaultBootTimeout
+	PollInterval  time.Duration // Zero means 500ms
+
+	// RollbackFailed kills the sessions of agents that time out, if the
+	// Starter is a Stopper.
+	RollbackFailed bool
+
+	// Serial starts one agent at a time, in phase order and then the order
+	// given, for debugging startup problems.
+	Serial bool
+
+	// Retries is how many times agents that failed or timed out are
+	// retried after the first pass; RetryBackoff, doubled for each retry,
+	// is how long before each one (zero means DefaultRetryBackoff).
+	// BeforeRetry, if set, is called with the agents about to be retried:
+	// not those the Starter fails to Refresh, if it is a Refresher, which
+	// aren't retried again.
+	Retries      int
+	RetryBackoff time.Duration
+	BeforeRetry  func(retry int, agents []Agent)
+
+	// OnChange, if set, is called with an agent's status each time its
+	// State changes, and OnDone as soon as the agent is ready or has
+	// failed, again after each retry. No two calls, to either, are ever
+	// concurrent.
+	OnChange func(a Agent, st AgentStatus)
+	OnDone   func(a Agent, st AgentStatus)
+}
+
+// Run starts agents and returns their statuses, in the order given. It
+// returns an error, without starting anything, if the dependencies can't
+// be satisfied in order: a duplicate ID, a cycle, or a dependency on an
+// agent in a later phase. Dependencies on agents not in agents are assumed
+// to be running already.
+//
+// After the first pass, agents that failed or timed out are retried up to
+// Retries times, each retry after a backoff, in the same dependency order:
+// a retried agent waits for its retried dependencies, and fails if a
+// dependency that isn't retried isn't ready.
+func (s *Sequence) Run(agents []Agent) ([]AgentStatus, error) {
+	index, err := validateSequence(agents)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultBootTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	r := &sequenceRun{
+		Sequence:      s,
+		ctx:           ctx,
+		agents:        agents,
+		index:         index,
+		statuses:      make([]AgentStatus, len(agents)),
+		unrefreshable: make([]bool, len(agents)),
+	}
+	all := make([]bool, len(agents))
+	for i, a := range agents {
+		r.statuses[i] = AgentStatus{ID: a.ID, Phase: a.Phase.String(), State: StatePending}
+		all[i] = true
+	}
+	r.pass(all)
+
+	for retry := 1; retry <= s.Retries; retry++ {
+		if !r.retry(retry) {
+			break
+		}
+	}
+	return r.statuses, nil
+}
+
+// sequenceRun is one Run of a Sequence. Each agent's status is only
+// written by the goroutine starting it; the callbacks get copies.
+type sequenceRun struct {
+	*Sequence
+	ctx        context.Context
+	agents     []Agent
+	index      map[string]int
+	statuses   []AgentStatus
+	callbackMu sync.Mutex
+
+	// unrefreshable are the agents the Starter failed to Refresh.
+	unrefreshable []bool
+}
+
+// pass starts the agents whose include flag is set. The others count as
+// finished: their dependents go by whether they are ready.
+func (r *sequenceRun) pass(include []bool) {
+	if r.Serial {
+		for _, i := range serialOrder(r.agents, r.index) {
+			if include[i] {
+				r.startAgent(i, nil)
+			}
+		}
+		return
+	}
+
+	done := make([]chan struct{}, len(r.agents))
+	var phases [numPhases]sync.WaitGroup
+	for i, a := range r.agents {
+		done[i] = make(chan struct{})
+		if include[i] {
+			phases[a.Phase].Add(1)
+		} else {
+			close(done[i])
+		}
+	}
+
+	var sem chan struct{}
+	switch {
+	case r.MaxConcurrent > 0:
+		sem = make(chan struct{}, r.MaxConcurrent)
+	case r.MaxConcurrent == 0:
+		sem = make(chan struct{}, DefaultMaxConcurrent)
+	}
+
+	var wg sync.WaitGroup
+	for i := range r.agents {
+		if !include[i] {
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer phases[r.agents[i].Phase].Done()
+			defer close(done[i])
+
+			a := r.agents[i]
+			for p := PhaseInfrastructure; p < a.Phase; p++ {
+				phases[p].Wait()
+			}
+			for _, dep := range a.DependsOn {
+				if j, ok := r.index[dep]; ok {
+					<-done[j]
+				}
+			}
+			r.startAgent(i, sem)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// startAgent starts agent i, if it can be started, once there is room in
+// sem (nil for no limit), and reports the result.
+func (r *sequenceRun) startAgent(i int, sem chan struct{}) {
+	st := &r.statuses[i]
+	if r.runnable(r.ctx, r.agents[i], r.index, r.statuses, st) && r.acquire(sem, st) {
+		r.start(r.ctx, r.agents[i], st, func(state AgentState) { r.transition(i, state) })
+		if sem != nil {
+			<-sem
+		}
+	}
+	r.finish(i)
+}
+
+// acquire takes a place in sem, unless the boot times out first.
+func (r *sequenceRun) acquire(sem chan struct{}, st *AgentStatus) bool {
+	if sem == nil {
+		return true
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-r.ctx.Done():
+		notAttempted(st)
+		return false
+	}
+}
+
+// retry starts the agents that failed or timed out again, once the
+// backoff before retry is over. An agent the Starter fails to Refresh
+// isn't retried, then or later. It reports whether any were retried.
+func (r *sequenceRun) retry(retry int) bool {
+	refresher, _ := r.Starter.(Refresher)
+	var failed []int
+	for i, st := range r.statuses {
+		if st.Ready || st.BootTimeout || r.unrefreshable[i] || (st.Outcome != OutcomeFailed && st.Outcome != OutcomeTimedOut) {
+			continue
+		}
+		if refresher != nil {
+			if err := refresher.Refresh(r.agents[i]); err != nil {
+				r.statuses[i].Error += fmt.Sprintf(" (not retried: %v)", err)
+				r.unrefreshable[i] = true
+				continue
+			}
+		}
+		failed = append(failed, i)
+	}
+	if len(failed) == 0 {
+		return false
+	}
+
+	backoff := r.RetryBackoff
+	if backoff == 0 {
+		backoff = DefaultRetryBackoff
+	}
+	timer := time.NewTimer(backoff << (retry - 1))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-r.ctx.Done():
+		return false
+	}
+
+	agents := make([]Agent, len(failed))
+	for n, i := range failed {
+		agents[n] = r.agents[i]
+	}
+	if r.BeforeRetry != nil {
+		r.BeforeRetry(retry, agents)
+	}
+
+	include := make([]bool, len(r.agents))
+	for _, i := range failed {
+		st := &r.statuses[i]
+		include[i] = true
+		*st = AgentStatus{
+			ID:      st.ID,
+			Phase:   st.Phase,
+			Action:  st.Action,
+			State:   st.State,
+			History: st.History,
+			Retry:   retry,
+		}
+	}
+	r.pass(include)
+	return true
+}
+
+func (r *sequenceRun) transition(i int, state AgentState) {
+	st := &r.statuses[i]
+	st.State = state
+	st.History = append(st.History, Transition{State: state, At: time.Now()})
+	if r.OnChange == nil {
+		return
+	}
+	r.callbackMu.Lock()
+	defer r.callbackMu.Unlock()
+	r.OnChange(r.agents[i], st.snapshot())
+}
+
+// finish records that agent i is ready or has failed.
+func (r *sequenceRun) finish(i int) {
+	if r.statuses[i].Ready {
+		r.transition(i, StateReady)
+	} else {
+		r.transition(i, StateFailed)
+	}
+	if r.OnDone == nil {
+		return
+	}
+	r.callbackMu.Lock()
+	defer r.callbackMu.Unlock()
+	r.OnDone(r.agents[i], r.statuses[i].snapshot())
+}
+
+// runnable reports whether a can be started: the boot hasn't timed out
+// and its dependencies in the sequence, which must have finished, are
+// ready. If not, it records why in st.
+func (s *Sequence) runnable(ctx context.Context, a Agent, index map[string]int, statuses []AgentStatus, st *AgentStatus) bool {
+	if ctx.Err() != nil {
+		notAttempted(st)
+		return false
+	}
+	for _, dep := range a.DependsOn {
+		if j, ok := index[dep]; ok && !statuses[j].Ready {
+			st.Outcome = OutcomeFailed
+			st.Error = fmt.Sprintf("dependency %s not ready", dep)
+			return false
+		}
+	}
+	return true
+}
+
+func notAttempted(st *AgentStatus) {
+	st.Outcome = OutcomeNotAttempted
+	st.BootTimeout = true
+	st.Error = "not started: boot timed out"
+}
+
+// snapshot returns a copy of st that doesn't share its history.
+func (st AgentStatus) snapshot() AgentStatus {
+	st.History = append([]Transition(nil), st.History...)
+	return st
+}
+
+// start starts a and waits for it to become ready, recording both in st
+// and reporting the starting and waiting_ready states to transition. It
+// gives up when a's timeout or the boot's passes; a Start call still
+// running then is left to finish in the background, unless the sequence
+// rolls back, which waits for it.
+func (s *Sequence) start(bootCtx context.Context, a Agent, st *AgentStatus, transition func(AgentState)) {
+	timeout := a.ReadyTimeout
+	if timeout == 0 {
+		timeout = s.ReadyTimeout
+	}
+	if timeout == 0 {
+		timeout = DefaultReadyTimeout
+	}
+	poll := s.PollInterval
+	if poll == 0 {
+		poll = 500 * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(bootCtx, timeout)
+	defer cancel()
+
+	st.StartedAt = time.Now()
+	transition(StateStarting)
+	started := make(chan error, 1)
+	go func() { started <- s.Starter.Start(ctx, a) }()
+	select {
+	case err := <-started:
+		if err != nil {
+			st.Outcome = OutcomeFailed
+			st.Error = err.Error()
+			return
+		}
+	case <-ctx.Done():
+		s.timedOut(bootCtx, a, st, timeout, started)
+		return
+	}
+	transition(StateWaitingReady)
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		if s.Starter.Ready(a) {
+			st.Ready = true
+			st.Outcome = OutcomeReady
+			st.ReadyAt = time.Now()
+			return
+		}
+		select {
+		case <-ctx.Done():
+			s.timedOut(bootCtx, a, st, timeout, nil)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// timedOut records that a didn't become ready in time, with what its
+// session shows, and kills the session if the sequence rolls back. If a's
+// Start call is still running, pending receives its result; the rollback
+// waits for it, so as not to kill the session before Start has made it.
+func (s *Sequence) timedOut(bootCtx context.Context, a Agent, st *AgentStatus, timeout time.Duration, pending <-chan error) {
+	st.Outcome = OutcomeTimedOut
+	if bootCtx.Err() != nil {
+		st.BootTimeout = true
+		st.Error = "not ready when the boot timed out"
+	} else {
+		st.Error = fmt.Sprintf("not ready after %s", timeout)
+	}
+
+	if in, ok := s.Starter.(Inspector); ok {
+		if out, err := in.Capture(a); err == nil {
+			st.PaneOutput = out
+		}
+	}
+	if s.RollbackFailed {
+		if stopper, ok := s.Starter.(Stopper); ok {
+			if pending != nil {
+				<-pending
+			}
+			switch err := stopper.Stop(a); {
+			case err == nil:
+				st.RolledBack = true
+			case errors.Is(err, ErrNotRunning), errors.Is(err, errors.ErrUnsupported):
+				// Nothing to roll back
+			default:
+				st.Error += fmt.Sprintf(" (rollback failed: %v)", err)
+			}
+		}
+	}
+}
+
+// serialOrder returns the indexes of agents in the order Serial starts
+// them: phase by phase, and within a phase in the order given, except that
+// an agent waits for the dependencies listed after it. agents must have
+// been validated.
+func serialOrder(agents []Agent, index map[string]int) []int {
+	order := make([]int, 0, len(agents))
+	placed := make([]bool, len(agents))
+	for p := PhaseInfrastructure; p < numPhases; p++ {
+		for progress := true; progress; {
+			progress = false
+			for i, a := range agents {
+				if a.Phase != p || placed[i] {
+					continue
+				}
+				ready := true
+				for _, dep := range a.DependsOn {
+					if j, ok := index[dep]; ok && !placed[j] {
+						ready = false
+						break
+					}
+				}
+				if ready {
+					order = append(order, i)
+					placed[i] = true
+					progress = true
+					break
+				}
+			}
+		}
+	}
+	return order
+}
+
+// validateSequence indexes agents by ID and checks that their dependencies
+// can be started in order.
+func validateSequence(agents []Agent) (map[string]int, error) {
+	index := make(map[string]int, len(agents))
+	for i, a := range agents {
+		if a.Phase < 0 || a.Phase >= numPhases {
+			return nil, fmt.Errorf("agent %s: invalid phase %d", a.ID, int(a.Phase))
+		}
+		if _, dup := index[a.ID]; dup {
+			return nil, fmt.Errorf("agent %s listed twice", a.ID)
+		}
+		index[a.ID] = i
+	}
+
+	for _, a := range agents {
+		for _, dep := range a.DependsOn {
+			if j, ok := index[dep]; ok && agents[j].Phase > a.Phase {
+				return nil, fmt.Errorf("agent %s (%s phase) depends on %s in the later %s phase", a.ID, a.Phase, dep, agents[j].Phase)
+			}
+		}
+	}
+
+	// Depth-first search for cycles among the dependencies in the plan.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	marks := make([]int, len(agents))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch marks[i] {
+		case visiting:
+			return fmt.Errorf("dependency cycle through %s", agents[i].ID)
+		case visited:
+			return nil
+		}
+		marks[i] = visiting
+		for _, dep := range agents[i].DependsOn {
+			if j, ok := index[dep]; ok {
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		marks[i] = visited
+		return nil
+	}
+	for i := range agents {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return index, nil
+}
@@ -0,0 +1,730 @@
+package boot
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStarter records start order. Agents in never don't become ready;
+// agents in fail fail to start; the rest are ready once started.
+type fakeStarter struct {
+	mu      sync.Mutex
+	order   []string
+	started map[string]bool
+	never   map[string]bool
+	fail    map[string]bool
+}
+
+func newFakeStarter() *fakeStarter {
+	return &fakeStarter{started: map[string]bool{}, never: map[string]bool{}, fail: map[string]bool{}}
+}
+
+func (f *fakeStarter) Start(_ context.Context, a Agent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.order = append(f.order, a.ID)
+	if f.fail[a.ID] {
+		return errors.New("session failed")
+	}
+	f.started[a.ID] = true
+	return nil
+}
+
+func (f *fakeStarter) Ready(a Agent) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.started[a.ID] && !f.never[a.ID]
+}
+
+// position returns where id was started, or -1.
+func (f *fakeStarter) position(id string) int {
+	for i, started := range f.order {
+		if started == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// townPlan is a town with two rigs, listed in reverse of start order so
+// that ordering can't come from the list.
+func townPlan() []Agent {
+	return []Agent{
+		NewAgent("polecat", "alpha", "Toast", "gt-alpha-Toast"),
+		NewAgent("crew", "beta", "max", "gt-beta-crew-max"),
+		NewAgent("polecat", "beta", "Nux", "gt-beta-Nux"),
+		NewAgent("refinery", "alpha", "", "gt-alpha-refinery"),
+		NewAgent("refinery", "beta", "", "gt-beta-refinery"),
+		NewAgent("witness", "alpha", "", "gt-alpha-witness"),
+		NewAgent("witness", "beta", "", "gt-beta-witness"),
+		NewAgent("mayor", "", "", "hq-mayor"),
+		NewAgent("deacon", "", "", "hq-deacon"),
+		NewAgent("daemon", "", "", ""),
+	}
+}
+
+func TestSequenceOrder(t *testing.T) {
+	starter := newFakeStarter()
+	seq := &Sequence{Starter: starter, PollInterval: time.Millisecond}
+
+	plan := townPlan()
+	statuses, err := seq.Run(plan)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	for i, st := range statuses {
+		if !st.Ready || st.Error != "" || st.StartedAt.IsZero() || st.ReadyAt.Before(st.StartedAt) {
+			t.Errorf("status %+v, want ready", st)
+		}
+		if st.ID != plan[i].ID || st.Phase != plan[i].Phase.String() {
+			t.Errorf("status %d = %s (%s), want %s (%s)", i, st.ID, st.Phase, plan[i].ID, plan[i].Phase)
+		}
+	}
+
+	// Every agent starts after all agents of earlier phases and after its
+	// dependencies.
+	for _, a := range plan {
+		for _, b := range plan {
+			if b.Phase < a.Phase && starter.position(b.ID) > starter.position(a.ID) {
+				t.Errorf("%s (%s) started before %s (%s): %v", a.ID, a.Phase, b.ID, b.Phase, starter.order)
+			}
+		}
+		for _, dep := range a.DependsOn {
+			if starter.position(dep) > starter.position(a.ID) {
+				t.Errorf("%s started before its dependency %s: %v", a.ID, dep, starter.order)
+			}
+		}
+	}
+	if starter.order[0] != "daemon" {
+		t.Errorf("first started = %s, want daemon", starter.order[0])
+	}
+}
+
+func TestSequencePartialFailure(t *testing.T) {
+	starter := newFakeStarter()
+	starter.never["alpha/witness"] = true
+	starter.fail["mayor"] = true
+	seq := &Sequence{Starter: starter, PollInterval: time.Millisecond, ReadyTimeout: 20 * time.Millisecond}
+
+	plan := townPlan()
+	statuses, err := seq.Run(plan)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	byID := make(map[string]AgentStatus)
+	for _, st := range statuses {
+		byID[st.ID] = st
+	}
+
+	if st := byID["alpha/witness"]; st.Ready || !strings.Contains(st.Error, "not ready after") {
+		t.Errorf("alpha/witness = %+v, want timed out", st)
+	}
+	for _, id := range []string{"alpha/refinery", "alpha/polecats/Toast"} {
+		if st := byID[id]; st.Ready || st.Error != "dependency alpha/witness not ready" || !st.StartedAt.IsZero() {
+			t.Errorf("%s = %+v, want failed on its dependency without starting", id, st)
+		}
+	}
+	if st := byID["mayor"]; st.Ready || st.Error != "session failed" {
+		t.Errorf("mayor = %+v, want the start error", st)
+	}
+
+	// Nothing depends on the mayor or on alpha, so beta comes up anyway.
+	for _, id := range []string{"daemon", "deacon", "beta/witness", "beta/refinery", "beta/polecats/Nux", "beta/crew/max"} {
+		if st := byID[id]; !st.Ready {
+			t.Errorf("%s = %+v, want ready", id, st)
+		}
+	}
+}
+
+func TestSequenceAgentTimeout(t *testing.T) {
+	starter := newFakeStarter()
+	starter.never["deacon"] = true
+	deacon := NewAgent("deacon", "", "", "hq-deacon")
+	deacon.ReadyTimeout = 5 * time.Millisecond
+	seq := &Sequence{Starter: starter, PollInterval: time.Millisecond, ReadyTimeout: time.Hour}
+
+	start := time.Now()
+	statuses, err := seq.Run([]Agent{deacon})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if statuses[0].Ready || time.Since(start) > time.Second {
+		t.Errorf("status = %+v after %s, want the agent's own timeout", statuses[0], time.Since(start))
+	}
+}
+
+func TestSequenceMaxConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	current, peak := 0, 0
+	starter := &funcStarter{start: func(Agent) {
+		mu.Lock()
+		current++
+		if current > peak {
+			peak = current
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		current--
+		mu.Unlock()
+	}}
+
+	var plan []Agent
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		plan = append(plan, NewAgent("crew", "rig", name, ""))
+	}
+	if _, err := (&Sequence{Starter: starter, MaxConcurrent: 2}).Run(plan); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if peak > 2 {
+		t.Errorf("peak concurrent starts = %d, want <= 2", peak)
+	}
+}
+
+// sleepyStarter takes delay to start each agent and fails those in fail.
+type sleepyStarter struct {
+	delay time.Duration
+	fail  map[string]bool
+}
+
+func (f *sleepyStarter) Start(_ context.Context, a Agent) error {
+	time.Sleep(f.delay)
+	if f.fail[a.ID] {
+		return errors.New("session failed")
+	}
+	return nil
+}
+
+func (f *sleepyStarter) Ready(a Agent) bool { return !f.fail[a.ID] }
+
+// fourRigTown is a town with four rigs of a witness, a refinery and two
+// polecats each.
+func fourRigTown() []Agent {
+	plan := []Agent{
+		NewAgent("daemon", "", "", ""),
+		NewAgent("deacon", "", "", ""),
+		NewAgent("mayor", "", "", ""),
+	}
+	for _, r := range []string{"a", "b", "c", "d"} {
+		plan = append(plan,
+			NewAgent("witness", r, "", ""),
+			NewAgent("refinery", r, "", ""),
+			NewAgent("polecat", r, "Toast", ""),
+			NewAgent("polecat", r, "Nux", ""))
+	}
+	return plan
+}
+
+func TestSequenceParallelFasterThanSerial(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	plan := fourRigTown()
+
+	elapsed := func(seq *Sequence) time.Duration {
+		start := time.Now()
+		statuses, err := seq.Run(plan)
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		for _, st := range statuses {
+			if !st.Ready {
+				t.Fatalf("status %+v, want ready", st)
+			}
+		}
+		return time.Since(start)
+	}
+
+	serial := elapsed(&Sequence{Starter: &sleepyStarter{delay: delay}, PollInterval: time.Millisecond, Serial: true})
+	parallel := elapsed(&Sequence{Starter: &sleepyStarter{delay: delay}, PollInterval: time.Millisecond})
+
+	// Serially, each of the 19 agents takes delay. With the default four
+	// workers: the daemon, the deacon and mayor together, the witnesses,
+	// the refineries, then eight polecats in two rounds.
+	if serial < time.Duration(len(plan))*delay {
+		t.Errorf("serial boot took %s, want at least %s", serial, time.Duration(len(plan))*delay)
+	}
+	if parallel > serial/2 {
+		t.Errorf("parallel boot took %s, serial %s; want under half", parallel, serial)
+	}
+}
+
+func TestSequenceAggregatesFailures(t *testing.T) {
+	starter := &sleepyStarter{delay: 5 * time.Millisecond, fail: map[string]bool{
+		"b/witness":      true,
+		"c/polecats/Nux": true,
+	}}
+	var done []string
+	seq := &Sequence{
+		Starter:       starter,
+		PollInterval:  time.Millisecond,
+		MaxConcurrent: 3,
+		OnDone: func(a Agent, st AgentStatus) {
+			// Not locked: OnDone calls must not overlap (go test -race).
+			done = append(done, a.ID)
+			if st.ID != a.ID {
+				t.Errorf("OnDone(%s) with the status of %s", a.ID, st.ID)
+			}
+		},
+	}
+
+	plan := fourRigTown()
+	statuses, err := seq.Run(plan)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(done) != len(plan) {
+		t.Errorf("OnDone called for %v, want each of the %d agents once", done, len(plan))
+	}
+
+	failed := map[string]string{
+		"b/witness":        "session failed",
+		"b/refinery":       "dependency b/witness not ready",
+		"b/polecats/Toast": "dependency b/witness not ready",
+		"b/polecats/Nux":   "dependency b/witness not ready",
+		"c/polecats/Nux":   "session failed",
+	}
+	for _, st := range statuses {
+		want, ok := failed[st.ID]
+		switch {
+		case ok && (st.Ready || st.Error != want):
+			t.Errorf("%s = %+v, want error %q", st.ID, st, want)
+		case !ok && !st.Ready:
+			// A failure doesn't cancel its siblings.
+			t.Errorf("%s = %+v, want ready", st.ID, st)
+		}
+	}
+}
+
+func TestSequenceSerialOrder(t *testing.T) {
+	starter := newFakeStarter()
+	seq := &Sequence{Starter: starter, PollInterval: time.Millisecond, Serial: true}
+
+	plan := townPlan()
+	if _, err := seq.Run(plan); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Phase by phase, and within a phase in the order given, except that
+	// each refinery waits for its witness.
+	want := []string{
+		"daemon",
+		"mayor", "deacon",
+		"alpha/witness", "alpha/refinery", "beta/witness", "beta/refinery",
+		"alpha/polecats/Toast", "beta/crew/max", "beta/polecats/Nux",
+	}
+	if strings.Join(starter.order, " ") != strings.Join(want, " ") {
+		t.Errorf("serial start order = %v, want %v", starter.order, want)
+	}
+}
+
+// hangingStarter never makes its agents ready, and hangs starting those
+// in hang until their context is done. It captures and stops sessions for
+// Sequence's timeout handling; those in absent have no session to stop.
+type hangingStarter struct {
+	mu       sync.Mutex
+	hang     map[string]bool
+	absent   map[string]bool
+	started  []string
+	returned map[string]bool
+	stopped  []string
+	early    []string // Stopped before their Start returned
+}
+
+func (f *hangingStarter) Start(ctx context.Context, a Agent) error {
+	f.mu.Lock()
+	f.started = append(f.started, a.ID)
+	hang := f.hang[a.ID]
+	f.mu.Unlock()
+	if hang {
+		<-ctx.Done()
+		time.Sleep(5 * time.Millisecond) // Still making the session
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.returned == nil {
+		f.returned = make(map[string]bool)
+	}
+	f.returned[a.ID] = true
+	return ctx.Err()
+}
+
+func (f *hangingStarter) Ready(Agent) bool { return false }
+
+func (f *hangingStarter) Capture(a Agent) (string, error) {
+	return "Loading " + a.ID + "...", nil
+}
+
+func (f *hangingStarter) Stop(a Agent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.absent[a.ID] {
+		return ErrNotRunning
+	}
+	if !f.returned[a.ID] {
+		f.early = append(f.early, a.ID)
+	}
+	f.stopped = append(f.stopped, a.ID)
+	return nil
+}
+
+func TestSequenceAgentTimeoutCapturesAndRollsBack(t *testing.T) {
+	for _, rollback := range []bool{false, true} {
+		starter := &hangingStarter{hang: map[string]bool{"mayor": true}}
+		seq := &Sequence{
+			Starter:        starter,
+			PollInterval:   time.Millisecond,
+			ReadyTimeout:   10 * time.Millisecond,
+			RollbackFailed: rollback,
+		}
+		statuses, err := seq.Run([]Agent{NewAgent("deacon", "", "", ""), NewAgent("mayor", "", "", "")})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+
+		// The deacon never comes up; the mayor's start never returns.
+		for _, st := range statuses {
+			if st.Outcome != OutcomeTimedOut || st.Error != "not ready after 10ms" || st.BootTimeout {
+				t.Errorf("rollback=%v: %s = %+v, want timed out on its own timeout", rollback, st.ID, st)
+			}
+			if st.PaneOutput != "Loading "+st.ID+"..." {
+				t.Errorf("rollback=%v: %s pane output = %q", rollback, st.ID, st.PaneOutput)
+			}
+			if st.RolledBack != rollback {
+				t.Errorf("rollback=%v: %s rolled back = %v", rollback, st.ID, st.RolledBack)
+			}
+		}
+		if rollback && len(starter.stopped) != 2 || !rollback && len(starter.stopped) != 0 {
+			t.Errorf("rollback=%v: stopped %v", rollback, starter.stopped)
+		}
+		// The mayor's session is only killed once its Start has returned.
+		if len(starter.early) != 0 {
+			t.Errorf("rollback=%v: stopped %v before their Start returned", rollback, starter.early)
+		}
+	}
+}
+
+func TestSequenceRollbackWithoutSession(t *testing.T) {
+	starter := &hangingStarter{absent: map[string]bool{"deacon": true}}
+	seq := &Sequence{
+		Starter:        starter,
+		PollInterval:   time.Millisecond,
+		ReadyTimeout:   10 * time.Millisecond,
+		RollbackFailed: true,
+	}
+	statuses, err := seq.Run([]Agent{NewAgent("deacon", "", "", "")})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// There was no session to kill, so nothing was rolled back.
+	if st := statuses[0]; st.RolledBack || st.Error != "not ready after 10ms" {
+		t.Errorf("deacon = %+v, want timed out and not rolled back", st)
+	}
+}
+
+func TestSequenceBootTimeout(t *testing.T) {
+	for _, serial := range []bool{false, true} {
+		starter := &hangingStarter{}
+		seq := &Sequence{
+			Starter:      starter,
+			PollInterval: time.Millisecond,
+			ReadyTimeout: time.Hour,
+			Timeout:      20 * time.Millisecond,
+			Serial:       serial,
+		}
+
+		start := time.Now()
+		statuses, err := seq.Run(townPlan())
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("serial=%v: boot took %s, want it stopped at its timeout", serial, elapsed)
+		}
+
+		// The daemon is started first and never comes up; nothing else is
+		// started.
+		if len(starter.started) != 1 || starter.started[0] != "daemon" {
+			t.Errorf("serial=%v: started %v, want only the daemon", serial, starter.started)
+		}
+		for _, st := range statuses {
+			switch {
+			case st.ID == "daemon":
+				if st.Outcome != OutcomeTimedOut || !st.BootTimeout || st.Error != "not ready when the boot timed out" {
+					t.Errorf("serial=%v: daemon = %+v, want cut off by the boot timeout", serial, st)
+				}
+			case st.Outcome != OutcomeNotAttempted || !st.BootTimeout || !st.StartedAt.IsZero():
+				t.Errorf("serial=%v: %s = %+v, want not attempted", serial, st.ID, st)
+			}
+		}
+
+		want := Summary{Failed: 1, TimedOut: 1, NotAttempted: len(statuses) - 1, BootTimedOut: true}
+		if got := Summarize(statuses); got != want {
+			t.Errorf("serial=%v: Summarize() = %+v, want %+v", serial, got, want)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	statuses := []AgentStatus{
+		{ID: "daemon", Ready: true, Outcome: OutcomeReady},
+		{ID: "mayor", Ready: true}, // Saved before outcomes were recorded
+		{ID: "deacon", Outcome: OutcomeFailed, Error: "session failed"},
+		{ID: "alpha/witness", Outcome: OutcomeTimedOut},
+		{ID: "alpha/refinery", Error: "dependency alpha/witness not ready"},
+	}
+	want := Summary{Ready: 2, Failed: 3, TimedOut: 1}
+	if got := Summarize(statuses); got != want {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+}
+
+type funcStarter struct{ start func(Agent) }
+
+func (f *funcStarter) Start(_ context.Context, a Agent) error { f.start(a); return nil }
+func (f *funcStarter) Ready(Agent) bool                       { return true }
+
+func TestSequenceInvalidPlans(t *testing.T) {
+	witness := NewAgent("witness", "alpha", "", "")
+	refinery := NewAgent("refinery", "alpha", "", "")
+	witness.DependsOn = []string{"alpha/refinery"}
+
+	polecat := NewAgent("polecat", "alpha", "Toast", "")
+	earlyWitness := NewAgent("witness", "alpha", "", "")
+	earlyWitness.DependsOn = []string{polecat.ID}
+
+	tests := map[string][]Agent{
+		"cycle":          {witness, refinery},
+		"duplicate":      {refinery, refinery},
+		"later phase":    {earlyWitness, polecat},
+		"invalid phase":  {{ID: "x", Phase: Phase(9)}},
+		"self reference": {{ID: "x", DependsOn: []string{"x"}}},
+	}
+	for name, plan := range tests {
+		starter := newFakeStarter()
+		if _, err := (&Sequence{Starter: starter}).Run(plan); err == nil {
+			t.Errorf("%s: Run() error = nil", name)
+		}
+		if len(starter.order) != 0 {
+			t.Errorf("%s: started %v for an invalid plan", name, starter.order)
+		}
+	}
+}
+
+func TestSequenceMissingDependencyAssumedRunning(t *testing.T) {
+	// No witness in the plan: it is managed elsewhere.
+	starter := newFakeStarter()
+	statuses, err := (&Sequence{Starter: starter, PollInterval: time.Millisecond}).Run([]Agent{NewAgent("polecat", "alpha", "Toast", "")})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !statuses[0].Ready {
+		t.Errorf("status = %+v, want ready", statuses[0])
+	}
+}
+
+func TestNewAgent(t *testing.T) {
+	tests := []struct {
+		role, rig, name string
+		wantID          string
+		wantPhase       Phase
+		wantDeps        string
+	}{
+		{"daemon", "", "", "daemon", PhaseInfrastructure, ""},
+		{"deacon", "", "", "deacon", PhaseTown, "daemon"},
+		{"mayor", "", "", "mayor", PhaseTown, "daemon"},
+		{"witness", "gs", "", "gs/witness", PhaseRig, ""},
+		{"refinery", "gs", "", "gs/refinery", PhaseRig, "gs/witness"},
+		{"polecat", "gs", "Toast", "gs/polecats/Toast", PhaseWorkers, "gs/witness"},
+		{"crew", "gs", "max", "gs/crew/max", PhaseWorkers, ""},
+	}
+	for _, tt := range tests {
+		a := NewAgent(tt.role, tt.rig, tt.name, "")
+		if a.ID != tt.wantID || a.Phase != tt.wantPhase || strings.Join(a.DependsOn, ",") != tt.wantDeps {
+			t.Errorf("NewAgent(%s) = %s %s %v, want %s %s %s", tt.role, a.ID, a.Phase, a.DependsOn, tt.wantID, tt.wantPhase, tt.wantDeps)
+		}
+	}
+}
+
+func TestSequenceStateTransitions(t *testing.T) {
+	starter := &sleepyStarter{delay: time.Millisecond, fail: map[string]bool{"b/witness": true}}
+	changes := make(map[string][]AgentState)
+	seq := &Sequence{
+		Starter:      starter,
+		PollInterval: time.Millisecond,
+		OnChange: func(a Agent, st AgentStatus) {
+			// Not locked: OnChange calls must not overlap (go test -race).
+			changes[a.ID] = append(changes[a.ID], st.State)
+		},
+	}
+
+	statuses, err := seq.Run(fourRigTown())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string][]AgentState{
+		"a/witness":  {StateStarting, StateWaitingReady, StateReady},
+		"b/witness":  {StateStarting, StateFailed},
+		"b/refinery": {StateFailed}, // Its dependency failed, so it was never started
+	}
+	for id, states := range want {
+		if got := changes[id]; !reflect.DeepEqual(got, states) {
+			t.Errorf("%s went through %v, want %v", id, got, states)
+		}
+	}
+	for _, st := range statuses {
+		if len(st.History) != len(changes[st.ID]) || st.State != st.History[len(st.History)-1].State {
+			t.Errorf("%s: state %s, history %+v, want the %d transitions ending in its state", st.ID, st.State, st.History, len(changes[st.ID]))
+			continue
+		}
+		for i := 1; i < len(st.History); i++ {
+			if st.History[i].At.Before(st.History[i-1].At) {
+				t.Errorf("%s: history out of order: %+v", st.ID, st.History)
+			}
+		}
+	}
+}
+
+// flakyStarter fails each agent in failures that many times, then starts
+// it. Agents in refuse can't be refreshed for a retry.
+type flakyStarter struct {
+	mu       sync.Mutex
+	failures map[string]int
+	refuse   map[string]bool
+	started  []string
+	ready    map[string]bool
+}
+
+func (f *flakyStarter) Start(_ context.Context, a Agent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.started = append(f.started, a.ID)
+	if f.failures[a.ID] > 0 {
+		f.failures[a.ID]--
+		return errors.New("bd daemon not ready")
+	}
+	if f.ready == nil {
+		f.ready = make(map[string]bool)
+	}
+	f.ready[a.ID] = true
+	return nil
+}
+
+func (f *flakyStarter) Ready(a Agent) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ready[a.ID]
+}
+
+func (f *flakyStarter) Refresh(a Agent) error {
+	if f.refuse[a.ID] {
+		return errors.New("session busy")
+	}
+	return nil
+}
+
+func TestSequenceRetriesFailedAgents(t *testing.T) {
+	starter := &flakyStarter{failures: map[string]int{
+		"b/witness":      1,
+		"c/polecats/Nux": 2,
+		"d/witness":      5,
+	}}
+	var retries []int
+	seq := &Sequence{
+		Starter:      starter,
+		PollInterval: time.Millisecond,
+		Retries:      2,
+		RetryBackoff: time.Millisecond,
+		BeforeRetry: func(retry int, agents []Agent) {
+			retries = append(retries, retry)
+		},
+	}
+
+	statuses, err := seq.Run(fourRigTown())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if !reflect.DeepEqual(retries, []int{1, 2}) {
+		t.Errorf("BeforeRetry called for retries %v, want [1 2]", retries)
+	}
+
+	byID := make(map[string]AgentStatus)
+	for _, st := range statuses {
+		byID[st.ID] = st
+	}
+	tests := []struct {
+		id    string
+		ready bool
+		retry int
+	}{
+		{"a/witness", true, 0},
+		{"b/witness", true, 1},
+		{"b/refinery", true, 1}, // Its witness failed the first pass
+		{"b/polecats/Toast", true, 1},
+		{"c/polecats/Nux", true, 2},
+		{"d/witness", false, 2},
+		{"d/refinery", false, 2},
+	}
+	for _, tt := range tests {
+		st := byID[tt.id]
+		if st.Ready != tt.ready || st.Retry != tt.retry {
+			t.Errorf("%s: ready %v on retry %d (%s), want ready %v on retry %d", tt.id, st.Ready, st.Retry, st.Error, tt.ready, tt.retry)
+		}
+	}
+	if got := byID["d/refinery"].Error; got != "dependency d/witness not ready" {
+		t.Errorf("d/refinery error = %q, want its dependency", got)
+	}
+
+	// The retry of rig b still started the witness before its refinery.
+	last := func(id string) int {
+		n := -1
+		for i, started := range starter.started {
+			if started == id {
+				n = i
+			}
+		}
+		return n
+	}
+	if last("b/witness") > last("b/refinery") {
+		t.Errorf("retried b/refinery before b/witness: %v", starter.started)
+	}
+
+	sum := Summarize(statuses)
+	if sum.Retried != 5 || sum.Failed != 4 {
+		t.Errorf("Summarize() = %+v, want 5 ready on retry and d's 4 agents failed", sum)
+	}
+}
+
+func TestSequenceRetrySkipsUnrefreshable(t *testing.T) {
+	starter := &flakyStarter{
+		failures: map[string]int{"deacon": 1},
+		refuse:   map[string]bool{"deacon": true},
+	}
+	retried := 0
+	seq := &Sequence{
+		Starter:      starter,
+		PollInterval: time.Millisecond,
+		Retries:      3,
+		RetryBackoff: time.Hour,
+		BeforeRetry:  func(int, []Agent) { retried++ },
+	}
+
+	statuses, err := seq.Run([]Agent{NewAgent("daemon", "", "", ""), NewAgent("deacon", "", "", "")})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	// With nothing left to retry, Run doesn't wait out the backoff.
+	if retried != 0 {
+		t.Errorf("BeforeRetry called %d time(s), want none", retried)
+	}
+	st := statuses[1]
+	if st.Ready || st.Retry != 0 || strings.Count(st.Error, "not retried: session busy") != 1 {
+		t.Errorf("deacon = %+v, want its first failure, not retried, once", st)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/KeithWyatt/gongshow/internal/util"
 )
 
 //go:embed config/*.json
@@ -73,8 +75,9 @@ func EnsureSettingsAt(workDir string, roleType RoleType, settingsDir, settingsFi
 		return fmt.Errorf("reading template %s: %w", templateName, err)
 	}
 
-	// Write settings file
-	if err := os.WriteFile(settingsPath, content, 0600); err != nil {
+	// Write settings file. Agents sharing a settings directory (crew,
+	// polecats) may be started at once, so write it whole or not at all.
+	if err := util.AtomicWriteFileUnique(settingsPath, content, 0600); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
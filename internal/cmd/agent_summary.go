@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var agentSetSummaryCmd = &cobra.Command{
+	Use:   "set-summary <address> <text>",
+	Short: "Set the one-line summary of what an agent is working on",
+	Long: `Set the work summary on an agent's bead: one line, for humans, on what
+the agent is doing right now. 'gt status' shows it next to the agent.
+
+The summary is at most 256 characters and can't contain newlines. Pass
+an empty text to clear it.
+
+ADDRESSES:
+  mayor, deacon
+  <rig>/witness, <rig>/refinery
+  <rig>/crew/<name>
+  <rig>/polecats/<name> (or <rig>/<name>)
+
+EXAMPLES:
+  gt agent set-summary gongshow/Toast "Fixing the flaky merge queue test"
+  gt agent set-summary mayor "Planning the v2 convoy"
+  gt agent set-summary gongshow/crew/max ""`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAgentSetSummary,
+}
+
+func init() {
+	agentsCmd.AddCommand(agentSetSummaryCmd)
+}
+
+func runAgentSetSummary(cmd *cobra.Command, args []string) error {
+	address, summary := args[0], strings.TrimSpace(args[1])
+	if err := beads.ValidateAgentFields(&beads.AgentFields{WorkSummary: summary}); err != nil {
+		return err
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	agentBeadID := agentIDToBeadID(normalizeAgentAddress(address), townRoot)
+	if agentBeadID == "" {
+		return fmt.Errorf("unrecognized agent address %q", address)
+	}
+
+	bd := beads.New(townRoot)
+	_, fields, err := bd.GetAgentBead(agentBeadID)
+	if err != nil {
+		return fmt.Errorf("getting agent bead %s: %w", agentBeadID, err)
+	}
+	if fields == nil {
+		return fmt.Errorf("agent bead %s not found", agentBeadID)
+	}
+	previous := fields.WorkSummary
+
+	if err := bd.UpdateAgentWorkSummary(agentBeadID, summary); err != nil {
+		return fmt.Errorf("updating work summary: %w", err)
+	}
+	_ = events.LogFeed(events.TypeBeadTransition, detectSender(),
+		events.BeadTransitionPayload(agentBeadID, previous, summary, "work summary"))
+
+	if summary == "" {
+		fmt.Printf("%s Cleared work summary for %s\n", style.Bold.Render("✓"), address)
+	} else {
+		fmt.Printf("%s Work summary for %s: %s\n", style.Bold.Render("✓"), address, summary)
+	}
+	return nil
+}
+
+// normalizeAgentAddress turns the short polecat address <rig>/<name> into
+// <rig>/polecats/<name> and drops the trailing slash of mayor/ and
+// deacon/, leaving other addresses as they are.
+func normalizeAgentAddress(address string) string {
+	address = strings.TrimSuffix(address, "/")
+	parts := strings.Split(address, "/")
+	if len(parts) == 2 && parts[1] != "witness" && parts[1] != "refinery" {
+		return parts[0] + "/polecats/" + parts[1]
+	}
+	return address
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeAgentAddress(t *testing.T) {
+	tests := map[string]string{
+		"mayor":                 "mayor",
+		"mayor/":                "mayor",
+		"gongshow/witness":      "gongshow/witness",
+		"gongshow/refinery":     "gongshow/refinery",
+		"gongshow/Toast":        "gongshow/polecats/Toast",
+		"gongshow/polecats/Nux": "gongshow/polecats/Nux",
+		"gongshow/crew/max":     "gongshow/crew/max",
+	}
+	for address, want := range tests {
+		if got := normalizeAgentAddress(address); got != want {
+			t.Errorf("normalizeAgentAddress(%q) = %q, want %q", address, got, want)
+		}
+	}
+}
+
+func TestWorkSummarySuffix(t *testing.T) {
+	if got := workSummarySuffix(AgentRuntime{Name: "Toast"}, 40); got != "" {
+		t.Errorf("workSummarySuffix without a summary = %q, want empty", got)
+	}
+
+	got := workSummarySuffix(AgentRuntime{Name: "Toast", WorkSummary: "Fixing the flaky merge queue test in the refinery"}, 20)
+	if !strings.Contains(got, `"Fixing the flaky ..."`) {
+		t.Errorf("workSummarySuffix = %q, want the summary quoted and cut to 20", got)
+	}
+}
@@ -60,7 +60,7 @@ var AgentTypeIcons = map[AgentType]string{
 
 var agentsCmd = &cobra.Command{
 	Use:     "agents",
-	Aliases: []string{"ag"},
+	Aliases: []string{"ag", "agent"},
 	GroupID: GroupAgents,
 	Short:   "Switch between GongShow agent sessions",
 	Long: `Display a popup menu of core GongShow agent sessions.
@@ -272,6 +272,23 @@ func (a *AgentSession) displayLabel() string {
 	return a.Name
 }
 
+// address returns the agent's address, as gt nudge and gt mail take it.
+func (a *AgentSession) address() string {
+	switch a.Type {
+	case AgentMayor:
+		return "mayor"
+	case AgentDeacon:
+		return "deacon"
+	case AgentWitness:
+		return a.Rig + "/witness"
+	case AgentRefinery:
+		return a.Rig + "/refinery"
+	case AgentCrew:
+		return a.Rig + "/crew/" + a.AgentName
+	}
+	return a.Rig + "/" + a.AgentName
+}
+
 // shortcutKey returns a keyboard shortcut for the menu item.
 func shortcutKey(index int) string {
 	if index < 9 {
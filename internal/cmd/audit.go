@@ -412,8 +412,7 @@ func formatTownlogSummary(e townlog.Event) string {
 func collectFeedEvents(townRoot, actor string, since time.Time) ([]AuditEntry, error) {
 	var entries []AuditEntry
 
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-	file, err := os.Open(eventsPath)
+	file, err := events.OpenLog(townRoot)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil // No events file yet
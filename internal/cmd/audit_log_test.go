@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+func newTestAuditLog(t *testing.T) *AuditLog {
+	t.Helper()
+	t.Setenv(AuditHMACKeyEnv, "test-key")
+	al, err := NewAuditLog(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewAuditLog: %v", err)
+	}
+	return al
+}
+
+func TestAuditLogRequiresKey(t *testing.T) {
+	t.Setenv(AuditHMACKeyEnv, "")
+	if _, err := NewAuditLog(t.TempDir()); !errors.Is(err, ErrAuditKeyNotSet) {
+		t.Errorf("expected ErrAuditKeyNotSet, got %v", err)
+	}
+}
+
+func TestAuditLogRecordAndVerify(t *testing.T) {
+	al := newTestAuditLog(t)
+
+	if err := al.Record("mayor", "rig.add", "gongshow", map[string]interface{}{"url": "git@example.com:x.git"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := al.Record("gongshow/witness", "polecat.nuke", "gongshow/Toast", nil); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	valid, invalid, err := al.Verify("")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if valid != 2 || invalid != 0 {
+		t.Errorf("Verify = (%d valid, %d invalid), want (2, 0)", valid, invalid)
+	}
+}
+
+func TestAuditLogDetectsTampering(t *testing.T) {
+	al := newTestAuditLog(t)
+	for _, target := range []string{"gt-1", "gt-2", "gt-3"} {
+		if err := al.Record("mayor", "bead.close", target, nil); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(al.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(data), `"actor":"mayor","action":"bead.close","target":"gt-2"`,
+		`"actor":"deacon","action":"bead.close","target":"gt-2"`, 1)
+	if tampered == string(data) {
+		t.Fatal("test setup: could not find entry to tamper with")
+	}
+	tampered += "not json\n"
+	if err := os.WriteFile(al.Path(), []byte(tampered), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	valid, invalid, err := al.Verify("")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if valid != 2 || invalid != 2 {
+		t.Errorf("Verify = (%d valid, %d invalid), want (2, 2)", valid, invalid)
+	}
+}
+
+func TestAuditLogWrongKey(t *testing.T) {
+	al := newTestAuditLog(t)
+	if err := al.Record("mayor", "config.set", "escalation", nil); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	other := &AuditLog{path: al.Path(), key: []byte("different-key")}
+	valid, invalid, err := other.Verify("")
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if valid != 0 || invalid != 1 {
+		t.Errorf("Verify with wrong key = (%d valid, %d invalid), want (0, 1)", valid, invalid)
+	}
+}
+
+func TestDestructiveEventsAreAudited(t *testing.T) {
+	t.Setenv(AuditHMACKeyEnv, "test-key")
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	if err := events.LogAudit(events.TypeDoctorFix, "gt doctor", events.DoctorFixPayload("stale-locks", nil)); err != nil {
+		t.Fatalf("LogAudit: %v", err)
+	}
+	// Not destructive, so only in the events audit log.
+	if err := events.LogAudit(events.TypeMigration, "gt", events.MigrationPayload("agent-fields", "v1", "v2", 1)); err != nil {
+		t.Fatalf("LogAudit: %v", err)
+	}
+
+	al, err := NewAuditLog(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(al.Path())
+	if err != nil {
+		t.Fatalf("reading compliance log: %v", err)
+	}
+	if !strings.Contains(string(data), `"action":"doctor_fix"`) || strings.Contains(string(data), "migration") {
+		t.Errorf("compliance log = %s, want just the doctor fix", data)
+	}
+	if valid, invalid, err := al.Verify(""); err != nil || valid != 1 || invalid != 0 {
+		t.Errorf("Verify = (%d valid, %d invalid, %v), want (1, 0, nil)", valid, invalid, err)
+	}
+}
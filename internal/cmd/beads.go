@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/style"
+)
+
+// Beads command flags
+var (
+	beadsGCDryRun bool
+	beadsGCJSON   bool
+
+	beadsSearchLimit int
+	beadsSearchJSON  bool
+
+	beadsVerifyAll    bool
+	beadsVerifyRecord bool
+
+	beadsStatsJSON bool
+)
+
+var beadsCmd = &cobra.Command{
+	Use:     "beads",
+	GroupID: GroupWork,
+	Short:   "Bead store maintenance",
+	Long: `Maintenance operations on the beads store for the current directory.
+
+These commands complement bd with GongShow-specific housekeeping.`,
+	RunE: requireSubcommand,
+}
+
+var beadsGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove orphaned delegations",
+	Long: `Garbage-collect delegations whose parent or child bead no longer exists.
+
+When a parent bead is deleted, delegations pointing at it are left behind on
+their child beads. This command reads every delegation from the store's
+issues.jsonl, checks both ends against bd in a single lookup, and removes
+the ones with a missing parent or child. One bead_transition event is
+recorded per removed delegation.
+
+The daemon runs this automatically once the store holds more than
+1000 delegations.
+
+Examples:
+  gt beads gc              # Remove orphaned delegations
+  gt beads gc --dry-run    # Show what would be removed
+  gt beads gc --json       # Machine-readable output`,
+	RunE: runBeadsGC,
+}
+
+var beadsSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find beads by partial title or description",
+	Long: `Search bead titles and descriptions for any of the words in the query.
+
+Matching is case-insensitive and by substring, so "auth" finds "OAuth" and
+"authentication". Words found in the title rank above words found in the
+description. Each search reads the current beads; nothing is cached
+between runs.
+
+Examples:
+  gt beads search auth
+  gt beads search refinery stuck --limit 5
+  gt beads search token --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBeadsSearch,
+}
+
+var beadsVerifyCmd = &cobra.Command{
+	Use:   "verify [bead-id...]",
+	Short: "Check beads for on-disk corruption",
+	Long: `Check beads in the store's issues.jsonl against their recorded checksums.
+
+Beads on network filesystems or after disk errors can be silently
+corrupted. gt keeps a SHA-256 checksum of each bead it writes in
+.beads/checksums.json, taken when its bd command finishes. A bead that no
+longer matches with the same updated_at, or a line that no longer parses,
+is corrupt. A bead with a new updated_at was changed by running bd
+directly: it is reported as modified and its checksum is taken again.
+Beads gt hasn't written are skipped.
+
+--record takes the checksums of the given beads (or with --all, every
+bead) as they are now, for beads known to be good.
+
+Exits 1 if any corruption is found.
+
+Examples:
+  gt beads verify --all
+  gt beads verify gt-abc12 gt-def34
+  gt beads verify --all --record`,
+	RunE: runBeadsVerify,
+}
+
+var beadsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show bead store health and usage",
+	Long: `Show how big the bead store is and what is in it.
+
+Reads .beads/issues.jsonl directly and reports the number of beads, broken
+down by state (an agent bead's agent_state, otherwise its status) and by
+type (agent, escalation, merge request, delegation), the oldest bead, the
+largest bead and file, and the total size of the .beads directory.
+
+Examples:
+  gt beads stats
+  gt beads stats --json`,
+	Args: cobra.NoArgs,
+	RunE: runBeadsStats,
+}
+
+func init() {
+	beadsGCCmd.Flags().BoolVarP(&beadsGCDryRun, "dry-run", "n", false, "Show orphaned delegations without removing them")
+	beadsGCCmd.Flags().BoolVar(&beadsGCJSON, "json", false, "Output as JSON")
+
+	beadsSearchCmd.Flags().IntVar(&beadsSearchLimit, "limit", 20, "Maximum number of results (0 for all)")
+	beadsSearchCmd.Flags().BoolVar(&beadsSearchJSON, "json", false, "Output as JSON")
+
+	beadsVerifyCmd.Flags().BoolVar(&beadsVerifyAll, "all", false, "Verify every bead in the store")
+	beadsVerifyCmd.Flags().BoolVar(&beadsVerifyRecord, "record", false, "Record the beads' checksums as they are now instead of verifying")
+
+	beadsStatsCmd.Flags().BoolVar(&beadsStatsJSON, "json", false, "Output as JSON")
+
+	beadsCmd.AddCommand(beadsGCCmd)
+	beadsCmd.AddCommand(beadsSearchCmd)
+	beadsCmd.AddCommand(beadsVerifyCmd)
+	beadsCmd.AddCommand(beadsStatsCmd)
+	rootCmd.AddCommand(beadsCmd)
+}
+
+func runBeadsGC(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	bd := beads.New(cwd)
+	result, err := bd.GarbageCollectWithOptions(beads.GCOptions{
+		DryRun: beadsGCDryRun,
+		Actor:  detectActor(),
+	})
+	if err != nil {
+		return fmt.Errorf("garbage collection failed: %w", err)
+	}
+
+	if beadsGCJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	if len(result.Orphaned) == 0 {
+		fmt.Printf("%s No orphaned delegations (%d scanned)\n", style.SuccessPrefix, result.Scanned)
+		return nil
+	}
+
+	for _, d := range result.Orphaned {
+		fmt.Printf("  %s → %s %s\n", d.Parent, d.Child, style.Dim.Render("("+d.DelegatedBy+" → "+d.DelegatedTo+")"))
+	}
+	fmt.Println()
+
+	if beadsGCDryRun {
+		fmt.Printf("%s Would remove %d orphaned delegation(s) (%d scanned)\n",
+			style.WarningPrefix, len(result.Orphaned), result.Scanned)
+		return nil
+	}
+
+	for _, w := range result.Warnings {
+		style.PrintWarning("%s", w)
+	}
+	fmt.Printf("%s Removed %d orphaned delegation(s) (%d scanned)\n",
+		style.SuccessPrefix, result.Collected, result.Scanned)
+	return nil
+}
+
+func runBeadsSearch(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	query := strings.Join(args, " ")
+	results, err := beads.New(cwd).FuzzySearch(query, beadsSearchLimit)
+	if err != nil {
+		return fmt.Errorf("searching beads: %w", err)
+	}
+
+	if beadsSearchJSON {
+		if results == nil {
+			results = []*beads.Issue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No beads match %q\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tTITLE")
+	for _, issue := range results {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", issue.ID, issue.Status, issue.Title)
+	}
+	return w.Flush()
+}
+
+func runBeadsVerify(cmd *cobra.Command, args []string) error {
+	if beadsVerifyAll == (len(args) > 0) {
+		return fmt.Errorf("give bead IDs or --all")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	bd := beads.New(cwd)
+
+	if beadsVerifyRecord {
+		// With --all, args is empty, which records every bead.
+		if err := bd.RecordChecksums(args...); err != nil {
+			return fmt.Errorf("recording checksums: %w", err)
+		}
+		fmt.Printf("%s Recorded checksums\n", style.SuccessPrefix)
+		return nil
+	}
+
+	if beadsVerifyAll {
+		valid, modified, corrupt, err := bd.VerifyAll()
+		if err != nil {
+			return fmt.Errorf("verifying beads: %w", err)
+		}
+		if modified > 0 {
+			fmt.Printf("%s %d bead(s) modified outside gt; checksums taken again\n", style.WarningPrefix, modified)
+		}
+		if corrupt > 0 {
+			fmt.Printf("%s %d corrupt bead(s), %d valid\n", style.ErrorPrefix, corrupt, valid)
+			fmt.Println("  Compare .beads/issues.jsonl with git ('git diff') and restore it from git or a backup.")
+			return NewSilentExit(1)
+		}
+		fmt.Printf("%s %d bead(s) verified\n", style.SuccessPrefix, valid)
+		return nil
+	}
+
+	failed := 0
+	for _, id := range args {
+		if err := bd.VerifyIntegrity(id); errors.Is(err, beads.ErrNoChecksum) || errors.Is(err, beads.ErrModified) {
+			fmt.Printf("%s %v\n", style.WarningPrefix, err)
+			continue
+		} else if err != nil {
+			fmt.Printf("%s %v\n", style.ErrorPrefix, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s %s\n", style.SuccessPrefix, id)
+	}
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func runBeadsStats(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	stats, err := beads.BeadStats(beads.New(cwd).Store())
+	if err != nil {
+		return fmt.Errorf("reading bead stats: %w", err)
+	}
+
+	if beadsStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	fmt.Printf("%s %d bead(s), %s on disk\n", style.Bold.Render("Beads:"), stats.Total, formatBeadBytes(stats.StoreBytes))
+	if stats.Unreadable > 0 {
+		fmt.Printf("%s %d line(s) of issues.jsonl don't parse; run 'gt beads verify --all'\n", style.WarningPrefix, stats.Unreadable)
+	}
+	if stats.Total == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("By state:"))
+	printBeadCounts(stats.ByState)
+	fmt.Printf("\n%s\n", style.Bold.Render("By type:"))
+	printBeadCounts(stats.ByType)
+
+	fmt.Println()
+	if stats.Oldest != nil {
+		fmt.Printf("Oldest bead:  %s %s\n", stats.Oldest.ID, style.Dim.Render("(created "+stats.Oldest.CreatedAt+")"))
+	}
+	if stats.LargestBead != nil {
+		fmt.Printf("Largest bead: %s %s\n", stats.LargestBead.ID, style.Dim.Render("("+formatBeadBytes(int64(stats.LargestBead.Bytes))+")"))
+	}
+	if stats.LargestFile != nil {
+		fmt.Printf("Largest file: %s %s\n", stats.LargestFile.Path, style.Dim.Render("("+formatBeadBytes(stats.LargestFile.Bytes)+")"))
+	}
+	return nil
+}
+
+// printBeadCounts prints counts, largest first.
+func printBeadCounts(counts map[string]int) {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for _, k := range keys {
+		fmt.Printf("  %-12s %d\n", k, counts[k])
+	}
+}
+
+func formatBeadBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/style"
+)
+
+var (
+	beadsBulkFile        string
+	beadsBulkStopOnError bool
+)
+
+var beadsBulkCmd = &cobra.Command{
+	Use:   "bulk --file <operations.json>",
+	Short: "Create, update or delete many beads from a JSON file",
+	Long: `Apply a batch of bead operations from a JSON file, e.g. when migrating
+work from an external tracker.
+
+The file holds an array of operations, applied in order:
+
+  [
+    {"op": "create", "fields": {"title": "Port login page", "type": "task", "priority": 1,
+                                "labels": ["migrated"]}},
+    {"op": "create", "id": "gt-legacy-42", "fields": {"title": "Fix flaky test"}},
+    {"op": "update", "id": "gt-abc", "fields": {"status": "in_progress", "assignee": "gongshow/crew/joe"}},
+    {"op": "delete", "id": "gt-old"}
+  ]
+
+Fields: title, type, priority, description, parent (create only), status,
+assignee, labels (added), remove_labels.
+
+A failing operation is reported and the rest still run; use --stop-on-error
+to stop at the first failure. The command fails if any operation failed.
+
+Examples:
+  gt beads bulk --file operations.json
+  gt beads bulk --file operations.json --stop-on-error`,
+	RunE: runBeadsBulk,
+}
+
+func init() {
+	beadsBulkCmd.Flags().StringVarP(&beadsBulkFile, "file", "f", "", "JSON file with the operations (required)")
+	beadsBulkCmd.Flags().BoolVar(&beadsBulkStopOnError, "stop-on-error", false, "Stop at the first failed operation")
+	_ = beadsBulkCmd.MarkFlagRequired("file")
+
+	beadsCmd.AddCommand(beadsBulkCmd)
+}
+
+// bulkOperation is one entry in a bulk operations file.
+type bulkOperation struct {
+	Op     string          `json:"op"` // create, update, delete
+	ID     string          `json:"id,omitempty"`
+	Fields json.RawMessage `json:"fields,omitempty"`
+}
+
+// bulkFields are the bead fields an operation may set.
+type bulkFields struct {
+	Title        *string  `json:"title,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Priority     *int     `json:"priority,omitempty"`
+	Description  *string  `json:"description,omitempty"`
+	Parent       string   `json:"parent,omitempty"`
+	Status       *string  `json:"status,omitempty"`
+	Assignee     *string  `json:"assignee,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	RemoveLabels []string `json:"remove_labels,omitempty"`
+}
+
+// bulkStore is the subset of *beads.Beads used by bulk operations.
+type bulkStore interface {
+	Create(opts beads.CreateOptions) (*beads.Issue, error)
+	CreateWithID(id string, opts beads.CreateOptions) (*beads.Issue, error)
+	Update(id string, opts beads.UpdateOptions) error
+	Delete(ids ...string) error
+}
+
+// bulkError records a failed operation.
+type bulkError struct {
+	Index int // 1-based position in the file
+	Op    string
+	ID    string
+	Err   error
+}
+
+// bulkSummary is the outcome of a bulk run.
+type bulkSummary struct {
+	Total   int
+	Created int
+	Updated int
+	Deleted int
+	Skipped int // not attempted after --stop-on-error
+	Errors  []bulkError
+}
+
+func runBeadsBulk(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(beadsBulkFile)
+	if err != nil {
+		return fmt.Errorf("reading operations file: %w", err)
+	}
+	ops, err := parseBulkOperations(data)
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+
+	summary := applyBulkOperations(beads.New(cwd), ops, beadsBulkStopOnError, os.Stdout)
+	printBulkSummary(os.Stdout, summary)
+	if len(summary.Errors) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// parseBulkOperations decodes and checks an operations file up front, so a
+// malformed file fails before any bead is touched.
+func parseBulkOperations(data []byte) ([]bulkOperation, error) {
+	var ops []bulkOperation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("parsing operations file: %w", err)
+	}
+	for i, op := range ops {
+		switch op.Op {
+		case "create":
+		case "update", "delete":
+			if op.ID == "" {
+				return nil, fmt.Errorf("operation %d: %s requires an id", i+1, op.Op)
+			}
+		default:
+			return nil, fmt.Errorf("operation %d: unknown op %q (want create, update or delete)", i+1, op.Op)
+		}
+		if _, err := op.fields(); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i+1, err)
+		}
+	}
+	return ops, nil
+}
+
+// fields decodes the operation's fields, rejecting unknown ones.
+func (op bulkOperation) fields() (*bulkFields, error) {
+	f := &bulkFields{}
+	if len(op.Fields) == 0 {
+		return f, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(op.Fields))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(f); err != nil {
+		return nil, fmt.Errorf("invalid fields: %w", err)
+	}
+	return f, nil
+}
+
+// updateOptions converts fields to update options; ok is false if there is
+// nothing to update.
+func (f *bulkFields) updateOptions() (opts beads.UpdateOptions, ok bool) {
+	opts = beads.UpdateOptions{
+		Title:        f.Title,
+		Status:       f.Status,
+		Priority:     f.Priority,
+		Description:  f.Description,
+		Assignee:     f.Assignee,
+		AddLabels:    f.Labels,
+		RemoveLabels: f.RemoveLabels,
+	}
+	ok = opts.Title != nil || opts.Status != nil || opts.Priority != nil || opts.Description != nil ||
+		opts.Assignee != nil || len(opts.AddLabels) > 0 || len(opts.RemoveLabels) > 0
+	return opts, ok
+}
+
+// applyBulkOperations runs ops one at a time, in order, printing a progress
+// line per operation to w.
+func applyBulkOperations(store bulkStore, ops []bulkOperation, stopOnError bool, w io.Writer) *bulkSummary {
+	summary := &bulkSummary{Total: len(ops)}
+	for i, op := range ops {
+		id, err := applyBulkOperation(store, op)
+		progress := fmt.Sprintf("[%d/%d]", i+1, len(ops))
+		if err != nil {
+			summary.Errors = append(summary.Errors, bulkError{Index: i + 1, Op: op.Op, ID: op.ID, Err: err})
+			fmt.Fprintf(w, "%s %s %s %s: %v\n", style.Dim.Render(progress), style.WarningPrefix, op.Op, op.ID, err)
+			if stopOnError {
+				summary.Skipped = len(ops) - i - 1
+				break
+			}
+			continue
+		}
+		switch op.Op {
+		case "create":
+			summary.Created++
+		case "update":
+			summary.Updated++
+		case "delete":
+			summary.Deleted++
+		}
+		fmt.Fprintf(w, "%s %s %s\n", style.Dim.Render(progress), op.Op, id)
+	}
+	return summary
+}
+
+// applyBulkOperation runs one operation and returns the bead ID it touched.
+func applyBulkOperation(store bulkStore, op bulkOperation) (string, error) {
+	f, err := op.fields()
+	if err != nil {
+		return op.ID, err
+	}
+
+	switch op.Op {
+	case "create":
+		if f.Title == nil || *f.Title == "" {
+			return op.ID, fmt.Errorf("create requires a title")
+		}
+		opts := beads.CreateOptions{Title: *f.Title, Type: f.Type, Priority: -1, Parent: f.Parent}
+		if f.Priority != nil {
+			opts.Priority = *f.Priority
+		}
+		if f.Description != nil {
+			opts.Description = *f.Description
+		}
+		var issue *beads.Issue
+		if op.ID != "" {
+			issue, err = store.CreateWithID(op.ID, opts)
+		} else {
+			issue, err = store.Create(opts)
+		}
+		if err != nil {
+			return op.ID, err
+		}
+
+		// Fields bd create doesn't take are applied with a follow-up update
+		rest := bulkFields{Status: f.Status, Assignee: f.Assignee, Labels: f.Labels}
+		if update, ok := rest.updateOptions(); ok {
+			if err := store.Update(issue.ID, update); err != nil {
+				return issue.ID, fmt.Errorf("created, but setting fields failed: %w", err)
+			}
+		}
+		return issue.ID, nil
+
+	case "update":
+		if f.Parent != "" || f.Type != "" {
+			return op.ID, fmt.Errorf("parent and type can only be set on create")
+		}
+		update, ok := f.updateOptions()
+		if !ok {
+			return op.ID, fmt.Errorf("update has no fields")
+		}
+		return op.ID, store.Update(op.ID, update)
+
+	case "delete":
+		return op.ID, store.Delete(op.ID)
+	}
+	return op.ID, fmt.Errorf("unknown op %q", op.Op)
+}
+
+func printBulkSummary(w io.Writer, s *bulkSummary) {
+	fmt.Fprintln(w)
+	status := style.SuccessPrefix
+	if len(s.Errors) > 0 {
+		status = style.WarningPrefix
+	}
+	fmt.Fprintf(w, "%s %d operation(s): %d created, %d updated, %d deleted, %d failed",
+		status, s.Total, s.Created, s.Updated, s.Deleted, len(s.Errors))
+	if s.Skipped > 0 {
+		fmt.Fprintf(w, ", %d skipped", s.Skipped)
+	}
+	fmt.Fprintln(w)
+	for _, e := range s.Errors {
+		fmt.Fprintf(w, "  #%d %s %s: %v\n", e.Index, e.Op, e.ID, e.Err)
+	}
+}
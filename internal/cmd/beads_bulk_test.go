@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+)
+
+// fakeBulkStore records the calls made by bulk operations.
+type fakeBulkStore struct {
+	calls   []string
+	updates map[string]beads.UpdateOptions
+	failOn  map[string]bool // bead IDs whose operations fail
+	nextID  int
+}
+
+func newFakeBulkStore() *fakeBulkStore {
+	return &fakeBulkStore{updates: make(map[string]beads.UpdateOptions), failOn: make(map[string]bool)}
+}
+
+func (s *fakeBulkStore) Create(opts beads.CreateOptions) (*beads.Issue, error) {
+	s.nextID++
+	return s.CreateWithID(fmt.Sprintf("gt-new%d", s.nextID), opts)
+}
+
+func (s *fakeBulkStore) CreateWithID(id string, opts beads.CreateOptions) (*beads.Issue, error) {
+	if s.failOn[id] {
+		return nil, fmt.Errorf("create %s failed", id)
+	}
+	s.calls = append(s.calls, fmt.Sprintf("create %s %q type=%s p=%d", id, opts.Title, opts.Type, opts.Priority))
+	return &beads.Issue{ID: id, Title: opts.Title}, nil
+}
+
+func (s *fakeBulkStore) Update(id string, opts beads.UpdateOptions) error {
+	if s.failOn[id] {
+		return fmt.Errorf("update %s failed", id)
+	}
+	s.calls = append(s.calls, "update "+id)
+	s.updates[id] = opts
+	return nil
+}
+
+func (s *fakeBulkStore) Delete(ids ...string) error {
+	for _, id := range ids {
+		if s.failOn[id] {
+			return fmt.Errorf("delete %s failed", id)
+		}
+	}
+	s.calls = append(s.calls, "delete "+strings.Join(ids, " "))
+	return nil
+}
+
+const bulkOperationsFixture = `[
+  {"op": "create", "fields": {"title": "Port login page", "type": "task", "priority": 1, "labels": ["migrated"]}},
+  {"op": "create", "id": "gt-legacy-42", "fields": {"title": "Fix flaky test"}},
+  {"op": "update", "id": "gt-abc", "fields": {"status": "in_progress", "assignee": "gongshow/crew/joe"}},
+  {"op": "delete", "id": "gt-old"}
+]`
+
+func loadBulkFixture(t *testing.T, content string) []bulkOperation {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "operations.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops, err := parseBulkOperations(data)
+	if err != nil {
+		t.Fatalf("parseBulkOperations: %v", err)
+	}
+	return ops
+}
+
+func TestApplyBulkOperations(t *testing.T) {
+	ops := loadBulkFixture(t, bulkOperationsFixture)
+	store := newFakeBulkStore()
+	var out bytes.Buffer
+
+	summary := applyBulkOperations(store, ops, false, &out)
+
+	want := []string{
+		`create gt-new1 "Port login page" type=task p=1`,
+		"update gt-new1", // labels applied after create
+		`create gt-legacy-42 "Fix flaky test" type= p=-1`,
+		"update gt-abc",
+		"delete gt-old",
+	}
+	if !reflect.DeepEqual(store.calls, want) {
+		t.Errorf("calls = %q, want %q", store.calls, want)
+	}
+	if got := store.updates["gt-new1"].AddLabels; !reflect.DeepEqual(got, []string{"migrated"}) {
+		t.Errorf("labels after create = %v", got)
+	}
+	if u := store.updates["gt-abc"]; u.Status == nil || *u.Status != "in_progress" || u.Assignee == nil || *u.Assignee != "gongshow/crew/joe" {
+		t.Errorf("update options = %+v", u)
+	}
+	if summary.Created != 2 || summary.Updated != 1 || summary.Deleted != 1 || len(summary.Errors) != 0 {
+		t.Errorf("summary = %+v", summary)
+	}
+	for _, progress := range []string{"[1/4]", "[4/4]"} {
+		if !strings.Contains(out.String(), progress) {
+			t.Errorf("output missing %s:\n%s", progress, out.String())
+		}
+	}
+}
+
+func TestApplyBulkOperations_ContinuesOnError(t *testing.T) {
+	ops := loadBulkFixture(t, bulkOperationsFixture)
+	store := newFakeBulkStore()
+	store.failOn["gt-legacy-42"] = true
+	store.failOn["gt-abc"] = true
+
+	summary := applyBulkOperations(store, ops, false, &bytes.Buffer{})
+
+	if summary.Created != 1 || summary.Updated != 0 || summary.Deleted != 1 || summary.Skipped != 0 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if len(summary.Errors) != 2 || summary.Errors[0].Index != 2 || summary.Errors[1].Index != 3 {
+		t.Fatalf("errors = %+v", summary.Errors)
+	}
+
+	var out bytes.Buffer
+	printBulkSummary(&out, summary)
+	if !strings.Contains(out.String(), "2 failed") || !strings.Contains(out.String(), "#3 update gt-abc") {
+		t.Errorf("summary output:\n%s", out.String())
+	}
+}
+
+func TestApplyBulkOperations_StopOnError(t *testing.T) {
+	ops := loadBulkFixture(t, bulkOperationsFixture)
+	store := newFakeBulkStore()
+	store.failOn["gt-legacy-42"] = true
+
+	summary := applyBulkOperations(store, ops, true, &bytes.Buffer{})
+
+	if summary.Created != 1 || len(summary.Errors) != 1 || summary.Skipped != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+	for _, call := range store.calls {
+		if strings.HasPrefix(call, "delete") {
+			t.Errorf("operation ran after stop: %s", call)
+		}
+	}
+}
+
+func TestParseBulkOperations_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"not an array", `{"op": "create"}`, "parsing operations file"},
+		{"unknown op", `[{"op": "merge", "id": "gt-a"}]`, `unknown op "merge"`},
+		{"update without id", `[{"op": "update", "fields": {"title": "x"}}]`, "requires an id"},
+		{"delete without id", `[{"op": "delete"}]`, "requires an id"},
+		{"unknown field", `[{"op": "create", "fields": {"title": "x", "colour": "red"}}]`, "colour"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseBulkOperations([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyBulkOperation_FieldErrors(t *testing.T) {
+	ops := loadBulkFixture(t, `[
+  {"op": "create", "fields": {"type": "task"}},
+  {"op": "update", "id": "gt-a"},
+  {"op": "update", "id": "gt-a", "fields": {"parent": "gt-epic"}}
+]`)
+	summary := applyBulkOperations(newFakeBulkStore(), ops, false, &bytes.Buffer{})
+	if len(summary.Errors) != 3 {
+		t.Fatalf("errors = %+v", summary.Errors)
+	}
+	for i, want := range []string{"requires a title", "no fields", "only be set on create"} {
+		if !strings.Contains(summary.Errors[i].Err.Error(), want) {
+			t.Errorf("error %d = %v, want %q", i, summary.Errors[i].Err, want)
+		}
+	}
+}
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,10 +17,12 @@ import (
 	"github.com/KeithWyatt/gongshow/internal/deacon"
 	"github.com/KeithWyatt/gongshow/internal/style"
 	"github.com/KeithWyatt/gongshow/internal/workspace"
+	"golang.org/x/term"
 )
 
 var (
 	bootStatusJSON    bool
+	bootStatusFollow  bool
 	bootDegraded      bool
 	bootAgentOverride string
 )
@@ -37,7 +45,15 @@ Boot lifecycle:
   4. Boot exits (or handoffs in non-degraded mode)
 
 Location: ~/gt/deacon/dogs/boot/
-Session: gt-boot`,
+Session: gt-boot
+
+Use --plan to see what booting the whole town with 'gt up' would do,
+without starting anything (same as 'gt up --plan').
+
+Use --profile, --rig or --only to boot part of the town, as with
+'gt up --profile': 'gt boot --profile dev' starts the rigs and roles of
+the town settings' "dev" boot profile.`,
+	RunE: runBoot,
 }
 
 var bootStatusCmd = &cobra.Command{
@@ -49,7 +65,13 @@ Displays:
   - Whether Boot is currently running
   - Last action taken (start/wake/nudge/nothing)
   - Timing information
-  - Degraded mode status`,
+  - Degraded mode status
+  - The last 'gt up': each agent's state while it runs, its outcome after
+
+With --follow, shows a table of the agents of a 'gt up' in progress
+(agent, phase, state, elapsed), redrawn as their states change, until the
+boot finishes. With --json as well, prints the status as a line of JSON on
+each change.`,
 	RunE: runBootStatus,
 }
 
@@ -84,8 +106,15 @@ Use --degraded flag when running in degraded mode.`,
 
 func init() {
 	bootStatusCmd.Flags().BoolVar(&bootStatusJSON, "json", false, "Output as JSON")
+	bootStatusCmd.Flags().BoolVarP(&bootStatusFollow, "follow", "f", false, "Follow a 'gt up' in progress until it finishes")
 	bootTriageCmd.Flags().BoolVar(&bootDegraded, "degraded", false, "Run in degraded mode (no tmux)")
 	bootSpawnCmd.Flags().StringVar(&bootAgentOverride, "agent", "", "Agent alias to run Boot with (overrides town default)")
+	bootCmd.Flags().BoolVar(&upPlan, "plan", false, "Show what 'gt up' would start, without starting anything")
+	bootCmd.Flags().BoolVar(&upJSON, "json", false, "With --plan, output the plan as JSON")
+	bootCmd.Flags().BoolVar(&upRestore, "restore", false, "Include the crew and polecats 'gt up --restore' starts")
+	bootCmd.Flags().StringVar(&upProfile, "profile", "", "Boot the rigs and roles of this boot profile")
+	bootCmd.Flags().StringSliceVar(&upRigs, "rig", nil, "Boot only this rig's agents (repeatable; replaces the profile's rigs)")
+	bootCmd.Flags().StringSliceVar(&upOnly, "only", nil, "Boot only agents of this role (repeatable; replaces the profile's roles)")
 
 	bootCmd.AddCommand(bootStatusCmd)
 	bootCmd.AddCommand(bootSpawnCmd)
@@ -94,6 +123,16 @@ func init() {
 	rootCmd.AddCommand(bootCmd)
 }
 
+// runBoot boots the town as gt up does - showing the plan with --plan, or
+// starting the part of it --profile, --rig and --only select - and shows
+// help otherwise.
+func runBoot(cmd *cobra.Command, args []string) error {
+	if !upPlan && upProfile == "" && len(upRigs) == 0 && len(upOnly) == 0 {
+		return cmd.Help()
+	}
+	return runUp(cmd, args)
+}
+
 func getBootManager() (*boot.Boot, error) {
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil {
@@ -109,6 +148,10 @@ func runBootStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if bootStatusFollow {
+		return followBootStatus(b)
+	}
+
 	status, err := b.LoadStatus()
 	if err != nil {
 		return fmt.Errorf("loading status: %w", err)
@@ -181,12 +224,154 @@ func runBootStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if status.StartupRunning {
+		fmt.Println()
+		fmt.Println(style.Dim.Render("Startup In Progress:"))
+		printBootProgress(os.Stdout, status, time.Now())
+	} else if len(status.Agents) > 0 {
+		fmt.Println()
+		fmt.Println(style.Dim.Render("Last Startup:"))
+		printStartupStatus(os.Stdout, status)
+	}
+
 	fmt.Println()
 	fmt.Printf("  Dir: %s\n", b.Dir())
 
 	return nil
 }
 
+// printStartupStatus prints the outcome of the last gt up: the counts,
+// the boot hooks that failed, and each agent that didn't come up, with
+// what its session showed.
+func printStartupStatus(w io.Writer, status *boot.Status) {
+	summary := boot.Summarize(status.Agents)
+	if status.Summary != nil {
+		summary = *status.Summary
+	}
+	_, _ = fmt.Fprintf(w, "  Selection: %s\n", status.Selection)
+	_, _ = fmt.Fprintf(w, "  Agents: %s\n", formatBootSummary(summary))
+	if summary.BootTimedOut {
+		_, _ = fmt.Fprintf(w, "  %s\n", style.Bold.Render("The boot timed out"))
+	}
+	if status.StartupAbandoned {
+		_, _ = fmt.Fprintf(w, "  %s\n", style.Bold.Render(fmt.Sprintf("gt up (pid %d) exited before finishing", status.StartupPID)))
+	}
+	if len(status.Hooks) > 0 {
+		failed := 0
+		for _, h := range status.Hooks {
+			if !h.OK {
+				failed++
+			}
+		}
+		_, _ = fmt.Fprintf(w, "  Hooks: %d ran, %d failed\n", len(status.Hooks), failed)
+		for _, h := range status.Hooks {
+			if !h.OK {
+				_, _ = fmt.Fprintf(w, "  %s %s hook %s: %s\n", style.ErrorPrefix, h.Stage, h.Name, h.Error)
+			}
+		}
+	}
+
+	for _, st := range status.Agents {
+		if st.Ready {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "  %s %s (%s): %s\n", style.ErrorPrefix, st.ID, st.Outcome, st.Error)
+		if st.RolledBack {
+			_, _ = fmt.Fprintf(w, "      %s\n", style.Dim.Render("session killed (--rollback-failed)"))
+		}
+		if out := strings.TrimRight(st.PaneOutput, "\n "); out != "" {
+			for _, line := range strings.Split(out, "\n") {
+				_, _ = fmt.Fprintf(w, "      %s\n", style.Dim.Render("│ "+line))
+			}
+		}
+	}
+}
+
+// followBootStatus redraws the progress of a gt up each time the status
+// file changes, until the boot finishes or is interrupted.
+func followBootStatus(b *boot.Boot) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+	return b.FollowStatus(ctx, 250*time.Millisecond, func(status *boot.Status) {
+		if bootStatusJSON {
+			// One line per change, for piping.
+			data, err := json.Marshal(status)
+			if err == nil {
+				fmt.Println(string(data))
+			}
+			return
+		}
+
+		if isTTY {
+			fmt.Print("\033[H\033[2J") // ANSI: cursor home + clear screen
+		}
+		header := "gt boot status --follow (Ctrl+C to stop)"
+		if !status.StartupStartedAt.IsZero() {
+			header = fmt.Sprintf("[%s] %s", status.StartupStartedAt.Format("15:04:05"), header)
+		}
+		fmt.Printf("%s\n\n", style.Dim.Render(header))
+		printBootProgress(os.Stdout, status, time.Now())
+		if !status.StartupRunning && len(status.Agents) > 0 {
+			fmt.Println()
+			printStartupStatus(os.Stdout, status)
+		}
+	})
+}
+
+// printBootProgress prints a table of the agents of a gt up: phase, state
+// and how long each has been starting, or took, as of now.
+func printBootProgress(w io.Writer, status *boot.Status, now time.Time) {
+	if len(status.Agents) == 0 {
+		_, _ = fmt.Fprintf(w, "  %s\n", style.Dim.Render("(no startup recorded)"))
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "  AGENT\tPHASE\tSTATE\tELAPSED")
+	for _, st := range status.Agents {
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\n", st.ID, st.Phase, bootProgressState(st), bootProgressElapsed(st, now))
+	}
+	_ = tw.Flush()
+}
+
+// bootProgressState is an agent's state, with how a failed start failed
+// and which retry it is on.
+func bootProgressState(st boot.AgentStatus) string {
+	state := st.State
+	if state == "" {
+		// Saved before states were recorded.
+		state = boot.StateFailed
+		if st.Ready {
+			state = boot.StateReady
+		}
+	}
+	text := string(state)
+	if state == boot.StateFailed && st.Outcome != "" && st.Outcome != boot.OutcomeFailed {
+		text += fmt.Sprintf(" (%s)", st.Outcome)
+	}
+	if st.Retry > 0 {
+		text += fmt.Sprintf(" [retry %d]", st.Retry)
+	}
+	return text
+}
+
+// bootProgressElapsed is how long an agent has been starting, or how long
+// its start took once it is over; "-" if it was never started.
+func bootProgressElapsed(st boot.AgentStatus, now time.Time) string {
+	if st.StartedAt.IsZero() {
+		return "-"
+	}
+	end := now
+	if st.State.Done() && len(st.History) > 0 {
+		end = st.History[len(st.History)-1].At
+	} else if st.State == "" && !st.ReadyAt.IsZero() {
+		end = st.ReadyAt
+	}
+	return end.Sub(st.StartedAt).Round(100 * time.Millisecond).String()
+}
+
 func runBootSpawn(cmd *cobra.Command, args []string) error {
 	b, err := getBootManager()
 	if err != nil {
@@ -203,7 +388,7 @@ func runBootSpawn(cmd *cobra.Command, args []string) error {
 		Running:   true,
 		StartedAt: time.Now(),
 	}
-	if err := b.SaveStatus(status); err != nil {
+	if err := b.SaveTriageStatus(status); err != nil {
 		return fmt.Errorf("saving status: %w", err)
 	}
 
@@ -212,7 +397,7 @@ func runBootSpawn(cmd *cobra.Command, args []string) error {
 		status.Error = err.Error()
 		status.CompletedAt = time.Now()
 		status.Running = false
-		_ = b.SaveStatus(status)
+		_ = b.SaveTriageStatus(status)
 		return fmt.Errorf("spawning boot: %w", err)
 	}
 
@@ -256,7 +441,7 @@ func runBootTriage(cmd *cobra.Command, args []string) error {
 		status.Error = triageErr.Error()
 	}
 
-	if err := b.SaveStatus(status); err != nil {
+	if err := b.SaveTriageStatus(status); err != nil {
 		return fmt.Errorf("saving status: %w", err)
 	}
 
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/boot"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var (
+	bootHistoryLast  int
+	bootHistoryAgent string
+	bootHistoryJSON  bool
+)
+
+// bootTrendBarWidth is the width of the longest bar in an agent's trend.
+const bootTrendBarWidth = 30
+
+var bootHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show how long past boots took",
+	Long: `Show the town's past boots ('gt up'), newest last: when each finished,
+its profile, how many agents came up, retries, failures, the wall time,
+and the slowest agent.
+
+With --agent, show one agent's startup duration in each boot that
+started it, and how the recent boots compare with the earlier ones.
+
+History is kept in <town>/logs/boot-history.jsonl, capped at the last
+500 boots.
+
+Examples:
+  gt boot history                          # The last 10 boots
+  gt boot history --last 50
+  gt boot history --agent gongshow/witness # Is the witness getting slower?
+  gt boot history --json`,
+	Args: cobra.NoArgs,
+	RunE: runBootHistory,
+}
+
+func init() {
+	bootHistoryCmd.Flags().IntVar(&bootHistoryLast, "last", 10, "Number of most recent boots to show (0 for all)")
+	bootHistoryCmd.Flags().StringVar(&bootHistoryAgent, "agent", "", "Show this agent's startup duration trend (e.g., gongshow/witness)")
+	bootHistoryCmd.Flags().BoolVar(&bootHistoryJSON, "json", false, "Output as JSON")
+
+	bootCmd.AddCommand(bootHistoryCmd)
+}
+
+func runBootHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+	if bootHistoryLast < 0 {
+		return fmt.Errorf("--last can't be negative")
+	}
+
+	records, err := boot.ReadHistory(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if bootHistoryAgent != "" {
+		points := lastN(boot.AgentTrend(records, bootHistoryAgent), bootHistoryLast)
+		if bootHistoryJSON {
+			if points == nil {
+				points = []boot.TrendPoint{}
+			}
+			return printBootHistoryJSON(points)
+		}
+		if len(points) == 0 {
+			fmt.Println(style.Dim.Render("No recorded boots started " + bootHistoryAgent))
+			return nil
+		}
+		return printAgentTrend(os.Stdout, bootHistoryAgent, points)
+	}
+
+	records = lastN(records, bootHistoryLast)
+	if bootHistoryJSON {
+		if records == nil {
+			records = []*boot.HistoryRecord{}
+		}
+		return printBootHistoryJSON(records)
+	}
+	if len(records) == 0 {
+		fmt.Println(style.Dim.Render("No boots recorded"))
+		return nil
+	}
+	return printBootHistory(os.Stdout, records)
+}
+
+func printBootHistoryJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// lastN returns the last n items, or all of them when n is 0.
+func lastN[T any](items []T, n int) []T {
+	if n > 0 && len(items) > n {
+		return items[len(items)-n:]
+	}
+	return items
+}
+
+// printBootHistory writes a table of boots, one per line.
+func printBootHistory(w io.Writer, records []*boot.HistoryRecord) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TIME\tPROFILE\tAGENTS\tRETRIES\tFAILED\tWALL\tSLOWEST")
+	for _, rec := range records {
+		profile := rec.Profile
+		if profile == "" {
+			profile = "-"
+		}
+		slowest := "-"
+		var slowestMs int64 = -1
+		for _, a := range rec.Agents {
+			if a.Started && a.DurationMs > slowestMs {
+				slowestMs = a.DurationMs
+				slowest = fmt.Sprintf("%s (%s)", a.ID, formatHistoryDuration(a.DurationMs))
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%d\t%d\t%s\t%s\n",
+			rec.Timestamp.Local().Format("2006-01-02 15:04:05"), profile,
+			len(rec.Agents)-rec.Failures, len(rec.Agents), rec.Retries, rec.Failures,
+			formatHistoryDuration(rec.WallMs), slowest)
+	}
+	return tw.Flush()
+}
+
+// printAgentTrend writes an agent's startup duration in each boot, with a
+// bar scaled to the slowest, and compares the mean of the newer half of
+// the boots with the older half.
+func printAgentTrend(w io.Writer, id string, points []boot.TrendPoint) error {
+	var longest int64
+	for _, p := range points {
+		if p.DurationMs > longest {
+			longest = p.DurationMs
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Startup duration of %s\n\n", style.Bold.Render(id))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, p := range points {
+		bar := 0
+		if longest > 0 {
+			bar = int(p.DurationMs * bootTrendBarWidth / longest)
+		}
+		note := ""
+		if p.Outcome != boot.OutcomeReady {
+			note = string(p.Outcome)
+		}
+		if p.Retry > 0 {
+			note = strings.TrimSpace(fmt.Sprintf("%s retry %d", note, p.Retry))
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			p.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			formatHistoryDuration(p.DurationMs),
+			strings.Repeat("█", bar), note)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(points) >= 2 {
+		half := len(points) / 2
+		older, newer := meanDuration(points[:half]), meanDuration(points[len(points)-half:])
+		change := ""
+		if older > 0 {
+			change = fmt.Sprintf(" (%+.0f%%)", float64(newer-older)*100/float64(older))
+		}
+		_, _ = fmt.Fprintf(w, "\nTrend: %s → %s%s, mean of the oldest %d vs newest %d boots\n",
+			formatHistoryDuration(older), formatHistoryDuration(newer),
+			change, half, half)
+	}
+	return nil
+}
+
+// meanDuration returns the mean startup duration of points, in ms.
+func meanDuration(points []boot.TrendPoint) int64 {
+	var total int64
+	for _, p := range points {
+		total += p.DurationMs
+	}
+	return total / int64(len(points))
+}
+
+// formatHistoryDuration formats milliseconds to a tenth of a second.
+func formatHistoryDuration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/boot"
+)
+
+func TestPrintBootHistory(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	records := []*boot.HistoryRecord{
+		{Timestamp: at, Profile: "dev", WallMs: 12340, Agents: []boot.HistoryAgent{
+			{ID: "daemon", Outcome: boot.OutcomeReady, Started: true, DurationMs: 900},
+			{ID: "alpha/witness", Outcome: boot.OutcomeReady, Started: true, DurationMs: 8260},
+		}},
+		{Timestamp: at.Add(time.Hour), WallMs: 61000, Retries: 2, Failures: 1, Agents: []boot.HistoryAgent{
+			{ID: "daemon", Outcome: boot.OutcomeReady},
+			{ID: "alpha/witness", Outcome: boot.OutcomeFailed, Retry: 2, Started: true, DurationMs: 60000},
+		}},
+	}
+
+	var out bytes.Buffer
+	if err := printBootHistory(&out, records); err != nil {
+		t.Fatalf("printBootHistory() error = %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("printBootHistory() wrote %d lines, want a header and 2 boots:\n%s", len(lines), out.String())
+	}
+	for i, want := range [][]string{
+		{"TIME", "PROFILE", "AGENTS", "RETRIES", "FAILED", "WALL", "SLOWEST"},
+		{"2026-03-01", "09:00:00", "dev", "2/2", "0", "0", "12.3s", "alpha/witness", "(8.3s)"},
+		{"2026-03-01", "10:00:00", "-", "1/2", "2", "1", "1m1s", "alpha/witness", "(1m0s)"},
+	} {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Errorf("line %d = %q, want fields %q", i, lines[i], want)
+		}
+	}
+	// Columns line up.
+	if col := strings.Index(lines[0], "WALL"); strings.Index(lines[1], "12.3s") != col || strings.Index(lines[2], "1m1s") != col {
+		t.Errorf("WALL column not aligned:\n%s", out.String())
+	}
+}
+
+func TestPrintAgentTrend(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	points := []boot.TrendPoint{
+		{Timestamp: at, DurationMs: 4000, Outcome: boot.OutcomeReady},
+		{Timestamp: at.Add(24 * time.Hour), DurationMs: 6000, Outcome: boot.OutcomeReady},
+		{Timestamp: at.Add(48 * time.Hour), DurationMs: 6000, Outcome: boot.OutcomeReady, Retry: 1},
+		{Timestamp: at.Add(72 * time.Hour), DurationMs: 12000, Outcome: boot.OutcomeTimedOut},
+	}
+
+	var out bytes.Buffer
+	if err := printAgentTrend(&out, "alpha/witness", points); err != nil {
+		t.Fatalf("printAgentTrend() error = %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"alpha/witness",
+		"2026-03-01 09:00:00  4s   " + strings.Repeat("█", 10),
+		"retry 1",
+		"2026-03-04 09:00:00  12s  " + strings.Repeat("█", bootTrendBarWidth) + "  timed_out",
+		"Trend: 5s → 9s (+80%), mean of the oldest 2 vs newest 2 boots",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("trend missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestFormatHistoryDuration(t *testing.T) {
+	for ms, want := range map[int64]string{0: "0s", 940: "900ms", 12340: "12.3s", 61000: "1m1s"} {
+		if got := formatHistoryDuration(ms); got != want {
+			t.Errorf("formatHistoryDuration(%d) = %q, want %q", ms, got, want)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bytes"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/boot"
 )
 
 func TestBootSpawnAgentFlag(t *testing.T) {
@@ -17,3 +21,67 @@ func TestBootSpawnAgentFlag(t *testing.T) {
 		t.Errorf("expected --agent usage to mention overrides town default, got %q", flag.Usage)
 	}
 }
+
+func TestPrintStartupStatus(t *testing.T) {
+	status := &boot.Status{Agents: []boot.AgentStatus{
+		{ID: "daemon", Ready: true, Outcome: boot.OutcomeReady},
+		{ID: "alpha/witness", Outcome: boot.OutcomeTimedOut, Error: "not ready after 1m0s",
+			PaneOutput: "Loading config...\nwaiting for auth\n", RolledBack: true},
+		{ID: "alpha/refinery", Outcome: boot.OutcomeFailed, Error: "dependency alpha/witness not ready"},
+		{ID: "beta/witness", Outcome: boot.OutcomeNotAttempted, BootTimeout: true, Error: "not started: boot timed out"},
+	}}
+	status.Hooks = []boot.HookResult{
+		{Stage: boot.HookPre, Name: "10-vpn", OK: true},
+		{Stage: boot.HookPost, Name: "50-slack", TimedOut: true, Error: "timed out after 1m0s"},
+	}
+
+	var out bytes.Buffer
+	printStartupStatus(&out, status)
+	text := out.String()
+	for _, want := range []string{
+		"Agents: 1 ready, 2 failed (1 timed out), 1 not attempted",
+		"The boot timed out",
+		"alpha/witness (timed_out): not ready after 1m0s",
+		"session killed",
+		"│ waiting for auth",
+		"alpha/refinery (failed): dependency alpha/witness not ready",
+		"beta/witness (not_attempted)",
+		"Hooks: 2 ran, 1 failed",
+		"post hook 50-slack: timed out after 1m0s",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("startup status missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "daemon") {
+		t.Errorf("startup status lists the ready daemon:\n%s", text)
+	}
+}
+
+func TestPrintBootProgress(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	now := start.Add(5 * time.Second)
+	status := &boot.Status{StartupRunning: true, Agents: []boot.AgentStatus{
+		{ID: "daemon", Phase: "infrastructure", State: boot.StateReady, Ready: true, StartedAt: start,
+			History: []boot.Transition{{State: boot.StateStarting, At: start}, {State: boot.StateReady, At: start.Add(1500 * time.Millisecond)}}},
+		{ID: "alpha/witness", Phase: "rig", State: boot.StateWaitingReady, StartedAt: start.Add(2 * time.Second)},
+		{ID: "beta/witness", Phase: "rig", State: boot.StateFailed, Outcome: boot.OutcomeTimedOut, StartedAt: start,
+			History: []boot.Transition{{State: boot.StateStarting, At: start}, {State: boot.StateFailed, At: start.Add(4 * time.Second)}}},
+		{ID: "alpha/polecats/Toast", Phase: "workers", State: boot.StatePending},
+	}}
+
+	var out bytes.Buffer
+	printBootProgress(&out, status, now)
+	text := out.String()
+	for _, want := range []string{
+		"AGENT",
+		"daemon                infrastructure  ready               1.5s",
+		"alpha/witness         rig             waiting_ready       3s",
+		"beta/witness          rig             failed (timed_out)  4s",
+		"alpha/polecats/Toast  workers         pending             -",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("boot progress missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/doctor"
+	"github.com/KeithWyatt/gongshow/internal/state"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a town config value",
+	Long: `Set a value in a town config file by key.
+
+The first part of the key names the file:
+  daemon       mayor/daemon.json
+  escalation   settings/escalation.json
+  messaging    config/messaging.json
+  settings     settings/config.json
+
+The rest follows the JSON structure: field names, map keys and list
+indexes, separated by dots. The value is parsed as the field's type:
+numbers and true/false as text, lists of strings as comma-separated
+values or a JSON array, and objects as JSON. The file is validated
+before it is written.
+
+Keys under "shell" are settings of this machine's shell integration
+rather than the town, and work outside a town:
+  shell.mode   auto (the hook runs in every git repo and offers to add
+               it) or opt-in (only in repos registered with
+               'gt rig opt-in' or inside a town, without prompting)
+
+Examples:
+  gt config set messaging.lists.oncall mayor/,gongshow/witness
+  gt config set messaging.queues.work/gongshow.workers 'gongshow/polecats/*'
+  gt config set messaging.queues.work/gongshow.max_claims 3
+  gt config set settings.mass_death '{"threshold": 8, "window": "1m"}'
+  gt config set shell.mode opt-in`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show a town config value",
+	Long: `Show a value from a town config file by key (see 'gt config set').
+
+A key naming a whole file or object prints it as JSON.
+
+Examples:
+  gt config get messaging.queues.work/gongshow.max_claims
+  gt config get escalation.routes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigGet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list [prefix]",
+	Short: "List town config values",
+	Long: `List every value set in the town config files, one key per line.
+
+With a prefix, only keys at or below it are listed.
+
+Examples:
+  gt config list
+  gt config list messaging.queues`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigList,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the town config files against their schemas",
+	Long: `Check the town config files against their schemas, as the
+config-schema check in 'gt doctor' does: each must parse, have no
+unknown fields, and pass validation.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configValidateCmd)
+}
+
+// readOnlyConfigKeys are managed by gt and can't be set.
+var readOnlyConfigKeys = map[string]bool{"type": true, "version": true}
+
+// shellModeKey is the machine-level key for the shell hook mode, kept in
+// the state file rather than a town config file.
+const shellModeKey = "shell.mode"
+
+// isShellConfigKey reports whether key is under "shell".
+func isShellConfigKey(key string) bool {
+	return key == "shell" || strings.HasPrefix(key, "shell.")
+}
+
+// checkShellConfigKey returns an error unless key is a shell key that
+// exists.
+func checkShellConfigKey(key string) error {
+	if key != shellModeKey {
+		return fmt.Errorf("%w: %q (shell has only %s)", config.ErrInvalidKey, key, shellModeKey)
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	if isShellConfigKey(args[0]) {
+		if err := checkShellConfigKey(args[0]); err != nil {
+			return err
+		}
+		if err := state.SetShellMode(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("%s Set %s = %s\n", style.Success.Render("✓"), style.Bold.Render(args[0]), args[1])
+		if args[1] == state.ShellModeOptIn {
+			fmt.Println("  The shell hook now only runs in repos registered with 'gt rig opt-in' or inside a town.")
+		}
+		return nil
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+	key, value := args[0], args[1]
+	if err := setTownConfigKey(townRoot, key, value); err != nil {
+		return err
+	}
+	fmt.Printf("%s Set %s = %s\n", style.Success.Render("✓"), style.Bold.Render(key), value)
+	return nil
+}
+
+// setTownConfigKey sets key to value in the town config file it names.
+func setTownConfigKey(townRoot, key, value string) error {
+	file, rest, err := config.LookupTownConfigKey(key)
+	if err != nil {
+		return err
+	}
+	if rest == "" {
+		return fmt.Errorf("%w: %q names the whole %s config; set a key within it", config.ErrInvalidKey, key, file.Name)
+	}
+	if readOnlyConfigKeys[rest] {
+		return fmt.Errorf("%w: %s is managed by gt", config.ErrInvalidKey, key)
+	}
+
+	cfg, err := file.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	if err := config.SetKey(cfg, rest, value); err != nil {
+		return fmt.Errorf("%s: %w", file.Name, err)
+	}
+	if err := file.Save(townRoot, cfg); err != nil {
+		return fmt.Errorf("not saved: %w", err)
+	}
+	return nil
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	if isShellConfigKey(args[0]) {
+		if args[0] != "shell" {
+			if err := checkShellConfigKey(args[0]); err != nil {
+				return err
+			}
+		}
+		fmt.Println(state.GetShellMode())
+		return nil
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+	value, err := getTownConfigKey(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+// getTownConfigKey returns the value of key, formatted for display.
+func getTownConfigKey(townRoot, key string) (string, error) {
+	file, rest, err := config.LookupTownConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := file.Load(townRoot)
+	if err != nil {
+		return "", err
+	}
+
+	value := cfg
+	if rest != "" {
+		if value, err = config.GetKey(cfg, rest); err != nil {
+			if errors.Is(err, config.ErrKeyNotSet) {
+				return "", fmt.Errorf("%s is not set", key)
+			}
+			return "", fmt.Errorf("%s: %w", file.Name, err)
+		}
+	}
+	if formatted := config.FormatValue(value); !strings.HasPrefix(formatted, "{") {
+		return formatted, nil
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	prefix := ""
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+	if isShellConfigKey(prefix) {
+		fmt.Printf("%s = %s\n", shellModeKey, state.GetShellMode())
+		return nil
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+	values, err := listTownConfigKeys(townRoot, prefix)
+	if err != nil {
+		return err
+	}
+	for _, kv := range values {
+		fmt.Printf("%s = %s\n", kv.Key, config.FormatValue(kv.Value))
+	}
+	if prefix == "" {
+		fmt.Printf("%s = %s\n", shellModeKey, state.GetShellMode())
+	}
+	return nil
+}
+
+// listTownConfigKeys returns the values set in the town config files at
+// or below prefix ("" for all), with keys including the file name.
+func listTownConfigKeys(townRoot, prefix string) ([]config.KeyValue, error) {
+	files := config.TownConfigFiles()
+	if prefix != "" {
+		file, _, err := config.LookupTownConfigKey(prefix)
+		if err != nil {
+			return nil, err
+		}
+		files = []config.TownConfigFile{file}
+	}
+
+	var out []config.KeyValue
+	for _, file := range files {
+		cfg, err := file.Load(townRoot)
+		if err != nil {
+			return nil, err
+		}
+		for _, kv := range config.ListKeys(cfg) {
+			kv.Key = file.Name + "." + kv.Key
+			if prefix == "" || kv.Key == prefix || strings.HasPrefix(kv.Key, prefix+".") {
+				out = append(out, kv)
+			}
+		}
+	}
+	return out, nil
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	result := doctor.NewConfigSchemaCheck().Run(&doctor.CheckContext{TownRoot: townRoot})
+	if result.Status == doctor.StatusOK {
+		fmt.Printf("%s %s\n", style.Success.Render("✓"), result.Message)
+		return nil
+	}
+	fmt.Printf("%s %s\n", style.Error.Render("✗"), result.Message)
+	for _, detail := range result.Details {
+		fmt.Printf("  %s\n", detail)
+	}
+	return NewSilentExit(1)
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+func TestSetTownConfigKey(t *testing.T) {
+	townRoot := setupTestTownForConfig(t)
+
+	if err := setTownConfigKey(townRoot, "messaging.queues.work/gongshow.workers", "gongshow/polecats/*"); err != nil {
+		t.Fatalf("set workers: %v", err)
+	}
+	if err := setTownConfigKey(townRoot, "messaging.queues.work/gongshow.max_claims", "3"); err != nil {
+		t.Fatalf("set max_claims: %v", err)
+	}
+
+	cfg, err := config.LoadMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil {
+		t.Fatalf("LoadMessagingConfig: %v", err)
+	}
+	if got := cfg.Queues["work/gongshow"].MaxClaims; got != 3 {
+		t.Errorf("max_claims = %d, want 3", got)
+	}
+
+	got, err := getTownConfigKey(townRoot, "messaging.queues.work/gongshow.max_claims")
+	if err != nil {
+		t.Fatalf("getTownConfigKey: %v", err)
+	}
+	if got != "3" {
+		t.Errorf("get max_claims = %q, want 3", got)
+	}
+
+	values, err := listTownConfigKeys(townRoot, "messaging.queues")
+	if err != nil {
+		t.Fatalf("listTownConfigKeys: %v", err)
+	}
+	if len(values) != 2 {
+		t.Errorf("list messaging.queues = %v, want 2 values", values)
+	}
+
+	// Validation failures leave the file untouched.
+	if err := setTownConfigKey(townRoot, "messaging.queues.work/gongshow.max_claims", "-1"); err == nil {
+		t.Error("negative max_claims was saved")
+	}
+
+	for _, key := range []string{"messaging", "messaging.version", "nosuch.key", "messaging.queues.work/gongshow.typo"} {
+		if err := setTownConfigKey(townRoot, key, "1"); !errors.Is(err, config.ErrInvalidKey) {
+			t.Errorf("set %q error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
@@ -39,15 +39,16 @@ Unlike polecats which are witness-managed and transient, crew workers are:
 - Tmux optional: Can work in terminal directly
 
 Commands:
-  gt crew start <name>     Start a crew workspace (creates if needed)
-  gt crew stop <name>      Stop crew workspace session(s)
-  gt crew add <name>       Create a new crew workspace
-  gt crew list             List crew workspaces with status
-  gt crew at <name>        Attach to crew workspace session
-  gt crew remove <name>    Remove a crew workspace
-  gt crew refresh <name>   Context cycling with mail-to-self handoff
-  gt crew restart <name>   Kill and restart session fresh (alias: rs)
-  gt crew status [<name>]  Show detailed workspace status`,
+  gt crew start <name>          Start a crew workspace (creates if needed)
+  gt crew stop <name>           Stop crew workspace session(s)
+  gt crew add <name>            Create a new crew workspace
+  gt crew list                  List crew workspaces with status
+  gt crew at <name>             Attach to crew workspace session
+  gt crew remove <name>         Remove a crew workspace
+  gt crew refresh <name>        Context cycling with mail-to-self handoff
+  gt crew restart <name>        Kill and restart session fresh (alias: rs)
+  gt crew status [<name>]       Show detailed workspace status
+  gt crew handover --to <name>  Hand your work to another crew member`,
 }
 
 var crewAddCmd = &cobra.Command{
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
 	"github.com/KeithWyatt/gongshow/internal/config"
 	"github.com/KeithWyatt/gongshow/internal/constants"
 	"github.com/KeithWyatt/gongshow/internal/crew"
@@ -153,23 +154,21 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 	}
 
 	if !hasSession {
-		// Create new session
-		if err := t.NewSession(sessionID, worker.ClonePath); err != nil {
-			return fmt.Errorf("creating session: %w", err)
-		}
-
-		// Set environment (non-fatal: session works without these)
+		// Create new session with its environment set. On tmux 3.2+ the
+		// shell inherits it; on older tmux only the runtime respawned below
+		// does.
 		// Use centralized AgentEnv for consistency across all role startup paths
-		envVars := config.AgentEnv(config.AgentEnvConfig{
+		roleConfig, _ := beads.LoadRoleConfig(townRoot, "crew")
+		envVars := beads.AgentEnv(config.AgentEnvConfig{
 			Role:             "crew",
 			Rig:              r.Name,
 			AgentName:        name,
 			TownRoot:         townRoot,
 			RuntimeConfigDir: claudeConfigDir,
 			BeadsNoDaemon:    true,
-		})
-		for k, v := range envVars {
-			_ = t.SetEnvironment(sessionID, k, v)
+		}, sessionID, roleConfig)
+		if err := t.NewSessionWithEnv(sessionID, worker.ClonePath, envVars); err != nil {
+			return fmt.Errorf("creating session: %w", err)
 		}
 
 		// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
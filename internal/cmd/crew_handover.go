@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/crew"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var (
+	crewHandoverTo      string
+	crewHandoverFrom    string
+	crewHandoverSummary string
+	crewHandoverBeads   []string
+)
+
+var crewHandoverCmd = &cobra.Command{
+	Use:   "handover",
+	Short: "Hand your work to another crew member at a shift change",
+	Long: `Hand your context and pending beads to the crew member taking over.
+
+The incoming crew member gets a mail with your summary and the beads
+handed over, and each listed bead is reassigned to them. A crew_handover
+event records the shift change in the feed.
+
+You are the crew member whose workspace you are in, or --from. The
+incoming crew member is in the same rig unless given as <rig>/<name>.
+
+Examples:
+  gt crew handover --to joe --summary "Auth refactor half done; tests red"
+  gt crew handover --to joe --summary "See notes" --beads gt-abc,gt-def
+  gt crew handover --from beads/emma --to beads/fred --summary "Nothing pending"`,
+	Args: cobra.NoArgs,
+	RunE: runCrewHandover,
+}
+
+func init() {
+	crewHandoverCmd.Flags().StringVar(&crewHandoverTo, "to", "", "Crew member taking over (<name> or <rig>/<name>)")
+	crewHandoverCmd.Flags().StringVar(&crewHandoverFrom, "from", "", "Crew member handing over (default: the workspace you are in)")
+	crewHandoverCmd.Flags().StringVar(&crewHandoverSummary, "summary", "", "Context notes for the incoming crew member")
+	crewHandoverCmd.Flags().StringSliceVar(&crewHandoverBeads, "beads", nil, "Beads to reassign to the incoming crew member (comma-separated)")
+	_ = crewHandoverCmd.MarkFlagRequired("to")
+	_ = crewHandoverCmd.MarkFlagRequired("summary")
+
+	crewCmd.AddCommand(crewHandoverCmd)
+}
+
+func runCrewHandover(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	fromRig, fromName := "", crewHandoverFrom
+	if fromName == "" {
+		detected, err := detectCrewFromCwd()
+		if err != nil {
+			return fmt.Errorf("use --from to say who is handing over: %w", err)
+		}
+		fromRig, fromName = detected.rigName, detected.crewName
+	} else if rigName, name, ok := parseRigSlashName(fromName); ok {
+		fromRig, fromName = rigName, name
+	}
+	if fromRig, err = checkCrewExists(fromRig, fromName); err != nil {
+		return err
+	}
+
+	toRig, toName := fromRig, crewHandoverTo
+	if rigName, name, ok := parseRigSlashName(toName); ok {
+		toRig, toName = rigName, name
+	}
+	if toRig, err = checkCrewExists(toRig, toName); err != nil {
+		return err
+	}
+
+	h := &crewHandover{
+		From:    crewAddress(fromRig, fromName),
+		To:      crewAddress(toRig, toName),
+		Summary: strings.TrimSpace(crewHandoverSummary),
+		Beads:   crewHandoverBeads,
+	}
+	if h.From == h.To {
+		return fmt.Errorf("%s can't hand over to themselves", h.From)
+	}
+
+	bd := beads.New(townRoot)
+	assign := func(id, assignee string) error {
+		return bd.Update(id, beads.UpdateOptions{Assignee: &assignee})
+	}
+	router := mail.NewRouter(townRoot)
+	if err := h.run(assign, router.Send); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Handed over from %s to %s\n", style.Bold.Render("✓"), h.From, h.To)
+	if len(h.Beads) > 0 {
+		fmt.Printf("  Reassigned: %s\n", strings.Join(h.Beads, ", "))
+	}
+	return nil
+}
+
+// checkCrewExists returns the rig of the crew workspace called name, or
+// an error if there is none. An empty rigName is inferred from the cwd.
+func checkCrewExists(rigName, name string) (string, error) {
+	crewMgr, r, err := getCrewManager(rigName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := crewMgr.Get(name); err != nil {
+		if err == crew.ErrCrewNotFound {
+			return "", fmt.Errorf("crew workspace '%s/%s' not found", r.Name, name)
+		}
+		return "", fmt.Errorf("getting crew worker: %w", err)
+	}
+	return r.Name, nil
+}
+
+func crewAddress(rigName, name string) string {
+	return rigName + "/crew/" + name
+}
+
+// crewHandover is one crew member handing their work to another.
+type crewHandover struct {
+	From    string // Crew addresses, <rig>/crew/<name>
+	To      string
+	Summary string
+	Beads   []string // Reassigned to To
+}
+
+// run reassigns the beads to the incoming crew member with assign, mails
+// them the handover with send, and logs a crew_handover event. It stops
+// at the first bead it can't reassign, before sending anything.
+func (h *crewHandover) run(assign func(id, assignee string) error, send func(*mail.Message) error) error {
+	for _, id := range h.Beads {
+		if err := assign(id, h.To); err != nil {
+			return fmt.Errorf("reassigning %s to %s: %w", id, h.To, err)
+		}
+	}
+	if err := send(h.message()); err != nil {
+		return fmt.Errorf("mailing %s: %w", h.To, err)
+	}
+	_ = events.LogFeed(events.TypeCrewHandover, h.From,
+		events.CrewHandoverPayload(h.From, h.To, h.Summary, h.Beads))
+	return nil
+}
+
+// message is the mail telling the incoming crew member what they take over.
+func (h *crewHandover) message() *mail.Message {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s is handing over to you.\n\n", h.From)
+	if h.Summary != "" {
+		fmt.Fprintf(&body, "%s\n", h.Summary)
+	}
+	if len(h.Beads) > 0 {
+		fmt.Fprintf(&body, "\nNow assigned to you:\n")
+		for _, id := range h.Beads {
+			fmt.Fprintf(&body, "- %s\n", id)
+		}
+	}
+	return &mail.Message{
+		From:     h.From,
+		To:       h.To,
+		Subject:  "🤝 HANDOVER from " + h.From,
+		Body:     body.String(),
+		Priority: mail.PriorityHigh,
+		Type:     mail.TypeTask,
+	}
+}
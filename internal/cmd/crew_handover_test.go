@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/mail"
+)
+
+func TestCrewHandoverRun(t *testing.T) {
+	t.Chdir(t.TempDir()) // keep the handover event out of the source tree
+	h := &crewHandover{
+		From:    "gongshow/crew/emma",
+		To:      "gongshow/crew/fred",
+		Summary: "Auth refactor half done",
+		Beads:   []string{"gt-abc", "gt-def"},
+	}
+
+	assigned := map[string]string{}
+	var sent []*mail.Message
+	err := h.run(
+		func(id, assignee string) error {
+			assigned[id] = assignee
+			return nil
+		},
+		func(msg *mail.Message) error {
+			sent = append(sent, msg)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	want := map[string]string{"gt-abc": "gongshow/crew/fred", "gt-def": "gongshow/crew/fred"}
+	if !reflect.DeepEqual(assigned, want) {
+		t.Errorf("assigned = %v, want %v", assigned, want)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sent))
+	}
+	msg := sent[0]
+	if msg.To != "gongshow/crew/fred" || msg.From != "gongshow/crew/emma" {
+		t.Errorf("message from %q to %q, want gongshow/crew/emma to gongshow/crew/fred", msg.From, msg.To)
+	}
+	for _, s := range []string{"Auth refactor half done", "gt-abc", "gt-def"} {
+		if !strings.Contains(msg.Body, s) {
+			t.Errorf("message body missing %q:\n%s", s, msg.Body)
+		}
+	}
+}
+
+func TestCrewHandoverRunStopsOnAssignError(t *testing.T) {
+	h := &crewHandover{
+		From:  "gongshow/crew/emma",
+		To:    "gongshow/crew/fred",
+		Beads: []string{"gt-abc", "gt-missing", "gt-def"},
+	}
+
+	var assigned []string
+	sent := false
+	err := h.run(
+		func(id, assignee string) error {
+			if id == "gt-missing" {
+				return errors.New("not found")
+			}
+			assigned = append(assigned, id)
+			return nil
+		},
+		func(*mail.Message) error {
+			sent = true
+			return nil
+		},
+	)
+	if err == nil || !strings.Contains(err.Error(), "gt-missing") {
+		t.Errorf("run() error = %v, want one naming gt-missing", err)
+	}
+	if !reflect.DeepEqual(assigned, []string{"gt-abc"}) {
+		t.Errorf("assigned = %v, want [gt-abc]", assigned)
+	}
+	if sent {
+		t.Error("run() sent the handover mail after a failed reassignment")
+	}
+}
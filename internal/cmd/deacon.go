@@ -368,10 +368,11 @@ func startDeaconSession(t *tmux.Tmux, sessionName, agentOverride string) error {
 
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	roleConfig, _ := beads.LoadRoleConfig(townRoot, "deacon")
+	envVars := beads.AgentEnv(config.AgentEnvConfig{
 		Role:     "deacon",
 		TownRoot: townRoot,
-	})
+	}, sessionName, roleConfig)
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionName, k, v)
 	}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/KeithWyatt/gongshow/internal/doctor"
@@ -15,6 +17,8 @@ var (
 	doctorRig             string
 	doctorRestartSessions bool
 	doctorDryRun          bool
+	doctorParallel        bool
+	doctorCheckTimeout    time.Duration
 )
 
 var doctorCmd = &cobra.Command{
@@ -43,10 +47,12 @@ Infrastructure checks:
   - daemon                   Check if daemon is running (fixable)
   - repo-fingerprint         Check database has valid repo fingerprint (fixable)
   - boot-health              Check Boot watchdog health (vet mode)
+  - events                   Validate the events log against event schemas
 
 Cleanup checks (fixable):
   - orphan-sessions          Detect orphaned tmux sessions
   - orphan-processes         Detect orphaned Claude processes
+  - zombie-processes         Detect defunct processes in GongShow sessions
   - wisp-gc                  Detect and clean abandoned wisps (>1h)
 
 Clone divergence checks:
@@ -83,7 +89,8 @@ Patrol checks:
 
 Use --fix to attempt automatic fixes for issues that support it.
 Use --fix --dry-run to see what would be fixed without making changes.
-Use --rig to check a specific rig instead of the entire workspace.`,
+Use --rig to check a specific rig instead of the entire workspace.
+Use --parallel to run checks concurrently (each bounded by --check-timeout).`,
 	RunE: runDoctor,
 }
 
@@ -91,8 +98,10 @@ func init() {
 	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Attempt to automatically fix issues")
 	doctorCmd.Flags().BoolVarP(&doctorVerbose, "verbose", "v", false, "Show detailed output")
 	doctorCmd.Flags().StringVar(&doctorRig, "rig", "", "Check specific rig only")
-	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings (use with --fix)")
+	doctorCmd.Flags().BoolVar(&doctorRestartSessions, "restart-sessions", false, "Restart patrol sessions when fixing stale settings or unreaped zombies (use with --fix)")
 	doctorCmd.Flags().BoolVar(&doctorDryRun, "dry-run", false, "Show what would be fixed without actually fixing (use with --fix)")
+	doctorCmd.Flags().BoolVar(&doctorParallel, "parallel", false, "Run checks concurrently (ignored with --fix)")
+	doctorCmd.Flags().DurationVar(&doctorCheckTimeout, "check-timeout", doctor.DefaultCheckTimeout, "Per-check timeout with --parallel")
 	rootCmd.AddCommand(doctorCmd)
 }
 
@@ -128,6 +137,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewDaemonCheck())
 	d.Register(doctor.NewRepoFingerprintCheck())
 	d.Register(doctor.NewBootHealthCheck())
+	d.Register(doctor.NewEventsCheck())
 	d.Register(doctor.NewBeadsDatabaseCheck())
 	d.Register(doctor.NewCustomTypesCheck())
 	d.Register(doctor.NewRoleLabelCheck())
@@ -139,6 +149,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewRigRoutesJSONLCheck())
 	d.Register(doctor.NewOrphanSessionCheck())
 	d.Register(doctor.NewOrphanProcessCheck())
+	d.Register(doctor.NewZombieProcessCheck())
 	d.Register(doctor.NewWispGCCheck())
 	d.Register(doctor.NewBranchCheck())
 	d.Register(doctor.NewBeadsSyncOrphanCheck())
@@ -158,11 +169,13 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewAgentBeadsCheck())
 	d.Register(doctor.NewRigBeadsCheck())
 	d.Register(doctor.NewRoleBeadsCheck())
+	d.Register(doctor.NewAgentStartCommandCheck())
 
 	// NOTE: StaleAttachmentsCheck removed - staleness detection belongs in Deacon molecule
 
 	// Config architecture checks
 	d.Register(doctor.NewSettingsCheck())
+	d.Register(doctor.NewConfigSchemaCheck())
 	d.Register(doctor.NewSessionHookCheck())
 	d.Register(doctor.NewRuntimeGitignoreCheck())
 	d.Register(doctor.NewLegacyGongshowCheck())
@@ -193,6 +206,9 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	var report *doctor.Report
 	if doctorFix {
 		report = d.Fix(ctx)
+	} else if doctorParallel {
+		ctx.CheckTimeout = doctorCheckTimeout
+		report = d.RunParallel(context.Background(), ctx)
 	} else {
 		report = d.Run(ctx)
 	}
@@ -418,7 +418,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("\nNotifying Witness...\n")
-	if err := townRouter.Send(doneNotification); err != nil {
+	if err := townRouter.SendContext(cmd.Context(), doneNotification); err != nil {
 		style.PrintWarning("could not notify witness: %v", err)
 	} else {
 		fmt.Printf("%s Witness notified of %s\n", style.Bold.Render("✓"), exitType)
@@ -433,7 +433,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 				Subject: fmt.Sprintf("WORK_DONE: %s", issueID),
 				Body:    strings.Join(bodyLines, "\n"),
 			}
-			if err := townRouter.Send(dispatcherNotification); err != nil {
+			if err := townRouter.SendContext(cmd.Context(), dispatcherNotification); err != nil {
 				style.PrintWarning("could not notify dispatcher %s: %v", dispatcher, err)
 			} else {
 				fmt.Printf("%s Dispatcher %s notified of %s\n", style.Bold.Render("✓"), dispatcher, exitType)
@@ -443,7 +443,7 @@ func runDone(cmd *cobra.Command, args []string) error {
 
 	// Log done event (townlog and activity feed)
 	_ = LogDone(townRoot, sender, issueID)
-	_ = events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch))
+	_ = events.LogFeedContext(cmd.Context(), events.TypeDone, sender, events.DonePayload(issueID, branch))
 
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
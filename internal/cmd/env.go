@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/boot"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/session"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var (
+	envAddress string
+	envFormat  string
+	envDiff    bool
+)
+
+var envCmd = &cobra.Command{
+	Use:     "env",
+	GroupID: GroupDiag,
+	Short:   "Show the environment GongShow gives an agent",
+	Long: `Show the environment GongShow would give the agent at an address when
+starting its session: GT_ROLE, GT_RIG, BD_ACTOR and the rest, computed the
+way the start paths compute them, including env vars from the role bead's
+config. The agent's tmux session, work directory, beads directory and
+config files are listed alongside.
+
+With no --address, the agent whose workspace the current directory is in
+is described (mayor at the town root).
+
+--diff compares the result with the environment of the agent's running
+tmux session and lists what has drifted: missing variables, different
+values, and GongShow variables the session has but shouldn't. It exits 1
+when anything differs.
+
+Examples:
+  gt env
+  gt env --address gongshow/polecats/Toast
+  gt env --address gongshow/witness --format json
+  gt env --address gongshow/crew/max --diff`,
+	Args: cobra.NoArgs,
+	RunE: runEnv,
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envAddress, "address", "", "Agent address (e.g. mayor, gongshow/witness, gongshow/polecats/Toast)")
+	envCmd.Flags().StringVar(&envFormat, "format", "shell", "Output format: shell, json or dotenv")
+	envCmd.Flags().BoolVar(&envDiff, "diff", false, "Compare with the running tmux session's environment")
+	rootCmd.AddCommand(envCmd)
+}
+
+// agentEnvironment is what an agent is started with.
+type agentEnvironment struct {
+	Address string            `json:"address"`
+	Session string            `json:"session"`
+	Env     map[string]string `json:"env"`
+	Paths   map[string]string `json:"paths"`
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	switch envFormat {
+	case "shell", "json", "dotenv":
+	default:
+		return fmt.Errorf("invalid --format %q: use shell, json or dotenv", envFormat)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	var info RoleInfo
+	if envAddress != "" {
+		if info, err = roleInfoFromAddress(envAddress, townRoot); err != nil {
+			return err
+		}
+	} else {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("getting current directory: %w", err)
+		}
+		info = detectRole(cwd, townRoot)
+		if info.Role == RoleUnknown {
+			return fmt.Errorf("%s is not an agent's workspace; pass --address", cwd)
+		}
+	}
+
+	// Role bead env vars are best-effort: without bd, the base environment
+	// is still right.
+	roleConfig, _ := beads.LoadRoleConfig(townRoot, string(info.Role))
+	ae := resolveAgentEnvironment(townRoot, info, roleConfig)
+
+	if envDiff {
+		return runEnvDiff(ae)
+	}
+	return printAgentEnvironment(ae, envFormat)
+}
+
+// roleInfoFromAddress parses an agent address into role info.
+func roleInfoFromAddress(address, townRoot string) (RoleInfo, error) {
+	role, rig, name := parseRoleString(strings.TrimSuffix(address, "/"))
+	info := RoleInfo{Role: role, Rig: rig, Polecat: name, TownRoot: townRoot, Source: "explicit"}
+	switch role {
+	case RoleMayor, RoleDeacon, RoleBoot:
+	case RoleWitness, RoleRefinery:
+		if rig == "" {
+			return info, fmt.Errorf("address %q needs a rig (e.g. <rig>/%s)", address, role)
+		}
+	case RolePolecat, RoleCrew:
+		if rig == "" || name == "" {
+			return info, fmt.Errorf("address %q needs a rig and name (e.g. <rig>/%ss/<name>)", address, role)
+		}
+	default:
+		return info, fmt.Errorf("unknown agent address %q", address)
+	}
+	return info, nil
+}
+
+// resolveAgentEnvironment computes the environment the start paths give
+// the agent, with beads.AgentEnv and the same options they pass.
+func resolveAgentEnvironment(townRoot string, info RoleInfo, roleConfig *beads.RoleConfig) *agentEnvironment {
+	identity := session.AgentIdentity{Role: session.Role(info.Role), Rig: info.Rig, Name: info.Polecat}
+	sessionName := identity.SessionName()
+	if info.Role == RoleBoot {
+		sessionName = boot.SessionName
+	}
+	env := beads.AgentEnv(config.AgentEnvConfig{
+		Role:          string(info.Role),
+		Rig:           info.Rig,
+		AgentName:     info.Polecat,
+		TownRoot:      townRoot,
+		BeadsNoDaemon: info.Role == RolePolecat || info.Role == RoleCrew || info.Role == RoleRefinery,
+	}, sessionName, roleConfig)
+
+	ae := &agentEnvironment{
+		Address: info.ActorString(),
+		Session: sessionName,
+		Env:     env,
+		Paths: map[string]string{
+			"town_root":        townRoot,
+			"town_settings":    config.TownSettingsPath(townRoot),
+			"messaging_config": config.MessagingConfigPath(townRoot),
+		},
+	}
+	if home := getRoleHome(info.Role, info.Rig, info.Polecat, townRoot); home != "" {
+		ae.Paths["work_dir"] = home
+		ae.Paths["beads_dir"] = beads.ResolveBeadsDir(home)
+	} else {
+		ae.Paths["beads_dir"] = beads.GetTownBeadsPath(townRoot)
+	}
+	if info.Rig != "" {
+		ae.Paths["rig_settings"] = config.RigSettingsPath(filepath.Join(townRoot, info.Rig))
+	}
+	return ae
+}
+
+func printAgentEnvironment(ae *agentEnvironment, format string) error {
+	if format == "json" {
+		data, err := json.MarshalIndent(ae, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("# %s (session %s)\n", ae.Address, ae.Session)
+	for _, k := range sortedKeys(ae.Paths) {
+		fmt.Printf("# %s: %s\n", k, ae.Paths[k])
+	}
+	for _, k := range sortedKeys(ae.Env) {
+		if format == "dotenv" {
+			fmt.Printf("%s=%s\n", k, dotenvQuote(ae.Env[k]))
+		} else {
+			fmt.Println(exportStatement("sh", k, ae.Env[k]))
+		}
+	}
+	return nil
+}
+
+// dotenvQuote quotes v for a .env file when it isn't a plain word.
+func dotenvQuote(v string) string {
+	if v != "" && !strings.ContainsAny(v, " \t\n\"'#$\\`") {
+		return v
+	}
+	return strconv.Quote(v)
+}
+
+// envDrift is one difference between an agent's expected environment and
+// its session's.
+type envDrift struct {
+	Key        string
+	Expected   string
+	Actual     string
+	Missing    bool // Expected, but not set in the session
+	Unexpected bool // A GongShow variable the agent shouldn't have
+}
+
+// managedEnvPrefixes are the variables GongShow sets on sessions; a session
+// variable with one of these prefixes that the agent shouldn't have is
+// drift.
+var managedEnvPrefixes = []string{"GT_", "BD_", "BEADS_"}
+
+// diffAgentEnv compares an agent's expected environment with its session's,
+// sorted by key.
+func diffAgentEnv(expected, actual map[string]string) []envDrift {
+	var drift []envDrift
+	for _, k := range sortedKeys(expected) {
+		v, ok := actual[k]
+		switch {
+		case !ok:
+			drift = append(drift, envDrift{Key: k, Expected: expected[k], Missing: true})
+		case v != expected[k]:
+			drift = append(drift, envDrift{Key: k, Expected: expected[k], Actual: v})
+		}
+	}
+	for _, k := range sortedKeys(actual) {
+		if _, ok := expected[k]; ok {
+			continue
+		}
+		for _, prefix := range managedEnvPrefixes {
+			if strings.HasPrefix(k, prefix) {
+				drift = append(drift, envDrift{Key: k, Actual: actual[k], Unexpected: true})
+				break
+			}
+		}
+	}
+	sort.SliceStable(drift, func(i, j int) bool { return drift[i].Key < drift[j].Key })
+	return drift
+}
+
+func runEnvDiff(ae *agentEnvironment) error {
+	actual, err := tmux.NewTmux().GetAllEnvironment(ae.Session)
+	if err != nil {
+		return fmt.Errorf("reading environment of session %s (is %s running?): %w", ae.Session, ae.Address, err)
+	}
+
+	drift := diffAgentEnv(ae.Env, actual)
+	if len(drift) == 0 {
+		fmt.Printf("%s %s matches (%d variables)\n", style.Success.Render("✓"), ae.Session, len(ae.Env))
+		return nil
+	}
+
+	fmt.Printf("%s %s has drifted from %s:\n", style.Warning.Render("⚠"), ae.Session, ae.Address)
+	for _, d := range drift {
+		switch {
+		case d.Missing:
+			fmt.Printf("  %s %s (expected %q)\n", style.Error.Render("- missing"), d.Key, d.Expected)
+		case d.Unexpected:
+			fmt.Printf("  %s %s=%q\n", style.Warning.Render("+ unexpected"), d.Key, d.Actual)
+		default:
+			fmt.Printf("  %s %s=%q (expected %q)\n", style.Warning.Render("~ differs"), d.Key, d.Actual, d.Expected)
+		}
+	}
+	fmt.Println()
+	fmt.Println("Sessions pick up a corrected environment when restarted.")
+	return NewSilentExit(1)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+)
+
+func TestResolveAgentEnvironment(t *testing.T) {
+	townRoot := setupTestTownForConfig(t)
+
+	tests := []struct {
+		address     string
+		wantSession string
+		wantEnv     map[string]string
+		wantWorkDir string
+	}{
+		{
+			address:     "gongshow/polecats/Toast",
+			wantSession: "gt-gongshow-Toast",
+			wantEnv: map[string]string{
+				"GT_ROLE":          "polecat",
+				"GT_RIG":           "gongshow",
+				"GT_POLECAT":       "Toast",
+				"BD_ACTOR":         "gongshow/polecats/Toast",
+				"GIT_AUTHOR_NAME":  "Toast",
+				"GT_ROOT":          townRoot,
+				"BEADS_AGENT_NAME": "gongshow/Toast",
+				"BEADS_NO_DAEMON":  "1",
+			},
+			wantWorkDir: filepath.Join(townRoot, "gongshow", "polecats", "Toast", "rig"),
+		},
+		{
+			address:     "gongshow/crew/max",
+			wantSession: "gt-gongshow-crew-max",
+			wantEnv: map[string]string{
+				"GT_ROLE":          "crew",
+				"GT_RIG":           "gongshow",
+				"GT_CREW":          "max",
+				"BD_ACTOR":         "gongshow/crew/max",
+				"GIT_AUTHOR_NAME":  "max",
+				"GT_ROOT":          townRoot,
+				"BEADS_AGENT_NAME": "gongshow/max",
+				"BEADS_NO_DAEMON":  "1",
+			},
+			wantWorkDir: filepath.Join(townRoot, "gongshow", "crew", "max", "rig"),
+		},
+		{
+			address:     "mayor",
+			wantSession: "hq-mayor",
+			wantEnv: map[string]string{
+				"GT_ROLE":         "mayor",
+				"BD_ACTOR":        "mayor",
+				"GIT_AUTHOR_NAME": "mayor",
+				"GT_ROOT":         townRoot,
+			},
+			wantWorkDir: filepath.Join(townRoot, "mayor"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			info, err := roleInfoFromAddress(tt.address, townRoot)
+			if err != nil {
+				t.Fatalf("roleInfoFromAddress: %v", err)
+			}
+			ae := resolveAgentEnvironment(townRoot, info, nil)
+			if ae.Address != tt.address {
+				t.Errorf("Address = %q, want %q", ae.Address, tt.address)
+			}
+			if ae.Session != tt.wantSession {
+				t.Errorf("Session = %q, want %q", ae.Session, tt.wantSession)
+			}
+			if !reflect.DeepEqual(ae.Env, tt.wantEnv) {
+				t.Errorf("Env = %v\nwant %v", ae.Env, tt.wantEnv)
+			}
+			if got := ae.Paths["work_dir"]; got != tt.wantWorkDir {
+				t.Errorf("work_dir = %q, want %q", got, tt.wantWorkDir)
+			}
+			if ae.Paths["beads_dir"] == "" {
+				t.Error("beads_dir not set")
+			}
+		})
+	}
+}
+
+func TestResolveAgentEnvironment_FromCwdAndRoleConfig(t *testing.T) {
+	townRoot := setupTestTownForConfig(t)
+
+	info := detectRole(filepath.Join(townRoot, "gongshow", "polecats", "Toast", "rig"), townRoot)
+	roleConfig := &beads.RoleConfig{EnvVars: map[string]string{"GT_SCRATCH": "{town}/{rig}/scratch/{name}"}}
+	ae := resolveAgentEnvironment(townRoot, info, roleConfig)
+
+	if ae.Address != "gongshow/polecats/Toast" {
+		t.Errorf("Address from cwd = %q", ae.Address)
+	}
+	if got, want := ae.Env["GT_SCRATCH"], filepath.Join(townRoot, "gongshow", "scratch", "Toast"); got != want {
+		t.Errorf("GT_SCRATCH = %q, want %q", got, want)
+	}
+}
+
+func TestRoleInfoFromAddressInvalid(t *testing.T) {
+	for _, address := range []string{"witness", "gongshow/polecats", "gongshow/crew/", "nobody"} {
+		if _, err := roleInfoFromAddress(address, "/town"); err == nil {
+			t.Errorf("roleInfoFromAddress(%q) succeeded, want error", address)
+		}
+	}
+}
+
+func TestDiffAgentEnv(t *testing.T) {
+	expected := map[string]string{"GT_ROLE": "crew", "GT_RIG": "gongshow", "BD_ACTOR": "gongshow/crew/max"}
+	actual := map[string]string{
+		"GT_ROLE":   "polecat",
+		"BD_ACTOR":  "gongshow/crew/max",
+		"GT_CREW":   "max",
+		"BEADS_DIR": "/tmp/.beads",
+		"TERM":      "screen",
+	}
+
+	got := diffAgentEnv(expected, actual)
+	want := []envDrift{
+		{Key: "BEADS_DIR", Actual: "/tmp/.beads", Unexpected: true},
+		{Key: "GT_CREW", Actual: "max", Unexpected: true},
+		{Key: "GT_RIG", Expected: "gongshow", Missing: true},
+		{Key: "GT_ROLE", Expected: "crew", Actual: "polecat"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffAgentEnv =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if drift := diffAgentEnv(expected, expected); len(drift) != 0 {
+		t.Errorf("diffAgentEnv(same) = %+v, want none", drift)
+	}
+}
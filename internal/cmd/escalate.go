@@ -55,6 +55,7 @@ Examples:
   gt escalate list                          # Show open escalations
   gt escalate ack hq-abc123                 # Acknowledge
   gt escalate close hq-abc123 --reason "Fixed in commit abc"
+  gt escalate link hq-abc123 gt-xyz         # Link a related bead
   gt escalate stale                         # Re-escalate stale escalations`,
 }
 
@@ -101,6 +102,33 @@ Examples:
 	RunE: runEscalateClose,
 }
 
+var escalateLinkCmd = &cobra.Command{
+	Use:   "link <escalation-id> <bead-id>",
+	Short: "Link a related bead to an escalation",
+	Long: `Record a bead as related work for an escalation.
+
+An escalation can relate to several beads (e.g., the failing test, the CI
+bead and the on-call bead). Linked beads are listed by 'gt escalate show'.
+Linking an already-linked bead does nothing.
+
+Examples:
+  gt escalate link hq-abc123 gt-flaky-test
+  gt escalate link hq-abc123 gt-ci-outage`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEscalateLink,
+}
+
+var escalateUnlinkCmd = &cobra.Command{
+	Use:   "unlink <escalation-id> <bead-id>",
+	Short: "Unlink a bead from an escalation",
+	Long: `Remove a bead from an escalation's linked beads.
+
+Examples:
+  gt escalate unlink hq-abc123 gt-ci-outage`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEscalateUnlink,
+}
+
 var escalateStaleCmd = &cobra.Command{
 	Use:   "stale",
 	Short: "Re-escalate stale unacknowledged escalations",
@@ -135,6 +163,24 @@ Examples:
 	RunE: runEscalateShow,
 }
 
+var escalateReportCmd = &cobra.Command{
+	Use:   "report <escalation-id>",
+	Short: "Show how an escalation's notifications were delivered",
+	Long: `Show the delivery report for an escalation's external notifications.
+
+Each channel the escalation was routed to (email, sms, slack, teams, log) is
+listed with its outcome. Deferred sends count as delivered; they were spooled
+by quiet hours or a rate limit and will be retried.
+
+Reports are kept in .beads/notify-reports/<escalation-id>.json.
+
+Examples:
+  gt escalate report hq-abc123
+  gt escalate report hq-abc123 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEscalateReport,
+}
+
 func init() {
 	// Main escalate command flags
 	escalateCmd.Flags().StringVarP(&escalateSeverity, "severity", "s", "medium", "Severity level: critical, high, medium, low")
@@ -159,12 +205,18 @@ func init() {
 	// Show subcommand flags
 	escalateShowCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
 
+	// Report subcommand flags
+	escalateReportCmd.Flags().BoolVar(&escalateJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	escalateCmd.AddCommand(escalateListCmd)
 	escalateCmd.AddCommand(escalateAckCmd)
 	escalateCmd.AddCommand(escalateCloseCmd)
+	escalateCmd.AddCommand(escalateLinkCmd)
+	escalateCmd.AddCommand(escalateUnlinkCmd)
 	escalateCmd.AddCommand(escalateStaleCmd)
 	escalateCmd.AddCommand(escalateShowCmd)
+	escalateCmd.AddCommand(escalateReportCmd)
 
 	rootCmd.AddCommand(escalateCmd)
 }
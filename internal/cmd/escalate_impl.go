@@ -2,8 +2,10 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -110,13 +112,20 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 			msg.Priority = mail.PriorityLow
 		}
 
-		if err := router.Send(msg); err != nil {
+		if err := router.SendContext(cmd.Context(), msg); err != nil {
 			style.PrintWarning("failed to send to %s: %v", target, err)
 		}
 	}
 
 	// Process external notification actions (email:, sms:, slack, log)
-	executeExternalActions(actions, escalationConfig, townRoot, issue.ID, severity, description)
+	for _, result := range executeExternalActions(actions, escalationConfig, townRoot, escalationNotification(issue, fields)) {
+		// Remember where Slack posted so ack/close can update the message
+		if result.Slack != nil {
+			if err := bd.SetEscalationSlackRef(issue.ID, result.Slack.Channel, result.Slack.TS); err != nil {
+				style.PrintWarning("failed to record Slack message for %s: %v", issue.ID, err)
+			}
+		}
+	}
 
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
@@ -125,7 +134,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	if escalateSource != "" {
 		payload["source"] = escalateSource
 	}
-	if err := events.LogFeed(events.TypeEscalationSent, agentID, payload); err != nil {
+	if err := events.LogFeedContext(cmd.Context(), events.TypeEscalationSent, agentID, payload); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to log escalation event: %v\n", err)
 	}
 
@@ -233,7 +242,7 @@ func runEscalateAck(cmd *cobra.Command, args []string) error {
 	}
 
 	// Log to activity feed
-	if err := events.LogFeed(events.TypeEscalationAcked, ackedBy, map[string]interface{}{
+	if err := events.LogFeedContext(cmd.Context(), events.TypeEscalationAcked, ackedBy, map[string]interface{}{
 		"escalation_id": escalationID,
 		"acked_by":      ackedBy,
 	}); err != nil {
@@ -241,6 +250,47 @@ func runEscalateAck(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("%s Escalation acknowledged: %s\n", style.Bold.Render("✓"), escalationID)
+	ackedAt := time.Now()
+	updateEscalationSlack(townRoot, bd, escalationID, func(webhookURL string, ref *notify.SlackRef, n *notify.Notification) *notify.Result {
+		return notify.UpdateSlackForAck(webhookURL, ref, n, ackedBy, ackedAt)
+	})
+	return nil
+}
+
+func runEscalateLink(cmd *cobra.Command, args []string) error {
+	escalationID, beadID := args[0], args[1]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	if _, err := bd.Show(beadID); err != nil {
+		return fmt.Errorf("looking up bead %s: %w", beadID, err)
+	}
+	if err := bd.LinkBead(escalationID, beadID); err != nil {
+		return fmt.Errorf("linking bead: %w", err)
+	}
+
+	fmt.Printf("%s Linked %s to escalation %s\n", style.Bold.Render("✓"), beadID, escalationID)
+	return nil
+}
+
+func runEscalateUnlink(cmd *cobra.Command, args []string) error {
+	escalationID, beadID := args[0], args[1]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	if err := bd.UnlinkBead(escalationID, beadID); err != nil {
+		return fmt.Errorf("unlinking bead: %w", err)
+	}
+
+	fmt.Printf("%s Unlinked %s from escalation %s\n", style.Bold.Render("✓"), beadID, escalationID)
 	return nil
 }
 
@@ -264,7 +314,7 @@ func runEscalateClose(cmd *cobra.Command, args []string) error {
 	}
 
 	// Log to activity feed
-	if err := events.LogFeed(events.TypeEscalationClosed, closedBy, map[string]interface{}{
+	if err := events.LogFeedContext(cmd.Context(), events.TypeEscalationClosed, closedBy, map[string]interface{}{
 		"escalation_id": escalationID,
 		"closed_by":     closedBy,
 		"reason":        escalateCloseReason,
@@ -274,6 +324,11 @@ func runEscalateClose(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("%s Escalation closed: %s\n", style.Bold.Render("✓"), escalationID)
 	fmt.Printf("  Reason: %s\n", escalateCloseReason)
+	closedAt := time.Now()
+	updateEscalationSlack(townRoot, bd, escalationID, func(webhookURL string, ref *notify.SlackRef, n *notify.Notification) *notify.Result {
+		return notify.UpdateSlackForClose(webhookURL, ref, n, closedBy, escalateCloseReason, closedAt)
+	})
+	closeEscalationOpsGenie(townRoot, bd, escalationID)
 	return nil
 }
 
@@ -381,13 +436,13 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 					msg.Priority = mail.PriorityLow
 				}
 
-				if err := router.Send(msg); err != nil {
+				if err := router.SendContext(cmd.Context(), msg); err != nil {
 					style.PrintWarning("failed to send reescalation to %s: %v", target, err)
 				}
 			}
 
 			// Log to activity feed
-			_ = events.LogFeed(events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
+			_ = events.LogFeedContext(cmd.Context(), events.TypeEscalationSent, reescalatedBy, map[string]interface{}{
 				"escalation_id":    result.ID,
 				"reescalated":      true,
 				"old_severity":     result.OldSeverity,
@@ -484,19 +539,20 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 
 	if escalateJSON {
 		data := map[string]interface{}{
-			"id":          issue.ID,
-			"title":       issue.Title,
-			"status":      issue.Status,
-			"created_at":  issue.CreatedAt,
-			"severity":    fields.Severity,
-			"reason":      fields.Reason,
-			"escalatedBy": fields.EscalatedBy,
-			"escalatedAt": fields.EscalatedAt,
-			"ackedBy":     fields.AckedBy,
-			"ackedAt":     fields.AckedAt,
-			"closedBy":    fields.ClosedBy,
+			"id":           issue.ID,
+			"title":        issue.Title,
+			"status":       issue.Status,
+			"created_at":   issue.CreatedAt,
+			"severity":     fields.Severity,
+			"reason":       fields.Reason,
+			"escalatedBy":  fields.EscalatedBy,
+			"escalatedAt":  fields.EscalatedAt,
+			"ackedBy":      fields.AckedBy,
+			"ackedAt":      fields.AckedAt,
+			"closedBy":     fields.ClosedBy,
 			"closedReason": fields.ClosedReason,
-			"relatedBead": fields.RelatedBead,
+			"relatedBead":  fields.RelatedBead,
+			"linkedBeads":  fields.LinkedBeads,
 		}
 		out, _ := json.MarshalIndent(data, "", "  ")
 		fmt.Println(string(out))
@@ -523,6 +579,9 @@ func runEscalateShow(cmd *cobra.Command, args []string) error {
 	if fields.RelatedBead != "" {
 		fmt.Printf("  Related: %s\n", fields.RelatedBead)
 	}
+	if len(fields.LinkedBeads) > 0 {
+		fmt.Printf("  Linked: %s\n", strings.Join(fields.LinkedBeads, ", "))
+	}
 
 	return nil
 }
@@ -545,65 +604,187 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
-// executeExternalActions processes external notification actions (email:, sms:, slack, log).
-// Sends actual notifications via the notify package.
-func executeExternalActions(actions []string, cfg *config.EscalationConfig, townRoot, escalationID, severity, description string) {
-	// Build notification object
-	n := &notify.Notification{
-		ID:        escalationID,
-		Severity:  severity,
-		Title:     description,
-		Source:    detectSender(),
-		Timestamp: time.Now(),
+func runEscalateReport(cmd *cobra.Command, args []string) error {
+	escalationID := args[0]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
 	}
 
-	for _, action := range actions {
+	report, err := notify.LoadReport(beads.ResolveBeadsDir(townRoot), escalationID)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no delivery report for %s (no external notifications were sent)", escalationID)
+		}
+		return err
+	}
+
+	if escalateJSON {
+		out, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(out))
+		return nil
+	}
+
+	fmt.Printf("Delivery report: %s\n", report.EscalationID)
+	fmt.Printf("  Sent: %s\n", report.At.Local().Format(time.RFC3339))
+	fmt.Printf("  Delivered: %d of %d\n", report.SuccessCount, report.SuccessCount+report.FailureCount)
+	for _, result := range report.Results {
 		switch {
-		case strings.HasPrefix(action, "email:"):
-			if cfg.Contacts.HumanEmail == "" {
-				style.PrintWarning("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action)
-			} else {
-				result := notify.SendEmail(cfg.Contacts.HumanEmail, n)
-				if result.Success {
-					fmt.Printf("  📧 %s\n", result.Message)
-				} else {
-					style.PrintWarning("email: %s", result.Message)
-				}
-			}
+		case result.Deferred:
+			fmt.Printf("  ⏸  %s: %s\n", result.Channel, result.Message)
+		case result.Success:
+			fmt.Printf("  %s %s: %s\n", style.SuccessPrefix, result.Channel, result.Message)
+		default:
+			fmt.Printf("  %s %s: %s\n", style.ErrorPrefix, result.Channel, result.Message)
+		}
+	}
+	if !report.AllSucceeded() {
+		fmt.Printf("  Failed: %s\n", strings.Join(report.FailedChannels(), ", "))
+	}
+	return nil
+}
 
-		case strings.HasPrefix(action, "sms:"):
-			if cfg.Contacts.HumanSMS == "" {
-				style.PrintWarning("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action)
-			} else {
-				result := notify.SendSMS(cfg.Contacts.HumanSMS, n)
-				if result.Success {
-					fmt.Printf("  📱 %s\n", result.Message)
-				} else {
-					style.PrintWarning("sms: %s", result.Message)
-				}
-			}
+// executeExternalActions processes external notification actions (email:, sms:, slack, teams, log).
+// Sends go through notify.SendAll so settings/notify.json rate limits and
+// quiet hours apply; deferred sends are spooled and retried by the daemon.
+// Returns the send results.
+func executeExternalActions(actions []string, cfg *config.EscalationConfig, townRoot string, n *notify.Notification) []*notify.Result {
+	targets, warnings := notify.EscalationTargets(actions, cfg.Contacts)
+	for _, w := range warnings {
+		style.PrintWarning("%s", w)
+	}
 
-		case action == "slack":
-			if cfg.Contacts.SlackWebhook == "" {
-				style.PrintWarning("slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
-			} else {
-				result := notify.SendSlack(cfg.Contacts.SlackWebhook, n)
-				if result.Success {
-					fmt.Printf("  💬 %s\n", result.Message)
-				} else {
-					style.PrintWarning("slack: %s", result.Message)
-				}
-			}
+	if len(targets) == 0 {
+		return nil
+	}
 
-		case action == "log":
-			result := notify.WriteLog(townRoot, n)
-			if result.Success {
-				fmt.Printf("  📝 %s\n", result.Message)
-			} else {
-				style.PrintWarning("log: %s", result.Message)
+	report := notify.Notify(n, notifyChannels(townRoot, targets, n))
+	if err := notify.SaveReport(beads.ResolveBeadsDir(townRoot), report); err != nil {
+		style.PrintWarning("failed to save delivery report: %v", err)
+	}
+	results := report.Results
+	for _, result := range results {
+		switch {
+		case result.Deferred:
+			fmt.Printf("  ⏸  %s: %s\n", result.Channel, result.Message)
+		case result.Success:
+			fmt.Printf("  %s %s\n", channelEmoji(result.Channel), result.Message)
+		default:
+			style.PrintWarning("%s: %s", result.Channel, result.Message)
+		}
+	}
+	return results
+}
+
+// notifyChannels returns one send per target for notify.Notify. Sends go
+// through a shared dispatcher so notify.json policy applies to each.
+func notifyChannels(townRoot string, targets []notify.Target, n *notify.Notification) []func() *notify.Result {
+	d, err := notify.NewDispatcher(townRoot)
+	if err != nil {
+		return []func() *notify.Result{func() *notify.Result {
+			return &notify.Result{Channel: "config", Success: false, Error: err, Message: err.Error()}
+		}}
+	}
+	channels := make([]func() *notify.Result, len(targets))
+	for i, t := range targets {
+		channels[i] = func() *notify.Result { return d.Send(t, n) }
+	}
+	return channels
+}
+
+// slackUpdateFunc is notify.UpdateSlackForAck or UpdateSlackForClose with the
+// lifecycle details bound.
+type slackUpdateFunc func(webhookURL string, ref *notify.SlackRef, n *notify.Notification) *notify.Result
+
+// updateEscalationSlack reflects an ack or close in Slack. It runs when the
+// escalation has a recorded Slack message, or when its severity route posts
+// to a Slack webhook (which gets a follow-up message instead of an edit).
+func updateEscalationSlack(townRoot string, bd *beads.Beads, escalationID string, update slackUpdateFunc) {
+	issue, fields, err := bd.GetEscalationBead(escalationID)
+	if err != nil || issue == nil {
+		return
+	}
+
+	var ref *notify.SlackRef
+	if fields.SlackChannel != "" && fields.SlackTS != "" {
+		ref = &notify.SlackRef{Channel: fields.SlackChannel, TS: fields.SlackTS}
+	}
+
+	var webhookURL string
+	if cfg, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot)); err == nil {
+		for _, action := range cfg.GetRouteForSeverity(fields.Severity) {
+			if action == "slack" {
+				webhookURL = cfg.Contacts.SlackWebhook
 			}
 		}
 	}
+	if ref == nil && webhookURL == "" {
+		return
+	}
+
+	printEscalationUpdate(update(webhookURL, ref, escalationNotification(issue, fields)))
+}
+
+// closeEscalationOpsGenie closes the escalation's OpsGenie alert, if its
+// severity route raised one.
+func closeEscalationOpsGenie(townRoot string, bd *beads.Beads, escalationID string) {
+	issue, fields, err := bd.GetEscalationBead(escalationID)
+	if err != nil || issue == nil {
+		return
+	}
+	cfg, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil || !slices.Contains(cfg.GetRouteForSeverity(fields.Severity), "opsgenie") {
+		return
+	}
+	printEscalationUpdate(notify.CloseOpsGenieAlert(notify.LoadOpsGenieConfig(), escalationNotification(issue, fields)))
+}
+
+// escalationNotification builds the notification an escalation is sent as.
+// Ack and close rebuild it from the bead, so a Slack edit keeps the original
+// message content.
+func escalationNotification(issue *beads.Issue, fields *beads.EscalationFields) *notify.Notification {
+	body := issue.Title
+	if fields.Reason != "" {
+		body += "\n\nReason: " + fields.Reason
+	}
+	n := &notify.Notification{
+		ID:          issue.ID,
+		Severity:    fields.Severity,
+		Title:       issue.Title,
+		Body:        body,
+		Source:      fields.EscalatedBy,
+		RelatedBead: fields.RelatedBead,
+	}
+	if t, err := time.Parse(time.RFC3339, fields.EscalatedAt); err == nil {
+		n.Timestamp = t
+	}
+	return n
+}
+
+// printEscalationUpdate reports the result of updating a channel.
+func printEscalationUpdate(result *notify.Result) {
+	if result.Success {
+		fmt.Printf("  %s %s\n", channelEmoji(result.Channel), result.Message)
+	} else {
+		style.PrintWarning("%s: %s", result.Channel, result.Message)
+	}
+}
+
+// channelEmoji returns the icon shown for a successful send on a channel.
+func channelEmoji(channel string) string {
+	switch channel {
+	case notify.ChannelEmail:
+		return "📧"
+	case notify.ChannelSMS:
+		return "📱"
+	case notify.ChannelSlack, notify.ChannelTeams:
+		return "💬"
+	case notify.ChannelOpsGenie:
+		return "📟"
+	default:
+		return "📝"
+	}
 }
 
 func formatEscalationMailBody(beadID, severity, reason, from, related string) string {
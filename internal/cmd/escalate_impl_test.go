@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+)
+
+func TestEscalationNotificationBody(t *testing.T) {
+	issue := &beads.Issue{ID: "hq-esc-1", Title: "Refinery stuck"}
+	fields := &beads.EscalationFields{
+		Severity:    "high",
+		Reason:      "Merge queue has not advanced in 2h",
+		EscalatedBy: "gongshow/witness",
+		EscalatedAt: "2026-01-15T10:00:00Z",
+	}
+
+	n := escalationNotification(issue, fields)
+	if !strings.Contains(n.Body, "Refinery stuck") {
+		t.Errorf("Body = %q, want the escalation description", n.Body)
+	}
+	if !strings.Contains(n.Body, fields.Reason) {
+		t.Errorf("Body = %q, want the reason", n.Body)
+	}
+	if n.Source != "gongshow/witness" || n.Timestamp.IsZero() {
+		t.Errorf("unexpected notification: %+v", n)
+	}
+}
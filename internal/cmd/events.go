@@ -0,0 +1,558 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/session"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+// Events command flags
+var (
+	eventsTailFollow bool
+	eventsTailTypes  []string
+	eventsTailActor  string
+	eventsTailCorr   string
+	eventsTailSince  string
+	eventsTailLines  int
+	eventsTailJSON   bool
+	eventsTailSource string
+
+	eventsQueryTypes   []string
+	eventsQueryActor   string
+	eventsQueryCorr    string
+	eventsQuerySince   string
+	eventsQueryUntil   string
+	eventsQueryPayload []string
+	eventsQueryFormat  string
+	eventsQueryLimit   int
+	eventsQueryReverse bool
+	eventsQuerySource  string
+
+	eventsStatsSince   string
+	eventsStatsGroupBy string
+	eventsStatsTop     int
+	eventsStatsJSON    bool
+)
+
+var eventsCmd = &cobra.Command{
+	Use:     "events",
+	GroupID: GroupDiag,
+	Short:   "Inspect the raw town events log",
+	RunE:    requireSubcommand,
+	Long: `Inspect the raw town events log (.events.jsonl and its rotated archives).
+
+Feed-visible events go to .events.jsonl, which is rotated and pruned.
+Audit-visible events go to .audit.jsonl in the town root, which is only
+ever appended to; it also records the OS user and PID behind destructive
+operations (kill, doctor fix, queue release). tail and query read the feed
+log unless given --source audit.
+
+With per_rig_events set in settings/config.json, rig events are written to
+<rig>/.events.jsonl instead; every command reads the town and rig logs as
+one, merged in timestamp order.
+
+Commands:
+  tail         Show recent events, optionally following new ones
+  query        Search the full log with time, type, actor and payload filters
+  stats        Summarise activity: counts, busiest actors, daily histogram
+  queue-stats  Per-worker claims, completions and failures on a work queue
+  export       Export the log to SQLite or CSV for ad hoc analysis
+  hooks        Inspect the hooks that run when matching events are logged
+  replay       Replay recorded events through the hooks
+  migrate      Split the town log into per-rig logs
+  repair       Move corrupt lines out of the logs`,
+}
+
+var eventsTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Show recent events, optionally following new ones",
+	Long: `Show recent events from the town's events log, one per line.
+
+Without --since, the last --lines matching events are shown. With --since,
+every matching event in that window is shown (across rotated archives).
+--follow keeps streaming new events until interrupted, surviving log
+rotation.
+
+--actor accepts mail-style wildcards: '*' matches one path segment, so
+'gongshow/*' matches gongshow/Toast but not gongshow/crew/max.
+
+Malformed lines are skipped and counted; the count is reported on exit.
+
+Examples:
+  gt events tail                              # Last 20 events
+  gt events tail -f                           # Follow new events
+  gt events tail -f --type sling,mail         # Only sling and mail events
+  gt events tail --actor 'gongshow/*' --since 10m
+  gt events tail -f --json | jq .payload      # Raw JSON lines
+  gt events tail -f --correlation 3f9a0c1d
+  gt events tail --source audit               # The audit log`,
+	Args: cobra.NoArgs,
+	RunE: runEventsTail,
+}
+
+var eventsQueryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Search the full log with time, type, actor and payload filters",
+	Long: `Search the whole events log, including rotated archives.
+
+--since and --until take a duration back from now (10m, 24h, 7d) or a
+timestamp (2024-01-15, 2024-01-15T09:30, or RFC3339). --payload filters
+match key=glob against the event payload, where '*' matches any run of
+characters; repeat it to require several keys.
+
+Use --reverse --limit N to get the last N matching events, newest first.
+
+Each command stamps the events it causes with a correlation ID (shown in
+the CORRELATION column, abbreviated); a polecat spawned by gt sling keeps
+the sling's ID. --correlation takes an ID or prefix and shows one such
+chain, e.g. a sling, its spawn, and the polecat's done.
+
+Examples:
+  gt events query --type session_death --since 2024-01-15T00:00 --until 2024-01-16
+  gt events query --actor mayor --since 1h
+  gt events query --payload 'session=gt-gongshow-*' --reverse --limit 20
+  gt events query --type mail --format csv > mail.csv
+  gt events query --correlation 3f9a0c1d
+  gt events query --source audit --type doctor_fix,queue_release`,
+	Args: cobra.NoArgs,
+	RunE: runEventsQuery,
+}
+
+var eventsStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Summarise activity: counts, busiest actors, daily histogram",
+	Long: `Summarise town activity from the events log in a single pass.
+
+Shows event counts grouped by type (with the busiest actors for each),
+by actor, or by day, plus a per-day sparkline. Derived metrics:
+  - Average polecat lifetime (spawn to session_death of the same session)
+  - Escalations by severity
+
+Examples:
+  gt events stats                     # Last 7 days, grouped by type
+  gt events stats --since 30d --group-by actor
+  gt events stats --group-by day --json`,
+	Args: cobra.NoArgs,
+	RunE: runEventsStats,
+}
+
+func init() {
+	eventsTailCmd.Flags().BoolVarP(&eventsTailFollow, "follow", "f", false, "Keep streaming new events")
+	eventsTailCmd.Flags().StringSliceVar(&eventsTailTypes, "type", nil, "Only show these event types (comma-separated)")
+	eventsTailCmd.Flags().StringVar(&eventsTailActor, "actor", "", "Only show events by actors matching this pattern (e.g., 'gongshow/*')")
+	eventsTailCmd.Flags().StringVar(&eventsTailCorr, "correlation", "", "Only show events with this correlation ID (or prefix)")
+	eventsTailCmd.Flags().StringVar(&eventsTailSince, "since", "", "Show events since duration (e.g., 10m, 1h, 7d)")
+	eventsTailCmd.Flags().IntVarP(&eventsTailLines, "lines", "n", 20, "Number of past events to show (ignored with --since)")
+	eventsTailCmd.Flags().BoolVar(&eventsTailJSON, "json", false, "Print raw JSON lines")
+	eventsTailCmd.Flags().StringVar(&eventsTailSource, "source", events.SourceFeed, "Log to read: feed or audit")
+
+	eventsQueryCmd.Flags().StringSliceVar(&eventsQueryTypes, "type", nil, "Only show these event types (comma-separated)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryActor, "actor", "", "Only show events by actors matching this pattern (e.g., 'gongshow/*')")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryCorr, "correlation", "", "Only show events with this correlation ID (or prefix)")
+	eventsQueryCmd.Flags().StringVar(&eventsQuerySince, "since", "", "Start of window: duration ago (1h, 7d) or timestamp")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryUntil, "until", "", "End of window: duration ago (1h, 7d) or timestamp")
+	eventsQueryCmd.Flags().StringArrayVar(&eventsQueryPayload, "payload", nil, "Payload filter key=glob (can be used multiple times)")
+	eventsQueryCmd.Flags().StringVar(&eventsQueryFormat, "format", "table", "Output format: table, json, or csv")
+	eventsQueryCmd.Flags().IntVarP(&eventsQueryLimit, "limit", "n", 0, "Maximum number of events (0 for no limit)")
+	eventsQueryCmd.Flags().BoolVar(&eventsQueryReverse, "reverse", false, "Newest first (with --limit: the last N matches)")
+	eventsQueryCmd.Flags().StringVar(&eventsQuerySource, "source", events.SourceFeed, "Log to search: feed or audit")
+
+	eventsStatsCmd.Flags().StringVar(&eventsStatsSince, "since", "7d", "Start of window: duration ago (1h, 7d) or timestamp")
+	eventsStatsCmd.Flags().StringVar(&eventsStatsGroupBy, "group-by", "type", "Group counts by: type, actor, or day")
+	eventsStatsCmd.Flags().IntVar(&eventsStatsTop, "top", events.DefaultStatsTopN, "Busiest actors to show per type")
+	eventsStatsCmd.Flags().BoolVar(&eventsStatsJSON, "json", false, "Output as JSON")
+
+	eventsCmd.AddCommand(eventsTailCmd)
+	eventsCmd.AddCommand(eventsQueryCmd)
+	eventsCmd.AddCommand(eventsStatsCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+func runEventsTail(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	opts := events.StreamOptions{
+		Filter: events.Filter{Types: eventsTailTypes, Correlation: eventsTailCorr},
+		Follow: eventsTailFollow,
+		Source: eventsTailSource,
+	}
+	if eventsTailActor != "" {
+		pattern := eventsTailActor
+		opts.Filter.Actor = func(actor string) bool {
+			return mail.MatchPattern(pattern, actor)
+		}
+	}
+	opts.History = eventsTailLines
+	if eventsTailSince != "" {
+		d, err := parseDuration(eventsTailSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		opts.Filter.Since = time.Now().Add(-d)
+		opts.History = -1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	skipped, err := events.Stream(ctx, townRoot, opts, func(raw string, e events.Event) error {
+		if eventsTailJSON {
+			fmt.Println(raw)
+		} else {
+			fmt.Println(formatEventLine(e))
+		}
+		return nil
+	})
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%s Skipped %d malformed line(s)\n", style.WarningPrefix, skipped)
+	}
+	return err
+}
+
+func runEventsQuery(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	opts := events.QueryOptions{
+		Filter:  events.Filter{Types: eventsQueryTypes, Correlation: eventsQueryCorr},
+		Limit:   eventsQueryLimit,
+		Reverse: eventsQueryReverse,
+		Source:  eventsQuerySource,
+	}
+	if eventsQueryActor != "" {
+		pattern := eventsQueryActor
+		opts.Filter.Actor = func(actor string) bool {
+			return mail.MatchPattern(pattern, actor)
+		}
+	}
+	now := time.Now()
+	if eventsQuerySince != "" {
+		if opts.Filter.Since, err = parseEventTime(eventsQuerySince, now); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if eventsQueryUntil != "" {
+		if opts.Filter.Until, err = parseEventTime(eventsQueryUntil, now); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	for _, p := range eventsQueryPayload {
+		key, glob, ok := strings.Cut(p, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid --payload %q: want key=glob", p)
+		}
+		if opts.Filter.Payload == nil {
+			opts.Filter.Payload = make(map[string]string)
+		}
+		opts.Filter.Payload[key] = glob
+	}
+
+	out, err := newEventWriter(os.Stdout, eventsQueryFormat)
+	if err != nil {
+		return err
+	}
+	skipped, err := events.Query(townRoot, opts, out.write)
+	if err != nil {
+		return err
+	}
+	if err := out.close(); err != nil {
+		return err
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%s Skipped %d malformed line(s)\n", style.WarningPrefix, skipped)
+	}
+	return nil
+}
+
+func runEventsStats(cmd *cobra.Command, args []string) error {
+	switch eventsStatsGroupBy {
+	case "type", "actor", "day":
+	default:
+		return fmt.Errorf("invalid --group-by %q: want type, actor, or day", eventsStatsGroupBy)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	opts := events.StatsOptions{
+		TopN:           eventsStatsTop,
+		PolecatSession: session.PolecatSessionName,
+	}
+	if eventsStatsSince != "" {
+		if opts.Since, err = parseEventTime(eventsStatsSince, time.Now()); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+
+	stats, err := events.CollectStats(townRoot, opts)
+	if err != nil {
+		return err
+	}
+
+	if eventsStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	printEventStats(os.Stdout, stats, eventsStatsGroupBy)
+	return nil
+}
+
+// printEventStats renders stats as text, with the main table grouped by groupBy.
+func printEventStats(w io.Writer, stats *events.Stats, groupBy string) {
+	header := fmt.Sprintf("Events: %d", stats.Total)
+	if !stats.Since.IsZero() {
+		header += fmt.Sprintf(" since %s", stats.Since.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintln(w, style.Bold.Render(header))
+	if stats.Total == 0 {
+		return
+	}
+	if len(stats.Days) > 0 {
+		fmt.Fprintf(w, "  %s  %s\n", sparkline(stats.Days), style.Dim.Render(stats.Days[0].Day+" → "+stats.Days[len(stats.Days)-1].Day))
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	switch groupBy {
+	case "actor":
+		fmt.Fprintln(tw, "ACTOR\tCOUNT")
+		for _, kv := range sortedCounts(stats.ByActor) {
+			fmt.Fprintf(tw, "%s\t%d\n", kv.Key, kv.Count)
+		}
+	case "day":
+		peak := 0
+		for _, d := range stats.Days {
+			peak = max(peak, d.Count)
+		}
+		fmt.Fprintln(tw, "DAY\tCOUNT\t")
+		for _, d := range stats.Days {
+			bar := ""
+			if peak > 0 {
+				bar = strings.Repeat("█", (d.Count*30+peak-1)/peak)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", d.Day, d.Count, bar)
+		}
+	default:
+		fmt.Fprintln(tw, "TYPE\tCOUNT\tTOP ACTORS")
+		for _, kv := range sortedCounts(stats.ByType) {
+			var top []string
+			for _, a := range stats.TopActors[kv.Key] {
+				top = append(top, fmt.Sprintf("%s (%d)", a.Actor, a.Count))
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", kv.Key, kv.Count, strings.Join(top, ", "))
+		}
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(w)
+
+	if lt := stats.PolecatLifetime; lt.Count > 0 {
+		fmt.Fprintf(w, "Polecat lifetime: avg %s over %d polecat(s)\n", lt.Average.Round(time.Second), lt.Count)
+	}
+	if len(stats.EscalationsBySeverity) > 0 {
+		var parts []string
+		for _, kv := range sortedCounts(stats.EscalationsBySeverity) {
+			parts = append(parts, fmt.Sprintf("%s %d", kv.Key, kv.Count))
+		}
+		fmt.Fprintf(w, "Escalations: %s\n", strings.Join(parts, ", "))
+	}
+	if stats.Skipped > 0 {
+		fmt.Fprintf(w, "%s Skipped %d malformed line(s)\n", style.WarningPrefix, stats.Skipped)
+	}
+}
+
+// countEntry is one row of a count table.
+type countEntry struct {
+	Key   string
+	Count int
+}
+
+// sortedCounts orders a count map by count descending, then key.
+func sortedCounts(counts map[string]int) []countEntry {
+	list := make([]countEntry, 0, len(counts))
+	for k, n := range counts {
+		list = append(list, countEntry{k, n})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// sparkline renders per-day counts as a row of block characters.
+func sparkline(days []events.DayCount) string {
+	const ticks = "▁▂▃▄▅▆▇█"
+	blocks := []rune(ticks)
+	peak := 0
+	for _, d := range days {
+		peak = max(peak, d.Count)
+	}
+	var sb strings.Builder
+	for _, d := range days {
+		if d.Count == 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		sb.WriteRune(blocks[d.Count*(len(blocks)-1)/peak])
+	}
+	return sb.String()
+}
+
+// parseEventTime parses an absolute timestamp or a duration before now.
+func parseEventTime(s string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	d, err := parseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a timestamp nor a duration", s)
+	}
+	return now.Add(-d), nil
+}
+
+// eventWriter streams query results in one output format.
+type eventWriter struct {
+	format string
+	tw     *tabwriter.Writer
+	csv    *csv.Writer
+	w      io.Writer
+	n      int
+}
+
+func newEventWriter(w io.Writer, format string) (*eventWriter, error) {
+	ew := &eventWriter{format: format, w: w}
+	switch format {
+	case "table":
+		ew.tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(ew.tw, "TIME\tTYPE\tACTOR\tCORRELATION\tPAYLOAD")
+	case "csv":
+		ew.csv = csv.NewWriter(w)
+		if err := ew.csv.Write([]string{"timestamp", "type", "actor", "source", "visibility", "correlation_id", "payload"}); err != nil {
+			return nil, err
+		}
+	case "json":
+		fmt.Fprint(w, "[")
+	default:
+		return nil, fmt.Errorf("invalid --format %q: want table, json, or csv", format)
+	}
+	return ew, nil
+}
+
+func (ew *eventWriter) write(raw string, e events.Event) error {
+	ew.n++
+	switch ew.format {
+	case "table":
+		ts := e.Timestamp
+		if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+			ts = t.Local().Format("2006-01-02 15:04:05")
+		}
+		_, err := fmt.Fprintf(ew.tw, "%s\t%s\t%s\t%s\t%s\n", ts, e.Type, e.Actor, shortCorrelation(e.CorrelationID), formatEventPayload(e.Payload))
+		return err
+	case "csv":
+		payload := ""
+		if len(e.Payload) > 0 {
+			data, err := json.Marshal(e.Payload)
+			if err != nil {
+				return err
+			}
+			payload = string(data)
+		}
+		return ew.csv.Write([]string{e.Timestamp, e.Type, e.Actor, e.Source, e.Visibility, e.CorrelationID, payload})
+	default:
+		sep := ",\n  "
+		if ew.n == 1 {
+			sep = "\n  "
+		}
+		_, err := fmt.Fprint(ew.w, sep+raw)
+		return err
+	}
+}
+
+func (ew *eventWriter) close() error {
+	switch ew.format {
+	case "table":
+		return ew.tw.Flush()
+	case "csv":
+		ew.csv.Flush()
+		return ew.csv.Error()
+	default:
+		if ew.n > 0 {
+			fmt.Fprint(ew.w, "\n")
+		}
+		_, err := fmt.Fprintln(ew.w, "]")
+		return err
+	}
+}
+
+// formatEventLine renders an event as "time type actor [correlation] payload".
+func formatEventLine(e events.Event) string {
+	ts := e.Timestamp
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		ts = t.Local().Format("15:04:05")
+	}
+	line := fmt.Sprintf("%s %-16s %s", style.Dim.Render(ts), style.Bold.Render(e.Type), e.Actor)
+	if e.CorrelationID != "" {
+		line += " " + style.Dim.Render("["+shortCorrelation(e.CorrelationID)+"]")
+	}
+	if summary := formatEventPayload(e.Payload); summary != "" {
+		line += " " + style.Dim.Render(summary)
+	}
+	return line
+}
+
+// shortCorrelation abbreviates a correlation ID for display; the first 8
+// characters are enough to tell a town's recent chains apart.
+func shortCorrelation(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// formatEventPayload renders a payload as sorted key=value pairs, truncating
+// long values to keep events on one line.
+func formatEventPayload(payload map[string]interface{}) string {
+	keys := make([]string, 0, len(payload))
+	for k := range payload {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := strings.Join(strings.Fields(fmt.Sprint(payload[k])), " ")
+		if r := []rune(v); len(r) > 40 {
+			v = string(r[:39]) + "…"
+		}
+		parts = append(parts, k+"="+v)
+	}
+	return strings.Join(parts, " ")
+}
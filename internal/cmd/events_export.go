@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+// sqliteDriver is the database/sql driver used for SQLite exports. It is
+// pure Go, so builds with CGO_ENABLED=0 (as the FreeBSD release is) can
+// write databases too.
+const sqliteDriver = "sqlite"
+
+var (
+	eventsExportFormat string
+	eventsExportOut    string
+	eventsExportSince  string
+	eventsExportAppend bool
+)
+
+var eventsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the events log to SQLite or CSV",
+	Long: `Export the events log (archives included) for ad hoc analysis.
+
+SQLite exports create an "events" table with typed columns (ts, ts_unix,
+type, actor, source, visibility, schema_version) and the payload as JSON,
+indexed on type and ts_unix. Payload fields can be queried with json_extract:
+
+  SELECT json_extract(payload, '$.rig') AS rig, count(*)
+  FROM events WHERE type = 'session_death' GROUP BY rig;
+
+With --append, events already in the database are skipped using the
+high-water mark stored in its export_state table, so the export can be
+re-run periodically. Without --append, an existing output file is replaced.
+
+Examples:
+  gt events export --format sqlite --out town.db
+  gt events export --format sqlite --out town.db --append
+  gt events export --format csv --since 30d > events.csv`,
+	RunE: runEventsExport,
+}
+
+func init() {
+	eventsExportCmd.Flags().StringVar(&eventsExportFormat, "format", "sqlite", "Output format: sqlite or csv")
+	eventsExportCmd.Flags().StringVarP(&eventsExportOut, "out", "o", "", "Output file (required for sqlite; csv defaults to stdout)")
+	eventsExportCmd.Flags().StringVar(&eventsExportSince, "since", "", "Only export events since duration ago (30d) or timestamp")
+	eventsExportCmd.Flags().BoolVar(&eventsExportAppend, "append", false, "Add only events not yet exported (sqlite)")
+
+	eventsCmd.AddCommand(eventsExportCmd)
+}
+
+func runEventsExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	opts := events.ExportOptions{Append: eventsExportAppend}
+	if eventsExportSince != "" {
+		if opts.Since, err = parseEventTime(eventsExportSince, time.Now()); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+
+	var result *events.ExportResult
+	switch eventsExportFormat {
+	case "sqlite":
+		result, err = exportEventsSQLite(townRoot, opts)
+	case "csv":
+		if eventsExportAppend {
+			return fmt.Errorf("--append is only supported for sqlite exports")
+		}
+		result, err = exportEventsCSV(townRoot, opts)
+	default:
+		return fmt.Errorf("invalid --format %q: want sqlite or csv", eventsExportFormat)
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.Malformed > 0 {
+		fmt.Fprintf(os.Stderr, "%s Skipped %d malformed line(s)\n", style.WarningPrefix, result.Malformed)
+	}
+	if eventsExportOut != "" && eventsExportOut != "-" {
+		msg := fmt.Sprintf("%s Exported %d event(s) to %s", style.Bold.Render("✓"), result.Exported, eventsExportOut)
+		if result.Present > 0 {
+			msg += style.Dim.Render(fmt.Sprintf(" (%d already present)", result.Present))
+		}
+		fmt.Println(msg)
+	}
+	return nil
+}
+
+func exportEventsSQLite(townRoot string, opts events.ExportOptions) (*events.ExportResult, error) {
+	if eventsExportOut == "" || eventsExportOut == "-" {
+		return nil, fmt.Errorf("--out is required for sqlite exports")
+	}
+	if !opts.Append {
+		if err := os.Remove(eventsExportOut); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("replacing %s: %w", eventsExportOut, err)
+		}
+	}
+
+	db, err := sql.Open(sqliteDriver, eventsExportOut)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", eventsExportOut, err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("opening %s: %w", eventsExportOut, err)
+	}
+
+	sink, err := events.NewSQLSink(db)
+	if err != nil {
+		return nil, fmt.Errorf("preparing %s: %w", eventsExportOut, err)
+	}
+	result, err := events.Export(townRoot, opts, sink)
+	if err != nil {
+		_ = sink.Abort()
+		return nil, fmt.Errorf("exporting events: %w", err)
+	}
+	return result, nil
+}
+
+func exportEventsCSV(townRoot string, opts events.ExportOptions) (*events.ExportResult, error) {
+	var w io.Writer = os.Stdout
+	if eventsExportOut != "" && eventsExportOut != "-" {
+		f, err := os.Create(eventsExportOut)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		w = f
+	}
+	result, err := events.Export(townRoot, opts, events.NewCSVSink(w))
+	if err != nil {
+		return nil, fmt.Errorf("exporting events: %w", err)
+	}
+	return result, nil
+}
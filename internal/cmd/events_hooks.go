@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+// eventHookMailSender is the From address of mail sent by event hooks.
+const eventHookMailSender = "event-hooks"
+
+var eventsHooksTestRun bool
+
+var eventsHooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Inspect event-triggered hooks",
+	RunE:  requireSubcommand,
+	Long: `Inspect the event hooks configured in settings/config.json.
+
+Each hook maps an event filter to actions that run whenever a matching event
+is appended to the events log:
+
+  "hooks": [
+    {
+      "name": "page-on-witness-death",
+      "match": {"types": ["session_death"], "actor": "*/witness"},
+      "command": "notify-send \"$GT_EVENT_ACTOR died\"",
+      "mail": {"to": "overseer", "subject": "$GT_EVENT_PAYLOAD_SESSION died"},
+      "notify": {"severity": "critical", "targets": [{"channel": "sms", "address": "+15551234567"}]},
+      "timeout": "10s"
+    }
+  ]
+
+match.actor and match.payload values are globs. Commands run with "sh -c" in
+the town root with the event exported as GT_EVENT_TYPE, GT_EVENT_ACTOR,
+GT_EVENT_TS, GT_EVENT_JSON and GT_EVENT_PAYLOAD_<KEY>. Hooks run in the
+background and never fail the command that logged the event; failures are
+written to logs/event-hooks.log.`,
+}
+
+var eventsHooksTestCmd = &cobra.Command{
+	Use:   "test <fixture.json>",
+	Short: "Dry-run a sample event against the configured hooks",
+	Long: `Show which hooks a sample event would trigger and what they would do.
+
+The fixture is a single event as it appears in .events.jsonl. With --run, the
+matching hooks are executed for real (synchronously) and their results shown.
+
+Examples:
+  gt events hooks test witness-death.json
+  gt events hooks test witness-death.json --run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsHooksTest,
+}
+
+func init() {
+	eventsHooksTestCmd.Flags().BoolVar(&eventsHooksTestRun, "run", false, "Execute the matching hooks instead of only listing them")
+
+	eventsHooksCmd.AddCommand(eventsHooksTestCmd)
+	eventsCmd.AddCommand(eventsHooksCmd)
+
+	events.SetMailSender(sendEventHookMail)
+}
+
+// sendEventHookMail delivers mail for an event hook's mail action.
+func sendEventHookMail(ctx context.Context, townRoot, to, subject, body string) error {
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	return router.SendContext(ctx, mail.NewMessage(eventHookMailSender, to, subject, body))
+}
+
+func runEventsHooksTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading fixture: %w", err)
+	}
+	var e events.Event
+	if err := json.Unmarshal(data, &e); err != nil {
+		return fmt.Errorf("parsing fixture: %w", err)
+	}
+	if e.Type == "" {
+		return fmt.Errorf("fixture has no event type")
+	}
+	if e.Timestamp == "" {
+		e.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if len(settings.Hooks) == 0 {
+		fmt.Printf("%s No hooks configured in settings/config.json\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("Event: %s\n\n", formatEventLine(e))
+
+	matched := make(map[int]bool)
+	for _, i := range events.MatchingHooks(settings.Hooks, e) {
+		matched[i] = true
+	}
+
+	for i, hook := range settings.Hooks {
+		name := events.HookName(hook, i)
+		if !matched[i] {
+			fmt.Printf("  %s %s %s\n", style.Dim.Render("·"), name, style.Dim.Render("(no match)"))
+			continue
+		}
+		fmt.Printf("  %s %s (timeout %s)\n", style.Bold.Render("✓"), style.Bold.Render(name), events.HookTimeout(hook))
+		for _, action := range describeHookActions(hook) {
+			fmt.Printf("      %s\n", action)
+		}
+		if eventsHooksTestRun {
+			if err := events.RunHook(context.Background(), townRoot, hook, e); err != nil {
+				fmt.Printf("      %s %v\n", style.WarningPrefix, err)
+			} else {
+				fmt.Printf("      %s ran successfully\n", style.Bold.Render("✓"))
+			}
+		}
+	}
+
+	if len(matched) > 0 {
+		fmt.Printf("\nEnvironment:\n")
+		for _, kv := range events.HookEnv(e) {
+			if strings.HasPrefix(kv, "GT_EVENT_JSON=") {
+				continue
+			}
+			fmt.Printf("  %s\n", kv)
+		}
+	}
+	if !eventsHooksTestRun && len(matched) > 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("Dry run: use --run to execute the matching hooks"))
+	}
+	return nil
+}
+
+// describeHookActions lists what a hook does, one line per action.
+func describeHookActions(hook config.EventHook) []string {
+	var actions []string
+	if hook.Command != "" {
+		actions = append(actions, "command: "+hook.Command)
+	}
+	if hook.Mail != nil {
+		actions = append(actions, "mail: "+hook.Mail.To)
+	}
+	if hook.Notify != nil {
+		var targets []string
+		for _, t := range hook.Notify.Targets {
+			targets = append(targets, t.Channel)
+		}
+		actions = append(actions, "notify: "+strings.Join(targets, ", "))
+	}
+	if len(actions) == 0 {
+		actions = append(actions, style.Dim.Render("(no actions)"))
+	}
+	return actions
+}
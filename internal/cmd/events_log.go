@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/daemon"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/session"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+var (
+	eventsLogSession string
+	eventsLogReason  string
+)
+
+var eventsLogCmd = &cobra.Command{
+	Use:    "log <event>",
+	Short:  "Record an event (called by tmux hooks)",
+	Hidden: true,
+	Long: `Record an event in the town's events log.
+
+This is called by the tmux hooks the daemon installs, not typically run by
+hand. Supported events:
+
+  session-death   A session closed. Logged as session_death for GongShow
+                  sessions (gt-*, hq-*); other sessions are ignored.
+
+Examples:
+  gt events log session-death --session gt-gongshow-Toast`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"session-death"},
+	RunE:      runEventsLog,
+}
+
+func init() {
+	eventsLogCmd.Flags().StringVar(&eventsLogSession, "session", "", "Tmux session name")
+	eventsLogCmd.Flags().StringVar(&eventsLogReason, "reason", "session closed", "Why the session ended")
+
+	eventsCmd.AddCommand(eventsLogCmd)
+
+	tmux.SetKillRecorder(logSessionKill)
+}
+
+func runEventsLog(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "session-death":
+		return logSessionClosed(eventsLogSession, eventsLogReason)
+	default:
+		return fmt.Errorf("unsupported event %q (supported: session-death)", args[0])
+	}
+}
+
+// logSessionClosed logs a session_death event for a GongShow session.
+// Sessions that aren't GongShow's are ignored.
+func logSessionClosed(sessionName, reason string) error {
+	if sessionName == "" {
+		return fmt.Errorf("--session is required")
+	}
+	identity, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return nil
+	}
+	agent := identity.Address()
+	return events.LogFeed(events.TypeSessionDeath, agent,
+		events.SessionDeathPayload(sessionName, agent, reason, daemon.SessionHookCaller))
+}
+
+// logSessionKill logs an audit kill event for a GongShow session killed by
+// this process, attributed to whoever is running gt. Sessions that aren't
+// GongShow's are ignored.
+func logSessionKill(sessionName, reason string) {
+	identity, err := session.ParseSessionName(sessionName)
+	if err != nil {
+		return
+	}
+	_ = events.LogAudit(events.TypeKill, detectSender(),
+		events.KillPayload(identity.Rig, identity.Address(), reason))
+}
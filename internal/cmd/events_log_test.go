@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+func TestLogSessionClosed(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	if err := logSessionClosed("gt-gongshow-Toast", "session closed"); err != nil {
+		t.Fatalf("logSessionClosed: %v", err)
+	}
+	// Not a GongShow session: nothing is logged.
+	if err := logSessionClosed("scratch", "session closed"); err != nil {
+		t.Fatalf("logSessionClosed(scratch): %v", err)
+	}
+	if err := logSessionClosed("", "session closed"); err == nil {
+		t.Error("logSessionClosed without a session succeeded")
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatalf("reading events: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d events, want 1:\n%s", len(lines), data)
+	}
+	for _, want := range []string{`"type":"session_death"`, `"agent":"gongshow/polecats/Toast"`, `"caller":"tmux"`, `"session":"gt-gongshow-Toast"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("event missing %s: %s", want, lines[0])
+		}
+	}
+}
+
+func TestLogSessionKill(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	t.Setenv("GT_ROLE", "mayor")
+
+	logSessionKill("gt-gongshow-Toast", "session killed")
+	logSessionKill("scratch", "session killed") // not a GongShow session
+
+	data, err := os.ReadFile(filepath.Join(townRoot, events.AuditFile))
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d audit events, want 1:\n%s", len(lines), data)
+	}
+	for _, want := range []string{`"type":"kill"`, `"actor":"mayor/"`, `"rig":"gongshow"`, `"target":"gongshow/polecats/Toast"`, `"os_user"`} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("event missing %s: %s", want, lines[0])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var eventsMigrateDryRun bool
+
+var eventsMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Split the town events log into per-rig logs",
+	Long: `Switch the town to per-rig event files.
+
+Turns on per_rig_events in settings/config.json, so events from rig actors
+(e.g. gongshow/witness) are written to <rig>/.events.jsonl from now on, then
+moves the rig events already in the town log (archives included) into the
+rig directories. Town-level events (mayor, deacon, ...) stay at the root.
+
+The town's active file is rotated first, so followers such as
+"gt events tail -f" keep working. Running migrate again is safe.
+
+Examples:
+  gt events migrate --dry-run
+  gt events migrate`,
+	Args: cobra.NoArgs,
+	RunE: runEventsMigrate,
+}
+
+func init() {
+	eventsMigrateCmd.Flags().BoolVar(&eventsMigrateDryRun, "dry-run", false, "Show what would move without changing anything")
+
+	eventsCmd.AddCommand(eventsMigrateCmd)
+}
+
+func runEventsMigrate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	if !eventsMigrateDryRun {
+		// Route new rig events to the rig files before splitting, so nothing
+		// written meanwhile is left behind in the town log.
+		settingsPath := config.TownSettingsPath(townRoot)
+		settings, err := config.LoadOrCreateTownSettings(settingsPath)
+		if err != nil {
+			return fmt.Errorf("loading town settings: %w", err)
+		}
+		if !settings.PerRigEvents {
+			settings.PerRigEvents = true
+			if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+				return fmt.Errorf("saving town settings: %w", err)
+			}
+			fmt.Printf("%s Enabled per_rig_events in settings/config.json\n", style.SuccessPrefix)
+		}
+	}
+
+	result, err := events.SplitByRig(townRoot, eventsMigrateDryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "Moved"
+	if eventsMigrateDryRun {
+		verb = "Would move"
+	}
+	if len(result.Moved) == 0 {
+		fmt.Printf("%s No rig events in the town log (%d kept)\n", style.Dim.Render("○"), result.Kept)
+		return nil
+	}
+
+	rigs := make([]string, 0, len(result.Moved))
+	total := 0
+	for rig, n := range result.Moved {
+		rigs = append(rigs, rig)
+		total += n
+	}
+	sort.Strings(rigs)
+
+	fmt.Printf("%s %d event(s) from %d file(s); %d stay in the town log\n", verb, total, result.Files, result.Kept)
+	for _, rig := range rigs {
+		fmt.Printf("  %-20s %d\n", rig, result.Moved[rig])
+	}
+	return nil
+}
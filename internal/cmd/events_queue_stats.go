@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var (
+	eventsQueueStatsSince string
+	eventsQueueStatsJSON  bool
+)
+
+var eventsQueueStatsCmd = &cobra.Command{
+	Use:   "queue-stats <queue-name>",
+	Short: "Per-worker claims, completions and failures on a work queue",
+	Long: `Count what each worker did with a work queue's messages.
+
+Reads the queue_claim events written by 'gt mail claim' and the
+queue_release events written by 'gt mail release' from the audit log, and
+shows per worker how many messages they claimed, completed, failed, and
+released back unfinished.
+
+Examples:
+  gt events queue-stats work/gongshow
+  gt events queue-stats work/gongshow --since 7d
+  gt events queue-stats work/gongshow --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEventsQueueStats,
+}
+
+func init() {
+	eventsQueueStatsCmd.Flags().StringVar(&eventsQueueStatsSince, "since", "", "Start of window: duration ago (1h, 7d) or timestamp")
+	eventsQueueStatsCmd.Flags().BoolVar(&eventsQueueStatsJSON, "json", false, "Output as JSON")
+
+	eventsCmd.AddCommand(eventsQueueStatsCmd)
+}
+
+func runEventsQueueStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	var since time.Time
+	if eventsQueueStatsSince != "" {
+		if since, err = parseEventTime(eventsQueueStatsSince, time.Now()); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+
+	stats, err := events.CollectQueueStats(townRoot, args[0], since)
+	if err != nil {
+		return err
+	}
+
+	if eventsQueueStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	printQueueStats(os.Stdout, stats)
+	return nil
+}
+
+// printQueueStats renders per-worker queue counts as a table with a total row.
+func printQueueStats(w io.Writer, stats *events.QueueStats) {
+	header := "Queue " + stats.Queue
+	if !stats.Since.IsZero() {
+		header += fmt.Sprintf(" since %s", stats.Since.Local().Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintln(w, style.Bold.Render(header))
+	if len(stats.Workers) == 0 {
+		fmt.Fprintf(w, "%s No claims or releases recorded\n", style.Dim.Render("○"))
+	} else {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "WORKER\tCLAIMS\tCOMPLETED\tFAILED\tRELEASED")
+		for _, ws := range stats.Workers {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", ws.Worker, ws.Claims, ws.Completed, ws.Failed, ws.Released)
+		}
+		if len(stats.Workers) > 1 {
+			t := stats.Total()
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", "total", t.Claims, t.Completed, t.Failed, t.Released)
+		}
+		_ = tw.Flush()
+	}
+	if stats.Skipped > 0 {
+		fmt.Fprintf(w, "%s Skipped %d malformed line(s)\n", style.WarningPrefix, stats.Skipped)
+	}
+}
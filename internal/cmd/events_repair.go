@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var eventsRepairDryRun bool
+
+var eventsRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Move corrupt lines out of the events logs",
+	Long: `Scan the events logs for corrupt lines and move them aside.
+
+Every log file is checked: the town and rig logs, archives included. Lines
+that are not a JSON event (for example half-lines left by interleaved
+writes) are moved to a .events.corrupt file next to the log they came from,
+so every tool that reads the log can parse it again.
+
+Examples:
+  gt events repair --dry-run
+  gt events repair`,
+	Args: cobra.NoArgs,
+	RunE: runEventsRepair,
+}
+
+func init() {
+	eventsRepairCmd.Flags().BoolVar(&eventsRepairDryRun, "dry-run", false, "Count corrupt lines without moving them")
+
+	eventsCmd.AddCommand(eventsRepairCmd)
+}
+
+func runEventsRepair(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	result, err := events.Repair(townRoot, eventsRepairDryRun)
+	if err != nil {
+		return err
+	}
+
+	if result.Corrupt == 0 {
+		fmt.Printf("%s No corrupt lines in %d line(s) across %d file(s)\n", style.SuccessPrefix, result.Lines, len(result.Files))
+		return nil
+	}
+
+	verb := "Moved"
+	if eventsRepairDryRun {
+		verb = "Would move"
+	}
+	fmt.Printf("%s %d corrupt line(s) of %d to %s\n", verb, result.Corrupt, result.Lines, events.CorruptFile)
+	for _, fr := range result.Files {
+		if fr.Corrupt == 0 {
+			continue
+		}
+		name := fr.Path
+		if rel, err := filepath.Rel(townRoot, fr.Path); err == nil {
+			name = rel
+		}
+		fmt.Printf("  %-40s %d\n", name, fr.Corrupt)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var (
+	eventsReplayFrom   string
+	eventsReplaySpeed  string
+	eventsReplayFilter []string
+	eventsReplayDryRun bool
+)
+
+var eventsReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Replay recorded events through the configured hooks",
+	Long: `Replay events from a recorded events file through the town's hooks.
+
+Each event is re-dispatched to the hooks it matches, in file order, with
+the recorded gaps between events scaled by --speed (10x replays ten times
+faster; max doesn't wait). Nothing is appended to the events log.
+
+Replayed events are marked: hooks see GT_EVENT_REPLAYED=1, and their mail
+and notify actions are skipped, so a replay never sends real mail or
+notifications. Command actions still run.
+
+--filter takes key=value: type (comma-separated types), actor (a pattern,
+as in tail), correlation (an ID or prefix), or any other key to match a
+payload value glob. Repeat it to require several.
+
+--dry-run lists what each matching hook would do instead of running it.
+
+Examples:
+  gt events replay --from events.jsonl --dry-run
+  gt events replay --from events.jsonl --speed 10x --filter type=polecat_checked
+  gt events replay --from incident.jsonl --speed max --filter actor='gongshow/*'`,
+	Args: cobra.NoArgs,
+	RunE: runEventsReplay,
+}
+
+func init() {
+	eventsReplayCmd.Flags().StringVar(&eventsReplayFrom, "from", "", "Events file to replay (JSON lines, as in .events.jsonl)")
+	eventsReplayCmd.Flags().StringVar(&eventsReplaySpeed, "speed", "1x", "Replay speed: a multiplier like 10x, or max")
+	eventsReplayCmd.Flags().StringArrayVar(&eventsReplayFilter, "filter", nil, "Only replay events matching key=value (can be used multiple times)")
+	eventsReplayCmd.Flags().BoolVar(&eventsReplayDryRun, "dry-run", false, "Show what each matching hook would do without running it")
+	_ = eventsReplayCmd.MarkFlagRequired("from")
+
+	eventsCmd.AddCommand(eventsReplayCmd)
+}
+
+func runEventsReplay(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	speed, err := events.ParseReplaySpeed(eventsReplaySpeed)
+	if err != nil {
+		return fmt.Errorf("invalid --speed: %w", err)
+	}
+	filter, err := parseReplayFilter(eventsReplayFilter)
+	if err != nil {
+		return err
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	hooks := settings.Hooks
+
+	f, err := os.Open(eventsReplayFrom)
+	if err != nil {
+		return fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	replayed := 0
+	opts := events.ReplayOptions{Filter: filter, Speed: speed}
+	skipped, err := events.ReplayWithOptions(ctx, f, opts, func(e events.Event) {
+		replayed++
+		fmt.Println(formatEventLine(e))
+		for _, i := range events.MatchingHooks(hooks, e) {
+			hook, name := hooks[i], events.HookName(hooks[i], i)
+			if eventsReplayDryRun {
+				fmt.Printf("    %s %s\n", style.Bold.Render("→"), name)
+				for _, action := range describeReplayActions(hook) {
+					fmt.Printf("        %s\n", action)
+				}
+				continue
+			}
+			if err := events.RunHook(ctx, townRoot, hook, e); err != nil {
+				fmt.Printf("    %s %s: %v\n", style.WarningPrefix, name, err)
+			} else {
+				fmt.Printf("    %s %s\n", style.Bold.Render("✓"), name)
+			}
+		}
+	})
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "%s Skipped %d malformed line(s)\n", style.WarningPrefix, skipped)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render(fmt.Sprintf("Replayed %d event(s)", replayed)))
+	return nil
+}
+
+// parseReplayFilter builds a Filter from --filter key=value flags.
+func parseReplayFilter(specs []string) (events.Filter, error) {
+	var f events.Filter
+	for _, spec := range specs {
+		key, value, ok := strings.Cut(spec, "=")
+		if !ok || key == "" {
+			return f, fmt.Errorf("invalid --filter %q: want key=value", spec)
+		}
+		switch key {
+		case "type":
+			f.Types = append(f.Types, strings.Split(value, ",")...)
+		case "actor":
+			pattern := value
+			f.Actor = func(actor string) bool {
+				return mail.MatchPattern(pattern, actor)
+			}
+		case "correlation":
+			f.Correlation = value
+		default:
+			if f.Payload == nil {
+				f.Payload = make(map[string]string)
+			}
+			f.Payload[key] = value
+		}
+	}
+	return f, nil
+}
+
+// describeReplayActions lists what a hook would do for a replayed event,
+// noting the actions replay skips.
+func describeReplayActions(hook config.EventHook) []string {
+	actions := describeHookActions(hook)
+	for i, action := range actions {
+		if strings.HasPrefix(action, "mail: ") || strings.HasPrefix(action, "notify: ") {
+			actions[i] = action + " " + style.Dim.Render("(skipped: replayed)")
+		}
+	}
+	return actions
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+func TestFormatEventPayload(t *testing.T) {
+	got := formatEventPayload(map[string]interface{}{
+		"target":  "gongshow/Toast",
+		"bead":    "gt-abc",
+		"message": "line one\nline two, which goes on for far longer than anyone wants to read",
+	})
+	want := "bead=gt-abc message=line one line two, which goes on for fa… target=gongshow/Toast"
+	if got != want {
+		t.Errorf("formatEventPayload = %q, want %q", got, want)
+	}
+	if got := formatEventPayload(nil); got != "" {
+		t.Errorf("formatEventPayload(nil) = %q, want empty", got)
+	}
+}
+
+func TestParseEventTime(t *testing.T) {
+	now := time.Date(2024, 1, 20, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2024-01-15", time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)},
+		{"2024-01-15T09:30", time.Date(2024, 1, 15, 9, 30, 0, 0, time.Local)},
+		{"2024-01-15T09:30:00Z", time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)},
+		{"2h", now.Add(-2 * time.Hour)},
+		{"7d", now.Add(-7 * 24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := parseEventTime(tt.input, now)
+		if err != nil {
+			t.Errorf("parseEventTime(%q): %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseEventTime(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+	if _, err := parseEventTime("yesterday", now); err == nil {
+		t.Error("parseEventTime(\"yesterday\") should fail")
+	}
+}
+
+func TestEventWriterJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newEventWriter(&buf, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.write(`{"type":"a"}`, events.Event{})
+	_ = w.write(`{"type":"b"}`, events.Event{})
+	if err := w.close(); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != 2 || decoded[1]["type"] != "b" {
+		t.Errorf("decoded %v", decoded)
+	}
+
+	if _, err := newEventWriter(&buf, "xml"); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	days := []events.DayCount{{Day: "a", Count: 0}, {Day: "b", Count: 1}, {Day: "c", Count: 4}, {Day: "d", Count: 8}}
+	if got, want := sparkline(days), " ▁▄█"; got != want {
+		t.Errorf("sparkline = %q, want %q", got, want)
+	}
+}
+
+func TestPrintEventStatsGroupByType(t *testing.T) {
+	stats := &events.Stats{
+		Total:  5,
+		ByType: map[string]int{"sling": 3, "spawn": 2},
+		TopActors: map[string][]events.ActorCount{
+			"sling": {{Actor: "mayor", Count: 2}, {Actor: "deacon", Count: 1}},
+		},
+		Days:                  []events.DayCount{{Day: "2024-01-15", Count: 5}},
+		PolecatLifetime:       events.LifetimeStats{Count: 2, Average: 90 * time.Minute},
+		EscalationsBySeverity: map[string]int{"high": 1},
+	}
+	var buf bytes.Buffer
+	printEventStats(&buf, stats, "type")
+	out := buf.String()
+	for _, want := range []string{"mayor (2), deacon (1)", "avg 1h30m0s over 2 polecat(s)", "Escalations: high 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPrintQueueStats(t *testing.T) {
+	stats := &events.QueueStats{
+		Queue: "work/gongshow",
+		Workers: []events.QueueWorkerStats{
+			{Worker: "gongshow/polecats/Toast", Claims: 3, Completed: 2, Failed: 1},
+			{Worker: "gongshow/polecats/Nux", Claims: 1, Released: 1},
+		},
+	}
+	var buf bytes.Buffer
+	printQueueStats(&buf, stats)
+	out := buf.String()
+	for _, want := range []string{"Queue work/gongshow", "WORKER", "gongshow/polecats/Toast  3       2          1       0", "total                    4       2          1       1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestParseReplayFilter(t *testing.T) {
+	f, err := parseReplayFilter([]string{"type=polecat_checked,sling", "actor=gongshow/*", "session=gt-*"})
+	if err != nil {
+		t.Fatalf("parseReplayFilter: %v", err)
+	}
+	match := events.Event{Type: "sling", Actor: "gongshow/Toast", Payload: map[string]interface{}{"session": "gt-gongshow-Toast"}}
+	if !f.Match(&match) {
+		t.Errorf("filter rejected %+v", match)
+	}
+	for _, e := range []events.Event{
+		{Type: "mail", Actor: "gongshow/Toast", Payload: map[string]interface{}{"session": "gt-x"}},
+		{Type: "sling", Actor: "mayor", Payload: map[string]interface{}{"session": "gt-x"}},
+		{Type: "sling", Actor: "gongshow/Toast"},
+	} {
+		if f.Match(&e) {
+			t.Errorf("filter accepted %+v", e)
+		}
+	}
+
+	if _, err := parseReplayFilter([]string{"polecat_checked"}); err == nil {
+		t.Error("expected error for filter without '='")
+	}
+}
@@ -25,16 +25,19 @@ import (
 )
 
 var (
-	installForce      bool
-	installName       string
-	installOwner      string
-	installPublicName string
-	installNoBeads    bool
-	installGit        bool
-	installGitHub     string
-	installPublic     bool
-	installShell      bool
-	installWrappers   bool
+	installForce       bool
+	installName        string
+	installOwner       string
+	installPublicName  string
+	installNoBeads     bool
+	installGit         bool
+	installGitHub      string
+	installPublic      bool
+	installShell       bool
+	installRCFile      string
+	installOutsideHome bool
+	installRepair      bool
+	installWrappers    bool
 )
 
 var installCmd = &cobra.Command{
@@ -61,7 +64,8 @@ Examples:
   gt install ~/gt --git                        # Also init git with .gitignore
   gt install ~/gt --github=user/repo           # Create private GitHub repo (default)
   gt install ~/gt --github=user/repo --public  # Create public GitHub repo
-  gt install ~/gt --shell                      # Install shell integration (sets GT_TOWN_ROOT/GT_RIG)`,
+  gt install ~/gt --shell                      # Install shell integration (sets GT_TOWN_ROOT/GT_RIG)
+  gt install --shell --repair                  # Fix a damaged or duplicated shell integration block`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInstall,
 }
@@ -76,11 +80,21 @@ func init() {
 	installCmd.Flags().StringVar(&installGitHub, "github", "", "Create GitHub repo (format: owner/repo, private by default)")
 	installCmd.Flags().BoolVar(&installPublic, "public", false, "Make GitHub repo public (use with --github)")
 	installCmd.Flags().BoolVar(&installShell, "shell", false, "Install shell integration (sets GT_TOWN_ROOT/GT_RIG env vars)")
+	installCmd.Flags().StringVar(&installRCFile, "rc-file", "", "RC file for --shell to add the hook to (default: the shell's RC file)")
+	installCmd.Flags().BoolVar(&installOutsideHome, "allow-outside-home", false, "With --shell, allow an RC file or hook script outside your home directory")
+	installCmd.Flags().BoolVar(&installRepair, "repair", false, "With --shell, remove any damaged or duplicated integration blocks and reinstall (no HQ is created)")
 	installCmd.Flags().BoolVar(&installWrappers, "wrappers", false, "Install gt-codex/gt-opencode wrapper scripts to ~/bin/")
 	rootCmd.AddCommand(installCmd)
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
+	if installRepair {
+		if !installShell {
+			return fmt.Errorf("--repair requires --shell")
+		}
+		return runInstallShellRepair()
+	}
+
 	// Determine target path
 	targetPath := "."
 	if len(args) > 0 {
@@ -286,10 +300,11 @@ func runInstall(cmd *cobra.Command, args []string) error {
 
 	if installShell {
 		fmt.Println()
-		if err := shell.Install(); err != nil {
+		opts := shell.InstallOptions{RCFile: installRCFile, AllowOutsideHome: installOutsideHome}
+		if err := shell.InstallWithOptions(opts); err != nil {
 			fmt.Printf("   %s Could not install shell integration: %v\n", style.Dim.Render("⚠"), err)
 		} else {
-			fmt.Printf("   ✓ Installed shell integration (%s)\n", shell.RCFilePath(shell.DetectShell()))
+			fmt.Printf("   ✓ Installed shell integration (%s)\n", shell.InstalledRCFile(shell.DetectShell()))
 		}
 		if err := state.Enable(Version); err != nil {
 			fmt.Printf("   %s Could not enable GongShow: %v\n", style.Dim.Render("⚠"), err)
@@ -397,6 +412,13 @@ func initTownBeads(townPath string) error {
 		}
 	}
 
+	// Run the registered schema migrations on the new store, recording them
+	// in its migrations.json. None are registered yet, so this does nothing
+	// until one is.
+	if _, err := beads.MigrateBeadsDir(townPath, filepath.Join(townPath, ".beads")); err != nil {
+		fmt.Printf("   %s Could not run beads migrations: %v\n", style.Dim.Render("⚠"), err)
+	}
+
 	// Ensure routes.jsonl has an explicit town-level mapping for hq-* beads.
 	// This keeps hq-* operations stable even when invoked from rig worktrees.
 	if err := beads.AppendRoute(townPath, beads.Route{Prefix: "hq-", Path: "."}); err != nil {
@@ -558,3 +580,33 @@ func ensureBeadsCustomTypes(workDir string, types []string) error {
 	}
 	return nil
 }
+
+// runInstallShellRepair reports what is wrong with the shell integration,
+// then strips every GongShow block from the RC file and reinstalls it.
+func runInstallShellRepair() error {
+	before, err := shell.Verify()
+	if err != nil {
+		return err
+	}
+	for _, problem := range before.Problems {
+		fmt.Printf("%s %s\n", style.Warning.Render("⚠"), problem)
+	}
+
+	backup, err := shell.Repair()
+	if err != nil {
+		return fmt.Errorf("repairing shell integration: %w", err)
+	}
+	if backup != "" {
+		fmt.Printf("%s Backed up %s to %s\n", style.Dim.Render("○"), before.RCFile, backup)
+	}
+
+	after, err := shell.Verify()
+	if err != nil {
+		return err
+	}
+	if len(after.Problems) > 0 {
+		return fmt.Errorf("shell integration still has problems: %s", strings.Join(after.Problems, "; "))
+	}
+	fmt.Printf("%s Reinstalled shell integration (%s)\n", style.Success.Render("✓"), after.RCFile)
+	return nil
+}
@@ -42,6 +42,8 @@ Events logged include:
   crash   - agent exited unexpectedly
   kill    - agent killed intentionally
 
+For events, mail and bead transitions in one timeline, see 'gt log all'.
+
 Examples:
   gt log                     # Show last 20 events
   gt log -n 50               # Show last 50 events
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/constants"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+// Unified log sources.
+const (
+	unifiedSourceEvent = "event" // The events feed
+	unifiedSourceMail  = "mail"  // Archived mail
+	unifiedSourceBead  = "bead"  // Bead transitions, from the audit log
+)
+
+// log all flags
+var (
+	logAllSince string
+	logAllRig   string
+	logAllActor string
+	logAllType  string
+	logAllTail  int
+	logAllJSON  bool
+)
+
+var logAllCmd = &cobra.Command{
+	Use:   "all",
+	Short: "View events, mail and bead transitions in one timeline",
+	Long: `View all town activity in one chronological timeline, oldest first:
+
+  event - the activity feed (.events.jsonl, rig logs and archives included)
+  mail  - archived mail, in the town's and each rig's beads
+  bead  - bead transitions, from the audit log
+
+Each line shows the time, source, type, actor and a short summary. Mail
+matches --actor and --rig by sender or recipient.
+
+Examples:
+  gt log all                          # The last 50 entries
+  gt log all --since 1h               # Everything in the last hour
+  gt log all --rig gongshow           # One rig's activity
+  gt log all --actor gongshow/crew/max
+  gt log all --type mail              # Only mail
+  gt log all --since 1d --json`,
+	Args: cobra.NoArgs,
+	RunE: runLogAll,
+}
+
+func init() {
+	logAllCmd.Flags().StringVar(&logAllSince, "since", "", "Show entries since duration (e.g., 30m, 1h, 7d)")
+	logAllCmd.Flags().StringVar(&logAllRig, "rig", "", "Show only this rig's entries")
+	logAllCmd.Flags().StringVar(&logAllActor, "actor", "", "Show only entries by (or mail to) this address or its agents (e.g., gongshow/, mayor)")
+	logAllCmd.Flags().StringVarP(&logAllType, "type", "t", "", "Show only this type (an event type, mail or bead_transition)")
+	logAllCmd.Flags().IntVarP(&logAllTail, "tail", "n", 50, "Number of entries to show (0 for all)")
+	logAllCmd.Flags().BoolVar(&logAllJSON, "json", false, "Output as JSON")
+
+	logCmd.AddCommand(logAllCmd)
+}
+
+// UnifiedEntry is one entry of the unified log: an event, an archived mail
+// message or a bead transition.
+type UnifiedEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source"` // "event", "mail" or "bead"
+	Type      string    `json:"type"`
+	Actor     string    `json:"actor"`
+	To        string    `json:"to,omitempty"` // Mail recipient
+	Rig       string    `json:"rig,omitempty"`
+	Summary   string    `json:"summary"`
+	ID        string    `json:"id,omitempty"` // Event, message or bead ID
+}
+
+// unifiedFilter selects entries of the unified log.
+type unifiedFilter struct {
+	Since time.Time
+	Rig   string
+	Actor string
+	Type  string
+}
+
+func runLogAll(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	filter := unifiedFilter{Rig: logAllRig, Actor: logAllActor, Type: logAllType}
+	if logAllSince != "" {
+		d, err := parseDuration(logAllSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		filter.Since = time.Now().Add(-d)
+	}
+
+	var entries []UnifiedEntry
+	sources := []struct {
+		name    string
+		collect func(townRoot string, since time.Time) ([]UnifiedEntry, error)
+	}{
+		{"events", collectUnifiedEvents},
+		{"mail archive", collectUnifiedMail},
+		{"bead transitions", collectUnifiedBeadTransitions},
+	}
+	for _, src := range sources {
+		found, err := src.collect(townRoot, filter.Since)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s could not read %s: %v\n", style.WarningPrefix, src.name, err)
+		}
+		entries = append(entries, found...)
+	}
+
+	entries = mergeUnifiedEntries(entries, filter)
+	if logAllTail > 0 && len(entries) > logAllTail {
+		entries = entries[len(entries)-logAllTail:]
+	}
+
+	if logAllJSON {
+		if entries == nil {
+			entries = []UnifiedEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s No activity matches\n", style.Dim.Render("○"))
+		return nil
+	}
+	printUnifiedEntries(os.Stdout, entries)
+	return nil
+}
+
+// collectUnifiedEvents reads the events feed.
+func collectUnifiedEvents(townRoot string, since time.Time) ([]UnifiedEntry, error) {
+	var entries []UnifiedEntry
+	_, err := events.Query(townRoot, events.QueryOptions{Filter: events.Filter{Since: since}}, func(_ string, e events.Event) error {
+		summary := formatFeedSummary(e)
+		if summary == e.Type {
+			summary = formatEventPayload(e.Payload)
+		}
+		rig, _ := e.Payload["rig"].(string)
+		if rig == "" {
+			rig = addressRig(e.Actor)
+		}
+		entries = append(entries, UnifiedEntry{
+			Timestamp: parseEventTimestamp(e.Timestamp),
+			Source:    unifiedSourceEvent,
+			Type:      e.Type,
+			Actor:     e.Actor,
+			Rig:       rig,
+			Summary:   summary,
+			ID:        e.ID,
+		})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return entries, err
+}
+
+// collectUnifiedBeadTransitions reads bead transitions from the audit log.
+func collectUnifiedBeadTransitions(townRoot string, since time.Time) ([]UnifiedEntry, error) {
+	opts := events.QueryOptions{
+		Source: events.SourceAudit,
+		Filter: events.Filter{Types: []string{events.TypeBeadTransition}, Since: since},
+	}
+	var entries []UnifiedEntry
+	_, err := events.Query(townRoot, opts, func(_ string, e events.Event) error {
+		bead, _ := e.Payload["bead"].(string)
+		from, _ := e.Payload["from"].(string)
+		to, _ := e.Payload["to"].(string)
+		summary := fmt.Sprintf("%s: %s → %s", bead, from, to)
+		if reason, _ := e.Payload["reason"].(string); reason != "" {
+			summary += " (" + reason + ")"
+		}
+		entries = append(entries, UnifiedEntry{
+			Timestamp: parseEventTimestamp(e.Timestamp),
+			Source:    unifiedSourceBead,
+			Type:      e.Type,
+			Actor:     e.Actor,
+			Rig:       addressRig(e.Actor),
+			Summary:   summary,
+			ID:        bead,
+		})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return entries, err
+}
+
+// collectUnifiedMail reads the mail archives of the town's and each rig's
+// beads.
+func collectUnifiedMail(townRoot string, since time.Time) ([]UnifiedEntry, error) {
+	locations := []string{townRoot}
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err == nil && rigsConfig != nil {
+		for rigName := range rigsConfig.Rigs {
+			locations = append(locations, filepath.Join(townRoot, rigName))
+		}
+	}
+	sort.Strings(locations[1:])
+
+	var entries []UnifiedEntry
+	var firstErr error
+	for _, dir := range locations {
+		mailbox := mail.NewMailboxWithBeadsDir("", dir, beads.ResolveBeadsDir(dir))
+		messages, err := mailbox.ListArchived()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, msg := range messages {
+			if !since.IsZero() && msg.Timestamp.Before(since) {
+				continue
+			}
+			rig := addressRig(msg.To)
+			if rig == "" {
+				rig = addressRig(msg.From)
+			}
+			entries = append(entries, UnifiedEntry{
+				Timestamp: msg.Timestamp,
+				Source:    unifiedSourceMail,
+				Type:      "mail",
+				Actor:     msg.From,
+				To:        msg.To,
+				Rig:       rig,
+				Summary:   fmt.Sprintf("to %s: %s", msg.To, msg.Subject),
+				ID:        msg.ID,
+			})
+		}
+	}
+	return entries, firstErr
+}
+
+// mergeUnifiedEntries filters the entries and sorts them oldest first.
+// Entries with the same timestamp keep their sources' order.
+func mergeUnifiedEntries(entries []UnifiedEntry, f unifiedFilter) []UnifiedEntry {
+	var kept []UnifiedEntry
+	for _, e := range entries {
+		if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+			continue
+		}
+		if f.Type != "" && e.Type != f.Type {
+			continue
+		}
+		if f.Rig != "" && e.Rig != f.Rig && addressRig(e.Actor) != f.Rig && addressRig(e.To) != f.Rig {
+			continue
+		}
+		if f.Actor != "" && !addressMatches(e.Actor, f.Actor) && !addressMatches(e.To, f.Actor) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Timestamp.Before(kept[j].Timestamp)
+	})
+	return kept
+}
+
+// addressMatches reports whether addr is want or one of its agents:
+// "gongshow" and "gongshow/" match "gongshow/crew/max"; "mayor" matches
+// "mayor/".
+func addressMatches(addr, want string) bool {
+	addr, want = strings.TrimSuffix(addr, "/"), strings.TrimSuffix(want, "/")
+	return addr != "" && (addr == want || strings.HasPrefix(addr, want+"/"))
+}
+
+// addressRig returns the rig of an agent address, or "" for town-level
+// agents and other actors.
+func addressRig(addr string) string {
+	rig, _, ok := strings.Cut(addr, "/")
+	if !ok || rig == "" || rig == constants.DirMayor || rig == "deacon" {
+		return ""
+	}
+	return rig
+}
+
+// parseEventTimestamp parses an event's RFC 3339 timestamp, or returns the
+// zero time.
+func parseEventTimestamp(ts string) time.Time {
+	t, _ := time.Parse(time.RFC3339, ts)
+	return t
+}
+
+// printUnifiedEntries writes one colourised line per entry: time, source,
+// type, actor and summary.
+func printUnifiedEntries(w io.Writer, entries []UnifiedEntry) {
+	for _, e := range entries {
+		var source string
+		switch e.Source {
+		case unifiedSourceEvent:
+			source = style.Warning.Render(fmt.Sprintf("%-7s", "[event]"))
+		case unifiedSourceMail:
+			source = style.Bold.Render(fmt.Sprintf("%-7s", "[mail]"))
+		case unifiedSourceBead:
+			source = style.Success.Render(fmt.Sprintf("%-7s", "[bead]"))
+		default:
+			source = fmt.Sprintf("%-7s", "["+e.Source+"]")
+		}
+		_, _ = fmt.Fprintf(w, "%s %s %s %s %s\n",
+			style.Dim.Render(e.Timestamp.Local().Format("2006-01-02 15:04:05")),
+			source,
+			style.Bold.Render(fmt.Sprintf("%-16s", e.Type)),
+			e.Actor,
+			e.Summary)
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+)
+
+// writeJSONLines writes each value as a line of JSON.
+func writeJSONLines(t *testing.T, path string, values ...interface{}) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	for _, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Write(append(data, '\n'))
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setupUnifiedLogTown writes a town with a rig, interleaving events, mail
+// in the town's and the rig's archives, and bead transitions.
+func setupUnifiedLogTown(t *testing.T) (string, time.Time) {
+	t.Helper()
+	town := t.TempDir()
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	ts := func(min int) string { return at(min).Format(time.RFC3339) }
+
+	rigs := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{"gongshow": {}}}
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigsConfig(filepath.Join(town, "mayor", "rigs.json"), rigs); err != nil {
+		t.Fatal(err)
+	}
+
+	writeJSONLines(t, filepath.Join(town, events.EventsFile),
+		events.Event{Timestamp: ts(0), Source: "gt", Type: events.TypeSling, Actor: "mayor",
+			Payload: events.SlingPayload("gt-1", "gongshow/Toast"), Visibility: events.VisibilityFeed},
+		events.Event{Timestamp: ts(3), Source: "gt", Type: events.TypeDone, Actor: "gongshow/Toast",
+			Payload: events.DonePayload("gt-1", "polecat/Toast"), Visibility: events.VisibilityFeed},
+	)
+	writeJSONLines(t, filepath.Join(town, events.AuditFile),
+		events.Event{Timestamp: ts(2), Source: "gt", Type: events.TypeBeadTransition, Actor: "gongshow/witness",
+			Payload: events.BeadTransitionPayload("gt-1", "hooked", "in_progress", ""), Visibility: events.VisibilityAudit},
+		events.Event{Timestamp: ts(4), Source: "gt", Type: events.TypeKill, Actor: "mayor", Visibility: events.VisibilityAudit},
+	)
+	writeJSONLines(t, filepath.Join(town, ".beads", "archive.jsonl"),
+		mail.Message{ID: "hq-m1", From: "mayor/", To: "gongshow/Toast", Subject: "Take gt-1", Timestamp: at(1)},
+	)
+	writeJSONLines(t, filepath.Join(town, "gongshow", ".beads", "archive.jsonl"),
+		mail.Message{ID: "gt-m2", From: "gongshow/Toast", To: "mayor/", Subject: "gt-1 done", Timestamp: at(5)},
+	)
+	return town, base
+}
+
+func collectUnifiedLog(t *testing.T, town string, since time.Time) []UnifiedEntry {
+	t.Helper()
+	var entries []UnifiedEntry
+	for _, collect := range []func(string, time.Time) ([]UnifiedEntry, error){
+		collectUnifiedEvents, collectUnifiedMail, collectUnifiedBeadTransitions,
+	} {
+		found, err := collect(town, since)
+		if err != nil {
+			t.Fatalf("collecting: %v", err)
+		}
+		entries = append(entries, found...)
+	}
+	return entries
+}
+
+func unifiedSummaries(entries []UnifiedEntry) string {
+	var lines []string
+	for _, e := range entries {
+		lines = append(lines, e.Source+" "+e.Type+" "+e.Summary)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestUnifiedLogOrder(t *testing.T) {
+	town, _ := setupUnifiedLogTown(t)
+
+	entries := mergeUnifiedEntries(collectUnifiedLog(t, town, time.Time{}), unifiedFilter{})
+	want := strings.Join([]string{
+		"event sling Slung gt-1",
+		"mail mail to gongshow/Toast: Take gt-1",
+		"bead bead_transition gt-1: hooked → in_progress",
+		"event done Done gt-1",
+		"mail mail to mayor/: gt-1 done",
+	}, "\n")
+	if got := unifiedSummaries(entries); got != want {
+		t.Errorf("unified log:\n%s\nwant:\n%s", got, want)
+	}
+	if entries[2].Rig != "gongshow" || entries[1].Rig != "gongshow" || entries[0].Rig != "" {
+		t.Errorf("rigs = %q, %q, %q; want the mayor's sling town-level and the rest gongshow's",
+			entries[0].Rig, entries[1].Rig, entries[2].Rig)
+	}
+}
+
+func TestUnifiedLogFilters(t *testing.T) {
+	town, base := setupUnifiedLogTown(t)
+	all := collectUnifiedLog(t, town, time.Time{})
+
+	tests := []struct {
+		name   string
+		filter unifiedFilter
+		want   []string // Entry types, in order
+	}{
+		{"since", unifiedFilter{Since: base.Add(3 * time.Minute)}, []string{"done", "mail"}},
+		{"type", unifiedFilter{Type: "mail"}, []string{"mail", "mail"}},
+		{"actor matches sender or recipient", unifiedFilter{Actor: "mayor"}, []string{"sling", "mail", "mail"}},
+		{"actor prefix", unifiedFilter{Actor: "gongshow/"}, []string{"mail", "bead_transition", "done", "mail"}},
+		{"rig", unifiedFilter{Rig: "gongshow", Type: "mail"}, []string{"mail", "mail"}},
+		{"other rig", unifiedFilter{Rig: "beads"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range mergeUnifiedEntries(all, tt.filter) {
+				got = append(got, e.Type)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("types = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The collectors apply --since themselves too.
+	if got := collectUnifiedLog(t, town, base.Add(4*time.Minute)); len(got) != 1 || got[0].ID != "gt-m2" {
+		t.Errorf("collected since 09:04 = %+v, want only the last mail", got)
+	}
+}
+
+func TestPrintUnifiedEntries(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.Local)
+	var out bytes.Buffer
+	printUnifiedEntries(&out, []UnifiedEntry{
+		{Timestamp: at, Source: unifiedSourceMail, Type: "mail", Actor: "mayor/", Summary: "to gongshow/Toast: Take gt-1"},
+		{Timestamp: at.Add(time.Minute), Source: unifiedSourceBead, Type: events.TypeBeadTransition, Actor: "gongshow/witness", Summary: "gt-1: hooked → in_progress"},
+	})
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("printed %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	for i, want := range []string{
+		"2026-03-01 09:00:00 [mail]  mail             mayor/ to gongshow/Toast: Take gt-1",
+		"2026-03-01 09:01:00 [bead]  bead_transition  gongshow/witness gt-1: hooked → in_progress",
+	} {
+		if lines[i] != want {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want)
+		}
+	}
+}
+
+func TestAddressMatches(t *testing.T) {
+	tests := []struct {
+		addr, want string
+		match      bool
+	}{
+		{"mayor/", "mayor", true},
+		{"gongshow/crew/max", "gongshow", true},
+		{"gongshow/crew/max", "gongshow/crew/max", true},
+		{"gongshow/crew/maxine", "gongshow/crew/max", false},
+		{"gongshowtwo/witness", "gongshow", false},
+		{"", "gongshow", false},
+	}
+	for _, tt := range tests {
+		if got := addressMatches(tt.addr, tt.want); got != tt.match {
+			t.Errorf("addressMatches(%q, %q) = %v, want %v", tt.addr, tt.want, got, tt.match)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/events"
 )
 
 // Mail command flags
@@ -17,6 +18,7 @@ var (
 	mailReplyTo       string
 	mailNotify        bool
 	mailSendSelf      bool
+	mailBounce        bool
 	mailCC            []string // CC recipients
 	mailInboxJSON     bool
 	mailReadJSON      bool
@@ -41,6 +43,9 @@ var (
 
 	// Clear flags
 	mailClearAll bool
+
+	// Release flags
+	mailReleaseStatus string
 )
 
 var mailCmd = &cobra.Command{
@@ -116,6 +121,9 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+With --bounce, mail to an unknown or undeliverable address is returned to
+the sender's inbox as "BOUNCE: <subject>" instead of failing the command.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -125,7 +133,8 @@ Examples:
   gt mail send mayor/ -s "Re: Status" -m "Done" --reply-to msg-abc123
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
-  gt mail send list:oncall -s "Alert" -m "System down"`,
+  gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send greenplace/Toast -s "Ping" -m "Still there?" --bounce`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runMailSend,
 }
@@ -304,16 +313,20 @@ Examples:
 var mailReleaseCmd = &cobra.Command{
 	Use:   "release <message-id>",
 	Short: "Release a claimed queue message",
-	Long: `Release a previously claimed message back to its queue.
+	Long: `Release a previously claimed message back to its queue, or finish it.
 
 SYNTAX:
-  gt mail release <message-id>
+  gt mail release <message-id> [--status released|completed|failed]
 
 BEHAVIOR:
 1. Find the message by ID
 2. Verify caller is the one who claimed it (claimed-by label matches)
-3. Remove claimed-by and claimed-at labels
-4. Message returns to queue for others to claim
+3. released (default) or failed: remove claimed-by and claimed-at labels,
+   so the message returns to the queue for others to claim
+4. completed: close the message
+
+Each release is recorded as a queue_release event with its status; see
+'gt events queue-stats' for per-worker counts.
 
 ERROR CASES:
 - Message not found
@@ -322,7 +335,9 @@ ERROR CASES:
 - Caller did not claim this message
 
 Examples:
-  gt mail release hq-abc123    # Release a claimed message`,
+  gt mail release hq-abc123                     # Release a claimed message
+  gt mail release hq-abc123 --status completed  # Mark the work done
+  gt mail release hq-abc123 --status failed     # Give it back as failed`,
 	Args: cobra.ExactArgs(1),
 	RunE: runMailRelease,
 }
@@ -426,6 +441,7 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailPermanent, "permanent", false, "Send as permanent (not ephemeral, synced to remote)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().BoolVar(&mailBounce, "bounce", false, "Bounce undeliverable mail back to the sender instead of failing")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -461,6 +477,9 @@ func init() {
 	// Announces flags
 	mailAnnouncesCmd.Flags().BoolVar(&mailAnnouncesJSON, "json", false, "Output as JSON")
 
+	// Release flags
+	mailReleaseCmd.Flags().StringVar(&mailReleaseStatus, "status", events.QueueStatusReleased, "Outcome: released (back to the queue), completed (close the message), or failed (back to the queue, counted as a failure)")
+
 	// Clear flags
 	mailClearCmd.Flags().BoolVar(&mailClearAll, "all", false, "Clear all messages (default behavior)")
 
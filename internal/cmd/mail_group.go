@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/mail"
 	"github.com/KeithWyatt/gongshow/internal/workspace"
 )
 
@@ -35,7 +36,14 @@ Examples:
   gt mail group create ops-team gongshow/witness gongshow/crew/max
   gt mail group add ops-team deacon/
   gt mail group remove ops-team gongshow/witness
-  gt mail group delete ops-team`,
+  gt mail group delete ops-team
+
+@group addresses (@rig/gongshow, @witnesses, ...) resolve dynamically from
+the agents that exist when mail is sent. subscribe adds a static member to
+an @group, kept in .beads/groups/; mail to the group reaches both.
+
+  gt mail group subscribe @rig/gongshow mayor/
+  gt mail group unsubscribe @rig/gongshow mayor/`,
 	RunE: requireSubcommand,
 }
 
@@ -93,6 +101,33 @@ var groupDeleteCmd = &cobra.Command{
 	RunE:  runGroupDelete,
 }
 
+var groupSubscribeCmd = &cobra.Command{
+	Use:   "subscribe <@group> <address>",
+	Short: "Add a static member to an @group",
+	Long: `Subscribe an address to an @group address.
+
+The address receives mail sent to the group in addition to the agents the
+group resolves to dynamically, whether or not it would otherwise be a member.
+A group with no dynamic meaning (e.g. @oncall) consists of its static members.
+
+Examples:
+  gt mail group subscribe @rig/gongshow mayor/
+  gt mail group subscribe @oncall gongshow/crew/max`,
+	Args: cobra.ExactArgs(2),
+	RunE: runGroupSubscribe,
+}
+
+var groupUnsubscribeCmd = &cobra.Command{
+	Use:   "unsubscribe <@group> <address>",
+	Short: "Remove a static member from an @group",
+	Long: `Unsubscribe an address from an @group address.
+
+Only static membership is removed: an agent the group resolves to
+dynamically keeps receiving its mail.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runGroupUnsubscribe,
+}
+
 func init() {
 	// List flags
 	groupListCmd.Flags().BoolVar(&groupJSON, "json", false, "Output as JSON")
@@ -110,6 +145,8 @@ func init() {
 	mailGroupCmd.AddCommand(groupAddCmd)
 	mailGroupCmd.AddCommand(groupRemoveCmd)
 	mailGroupCmd.AddCommand(groupDeleteCmd)
+	mailGroupCmd.AddCommand(groupSubscribeCmd)
+	mailGroupCmd.AddCommand(groupUnsubscribeCmd)
 
 	mailCmd.AddCommand(mailGroupCmd)
 }
@@ -306,6 +343,44 @@ func runGroupDelete(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runGroupSubscribe(cmd *cobra.Command, args []string) error {
+	group, address := args[0], args[1]
+
+	if !isValidMemberPattern(address) || strings.HasPrefix(address, "@") {
+		return fmt.Errorf("invalid address: %s", address)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	if err := router.SubscribeGroup(group, address); err != nil {
+		return fmt.Errorf("subscribing: %w", err)
+	}
+
+	fmt.Printf("Subscribed %q to @%s\n", address, strings.TrimPrefix(group, "@"))
+	return nil
+}
+
+func runGroupUnsubscribe(cmd *cobra.Command, args []string) error {
+	group, address := args[0], args[1]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	router := mail.NewRouterWithTownRoot(townRoot, townRoot)
+	if err := router.UnsubscribeGroup(group, address); err != nil {
+		return fmt.Errorf("unsubscribing: %w", err)
+	}
+
+	fmt.Printf("Unsubscribed %q from @%s\n", address, strings.TrimPrefix(group, "@"))
+	return nil
+}
+
 // isValidGroupName checks if a group name is valid.
 // Group names must be alphanumeric with dashes and underscores.
 func isValidGroupName(name string) bool {
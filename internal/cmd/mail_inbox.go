@@ -162,6 +162,9 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 	if msg.ReplyTo != "" {
 		fmt.Printf("Reply-To: %s\n", style.Dim.Render(msg.ReplyTo))
 	}
+	for _, k := range sortedKeys(msg.Metadata) {
+		fmt.Printf("Meta %s: %s\n", k, style.Dim.Render(msg.Metadata[k]))
+	}
 
 	if msg.Body != "" {
 		fmt.Printf("\n%s\n", msg.Body)
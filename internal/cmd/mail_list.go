@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var mailListConfigJSON bool
+
+var mailListListsCmd = &cobra.Command{
+	Use:   "list-lists",
+	Short: "List the mailing lists in messaging.json",
+	Long: `List the mailing lists defined in config/messaging.json.
+
+Shows each list with its member count and how many live agent sessions
+its members (wildcards such as gongshow/polecats/* included) match right now.
+
+Examples:
+  gt mail list-lists
+  gt mail list-lists --json`,
+	Args: cobra.NoArgs,
+	RunE: runMailListLists,
+}
+
+var mailListQueuesCmd = &cobra.Command{
+	Use:   "list-queues",
+	Short: "List the work queues in messaging.json",
+	Long: `List the work queues defined in config/messaging.json.
+
+Shows each queue's worker patterns, its max_claims limit, and how many live
+agent sessions the worker patterns match right now.
+
+Examples:
+  gt mail list-queues
+  gt mail list-queues --json`,
+	Args: cobra.NoArgs,
+	RunE: runMailListQueues,
+}
+
+var mailListAnnouncesCmd = &cobra.Command{
+	Use:   "list-announces",
+	Short: "List the announce channels in messaging.json",
+	Long: `List the announce channels defined in config/messaging.json, with their
+reader patterns and retention. Use 'gt mail announces <channel>' to read one.
+
+Examples:
+  gt mail list-announces
+  gt mail list-announces --json`,
+	Args: cobra.NoArgs,
+	RunE: runMailListAnnounces,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{mailListListsCmd, mailListQueuesCmd, mailListAnnouncesCmd} {
+		c.Flags().BoolVar(&mailListConfigJSON, "json", false, "Output as JSON")
+		mailCmd.AddCommand(c)
+	}
+}
+
+// mailListSessions returns the live tmux sessions; a var for tests.
+var mailListSessions = func() []*tmux.SessionInfo {
+	names, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		return nil
+	}
+	sessions := make([]*tmux.SessionInfo, len(names))
+	for i, name := range names {
+		sessions[i] = &tmux.SessionInfo{Name: name}
+	}
+	return sessions
+}
+
+// loadMessagingConfigForList loads the town's messaging config; a town
+// without one has nothing configured.
+func loadMessagingConfigForList() (*config.MessagingConfig, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+	cfg, err := config.LoadOrCreateMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	return cfg, nil
+}
+
+// sortedConfigNames returns the names in a messaging config map, in order.
+func sortedConfigNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// nameColumnWidth fits the NAME column to the longest name.
+func nameColumnWidth(names []string) int {
+	width := len("NAME")
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	return width
+}
+
+func printConfigJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+type mailListInfo struct {
+	Name         string   `json:"name"`
+	Members      []string `json:"members"`
+	MemberCount  int      `json:"member_count"`
+	LiveSessions []string `json:"live_sessions"`
+}
+
+func runMailListLists(cmd *cobra.Command, args []string) error {
+	cfg, err := loadMessagingConfigForList()
+	if err != nil {
+		return err
+	}
+
+	names := sortedConfigNames(cfg.Lists)
+	sessions := mailListSessions()
+	infos := make([]mailListInfo, 0, len(names))
+	for _, name := range names {
+		members := cfg.Lists[name]
+		infos = append(infos, mailListInfo{
+			Name:         name,
+			Members:      members,
+			MemberCount:  len(members),
+			LiveSessions: nonNilStrings(mail.LiveMatches(members, sessions)),
+		})
+	}
+
+	if mailListConfigJSON {
+		return printConfigJSON(infos)
+	}
+	if len(infos) == 0 {
+		fmt.Printf("%s No mailing lists configured\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Mailing Lists (%d)\n\n", style.Bold.Render("📬"), len(infos))
+	table := style.NewTable(
+		style.Column{Name: "NAME", Width: nameColumnWidth(names)},
+		style.Column{Name: "MEMBERS", Width: 7, Align: style.AlignRight},
+		style.Column{Name: "LIVE", Width: 4, Align: style.AlignRight},
+	)
+	for _, info := range infos {
+		table.AddRow(info.Name, strconv.Itoa(info.MemberCount), strconv.Itoa(len(info.LiveSessions)))
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+type mailQueueInfo struct {
+	Name         string   `json:"name"`
+	Workers      []string `json:"workers"`
+	MaxClaims    int      `json:"max_claims"`
+	LiveSessions []string `json:"live_sessions"`
+}
+
+func runMailListQueues(cmd *cobra.Command, args []string) error {
+	cfg, err := loadMessagingConfigForList()
+	if err != nil {
+		return err
+	}
+
+	names := sortedConfigNames(cfg.Queues)
+	sessions := mailListSessions()
+	infos := make([]mailQueueInfo, 0, len(names))
+	workersWidth := len("WORKERS")
+	for _, name := range names {
+		q := cfg.Queues[name]
+		infos = append(infos, mailQueueInfo{
+			Name:         name,
+			Workers:      q.Workers,
+			MaxClaims:    q.MaxClaims,
+			LiveSessions: nonNilStrings(mail.LiveMatches(q.Workers, sessions)),
+		})
+		if w := len(strings.Join(q.Workers, ", ")); w > workersWidth {
+			workersWidth = w
+		}
+	}
+
+	if mailListConfigJSON {
+		return printConfigJSON(infos)
+	}
+	if len(infos) == 0 {
+		fmt.Printf("%s No queues configured\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Work Queues (%d)\n\n", style.Bold.Render("📥"), len(infos))
+	table := style.NewTable(
+		style.Column{Name: "NAME", Width: nameColumnWidth(names)},
+		style.Column{Name: "WORKERS", Width: workersWidth},
+		style.Column{Name: "MAX CLAIMS", Width: 10, Align: style.AlignRight},
+		style.Column{Name: "LIVE", Width: 4, Align: style.AlignRight},
+	)
+	for _, info := range infos {
+		maxClaims := "unlimited"
+		if info.MaxClaims > 0 {
+			maxClaims = strconv.Itoa(info.MaxClaims)
+		}
+		table.AddRow(info.Name, strings.Join(info.Workers, ", "), maxClaims, strconv.Itoa(len(info.LiveSessions)))
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+type mailAnnounceInfo struct {
+	Name        string   `json:"name"`
+	Readers     []string `json:"readers"`
+	RetainCount int      `json:"retain_count"`
+}
+
+func runMailListAnnounces(cmd *cobra.Command, args []string) error {
+	cfg, err := loadMessagingConfigForList()
+	if err != nil {
+		return err
+	}
+
+	names := sortedConfigNames(cfg.Announces)
+	infos := make([]mailAnnounceInfo, 0, len(names))
+	readersWidth := len("READERS")
+	for _, name := range names {
+		a := cfg.Announces[name]
+		infos = append(infos, mailAnnounceInfo{Name: name, Readers: a.Readers, RetainCount: a.RetainCount})
+		if w := len(strings.Join(a.Readers, ", ")); w > readersWidth {
+			readersWidth = w
+		}
+	}
+
+	if mailListConfigJSON {
+		return printConfigJSON(infos)
+	}
+	if len(infos) == 0 {
+		fmt.Printf("%s No announce channels configured\n", style.Dim.Render("○"))
+		return nil
+	}
+
+	fmt.Printf("%s Announce Channels (%d)\n\n", style.Bold.Render("📢"), len(infos))
+	table := style.NewTable(
+		style.Column{Name: "NAME", Width: nameColumnWidth(names)},
+		style.Column{Name: "READERS", Width: readersWidth},
+		style.Column{Name: "RETAIN", Width: 9, Align: style.AlignRight},
+	)
+	for _, info := range infos {
+		retain := "unlimited"
+		if info.RetainCount > 0 {
+			retain = strconv.Itoa(info.RetainCount)
+		}
+		table.AddRow(info.Name, strings.Join(info.Readers, ", "), retain)
+	}
+	fmt.Print(table.Render())
+	return nil
+}
+
+// nonNilStrings makes a nil slice encode as [] rather than null.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
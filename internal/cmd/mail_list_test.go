@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+const testMessagingJSON = `{
+  "type": "messaging",
+  "version": 1,
+  "lists": {
+    "oncall": ["mayor/", "gongshow/witness"],
+    "all-polecats": ["*/polecats/*"]
+  },
+  "queues": {
+    "work/gongshow": {"workers": ["gongshow/polecats/*"], "max_claims": 2},
+    "triage": {"workers": ["gongshow/crew/max"]}
+  },
+  "announces": {
+    "alerts": {"readers": ["@town"], "retain_count": 50}
+  }
+}`
+
+// setupMailListTown creates a town with a synthetic messaging.json and a
+// fixed set of live sessions, and makes it the working directory.
+func setupMailListTown(t *testing.T) {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town","version":2,"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "config", "messaging.json"), []byte(testMessagingJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	orig := mailListSessions
+	mailListSessions = func() []*tmux.SessionInfo {
+		return []*tmux.SessionInfo{
+			{Name: "hq-mayor"},
+			{Name: "gt-gongshow-Toast"},
+			{Name: "gt-gongshow-Nux"},
+			{Name: "gt-other-Slit"},
+		}
+	}
+	origJSON := mailListConfigJSON
+	t.Cleanup(func() {
+		mailListSessions = orig
+		mailListConfigJSON = origJSON
+	})
+}
+
+// tableRow returns the fields of the output line whose first field is name.
+func tableRow(t *testing.T, out, name string) []string {
+	t.Helper()
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == name {
+			return fields
+		}
+	}
+	t.Fatalf("no row for %q in:\n%s", name, out)
+	return nil
+}
+
+func TestMailListLists(t *testing.T) {
+	setupMailListTown(t)
+
+	out := captureStdout(t, func() {
+		if err := runMailListLists(nil, nil); err != nil {
+			t.Fatalf("runMailListLists: %v", err)
+		}
+	})
+	if !strings.Contains(out, "Mailing Lists (2)") {
+		t.Errorf("missing header:\n%s", out)
+	}
+	// NAME MEMBERS LIVE: the mayor is up, the witness isn't; the wildcard
+	// matches all three polecats.
+	if got := strings.Join(tableRow(t, out, "oncall"), " "); got != "oncall 2 1" {
+		t.Errorf("oncall row = %q, want %q", got, "oncall 2 1")
+	}
+	if got := strings.Join(tableRow(t, out, "all-polecats"), " "); got != "all-polecats 1 3" {
+		t.Errorf("all-polecats row = %q, want %q", got, "all-polecats 1 3")
+	}
+	// Rows are sorted by name.
+	if strings.Index(out, "all-polecats") > strings.Index(out, "oncall") {
+		t.Errorf("lists not sorted:\n%s", out)
+	}
+}
+
+func TestMailListQueues(t *testing.T) {
+	setupMailListTown(t)
+
+	out := captureStdout(t, func() {
+		if err := runMailListQueues(nil, nil); err != nil {
+			t.Fatalf("runMailListQueues: %v", err)
+		}
+	})
+	if got := strings.Join(tableRow(t, out, "work/gongshow"), " "); got != "work/gongshow gongshow/polecats/* 2 2" {
+		t.Errorf("work/gongshow row = %q", got)
+	}
+	if got := strings.Join(tableRow(t, out, "triage"), " "); got != "triage gongshow/crew/max unlimited 0" {
+		t.Errorf("triage row = %q", got)
+	}
+}
+
+func TestMailListQueuesJSON(t *testing.T) {
+	setupMailListTown(t)
+	mailListConfigJSON = true
+
+	out := captureStdout(t, func() {
+		if err := runMailListQueues(nil, nil); err != nil {
+			t.Fatalf("runMailListQueues: %v", err)
+		}
+	})
+	var queues []mailQueueInfo
+	if err := json.Unmarshal([]byte(out), &queues); err != nil {
+		t.Fatalf("parsing JSON: %v\n%s", err, out)
+	}
+	if len(queues) != 2 || queues[0].Name != "triage" || queues[1].Name != "work/gongshow" {
+		t.Fatalf("queues = %+v", queues)
+	}
+	if q := queues[1]; q.MaxClaims != 2 || strings.Join(q.LiveSessions, ",") != "gongshow/polecats/Toast,gongshow/polecats/Nux" {
+		t.Errorf("work/gongshow = %+v", q)
+	}
+	if queues[0].LiveSessions == nil {
+		t.Error("live_sessions should encode as [], not null")
+	}
+}
+
+func TestMailListAnnounces(t *testing.T) {
+	setupMailListTown(t)
+
+	out := captureStdout(t, func() {
+		if err := runMailListAnnounces(nil, nil); err != nil {
+			t.Fatalf("runMailListAnnounces: %v", err)
+		}
+	})
+	if got := strings.Join(tableRow(t, out, "alerts"), " "); got != "alerts @town 50" {
+		t.Errorf("alerts row = %q", got)
+	}
+}
+
+func TestMailListWithoutMessagingConfig(t *testing.T) {
+	setupMailListTown(t)
+	if err := os.Remove(filepath.Join("config", "messaging.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runMailListLists(nil, nil); err != nil {
+			t.Fatalf("runMailListLists: %v", err)
+		}
+	})
+	if !strings.Contains(out, "No mailing lists configured") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/events"
 	"github.com/KeithWyatt/gongshow/internal/style"
 	"github.com/KeithWyatt/gongshow/internal/workspace"
 )
@@ -102,6 +103,8 @@ func runMailClaim(cmd *cobra.Command, args []string) error {
 	if err := claimQueueMessage(beadsDir, oldest.ID, caller); err != nil {
 		return fmt.Errorf("claiming message: %w", err)
 	}
+	_ = events.Log(events.TypeQueueClaim, caller,
+		events.QueueClaimPayload(queueName, oldest.ID, caller), events.VisibilityBoth)
 
 	// Print claimed message details
 	fmt.Printf("%s Claimed message from queue %s\n", style.Bold.Render("✓"), queueName)
@@ -246,10 +249,17 @@ func claimQueueMessage(beadsDir, messageID, claimant string) error {
 	return nil
 }
 
-// runMailRelease releases a claimed queue message back to its queue.
+// runMailRelease releases a claimed queue message back to its queue, or
+// closes it when the work is completed.
 func runMailRelease(cmd *cobra.Command, args []string) error {
 	messageID := args[0]
 
+	switch mailReleaseStatus {
+	case events.QueueStatusReleased, events.QueueStatusCompleted, events.QueueStatusFailed:
+	default:
+		return fmt.Errorf("invalid --status %q: want released, completed, or failed", mailReleaseStatus)
+	}
+
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
@@ -280,12 +290,30 @@ func runMailRelease(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("message %s was claimed by %s, not %s", messageID, msgInfo.ClaimedBy, caller)
 	}
 
-	// Release the message: remove claimed-by and claimed-at labels
-	if err := releaseQueueMessage(beadsDir, messageID, caller); err != nil {
-		return fmt.Errorf("releasing message: %w", err)
+	if mailReleaseStatus == events.QueueStatusCompleted {
+		// Completed work leaves the queue: close the message, keeping its
+		// claim labels as a record of who did it
+		bd := beads.NewWithBeadsDir(townRoot, beadsDir)
+		if err := bd.CloseWithReason("completed by "+caller, messageID); err != nil {
+			return fmt.Errorf("completing message: %w", err)
+		}
+	} else {
+		// Release the message: remove claimed-by and claimed-at labels
+		if err := releaseQueueMessage(beadsDir, messageID, caller); err != nil {
+			return fmt.Errorf("releasing message: %w", err)
+		}
 	}
+	_ = events.Log(events.TypeQueueRelease, caller,
+		events.QueueReleasePayload(msgInfo.QueueName, messageID, msgInfo.ClaimedBy, mailReleaseStatus), events.VisibilityBoth)
 
-	fmt.Printf("%s Released message back to queue %s\n", style.Bold.Render("✓"), msgInfo.QueueName)
+	switch mailReleaseStatus {
+	case events.QueueStatusCompleted:
+		fmt.Printf("%s Completed message from queue %s\n", style.Bold.Render("✓"), msgInfo.QueueName)
+	case events.QueueStatusFailed:
+		fmt.Printf("%s Released failed message back to queue %s\n", style.Bold.Render("✓"), msgInfo.QueueName)
+	default:
+		fmt.Printf("%s Released message back to queue %s\n", style.Bold.Render("✓"), msgInfo.QueueName)
+	}
 	fmt.Printf("  ID: %s\n", messageID)
 	fmt.Printf("  Subject: %s\n", msgInfo.Title)
 
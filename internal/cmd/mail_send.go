@@ -117,12 +117,19 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 
 	recipients, err := resolver.Resolve(to)
 	if err != nil {
-		// Fall back to legacy routing if resolver fails
 		router := mail.NewRouter(workDir)
-		if err := router.Send(msg); err != nil {
+		if mailBounce {
+			// Unknown address: return it to the sender rather than guessing
+			return bounceMail(router, msg, err.Error())
+		}
+
+		// Fall back to legacy routing if resolver fails
+		if err := router.SendContext(cmd.Context(), msg); err != nil {
 			return fmt.Errorf("sending message: %w", err)
 		}
-		_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
+		if !router.FilterWisp(msg) {
+			_ = events.LogFeedContext(cmd.Context(), events.TypeMail, from, events.MailPayload(to, mailSubject))
+		}
 		fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
 		fmt.Printf("  Subject: %s\n", mailSubject)
 		return nil
@@ -137,7 +144,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		case mail.RecipientQueue:
 			// Queue messages: single message, workers claim
 			msg.To = rec.Address
-			if err := router.Send(msg); err != nil {
+			if err := router.SendContext(cmd.Context(), msg); err != nil {
 				return fmt.Errorf("sending to queue: %w", err)
 			}
 			recipientAddrs = append(recipientAddrs, rec.Address)
@@ -145,7 +152,7 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		case mail.RecipientChannel:
 			// Channel messages: single message, broadcast
 			msg.To = rec.Address
-			if err := router.Send(msg); err != nil {
+			if err := router.SendContext(cmd.Context(), msg); err != nil {
 				return fmt.Errorf("sending to channel: %w", err)
 			}
 			recipientAddrs = append(recipientAddrs, rec.Address)
@@ -154,15 +161,23 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 			// Direct/agent messages: fan out to each recipient
 			msgCopy := *msg
 			msgCopy.To = rec.Address
-			if err := router.Send(&msgCopy); err != nil {
-				return fmt.Errorf("sending to %s: %w", rec.Address, err)
+			if err := router.SendContext(cmd.Context(), &msgCopy); err != nil {
+				if !mailBounce {
+					return fmt.Errorf("sending to %s: %w", rec.Address, err)
+				}
+				if err := bounceMail(router, &msgCopy, err.Error()); err != nil {
+					return err
+				}
+				continue
 			}
 			recipientAddrs = append(recipientAddrs, rec.Address)
 		}
 	}
 
-	// Log mail event to activity feed
-	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
+	// Log mail event to activity feed, unless it is a suppressed wisp
+	if !router.FilterWisp(msg) {
+		_ = events.LogFeedContext(cmd.Context(), events.TypeMail, from, events.MailPayload(to, mailSubject))
+	}
 
 	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
 	fmt.Printf("  Subject: %s\n", mailSubject)
@@ -182,6 +197,16 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// bounceMail returns an undeliverable message to its sender and reports it.
+func bounceMail(router *mail.Router, msg *mail.Message, reason string) error {
+	if err := router.Bounce(msg, reason); err != nil {
+		return fmt.Errorf("sending to %s: %s (bounce failed: %w)", msg.To, reason, err)
+	}
+	fmt.Printf("%s Message to %s bounced back to %s\n", style.WarningPrefix, msg.To, msg.From)
+	fmt.Printf("  Reason: %s\n", reason)
+	return nil
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
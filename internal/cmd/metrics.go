@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/metrics"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+var (
+	metricsListen       string
+	metricsBeadsTimeout time.Duration
+)
+
+var metricsCmd = &cobra.Command{
+	Use:     "metrics",
+	GroupID: GroupDiag,
+	Short:   "Export town health metrics",
+	RunE:    requireSubcommand,
+}
+
+var metricsServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve Prometheus metrics over HTTP",
+	Long: `Start an HTTP server exposing town health at /metrics in the Prometheus
+text exposition format. Metrics are computed on each scrape:
+
+  gt_sessions{role}                 Live agent sessions by role
+  gt_escalations_open{severity}     Open escalations by severity
+  gt_queue_depth{queue}             Unclaimed messages by mail queue
+  gt_events_total{type}             Events logged since the exporter started
+  gt_events_last_interval{type}     Events logged since the previous scrape
+  gt_bd_daemons{status}             bd daemons by health status
+  gt_beads_stale                    1 if bd was down or slow this scrape
+
+If bd doesn't answer within --beads-timeout, the last values it returned are
+exported with gt_beads_stale set, so scrapes stay fast while bd is down.
+
+Example:
+  gt metrics serve                  # Listen on :9321
+  gt metrics serve --listen :9400`,
+	RunE: runMetricsServe,
+}
+
+func init() {
+	metricsServeCmd.Flags().StringVar(&metricsListen, "listen", ":9321", "Address to listen on")
+	metricsServeCmd.Flags().DurationVar(&metricsBeadsTimeout, "beads-timeout", metrics.DefaultBeadsTimeout, "How long each scrape waits for bd")
+	metricsCmd.AddCommand(metricsServeCmd)
+	rootCmd.AddCommand(metricsCmd)
+}
+
+func runMetricsServe(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	source, err := metrics.NewLiveSource(townRoot)
+	if err != nil {
+		return fmt.Errorf("creating metrics source: %w", err)
+	}
+	defer func() { _ = source.Close() }()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.NewHandler(source, metricsBeadsTimeout))
+
+	fmt.Printf("📈 GongShow metrics at http://%s/metrics\n", metricsListen)
+	fmt.Printf("   Press Ctrl+C to stop\n")
+
+	server := &http.Server{
+		Addr:              metricsListen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      60 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+	return server.ListenAndServe()
+}
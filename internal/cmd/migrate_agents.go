@@ -207,6 +207,7 @@ func migrateAgentBead(sourceBd, targetBd *beads.Beads, oldID, newID, desc string
 		CleanupStatus:     oldFields.CleanupStatus,
 		ActiveMR:          oldFields.ActiveMR,
 		NotificationLevel: oldFields.NotificationLevel,
+		WorkSummary:       oldFields.WorkSummary,
 	}
 
 	_, err = targetBd.CreateAgentBead(newID, desc, newFields)
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/notify"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+// Notify history flags
+var (
+	notifyHistoryEscalation string
+	notifyHistoryChannel    string
+	notifyHistoryFailed     bool
+	notifyHistorySince      string
+	notifyHistoryJSON       bool
+)
+
+var notifyHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show external notification delivery history",
+	Long: `Show every external notification attempt (email, sms, slack, log).
+
+Each entry records when the attempt was made, which escalation it was for,
+the channel, the recipient (masked), whether it succeeded, and how long it took.
+Sends held back by quiet hours or rate limits are shown as deferred.
+
+History is kept in <town>/logs/notify-history.jsonl and rotated by size.
+
+Examples:
+  gt notify history                          # All recorded attempts
+  gt notify history --escalation hq-abc      # Who was told about one escalation
+  gt notify history --channel slack --failed # Failed Slack posts
+  gt notify history --since 24h --json       # Last day, machine-readable`,
+	Args: cobra.NoArgs,
+	RunE: runNotifyHistory,
+}
+
+func init() {
+	notifyHistoryCmd.Flags().StringVar(&notifyHistoryEscalation, "escalation", "", "Only show attempts for this escalation ID")
+	notifyHistoryCmd.Flags().StringVar(&notifyHistoryChannel, "channel", "", "Only show this channel (email, sms, slack, log)")
+	notifyHistoryCmd.Flags().BoolVar(&notifyHistoryFailed, "failed", false, "Only show failed attempts")
+	notifyHistoryCmd.Flags().StringVar(&notifyHistorySince, "since", "", "Only show attempts within this duration (e.g., 1h, 24h)")
+	notifyHistoryCmd.Flags().BoolVar(&notifyHistoryJSON, "json", false, "Output as JSON")
+
+	notifyCmd.AddCommand(notifyHistoryCmd)
+}
+
+func runNotifyHistory(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+
+	filter := notify.HistoryFilter{
+		Escalation: notifyHistoryEscalation,
+		Channel:    notifyHistoryChannel,
+		FailedOnly: notifyHistoryFailed,
+	}
+	if notifyHistorySince != "" {
+		duration, err := time.ParseDuration(notifyHistorySince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		filter.Since = time.Now().Add(-duration)
+	}
+
+	entries, err := notify.ReadHistory(townRoot, filter)
+	if err != nil {
+		return fmt.Errorf("reading notify history: %w", err)
+	}
+
+	if notifyHistoryJSON {
+		if entries == nil {
+			entries = []*notify.HistoryEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println(style.Dim.Render("No notification attempts recorded"))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tESCALATION\tCHANNEL\tRECIPIENT\tSTATUS\tLATENCY\tERROR")
+	for _, e := range entries {
+		status := "ok"
+		switch {
+		case e.Deferred:
+			status = "deferred"
+		case !e.Success:
+			status = "failed"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%dms\t%s\n",
+			e.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			e.Escalation, e.Channel, e.Recipient, status, e.LatencyMs, e.Error)
+	}
+	return w.Flush()
+}
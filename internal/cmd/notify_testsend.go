@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/notify"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+// Notify test flags
+var (
+	notifyTestChannel  string
+	notifyTestSeverity string
+	notifyTestDryRun   bool
+)
+
+// notifyTestChannels are the channels "gt notify test --channel all" covers.
+var notifyTestChannels = []string{
+	notify.ChannelEmail, notify.ChannelSMS, notify.ChannelSlack, notify.ChannelTeams, notify.ChannelLog,
+}
+
+// notifyTestSend delivers the test notification. Replaced in tests.
+var notifyTestSend = notify.SendNow
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a test notification through external channels",
+	Long: `Send a clearly labeled test notification through the configured
+external channels, so broken credentials are found before a real incident.
+
+Recipients come from contacts in settings/escalation.json, the same as for
+real escalations. The test is sent straight away: notify.json rate limits
+and quiet hours don't apply to it, and it is never spooled for later.
+A per-channel pass/fail table is printed, and the command exits non-zero if
+any requested channel fails.
+
+With --channel all, channels that have no contact configured are skipped.
+Naming a channel explicitly treats a missing contact as a failure.
+
+Examples:
+  gt notify test                         # Test every configured channel
+  gt notify test --channel slack         # Test only Slack
+  gt notify test --severity critical     # Test with critical severity
+  gt notify test --dry-run               # Show what would be sent`,
+	Args: cobra.NoArgs,
+	RunE: runNotifyTest,
+}
+
+func init() {
+	notifyTestCmd.Flags().StringVar(&notifyTestChannel, "channel", "all", "Channel to test: email, sms, slack, teams, log, or all")
+	notifyTestCmd.Flags().StringVar(&notifyTestSeverity, "severity", config.SeverityHigh, "Severity of the test notification")
+	notifyTestCmd.Flags().BoolVarP(&notifyTestDryRun, "dry-run", "n", false, "Print what would be sent without sending")
+
+	notifyCmd.AddCommand(notifyTestCmd)
+}
+
+// notifyTestRow is one line of the pass/fail table.
+type notifyTestRow struct {
+	Channel string
+	Status  string // pass, fail, skipped
+	Detail  string
+}
+
+func runNotifyTest(cmd *cobra.Command, args []string) error {
+	if !config.IsValidSeverity(notifyTestSeverity) {
+		return fmt.Errorf("invalid severity %q: must be one of %s",
+			notifyTestSeverity, strings.Join(config.ValidSeverities(), ", "))
+	}
+
+	channels, explicit, err := parseNotifyTestChannel(notifyTestChannel)
+	if err != nil {
+		return err
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a GongShow workspace: %w", err)
+	}
+	escCfg, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading escalation config: %w", err)
+	}
+
+	n := newTestNotification(notifyTestSeverity, detectSender(), time.Now())
+	targets, rows := notifyTestTargets(channels, explicit, escCfg.Contacts)
+
+	if notifyTestDryRun {
+		printNotifyTestDryRun(townRoot, targets, n, rows)
+		return nil
+	}
+
+	if len(targets) > 0 {
+		rows = append(rows, notifyTestRows(notifyTestSend(townRoot, targets, n))...)
+	}
+	failed := printNotifyTestTable(rows)
+	if failed > 0 {
+		return fmt.Errorf("%d channel(s) failed", failed)
+	}
+	return nil
+}
+
+// parseNotifyTestChannel expands the --channel flag. explicit is false for "all".
+func parseNotifyTestChannel(channel string) (channels []string, explicit bool, err error) {
+	if channel == "" || channel == "all" {
+		return notifyTestChannels, false, nil
+	}
+	for _, c := range notifyTestChannels {
+		if c == channel {
+			return []string{channel}, true, nil
+		}
+	}
+	return nil, false, fmt.Errorf("invalid channel %q: use email, sms, slack, teams, log, or all", channel)
+}
+
+// newTestNotification builds a synthetic notification that is obviously a test.
+func newTestNotification(severity, source string, now time.Time) *notify.Notification {
+	return &notify.Notification{
+		ID:       fmt.Sprintf("notify-test-%d", now.Unix()),
+		Severity: severity,
+		Title:    "TEST - GongShow notification check (no action needed)",
+		Body: "This is a test notification sent by `gt notify test` to verify channel " +
+			"configuration. It is not a real escalation and can be ignored.",
+		Source:    source,
+		Timestamp: now,
+	}
+}
+
+// notifyTestTargets maps channels to targets using the escalation contacts.
+// Channels without a contact become skipped rows, or failed rows if the
+// channel was requested explicitly.
+func notifyTestTargets(channels []string, explicit bool, contacts config.EscalationContacts) ([]notify.Target, []notifyTestRow) {
+	addresses := map[string]struct{ addr, setting string }{
+		notify.ChannelEmail: {contacts.HumanEmail, "contacts.human_email"},
+		notify.ChannelSMS:   {contacts.HumanSMS, "contacts.human_sms"},
+		notify.ChannelSlack: {contacts.SlackWebhook, "contacts.slack_webhook"},
+		notify.ChannelTeams: {contacts.TeamsWebhook, "contacts.teams_webhook"},
+	}
+
+	var targets []notify.Target
+	var rows []notifyTestRow
+	for _, ch := range channels {
+		if ch == notify.ChannelLog {
+			targets = append(targets, notify.Target{Channel: ch})
+			continue
+		}
+		a := addresses[ch]
+		if a.addr == "" {
+			status := "skipped"
+			if explicit {
+				status = "fail"
+			}
+			rows = append(rows, notifyTestRow{Channel: ch, Status: status, Detail: a.setting + " not configured in settings/escalation.json"})
+			continue
+		}
+		targets = append(targets, notify.Target{Channel: ch, Address: a.addr})
+	}
+	return targets, rows
+}
+
+// notifyTestRows converts send results to table rows.
+func notifyTestRows(results []*notify.Result) []notifyTestRow {
+	rows := make([]notifyTestRow, 0, len(results))
+	for _, r := range results {
+		row := notifyTestRow{Channel: r.Channel, Status: "pass", Detail: r.Message}
+		if !r.Success {
+			row.Status = "fail"
+			if r.Error != nil {
+				row.Detail = r.Error.Error()
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// printNotifyTestTable prints the results and returns the number of failures.
+func printNotifyTestTable(rows []notifyTestRow) int {
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANNEL\tRESULT\tDETAIL")
+	for _, row := range rows {
+		if row.Status == "fail" {
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", row.Channel, row.Status, row.Detail)
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+	if failed > 0 {
+		fmt.Printf("%s %d of %d channel(s) failed\n", style.WarningPrefix, failed, len(rows))
+	} else {
+		fmt.Printf("%s All tested channels passed\n", style.SuccessPrefix)
+	}
+	return failed
+}
+
+func printNotifyTestDryRun(townRoot string, targets []notify.Target, n *notify.Notification, rows []notifyTestRow) {
+	fmt.Printf("%s Dry run: nothing will be sent\n\n", style.Bold.Render("notify test"))
+	for _, t := range targets {
+		p := notify.Render(townRoot, t, n)
+		fmt.Printf("%s → %s\n", style.Bold.Render(p.Channel), p.Recipient)
+		if p.Subject != "" {
+			fmt.Printf("  Subject: %s\n", p.Subject)
+		}
+		for _, line := range strings.Split(p.Body, "\n") {
+			fmt.Printf("  %s\n", style.Dim.Render(line))
+		}
+		fmt.Println()
+	}
+	for _, row := range rows {
+		fmt.Printf("%s %s: %s\n", style.WarningPrefix, row.Channel, row.Detail)
+	}
+}
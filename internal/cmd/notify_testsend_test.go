@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/notify"
+)
+
+func TestParseNotifyTestChannel(t *testing.T) {
+	if chans, explicit, err := parseNotifyTestChannel("all"); err != nil || explicit || len(chans) != len(notifyTestChannels) {
+		t.Errorf("all: got %v, %v, %v", chans, explicit, err)
+	}
+	if chans, explicit, err := parseNotifyTestChannel("slack"); err != nil || !explicit || len(chans) != 1 {
+		t.Errorf("slack: got %v, %v, %v", chans, explicit, err)
+	}
+	if _, _, err := parseNotifyTestChannel("pager"); err == nil {
+		t.Error("expected error for unknown channel")
+	}
+}
+
+func TestNewTestNotificationIsLabeled(t *testing.T) {
+	n := newTestNotification("high", "mayor", time.Unix(1700000000, 0))
+	if n.ID != "notify-test-1700000000" {
+		t.Errorf("ID = %q", n.ID)
+	}
+	if n.Title == "" || n.Title[:4] != "TEST" {
+		t.Errorf("title should be labeled as a test: %q", n.Title)
+	}
+}
+
+func TestNotifyTestTargets(t *testing.T) {
+	contacts := config.EscalationContacts{
+		HumanEmail:   "oncall@example.com",
+		SlackWebhook: "https://hooks.slack.com/services/x",
+	}
+
+	// "all" skips unconfigured channels without failing.
+	targets, rows := notifyTestTargets(notifyTestChannels, false, contacts)
+	if len(targets) != 3 { // email, slack, log
+		t.Errorf("expected 3 targets, got %+v", targets)
+	}
+	for _, row := range rows {
+		if row.Status != "skipped" {
+			t.Errorf("unconfigured %s should be skipped, got %s", row.Channel, row.Status)
+		}
+	}
+
+	// An explicitly requested but unconfigured channel fails.
+	targets, rows = notifyTestTargets([]string{notify.ChannelSMS}, true, contacts)
+	if len(targets) != 0 || len(rows) != 1 || rows[0].Status != "fail" {
+		t.Errorf("explicit sms without contact: targets=%v rows=%+v", targets, rows)
+	}
+}
+
+func TestNotifyTestAggregation(t *testing.T) {
+	orig := notifyTestSend
+	defer func() { notifyTestSend = orig }()
+
+	notifyTestSend = func(townRoot string, targets []notify.Target, n *notify.Notification) []*notify.Result {
+		var results []*notify.Result
+		for _, tgt := range targets {
+			switch tgt.Channel {
+			case notify.ChannelEmail:
+				results = append(results, &notify.Result{Channel: tgt.Channel, Error: errors.New("authentication failed: 535")})
+			default:
+				results = append(results, &notify.Result{Channel: tgt.Channel, Success: true, Message: "ok"})
+			}
+		}
+		return results
+	}
+
+	targets := []notify.Target{{Channel: notify.ChannelEmail}, {Channel: notify.ChannelSlack}, {Channel: notify.ChannelLog}}
+	rows := notifyTestRows(notifyTestSend("/town", targets, newTestNotification("high", "mayor", time.Now())))
+
+	want := map[string]string{notify.ChannelEmail: "fail", notify.ChannelSlack: "pass", notify.ChannelLog: "pass"}
+	for _, row := range rows {
+		if row.Status != want[row.Channel] {
+			t.Errorf("%s: status %s, want %s", row.Channel, row.Status, want[row.Channel])
+		}
+	}
+	if rows[0].Detail != "authentication failed: 535" {
+		t.Errorf("failure detail = %q", rows[0].Detail)
+	}
+
+	if failed := printNotifyTestTable(rows); failed != 1 {
+		t.Errorf("printNotifyTestTable reported %d failures, want 1", failed)
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -18,11 +19,13 @@ import (
 
 var nudgeMessageFlag string
 var nudgeForceFlag bool
+var nudgeRegexFlag bool
 
 func init() {
 	rootCmd.AddCommand(nudgeCmd)
 	nudgeCmd.Flags().StringVarP(&nudgeMessageFlag, "message", "m", "", "Message to send")
 	nudgeCmd.Flags().BoolVarP(&nudgeForceFlag, "force", "f", false, "Send even if target has DND enabled")
+	nudgeCmd.Flags().BoolVar(&nudgeRegexFlag, "regex", false, "Match the target, or a channel's patterns, as regular expressions against session names")
 }
 
 var nudgeCmd = &cobra.Command{
@@ -50,6 +53,11 @@ Channel syntax:
                   ~/gt/config/messaging.json under "nudge_channels".
                   Patterns like "gongshow/polecats/*" are expanded.
 
+Regex matching:
+  With --regex, the target (or each pattern of a channel target) is a Go
+  regular expression that must match a whole session name, and every
+  matching session is nudged. Named capture groups are not allowed.
+
 DND (Do Not Disturb):
   If the target has DND enabled (gt dnd on), the nudge is skipped.
   Use --force to override DND and send anyway.
@@ -60,7 +68,8 @@ Examples:
   gt nudge mayor "Status update requested"
   gt nudge witness "Check polecat health"
   gt nudge deacon session-started
-  gt nudge channel:workers "New priority work available"`,
+  gt nudge channel:workers "New priority work available"
+  gt nudge --regex 'gt-gongshow-crew-.*' "Standup in 5"`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runNudge,
 }
@@ -83,6 +92,9 @@ func runNudge(cmd *cobra.Command, args []string) error {
 		channelName := strings.TrimPrefix(target, "channel:")
 		return runNudgeChannel(channelName, message)
 	}
+	if nudgeRegexFlag {
+		return runNudgePatterns(fmt.Sprintf("regex %q", target), "regex:"+target, []string{target}, true, message)
+	}
 
 	// Identify sender for message prefix
 	sender := "unknown"
@@ -269,6 +281,24 @@ func runNudgeChannel(channelName, message string) error {
 		return fmt.Errorf("nudge channel %q has no members", channelName)
 	}
 
+	// Channel members are globs, even with --regex.
+	return runNudgePatterns(fmt.Sprintf("channel %q", channelName), "channel:"+channelName, patterns, false, message)
+}
+
+// runNudgePatterns nudges every session matching any of patterns, which
+// are regexes if isRegex. label names the patterns in output, and
+// eventTarget is the target the nudge event records. Sessions matched by
+// regex are each checked for DND, as a single target is; channel members
+// aren't.
+func runNudgePatterns(label, eventTarget string, patterns []string, isRegex bool, message string) error {
+	if isRegex {
+		for _, pattern := range patterns {
+			if _, err := compileNudgeRegex(pattern); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Identify sender for message prefix
 	sender := "unknown"
 	if roleInfo, err := GetRole(); err == nil {
@@ -304,7 +334,7 @@ func runNudgeChannel(channelName, message string) error {
 	seenTargets := make(map[string]bool)
 
 	for _, pattern := range patterns {
-		resolved := resolveNudgePattern(pattern, agents)
+		resolved := resolveNudgePattern(pattern, agents, isRegex)
 		for _, sessionName := range resolved {
 			if !seenTargets[sessionName] {
 				seenTargets[sessionName] = true
@@ -314,16 +344,37 @@ func runNudgeChannel(channelName, message string) error {
 	}
 
 	if len(targets) == 0 {
-		fmt.Printf("%s No sessions match channel %q patterns\n", style.WarningPrefix, channelName)
+		fmt.Printf("%s No sessions match %s\n", style.WarningPrefix, label)
 		return nil
 	}
 
+	if isRegex {
+		townRoot, _ := workspace.FindFromCwd()
+		var skipped []string
+		targets, skipped = filterNudgeDND(targets, agents, func(address string) (bool, string) {
+			if townRoot == "" {
+				return true, ""
+			}
+			send, level, _ := shouldNudgeTarget(townRoot, address, nudgeForceFlag)
+			return send, level
+		})
+		for _, s := range skipped {
+			fmt.Printf("%s %s has DND enabled - nudge skipped\n", style.Dim.Render("○"), s)
+		}
+		if len(skipped) > 0 {
+			fmt.Printf("  Use %s to override\n", style.Bold.Render("--force"))
+		}
+		if len(targets) == 0 {
+			return nil
+		}
+	}
+
 	// Send nudges
 	t := tmux.NewTmux()
 	var succeeded, failed int
 	var failures []string
 
-	fmt.Printf("Nudging channel %q (%d target(s))...\n\n", channelName, len(targets))
+	fmt.Printf("Nudging %s (%d target(s))...\n\n", label, len(targets))
 
 	for i, sessionName := range targets {
 		if err := t.NudgeSession(sessionName, prefixedMessage); err != nil {
@@ -344,12 +395,12 @@ func runNudgeChannel(channelName, message string) error {
 	fmt.Println()
 
 	// Log nudge event
-	if err := events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", "channel:"+channelName, message)); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to log channel nudge event: %v\n", err)
+	if err := events.LogFeed(events.TypeNudge, sender, events.NudgePayload("", eventTarget, message)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to log nudge event: %v\n", err)
 	}
 
 	if failed > 0 {
-		fmt.Printf("%s Channel nudge complete: %d succeeded, %d failed\n",
+		fmt.Printf("%s Nudge complete: %d succeeded, %d failed\n",
 			style.WarningPrefix, succeeded, failed)
 		for _, f := range failures {
 			fmt.Printf("  %s\n", style.Dim.Render(f))
@@ -357,10 +408,34 @@ func runNudgeChannel(channelName, message string) error {
 		return fmt.Errorf("%d nudge(s) failed", failed)
 	}
 
-	fmt.Printf("%s Channel nudge complete: %d target(s) nudged\n", style.SuccessPrefix, succeeded)
+	fmt.Printf("%s Nudge complete: %d target(s) nudged\n", style.SuccessPrefix, succeeded)
 	return nil
 }
 
+// filterNudgeDND splits targets, session names from agents, into those
+// allow lets be nudged, by the agent's address, and those it doesn't,
+// described with their notification level. A session that isn't among
+// agents is allowed.
+func filterNudgeDND(targets []string, agents []*AgentSession, allow func(address string) (bool, string)) (send, skipped []string) {
+	byName := make(map[string]*AgentSession, len(agents))
+	for _, agent := range agents {
+		byName[agent.Name] = agent
+	}
+	for _, name := range targets {
+		agent, ok := byName[name]
+		if !ok {
+			send = append(send, name)
+			continue
+		}
+		if ok, level := allow(agent.address()); !ok {
+			skipped = append(skipped, fmt.Sprintf("%s (%s)", name, level))
+			continue
+		}
+		send = append(send, name)
+	}
+	return send, skipped
+}
+
 // resolveNudgePattern resolves a nudge channel pattern to session names.
 // Patterns can be:
 //   - Literal: "gongshow/witness" → gt-gongshow-witness
@@ -368,9 +443,26 @@ func runNudgeChannel(channelName, message string) error {
 //   - Role: "*/witness" → all witness sessions
 //   - Special: "mayor", "deacon" → gt-{town}-mayor, gt-{town}-deacon
 // townName is used to generate the correct session names for mayor/deacon.
-func resolveNudgePattern(pattern string, agents []*AgentSession) []string {
+//
+// With isRegex, pattern is instead a regular expression matched against
+// whole session names (see compileNudgeRegex); an invalid one matches
+// nothing.
+func resolveNudgePattern(pattern string, agents []*AgentSession, isRegex bool) []string {
 	var results []string
 
+	if isRegex {
+		re, err := compileNudgeRegex(pattern)
+		if err != nil {
+			return nil
+		}
+		for _, agent := range agents {
+			if re.MatchString(agent.Name) {
+				results = append(results, agent.Name)
+			}
+		}
+		return results
+	}
+
 	// Handle special cases
 	switch pattern {
 	case "mayor":
@@ -435,6 +527,22 @@ func resolveNudgePattern(pattern string, agents []*AgentSession) []string {
 	return results
 }
 
+// compileNudgeRegex compiles a --regex nudge pattern, anchored so it must
+// match a whole session name. Named capture groups are rejected: patterns
+// select sessions and have no use for them.
+func compileNudgeRegex(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nudge regex %q: %w", pattern, err)
+	}
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			return nil, fmt.Errorf("invalid nudge regex %q: named capture group %q not allowed", pattern, name)
+		}
+	}
+	return regexp.MustCompile(`^(?:` + pattern + `)$`), nil
+}
+
 // shouldNudgeTarget checks if a nudge should be sent based on the target's notification level.
 // Returns (shouldSend bool, level string, err error).
 // If force is true, always returns true.
@@ -1,23 +1,27 @@
 package cmd
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// nudgeTestAgents are test agent sessions (mayor/deacon use hq- prefix).
+var nudgeTestAgents = []*AgentSession{
+	{Name: "hq-mayor", Type: AgentMayor},
+	{Name: "hq-deacon", Type: AgentDeacon},
+	{Name: "gt-gongshow-witness", Type: AgentWitness, Rig: "gongshow"},
+	{Name: "gt-gongshow-refinery", Type: AgentRefinery, Rig: "gongshow"},
+	{Name: "gt-gongshow-crew-max", Type: AgentCrew, Rig: "gongshow", AgentName: "max"},
+	{Name: "gt-gongshow-crew-jack", Type: AgentCrew, Rig: "gongshow", AgentName: "jack"},
+	{Name: "gt-gongshow-alpha", Type: AgentPolecat, Rig: "gongshow", AgentName: "alpha"},
+	{Name: "gt-gongshow-beta", Type: AgentPolecat, Rig: "gongshow", AgentName: "beta"},
+	{Name: "gt-beads-witness", Type: AgentWitness, Rig: "beads"},
+	{Name: "gt-beads-gamma", Type: AgentPolecat, Rig: "beads", AgentName: "gamma"},
+}
+
 func TestResolveNudgePattern(t *testing.T) {
-	// Create test agent sessions (mayor/deacon use hq- prefix)
-	agents := []*AgentSession{
-		{Name: "hq-mayor", Type: AgentMayor},
-		{Name: "hq-deacon", Type: AgentDeacon},
-		{Name: "gt-gongshow-witness", Type: AgentWitness, Rig: "gongshow"},
-		{Name: "gt-gongshow-refinery", Type: AgentRefinery, Rig: "gongshow"},
-		{Name: "gt-gongshow-crew-max", Type: AgentCrew, Rig: "gongshow", AgentName: "max"},
-		{Name: "gt-gongshow-crew-jack", Type: AgentCrew, Rig: "gongshow", AgentName: "jack"},
-		{Name: "gt-gongshow-alpha", Type: AgentPolecat, Rig: "gongshow", AgentName: "alpha"},
-		{Name: "gt-gongshow-beta", Type: AgentPolecat, Rig: "gongshow", AgentName: "beta"},
-		{Name: "gt-beads-witness", Type: AgentWitness, Rig: "beads"},
-		{Name: "gt-beads-gamma", Type: AgentPolecat, Rig: "beads", AgentName: "gamma"},
-	}
+	agents := nudgeTestAgents
 
 	tests := []struct {
 		name     string
@@ -88,7 +92,7 @@ func TestResolveNudgePattern(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := resolveNudgePattern(tt.pattern, agents)
+			got := resolveNudgePattern(tt.pattern, agents, false)
 
 			if len(got) != len(tt.expected) {
 				t.Errorf("resolveNudgePattern(%q) returned %d results, want %d: got %v, want %v",
@@ -110,3 +114,78 @@ func TestResolveNudgePattern(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveNudgePatternRegex(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		expected []string
+	}{
+		{
+			name:     "matches several agents",
+			pattern:  `gt-gongshow-crew-.*`,
+			expected: []string{"gt-gongshow-crew-max", "gt-gongshow-crew-jack"},
+		},
+		{
+			name:     "matches across rigs",
+			pattern:  `gt-(gongshow|beads)-witness`,
+			expected: []string{"gt-gongshow-witness", "gt-beads-witness"},
+		},
+		{
+			name:     "matches whole names only",
+			pattern:  `gongshow`,
+			expected: nil,
+		},
+		{
+			name:     "matches none",
+			pattern:  `gt-nonexistent-.*`,
+			expected: nil,
+		},
+		{
+			name:     "fails to compile",
+			pattern:  `gt-(gongshow`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveNudgePattern(tt.pattern, nudgeTestAgents, true)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("resolveNudgePattern(%q, regex) = %v, want %v", tt.pattern, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterNudgeDND(t *testing.T) {
+	targets := []string{"gt-gongshow-crew-max", "gt-gongshow-crew-jack", "gt-gongshow-alpha", "gt-unknown"}
+	var checked []string
+	send, skipped := filterNudgeDND(targets, nudgeTestAgents, func(address string) (bool, string) {
+		checked = append(checked, address)
+		return address != "gongshow/crew/jack", "muted"
+	})
+
+	if want := []string{"gongshow/crew/max", "gongshow/crew/jack", "gongshow/alpha"}; !reflect.DeepEqual(checked, want) {
+		t.Errorf("checked addresses %v, want %v", checked, want)
+	}
+	if want := []string{"gt-gongshow-crew-max", "gt-gongshow-alpha", "gt-unknown"}; !reflect.DeepEqual(send, want) {
+		t.Errorf("send = %v, want %v", send, want)
+	}
+	if want := []string{"gt-gongshow-crew-jack (muted)"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %v, want %v", skipped, want)
+	}
+}
+
+func TestCompileNudgeRegex(t *testing.T) {
+	if _, err := compileNudgeRegex(`gt-.*-witness`); err != nil {
+		t.Errorf("compileNudgeRegex() of a valid pattern error = %v", err)
+	}
+	if _, err := compileNudgeRegex(`gt-(gongshow`); err == nil {
+		t.Error("compileNudgeRegex() of an unbalanced pattern succeeded, want an error")
+	}
+	_, err := compileNudgeRegex(`gt-(?P<rig>\w+)-witness`)
+	if err == nil || !strings.Contains(err.Error(), "named capture group") {
+		t.Errorf("compileNudgeRegex() with a named group error = %v, want it rejected", err)
+	}
+}
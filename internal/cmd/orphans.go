@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/events"
 	"github.com/KeithWyatt/gongshow/internal/style"
 	"github.com/KeithWyatt/gongshow/internal/workspace"
 )
@@ -598,6 +599,8 @@ func runOrphansKillProcesses(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		_ = events.LogAudit(events.TypeKill, detectSender(),
+			events.KillPayload("", fmt.Sprintf("PID %d", o.PID), "orphaned process"))
 		fmt.Printf("  %s PID %d killed\n", style.Bold.Render("✓"), o.PID)
 		killed++
 	}
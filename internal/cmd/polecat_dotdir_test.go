@@ -36,7 +36,7 @@ func TestDiscoverHooksSkipsPolecatDotDirs(t *testing.T) {
 	}
 }
 
-func TestStartPolecatsWithWorkSkipsDotDirs(t *testing.T) {
+func TestPolecatsWithWorkSkipsDotDirs(t *testing.T) {
 	townRoot := setupTestTownForDotDir(t)
 	rigName := "gongshow"
 	rigPath := filepath.Join(townRoot, rigName)
@@ -92,13 +92,8 @@ exit 0
 		t.Fatalf("chdir town root: %v", err)
 	}
 
-	started, errs := startPolecatsWithWork(townRoot, rigName)
-
-	if len(started) != 0 {
-		t.Fatalf("expected no polecats started, got %v", started)
-	}
-	if len(errs) != 0 {
-		t.Fatalf("expected no errors, got %v", errs)
+	if withWork := polecatsWithWork(townRoot, rigName); len(withWork) != 0 {
+		t.Fatalf("expected no polecats with work, got %v", withWork)
 	}
 }
 
@@ -228,9 +228,7 @@ const maxEventsToScan = 10000
 // checkColdRig returns true if the rig is "cold" (no activity within threshold).
 // Also returns the timestamp of the last activity.
 func checkColdRig(townRoot, rigName string, threshold time.Duration) (bool, time.Time) {
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-
-	file, err := os.Open(eventsPath)
+	file, err := events.OpenLog(townRoot)
 	if err != nil {
 		// No events file means new rig - definitely cold
 		return true, time.Time{}
@@ -368,9 +366,7 @@ const maxSessionsToTrack = 100
 // Sessions younger than minAge are skipped (handoff mail suffices for recent sessions).
 // Returns nil if no predecessor found.
 func findPredecessorSession(townRoot, rigName, currentSessionID string, minAge time.Duration) *seanceEvent {
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-
-	file, err := os.Open(eventsPath)
+	file, err := events.OpenLog(townRoot)
 	if err != nil {
 		return nil
 	}
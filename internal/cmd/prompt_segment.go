@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/shell"
+	"github.com/KeithWyatt/gongshow/internal/state"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// promptSegmentDefaultFormat renders as [gongshow:crew/max ⚓go-abc ✉2].
+const promptSegmentDefaultFormat = "[{rig:}{role}{/name}{ ⚓hook}{ ✉mail}]"
+
+// promptCacheTTL is how long cached hook and mail values are shown before a
+// background refresh is started.
+const promptCacheTTL = 30 * time.Second
+
+var (
+	promptSegmentFormat  string
+	promptSegmentNoColor bool
+	promptSegmentRefresh bool
+)
+
+var promptSegmentCmd = &cobra.Command{
+	Use:     "prompt-segment",
+	GroupID: GroupConfig,
+	Short:   "Print a shell prompt segment with the current rig, role and hook",
+	Long: `Print a one-line prompt segment for the current directory, for use in
+PS1, a zsh precmd or fish_prompt. Outside a town it prints nothing.
+
+The town and rig come from GT_TOWN_ROOT and GT_RIG, as set by the shell
+integration ('gt install --shell'); the role and name from the directory
+(crew workspace, polecat worktree, ...). The hooked bead and unread mail
+count are read from a cache in ~/.cache/gongshow/prompt, refreshed in the
+background once older than 30s, so the prompt never waits on bd.
+
+Format placeholders: {rig} {role} {name} {hook} {mail}. Text inside the
+braces around a placeholder is only printed when it has a value, so
+'{ ⚓hook}' disappears when nothing is hooked. {mail} is the unread count,
+empty when there is none.
+
+Examples:
+  PS1='$(gt prompt-segment) '"$PS1"                    # bash
+  setopt PROMPT_SUBST; PROMPT='$(gt prompt-segment) '$PROMPT   # zsh
+  gt prompt-segment --format '{rig}{ @hook}' --no-color`,
+	Args: cobra.NoArgs,
+	RunE: runPromptSegment,
+}
+
+func init() {
+	promptSegmentCmd.Flags().StringVar(&promptSegmentFormat, "format", promptSegmentDefaultFormat, "Segment format (placeholders: {rig} {role} {name} {hook} {mail})")
+	promptSegmentCmd.Flags().BoolVar(&promptSegmentNoColor, "no-color", false, "Print without color codes")
+	promptSegmentCmd.Flags().BoolVar(&promptSegmentRefresh, "refresh", false, "Refresh the cached hook and mail count, printing nothing (internal use)")
+	_ = promptSegmentCmd.Flags().MarkHidden("refresh")
+
+	rootCmd.AddCommand(promptSegmentCmd)
+}
+
+func runPromptSegment(cmd *cobra.Command, args []string) error {
+	if promptSegmentRefresh {
+		return refreshPromptSegment()
+	}
+	colorShell := shell.DetectShell()
+	if promptSegmentNoColor || os.Getenv("NO_COLOR") != "" {
+		colorShell = ""
+	}
+	writePromptSegment(os.Stdout, promptSegmentFormat, colorShell)
+	return nil
+}
+
+// promptContext is what the prompt segment shows about the current directory.
+type promptContext struct {
+	TownRoot string
+	WorkDir  string
+	Rig      string
+	Role     Role
+	Name     string
+	Identity string // agent identity for hook and mail lookups; empty if none
+}
+
+// detectPromptContext resolves the prompt context from GT_TOWN_ROOT, GT_RIG
+// and the cwd. It only reads the environment and the cwd path, never the
+// filesystem, so it returns false instantly outside a town.
+func detectPromptContext() (promptContext, bool) {
+	townRoot := os.Getenv("GT_TOWN_ROOT")
+	if townRoot == "" {
+		return promptContext{}, false
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return promptContext{}, false
+	}
+	if rel, err := filepath.Rel(townRoot, cwd); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return promptContext{}, false // stale GT_TOWN_ROOT
+	}
+
+	info := detectRole(cwd, townRoot)
+	pc := promptContext{TownRoot: townRoot, WorkDir: cwd, Role: info.Role, Name: info.Polecat}
+	switch {
+	case os.Getenv("GT_RIG") != "":
+		pc.Rig = os.Getenv("GT_RIG")
+	case info.Role != RoleUnknown:
+		pc.Rig = info.Rig
+	}
+	if info.Role == RoleUnknown {
+		pc.Role = ""
+	}
+	pc.Identity = getAgentIdentity(info)
+	return pc, true
+}
+
+// promptCacheEntry is the cached hook and mail state for one agent.
+type promptCacheEntry struct {
+	Hook         string    `json:"hook,omitempty"`
+	Unread       int       `json:"unread"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	RefreshingAt time.Time `json:"refreshing_at,omitempty"`
+}
+
+// promptCachePath returns the cache file for an agent in a town.
+func promptCachePath(townRoot, identity string) string {
+	sum := sha256.Sum256([]byte(townRoot + "\x00" + identity))
+	return filepath.Join(state.CacheDir(), "prompt", hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadPromptCache reads a cache entry; a missing or unreadable one is empty.
+func loadPromptCache(path string) promptCacheEntry {
+	var entry promptCacheEntry
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &entry)
+	}
+	return entry
+}
+
+func savePromptCache(path string, entry promptCacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(path, entry)
+}
+
+// startPromptRefresh runs `gt prompt-segment --refresh` in the background,
+// in the current directory. It's a variable so tests can stub it.
+var startPromptRefresh = func() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	c := exec.Command(exe, "prompt-segment", "--refresh")
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Process.Release()
+}
+
+// writePromptSegment writes the segment for the cwd to w, or nothing
+// outside a town. Cached values older than promptCacheTTL are still shown,
+// while a background refresh (at most one per TTL) updates them.
+func writePromptSegment(w io.Writer, format, colorShell string) {
+	pc, ok := detectPromptContext()
+	if !ok {
+		return
+	}
+
+	fields := map[string]string{
+		"rig":  pc.Rig,
+		"role": string(pc.Role),
+		"name": pc.Name,
+	}
+	if pc.Identity != "" {
+		path := promptCachePath(pc.TownRoot, pc.Identity)
+		entry := loadPromptCache(path)
+		now := time.Now()
+		if now.Sub(entry.UpdatedAt) > promptCacheTTL && now.Sub(entry.RefreshingAt) > promptCacheTTL {
+			entry.RefreshingAt = now
+			if err := savePromptCache(path, entry); err == nil {
+				_ = startPromptRefresh()
+			}
+		}
+		fields["hook"] = entry.Hook
+		if entry.Unread > 0 {
+			fields["mail"] = strconv.Itoa(entry.Unread)
+		}
+	}
+
+	fmt.Fprint(w, formatPromptSegment(format, fields, colorShell))
+}
+
+// refreshPromptSegment looks up the hooked bead and unread mail count for
+// the cwd's agent and caches them.
+func refreshPromptSegment() error {
+	pc, ok := detectPromptContext()
+	if !ok || pc.Identity == "" {
+		return nil
+	}
+
+	entry := promptCacheEntry{UpdatedAt: time.Now()}
+	hooked, err := beads.New(pc.WorkDir).List(beads.ListOptions{
+		Status:   beads.StatusHooked,
+		Assignee: pc.Identity,
+		Priority: -1,
+	})
+	if err == nil && len(hooked) > 0 {
+		entry.Hook = hooked[0].ID
+	}
+	if _, unread, err := mail.NewMailboxFromAddress(pc.Identity, pc.TownRoot).Count(); err == nil {
+		entry.Unread = unread
+	}
+	return savePromptCache(promptCachePath(pc.TownRoot, pc.Identity), entry)
+}
+
+// promptPlaceholder matches {prefix key suffix}, e.g. "{ ⚓hook}".
+var promptPlaceholder = regexp.MustCompile(`\{([^{}]*?)(rig|role|name|hook|mail)([^{}]*?)\}`)
+
+// promptColors are the ANSI SGR codes for each placeholder's value.
+var promptColors = map[string]string{
+	"rig":  "36", // cyan
+	"role": "34", // blue
+	"name": "1",  // bold
+	"hook": "33", // yellow
+	"mail": "35", // magenta
+}
+
+// formatPromptSegment expands the placeholders in format. A placeholder
+// without a value is dropped along with the text inside its braces. Values
+// are colored for colorShell, with the escape codes marked as zero-width in
+// the way that shell's prompt expects; an empty colorShell means no color.
+func formatPromptSegment(format string, fields map[string]string, colorShell string) string {
+	return promptPlaceholder.ReplaceAllStringFunc(format, func(m string) string {
+		parts := promptPlaceholder.FindStringSubmatch(m)
+		prefix, key, suffix := parts[1], parts[2], parts[3]
+		value := fields[key]
+		if value == "" {
+			return ""
+		}
+		return prefix + promptColor(colorShell, promptColors[key], value) + suffix
+	})
+}
+
+// promptColor wraps s in an SGR color. bash and zsh have to be told the
+// escape codes take no space, or line editing miscounts the prompt width.
+func promptColor(colorShell, code, s string) string {
+	start, reset := "\x1b["+code+"m", "\x1b[0m"
+	switch colorShell {
+	case "":
+		return s
+	case "bash":
+		return "\x01" + start + "\x02" + s + "\x01" + reset + "\x02"
+	case "zsh":
+		return "%{" + start + "%}" + s + "%{" + reset + "%}"
+	default:
+		return start + s + reset
+	}
+}
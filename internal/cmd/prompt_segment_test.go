@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFormatPromptSegment(t *testing.T) {
+	crew := map[string]string{"rig": "gongshow", "role": "crew", "name": "max", "hook": "go-abc", "mail": "2"}
+	tests := []struct {
+		name   string
+		format string
+		fields map[string]string
+		shell  string
+		want   string
+	}{
+		{"default", promptSegmentDefaultFormat, crew, "", "[gongshow:crew/max ⚓go-abc ✉2]"},
+		{"nothing hooked", promptSegmentDefaultFormat, map[string]string{"rig": "gongshow", "role": "crew", "name": "max"}, "", "[gongshow:crew/max]"},
+		{"witness", promptSegmentDefaultFormat, map[string]string{"rig": "gongshow", "role": "witness"}, "", "[gongshow:witness]"},
+		{"mayor at town root", promptSegmentDefaultFormat, map[string]string{"role": "mayor"}, "", "[mayor]"},
+		{"custom", "{name}@{rig} {hook} ({mail} unread)", crew, "", "max@gongshow go-abc (2 unread)"},
+		{"unknown key kept", "{rig} {branch}", crew, "", "gongshow {branch}"},
+		{"bash color", "{rig}", crew, "bash", "\x01\x1b[36m\x02gongshow\x01\x1b[0m\x02"},
+		{"zsh color", "{ ⚓hook}", crew, "zsh", " ⚓%{\x1b[33m%}go-abc%{\x1b[0m%}"},
+		{"fish color", "{mail}", crew, "fish", "\x1b[35m2\x1b[0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatPromptSegment(tt.format, tt.fields, tt.shell); got != tt.want {
+				t.Errorf("formatPromptSegment(%q) = %q, want %q", tt.format, got, tt.want)
+			}
+		})
+	}
+}
+
+// setupPromptTown creates a town with a crew workspace, cds into it and
+// points the cache at a temp dir. It stubs the background refresh, counting
+// calls.
+func setupPromptTown(t *testing.T) (townRoot string, refreshes *int) {
+	t.Helper()
+	townRoot = t.TempDir()
+	crewDir := filepath.Join(townRoot, "gongshow", "crew", "max")
+	if err := os.MkdirAll(crewDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(crewDir)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("GT_TOWN_ROOT", townRoot)
+	t.Setenv("GT_RIG", "gongshow")
+
+	refreshes = new(int)
+	orig := startPromptRefresh
+	startPromptRefresh = func() error { *refreshes++; return nil }
+	t.Cleanup(func() { startPromptRefresh = orig })
+	return townRoot, refreshes
+}
+
+func TestPromptSegmentCacheHit(t *testing.T) {
+	townRoot, refreshes := setupPromptTown(t)
+	path := promptCachePath(townRoot, "gongshow/crew/max")
+	if err := savePromptCache(path, promptCacheEntry{Hook: "go-abc", Unread: 2, UpdatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	writePromptSegment(&buf, promptSegmentDefaultFormat, "")
+	if got, want := buf.String(), "[gongshow:crew/max ⚓go-abc ✉2]"; got != want {
+		t.Errorf("segment = %q, want %q", got, want)
+	}
+	if *refreshes != 0 {
+		t.Errorf("fresh cache started %d refresh(es), want 0", *refreshes)
+	}
+}
+
+func TestPromptSegmentStaleCacheRefreshesOnce(t *testing.T) {
+	townRoot, refreshes := setupPromptTown(t)
+	path := promptCachePath(townRoot, "gongshow/crew/max")
+	if err := savePromptCache(path, promptCacheEntry{Hook: "go-old", UpdatedAt: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		var buf bytes.Buffer
+		writePromptSegment(&buf, "{hook}", "")
+		if got := buf.String(); got != "go-old" {
+			t.Errorf("segment = %q, want the stale value while refreshing", got)
+		}
+	}
+	if *refreshes != 1 {
+		t.Errorf("started %d refreshes, want 1", *refreshes)
+	}
+}
+
+func TestPromptSegmentOutsideTown(t *testing.T) {
+	townRoot, refreshes := setupPromptTown(t)
+
+	for name, setup := range map[string]func(){
+		"no GT_TOWN_ROOT":    func() { t.Setenv("GT_TOWN_ROOT", "") },
+		"stale GT_TOWN_ROOT": func() { t.Setenv("GT_TOWN_ROOT", filepath.Join(townRoot, "elsewhere")) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			setup()
+			var buf bytes.Buffer
+			writePromptSegment(&buf, promptSegmentDefaultFormat, "bash")
+			if buf.Len() != 0 {
+				t.Errorf("segment = %q, want empty output", buf.String())
+			}
+		})
+	}
+	if *refreshes != 0 {
+		t.Errorf("started %d refresh(es) outside a town", *refreshes)
+	}
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/proc"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+	"github.com/KeithWyatt/gongshow/internal/witness"
+)
+
+// ps flags
+var (
+	psRig       string
+	psResources bool
+	psInterval  time.Duration
+	psJSON      bool
+)
+
+var psCmd = &cobra.Command{
+	Use:     "ps",
+	GroupID: GroupAgents,
+	Short:   "List agent sessions and their processes",
+	Long: `List running agent sessions with the PID of each pane, how many
+processes run under it, and its foreground command.
+
+With --resources, each session's process tree is sampled twice, --interval
+apart, and the CPU it used in between, its memory (RSS) and threads are
+shown, with what the samples say the agent is doing:
+
+  working   - its output changed
+  idle      - no new output, and its processes are nearly idle: most
+              likely waiting for input
+  stalled   - the same, over an --interval of a minute or more
+  spinning  - no new output, but a core is busy: most likely a loop
+  quiet     - no new output; CPU can't be measured on this platform
+
+GT_ROLE, GT_RIG and BD_ACTOR are the agent the session's runtime process
+was started as, read from its environment. They are "-" when unset, and
+for processes whose environment can't be read, such as another user's.
+
+Resource stats are read from /proc and are only available on Linux.
+
+Examples:
+  gt ps                          # All agent sessions
+  gt ps --rig gongshow           # One rig's sessions
+  gt ps --resources              # With CPU, memory and activity
+  gt ps --resources --interval 10s --json`,
+	Args: cobra.NoArgs,
+	RunE: runPs,
+}
+
+func init() {
+	psCmd.Flags().StringVar(&psRig, "rig", "", "Show only this rig's sessions")
+	psCmd.Flags().BoolVar(&psResources, "resources", false, "Sample CPU, memory and activity of each session")
+	psCmd.Flags().DurationVar(&psInterval, "interval", 2*time.Second, "Time between the samples taken with --resources")
+	psCmd.Flags().BoolVar(&psJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(psCmd)
+}
+
+// PsEntry is an agent session as gt ps shows it.
+type PsEntry struct {
+	Agent     string `json:"agent"`
+	Session   string `json:"session"`
+	PID       int    `json:"pid,omitempty"`
+	Processes int    `json:"processes,omitempty"` // The pane's process and its descendants
+	Command   string `json:"command,omitempty"`
+
+	// Identity is the GT_ROLE, GT_RIG and BD_ACTOR the session's runtime
+	// process was started with; nil if its environment can't be read.
+	Identity *proc.AgentIdentity `json:"identity,omitempty"`
+
+	Resources *PsResources `json:"resources,omitempty"` // With --resources
+}
+
+// PsResources is what two samples of a session's process tree showed.
+type PsResources struct {
+	CPUPercent float64          `json:"cpu_percent"` // Of one core, between the samples
+	RSS        uint64           `json:"rss"`         // Bytes, at the second sample
+	Threads    int              `json:"threads"`
+	Activity   witness.Activity `json:"activity"`
+	Supported  bool             `json:"supported"` // False where CPU and memory can't be read
+}
+
+func runPs(cmd *cobra.Command, args []string) error {
+	if psResources && psInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return fmt.Errorf("listing sessions: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	var entries []PsEntry
+	var sessions []string
+	for _, agent := range agents {
+		if psRig != "" && agent.Rig != psRig {
+			continue
+		}
+		entry := PsEntry{Agent: agent.address(), Session: agent.Name}
+		if pidStr, err := t.GetPanePID(agent.Name); err == nil {
+			if pid, err := strconv.Atoi(pidStr); err == nil {
+				descendants := proc.GetAllDescendants(pid)
+				entry.PID = pid
+				entry.Processes = 1 + len(descendants)
+				entry.Identity = runtimeIdentity(append(descendants, pid))
+			}
+		}
+		entry.Command, _ = t.GetPaneCommand(agent.Name)
+		entries = append(entries, entry)
+		sessions = append(sessions, agent.Name)
+	}
+
+	if psResources && len(entries) > 0 {
+		for i, res := range samplePsResources(t, sessions, psInterval) {
+			entries[i].Resources = res
+		}
+	}
+
+	if psJSON {
+		if entries == nil {
+			entries = []PsEntry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No agent sessions running.")
+		return nil
+	}
+	printPsEntries(os.Stdout, entries, psResources)
+	return nil
+}
+
+// runtimeIdentity returns the agent identity in the environment of the
+// first of pids to have one. Given a pane's descendants before the pane's
+// own process, that is the runtime's when a shell started it. Returns nil
+// if none has one, or none can be read.
+func runtimeIdentity(pids []int) *proc.AgentIdentity {
+	for _, pid := range pids {
+		if id, err := proc.Identity(pid); err == nil && !id.IsZero() {
+			return &id
+		}
+	}
+	return nil
+}
+
+// samplePsResources samples every session, waits interval and samples
+// them again. A session that can't be sampled both times gets nil.
+func samplePsResources(t *tmux.Tmux, sessions []string, interval time.Duration) []*PsResources {
+	first := make([]*witness.ActivitySample, len(sessions))
+	for i, session := range sessions {
+		first[i], _ = witness.SampleActivity(t, session)
+	}
+	time.Sleep(interval)
+
+	resources := make([]*PsResources, len(sessions))
+	for i, session := range sessions {
+		if first[i] == nil {
+			continue
+		}
+		second, err := witness.SampleActivity(t, session)
+		if err != nil {
+			continue
+		}
+		resources[i] = &PsResources{
+			CPUPercent: proc.StatsDelta(first[i].Stats, second.Stats),
+			RSS:        second.Stats.RSS,
+			Threads:    second.Stats.Threads,
+			Activity:   witness.DiagnoseActivity(first[i], second),
+			Supported:  second.Stats.Supported,
+		}
+	}
+	return resources
+}
+
+// printPsEntries prints the sessions as a table, with the resource columns
+// if resources is set.
+func printPsEntries(w io.Writer, entries []PsEntry, resources bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if resources {
+		_, _ = fmt.Fprintln(tw, "AGENT\tSESSION\tPID\tPROCS\tGT_ROLE\tGT_RIG\tBD_ACTOR\tCPU\tRSS\tTHREADS\tACTIVITY\tCOMMAND")
+	} else {
+		_, _ = fmt.Fprintln(tw, "AGENT\tSESSION\tPID\tPROCS\tGT_ROLE\tGT_RIG\tBD_ACTOR\tCOMMAND")
+	}
+
+	unsupported := false
+	for _, e := range entries {
+		pid, procs := "-", "-"
+		if e.PID > 0 {
+			pid, procs = strconv.Itoa(e.PID), strconv.Itoa(e.Processes)
+		}
+		command := e.Command
+		if command == "" {
+			command = "-"
+		}
+		role, rig, actor := "-", "-", "-"
+		if id := e.Identity; id != nil {
+			role, rig, actor = orDash(id.Role), orDash(id.Rig), orDash(id.Actor)
+		}
+		if !resources {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Agent, e.Session, pid, procs, role, rig, actor, command)
+			continue
+		}
+
+		cpu, rss, threads, activity := "-", "-", "-", "-"
+		if r := e.Resources; r != nil {
+			activity = string(r.Activity)
+			if r.Supported {
+				cpu = fmt.Sprintf("%.0f%%", r.CPUPercent)
+				rss = formatPsMemory(r.RSS)
+				threads = strconv.Itoa(r.Threads)
+			} else {
+				unsupported = true
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Agent, e.Session, pid, procs, role, rig, actor, cpu, rss, threads, activity, command)
+	}
+	_ = tw.Flush()
+
+	if unsupported {
+		_, _ = fmt.Fprintf(w, "\n%s\n", style.Dim.Render("CPU and memory can't be read on this platform."))
+	}
+}
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// formatPsMemory formats a byte count the way ps users expect: 812M, 6.1G.
+func formatPsMemory(bytes uint64) string {
+	const mib = 1 << 20
+	switch {
+	case bytes >= 10<<30:
+		return fmt.Sprintf("%.0fG", float64(bytes)/(1<<30))
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1fG", float64(bytes)/(1<<30))
+	case bytes >= mib:
+		return fmt.Sprintf("%dM", bytes/mib)
+	default:
+		return fmt.Sprintf("%dK", bytes/1024)
+	}
+}
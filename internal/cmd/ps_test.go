@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/proc"
+	"github.com/KeithWyatt/gongshow/internal/witness"
+)
+
+func TestAgentSessionAddress(t *testing.T) {
+	for session, want := range map[string]string{
+		"hq-mayor":             "mayor",
+		"hq-deacon":            "deacon",
+		"gt-gongshow-witness":  "gongshow/witness",
+		"gt-witness-gongshow":  "gongshow/witness",
+		"gt-gongshow-refinery": "gongshow/refinery",
+		"gt-gongshow-crew-max": "gongshow/crew/max",
+		"gt-gongshow-Toast":    "gongshow/Toast",
+	} {
+		if got := categorizeSession(session).address(); got != want {
+			t.Errorf("address of %s = %q, want %q", session, got, want)
+		}
+	}
+}
+
+func TestPrintPsEntries(t *testing.T) {
+	entries := []PsEntry{
+		{Agent: "mayor", Session: "hq-mayor", PID: 100, Processes: 3, Command: "claude",
+			Identity:  &proc.AgentIdentity{Role: "mayor", Actor: "mayor"},
+			Resources: &PsResources{CPUPercent: 4.2, RSS: 812 << 20, Threads: 23, Activity: witness.ActivityWorking, Supported: true}},
+		{Agent: "gongshow/Toast", Session: "gt-gongshow-Toast", PID: 200, Processes: 5, Command: "node",
+			Identity:  &proc.AgentIdentity{Role: "polecat", Rig: "gongshow", Actor: "gongshow/polecats/Toast"},
+			Resources: &PsResources{CPUPercent: 99.6, RSS: 6 << 30, Threads: 40, Activity: witness.ActivitySpinning, Supported: true}},
+		{Agent: "gongshow/witness", Session: "gt-gongshow-witness"},
+	}
+
+	var out bytes.Buffer
+	printPsEntries(&out, entries, false)
+	want := "" +
+		"AGENT             SESSION              PID  PROCS  GT_ROLE  GT_RIG    BD_ACTOR                 COMMAND\n" +
+		"mayor             hq-mayor             100  3      mayor    -         mayor                    claude\n" +
+		"gongshow/Toast    gt-gongshow-Toast    200  5      polecat  gongshow  gongshow/polecats/Toast  node\n" +
+		"gongshow/witness  gt-gongshow-witness  -    -      -        -         -                        -\n"
+	if out.String() != want {
+		t.Errorf("printPsEntries() =\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printPsEntries(&out, entries, true)
+	want = "" +
+		"AGENT             SESSION              PID  PROCS  GT_ROLE  GT_RIG    BD_ACTOR                 CPU   RSS   THREADS  ACTIVITY  COMMAND\n" +
+		"mayor             hq-mayor             100  3      mayor    -         mayor                    4%    812M  23       working   claude\n" +
+		"gongshow/Toast    gt-gongshow-Toast    200  5      polecat  gongshow  gongshow/polecats/Toast  100%  6.0G  40       spinning  node\n" +
+		"gongshow/witness  gt-gongshow-witness  -    -      -        -         -                        -     -     -        -         -\n"
+	if out.String() != want {
+		t.Errorf("printPsEntries(resources) =\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	printPsEntries(&out, []PsEntry{{Agent: "mayor", Session: "hq-mayor", PID: 100, Processes: 1,
+		Resources: &PsResources{Activity: witness.ActivityQuiet}}}, true)
+	if !strings.Contains(out.String(), "quiet") || !strings.Contains(out.String(), "can't be read on this platform") {
+		t.Errorf("printPsEntries(unsupported) =\n%s\nwant the activity and a note", out.String())
+	}
+}
+
+func TestFormatPsMemory(t *testing.T) {
+	for bytes, want := range map[uint64]string{
+		0:              "0K",
+		512 << 10:      "512K",
+		812 << 20:      "812M",
+		3 << 29:        "1.5G",
+		6 << 30:        "6.0G",
+		12<<30 + 1<<29: "12G",
+	} {
+		if got := formatPsMemory(bytes); got != want {
+			t.Errorf("formatPsMemory(%d) = %q, want %q", bytes, got, want)
+		}
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/KeithWyatt/gongshow/internal/beads"
 	"github.com/KeithWyatt/gongshow/internal/config"
-	"github.com/KeithWyatt/gongshow/internal/crew"
 	"github.com/KeithWyatt/gongshow/internal/deps"
 	"github.com/KeithWyatt/gongshow/internal/git"
 	"github.com/KeithWyatt/gongshow/internal/polecat"
@@ -176,28 +175,6 @@ Examples:
 	RunE: runRigShutdown,
 }
 
-var rigStatusCmd = &cobra.Command{
-	Use:   "status [rig]",
-	Short: "Show detailed status for a specific rig",
-	Long: `Show detailed status for a specific rig including all workers.
-
-If no rig is specified, infers the rig from the current directory.
-
-Displays:
-- Rig information (name, path, beads prefix)
-- Witness status (running/stopped, uptime)
-- Refinery status (running/stopped, uptime, queue size)
-- Polecats (name, state, assigned issue, session status)
-- Crew members (name, branch, session status, git status)
-
-Examples:
-  gt rig status           # Infer rig from current directory
-  gt rig status gongshow
-  gt rig status beads`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runRigStatus,
-}
-
 var rigStopCmd = &cobra.Command{
 	Use:   "stop <rig>...",
 	Short: "Stop one or more rigs (shutdown semantics)",
@@ -280,7 +257,6 @@ func init() {
 	rigCmd.AddCommand(rigRestartCmd)
 	rigCmd.AddCommand(rigShutdownCmd)
 	rigCmd.AddCommand(rigStartCmd)
-	rigCmd.AddCommand(rigStatusCmd)
 	rigCmd.AddCommand(rigStopCmd)
 
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
@@ -1020,151 +996,6 @@ func runRigReboot(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func runRigStatus(cmd *cobra.Command, args []string) error {
-	var rigName string
-
-	if len(args) > 0 {
-		rigName = args[0]
-	} else {
-		// Infer rig from current directory
-		roleInfo, err := GetRole()
-		if err != nil {
-			return fmt.Errorf("detecting rig from current directory: %w", err)
-		}
-		if roleInfo.Rig == "" {
-			return fmt.Errorf("could not detect rig from current directory; please specify rig name")
-		}
-		rigName = roleInfo.Rig
-	}
-
-	// Get rig
-	townRoot, r, err := getRig(rigName)
-	if err != nil {
-		return err
-	}
-
-	t := tmux.NewTmux()
-
-	// Header
-	fmt.Printf("%s\n", style.Bold.Render(rigName))
-
-	// Operational state
-	opState, opSource := getRigOperationalState(townRoot, rigName)
-	if opState == "OPERATIONAL" {
-		fmt.Printf("  Status: %s\n", style.Success.Render(opState))
-	} else if opState == "PARKED" {
-		fmt.Printf("  Status: %s (%s)\n", style.Warning.Render(opState), opSource)
-	} else if opState == "DOCKED" {
-		fmt.Printf("  Status: %s (%s)\n", style.Dim.Render(opState), opSource)
-	}
-
-	fmt.Printf("  Path: %s\n", r.Path)
-	if r.Config != nil && r.Config.Prefix != "" {
-		fmt.Printf("  Beads prefix: %s-\n", r.Config.Prefix)
-	}
-	fmt.Println()
-
-	// Witness status
-	fmt.Printf("%s\n", style.Bold.Render("Witness"))
-	witnessSession := fmt.Sprintf("gt-%s-witness", rigName)
-	witnessRunning, _ := t.HasSession(witnessSession)
-	witMgr := witness.NewManager(r)
-	witStatus, _ := witMgr.Status()
-	if witnessRunning {
-		fmt.Printf("  %s running", style.Success.Render("●"))
-		if witStatus != nil && witStatus.StartedAt != nil {
-			fmt.Printf(" (uptime: %s)", formatDuration(time.Since(*witStatus.StartedAt)))
-		}
-		fmt.Printf("\n")
-	} else {
-		fmt.Printf("  %s stopped\n", style.Dim.Render("○"))
-	}
-	fmt.Println()
-
-	// Refinery status
-	fmt.Printf("%s\n", style.Bold.Render("Refinery"))
-	refinerySession := fmt.Sprintf("gt-%s-refinery", rigName)
-	refineryRunning, _ := t.HasSession(refinerySession)
-	refMgr := refinery.NewManager(r)
-	refStatus, _ := refMgr.Status()
-	if refineryRunning {
-		fmt.Printf("  %s running", style.Success.Render("●"))
-		if refStatus != nil && refStatus.StartedAt != nil {
-			fmt.Printf(" (uptime: %s)", formatDuration(time.Since(*refStatus.StartedAt)))
-		}
-		fmt.Printf("\n")
-		// Show queue size
-		queue, err := refMgr.Queue()
-		if err == nil && len(queue) > 0 {
-			fmt.Printf("  Queue: %d items\n", len(queue))
-		}
-	} else {
-		fmt.Printf("  %s stopped\n", style.Dim.Render("○"))
-	}
-	fmt.Println()
-
-	// Polecats
-	polecatGit := git.NewGit(r.Path)
-	polecatMgr := polecat.NewManager(r, polecatGit, t)
-	polecats, err := polecatMgr.List()
-	fmt.Printf("%s", style.Bold.Render("Polecats"))
-	if err != nil || len(polecats) == 0 {
-		fmt.Printf(" (none)\n")
-	} else {
-		fmt.Printf(" (%d)\n", len(polecats))
-		for _, p := range polecats {
-			sessionName := fmt.Sprintf("gt-%s-%s", rigName, p.Name)
-			hasSession, _ := t.HasSession(sessionName)
-
-			sessionIcon := style.Dim.Render("○")
-			if hasSession {
-				sessionIcon = style.Success.Render("●")
-			}
-
-			stateStr := string(p.State)
-			if p.Issue != "" {
-				stateStr = fmt.Sprintf("%s → %s", p.State, p.Issue)
-			}
-
-			fmt.Printf("  %s %s: %s\n", sessionIcon, p.Name, stateStr)
-		}
-	}
-	fmt.Println()
-
-	// Crew
-	crewMgr := crew.NewManager(r, git.NewGit(townRoot))
-	crewWorkers, err := crewMgr.List()
-	fmt.Printf("%s", style.Bold.Render("Crew"))
-	if err != nil || len(crewWorkers) == 0 {
-		fmt.Printf(" (none)\n")
-	} else {
-		fmt.Printf(" (%d)\n", len(crewWorkers))
-		for _, w := range crewWorkers {
-			sessionName := crewSessionName(rigName, w.Name)
-			hasSession, _ := t.HasSession(sessionName)
-
-			sessionIcon := style.Dim.Render("○")
-			if hasSession {
-				sessionIcon = style.Success.Render("●")
-			}
-
-			// Get git info
-			crewGit := git.NewGit(w.ClonePath)
-			branch, _ := crewGit.CurrentBranch()
-			gitStatus, _ := crewGit.Status()
-
-			gitInfo := ""
-			if gitStatus != nil && !gitStatus.Clean {
-				gitInfo = style.Warning.Render(" (dirty)")
-			}
-
-			fmt.Printf("  %s %s: %s%s\n", sessionIcon, w.Name, branch, gitInfo)
-		}
-	}
-
-	return nil
-}
-
 func runRigStop(cmd *cobra.Command, args []string) error {
 	// Find workspace
 	townRoot, err := workspace.FindFromCwdOrError()
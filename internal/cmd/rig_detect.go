@@ -1,49 +1,92 @@
-// ABOUTME: Hidden command for shell hook to detect rigs and update cache.
+// ABOUTME: Hidden command for shell hook to detect rigs, with a cache.
 // ABOUTME: Called by shell integration to set GT_TOWN_ROOT and GT_RIG env vars.
 
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/KeithWyatt/gongshow/internal/state"
+	"github.com/KeithWyatt/gongshow/internal/util"
 	"github.com/KeithWyatt/gongshow/internal/workspace"
 )
 
-var rigDetectCache string
+var (
+	rigDetectLegacyCache string
+	rigDetectShell       string
+	rigDetectRefresh     bool
+	rigDetectClearCache  bool
+	rigDetectQuiet       bool
+	rigDetectActor       bool
+)
 
 var rigDetectCmd = &cobra.Command{
 	Use:    "detect [path]",
 	Short:  "Detect rig from repository path (internal use)",
 	Hidden: true,
-	Long: `Detect rig from a repository path and optionally cache the result.
+	Long: `Detect rig from a repository path.
 
-This is an internal command used by shell integration. It checks if the given
-path is inside a GongShow rig and outputs shell variable assignments.
+This is an internal command used by shell integration, which runs it on every
+prompt. It checks if the given path is inside a GongShow rig and outputs shell
+variable assignments.
 
-When --cache is specified, the result is written to ~/.cache/gongshow/rigs.cache
-for fast lookups by the shell hook.
+Results are cached in ~/.cache/gongshow/rig-detect.json. An entry is used for
+up to 10 minutes, and is dropped early if the repository's .git is replaced or
+the town's mayor/rigs.json changes. --refresh detects again and updates the
+entry; --clear-cache empties the cache.
 
 Output format (to stdout):
   export GT_TOWN_ROOT=/path/to/town
   export GT_RIG=rigname
 
 Or if not in a rig:
-  unset GT_TOWN_ROOT GT_RIG`,
+  unset GT_TOWN_ROOT GT_RIG
+
+With --shell fish, the same in fish syntax (set -gx / set -e). With
+--format nu, a record for nushell's load-env, holding only the variables
+that are set ({} if not in a rig); the nu hook hides the old ones first.
+
+With --actor, BD_ACTOR is also exported when the path is a role's workspace
+(crew, polecat, witness, refinery). --quiet drops warnings from stderr; the
+.envrc block written by 'gt rig envrc' uses both.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runRigDetect,
 }
 
 func init() {
 	rigCmd.AddCommand(rigDetectCmd)
-	rigDetectCmd.Flags().StringVar(&rigDetectCache, "cache", "", "Repository path to cache detection result for")
+	rigDetectCmd.Flags().StringVar(&rigDetectShell, "shell", "sh", "Syntax of the output: sh, fish or nu")
+	rigDetectCmd.Flags().StringVar(&rigDetectShell, "format", "sh", "Alias for --shell")
+	rigDetectCmd.Flags().BoolVar(&rigDetectRefresh, "refresh", false, "Ignore the cached result and detect again")
+	rigDetectCmd.Flags().BoolVar(&rigDetectClearCache, "clear-cache", false, "Remove all cached detection results and exit")
+	rigDetectCmd.Flags().BoolVarP(&rigDetectQuiet, "quiet", "q", false, "Don't print warnings")
+	rigDetectCmd.Flags().BoolVar(&rigDetectActor, "actor", false, "Also export BD_ACTOR for the role whose workspace the path is")
+
+	// Hook scripts installed by older versions still pass --cache.
+	rigDetectCmd.Flags().StringVar(&rigDetectLegacyCache, "cache", "", "Repository path to cache detection result for")
+	_ = rigDetectCmd.Flags().MarkDeprecated("cache", "results are always cached; reinstall the hook with 'gt install --shell'")
 }
 
 func runRigDetect(cmd *cobra.Command, args []string) error {
+	if rigDetectClearCache {
+		if err := clearRigDetectCache(); err != nil {
+			return fmt.Errorf("clearing rig detection cache: %w", err)
+		}
+		fmt.Fprintln(os.Stderr, "Cleared rig detection cache")
+		return nil
+	}
+	if rigDetectLegacyCache != "" {
+		// The old hook reads its own cache file before calling us, and
+		// nothing updates it any more: remove it so the hook falls through.
+		removeLegacyRigCaches()
+	}
+
 	checkPath := "."
 	if len(args) > 0 {
 		checkPath = args[0]
@@ -54,28 +97,56 @@ func runRigDetect(cmd *cobra.Command, args []string) error {
 		return outputNotInRig()
 	}
 
-	townRoot, err := workspace.Find(absPath)
-	if err != nil || townRoot == "" {
-		return outputNotInRig()
+	townRoot, rigName := detectRigCached(absPath, rigDetectRefresh, time.Now())
+	actor := ""
+	if rigDetectActor && rigName != "" {
+		actor = workspaceActor(absPath, townRoot)
 	}
 
-	rigName := detectRigFromPath(townRoot, absPath)
-
-	if rigName != "" {
-		fmt.Printf("export GT_TOWN_ROOT=%q\n", townRoot)
-		fmt.Printf("export GT_RIG=%q\n", rigName)
-	} else {
-		fmt.Printf("export GT_TOWN_ROOT=%q\n", townRoot)
-		fmt.Println("unset GT_RIG")
+	if rigDetectShell == "nu" {
+		fmt.Println(nuEnvRecord(townRoot, rigName, actor))
+		return nil
+	}
+	for _, stmt := range rigEnvStatements(rigDetectShell, townRoot, rigName) {
+		fmt.Println(stmt)
 	}
+	if actor != "" {
+		fmt.Println(exportStatement(rigDetectShell, "BD_ACTOR", actor))
+	}
+	return nil
+}
 
-	if rigDetectCache != "" {
-		if err := updateRigCache(rigDetectCache, townRoot, rigName); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: could not update cache: %v\n", err)
+// nuEnvRecord returns the load-env record for nushell: a JSON object, which
+// nu reads with 'from json', holding the variables that aren't empty.
+func nuEnvRecord(townRoot, rigName, actor string) string {
+	record := map[string]string{}
+	for name, value := range map[string]string{"GT_TOWN_ROOT": townRoot, "GT_RIG": rigName, "BD_ACTOR": actor} {
+		if value != "" {
+			record[name] = value
 		}
 	}
+	data, _ := json.Marshal(record)
+	return string(data)
+}
 
-	return nil
+// workspaceActor returns the BD_ACTOR identity of the role whose workspace
+// absPath is, or "" if it isn't one.
+func workspaceActor(absPath, townRoot string) string {
+	ctx := detectRole(absPath, townRoot)
+	if ctx.Role == RoleUnknown {
+		return ""
+	}
+	return ctx.ActorString()
+}
+
+// detectRig returns the town root and rig for absPath; both are empty
+// outside a town, and the rig is empty in a town but outside a rig.
+func detectRig(absPath string) (townRoot, rigName string) {
+	townRoot, err := workspace.Find(absPath)
+	if err != nil || townRoot == "" {
+		return "", ""
+	}
+	return townRoot, detectRigFromPath(townRoot, absPath)
 }
 
 func detectRigFromPath(townRoot, absPath string) string {
@@ -105,43 +176,165 @@ func detectRigFromPath(townRoot, absPath string) string {
 }
 
 func outputNotInRig() error {
-	fmt.Println("unset GT_TOWN_ROOT GT_RIG")
+	if rigDetectShell == "nu" {
+		fmt.Println(nuEnvRecord("", "", ""))
+		return nil
+	}
+	for _, stmt := range rigEnvStatements(rigDetectShell, "", "") {
+		fmt.Println(stmt)
+	}
 	return nil
 }
 
-func updateRigCache(repoRoot, townRoot, rigName string) error {
-	cacheDir := state.CacheDir()
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return err
+// rigEnvStatements returns the statements that set GT_TOWN_ROOT and GT_RIG
+// (unsetting whichever is empty) in the syntax of shell: "fish", or POSIX
+// sh for anything else.
+func rigEnvStatements(shell, townRoot, rigName string) []string {
+	if shell == "fish" {
+		if townRoot == "" {
+			return []string{"set -e GT_TOWN_ROOT", "set -e GT_RIG"}
+		}
+		stmts := []string{exportStatement(shell, "GT_TOWN_ROOT", townRoot)}
+		if rigName != "" {
+			return append(stmts, exportStatement(shell, "GT_RIG", rigName))
+		}
+		return append(stmts, "set -e GT_RIG")
 	}
 
-	cachePath := filepath.Join(cacheDir, "rigs.cache")
+	if townRoot == "" {
+		return []string{"unset GT_TOWN_ROOT GT_RIG"}
+	}
+	stmts := []string{exportStatement(shell, "GT_TOWN_ROOT", townRoot)}
+	if rigName != "" {
+		return append(stmts, exportStatement(shell, "GT_RIG", rigName))
+	}
+	return append(stmts, "unset GT_RIG")
+}
 
-	existing := make(map[string]string)
-	if data, err := os.ReadFile(cachePath); err == nil {
-		lines := strings.Split(string(data), "\n")
-		for _, line := range lines {
-			if idx := strings.Index(line, ":"); idx > 0 {
-				existing[line[:idx]] = line[idx+1:]
-			}
+// exportStatement returns the statement exporting name=value in the syntax
+// of shell.
+func exportStatement(shell, name, value string) string {
+	if shell == "fish" {
+		return fmt.Sprintf("set -gx %s %s", name, fishQuote(value))
+	}
+	return fmt.Sprintf("export %s=%q", name, value)
+}
+
+// fishQuote single-quotes s for fish, where only \ and ' need escaping.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// rigDetectCacheVersion is the cache format; a cache written with another
+// version is discarded.
+const rigDetectCacheVersion = 1
+
+// rigDetectCacheTTL is how long a cached detection is used before it is
+// detected again, to catch changes the stamps below don't see (a rig's
+// config.json appearing, a town being moved).
+const rigDetectCacheTTL = 10 * time.Minute
+
+// rigDetectCache maps absolute repository paths to their detected rig.
+type rigDetectCache struct {
+	Version int                            `json:"version"`
+	Entries map[string]rigDetectCacheEntry `json:"entries"`
+}
+
+// rigDetectCacheEntry is one detection result, stamped with what it depends
+// on: the inode of the repository's .git (a re-clone gets a new one) and
+// the mtime of the town's mayor/rigs.json (rigs added or removed).
+type rigDetectCacheEntry struct {
+	TownRoot   string    `json:"town_root,omitempty"`
+	Rig        string    `json:"rig,omitempty"`
+	GitDirIno  uint64    `json:"git_dir_ino,omitempty"`
+	RigsMtime  int64     `json:"rigs_mtime,omitempty"` // UnixNano; 0 if absent
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+func rigDetectCachePath() string {
+	return filepath.Join(state.CacheDir(), "rig-detect.json")
+}
+
+// loadRigDetectCache reads the cache; a missing, unreadable or outdated one
+// is empty.
+func loadRigDetectCache() *rigDetectCache {
+	var c rigDetectCache
+	if data, err := os.ReadFile(rigDetectCachePath()); err == nil {
+		_ = json.Unmarshal(data, &c)
+	}
+	if c.Version != rigDetectCacheVersion || c.Entries == nil {
+		c = rigDetectCache{Version: rigDetectCacheVersion, Entries: make(map[string]rigDetectCacheEntry)}
+	}
+	return &c
+}
+
+func saveRigDetectCache(c *rigDetectCache) error {
+	if err := os.MkdirAll(filepath.Dir(rigDetectCachePath()), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(rigDetectCachePath(), c)
+}
+
+// rigDetectStamps returns the stamps a detection of absPath in townRoot
+// depends on.
+func rigDetectStamps(absPath, townRoot string) (gitDirIno uint64, rigsMtime int64) {
+	if info, err := os.Stat(filepath.Join(absPath, ".git")); err == nil {
+		gitDirIno = fileInode(info)
+	}
+	if townRoot != "" {
+		if info, err := os.Stat(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+			rigsMtime = info.ModTime().UnixNano()
 		}
 	}
+	return gitDirIno, rigsMtime
+}
 
-	var value string
-	if rigName != "" {
-		value = fmt.Sprintf("export GT_TOWN_ROOT=%q; export GT_RIG=%q", townRoot, rigName)
-	} else if townRoot != "" {
-		value = fmt.Sprintf("export GT_TOWN_ROOT=%q; unset GT_RIG", townRoot)
-	} else {
-		value = "unset GT_TOWN_ROOT GT_RIG"
+// detectRigCached is detectRig backed by the cache. Unless refresh is set,
+// a cached entry is used while it is younger than rigDetectCacheTTL and its
+// stamps still match. Fresh detections are cached, and expired entries are
+// dropped whenever the cache is written.
+func detectRigCached(absPath string, refresh bool, now time.Time) (townRoot, rigName string) {
+	c := loadRigDetectCache()
+	if e, ok := c.Entries[absPath]; ok && !refresh && now.Sub(e.DetectedAt) < rigDetectCacheTTL {
+		if ino, mtime := rigDetectStamps(absPath, e.TownRoot); ino == e.GitDirIno && mtime == e.RigsMtime {
+			return e.TownRoot, e.Rig
+		}
 	}
 
-	existing[repoRoot] = value
+	townRoot, rigName = detectRig(absPath)
+	ino, mtime := rigDetectStamps(absPath, townRoot)
+	for path, e := range c.Entries {
+		if now.Sub(e.DetectedAt) >= rigDetectCacheTTL {
+			delete(c.Entries, path)
+		}
+	}
+	c.Entries[absPath] = rigDetectCacheEntry{
+		TownRoot:   townRoot,
+		Rig:        rigName,
+		GitDirIno:  ino,
+		RigsMtime:  mtime,
+		DetectedAt: now,
+	}
+	if err := saveRigDetectCache(c); err != nil && !rigDetectQuiet {
+		fmt.Fprintf(os.Stderr, "warning: could not update rig detection cache: %v\n", err)
+	}
+	return townRoot, rigName
+}
 
-	var lines []string
-	for k, v := range existing {
-		lines = append(lines, k+":"+v)
+// clearRigDetectCache removes the cache, and the shell-side caches older
+// hook scripts kept.
+func clearRigDetectCache() error {
+	if err := os.Remove(rigDetectCachePath()); err != nil && !os.IsNotExist(err) {
+		return err
 	}
+	removeLegacyRigCaches()
+	return nil
+}
 
-	return os.WriteFile(cachePath, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+// removeLegacyRigCaches removes the caches hook scripts before
+// rig-detect.json grepped for themselves.
+func removeLegacyRigCaches() {
+	for _, name := range []string{"rigs.cache", "rigs.fish.cache"} {
+		_ = os.Remove(filepath.Join(state.CacheDir(), name))
+	}
 }
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRigEnvStatements(t *testing.T) {
+	tests := []struct {
+		shell, townRoot, rig string
+		want                 []string
+	}{
+		{"sh", "/home/me/gt", "gongshow", []string{`export GT_TOWN_ROOT="/home/me/gt"`, `export GT_RIG="gongshow"`}},
+		{"sh", "/home/me/gt", "", []string{`export GT_TOWN_ROOT="/home/me/gt"`, "unset GT_RIG"}},
+		{"sh", "", "", []string{"unset GT_TOWN_ROOT GT_RIG"}},
+		{"fish", "/home/me/gt", "gongshow", []string{"set -gx GT_TOWN_ROOT '/home/me/gt'", "set -gx GT_RIG 'gongshow'"}},
+		{"fish", "/home/me/gt", "", []string{"set -gx GT_TOWN_ROOT '/home/me/gt'", "set -e GT_RIG"}},
+		{"fish", "", "", []string{"set -e GT_TOWN_ROOT", "set -e GT_RIG"}},
+		{"fish", `/home/me/it's\gt`, "", []string{`set -gx GT_TOWN_ROOT '/home/me/it\'s\\gt'`, "set -e GT_RIG"}},
+	}
+	for _, tt := range tests {
+		if got := rigEnvStatements(tt.shell, tt.townRoot, tt.rig); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("rigEnvStatements(%q, %q, %q) = %q, want %q", tt.shell, tt.townRoot, tt.rig, got, tt.want)
+		}
+	}
+}
+
+// setupDetectTown creates a town with rig "myrig" holding a git repo, and
+// points the cache at a temp dir. Returns the town root and repo path.
+func setupDetectTown(t *testing.T) (townRoot, repo string) {
+	t.Helper()
+	townRoot = t.TempDir()
+	repo = filepath.Join(townRoot, "myrig", "crew", "max")
+	for _, dir := range []string{filepath.Join(townRoot, "mayor"), filepath.Join(repo, ".git")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{"mayor/town.json", "mayor/rigs.json", "myrig/config.json"} {
+		if err := os.WriteFile(filepath.Join(townRoot, f), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	return townRoot, repo
+}
+
+// removeRig deletes myrig's config.json, so a fresh detection no longer
+// finds the rig and only a cache hit still reports it.
+func removeRig(t *testing.T, townRoot string) {
+	t.Helper()
+	if err := os.Remove(filepath.Join(townRoot, "myrig", "config.json")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertDetected(t *testing.T, gotTown, gotRig, wantTown, wantRig string) {
+	t.Helper()
+	if gotTown != wantTown || gotRig != wantRig {
+		t.Errorf("detected (%q, %q), want (%q, %q)", gotTown, gotRig, wantTown, wantRig)
+	}
+}
+
+func TestNuEnvRecord(t *testing.T) {
+	tests := []struct {
+		townRoot, rig, actor string
+		want                 string
+	}{
+		{"", "", "", `{}`},
+		{"/home/me/gt", "", "", `{"GT_TOWN_ROOT":"/home/me/gt"}`},
+		{"/home/me/gt", "gongshow", "gongshow/crew/max", `{"BD_ACTOR":"gongshow/crew/max","GT_RIG":"gongshow","GT_TOWN_ROOT":"/home/me/gt"}`},
+		{`/home/me/"odd" gt`, "r", "", `{"GT_RIG":"r","GT_TOWN_ROOT":"/home/me/\"odd\" gt"}`},
+	}
+	for _, tt := range tests {
+		if got := nuEnvRecord(tt.townRoot, tt.rig, tt.actor); got != tt.want {
+			t.Errorf("nuEnvRecord(%q, %q, %q) = %s, want %s", tt.townRoot, tt.rig, tt.actor, got, tt.want)
+		}
+	}
+}
+
+func TestDetectRigCached_Hit(t *testing.T) {
+	townRoot, repo := setupDetectTown(t)
+	now := time.Now()
+
+	town, rig := detectRigCached(repo, false, now)
+	assertDetected(t, town, rig, townRoot, "myrig")
+
+	removeRig(t, townRoot)
+	town, rig = detectRigCached(repo, false, now.Add(time.Minute))
+	assertDetected(t, town, rig, townRoot, "myrig")
+
+	// --refresh bypasses the entry and replaces it.
+	town, rig = detectRigCached(repo, true, now.Add(time.Minute))
+	assertDetected(t, town, rig, townRoot, "")
+	town, rig = detectRigCached(repo, false, now.Add(time.Minute))
+	assertDetected(t, town, rig, townRoot, "")
+}
+
+func TestDetectRigCached_TTLExpiry(t *testing.T) {
+	townRoot, repo := setupDetectTown(t)
+	now := time.Now()
+	detectRigCached(repo, false, now)
+
+	removeRig(t, townRoot)
+	town, rig := detectRigCached(repo, false, now.Add(rigDetectCacheTTL))
+	assertDetected(t, town, rig, townRoot, "")
+}
+
+func TestDetectRigCached_RigsJSONInvalidates(t *testing.T) {
+	townRoot, repo := setupDetectTown(t)
+	now := time.Now()
+	detectRigCached(repo, false, now)
+
+	removeRig(t, townRoot)
+	rigsJSON := filepath.Join(townRoot, "mayor", "rigs.json")
+	later := now.Add(time.Hour)
+	if err := os.Chtimes(rigsJSON, later, later); err != nil {
+		t.Fatal(err)
+	}
+	town, rig := detectRigCached(repo, false, now.Add(time.Minute))
+	assertDetected(t, town, rig, townRoot, "")
+}
+
+func TestDetectRigCached_GitDirReplaced(t *testing.T) {
+	townRoot, repo := setupDetectTown(t)
+	now := time.Now()
+	detectRigCached(repo, false, now)
+
+	// A re-clone replaces .git, giving it a new inode.
+	removeRig(t, townRoot)
+	gitDir := filepath.Join(repo, ".git")
+	if err := os.Rename(gitDir, gitDir+".old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(gitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	town, rig := detectRigCached(repo, false, now.Add(time.Minute))
+	assertDetected(t, town, rig, townRoot, "")
+}
+
+func TestClearRigDetectCache(t *testing.T) {
+	townRoot, repo := setupDetectTown(t)
+	legacy := filepath.Join(os.Getenv("XDG_CACHE_HOME"), "gongshow", "rigs.cache")
+	detectRigCached(repo, false, time.Now())
+	if err := os.WriteFile(legacy, []byte(repo+":unset GT_TOWN_ROOT GT_RIG\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := clearRigDetectCache(); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{rigDetectCachePath(), legacy} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists after clearing", path)
+		}
+	}
+	removeRig(t, townRoot)
+	town, rig := detectRigCached(repo, false, time.Now())
+	assertDetected(t, town, rig, townRoot, "")
+}
+
+func TestWorkspaceActor(t *testing.T) {
+	townRoot, repo := setupDetectTown(t)
+	if got, want := workspaceActor(repo, townRoot), "myrig/crew/max"; got != want {
+		t.Errorf("workspaceActor(crew) = %q, want %q", got, want)
+	}
+	if got, want := workspaceActor(filepath.Join(townRoot, "myrig", "witness", "rig"), townRoot), "myrig/witness"; got != want {
+		t.Errorf("workspaceActor(witness) = %q, want %q", got, want)
+	}
+	if got := workspaceActor(filepath.Join(townRoot, "myrig"), townRoot); got != "" {
+		t.Errorf("workspaceActor(rig root) = %q, want empty", got)
+	}
+	if got, want := exportStatement("fish", "BD_ACTOR", "myrig/crew/max"), "set -gx BD_ACTOR 'myrig/crew/max'"; got != want {
+		t.Errorf("exportStatement(fish) = %q, want %q", got, want)
+	}
+}
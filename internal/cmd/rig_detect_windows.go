@@ -0,0 +1,11 @@
+//go:build windows
+
+package cmd
+
+import "os"
+
+// fileInode returns 0: FileInfo carries no file index on Windows, so cached
+// rig detections there rely on the TTL and rigs.json stamp alone.
+func fileInode(info os.FileInfo) uint64 {
+	return 0
+}
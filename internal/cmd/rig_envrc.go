@@ -0,0 +1,132 @@
+// ABOUTME: direnv integration command for rigs.
+// ABOUTME: Prints, writes or removes the GongShow block in a repo's .envrc.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/shell"
+	"github.com/KeithWyatt/gongshow/internal/style"
+)
+
+var (
+	rigEnvrcWrite  bool
+	rigEnvrcRemove bool
+	rigEnvrcAllow  bool
+)
+
+var rigEnvrcCmd = &cobra.Command{
+	Use:   "envrc [path]",
+	Short: "Print or manage a direnv .envrc block for the current repo",
+	Long: `Print, write or remove a direnv snippet that sets GT_TOWN_ROOT, GT_RIG
+and BD_ACTOR for a repository in a rig, as an alternative to the shell
+integration's prompt hook ('gt shell install').
+
+The snippet doesn't hard-code the values: it runs 'gt rig detect --quiet'
+each time direnv loads, so it stays correct if the rig is renamed.
+
+With no flags the snippet is printed. --write adds it to the .envrc at the
+repository root inside a managed marker block, or updates the block already
+there; --remove takes the block out again (deleting the .envrc if nothing
+else is in it). Content outside the markers is never touched.
+
+direnv refuses to load a changed .envrc until it is allowed again. Pass
+--allow to run 'direnv allow' after writing.
+
+Examples:
+  gt rig envrc                  # Print the snippet
+  gt rig envrc --write --allow  # Add it to ./.envrc and allow it
+  gt rig envrc --remove`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRigEnvrc,
+}
+
+func init() {
+	rigCmd.AddCommand(rigEnvrcCmd)
+	rigEnvrcCmd.Flags().BoolVar(&rigEnvrcWrite, "write", false, "Write the snippet into the repo's .envrc")
+	rigEnvrcCmd.Flags().BoolVar(&rigEnvrcRemove, "remove", false, "Remove the snippet from the repo's .envrc")
+	rigEnvrcCmd.Flags().BoolVar(&rigEnvrcAllow, "allow", false, "Run 'direnv allow' after changing the .envrc")
+	rigEnvrcCmd.MarkFlagsMutuallyExclusive("write", "remove")
+}
+
+func runRigEnvrc(cmd *cobra.Command, args []string) error {
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	repoRoot, err := findGitRoot(absPath)
+	if err != nil {
+		return fmt.Errorf("%s is not in a git repository", absPath)
+	}
+	envrcPath := shell.EnvrcPath(repoRoot)
+
+	if rigEnvrcRemove {
+		removed, err := shell.RemoveEnvrc(envrcPath)
+		if err != nil {
+			return fmt.Errorf("updating %s: %w", envrcPath, err)
+		}
+		if !removed {
+			fmt.Printf("%s No GongShow block in %s\n", style.Dim.Render("○"), envrcPath)
+			return nil
+		}
+		fmt.Printf("%s Removed GongShow block from %s\n", style.Success.Render("✓"), envrcPath)
+		if _, err := os.Stat(envrcPath); err == nil {
+			return allowEnvrc(repoRoot)
+		}
+		return nil
+	}
+
+	townRoot, rigName := detectRig(repoRoot)
+	if rigName == "" {
+		if townRoot == "" {
+			return fmt.Errorf("%s is not in a GongShow town", repoRoot)
+		}
+		return fmt.Errorf("%s is not in a rig of %s", repoRoot, townRoot)
+	}
+
+	if !rigEnvrcWrite {
+		fmt.Println(shell.EnvrcBlock)
+		return nil
+	}
+
+	changed, err := shell.WriteEnvrc(envrcPath)
+	if err != nil {
+		return fmt.Errorf("updating %s: %w", envrcPath, err)
+	}
+	if !changed {
+		fmt.Printf("%s %s is up to date (rig %s)\n", style.Success.Render("✓"), envrcPath, rigName)
+		return nil
+	}
+	fmt.Printf("%s Wrote GongShow block to %s (rig %s)\n", style.Success.Render("✓"), envrcPath, rigName)
+	return allowEnvrc(repoRoot)
+}
+
+// allowEnvrc runs 'direnv allow' for dir when --allow was passed, and
+// otherwise says it is needed.
+func allowEnvrc(dir string) error {
+	if !rigEnvrcAllow {
+		fmt.Printf("  Run 'direnv allow' in %s to load it.\n", dir)
+		return nil
+	}
+	direnv, err := exec.LookPath("direnv")
+	if err != nil {
+		return fmt.Errorf("--allow: direnv not found in PATH")
+	}
+	c := exec.Command(direnv, "allow", dir) //nolint:gosec // G204: direnv from PATH, dir is the repo root
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("direnv allow: %w", err)
+	}
+	fmt.Printf("%s Allowed %s\n", style.Success.Render("✓"), shell.EnvrcPath(dir))
+	return nil
+}
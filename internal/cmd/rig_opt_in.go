@@ -0,0 +1,83 @@
+// ABOUTME: Opt-in command for the shell hook's opt-in mode.
+// ABOUTME: Registers a repo for the hook without adding it as a rig.
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/state"
+	"github.com/KeithWyatt/gongshow/internal/style"
+)
+
+var rigOptInRemove bool
+
+var rigOptInCmd = &cobra.Command{
+	Use:   "opt-in [path]",
+	Short: "Let the shell hook run in a repo under opt-in mode",
+	Long: `Register a git repository for the shell hook's opt-in mode, without
+adding it as a rig.
+
+In opt-in mode ('gt config set shell.mode opt-in') the shell hook only
+runs in registered repos and in repos inside a town, and never offers to
+add a repo to GongShow. Registering a directory also covers the repos
+below it. In the default mode the registration has no effect.
+
+Examples:
+  gt rig opt-in                  # Register the current repo
+  gt rig opt-in ~/src            # Register every repo under ~/src
+  gt rig opt-in --remove`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRigOptIn,
+}
+
+func init() {
+	rigCmd.AddCommand(rigOptInCmd)
+	rigOptInCmd.Flags().BoolVar(&rigOptInRemove, "remove", false, "Unregister the repo")
+}
+
+func runRigOptIn(cmd *cobra.Command, args []string) error {
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	absPath, err := filepath.Abs(targetPath)
+	if err != nil {
+		return fmt.Errorf("resolving path: %w", err)
+	}
+	// A repo is registered by its root, which the hook matches against; a
+	// directory outside any repo is registered as given.
+	dir := absPath
+	if gitRoot, err := findGitRoot(absPath); err == nil {
+		dir = gitRoot
+	}
+
+	if rigOptInRemove {
+		removed, err := state.RemoveOptInRepo(dir)
+		if err != nil {
+			return fmt.Errorf("updating %s: %w", state.OptInReposPath(), err)
+		}
+		if !removed {
+			fmt.Printf("%s %s is not registered\n", style.Dim.Render("○"), dir)
+			return nil
+		}
+		fmt.Printf("%s Unregistered %s\n", style.Success.Render("✓"), dir)
+		return nil
+	}
+
+	added, err := state.AddOptInRepo(dir)
+	if err != nil {
+		return fmt.Errorf("updating %s: %w", state.OptInReposPath(), err)
+	}
+	if !added {
+		fmt.Printf("%s %s is already registered\n", style.Success.Render("✓"), dir)
+	} else {
+		fmt.Printf("%s Registered %s for the shell hook\n", style.Success.Render("✓"), dir)
+	}
+	if state.GetShellMode() != state.ShellModeOptIn {
+		fmt.Printf("  The shell hook runs in every repo in %s mode; 'gt config set shell.mode opt-in' limits it to registered ones.\n", state.GetShellMode())
+	}
+	return nil
+}
@@ -0,0 +1,551 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/crew"
+	"github.com/KeithWyatt/gongshow/internal/git"
+	"github.com/KeithWyatt/gongshow/internal/polecat"
+	"github.com/KeithWyatt/gongshow/internal/refinery"
+	"github.com/KeithWyatt/gongshow/internal/rig"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+	"github.com/KeithWyatt/gongshow/internal/witness"
+	"golang.org/x/term"
+)
+
+// Rig status flags
+var (
+	rigStatusJSON  bool
+	rigStatusWatch time.Duration
+)
+
+const (
+	// rigStatusEscalationWindow is how far back the dashboard looks for
+	// witness escalations.
+	rigStatusEscalationWindow = 24 * time.Hour
+
+	// rigStatusMaxEscalations caps the escalations shown, newest first.
+	rigStatusMaxEscalations = 10
+
+	// rigStatusSummaryLen is where work summaries are cut in the dashboard.
+	rigStatusSummaryLen = 60
+)
+
+var rigStatusCmd = &cobra.Command{
+	Use:   "status [rig]",
+	Short: "Show detailed status for a specific rig",
+	Long: `Show a health dashboard for a specific rig and its agents.
+
+If no rig is specified, infers the rig from the current directory.
+
+Displays:
+- Rig information (name, path, beads prefix, operational state)
+- Witness status (running/stopped, uptime, last patrol)
+- Refinery status (running/stopped, uptime, merge queue depth)
+- Polecats (name, state, agent state, assigned issue, work summary)
+- Crew members (name, branch, session status, git status)
+- Witness escalations from the last 24 hours
+- Pending beads by status
+
+With --watch the dashboard is redrawn at the given interval until
+interrupted. --json prints the same data once, for scripts.
+
+Examples:
+  gt rig status           # Infer rig from current directory
+  gt rig status gongshow
+  gt rig status gongshow --watch 10s
+  gt rig status gongshow --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRigStatus,
+}
+
+func init() {
+	rigCmd.AddCommand(rigStatusCmd)
+
+	rigStatusCmd.Flags().BoolVar(&rigStatusJSON, "json", false, "Output as JSON")
+	rigStatusCmd.Flags().DurationVar(&rigStatusWatch, "watch", 0, "Redraw the dashboard at this interval (e.g. 10s)")
+}
+
+// RigDashboard is a snapshot of a rig's agents and work, as shown by
+// gt rig status.
+type RigDashboard struct {
+	Rig         string                 `json:"rig"`
+	State       string                 `json:"state"`        // OPERATIONAL, PARKED or DOCKED
+	StateSource string                 `json:"state_source"` // Where State came from
+	Path        string                 `json:"path"`
+	Prefix      string                 `json:"prefix,omitempty"`
+	Witness     DashboardWitness       `json:"witness"`
+	Refinery    DashboardRefinery      `json:"refinery"`
+	Polecats    []DashboardPolecat     `json:"polecats"`
+	Crew        []DashboardCrew        `json:"crew"`
+	Escalations []witness.PatrolAction `json:"escalations"` // Newest first
+	Beads       map[string]int         `json:"beads"`       // Pending (not closed) beads by status
+	GeneratedAt time.Time              `json:"generated_at"`
+}
+
+// DashboardWitness is the witness's part of a RigDashboard.
+type DashboardWitness struct {
+	Running    bool                  `json:"running"`
+	StartedAt  *time.Time            `json:"started_at,omitempty"`
+	Patrols    int                   `json:"patrols"` // In the escalation window
+	LastPatrol *witness.PatrolRecord `json:"last_patrol,omitempty"`
+}
+
+// DashboardRefinery is the refinery's part of a RigDashboard.
+type DashboardRefinery struct {
+	Running    bool       `json:"running"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	QueueDepth int        `json:"queue_depth"`
+}
+
+// DashboardPolecat is one polecat in a RigDashboard. AgentState and
+// WorkSummary come from its agent bead.
+type DashboardPolecat struct {
+	Name           string `json:"name"`
+	State          string `json:"state"`
+	AgentState     string `json:"agent_state,omitempty"`
+	Issue          string `json:"issue,omitempty"`
+	WorkSummary    string `json:"work_summary,omitempty"`
+	SessionRunning bool   `json:"session_running"`
+}
+
+// DashboardCrew is one crew member in a RigDashboard.
+type DashboardCrew struct {
+	Name           string `json:"name"`
+	Branch         string `json:"branch,omitempty"`
+	Dirty          bool   `json:"dirty"`
+	SessionRunning bool   `json:"session_running"`
+}
+
+// newRigDashboard returns an empty status for rigName, with lists that
+// encode as [] rather than null.
+func newRigDashboard(rigName string) *RigDashboard {
+	return &RigDashboard{
+		Rig:         rigName,
+		Polecats:    []DashboardPolecat{},
+		Crew:        []DashboardCrew{},
+		Escalations: []witness.PatrolAction{},
+		Beads:       map[string]int{},
+		GeneratedAt: time.Now(),
+	}
+}
+
+func runRigStatus(cmd *cobra.Command, args []string) error {
+	var rigName string
+
+	if len(args) > 0 {
+		rigName = args[0]
+	} else {
+		// Infer rig from current directory
+		roleInfo, err := GetRole()
+		if err != nil {
+			return fmt.Errorf("detecting rig from current directory: %w", err)
+		}
+		if roleInfo.Rig == "" {
+			return fmt.Errorf("could not detect rig from current directory; please specify rig name")
+		}
+		rigName = roleInfo.Rig
+	}
+
+	if rigStatusWatch < 0 {
+		return fmt.Errorf("--watch interval must be positive, got %s", rigStatusWatch)
+	}
+	if rigStatusJSON && rigStatusWatch > 0 {
+		return fmt.Errorf("--json and --watch cannot be used together")
+	}
+
+	// Get rig
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	if rigStatusWatch > 0 {
+		return watchRigStatus(townRoot, r, rigStatusWatch)
+	}
+
+	status := gatherRigDashboard(townRoot, r)
+	if rigStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+	renderRigDashboard(os.Stdout, status)
+	return nil
+}
+
+// watchRigStatus redraws the dashboard every interval until interrupted.
+func watchRigStatus(townRoot string, r *rig.Rig, interval time.Duration) error {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
+
+	for {
+		// Gather before clearing so the old dashboard stays up meanwhile.
+		status := gatherRigDashboard(townRoot, r)
+
+		if isTTY {
+			fmt.Print("\033[H\033[2J") // ANSI: cursor home + clear screen
+		}
+
+		timestamp := time.Now().Format("15:04:05")
+		header := fmt.Sprintf("[%s] gt rig status %s --watch %s (Ctrl+C to stop)", timestamp, r.Name, interval)
+		if isTTY {
+			fmt.Printf("%s\n\n", style.Dim.Render(header))
+		} else {
+			fmt.Printf("%s\n\n", header)
+		}
+
+		renderRigDashboard(os.Stdout, status)
+
+		select {
+		case <-sigChan:
+			if isTTY {
+				fmt.Println("\nStopped.")
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// gatherRigDashboard collects r's status. Parts that can't be read are left
+// empty: the dashboard shows what it can.
+func gatherRigDashboard(townRoot string, r *rig.Rig) *RigDashboard {
+	t := tmux.NewTmux()
+	status := newRigDashboard(r.Name)
+
+	status.State, status.StateSource = getRigOperationalState(townRoot, r.Name)
+	status.Path = r.Path
+	if r.Config != nil {
+		status.Prefix = r.Config.Prefix
+	}
+
+	// Witness
+	status.Witness.Running, _ = t.HasSession(fmt.Sprintf("gt-%s-witness", r.Name))
+	witMgr := witness.NewManager(r)
+	if witStatus, err := witMgr.Status(); err == nil && witStatus != nil {
+		status.Witness.StartedAt = witStatus.StartedAt
+	}
+	if history, err := witMgr.PatrolHistory(rigStatusEscalationWindow); err == nil {
+		status.Witness.Patrols = len(history)
+		if len(history) > 0 {
+			status.Witness.LastPatrol = &history[len(history)-1]
+		}
+		status.Escalations = recentEscalations(history, rigStatusMaxEscalations)
+	}
+
+	// Refinery
+	status.Refinery.Running, _ = t.HasSession(fmt.Sprintf("gt-%s-refinery", r.Name))
+	refMgr := refinery.NewManager(r)
+	if refStatus, err := refMgr.Status(); err == nil && refStatus != nil {
+		status.Refinery.StartedAt = refStatus.StartedAt
+	}
+	if queue, err := refMgr.Queue(); err == nil {
+		status.Refinery.QueueDepth = len(queue)
+	}
+
+	// Polecats, with their agent beads
+	bd := beads.New(r.BeadsPath())
+	agentBeads, _ := bd.ListAgentBeads()
+	polecatMgr := polecat.NewManager(r, git.NewGit(r.Path), t)
+	if polecats, err := polecatMgr.List(); err == nil {
+		for _, p := range polecats {
+			running, _ := t.HasSession(fmt.Sprintf("gt-%s-%s", r.Name, p.Name))
+			status.Polecats = append(status.Polecats, DashboardPolecat{
+				Name:           p.Name,
+				State:          string(p.State),
+				Issue:          p.Issue,
+				SessionRunning: running,
+			})
+		}
+	}
+	applyPolecatAgentBeads(status.Polecats, r.Name, agentBeads)
+
+	// Crew
+	crewMgr := crew.NewManager(r, git.NewGit(townRoot))
+	if crewWorkers, err := crewMgr.List(); err == nil {
+		for _, w := range crewWorkers {
+			running, _ := t.HasSession(crewSessionName(r.Name, w.Name))
+			crewGit := git.NewGit(w.ClonePath)
+			branch, _ := crewGit.CurrentBranch()
+			gitStatus, _ := crewGit.Status()
+			status.Crew = append(status.Crew, DashboardCrew{
+				Name:           w.Name,
+				Branch:         branch,
+				Dirty:          gitStatus != nil && !gitStatus.Clean,
+				SessionRunning: running,
+			})
+		}
+	}
+
+	// Pending beads: bd lists only those not closed unless told otherwise
+	if issues, err := bd.List(beads.ListOptions{Priority: -1}); err == nil {
+		status.Beads = countPendingBeads(issues)
+	}
+
+	return status
+}
+
+// applyPolecatAgentBeads fills in the agent state and work summary of each
+// of polecats from its agent bead in agentBeads, if it has one.
+func applyPolecatAgentBeads(polecats []DashboardPolecat, rigName string, agentBeads map[string]*beads.Issue) {
+	byName := make(map[string]*beads.Issue)
+	for id, issue := range agentBeads {
+		beadRig, role, name, ok := beads.ParseAgentBeadID(id)
+		if !ok || role != "polecat" || beadRig != rigName || issue.Status == "closed" {
+			continue
+		}
+		byName[name] = issue
+	}
+
+	for i := range polecats {
+		issue, ok := byName[polecats[i].Name]
+		if !ok {
+			continue
+		}
+		fields := beads.ParseAgentFields(issue.Description)
+		polecats[i].AgentState = issue.AgentState
+		if polecats[i].AgentState == "" {
+			polecats[i].AgentState = fields.AgentState
+		}
+		polecats[i].WorkSummary = fields.WorkSummary
+	}
+}
+
+// recentEscalations returns the escalations from history, newest first,
+// at most limit of them.
+func recentEscalations(history []witness.PatrolRecord, limit int) []witness.PatrolAction {
+	escalations := []witness.PatrolAction{}
+	for _, record := range history {
+		escalations = append(escalations, record.Escalations...)
+	}
+	sort.SliceStable(escalations, func(i, j int) bool {
+		return escalations[i].At.After(escalations[j].At)
+	})
+	if len(escalations) > limit {
+		escalations = escalations[:limit]
+	}
+	return escalations
+}
+
+// countPendingBeads counts issues by status, leaving out closed issues and
+// agent beads.
+func countPendingBeads(issues []*beads.Issue) map[string]int {
+	counts := map[string]int{}
+	for _, issue := range issues {
+		if issue.Status == "closed" || beads.HasLabel(issue, "gt:agent") {
+			continue
+		}
+		counts[issue.Status]++
+	}
+	return counts
+}
+
+// beadStatusOrder is the order statuses are listed in the dashboard; any
+// others follow alphabetically.
+var beadStatusOrder = []string{"open", "in_progress", "hooked", "blocked", "deferred"}
+
+// sortedBeadStatuses returns the statuses in counts in beadStatusOrder.
+func sortedBeadStatuses(counts map[string]int) []string {
+	rank := make(map[string]int, len(beadStatusOrder))
+	for i, s := range beadStatusOrder {
+		rank[s] = i
+	}
+	statuses := make([]string, 0, len(counts))
+	for s := range counts {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		ri, iKnown := rank[statuses[i]]
+		rj, jKnown := rank[statuses[j]]
+		switch {
+		case iKnown && jKnown:
+			return ri < rj
+		case iKnown != jKnown:
+			return iKnown
+		default:
+			return statuses[i] < statuses[j]
+		}
+	})
+	return statuses
+}
+
+// dashboardBox is one titled section of the rig dashboard.
+type dashboardBox struct {
+	title string
+	lines []string
+}
+
+// renderRigDashboard draws status as a dashboard of boxed sections.
+func renderRigDashboard(w io.Writer, status *RigDashboard) {
+	header := style.Bold.Render(status.Rig)
+	switch status.State {
+	case "OPERATIONAL":
+		header += "  " + style.Success.Render(status.State)
+	case "PARKED":
+		header += "  " + style.Warning.Render(status.State) + style.Dim.Render(" ("+status.StateSource+")")
+	case "DOCKED":
+		header += "  " + style.Dim.Render(status.State+" ("+status.StateSource+")")
+	}
+	fmt.Fprintln(w, header)
+	fmt.Fprintf(w, "%s\n", style.Dim.Render(status.Path))
+	if status.Prefix != "" {
+		fmt.Fprintf(w, "%s\n", style.Dim.Render("Beads prefix: "+status.Prefix+"-"))
+	}
+
+	boxes := []dashboardBox{
+		{title: "Witness", lines: witnessLines(status.Witness)},
+		{title: "Refinery", lines: refineryLines(status.Refinery)},
+		{title: fmt.Sprintf("Polecats (%d)", len(status.Polecats)), lines: polecatLines(status.Polecats)},
+		{title: fmt.Sprintf("Crew (%d)", len(status.Crew)), lines: crewLines(status.Crew)},
+		{title: "Escalations (24h)", lines: escalationLines(status.Escalations)},
+		{title: "Pending beads", lines: beadLines(status.Beads)},
+	}
+	writeBoxes(w, boxes)
+}
+
+// sessionIcon is ● for a running session and ○ otherwise.
+func sessionIcon(running bool) string {
+	if running {
+		return style.Success.Render("●")
+	}
+	return style.Dim.Render("○")
+}
+
+// runningLine describes an agent session, with its uptime if known.
+func runningLine(running bool, startedAt *time.Time) string {
+	if !running {
+		return sessionIcon(false) + " stopped"
+	}
+	line := sessionIcon(true) + " running"
+	if startedAt != nil {
+		line += fmt.Sprintf(" (uptime: %s)", formatDuration(time.Since(*startedAt)))
+	}
+	return line
+}
+
+func witnessLines(s DashboardWitness) []string {
+	lines := []string{runningLine(s.Running, s.StartedAt)}
+	if s.LastPatrol == nil {
+		return append(lines, style.Dim.Render("No patrols in the last 24h"))
+	}
+	patrol := fmt.Sprintf("Last patrol: %s ago, %d polecats", formatDuration(time.Since(s.LastPatrol.StartedAt)), s.LastPatrol.PolecatCount)
+	if s.LastPatrol.CompletedAt == nil {
+		patrol += style.Warning.Render(" (not completed)")
+	}
+	return append(lines, patrol, fmt.Sprintf("Patrols (24h): %d", s.Patrols))
+}
+
+func refineryLines(s DashboardRefinery) []string {
+	return []string{
+		runningLine(s.Running, s.StartedAt),
+		fmt.Sprintf("Queue: %d items", s.QueueDepth),
+	}
+}
+
+func polecatLines(polecats []DashboardPolecat) []string {
+	if len(polecats) == 0 {
+		return []string{style.Dim.Render("(none)")}
+	}
+	var lines []string
+	for _, p := range polecats {
+		state := p.State
+		if p.AgentState != "" && p.AgentState != p.State {
+			state += "/" + p.AgentState
+		}
+		if p.AgentState == "stuck" {
+			state = style.Warning.Render(state)
+		}
+		line := fmt.Sprintf("%s %s: %s", sessionIcon(p.SessionRunning), p.Name, state)
+		if p.Issue != "" {
+			line += " → " + p.Issue
+		}
+		lines = append(lines, line)
+		if p.WorkSummary != "" {
+			lines = append(lines, "    "+style.Dim.Render(truncateWithEllipsis(p.WorkSummary, rigStatusSummaryLen)))
+		}
+	}
+	return lines
+}
+
+func crewLines(members []DashboardCrew) []string {
+	if len(members) == 0 {
+		return []string{style.Dim.Render("(none)")}
+	}
+	var lines []string
+	for _, c := range members {
+		line := fmt.Sprintf("%s %s: %s", sessionIcon(c.SessionRunning), c.Name, c.Branch)
+		if c.Dirty {
+			line += style.Warning.Render(" (dirty)")
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func escalationLines(escalations []witness.PatrolAction) []string {
+	if len(escalations) == 0 {
+		return []string{style.Dim.Render("(none)")}
+	}
+	var lines []string
+	for _, e := range escalations {
+		line := fmt.Sprintf("%s %s", style.Dim.Render(e.At.Local().Format("15:04")), e.Target)
+		if e.Reason != "" {
+			line += ": " + truncateWithEllipsis(e.Reason, rigStatusSummaryLen)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func beadLines(counts map[string]int) []string {
+	if len(counts) == 0 {
+		return []string{style.Dim.Render("(none)")}
+	}
+	total := 0
+	var parts []string
+	for _, s := range sortedBeadStatuses(counts) {
+		total += counts[s]
+		parts = append(parts, fmt.Sprintf("%s %d", s, counts[s]))
+	}
+	return []string{fmt.Sprintf("%d total: %s", total, strings.Join(parts, ", "))}
+}
+
+// writeBoxes draws boxes one under another, all as wide as the widest.
+func writeBoxes(w io.Writer, boxes []dashboardBox) {
+	// Inner width: content plus a space either side.
+	width := 0
+	for _, b := range boxes {
+		width = max(width, lipgloss.Width(b.title)+4)
+		for _, line := range b.lines {
+			width = max(width, lipgloss.Width(line)+2)
+		}
+	}
+
+	for _, b := range boxes {
+		title := "─ " + style.Bold.Render(b.title) + " "
+		fmt.Fprintf(w, "┌%s%s┐\n", title, strings.Repeat("─", width-lipgloss.Width(title)))
+		for _, line := range b.lines {
+			fmt.Fprintf(w, "│ %s%s │\n", line, strings.Repeat(" ", width-2-lipgloss.Width(line)))
+		}
+		fmt.Fprintf(w, "└%s┘\n", strings.Repeat("─", width))
+	}
+}
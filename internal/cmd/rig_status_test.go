@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/witness"
+)
+
+func sampleRigDashboard() *RigDashboard {
+	started := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	completed := started.Add(30 * time.Second)
+	d := newRigDashboard("gongshow")
+	d.State, d.StateSource = "OPERATIONAL", "default"
+	d.Path = "/town/gongshow"
+	d.Prefix = "gs"
+	d.Witness = DashboardWitness{
+		Running:   true,
+		StartedAt: &started,
+		Patrols:   3,
+		LastPatrol: &witness.PatrolRecord{
+			Rig:          "gongshow",
+			StartedAt:    started,
+			CompletedAt:  &completed,
+			PolecatCount: 2,
+		},
+	}
+	d.Refinery = DashboardRefinery{Running: true, StartedAt: &started, QueueDepth: 4}
+	d.Polecats = append(d.Polecats,
+		DashboardPolecat{Name: "Toast", State: "working", AgentState: "working", Issue: "gs-42", WorkSummary: "Fixing the flaky merge test", SessionRunning: true},
+		DashboardPolecat{Name: "Nux", State: "done"},
+	)
+	d.Crew = append(d.Crew, DashboardCrew{Name: "max", Branch: "main", Dirty: true})
+	d.Escalations = append(d.Escalations, witness.PatrolAction{At: completed, Target: "gongshow/polecats/Nux", Reason: "stuck for 30m"})
+	d.Beads = map[string]int{"open": 5, "in_progress": 2, "blocked": 1}
+	return d
+}
+
+// decodeJSON round-trips v through JSON into a generic map.
+func decodeJSON(t *testing.T, v interface{}) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	return m
+}
+
+func requireKeys(t *testing.T, what string, m map[string]interface{}, keys ...string) {
+	t.Helper()
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			t.Errorf("%s has no %q: %v", what, k, m)
+		}
+	}
+}
+
+func TestRigDashboardJSON(t *testing.T) {
+	m := decodeJSON(t, sampleRigDashboard())
+	requireKeys(t, "dashboard", m,
+		"rig", "state", "state_source", "path", "prefix", "witness", "refinery",
+		"polecats", "crew", "escalations", "beads", "generated_at")
+
+	wit, ok := m["witness"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("witness = %T, want an object", m["witness"])
+	}
+	requireKeys(t, "witness", wit, "running", "started_at", "patrols", "last_patrol")
+	if patrol, ok := wit["last_patrol"].(map[string]interface{}); !ok {
+		t.Errorf("last_patrol = %T, want an object", wit["last_patrol"])
+	} else {
+		requireKeys(t, "last_patrol", patrol, "started_at", "completed_at", "polecat_count")
+	}
+
+	ref, ok := m["refinery"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("refinery = %T, want an object", m["refinery"])
+	}
+	if ref["queue_depth"] != float64(4) {
+		t.Errorf("refinery.queue_depth = %v, want 4", ref["queue_depth"])
+	}
+
+	polecats, ok := m["polecats"].([]interface{})
+	if !ok || len(polecats) != 2 {
+		t.Fatalf("polecats = %v, want 2 entries", m["polecats"])
+	}
+	toast := polecats[0].(map[string]interface{})
+	requireKeys(t, "polecat", toast, "name", "state", "agent_state", "issue", "work_summary", "session_running")
+	// Empty agent fields are left out.
+	nux := polecats[1].(map[string]interface{})
+	if _, ok := nux["agent_state"]; ok {
+		t.Errorf("polecat without an agent bead has agent_state: %v", nux)
+	}
+
+	escalations, ok := m["escalations"].([]interface{})
+	if !ok || len(escalations) != 1 {
+		t.Fatalf("escalations = %v, want 1 entry", m["escalations"])
+	}
+	requireKeys(t, "escalation", escalations[0].(map[string]interface{}), "at", "target", "reason")
+
+	beadCounts, ok := m["beads"].(map[string]interface{})
+	if !ok || beadCounts["open"] != float64(5) {
+		t.Errorf("beads = %v, want open: 5", m["beads"])
+	}
+}
+
+func TestRigDashboardJSON_Empty(t *testing.T) {
+	m := decodeJSON(t, newRigDashboard("gongshow"))
+	// Lists are [] and counts {}, never null, so scripts can range over them.
+	for _, k := range []string{"polecats", "crew", "escalations"} {
+		if list, ok := m[k].([]interface{}); !ok || len(list) != 0 {
+			t.Errorf("%s = %#v, want []", k, m[k])
+		}
+	}
+	if counts, ok := m["beads"].(map[string]interface{}); !ok || len(counts) != 0 {
+		t.Errorf("beads = %#v, want {}", m["beads"])
+	}
+	if _, ok := m["witness"].(map[string]interface{})["last_patrol"]; ok {
+		t.Error("witness.last_patrol is set with no patrols")
+	}
+}
+
+func TestRecentEscalations(t *testing.T) {
+	at := func(min int) time.Time { return time.Date(2026, 10, 16, 9, min, 0, 0, time.UTC) }
+	history := []witness.PatrolRecord{
+		{Escalations: []witness.PatrolAction{{At: at(1), Target: "a"}, {At: at(2), Target: "b"}}},
+		{},
+		{Escalations: []witness.PatrolAction{{At: at(5), Target: "c"}}},
+	}
+
+	got := recentEscalations(history, 2)
+	if len(got) != 2 || got[0].Target != "c" || got[1].Target != "b" {
+		t.Errorf("recentEscalations() = %+v, want c then b", got)
+	}
+	if got := recentEscalations(nil, 2); got == nil || len(got) != 0 {
+		t.Errorf("recentEscalations(nil) = %#v, want empty", got)
+	}
+}
+
+func TestCountPendingBeads(t *testing.T) {
+	issues := []*beads.Issue{
+		{ID: "gs-1", Status: "open"},
+		{ID: "gs-2", Status: "open"},
+		{ID: "gs-3", Status: "in_progress"},
+		{ID: "gs-4", Status: "closed"},
+		{ID: "gs-5", Status: "review"},
+		{ID: "gs-6", Status: "blocked"},
+		{ID: "gs-gongshow-polecat-Toast", Status: "open", Labels: []string{"gt:agent"}},
+	}
+	counts := countPendingBeads(issues)
+	want := map[string]int{"open": 2, "in_progress": 1, "review": 1, "blocked": 1}
+	if len(counts) != len(want) {
+		t.Errorf("countPendingBeads() = %v, want %v", counts, want)
+	}
+	for s, n := range want {
+		if counts[s] != n {
+			t.Errorf("countPendingBeads()[%s] = %d, want %d", s, counts[s], n)
+		}
+	}
+
+	order := strings.Join(sortedBeadStatuses(counts), ",")
+	if order != "open,in_progress,blocked,review" {
+		t.Errorf("sortedBeadStatuses() = %s, want open,in_progress,blocked,review", order)
+	}
+}
+
+func TestApplyPolecatAgentBeads(t *testing.T) {
+	toastID := beads.PolecatBeadIDWithPrefix("gs", "gongshow", "Toast")
+	nuxID := beads.PolecatBeadIDWithPrefix("gs", "gongshow", "Nux")
+	otherRigID := beads.PolecatBeadIDWithPrefix("gs", "other", "Slit")
+	agentBeads := map[string]*beads.Issue{
+		toastID: {
+			ID:     toastID,
+			Status: "open",
+			Description: beads.FormatAgentDescription("Toast", &beads.AgentFields{
+				RoleType:    "polecat",
+				Rig:         "gongshow",
+				AgentState:  "working",
+				WorkSummary: "Fixing the flaky merge test",
+			}),
+		},
+		// The slot wins over the description.
+		nuxID: {
+			ID:          nuxID,
+			Status:      "open",
+			AgentState:  "stuck",
+			Description: beads.FormatAgentDescription("Nux", &beads.AgentFields{AgentState: "working"}),
+		},
+		otherRigID: {
+			ID:          otherRigID,
+			Status:      "open",
+			Description: beads.FormatAgentDescription("Slit", &beads.AgentFields{AgentState: "working"}),
+		},
+	}
+	polecats := []DashboardPolecat{{Name: "Toast"}, {Name: "Nux"}, {Name: "Slit"}}
+
+	applyPolecatAgentBeads(polecats, "gongshow", agentBeads)
+
+	if polecats[0].AgentState != "working" || polecats[0].WorkSummary != "Fixing the flaky merge test" {
+		t.Errorf("Toast = %+v, want working with its summary", polecats[0])
+	}
+	if polecats[1].AgentState != "stuck" {
+		t.Errorf("Nux.AgentState = %q, want stuck", polecats[1].AgentState)
+	}
+	if polecats[2].AgentState != "" {
+		t.Errorf("Slit got another rig's agent bead: %+v", polecats[2])
+	}
+}
+
+func TestRenderRigDashboard(t *testing.T) {
+	var buf bytes.Buffer
+	renderRigDashboard(&buf, sampleRigDashboard())
+	out := buf.String()
+
+	for _, want := range []string{"Witness", "Refinery", "Polecats (2)", "Crew (1)", "Escalations (24h)", "Pending beads",
+		"Queue: 4 items", "Toast", "working", "Fixing the flaky merge test", "stuck for 30m", "8 total: open 5, in_progress 2, blocked 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dashboard has no %q:\n%s", want, out)
+		}
+	}
+
+	// Every line of every box is the same width.
+	width := 0
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "┌") && !strings.HasPrefix(line, "│") && !strings.HasPrefix(line, "└") {
+			continue
+		}
+		w := lipgloss.Width(line)
+		if width == 0 {
+			width = w
+		}
+		if w != width {
+			t.Errorf("box line is %d wide, want %d: %q", w, width, line)
+		}
+	}
+	if width == 0 {
+		t.Errorf("dashboard has no boxes:\n%s", out)
+	}
+}
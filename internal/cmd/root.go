@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/KeithWyatt/gongshow/internal/events"
 	"github.com/KeithWyatt/gongshow/internal/style"
 	"github.com/KeithWyatt/gongshow/internal/version"
 	"github.com/KeithWyatt/gongshow/internal/workspace"
@@ -27,9 +28,11 @@ across distributed teams of AI agents working on shared codebases.`,
 // Commands that don't require beads to be installed/checked.
 // These are basic utility commands that should work without beads.
 var beadsExemptCommands = map[string]bool{
-	"version":    true,
-	"help":       true,
-	"completion": true,
+	"version":        true,
+	"help":           true,
+	"completion":     true,
+	"prompt-segment": true, // Runs on every shell prompt; must stay instant
+	"detect":         true, // gt rig detect runs on every shell prompt (also gt role detect)
 }
 
 // Commands exempt from the town root branch warning.
@@ -41,6 +44,9 @@ var branchCheckExemptCommands = map[string]bool{
 	"doctor":     true, // Used to fix the problem
 	"install":    true, // Initial setup
 	"git-init":   true, // Git setup
+
+	"prompt-segment": true, // Runs on every shell prompt; must stay instant
+	"detect":         true, // gt rig detect runs on every shell prompt (also gt role detect)
 }
 
 // persistentPreRun runs before every command.
@@ -48,6 +54,15 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
+	// Thread one correlation ID through the events this command causes,
+	// continuing the chain of whatever started this process (e.g. the sling
+	// that spawned this polecat).
+	correlationID := events.CorrelationFromEnv()
+	if correlationID == "" {
+		correlationID = events.NewCorrelationID()
+	}
+	cmd.SetContext(events.WithCorrelation(cmd.Context(), correlationID))
+
 	// Check town root branch (warning only, non-blocking)
 	if !branchCheckExemptCommands[cmdName] {
 		warnIfTownRootOffMain()
@@ -57,6 +72,10 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	if beadsExemptCommands[cmdName] {
 		return nil
 	}
+	// gt install --shell --repair only touches the shell integration.
+	if cmdName == "install" && installRepair {
+		return nil
+	}
 
 	// Check beads version
 	return CheckBeadsVersion()
@@ -168,6 +187,9 @@ func shouldDefaultJSON() bool {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
+	// Let event hooks started by this command finish before exiting
+	defer events.WaitForHooks()
+
 	if err := rootCmd.Execute(); err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -234,9 +233,7 @@ func runSeanceTalk(sessionID, prompt string) error {
 
 // discoverSessions reads session_start events from our event stream.
 func discoverSessions(townRoot string) ([]sessionEvent, error) {
-	eventsPath := filepath.Join(townRoot, events.EventsFile)
-
-	file, err := os.Open(eventsPath)
+	file, err := events.OpenLog(townRoot)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
@@ -12,6 +12,12 @@ import (
 	"github.com/KeithWyatt/gongshow/internal/style"
 )
 
+var (
+	shellInstallShell   string
+	shellInstallRCFile  string
+	shellInstallOutside bool
+)
+
 var shellCmd = &cobra.Command{
 	Use:     "shell",
 	GroupID: GroupConfig,
@@ -28,6 +34,14 @@ This adds a hook to your shell RC file that:
   - Sets GT_TOWN_ROOT and GT_RIG when you cd into a GongShow rig
   - Offers to add new git repos to GongShow on first visit
 
+The hook goes in ~/.zshrc ($ZDOTDIR/.zshrc if set), ~/.bashrc,
+~/.config/fish/conf.d/gongshow.fish, or nushell's env.nu ($nu.env-path).
+The shell is taken from $SHELL; use --shell to pick one (zsh, bash, fish
+or nu). Use --rc-file for a different file;
+it is remembered, so later installs and 'gt shell remove' use it too.
+Files outside your home directory are only written with
+--allow-outside-home.
+
 Run this after upgrading gt to get the latest shell hook features.`,
 	RunE: runShellInstall,
 }
@@ -45,6 +59,10 @@ var shellStatusCmd = &cobra.Command{
 }
 
 func init() {
+	shellInstallCmd.Flags().StringVar(&shellInstallShell, "shell", "", "Shell to install for: zsh, bash, fish or nu (default: detected)")
+	shellInstallCmd.Flags().StringVar(&shellInstallRCFile, "rc-file", "", "RC file to add the hook to (default: the shell's RC file)")
+	shellInstallCmd.Flags().BoolVar(&shellInstallOutside, "allow-outside-home", false, "Allow an RC file or hook script outside your home directory")
+
 	shellCmd.AddCommand(shellInstallCmd)
 	shellCmd.AddCommand(shellRemoveCmd)
 	shellCmd.AddCommand(shellStatusCmd)
@@ -52,7 +70,8 @@ func init() {
 }
 
 func runShellInstall(cmd *cobra.Command, args []string) error {
-	if err := shell.Install(); err != nil {
+	opts := shell.InstallOptions{Shell: shellInstallShell, RCFile: shellInstallRCFile, AllowOutsideHome: shellInstallOutside}
+	if err := shell.InstallWithOptions(opts); err != nil {
 		return err
 	}
 
@@ -60,9 +79,10 @@ func runShellInstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Could not enable GongShow: %v\n", style.Dim.Render("⚠"), err)
 	}
 
-	fmt.Printf("%s Shell integration installed (%s)\n", style.Success.Render("✓"), shell.RCFilePath(shell.DetectShell()))
+	rcPath := shell.InstalledRCFile(shell.InstalledShell())
+	fmt.Printf("%s Shell integration installed (%s)\n", style.Success.Render("✓"), rcPath)
 	fmt.Println()
-	fmt.Println("Run 'source ~/.zshrc' or open a new terminal to activate.")
+	fmt.Printf("Run 'source %s' or open a new terminal to activate.\n", rcPath)
 	return nil
 }
 
@@ -90,10 +110,11 @@ func runShellStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	if s.ShellIntegration != "" {
-		fmt.Printf("Shell integration: %s (%s)\n", s.ShellIntegration, shell.RCFilePath(s.ShellIntegration))
+		fmt.Printf("Shell integration: %s (%s)\n", s.ShellIntegration, shell.InstalledRCFile(s.ShellIntegration))
 	} else {
 		fmt.Println("Shell integration: not installed")
 	}
+	fmt.Printf("Shell mode: %s\n", s.Mode())
 
 	return nil
 }
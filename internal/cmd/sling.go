@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -121,6 +122,12 @@ func runSling(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("polecats cannot sling (use gt done for handoff)")
 	}
 
+	// Events from this sling and the polecats it spawns share a correlation ID.
+	ctx := context.Background()
+	if cmd != nil {
+		ctx = cmd.Context()
+	}
+
 	// Get town root early - needed for BEADS_DIR when running bd commands
 	// This ensures hq-* beads are accessible even when running from polecat worktree
 	townRoot, err := workspace.FindFromCwd()
@@ -140,7 +147,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 	if len(args) > 2 {
 		lastArg := args[len(args)-1]
 		if rigName, isRig := IsRigName(lastArg); isRig {
-			return runBatchSling(args[:len(args)-1], rigName, townBeadsDir)
+			return runBatchSling(ctx, args[:len(args)-1], rigName, townBeadsDir)
 		}
 	}
 
@@ -171,7 +178,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 			// Not a verified bead - try as standalone formula
 			if err := verifyFormulaExists(firstArg); err == nil {
 				// Standalone formula mode: gt sling <formula> [target]
-				return runSlingFormula(args)
+				return runSlingFormula(ctx, args)
 			}
 			// Not a formula either - check if it looks like a bead ID (routing issue workaround).
 			// Accept it and let the actual bd update fail later if the bead doesn't exist.
@@ -238,7 +245,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 					HookBead: beadID, // Set atomically at spawn time
 					Agent:    slingAgent,
 				}
-				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
+				spawnInfo, spawnErr := SpawnPolecatForSling(ctx, rigName, spawnOpts)
 				if spawnErr != nil {
 					return fmt.Errorf("spawning polecat: %w", spawnErr)
 				}
@@ -269,7 +276,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 							HookBead: beadID,
 							Agent:    slingAgent,
 						}
-						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
+						spawnInfo, spawnErr := SpawnPolecatForSling(ctx, rigName, spawnOpts)
 						if spawnErr != nil {
 							return fmt.Errorf("spawning polecat to replace dead polecat: %w", spawnErr)
 						}
@@ -451,7 +458,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 
 	// Log sling event to activity feed
 	actor := detectActor()
-	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	_ = events.LogFeedContext(ctx, events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	updateAgentHookBead(targetAgent, beadID, hookWorkDir, townBeadsDir)
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,7 +14,7 @@ import (
 
 // runBatchSling handles slinging multiple beads to a rig.
 // Each bead gets its own freshly spawned polecat.
-func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error {
+func runBatchSling(ctx context.Context, beadIDs []string, rigName string, townBeadsDir string) error {
 	// Validate all beads exist before spawning any polecats
 	for _, beadID := range beadIDs {
 		if err := verifyBeadExists(beadID); err != nil {
@@ -66,7 +67,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			HookBead: beadID, // Set atomically at spawn time
 			Agent:    slingAgent,
 		}
-		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
+		spawnInfo, err := SpawnPolecatForSling(ctx, rigName, spawnOpts)
 		if err != nil {
 			results = append(results, slingResult{beadID: beadID, success: false, errMsg: err.Error()})
 			fmt.Printf("  %s Failed to spawn polecat: %v\n", style.Dim.Render("✗"), err)
@@ -106,7 +107,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 		// Log sling event
 		actor := detectActor()
-		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+		_ = events.LogFeedContext(ctx, events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadID, hookWorkDir, townBeadsDir)
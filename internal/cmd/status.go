@@ -84,6 +84,7 @@ type AgentRuntime struct {
 	WorkTitle    string `json:"work_title,omitempty"`    // Title of pinned work
 	HookBead     string `json:"hook_bead,omitempty"`     // Pinned bead ID from agent bead
 	State        string `json:"state,omitempty"`         // Agent state from agent bead
+	WorkSummary  string `json:"work_summary,omitempty"`  // What the agent says it is doing (gt agent set-summary)
 	UnreadMail   int    `json:"unread_mail"`             // Number of unread messages
 	FirstSubject string `json:"first_subject,omitempty"` // Subject of first unread message
 }
@@ -646,7 +647,7 @@ func renderAgentDetails(agent AgentRuntime, indent string, hooks []AgentHookInfo
 		}
 	}
 
-	fmt.Printf("%s%s %s%s\n", indent, style.Dim.Render(agentBeadID), statusStr, stateInfo)
+	fmt.Printf("%s%s %s%s%s\n", indent, style.Dim.Render(agentBeadID), statusStr, stateInfo, workSummarySuffix(agent, 60))
 
 	// Line 2: Hook bead (pinned work)
 	hookStr := style.Dim.Render("(none)")
@@ -774,8 +775,8 @@ func renderAgentCompactWithSuffix(agent AgentRuntime, indent string, hooks []Age
 		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
 	}
 
-	// Print single line: name + status + hook + mail + suffix
-	fmt.Printf("%s%-12s %s%s%s%s\n", indent, agent.Name, statusIndicator, hookSuffix, mailSuffix, suffix)
+	// Print single line: name + status + summary + hook + mail + suffix
+	fmt.Printf("%s%-12s %s%s%s%s%s\n", indent, agent.Name, statusIndicator, workSummarySuffix(agent, 40), hookSuffix, mailSuffix, suffix)
 }
 
 // renderAgentCompact renders a single-line agent status
@@ -814,8 +815,17 @@ func renderAgentCompact(agent AgentRuntime, indent string, hooks []AgentHookInfo
 		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
 	}
 
-	// Print single line: name + status + hook + mail
-	fmt.Printf("%s%-12s %s%s%s\n", indent, agent.Name, statusIndicator, hookSuffix, mailSuffix)
+	// Print single line: name + status + summary + hook + mail
+	fmt.Printf("%s%-12s %s%s%s%s\n", indent, agent.Name, statusIndicator, workSummarySuffix(agent, 40), hookSuffix, mailSuffix)
+}
+
+// workSummarySuffix renders an agent's work summary, quoted and cut to
+// maxLen, to follow its name and status; "" if it has none.
+func workSummarySuffix(agent AgentRuntime, maxLen int) string {
+	if agent.WorkSummary == "" {
+		return ""
+	}
+	return " " + style.Dim.Render(fmt.Sprintf("%q", truncateWithEllipsis(agent.WorkSummary, maxLen)))
 }
 
 // buildStatusIndicator creates the visual status indicator for an agent.
@@ -973,12 +983,12 @@ func discoverGlobalAgents(allSessions map[string]bool, allAgentBeads map[string]
 						agent.WorkTitle = pinnedIssue.Title
 					}
 				}
-				// Fallback to description for legacy beads without SQLite columns
+				// The work summary is only in the description; so is the
+				// state of legacy beads without SQLite columns.
+				fields := beads.ParseAgentFields(issue.Description)
+				agent.WorkSummary = fields.WorkSummary
 				if agent.State == "" {
-					fields := beads.ParseAgentFields(issue.Description)
-					if fields != nil {
-						agent.State = fields.AgentState
-					}
+					agent.State = fields.AgentState
 				}
 			}
 
@@ -1113,12 +1123,12 @@ func discoverRigAgents(allSessions map[string]bool, r *rig.Rig, crews []string,
 						agent.WorkTitle = pinnedIssue.Title
 					}
 				}
-				// Fallback to description for legacy beads without SQLite columns
+				// The work summary is only in the description; so is the
+				// state of legacy beads without SQLite columns.
+				fields := beads.ParseAgentFields(issue.Description)
+				agent.WorkSummary = fields.WorkSummary
 				if agent.State == "" {
-					fields := beads.ParseAgentFields(issue.Description)
-					if fields != nil {
-						agent.State = fields.AgentState
-					}
+					agent.State = fields.AgentState
 				}
 			}
 
@@ -29,7 +29,7 @@ var uninstallCmd = &cobra.Command{
 	Long: `Completely remove GongShow from the system.
 
 By default, removes:
-  - Shell integration (~/.zshrc or ~/.bashrc)
+  - Shell integration (~/.zshrc, ~/.bashrc or ~/.config/fish/conf.d/gongshow.fish)
   - Wrapper scripts (~/bin/gt-codex, ~/bin/gt-opencode)
   - State directory (~/.local/state/gongshow/)
   - Config directory (~/.config/gongshow/)
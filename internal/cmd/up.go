@@ -1,16 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/boot"
 	"github.com/KeithWyatt/gongshow/internal/config"
 	"github.com/KeithWyatt/gongshow/internal/crew"
 	"github.com/KeithWyatt/gongshow/internal/daemon"
@@ -20,22 +22,13 @@ import (
 	"github.com/KeithWyatt/gongshow/internal/polecat"
 	"github.com/KeithWyatt/gongshow/internal/refinery"
 	"github.com/KeithWyatt/gongshow/internal/rig"
+	"github.com/KeithWyatt/gongshow/internal/session"
 	"github.com/KeithWyatt/gongshow/internal/style"
 	"github.com/KeithWyatt/gongshow/internal/tmux"
 	"github.com/KeithWyatt/gongshow/internal/witness"
 	"github.com/KeithWyatt/gongshow/internal/workspace"
 )
 
-// agentStartResult holds the result of starting an agent.
-type agentStartResult struct {
-	name   string // Display name like "Witness (gongshow)"
-	ok     bool   // Whether start succeeded
-	detail string // Status detail (session name or error)
-}
-
-// maxConcurrentAgentStarts limits parallel agent startups to avoid resource exhaustion.
-const maxConcurrentAgentStarts = 10
-
 var upCmd = &cobra.Command{
 	Use:     "up",
 	GroupID: GroupServices,
@@ -58,19 +51,106 @@ Use --restore to also start:
   • Crew       - Per rig settings (settings/config.json crew.startup)
   • Polecats   - Those with pinned beads (work attached)
 
+Agents start in dependency order: the daemon, then the Deacon and Mayor,
+then each rig's Witness and Refinery, then crew and polecats. An agent
+waits until the ones it depends on are running (a rig's polecats and
+Refinery wait for its Witness); if one of those fails, so does the
+agent, but unrelated agents still start. The results are recorded in
+the Boot status file as each agent comes up.
+
+Agents that can start together do, at most --max-concurrent at a time
+(default 4); each is reported as soon as it is up or has failed. Use
+--serial to start them one at a time, in order, when debugging startup.
+
+Use --profile to start part of the town: a boot profile in the town
+settings (settings/config.json "boot_profiles") names the rigs and roles
+it starts, e.g. {"dev": {"rigs": ["gongshow"], "roles": ["mayor",
+"witness", "crew"]}}. The built-in "full" profile starts everything,
+crew and polecats included. --rig and --only pick rigs and roles ad hoc,
+replacing the profile's. The Boot status records what was selected.
+
+An agent has --agent-timeout to start and come up, and the whole boot
+--boot-timeout; agents not started by then are reported as not
+attempted. The session of an agent that times out is captured into the
+Boot status ('gt boot status' shows it) and left running for inspection;
+--rollback-failed kills it instead.
+
+Agents that fail, often for a passing reason such as the bd daemon still
+warming up, are retried --retries times (default 2) once the others
+are done: after --retry-backoff (default 10s), doubled for each retry.
+Before each retry the bd daemon's health is checked and zombie sessions
+of the failed agents are killed. Retries keep the dependency order, so
+a polecat isn't retried before its Witness is up. Agents still failing
+after the last retry are escalated with a bead summarizing them.
+
+Executable scripts in config/boot.d/pre and config/boot.d/post of the
+town are boot hooks, run in lexical order with GT_TOWN_ROOT and
+GT_BOOT_PROFILE set; post hooks get the boot's result as JSON on
+stdin, also when the boot fails before starting anything. A pre hook
+that fails aborts the boot before anything is started, beads migrations
+included; a post hook that fails only warns. Each hook has
+--hook-timeout (default 1m), and the Boot status records how they went.
+--skip-hooks boots without them.
+
+Each boot is recorded in logs/boot-history.jsonl: its profile, wall
+time, retries, failures and how long each agent took to come up. 'gt
+boot history' shows it.
+
 Running 'gt up' multiple times is safe - it only starts services that
-aren't already running.`,
+aren't already running.
+
+Use --plan to see what 'gt up' would do without starting anything: each
+agent it would start, with its session, directory and command, the ones
+it would skip because they are already running, and conflicts that
+would stop an agent starting (a missing worktree, an unreadable role
+config) or change how it starts (a zombie session, a missing role
+config). Add --json for tooling. 'gt up' carries out the same plan.`,
 	RunE: runUp,
 }
 
 var (
 	upQuiet   bool
 	upRestore bool
+	upPlan    bool
+	upJSON    bool
+
+	upSerial         bool
+	upMaxConcurrent  int
+	upAgentTimeout   time.Duration
+	upBootTimeout    time.Duration
+	upRollbackFailed bool
+	upRetries        int
+	upRetryBackoff   time.Duration
+
+	upProfile string
+	upRigs    []string
+	upOnly    []string
+
+	upSkipHooks   bool
+	upHookTimeout time.Duration
 )
 
+// upPaneCaptureLines is how much of a timed out agent's session gt up
+// keeps for diagnosis.
+const upPaneCaptureLines = 30
+
 func init() {
 	upCmd.Flags().BoolVarP(&upQuiet, "quiet", "q", false, "Only show errors")
 	upCmd.Flags().BoolVar(&upRestore, "restore", false, "Also restore crew (from settings) and polecats (from hooks)")
+	upCmd.Flags().BoolVar(&upPlan, "plan", false, "Show what would be started, without starting anything")
+	upCmd.Flags().BoolVar(&upJSON, "json", false, "With --plan, output the plan as JSON")
+	upCmd.Flags().BoolVar(&upSerial, "serial", false, "Start agents one at a time, in order (for debugging)")
+	upCmd.Flags().IntVar(&upMaxConcurrent, "max-concurrent", boot.DefaultMaxConcurrent, "Maximum agents to start at once")
+	upCmd.Flags().DurationVar(&upAgentTimeout, "agent-timeout", boot.DefaultReadyTimeout, "Time each agent has to start and come up")
+	upCmd.Flags().DurationVar(&upBootTimeout, "boot-timeout", boot.DefaultBootTimeout, "Time the whole boot has; later agents aren't started")
+	upCmd.Flags().StringVar(&upProfile, "profile", "", "Start the rigs and roles of this boot profile")
+	upCmd.Flags().StringSliceVar(&upRigs, "rig", nil, "Start only this rig's agents (repeatable; replaces the profile's rigs)")
+	upCmd.Flags().StringSliceVar(&upOnly, "only", nil, "Start only agents of this role (repeatable; replaces the profile's roles)")
+	upCmd.Flags().BoolVar(&upRollbackFailed, "rollback-failed", false, "Kill the sessions of agents that time out instead of leaving them for inspection")
+	upCmd.Flags().IntVar(&upRetries, "retries", 2, "Times to retry agents that fail to start")
+	upCmd.Flags().DurationVar(&upRetryBackoff, "retry-backoff", boot.DefaultRetryBackoff, "Wait before the first retry, doubled for each one after")
+	upCmd.Flags().BoolVar(&upSkipHooks, "skip-hooks", false, "Don't run the boot hooks in config/boot.d (for emergencies)")
+	upCmd.Flags().DurationVar(&upHookTimeout, "hook-timeout", boot.DefaultHookTimeout, "Time each boot hook has to finish")
 	rootCmd.AddCommand(upCmd)
 }
 
@@ -80,151 +160,498 @@ func runUp(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a GongShow workspace: %w", err)
 	}
 
-	allOK := true
+	if upJSON && !upPlan {
+		return fmt.Errorf("--json requires --plan")
+	}
+	if upMaxConcurrent < 1 {
+		return fmt.Errorf("--max-concurrent must be at least 1")
+	}
+	if upAgentTimeout <= 0 || upBootTimeout <= 0 {
+		return fmt.Errorf("--agent-timeout and --boot-timeout must be positive")
+	}
+	if upRetries < 0 || upRetryBackoff <= 0 {
+		return fmt.Errorf("--retries can't be negative and --retry-backoff must be positive")
+	}
+	if upHookTimeout <= 0 {
+		return fmt.Errorf("--hook-timeout must be positive")
+	}
 
-	// Discover rigs early so we can prefetch while daemon/deacon/mayor start
-	rigs := discoverRigs(townRoot)
+	townRigs := discoverRigs(townRoot)
+	sel, err := resolveUpSelection(townRoot, townRigs, upProfile, upRigs, upOnly)
+	if err != nil {
+		return err
+	}
+	rigs := upSelectedRigs(sel, townRigs)
+	prefetchedRigs, rigErrors := prefetchRigs(rigs)
 
-	// Start daemon, deacon, mayor, and rig prefetch in parallel
-	var daemonErr error
-	var daemonPID int
-	var deaconResult, mayorResult agentStartResult
-	var prefetchedRigs map[string]*rig.Rig
-	var rigErrors map[string]error
+	// A selection naming crew or polecats starts them as --restore does.
+	restore := upRestore || sel.NamesRole("crew") || sel.NamesRole("polecat")
 
-	var startupWg sync.WaitGroup
-	startupWg.Add(4)
+	t := tmux.NewTmux()
+	planner := newUpPlanner(townRoot, prefetchedRigs, rigErrors, t)
+	plan := planner.plan(sel.Filter(upStartPlan(townRoot, rigs, prefetchedRigs, restore)))
+	if upPlan {
+		return printUpPlan(os.Stdout, plan, upJSON)
+	}
 
-	// 1. Daemon (Go process)
-	go func() {
-		defer startupWg.Done()
-		if err := ensureDaemon(townRoot); err != nil {
-			daemonErr = err
-		} else {
-			running, pid, _ := daemon.IsRunning(townRoot)
-			if running {
-				daemonPID = pid
-			}
+	if last, err := boot.New(townRoot).LoadStatus(); err == nil && last.Selection.Partial() && !upQuiet {
+		fmt.Printf("%s\n", style.Dim.Render("Last boot started only "+last.Selection.String()))
+	}
+	if sel.Partial() && !upQuiet {
+		fmt.Printf("Starting %s\n", sel)
+	}
+
+	// Sequence callbacks are never concurrent, so these need no lock.
+	progress := newUpProgress(townRoot, sel, plan.Agents())
+	hooks := &boot.HookRunner{TownRoot: townRoot, Profile: sel.Profile, Timeout: upHookTimeout}
+	if !upSkipHooks {
+		if err := runUpPreHooks(hooks, progress); err != nil {
+			return err
 		}
-	}()
+	}
 
-	// 2. Deacon
-	go func() {
-		defer startupWg.Done()
-		deaconMgr := deacon.NewManager(townRoot)
-		if err := deaconMgr.Start(""); err != nil {
-			if err == deacon.ErrAlreadyRunning {
-				deaconResult = agentStartResult{name: "Deacon", ok: true, detail: deaconMgr.SessionName()}
-			} else {
-				deaconResult = agentStartResult{name: "Deacon", ok: false, detail: err.Error()}
+	// After the pre hooks, which may stop the boot or prepare the beads.
+	migrateUpBeads(townRoot, rigs)
+	var startedServices []string
+	seq := &boot.Sequence{
+		Starter:        &upStarter{townRoot: townRoot, rigs: prefetchedRigs, tmux: t},
+		MaxConcurrent:  upMaxConcurrent,
+		Serial:         upSerial,
+		ReadyTimeout:   upAgentTimeout,
+		Timeout:        upBootTimeout,
+		RollbackFailed: upRollbackFailed,
+		Retries:        upRetries,
+		RetryBackoff:   upRetryBackoff,
+		BeforeRetry: func(retry int, agents []boot.Agent) {
+			if !upQuiet {
+				fmt.Printf("\nRetrying %d agent(s) (retry %d of %d)\n", len(agents), retry, upRetries)
 			}
-		} else {
-			deaconResult = agentStartResult{name: "Deacon", ok: true, detail: deaconMgr.SessionName()}
+			if warning := beads.EnsureBdDaemonHealth(townRoot); warning != "" {
+				fmt.Printf("%s %s\n", style.WarningPrefix, warning)
+			}
+		},
+		OnChange: progress.update,
+		OnDone: func(a boot.Agent, st boot.AgentStatus) {
+			if st.Ready {
+				startedServices = append(startedServices, a.ID)
+			}
+			printAgentStatus(townRoot, a, st)
+		},
+	}
+	statuses, err := seq.RunPlan(plan)
+	if err != nil {
+		err = fmt.Errorf("ordering agent startup: %w", err)
+		progress.abort(err.Error())
+		if !upSkipHooks {
+			runUpPostHooks(hooks, progress, err, boot.Summary{}, nil)
 		}
-	}()
+		return err
+	}
+	progress.finish(statuses)
 
-	// 3. Mayor
-	go func() {
-		defer startupWg.Done()
-		mayorMgr := mayor.NewManager(townRoot)
-		if err := mayorMgr.Start(""); err != nil {
-			if err == mayor.ErrAlreadyRunning {
-				mayorResult = agentStartResult{name: "Mayor", ok: true, detail: mayorMgr.SessionName()}
+	summary := boot.Summarize(statuses)
+	if !upSkipHooks {
+		runUpPostHooks(hooks, progress, nil, summary, statuses)
+	}
+	// Best-effort: a history that can't be written doesn't fail the boot.
+	rec := boot.NewHistoryRecord(sel.Profile, progress.startedAt, time.Now(), statuses)
+	if err := boot.AppendHistory(townRoot, rec); err != nil {
+		fmt.Printf("%s Could not record the boot in its history: %v\n", style.WarningPrefix, err)
+	}
+	fmt.Println()
+	if summary.Ready == len(statuses) {
+		fmt.Printf("%s All services running\n", style.Bold.Render("✓"))
+		// Log boot event with started services
+		_ = events.LogFeed(events.TypeBoot, "gt", events.BootPayload("town", startedServices))
+	} else {
+		fmt.Printf("%s Some services failed to start: %s\n", style.Bold.Render("✗"), formatBootSummary(summary))
+		if summary.BootTimedOut {
+			fmt.Printf("  The boot timed out after %s; run 'gt up' again to start the rest\n", upBootTimeout)
+		}
+		if summary.TimedOut > 0 {
+			fmt.Printf("  See 'gt boot status' for what timed out agents showed\n")
+		}
+		if upRetries > 0 {
+			if id, existing, err := escalateUpFailures(townRoot, statuses); err != nil {
+				fmt.Printf("%s Could not escalate the failures: %v\n", style.WarningPrefix, err)
+			} else if existing {
+				fmt.Printf("  Already escalated as %s\n", id)
 			} else {
-				mayorResult = agentStartResult{name: "Mayor", ok: false, detail: err.Error()}
+				fmt.Printf("  Escalated as %s\n", id)
 			}
-		} else {
-			mayorResult = agentStartResult{name: "Mayor", ok: true, detail: mayorMgr.SessionName()}
 		}
-	}()
+		return fmt.Errorf("not all services started")
+	}
 
-	// 4. Prefetch rig configs (overlaps with daemon/deacon/mayor startup)
-	go func() {
-		defer startupWg.Done()
-		prefetchedRigs, rigErrors = prefetchRigs(rigs)
-	}()
+	return nil
+}
 
-	startupWg.Wait()
+// migrateUpBeads brings the town's and each rig's beads up to the current
+// schema before any agent starts using them. A failed migration is
+// reported but doesn't stop the boot; it is retried next time.
+func migrateUpBeads(townRoot string, rigs []string) {
+	dirs := []string{townRoot}
+	for _, rigName := range rigs {
+		dirs = append(dirs, filepath.Join(townRoot, rigName))
+	}
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		beadsDir := beads.ResolveBeadsDir(dir)
+		if seen[beadsDir] {
+			continue // Rigs redirecting to the same beads
+		}
+		seen[beadsDir] = true
+		if _, err := os.Stat(beadsDir); err != nil {
+			continue
+		}
+		n, err := beads.MigrateBeadsDir(dir, beadsDir)
+		if err != nil {
+			fmt.Printf("%s Could not migrate %s: %v\n", style.WarningPrefix, beadsDir, err)
+		} else if n > 0 && !upQuiet {
+			fmt.Printf("Applied %d beads migration(s) to %s\n", n, beadsDir)
+		}
+	}
+}
 
-	// Print daemon/deacon/mayor results
-	if daemonErr != nil {
-		printStatus("Daemon", false, daemonErr.Error())
-		allOK = false
-	} else if daemonPID > 0 {
-		printStatus("Daemon", true, fmt.Sprintf("PID %d", daemonPID))
+// upStartPlan lists the agents gt up starts, in the order they are
+// reported: daemon, deacon, mayor, each rig's witness then refinery, and
+// with restore, crew from rig settings and polecats with pinned work.
+// The witness and refinery of a rig whose config didn't load are listed
+// for upPlanner to report. boot.Sequence decides the order they actually
+// start in.
+func upStartPlan(townRoot string, rigs []string, prefetchedRigs map[string]*rig.Rig, restore bool) []boot.Agent {
+	plan := []boot.Agent{
+		boot.NewAgent("daemon", "", "", ""),
+		boot.NewAgent("deacon", "", "", session.DeaconSessionName()),
+		boot.NewAgent("mayor", "", "", session.MayorSessionName()),
 	}
-	printStatus(deaconResult.name, deaconResult.ok, deaconResult.detail)
-	if !deaconResult.ok {
-		allOK = false
+	for _, rigName := range rigs {
+		plan = append(plan, boot.NewAgent("witness", rigName, "", session.WitnessSessionName(rigName)))
 	}
-	printStatus(mayorResult.name, mayorResult.ok, mayorResult.detail)
-	if !mayorResult.ok {
-		allOK = false
+	for _, rigName := range rigs {
+		plan = append(plan, boot.NewAgent("refinery", rigName, "", session.RefinerySessionName(rigName)))
+	}
+	if !restore {
+		return plan
 	}
-
-	// 5 & 6. Witnesses and Refineries (using prefetched rigs)
-	witnessResults, refineryResults := startRigAgentsWithPrefetch(rigs, prefetchedRigs, rigErrors)
-
-	// Print results in order: all witnesses first, then all refineries
 	for _, rigName := range rigs {
-		if result, ok := witnessResults[rigName]; ok {
-			printStatus(result.name, result.ok, result.detail)
-			if !result.ok {
-				allOK = false
-			}
+		for _, name := range crewToStart(townRoot, rigName) {
+			plan = append(plan, boot.NewAgent("crew", rigName, name, session.CrewSessionName(rigName, name)))
 		}
 	}
 	for _, rigName := range rigs {
-		if result, ok := refineryResults[rigName]; ok {
-			printStatus(result.name, result.ok, result.detail)
-			if !result.ok {
-				allOK = false
-			}
+		if _, ok := prefetchedRigs[rigName]; !ok {
+			continue
+		}
+		for _, name := range polecatsWithWork(townRoot, rigName) {
+			plan = append(plan, boot.NewAgent("polecat", rigName, name, session.PolecatSessionName(rigName, name)))
 		}
 	}
+	return plan
+}
 
-	// 7. Crew (if --restore)
-	if upRestore {
-		for _, rigName := range rigs {
-			crewStarted, crewErrors := startCrewFromSettings(townRoot, rigName)
-			for _, name := range crewStarted {
-				printStatus(fmt.Sprintf("Crew (%s/%s)", rigName, name), true, fmt.Sprintf("gt-%s-crew-%s", rigName, name))
-			}
-			for name, err := range crewErrors {
-				printStatus(fmt.Sprintf("Crew (%s/%s)", rigName, name), false, err.Error())
-				allOK = false
-			}
+// upDisplayName is how gt up reports an agent.
+func upDisplayName(a boot.Agent) string {
+	switch a.Role {
+	case "daemon":
+		return "Daemon"
+	case "deacon":
+		return "Deacon"
+	case "mayor":
+		return "Mayor"
+	case "witness":
+		return "Witness (" + a.Rig + ")"
+	case "refinery":
+		return "Refinery (" + a.Rig + ")"
+	case "crew":
+		return fmt.Sprintf("Crew (%s/%s)", a.Rig, a.Name)
+	case "polecat":
+		return fmt.Sprintf("Polecat (%s/%s)", a.Rig, a.Name)
+	}
+	return a.ID
+}
+
+// upStarter starts gt up's agents for boot.Sequence. An agent that is
+// already running counts as started.
+type upStarter struct {
+	townRoot string
+	rigs     map[string]*rig.Rig
+	tmux     *tmux.Tmux
+}
+
+// Start starts the agent. The managers it calls can't be cancelled, so
+// ctx is only checked before starting.
+func (s *upStarter) Start(ctx context.Context, a boot.Agent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	switch a.Role {
+	case "daemon":
+		return ensureDaemon(s.townRoot)
+	case "deacon":
+		if err := deacon.NewManager(s.townRoot).Start(""); err != nil && err != deacon.ErrAlreadyRunning {
+			return err
+		}
+	case "mayor":
+		if err := mayor.NewManager(s.townRoot).Start(""); err != nil && err != mayor.ErrAlreadyRunning {
+			return err
+		}
+	case "witness":
+		if err := witness.NewManager(s.rigs[a.Rig]).Start(false, "", nil); err != nil && err != witness.ErrAlreadyRunning {
+			return err
+		}
+	case "refinery":
+		if err := refinery.NewManager(s.rigs[a.Rig]).Start(false, ""); err != nil && err != refinery.ErrAlreadyRunning {
+			return err
+		}
+	case "crew":
+		crewMgr, _, err := getCrewManager(a.Rig)
+		if err != nil {
+			return err
+		}
+		if err := crewMgr.Start(a.Name, crew.StartOptions{}); err != nil && err != crew.ErrSessionRunning {
+			return err
+		}
+	case "polecat":
+		polecatMgr := polecat.NewSessionManager(s.tmux, s.rigs[a.Rig])
+		if err := polecatMgr.Start(a.Name, polecat.SessionStartOptions{}); err != nil && err != polecat.ErrSessionRunning {
+			return err
 		}
+	default:
+		return fmt.Errorf("don't know how to start a %s", a.Role)
+	}
+	return nil
+}
 
-		// 7. Polecats with pinned work (if --restore)
-		for _, rigName := range rigs {
-			polecatsStarted, polecatErrors := startPolecatsWithWork(townRoot, rigName)
-			for _, name := range polecatsStarted {
-				printStatus(fmt.Sprintf("Polecat (%s/%s)", rigName, name), true, fmt.Sprintf("gt-%s-polecat-%s", rigName, name))
-			}
-			for name, err := range polecatErrors {
-				printStatus(fmt.Sprintf("Polecat (%s/%s)", rigName, name), false, err.Error())
-				allOK = false
-			}
+func (s *upStarter) Ready(a boot.Agent) bool {
+	if a.Role == "daemon" {
+		running, _, err := daemon.IsRunning(s.townRoot)
+		return err == nil && running
+	}
+	return boot.SessionReady(s.tmux, a.Session)
+}
+
+// Capture returns the end of the agent's session, for an agent that
+// didn't come up.
+func (s *upStarter) Capture(a boot.Agent) (string, error) {
+	if a.Session == "" {
+		return "", nil
+	}
+	return s.tmux.CapturePane(a.Session, upPaneCaptureLines)
+}
+
+// Refresh kills the agent's session if no agent is running in it, so a
+// retry starts it afresh.
+func (s *upStarter) Refresh(a boot.Agent) error {
+	if a.Session == "" {
+		return nil
+	}
+	has, err := s.tmux.HasSession(a.Session)
+	if err != nil || !has || s.tmux.IsAgentRunning(a.Session) {
+		return err
+	}
+	return s.tmux.KillSessionWithProcesses(a.Session)
+}
+
+// Stop kills the agent's session, for --rollback-failed.
+func (s *upStarter) Stop(a boot.Agent) error {
+	if a.Session == "" {
+		return fmt.Errorf("%s has no session", a.ID)
+	}
+	has, err := s.tmux.HasSession(a.Session)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return boot.ErrNotRunning
+	}
+	return s.tmux.KillSessionWithProcesses(a.Session)
+}
+
+// upProgress keeps a gt up's per-agent statuses, and the part of the town
+// it starts, in the boot status file as each agent's state changes, so
+// 'gt boot status --follow' can show the boot as it runs.
+type upProgress struct {
+	boot      *boot.Boot
+	sel       *boot.Selection
+	startedAt time.Time
+	statuses  []boot.AgentStatus
+	index     map[string]int
+	hooks     []boot.HookResult
+	running   bool
+}
+
+// newUpProgress records a startup of agents, all of them pending.
+func newUpProgress(townRoot string, sel *boot.Selection, agents []boot.Agent) *upProgress {
+	p := &upProgress{
+		boot:      boot.New(townRoot),
+		sel:       sel,
+		startedAt: time.Now(),
+		statuses:  make([]boot.AgentStatus, len(agents)),
+		index:     make(map[string]int, len(agents)),
+	}
+	for i, a := range agents {
+		p.statuses[i] = boot.AgentStatus{ID: a.ID, Phase: a.Phase.String(), State: boot.StatePending}
+		p.index[a.ID] = i
+	}
+	p.running = true
+	p.record()
+	return p
+}
+
+// update records an agent's new state.
+func (p *upProgress) update(a boot.Agent, st boot.AgentStatus) {
+	if i, ok := p.index[a.ID]; ok {
+		p.statuses[i] = st
+	}
+	p.record()
+}
+
+// finish records the final statuses, with each agent's history; nil
+// keeps the ones recorded so far.
+func (p *upProgress) finish(statuses []boot.AgentStatus) {
+	if statuses != nil {
+		p.statuses = statuses
+	}
+	p.running = false
+	p.record()
+}
+
+// abort records the startup as finished without starting anything: each
+// agent still pending failed, not attempted, for reason.
+func (p *upProgress) abort(reason string) {
+	now := time.Now()
+	for i := range p.statuses {
+		st := &p.statuses[i]
+		if st.State != boot.StatePending {
+			continue
 		}
+		st.State = boot.StateFailed
+		st.History = append(st.History, boot.Transition{State: boot.StateFailed, At: now})
+		st.Outcome = boot.OutcomeNotAttempted
+		st.Error = "not started: " + reason
 	}
+	p.finish(nil)
+}
 
-	fmt.Println()
-	if allOK {
-		fmt.Printf("%s All services running\n", style.Bold.Render("✓"))
-		// Log boot event with started services
-		startedServices := []string{"daemon", "deacon", "mayor"}
-		for _, rigName := range rigs {
-			startedServices = append(startedServices, fmt.Sprintf("%s/witness", rigName))
-			startedServices = append(startedServices, fmt.Sprintf("%s/refinery", rigName))
+// addHooks records boot hook runs.
+func (p *upProgress) addHooks(results []boot.HookResult) {
+	p.hooks = append(p.hooks, results...)
+	p.record()
+}
+
+// record saves the startup in the boot status file, keeping the rest of
+// Boot's status (best-effort).
+func (p *upProgress) record() {
+	_ = p.boot.UpdateStatus(func(status *boot.Status) {
+		status.Agents = p.statuses
+		status.Hooks = p.hooks
+		status.Summary = nil
+		status.StartupRunning = p.running
+		status.StartupStartedAt = p.startedAt
+		status.StartupCompletedAt = time.Time{}
+		status.StartupPID = os.Getpid()
+		status.StartupAbandoned = false
+		if !p.running {
+			summary := boot.Summarize(p.statuses)
+			status.Summary = &summary
+			status.StartupCompletedAt = time.Now()
+		}
+		status.Selection = nil
+		if p.sel.Partial() || p.sel.Profile != "" {
+			status.Selection = p.sel
+		}
+	})
+}
+
+// escalateUpFailures creates an escalation bead listing the agents that
+// still failed after gt up's retries, and returns its ID. If an earlier
+// gt up's escalation is still open, it returns that one's instead, with
+// existing set, so repeated failing boots don't pile them up.
+func escalateUpFailures(townRoot string, statuses []boot.AgentStatus) (id string, existing bool, err error) {
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	open, err := bd.ListEscalationsBySeverity(config.SeverityHigh)
+	if err != nil {
+		return "", false, fmt.Errorf("listing open escalations: %w", err)
+	}
+	for _, issue := range open {
+		if beads.ParseEscalationFields(issue.Description).Source == "gt:up" {
+			return issue.ID, true, nil
 		}
-		_ = events.LogFeed(events.TypeBoot, "gt", events.BootPayload("town", startedServices))
-	} else {
-		fmt.Printf("%s Some services failed to start\n", style.Bold.Render("✗"))
-		return fmt.Errorf("not all services started")
 	}
 
-	return nil
+	title, reason := upFailureEscalation(statuses)
+	issue, err := bd.CreateEscalationBead(title, &beads.EscalationFields{
+		Severity:    config.SeverityHigh,
+		Reason:      reason,
+		Source:      "gt:up",
+		EscalatedBy: "gt",
+		EscalatedAt: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return issue.ID, false, nil
+}
+
+// upFailureEscalation returns the title and reason of the escalation of
+// the agents in statuses that aren't ready.
+func upFailureEscalation(statuses []boot.AgentStatus) (title, reason string) {
+	var failures []string
+	retries := 0
+	for _, st := range statuses {
+		if st.Ready {
+			continue
+		}
+		failures = append(failures, fmt.Sprintf("%s (%s): %s", st.ID, st.Outcome, st.Error))
+		if st.Retry > retries {
+			retries = st.Retry
+		}
+	}
+	title = fmt.Sprintf("gt up: %d agent(s) failed to start", len(failures))
+	switch {
+	case retries == 1:
+		title += " after 1 retry"
+	case retries > 1:
+		title += fmt.Sprintf(" after %d retries", retries)
+	}
+	return title, strings.Join(failures, "; ")
+}
+
+// formatBootSummary describes a startup's outcome counts.
+func formatBootSummary(sum boot.Summary) string {
+	parts := []string{fmt.Sprintf("%d ready", sum.Ready)}
+	if sum.Retried > 0 {
+		parts[0] += fmt.Sprintf(" (%d on retry)", sum.Retried)
+	}
+	if sum.Failed > 0 {
+		failed := fmt.Sprintf("%d failed", sum.Failed)
+		if sum.TimedOut > 0 {
+			failed += fmt.Sprintf(" (%d timed out)", sum.TimedOut)
+		}
+		parts = append(parts, failed)
+	}
+	if sum.NotAttempted > 0 {
+		parts = append(parts, fmt.Sprintf("%d not attempted", sum.NotAttempted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printAgentStatus reports how an agent's start went.
+func printAgentStatus(townRoot string, a boot.Agent, st boot.AgentStatus) {
+	if !st.Ready {
+		printStatus(upDisplayName(a), false, st.Error)
+		return
+	}
+	detail := a.Session
+	if a.Role == "daemon" {
+		_, pid, _ := daemon.IsRunning(townRoot)
+		detail = fmt.Sprintf("PID %d", pid)
+	}
+	if st.Retry > 0 {
+		detail += fmt.Sprintf(" (on retry %d)", st.Retry)
+	}
+	printStatus(upDisplayName(a), true, detail)
 }
 
 func printStatus(name string, ok bool, detail string) {
@@ -322,140 +749,6 @@ func prefetchRigs(rigNames []string) (map[string]*rig.Rig, map[string]error) {
 	return rigs, errors
 }
 
-// agentTask represents a unit of work for the agent worker pool.
-type agentTask struct {
-	rigName   string
-	rigObj    *rig.Rig
-	isWitness bool // true for witness, false for refinery
-}
-
-// agentResultMsg carries result back from worker to collector.
-type agentResultMsg struct {
-	rigName   string
-	isWitness bool
-	result    agentStartResult
-}
-
-// startRigAgentsParallel starts all Witnesses and Refineries concurrently.
-// Discovers and prefetches rigs internally. For use when rigs aren't pre-loaded.
-func startRigAgentsParallel(rigNames []string) (witnessResults, refineryResults map[string]agentStartResult) {
-	prefetchedRigs, rigErrors := prefetchRigs(rigNames)
-	return startRigAgentsWithPrefetch(rigNames, prefetchedRigs, rigErrors)
-}
-
-// startRigAgentsWithPrefetch starts all Witnesses and Refineries using pre-loaded rig configs.
-// Uses a worker pool with fixed goroutine count to limit concurrency and reduce overhead.
-func startRigAgentsWithPrefetch(rigNames []string, prefetchedRigs map[string]*rig.Rig, rigErrors map[string]error) (witnessResults, refineryResults map[string]agentStartResult) {
-	n := len(rigNames)
-	witnessResults = make(map[string]agentStartResult, n)
-	refineryResults = make(map[string]agentStartResult, n)
-
-	if n == 0 {
-		return
-	}
-
-	// Record errors for rigs that failed to load
-	for rigName, err := range rigErrors {
-		errDetail := err.Error()
-		witnessResults[rigName] = agentStartResult{
-			name:   "Witness (" + rigName + ")",
-			ok:     false,
-			detail: errDetail,
-		}
-		refineryResults[rigName] = agentStartResult{
-			name:   "Refinery (" + rigName + ")",
-			ok:     false,
-			detail: errDetail,
-		}
-	}
-
-	numTasks := len(prefetchedRigs) * 2 // witness + refinery per rig
-	if numTasks == 0 {
-		return
-	}
-
-	// Task channel and result channel
-	tasks := make(chan agentTask, numTasks)
-	results := make(chan agentResultMsg, numTasks)
-
-	// Start fixed worker pool (bounded by maxConcurrentAgentStarts)
-	numWorkers := maxConcurrentAgentStarts
-	if numTasks < numWorkers {
-		numWorkers = numTasks
-	}
-
-	var wg sync.WaitGroup
-	for i := 0; i < numWorkers; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for task := range tasks {
-				var result agentStartResult
-				if task.isWitness {
-					result = upStartWitness(task.rigName, task.rigObj)
-				} else {
-					result = upStartRefinery(task.rigName, task.rigObj)
-				}
-				results <- agentResultMsg{
-					rigName:   task.rigName,
-					isWitness: task.isWitness,
-					result:    result,
-				}
-			}
-		}()
-	}
-
-	// Enqueue all tasks
-	for rigName, r := range prefetchedRigs {
-		tasks <- agentTask{rigName: rigName, rigObj: r, isWitness: true}
-		tasks <- agentTask{rigName: rigName, rigObj: r, isWitness: false}
-	}
-	close(tasks)
-
-	// Close results channel when workers are done
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect results - no locking needed, single goroutine collects
-	for msg := range results {
-		if msg.isWitness {
-			witnessResults[msg.rigName] = msg.result
-		} else {
-			refineryResults[msg.rigName] = msg.result
-		}
-	}
-
-	return
-}
-
-// upStartWitness starts a witness for the given rig and returns a result struct.
-func upStartWitness(rigName string, r *rig.Rig) agentStartResult {
-	name := "Witness (" + rigName + ")"
-	mgr := witness.NewManager(r)
-	if err := mgr.Start(false, "", nil); err != nil {
-		if err == witness.ErrAlreadyRunning {
-			return agentStartResult{name: name, ok: true, detail: mgr.SessionName()}
-		}
-		return agentStartResult{name: name, ok: false, detail: err.Error()}
-	}
-	return agentStartResult{name: name, ok: true, detail: mgr.SessionName()}
-}
-
-// upStartRefinery starts a refinery for the given rig and returns a result struct.
-func upStartRefinery(rigName string, r *rig.Rig) agentStartResult {
-	name := "Refinery (" + rigName + ")"
-	mgr := refinery.NewManager(r)
-	if err := mgr.Start(false, ""); err != nil {
-		if err == refinery.ErrAlreadyRunning {
-			return agentStartResult{name: name, ok: true, detail: mgr.SessionName()}
-		}
-		return agentStartResult{name: name, ok: false, detail: err.Error()}
-	}
-	return agentStartResult{name: name, ok: true, detail: mgr.SessionName()}
-}
-
 // discoverRigs finds all rigs in the town.
 func discoverRigs(townRoot string) []string {
 	var rigs []string
@@ -466,6 +759,7 @@ func discoverRigs(townRoot string) []string {
 		for name := range rigsConfig.Rigs {
 			rigs = append(rigs, name)
 		}
+		sort.Strings(rigs)
 		return rigs
 	}
 
@@ -506,12 +800,9 @@ func discoverRigs(townRoot string) []string {
 	return rigs
 }
 
-// startCrewFromSettings starts crew members based on rig settings.
-// Returns list of started crew names and map of errors.
-func startCrewFromSettings(townRoot, rigName string) ([]string, map[string]error) {
-	started := []string{}
-	errors := map[string]error{}
-
+// crewToStart returns the crew members a rig's settings (crew.startup)
+// ask to be started.
+func crewToStart(townRoot, rigName string) []string {
 	rigPath := filepath.Join(townRoot, rigName)
 
 	// Load rig settings
@@ -519,27 +810,23 @@ func startCrewFromSettings(townRoot, rigName string) ([]string, map[string]error
 	settings, err := config.LoadRigSettings(settingsPath)
 	if err != nil {
 		// No settings file or error - skip crew startup
-		return started, errors
+		return nil
 	}
 
 	if settings.Crew == nil || settings.Crew.Startup == "" {
 		// No crew startup preference
-		return started, errors
+		return nil
 	}
 
 	// Get available crew members using helper
 	crewMgr, _, err := getCrewManager(rigName)
 	if err != nil {
-		return started, errors
+		return nil
 	}
 
 	crewWorkers, err := crewMgr.List()
-	if err != nil {
-		return started, errors
-	}
-
-	if len(crewWorkers) == 0 {
-		return started, errors
+	if err != nil || len(crewWorkers) == 0 {
+		return nil
 	}
 
 	// Extract crew names
@@ -549,22 +836,7 @@ func startCrewFromSettings(townRoot, rigName string) ([]string, map[string]error
 	}
 
 	// Parse startup preference and determine which crew to start
-	toStart := parseCrewStartupPreference(settings.Crew.Startup, crewNames)
-
-	// Start each crew member using Manager
-	for _, crewName := range toStart {
-		if err := crewMgr.Start(crewName, crew.StartOptions{}); err != nil {
-			if err == crew.ErrSessionRunning {
-				started = append(started, crewName)
-			} else {
-				errors[crewName] = err
-			}
-		} else {
-			started = append(started, crewName)
-		}
-	}
-
-	return started, errors
+	return parseCrewStartupPreference(settings.Crew.Startup, crewNames)
 }
 
 // parseCrewStartupPreference parses the natural language crew startup preference.
@@ -630,30 +902,19 @@ func parseCrewStartupPreference(pref string, available []string) []string {
 	return result
 }
 
-// startPolecatsWithWork starts polecats that have pinned beads (work attached).
-// Returns list of started polecat names and map of errors.
-func startPolecatsWithWork(townRoot, rigName string) ([]string, map[string]error) {
-	started := []string{}
-	errors := map[string]error{}
-
-	rigPath := filepath.Join(townRoot, rigName)
-	polecatsDir := filepath.Join(rigPath, "polecats")
+// polecatsWithWork returns the polecats in a rig that have pinned beads
+// (work attached).
+func polecatsWithWork(townRoot, rigName string) []string {
+	polecatsDir := filepath.Join(townRoot, rigName, "polecats")
 
 	// List polecat directories
 	entries, err := os.ReadDir(polecatsDir)
 	if err != nil {
 		// No polecats directory
-		return started, errors
+		return nil
 	}
 
-	// Get polecat session manager
-	_, r, err := getRig(rigName)
-	if err != nil {
-		return started, errors
-	}
-	t := tmux.NewTmux()
-	polecatMgr := polecat.NewSessionManager(t, r)
-
+	var withWork []string
 	for _, entry := range entries {
 		if !entry.IsDir() {
 			continue
@@ -677,18 +938,8 @@ func startPolecatsWithWork(townRoot, rigName string) ([]string, map[string]error
 			// No pinned beads - skip
 			continue
 		}
-
-		// This polecat has work - start it using SessionManager
-		if err := polecatMgr.Start(polecatName, polecat.SessionStartOptions{}); err != nil {
-			if err == polecat.ErrSessionRunning {
-				started = append(started, polecatName)
-			} else {
-				errors[polecatName] = err
-			}
-		} else {
-			started = append(started, polecatName)
-		}
+		withWork = append(withWork, polecatName)
 	}
 
-	return started, errors
+	return withWork
 }
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/boot"
+	"github.com/KeithWyatt/gongshow/internal/style"
+)
+
+// upHookInput is the boot result post hooks get as JSON.
+type upHookInput struct {
+	TownRoot string             `json:"town_root"`
+	Profile  string             `json:"profile,omitempty"`
+	Success  bool               `json:"success"`
+	Error    string             `json:"error,omitempty"` // Why the boot couldn't run its agents
+	Summary  boot.Summary       `json:"summary"`
+	Agents   []boot.AgentStatus `json:"agents"`
+}
+
+// runUpPreHooks runs the pre-boot hooks and records them. A hook that
+// fails aborts the boot: its output is shown and the startup is recorded
+// as finished with nothing attempted.
+func runUpPreHooks(hooks *boot.HookRunner, progress *upProgress) error {
+	results, err := hooks.Run(boot.HookPre, nil)
+	if err != nil {
+		progress.abort("pre-boot hooks could not be run")
+		return fmt.Errorf("pre-boot hooks: %w", err)
+	}
+	progress.addHooks(results)
+
+	for _, res := range results {
+		if res.OK {
+			if !upQuiet {
+				fmt.Printf("%s pre-boot hook %s\n", style.SuccessPrefix, res.Name)
+			}
+			continue
+		}
+		progress.abort(fmt.Sprintf("boot aborted by pre-boot hook %s", res.Name))
+		fmt.Printf("%s pre-boot hook %s: %s\n", style.ErrorPrefix, res.Name, res.Error)
+		printHookOutput(res)
+		return fmt.Errorf("boot aborted by pre-boot hook %s (use --skip-hooks to boot anyway)", res.Name)
+	}
+	return nil
+}
+
+// runUpPostHooks runs the post-boot hooks with the boot's result and
+// records them: bootErr if the boot couldn't run its agents, otherwise
+// their statuses. A hook that fails only warns.
+func runUpPostHooks(hooks *boot.HookRunner, progress *upProgress, bootErr error, summary boot.Summary, statuses []boot.AgentStatus) {
+	in := upHookInput{
+		TownRoot: hooks.TownRoot,
+		Profile:  hooks.Profile,
+		Success:  bootErr == nil && summary.Ready == len(statuses),
+		Summary:  summary,
+		Agents:   statuses,
+	}
+	if bootErr != nil {
+		in.Error = bootErr.Error()
+	}
+	input, err := json.Marshal(in)
+	if err != nil {
+		fmt.Printf("%s post-boot hooks: %v\n", style.WarningPrefix, err)
+		return
+	}
+	results, err := hooks.Run(boot.HookPost, input)
+	if err != nil {
+		fmt.Printf("%s post-boot hooks: %v\n", style.WarningPrefix, err)
+		return
+	}
+	progress.addHooks(results)
+
+	for _, res := range results {
+		if res.OK {
+			if !upQuiet {
+				fmt.Printf("%s post-boot hook %s\n", style.SuccessPrefix, res.Name)
+			}
+			continue
+		}
+		fmt.Printf("%s post-boot hook %s: %s\n", style.WarningPrefix, res.Name, res.Error)
+		printHookOutput(res)
+	}
+}
+
+// printHookOutput shows what a failed hook wrote, indented.
+func printHookOutput(res boot.HookResult) {
+	out := strings.TrimRight(res.Output, "\n")
+	if out == "" {
+		return
+	}
+	for _, line := range strings.Split(out, "\n") {
+		fmt.Printf("    %s\n", style.Dim.Render(line))
+	}
+}
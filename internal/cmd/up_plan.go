@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/boot"
+	"github.com/KeithWyatt/gongshow/internal/crew"
+	"github.com/KeithWyatt/gongshow/internal/daemon"
+	"github.com/KeithWyatt/gongshow/internal/deacon"
+	"github.com/KeithWyatt/gongshow/internal/mayor"
+	"github.com/KeithWyatt/gongshow/internal/polecat"
+	"github.com/KeithWyatt/gongshow/internal/refinery"
+	"github.com/KeithWyatt/gongshow/internal/rig"
+	"github.com/KeithWyatt/gongshow/internal/style"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+	"github.com/KeithWyatt/gongshow/internal/witness"
+)
+
+// upPlanner works out what gt up does with each agent - start it, skip
+// it, or report a conflict - and how it starts it, without starting
+// anything.
+type upPlanner struct {
+	townRoot  string
+	rigs      map[string]*rig.Rig
+	rigErrors map[string]error
+
+	// daemonPID returns the running daemon's PID (0 if it isn't running),
+	// hasSession and agentRunning probe tmux (agentRunning as the managers
+	// tell a zombie session from a live one), and roleConfig loads a role's
+	// config (nil if it has none); all are replaced in tests.
+	daemonPID    func() int
+	hasSession   func(session string) bool
+	agentRunning func(session string) bool
+	roleConfig   func(role string) (*beads.RoleConfig, error)
+
+	roleConfigs map[string]roleConfigResult
+}
+
+type roleConfigResult struct {
+	cfg *beads.RoleConfig
+	err error
+}
+
+func newUpPlanner(townRoot string, rigs map[string]*rig.Rig, rigErrors map[string]error, t *tmux.Tmux) *upPlanner {
+	return &upPlanner{
+		townRoot:  townRoot,
+		rigs:      rigs,
+		rigErrors: rigErrors,
+		daemonPID: func() int {
+			running, pid, err := daemon.IsRunning(townRoot)
+			if err != nil || !running {
+				return 0
+			}
+			return pid
+		},
+		hasSession: func(s string) bool {
+			has, err := t.HasSession(s)
+			return err == nil && has
+		},
+		agentRunning: func(s string) bool {
+			return t.IsClaudeRunning(s)
+		},
+		roleConfig: func(role string) (*beads.RoleConfig, error) {
+			bd := beads.New(beads.GetTownBeadsPath(townRoot))
+			return bd.GetRoleConfig(beads.RoleBeadIDTown(role))
+		},
+	}
+}
+
+// plan returns the plan for starting agents.
+func (p *upPlanner) plan(agents []boot.Agent) *boot.Plan {
+	plan := &boot.Plan{TownRoot: p.townRoot}
+	for _, a := range agents {
+		plan.Steps = append(plan.Steps, p.step(a))
+	}
+	return plan
+}
+
+func (p *upPlanner) step(a boot.Agent) boot.Step {
+	s := boot.Step{Agent: a, Action: boot.ActionStart}
+
+	if a.Role == "daemon" {
+		if pid := p.daemonPID(); pid != 0 {
+			s.Action = boot.ActionSkip
+			s.Reason = fmt.Sprintf("already running (PID %d)", pid)
+			return s
+		}
+		s.Dir = p.townRoot
+		s.Command = "gt daemon run"
+		return s
+	}
+
+	if err, ok := p.rigErrors[a.Rig]; ok {
+		s.Block(fmt.Sprintf("loading rig %s: %v", a.Rig, err))
+		return s
+	}
+
+	if p.hasSession(a.Session) {
+		if p.agentRunning(a.Session) {
+			s.Action = boot.ActionSkip
+			s.Reason = "already running in " + a.Session
+			return s
+		}
+		if a.Role == "polecat" {
+			// Unlike the other managers, the polecat session manager
+			// doesn't replace a dead session, so the agent would never
+			// come up.
+			s.Block(fmt.Sprintf("session %s exists but no agent is running in it (zombie); kill it with 'tmux kill-session -t %s'", a.Session, a.Session))
+		} else {
+			s.Flag(fmt.Sprintf("session %s exists but no agent is running in it (zombie); it will be replaced", a.Session))
+		}
+	}
+
+	cfg, err := p.loadRoleConfig(a.Role)
+	switch {
+	case err != nil && a.Role == "witness":
+		// The witness is started with its role config, so can't start without it.
+		s.Block(fmt.Sprintf("loading witness role config: %v", err))
+	case err != nil:
+		s.Flag(fmt.Sprintf("loading %s role config: %v", a.Role, err))
+	case cfg == nil:
+		s.Flag(fmt.Sprintf("missing role config %s; using the configured agent", beads.RoleBeadIDTown(a.Role)))
+	}
+
+	rigPath := filepath.Join(p.townRoot, a.Rig)
+	r, ok := p.rigs[a.Rig]
+	if ok {
+		rigPath = r.Path
+	} else {
+		r = &rig.Rig{Name: a.Rig, Path: rigPath}
+	}
+
+	// Each agent's dir and command come from the builders its manager
+	// starts it with.
+	var command string
+	var buildErr error
+	switch a.Role {
+	case "deacon":
+		s.Dir = deacon.WorkDir(p.townRoot)
+		command, buildErr = deacon.BuildStartCommand(p.townRoot)
+	case "mayor":
+		s.Dir = mayor.WorkDir(p.townRoot)
+		command, buildErr = mayor.BuildStartCommand(p.townRoot)
+	case "witness":
+		s.Dir = witness.WorkDir(rigPath)
+		if err == nil {
+			command, buildErr = witness.BuildStartCommand(rigPath, a.Rig, p.townRoot, cfg)
+		}
+	case "refinery":
+		s.Dir = refinery.WorkDir(rigPath)
+		command, buildErr = refinery.BuildStartCommand(rigPath, a.Rig)
+		if !dirExists(s.Dir) {
+			s.Block("worktree missing: " + s.Dir)
+		}
+	case "crew":
+		s.Dir = crew.WorkDir(rigPath, a.Name)
+		command, buildErr = crew.BuildStartCommand(rigPath, a.Rig, a.Name)
+		if !dirExists(s.Dir) {
+			s.Flag("worktree missing: " + s.Dir + "; it will be cloned")
+		}
+	case "polecat":
+		s.Dir = polecat.NewSessionManager(nil, r).ClonePath(a.Name)
+		command = polecat.BuildStartCommand(rigPath, a.Rig, a.Name)
+		if !dirExists(s.Dir) {
+			s.Block("worktree missing: " + s.Dir)
+		}
+	default:
+		s.Block(fmt.Sprintf("don't know how to start a %s", a.Role))
+	}
+	if buildErr != nil {
+		s.Block(buildErr.Error())
+	}
+	s.Command = command
+	return s
+}
+
+// loadRoleConfig returns role's config, loading it once per plan.
+func (p *upPlanner) loadRoleConfig(role string) (*beads.RoleConfig, error) {
+	if p.roleConfigs == nil {
+		p.roleConfigs = make(map[string]roleConfigResult)
+	}
+	res, ok := p.roleConfigs[role]
+	if !ok {
+		res.cfg, res.err = p.roleConfig(role)
+		p.roleConfigs[role] = res
+	}
+	return res.cfg, res.err
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// printUpPlan prints plan as JSON or as a tree of phases and their steps.
+func printUpPlan(w io.Writer, plan *boot.Plan, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+
+	_, _ = fmt.Fprintf(w, "%s %s: %d to start, %d already running, %d conflict(s)\n",
+		style.Bold.Render("Boot plan for"), plan.TownRoot,
+		plan.Count(boot.ActionStart), plan.Count(boot.ActionSkip), plan.Count(boot.ActionConflict))
+
+	for phase := boot.PhaseInfrastructure; phase <= boot.PhaseWorkers; phase++ {
+		var steps []boot.Step
+		for _, s := range plan.Steps {
+			if s.Agent.Phase == phase {
+				steps = append(steps, s)
+			}
+		}
+		if len(steps) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "\n%s\n", style.Bold.Render(phase.String()))
+		for i, s := range steps {
+			branch, indent := "├── ", "│     "
+			if i == len(steps)-1 {
+				branch, indent = "└── ", "      "
+			}
+			printPlanStep(w, s, branch, indent)
+		}
+	}
+	return nil
+}
+
+func printPlanStep(w io.Writer, s boot.Step, branch, indent string) {
+	name := upDisplayName(s.Agent)
+	switch s.Action {
+	case boot.ActionSkip:
+		_, _ = fmt.Fprintf(w, "%s%s %s: %s\n", branch, style.Dim.Render("skip    "), name, s.Reason)
+		return
+	case boot.ActionConflict:
+		_, _ = fmt.Fprintf(w, "%s%s %s\n", branch, style.Error.Render("conflict"), name)
+	default:
+		_, _ = fmt.Fprintf(w, "%s%s %s\n", branch, style.Success.Render("start   "), name)
+	}
+
+	if s.Agent.Session != "" {
+		_, _ = fmt.Fprintf(w, "%ssession: %s\n", indent, s.Agent.Session)
+	}
+	if s.Dir != "" {
+		_, _ = fmt.Fprintf(w, "%sdir:     %s\n", indent, s.Dir)
+	}
+	if s.Command != "" {
+		// Startup prompts span lines; keep the command on one.
+		command := strings.ReplaceAll(s.Command, "\n", `\n`)
+		_, _ = fmt.Fprintf(w, "%scommand: %s\n", indent, style.Dim.Render(command))
+	}
+	for _, dep := range s.Agent.DependsOn {
+		_, _ = fmt.Fprintf(w, "%safter:   %s\n", indent, dep)
+	}
+	for _, c := range s.Conflicts {
+		prefix := style.WarningPrefix
+		if c.Blocking {
+			prefix = style.ErrorPrefix
+		}
+		_, _ = fmt.Fprintf(w, "%s%s %s\n", indent, prefix, c.Message)
+	}
+}
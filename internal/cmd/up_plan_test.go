@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/boot"
+	"github.com/KeithWyatt/gongshow/internal/polecat"
+	"github.com/KeithWyatt/gongshow/internal/refinery"
+	"github.com/KeithWyatt/gongshow/internal/rig"
+)
+
+// fixtureUpPlanner returns a planner for a town with three rigs: alpha,
+// with its worktrees in place and a polecat Toast; beta, which has no
+// refinery worktree and a polecat Nux without one; and broken, whose
+// config doesn't load. The daemon and deacon are running, the mayor's
+// session is a zombie, and alpha's witness is running.
+func fixtureUpPlanner(t *testing.T) (*upPlanner, []boot.Agent) {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, dir := range []string{
+		"deacon",
+		"alpha/witness/rig",
+		"alpha/refinery/rig",
+		"alpha/polecats/Toast/alpha",
+		"alpha/crew/max",
+		"beta/witness",
+		"beta/polecats/Nux",
+	} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rigs := map[string]*rig.Rig{
+		"alpha": {Name: "alpha", Path: filepath.Join(townRoot, "alpha")},
+		"beta":  {Name: "beta", Path: filepath.Join(townRoot, "beta")},
+	}
+	sessions := map[string]bool{ // session -> agent running
+		"hq-deacon":        true,
+		"hq-mayor":         false,
+		"gt-alpha-witness": true,
+		"gt-beta-Nux":      false,
+	}
+	p := &upPlanner{
+		townRoot:  townRoot,
+		rigs:      rigs,
+		rigErrors: map[string]error{"broken": errors.New("rig config not found")},
+		daemonPID: func() int { return 4242 },
+		hasSession: func(s string) bool {
+			_, ok := sessions[s]
+			return ok
+		},
+		agentRunning: func(s string) bool { return sessions[s] },
+		roleConfig: func(role string) (*beads.RoleConfig, error) {
+			switch role {
+			case "refinery":
+				return nil, nil
+			case "crew":
+				return nil, errors.New("bd not installed")
+			}
+			return &beads.RoleConfig{}, nil
+		},
+	}
+
+	agents := upStartPlan(townRoot, []string{"alpha", "beta", "broken"}, rigs, false)
+	agents = append(agents,
+		boot.NewAgent("crew", "alpha", "max", "gt-alpha-crew-max"),
+		boot.NewAgent("polecat", "alpha", "Toast", "gt-alpha-Toast"),
+		boot.NewAgent("polecat", "beta", "Nux", "gt-beta-Nux"),
+	)
+	return p, agents
+}
+
+func TestUpPlanClassification(t *testing.T) {
+	p, agents := fixtureUpPlanner(t)
+	plan := p.plan(agents)
+
+	want := map[string]struct {
+		action    boot.Action
+		conflicts int
+	}{
+		"daemon":               {boot.ActionSkip, 0},
+		"deacon":               {boot.ActionSkip, 0},
+		"mayor":                {boot.ActionStart, 1}, // zombie, replaced
+		"alpha/witness":        {boot.ActionSkip, 0},
+		"beta/witness":         {boot.ActionStart, 0},
+		"broken/witness":       {boot.ActionConflict, 1}, // rig didn't load
+		"alpha/refinery":       {boot.ActionStart, 1},    // missing role config
+		"beta/refinery":        {boot.ActionConflict, 2}, // missing role config and worktree
+		"broken/refinery":      {boot.ActionConflict, 1},
+		"alpha/crew/max":       {boot.ActionStart, 1}, // unreadable role config
+		"alpha/polecats/Toast": {boot.ActionStart, 0},
+		"beta/polecats/Nux":    {boot.ActionConflict, 2}, // zombie and no worktree
+	}
+	if len(plan.Steps) != len(want) {
+		t.Fatalf("plan has %d steps, want %d", len(plan.Steps), len(want))
+	}
+	for _, s := range plan.Steps {
+		w, ok := want[s.Agent.ID]
+		if !ok {
+			t.Errorf("unexpected step %s", s.Agent.ID)
+			continue
+		}
+		if s.Action != w.action || len(s.Conflicts) != w.conflicts {
+			t.Errorf("%s = %s %+v, want %s with %d conflict(s)", s.Agent.ID, s.Action, s.Conflicts, w.action, w.conflicts)
+		}
+	}
+
+	byID := make(map[string]boot.Step)
+	for _, s := range plan.Steps {
+		byID[s.Agent.ID] = s
+	}
+	if s := byID["daemon"]; s.Reason != "already running (PID 4242)" {
+		t.Errorf("daemon reason = %q", s.Reason)
+	}
+	if s := byID["mayor"]; s.Dir != p.townRoot || !strings.Contains(s.Conflicts[0].Message, "zombie") || s.Conflicts[0].Blocking {
+		t.Errorf("mayor = %+v", s)
+	}
+	if s := byID["beta/witness"]; s.Dir != filepath.Join(p.townRoot, "beta", "witness") || s.Command == "" {
+		t.Errorf("beta/witness = %+v, want witness/ and a command", s)
+	}
+	if s := byID["alpha/polecats/Toast"]; s.Dir != filepath.Join(p.townRoot, "alpha", "polecats", "Toast", "alpha") {
+		t.Errorf("Toast dir = %s", s.Dir)
+	}
+	// The plan shows the commands the managers start the agents with.
+	alphaPath := filepath.Join(p.townRoot, "alpha")
+	if want, _ := refinery.BuildStartCommand(alphaPath, "alpha"); byID["alpha/refinery"].Command != want {
+		t.Errorf("alpha/refinery command = %q, want the refinery manager's %q", byID["alpha/refinery"].Command, want)
+	}
+	if want := polecat.BuildStartCommand(alphaPath, "alpha", "Toast"); byID["alpha/polecats/Toast"].Command != want {
+		t.Errorf("Toast command = %q, want the polecat manager's %q", byID["alpha/polecats/Toast"].Command, want)
+	}
+	if s := byID["beta/refinery"]; s.Conflicts[0].Blocking || !s.Conflicts[1].Blocking ||
+		s.Conflicts[1].Message != "worktree missing: "+filepath.Join(p.townRoot, "beta", "mayor", "rig") {
+		t.Errorf("beta/refinery conflicts = %+v, want a missing role config and a blocking missing worktree", s.Conflicts)
+	}
+
+	// The plan runs as it was made: nothing skipped or conflicting starts.
+	starter := &recordingStarter{}
+	if _, err := (&boot.Sequence{Starter: starter}).RunPlan(plan); err != nil {
+		t.Fatalf("RunPlan() error = %v", err)
+	}
+	started := strings.Join(starter.started, ",")
+	for _, id := range []string{"mayor", "beta/witness", "alpha/refinery", "alpha/crew/max", "alpha/polecats/Toast"} {
+		if !strings.Contains(started, id) {
+			t.Errorf("started %s, want %s started", started, id)
+		}
+	}
+	for _, id := range []string{"daemon", "deacon", "alpha/witness", "beta/refinery", "broken/witness", "beta/polecats/Nux"} {
+		if strings.Contains(started, id) {
+			t.Errorf("started %s, want %s not started", started, id)
+		}
+	}
+}
+
+func TestPrintUpPlan(t *testing.T) {
+	p, agents := fixtureUpPlanner(t)
+	plan := p.plan(agents)
+
+	var out bytes.Buffer
+	if err := printUpPlan(&out, plan, false); err != nil {
+		t.Fatalf("printUpPlan() error = %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"5 to start, 3 already running, 4 conflict(s)",
+		"infrastructure\n└── ",
+		"Daemon: already running (PID 4242)",
+		"Refinery (beta)",
+		"after:   beta/witness",
+		"dir:     " + filepath.Join(p.townRoot, "alpha", "refinery", "rig"),
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("plan output missing %q:\n%s", want, text)
+		}
+	}
+
+	out.Reset()
+	if err := printUpPlan(&out, plan, true); err != nil {
+		t.Fatalf("printUpPlan(json) error = %v", err)
+	}
+	var raw struct {
+		Steps []struct {
+			ID     string `json:"id"`
+			Action string `json:"action"`
+		} `json:"steps"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &raw); err != nil {
+		t.Fatalf("plan JSON doesn't parse: %v\n%s", err, out.String())
+	}
+	if len(raw.Steps) != len(plan.Steps) || raw.Steps[0].ID != "daemon" || raw.Steps[0].Action != "skip" {
+		t.Errorf("plan JSON steps = %+v", raw.Steps)
+	}
+}
+
+// recordingStarter records what it starts and reports everything ready.
+type recordingStarter struct {
+	mu      sync.Mutex
+	started []string
+}
+
+func (s *recordingStarter) Start(_ context.Context, a boot.Agent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = append(s.started, a.ID)
+	return nil
+}
+
+func (s *recordingStarter) Ready(boot.Agent) bool { return true }
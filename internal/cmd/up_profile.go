@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/boot"
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// resolveUpSelection works out the part of the town --profile, --rig and
+// --only select: the profile's rigs and roles, with --rig replacing its
+// rigs and --only its roles. Rigs must be among townRigs.
+func resolveUpSelection(townRoot string, townRigs []string, profile string, rigs, only []string) (*boot.Selection, error) {
+	sel := &boot.Selection{Profile: profile}
+	if profile != "" {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading town settings: %w", err)
+		}
+		p, err := settings.LookupBootProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		sel.Rigs, sel.Roles = p.Rigs, p.Roles
+	}
+
+	if len(rigs) > 0 {
+		sel.Rigs = rigs
+	}
+	if len(only) > 0 {
+		if err := config.ValidateBootRoles(only); err != nil {
+			return nil, fmt.Errorf("--only: %w", err)
+		}
+		sel.Roles = only
+	}
+
+	known := make(map[string]bool, len(townRigs))
+	for _, r := range townRigs {
+		known[r] = true
+	}
+	for _, r := range sel.Rigs {
+		if !known[r] {
+			return nil, fmt.Errorf("unknown rig %q (rigs: %s)", r, strings.Join(townRigs, ", "))
+		}
+	}
+	return sel, nil
+}
+
+// upSelectedRigs returns the rigs of townRigs in sel.
+func upSelectedRigs(sel *boot.Selection, townRigs []string) []string {
+	var rigs []string
+	for _, r := range townRigs {
+		if sel.HasRig(r) {
+			rigs = append(rigs, r)
+		}
+	}
+	return rigs
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/boot"
+	"github.com/KeithWyatt/gongshow/internal/rig"
+)
+
+// profileTown is a town whose settings define a "dev" boot profile.
+func profileTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	settings := `{"type": "town-settings", "version": 1, "boot_profiles": {
+		"dev": {"rigs": ["gongshow"], "roles": ["mayor", "witness", "crew"]},
+		"rigs-only": {"rigs": ["beads"]}
+	}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestResolveUpSelection(t *testing.T) {
+	townRoot := profileTown(t)
+	townRigs := []string{"beads", "gongshow", "wyvern"}
+
+	tests := []struct {
+		name      string
+		profile   string
+		rigs      []string
+		only      []string
+		wantRigs  string
+		wantRoles string
+		wantErr   string
+	}{
+		{name: "no selection"},
+		{name: "profile", profile: "dev", wantRigs: "gongshow", wantRoles: "mayor,witness,crew"},
+		{name: "--rig replaces the profile's rigs", profile: "dev", rigs: []string{"beads", "wyvern"},
+			wantRigs: "beads,wyvern", wantRoles: "mayor,witness,crew"},
+		{name: "--only replaces the profile's roles", profile: "dev", only: []string{"refinery"},
+			wantRigs: "gongshow", wantRoles: "refinery"},
+		{name: "ad hoc", rigs: []string{"beads"}, only: []string{"witness"}, wantRigs: "beads", wantRoles: "witness"},
+		{name: "full", profile: "full", wantRoles: "daemon,deacon,mayor,witness,refinery,crew,polecat"},
+		{name: "unknown profile", profile: "laptop", wantErr: "available: dev, full, rigs-only"},
+		{name: "unknown rig", rigs: []string{"nope"}, wantErr: `unknown rig "nope" (rigs: beads, gongshow, wyvern)`},
+		{name: "unknown role", only: []string{"janitor"}, wantErr: `unknown role "janitor"`},
+	}
+	for _, tt := range tests {
+		sel, err := resolveUpSelection(townRoot, townRigs, tt.profile, tt.rigs, tt.only)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: error = %v", tt.name, err)
+			continue
+		}
+		if got := strings.Join(sel.Rigs, ","); got != tt.wantRigs {
+			t.Errorf("%s: rigs = %s, want %s", tt.name, got, tt.wantRigs)
+		}
+		if got := strings.Join(sel.Roles, ","); got != tt.wantRoles {
+			t.Errorf("%s: roles = %s, want %s", tt.name, got, tt.wantRoles)
+		}
+	}
+}
+
+func TestUpSelectionFiltersPlan(t *testing.T) {
+	townRoot := profileTown(t)
+	townRigs := []string{"beads", "gongshow"}
+	prefetched := map[string]*rig.Rig{"beads": {Name: "beads"}, "gongshow": {Name: "gongshow"}}
+
+	planIDs := func(profile string, rigs, only []string) string {
+		t.Helper()
+		sel, err := resolveUpSelection(townRoot, townRigs, profile, rigs, only)
+		if err != nil {
+			t.Fatalf("resolveUpSelection() error = %v", err)
+		}
+		agents := sel.Filter(upStartPlan(townRoot, upSelectedRigs(sel, townRigs), prefetched, false))
+		var ids []string
+		for _, a := range agents {
+			ids = append(ids, a.ID)
+		}
+		return strings.Join(ids, " ")
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		rigs    []string
+		only    []string
+		want    string
+	}{
+		{"whole town", "", nil, nil,
+			"daemon deacon mayor beads/witness gongshow/witness beads/refinery gongshow/refinery"},
+		{"rig level", "rigs-only", nil, nil, "daemon deacon mayor beads/witness beads/refinery"},
+		{"role level", "", nil, []string{"witness", "refinery"},
+			"beads/witness gongshow/witness beads/refinery gongshow/refinery"},
+		{"rig and role", "dev", nil, nil, "mayor gongshow/witness"},
+		{"override composes with profile", "dev", []string{"beads"}, nil, "mayor beads/witness"},
+	}
+	for _, tt := range tests {
+		if got := planIDs(tt.profile, tt.rigs, tt.only); got != tt.want {
+			t.Errorf("%s: plan = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpProgressSelection(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "deacon", "dogs", "boot"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	statuses := []boot.AgentStatus{{ID: "mayor", Ready: true, Outcome: boot.OutcomeReady}}
+
+	dev := &boot.Selection{Profile: "dev", Rigs: []string{"gongshow"}, Roles: []string{"mayor"}}
+	newUpProgress(townRoot, dev, nil).finish(statuses)
+	status, err := boot.New(townRoot).LoadStatus()
+	if err != nil {
+		t.Fatalf("LoadStatus() error = %v", err)
+	}
+	if status.Selection == nil || status.Selection.Profile != "dev" || !status.Selection.Partial() {
+		t.Errorf("recorded selection = %+v, want the dev profile", status.Selection)
+	}
+
+	// A later whole-town boot replaces it.
+	newUpProgress(townRoot, &boot.Selection{}, nil).finish(statuses)
+	if status, _ = boot.New(townRoot).LoadStatus(); status.Selection != nil {
+		t.Errorf("recorded selection = %+v, want none for the whole town", status.Selection)
+	}
+}
@@ -1,40 +1,31 @@
 package cmd
 
 import (
-	"fmt"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/KeithWyatt/gongshow/internal/boot"
 	"github.com/KeithWyatt/gongshow/internal/rig"
 )
 
-func TestAgentStartResult_Fields(t *testing.T) {
-	result := agentStartResult{
-		name:   "Witness (gongshow)",
-		ok:     true,
-		detail: "gt-gongshow-witness",
+func TestUpMaxConcurrentFlag(t *testing.T) {
+	flag := upCmd.Flags().Lookup("max-concurrent")
+	if flag == nil {
+		t.Fatal("gt up has no --max-concurrent flag")
 	}
-
-	if result.name != "Witness (gongshow)" {
-		t.Errorf("name = %q, want %q", result.name, "Witness (gongshow)")
-	}
-	if !result.ok {
-		t.Error("ok should be true")
+	if flag.DefValue != "4" {
+		t.Errorf("--max-concurrent default = %s, want 4", flag.DefValue)
 	}
-	if result.detail != "gt-gongshow-witness" {
-		t.Errorf("detail = %q, want %q", result.detail, "gt-gongshow-witness")
-	}
-}
-
-func TestMaxConcurrentAgentStarts_Constant(t *testing.T) {
-	// Verify the constant is set to a reasonable value
-	if maxConcurrentAgentStarts < 1 {
-		t.Errorf("maxConcurrentAgentStarts = %d, should be >= 1", maxConcurrentAgentStarts)
-	}
-	if maxConcurrentAgentStarts > 100 {
-		t.Errorf("maxConcurrentAgentStarts = %d, should be <= 100 to prevent resource exhaustion", maxConcurrentAgentStarts)
+	if upCmd.Flags().Lookup("serial") == nil {
+		t.Error("gt up has no --serial flag")
 	}
 }
 
@@ -81,65 +72,6 @@ func TestSemaphoreLimitsConcurrency(t *testing.T) {
 	}
 }
 
-func TestStartRigAgentsParallel_EmptyRigs(t *testing.T) {
-	// Test with empty rig list - should return empty maps without error
-	witnessResults, refineryResults := startRigAgentsParallel([]string{})
-
-	if len(witnessResults) != 0 {
-		t.Errorf("witnessResults should be empty, got %d entries", len(witnessResults))
-	}
-	if len(refineryResults) != 0 {
-		t.Errorf("refineryResults should be empty, got %d entries", len(refineryResults))
-	}
-}
-
-func TestStartRigAgentsWithPrefetch_EmptyRigs(t *testing.T) {
-	// Test with empty inputs
-	witnessResults, refineryResults := startRigAgentsWithPrefetch(
-		[]string{},
-		make(map[string]*rig.Rig),
-		make(map[string]error),
-	)
-
-	if len(witnessResults) != 0 {
-		t.Errorf("witnessResults should be empty, got %d entries", len(witnessResults))
-	}
-	if len(refineryResults) != 0 {
-		t.Errorf("refineryResults should be empty, got %d entries", len(refineryResults))
-	}
-}
-
-func TestStartRigAgentsWithPrefetch_RecordsErrors(t *testing.T) {
-	// Test that rig errors are properly recorded
-	rigErrors := map[string]error{
-		"badrig": fmt.Errorf("rig not found"),
-	}
-
-	witnessResults, refineryResults := startRigAgentsWithPrefetch(
-		[]string{"badrig"},
-		make(map[string]*rig.Rig),
-		rigErrors,
-	)
-
-	if len(witnessResults) != 1 {
-		t.Errorf("witnessResults should have 1 entry, got %d", len(witnessResults))
-	}
-	if result, ok := witnessResults["badrig"]; !ok {
-		t.Error("witnessResults should have badrig entry")
-	} else if result.ok {
-		t.Error("badrig witness result should not be ok")
-	}
-
-	if len(refineryResults) != 1 {
-		t.Errorf("refineryResults should have 1 entry, got %d", len(refineryResults))
-	}
-	if result, ok := refineryResults["badrig"]; !ok {
-		t.Error("refineryResults should have badrig entry")
-	} else if result.ok {
-		t.Error("badrig refinery result should not be ok")
-	}
-}
-
 func TestPrefetchRigs_Empty(t *testing.T) {
 	// Test with empty rig list
 	rigs, errors := prefetchRigs([]string{})
@@ -214,3 +146,128 @@ func TestWorkerPoolLimitsConcurrency(t *testing.T) {
 		t.Errorf("max concurrent = %d, should not exceed %d workers", maxObserved, numWorkers)
 	}
 }
+
+func TestUpStartPlan(t *testing.T) {
+	townRoot := t.TempDir()
+	prefetched := map[string]*rig.Rig{"alpha": {Name: "alpha"}, "beta": {Name: "beta"}}
+
+	plan := upStartPlan(townRoot, []string{"alpha", "broken", "beta"}, prefetched, false)
+
+	var ids []string
+	for _, a := range plan {
+		ids = append(ids, a.ID)
+	}
+	// The broken rig's agents are listed for upPlanner to report.
+	want := []string{"daemon", "deacon", "mayor", "alpha/witness", "broken/witness", "beta/witness", "alpha/refinery", "broken/refinery", "beta/refinery"}
+	if len(ids) != len(want) {
+		t.Fatalf("plan = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("plan = %v, want %v", ids, want)
+		}
+	}
+
+	// The plan must be schedulable as built.
+	if _, err := (&boot.Sequence{Starter: readyStarter{}}).Run(plan); err != nil {
+		t.Errorf("Sequence.Run(plan) error = %v", err)
+	}
+	if plan[3].Session != "gt-alpha-witness" || plan[6].DependsOn[0] != "alpha/witness" {
+		t.Errorf("alpha agents = %+v, %+v", plan[3], plan[6])
+	}
+}
+
+// readyStarter starts nothing and reports every agent ready.
+type readyStarter struct{}
+
+func (readyStarter) Start(context.Context, boot.Agent) error { return nil }
+func (readyStarter) Ready(boot.Agent) bool                   { return true }
+
+func TestUpFailureEscalation(t *testing.T) {
+	statuses := []boot.AgentStatus{
+		{ID: "mayor", Ready: true, Outcome: boot.OutcomeReady},
+		{ID: "gongshow/witness", Ready: true, Outcome: boot.OutcomeReady, Retry: 1},
+		{ID: "beads/witness", Outcome: boot.OutcomeTimedOut, Error: "not ready after 1m0s", Retry: 2},
+		{ID: "beads/refinery", Outcome: boot.OutcomeFailed, Error: "dependency beads/witness not ready", Retry: 2},
+	}
+
+	title, reason := upFailureEscalation(statuses)
+	if want := "gt up: 2 agent(s) failed to start after 2 retries"; title != want {
+		t.Errorf("title = %q, want %q", title, want)
+	}
+	for _, want := range []string{
+		"beads/witness (timed_out): not ready after 1m0s",
+		"beads/refinery (failed): dependency beads/witness not ready",
+	} {
+		if !strings.Contains(reason, want) {
+			t.Errorf("reason %q lacks %q", reason, want)
+		}
+	}
+	if strings.Contains(reason, "gongshow/witness") {
+		t.Errorf("reason %q lists an agent that came up on retry", reason)
+	}
+
+	if got, want := formatBootSummary(boot.Summarize(statuses)), "2 ready (1 on retry), 2 failed (1 timed out)"; got != want {
+		t.Errorf("formatBootSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestUpPreHookAbort(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := boot.HooksDir(townRoot, boot.HookPre)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "10-vpn"), []byte("#!/bin/sh\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	agents := []boot.Agent{boot.NewAgent("daemon", "", "", ""), boot.NewAgent("mayor", "", "", "")}
+	progress := newUpProgress(townRoot, &boot.Selection{}, agents)
+	hooks := &boot.HookRunner{TownRoot: townRoot, Timeout: 10 * time.Second}
+	if err := runUpPreHooks(hooks, progress); err == nil {
+		t.Fatal("runUpPreHooks() with a failing hook succeeded, want the boot aborted")
+	}
+
+	status, err := boot.New(townRoot).LoadStatus()
+	if err != nil {
+		t.Fatalf("LoadStatus() error = %v", err)
+	}
+	if status.StartupRunning || status.Summary == nil || status.Summary.NotAttempted != len(agents) {
+		t.Errorf("recorded startup = running %v, summary %+v; want it finished with nothing attempted",
+			status.StartupRunning, status.Summary)
+	}
+	for _, st := range status.Agents {
+		if st.State != boot.StateFailed || !strings.Contains(st.Error, "pre-boot hook 10-vpn") {
+			t.Errorf("%s = %s (%s), want failed, not started because of the hook", st.ID, st.State, st.Error)
+		}
+	}
+}
+
+func TestUpPostHooksOnFailedBoot(t *testing.T) {
+	townRoot := t.TempDir()
+	dir := boot.HooksDir(townRoot, boot.HookPost)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(townRoot, "result.json")
+	if err := os.WriteFile(filepath.Join(dir, "10-notify"), []byte("#!/bin/sh\ncat > "+out+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	progress := newUpProgress(townRoot, &boot.Selection{}, []boot.Agent{boot.NewAgent("mayor", "", "", "")})
+	hooks := &boot.HookRunner{TownRoot: townRoot, Timeout: 10 * time.Second}
+	runUpPostHooks(hooks, progress, errors.New("ordering agent startup: dependency cycle"), boot.Summary{}, nil)
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("post hook didn't run: %v", err)
+	}
+	var in upHookInput
+	if err := json.Unmarshal(data, &in); err != nil {
+		t.Fatalf("post hook input doesn't parse: %v\n%s", err, data)
+	}
+	if in.Success || !strings.Contains(in.Error, "dependency cycle") {
+		t.Errorf("post hook input = %+v, want a failure with the boot's error", in)
+	}
+}
@@ -1,10 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/KeithWyatt/gongshow/internal/style"
@@ -19,6 +21,15 @@ var (
 	witnessStatusJSON    bool
 	witnessAgentOverride string
 	witnessEnvOverrides  []string
+
+	witnessReportRig         string
+	witnessReportFormat      string
+	witnessReportSince       string
+	witnessReportNudges      bool
+	witnessReportEscalations bool
+
+	witnessPluginsRig  string
+	witnessPluginsJSON bool
 )
 
 var witnessCmd = &cobra.Command{
@@ -112,6 +123,53 @@ Examples:
 	RunE: runWitnessRestart,
 }
 
+var witnessReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize witness patrol history",
+	Long: `Summarize a rig's witness patrols from the town events log.
+
+Each patrol is delimited by its patrol_started and patrol_complete events.
+Nudges and escalations logged during a patrol are counted against it.
+
+Formats:
+  text      Summary and table for the terminal (default)
+  markdown  GitHub-compatible tables, for issues and PRs
+  json      Raw patrol records
+
+If --rig is not specified, infers it from the current directory.
+
+Examples:
+  gt witness report --rig greenplace
+  gt witness report --rig greenplace --format markdown --since 7d
+  gt witness report --rig greenplace --format json --nudges=false`,
+	Args: cobra.NoArgs,
+	RunE: runWitnessReport,
+}
+
+var witnessPluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Run the town's patrol plugins against a rig",
+	Long: `Run the patrol plugins listed in config/patrol-plugins.json against a rig.
+
+The config file is a JSON array of executable paths (relative paths are
+resolved against the town root):
+
+  ["plugins/disk-space.sh", "/usr/local/bin/ci-status"]
+
+Each plugin runs in the rig directory, receives it as its only argument and
+must print a JSON object such as {"status": "ok", "details": {...}}. Every
+result is logged as a patrol_plugin_result event. The witness runs this in
+the run-plugins step of each patrol (mol-witness-patrol).
+
+If --rig is not specified, infers it from the current directory.
+
+Examples:
+  gt witness plugins --rig greenplace
+  gt witness plugins --rig greenplace --json`,
+	Args: cobra.NoArgs,
+	RunE: runWitnessPlugins,
+}
+
 func init() {
 	// Start flags
 	witnessStartCmd.Flags().BoolVar(&witnessForeground, "foreground", false, "Run in foreground (default: background)")
@@ -125,10 +183,23 @@ func init() {
 	witnessRestartCmd.Flags().StringVar(&witnessAgentOverride, "agent", "", "Agent alias to run the Witness with (overrides town default)")
 	witnessRestartCmd.Flags().StringArrayVar(&witnessEnvOverrides, "env", nil, "Environment variable override (KEY=VALUE, can be repeated)")
 
+	// Report flags
+	witnessReportCmd.Flags().StringVar(&witnessReportRig, "rig", "", "Rig to report on (default: infer from cwd)")
+	witnessReportCmd.Flags().StringVar(&witnessReportFormat, "format", witness.ReportText, "Output format: text, markdown, or json")
+	witnessReportCmd.Flags().StringVar(&witnessReportSince, "since", "", "Only include patrols within this duration (e.g., 24h, 7d)")
+	witnessReportCmd.Flags().BoolVar(&witnessReportNudges, "nudges", true, "Include nudges")
+	witnessReportCmd.Flags().BoolVar(&witnessReportEscalations, "escalations", true, "Include escalations")
+
+	// Plugins flags
+	witnessPluginsCmd.Flags().StringVar(&witnessPluginsRig, "rig", "", "Rig to run the plugins against (default: infer from cwd)")
+	witnessPluginsCmd.Flags().BoolVar(&witnessPluginsJSON, "json", false, "Output as JSON")
+
 	// Add subcommands
 	witnessCmd.AddCommand(witnessStartCmd)
 	witnessCmd.AddCommand(witnessStopCmd)
 	witnessCmd.AddCommand(witnessRestartCmd)
+	witnessCmd.AddCommand(witnessReportCmd)
+	witnessCmd.AddCommand(witnessPluginsCmd)
 	witnessCmd.AddCommand(witnessStatusCmd)
 	witnessCmd.AddCommand(witnessAttachCmd)
 
@@ -350,3 +421,87 @@ func runWitnessRestart(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  %s\n", style.Dim.Render("Use 'gt witness attach' to connect"))
 	return nil
 }
+
+func runWitnessReport(cmd *cobra.Command, args []string) error {
+	var since time.Duration
+	if witnessReportSince != "" {
+		var err error
+		since, err = parseDuration(witnessReportSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+	}
+
+	rigName := witnessReportRig
+	if rigName == "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a GongShow workspace: %w", err)
+		}
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return fmt.Errorf("could not determine rig: %w\nUsage: gt witness report --rig <rig>", err)
+		}
+	}
+
+	mgr, err := getWitnessManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	report, err := mgr.GenerateReport(witness.ReportOptions{
+		Format:             witnessReportFormat,
+		Since:              since,
+		IncludeNudges:      witnessReportNudges,
+		IncludeEscalations: witnessReportEscalations,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Print(report)
+	return nil
+}
+
+func runWitnessPlugins(cmd *cobra.Command, args []string) error {
+	rigName := witnessPluginsRig
+	if rigName == "" {
+		townRoot, err := workspace.FindFromCwdOrError()
+		if err != nil {
+			return fmt.Errorf("not in a GongShow workspace: %w", err)
+		}
+		rigName, err = inferRigFromCwd(townRoot)
+		if err != nil {
+			return fmt.Errorf("could not determine rig: %w\nUsage: gt witness plugins --rig <rig>", err)
+		}
+	}
+
+	mgr, err := getWitnessManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	results, err := mgr.RunPatrolPlugins(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if witnessPluginsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("%s No patrol plugins configured\n", style.Dim.Render("○"))
+		return nil
+	}
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			fmt.Printf("%s %s: %v\n", style.WarningPrefix, r.Plugin, r.Err)
+		default:
+			fmt.Printf("  %s %s\n", style.Bold.Render(r.Plugin), r.Status)
+		}
+	}
+	return nil
+}
@@ -67,6 +67,7 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		env["GT_RIG"] = cfg.Rig
 		env["BD_ACTOR"] = fmt.Sprintf("%s/refinery", cfg.Rig)
 		env["GIT_AUTHOR_NAME"] = fmt.Sprintf("%s/refinery", cfg.Rig)
+		env["GT_REFINERY"] = "1"
 
 	case "polecat":
 		env["GT_RIG"] = cfg.Rig
@@ -85,6 +85,7 @@ func TestAgentEnv_Refinery(t *testing.T) {
 	assertEnv(t, env, "GT_RIG", "myrig")
 	assertEnv(t, env, "BD_ACTOR", "myrig/refinery")
 	assertEnv(t, env, "GIT_AUTHOR_NAME", "myrig/refinery")
+	assertEnv(t, env, "GT_REFINERY", "1")
 	assertEnv(t, env, "BEADS_NO_DAEMON", "1")
 }
 
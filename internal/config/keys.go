@@ -0,0 +1,379 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Key path errors.
+var (
+	ErrInvalidKey = errors.New("invalid config key")
+	ErrKeyNotSet  = errors.New("config key not set")
+)
+
+// KeyValue is one leaf setting found by ListKeys.
+type KeyValue struct {
+	Key   string
+	Value any
+}
+
+// Config keys are dot-separated paths into a config struct. A segment names
+// a struct field by its JSON name, a map entry by its key, or a slice
+// element by its index: "queues.work/gongshow.max_claims", "hooks.0.name".
+
+// GetKey returns the value at key in cfg, a pointer to a config struct.
+// It returns ErrInvalidKey if the path doesn't exist in the config's
+// schema, and ErrKeyNotSet if it does but has no value.
+func GetKey(cfg any, key string) (any, error) {
+	path, err := splitKey(key)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(cfg)
+	for i, seg := range path {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if err := checkKeyType(v.Type(), path[i:], path[:i]); err != nil {
+					return nil, err
+				}
+				return nil, fmt.Errorf("%w: %s", ErrKeyNotSet, key)
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := fieldByJSONName(v, seg)
+			if !ok {
+				return nil, unknownKey(path[:i+1])
+			}
+			v = f
+		case reflect.Map:
+			e := v.MapIndex(reflect.ValueOf(seg).Convert(v.Type().Key()))
+			if !e.IsValid() {
+				if err := checkKeyType(v.Type().Elem(), path[i+1:], path[:i+1]); err != nil {
+					return nil, err
+				}
+				return nil, fmt.Errorf("%w: %s", ErrKeyNotSet, key)
+			}
+			v = e
+		case reflect.Slice:
+			n, err := strconv.Atoi(seg)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %q is not a list index", ErrInvalidKey, strings.Join(path[:i], "."), seg)
+			}
+			if n < 0 || n >= v.Len() {
+				return nil, fmt.Errorf("%w: %s", ErrKeyNotSet, key)
+			}
+			v = v.Index(n)
+		default:
+			return nil, notAnObject(path[:i], v.Type())
+		}
+	}
+
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotSet, key)
+	}
+	return v.Interface(), nil
+}
+
+// SetKey parses raw as the type of the value at key and stores it in cfg,
+// creating maps, map entries and pointers along the path as needed.
+// Numbers and booleans are parsed from their text, a list of strings from
+// comma-separated values or a JSON array, and anything else from JSON.
+func SetKey(cfg any, key, raw string) error {
+	path, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("SetKey: cfg must be a non-nil pointer, got %T", cfg)
+	}
+	return setPath(v.Elem(), path, 0, raw)
+}
+
+// setPath sets the value at path[i:] below v, which must be settable.
+func setPath(v reflect.Value, path []string, i int, raw string) error {
+	if i == len(path) {
+		if err := coerce(v, raw); err != nil {
+			return fmt.Errorf("%s: %w", strings.Join(path, "."), err)
+		}
+		return nil
+	}
+	seg := path[i]
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setPath(v.Elem(), path, i, raw)
+	case reflect.Struct:
+		f, ok := fieldByJSONName(v, seg)
+		if !ok {
+			return unknownKey(path[:i+1])
+		}
+		return setPath(f, path, i+1, raw)
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		k := reflect.ValueOf(seg).Convert(v.Type().Key())
+		// Map entries aren't addressable: update a copy and store it back.
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if e := v.MapIndex(k); e.IsValid() {
+			elem.Set(e)
+		}
+		if err := setPath(elem, path, i+1, raw); err != nil {
+			return err
+		}
+		v.SetMapIndex(k, elem)
+		return nil
+	case reflect.Slice:
+		n, err := strconv.Atoi(seg)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %q is not a list index", ErrInvalidKey, strings.Join(path[:i], "."), seg)
+		}
+		if n < 0 || n >= v.Len() {
+			return fmt.Errorf("%w: %s has %d entries, no index %d", ErrInvalidKey, strings.Join(path[:i], "."), v.Len(), n)
+		}
+		return setPath(v.Index(n), path, i+1, raw)
+	default:
+		return notAnObject(path[:i], v.Type())
+	}
+}
+
+// coerce parses raw into v according to v's type.
+func coerce(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		if err := coerce(p.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	case reflect.String:
+		v.SetString(raw)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean (true/false)", raw)
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a non-negative integer", raw)
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(raw), "[") {
+			var items []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			s := reflect.MakeSlice(v.Type(), len(items), len(items))
+			for i, item := range items {
+				s.Index(i).SetString(item)
+			}
+			v.Set(s)
+			return nil
+		}
+	}
+
+	p := reflect.New(v.Type())
+	if err := json.Unmarshal([]byte(raw), p.Interface()); err != nil {
+		return fmt.Errorf("%q is not a valid %s (JSON): %w", raw, describeType(v.Type()), err)
+	}
+	v.Set(p.Elem())
+	return nil
+}
+
+// ListKeys returns every setting in cfg that has a value, sorted by key.
+// Lists of scalars are single settings; other lists are listed by index.
+func ListKeys(cfg any) []KeyValue {
+	var out []KeyValue
+	listKeys(reflect.ValueOf(cfg), "", &out)
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+func listKeys(v reflect.Value, prefix string, out *[]KeyValue) {
+	join := func(seg string) string {
+		if prefix == "" {
+			return seg
+		}
+		return prefix + "." + seg
+	}
+	v = reflect.Indirect(v)
+	if !v.IsValid() {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if name := jsonName(t.Field(i)); name != "" {
+				listKeys(v.Field(i), join(name), out)
+			}
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			listKeys(v.MapIndex(k), join(k.String()), out)
+		}
+	case reflect.Slice:
+		if isScalar(v.Type().Elem()) {
+			if v.Len() > 0 {
+				*out = append(*out, KeyValue{Key: prefix, Value: v.Interface()})
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			listKeys(v.Index(i), join(strconv.Itoa(i)), out)
+		}
+	default:
+		if !v.IsZero() {
+			*out = append(*out, KeyValue{Key: prefix, Value: v.Interface()})
+		}
+	}
+}
+
+// FormatValue renders a config value for display: scalars as text, anything
+// else as JSON, in the forms SetKey accepts.
+func FormatValue(value any) string {
+	v := reflect.ValueOf(value)
+	if v.IsValid() && isScalar(v.Type()) {
+		return fmt.Sprint(value)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// checkKeyType checks that path is a valid key below a value of type t;
+// prefix is the key up to t, for error messages.
+func checkKeyType(t reflect.Type, path, prefix []string) error {
+	for i, seg := range path {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := structFieldByJSONName(t, seg)
+			if !ok {
+				return unknownKey(append(append([]string{}, prefix...), path[:i+1]...))
+			}
+			t = f.Type
+		case reflect.Map:
+			t = t.Elem()
+		case reflect.Slice:
+			if _, err := strconv.Atoi(seg); err != nil {
+				return fmt.Errorf("%w: %q is not a list index", ErrInvalidKey, seg)
+			}
+			t = t.Elem()
+		default:
+			return notAnObject(append(append([]string{}, prefix...), path[:i]...), t)
+		}
+	}
+	return nil
+}
+
+func splitKey(key string) ([]string, error) {
+	path := strings.Split(key, ".")
+	for _, seg := range path {
+		if seg == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return path, nil
+}
+
+// jsonName returns the JSON name of a struct field, or "" if it isn't
+// encoded.
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func structFieldByJSONName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); jsonName(f) == name {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	f, ok := structFieldByJSONName(v.Type(), name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return v.FieldByIndex(f.Index), true
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return describeType(t.Elem())
+	case reflect.Slice:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.Kind().String()
+}
+
+func unknownKey(path []string) error {
+	return fmt.Errorf("%w: unknown key %s", ErrInvalidKey, strings.Join(path, "."))
+}
+
+func notAnObject(path []string, t reflect.Type) error {
+	return fmt.Errorf("%w: %s is a %s, not an object", ErrInvalidKey, strings.Join(path, "."), describeType(t))
+}
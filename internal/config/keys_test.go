@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSetKeyNestedPaths(t *testing.T) {
+	t.Parallel()
+	cfg := NewMessagingConfig()
+
+	if err := SetKey(cfg, "lists.oncall", "mayor/, gongshow/witness"); err != nil {
+		t.Fatalf("SetKey lists.oncall: %v", err)
+	}
+	if err := SetKey(cfg, "queues.work/gongshow.workers", "gongshow/polecats/*"); err != nil {
+		t.Fatalf("SetKey queues workers: %v", err)
+	}
+	if err := SetKey(cfg, "queues.work/gongshow.max_claims", "3"); err != nil {
+		t.Fatalf("SetKey queues max_claims: %v", err)
+	}
+
+	if got, want := cfg.Lists["oncall"], []string{"mayor/", "gongshow/witness"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lists[oncall] = %v, want %v", got, want)
+	}
+	q := cfg.Queues["work/gongshow"]
+	if !reflect.DeepEqual(q.Workers, []string{"gongshow/polecats/*"}) {
+		t.Errorf("queue workers = %v", q.Workers)
+	}
+	if q.MaxClaims != 3 {
+		t.Errorf("queue max_claims = %d, want 3", q.MaxClaims)
+	}
+
+	got, err := GetKey(cfg, "queues.work/gongshow.max_claims")
+	if err != nil {
+		t.Fatalf("GetKey: %v", err)
+	}
+	if got != 3 {
+		t.Errorf("GetKey max_claims = %v, want 3", got)
+	}
+}
+
+func TestSetKeyTypeCoercion(t *testing.T) {
+	t.Parallel()
+	type nested struct {
+		Enabled *bool    `json:"enabled,omitempty"`
+		Ratio   float64  `json:"ratio"`
+		Tags    []string `json:"tags"`
+	}
+	type sample struct {
+		Count  int               `json:"count"`
+		Nested *nested           `json:"nested,omitempty"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	cfg := &sample{}
+	for key, raw := range map[string]string{
+		"count":          "42",
+		"nested.enabled": "true",
+		"nested.ratio":   "0.5",
+		"nested.tags":    `["a", "b,c"]`,
+		"labels.owner":   "mayor",
+	} {
+		if err := SetKey(cfg, key, raw); err != nil {
+			t.Fatalf("SetKey(%s, %q): %v", key, raw, err)
+		}
+	}
+
+	if cfg.Count != 42 {
+		t.Errorf("Count = %d, want 42", cfg.Count)
+	}
+	if cfg.Nested == nil || cfg.Nested.Enabled == nil || !*cfg.Nested.Enabled {
+		t.Fatalf("Nested.Enabled not set: %+v", cfg.Nested)
+	}
+	if cfg.Nested.Ratio != 0.5 {
+		t.Errorf("Ratio = %v, want 0.5", cfg.Nested.Ratio)
+	}
+	if !reflect.DeepEqual(cfg.Nested.Tags, []string{"a", "b,c"}) {
+		t.Errorf("Tags = %v, want JSON array decoded", cfg.Nested.Tags)
+	}
+	if cfg.Labels["owner"] != "mayor" {
+		t.Errorf("Labels[owner] = %q", cfg.Labels["owner"])
+	}
+
+	for key, raw := range map[string]string{
+		"count":          "three",
+		"nested.enabled": "maybe",
+		"nested.ratio":   "half",
+	} {
+		if err := SetKey(cfg, key, raw); err == nil {
+			t.Errorf("SetKey(%s, %q) succeeded, want a type error", key, raw)
+		}
+	}
+}
+
+func TestKeyInvalidPaths(t *testing.T) {
+	t.Parallel()
+	cfg := NewMessagingConfig()
+	cfg.Lists["oncall"] = []string{"mayor/"}
+
+	for _, key := range []string{
+		"",
+		"lists.",
+		"bogus",
+		"queues.work.max_clams",
+		"lists.oncall.members",
+		"version.major",
+	} {
+		if err := SetKey(cfg, key, "1"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("SetKey(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+		if _, err := GetKey(cfg, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("GetKey(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+
+	// Valid paths without a value are reported as not set.
+	for _, key := range []string{"lists.missing", "queues.work/other.max_claims", "lists.oncall.5"} {
+		if _, err := GetKey(cfg, key); !errors.Is(err, ErrKeyNotSet) {
+			t.Errorf("GetKey(%q) error = %v, want ErrKeyNotSet", key, err)
+		}
+	}
+}
+
+func TestListKeys(t *testing.T) {
+	t.Parallel()
+	cfg := NewMessagingConfig()
+	cfg.Lists["oncall"] = []string{"mayor/"}
+	cfg.Queues["work"] = QueueConfig{Workers: []string{"gongshow/polecats/*"}, MaxClaims: 2}
+
+	var keys []string
+	for _, kv := range ListKeys(cfg) {
+		keys = append(keys, kv.Key)
+	}
+	want := []string{"lists.oncall", "queues.work.max_claims", "queues.work.workers", "type", "version"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("ListKeys = %v, want %v", keys, want)
+	}
+}
+
+func TestTownConfigFileCheckSchema(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	file, rest, err := LookupTownConfigKey("messaging.lists")
+	if err != nil {
+		t.Fatalf("LookupTownConfigKey: %v", err)
+	}
+	if file.Name != "messaging" || rest != "lists" {
+		t.Fatalf("LookupTownConfigKey = %s, %q", file.Name, rest)
+	}
+
+	// A missing file is valid.
+	if err := file.CheckSchema(townRoot); err != nil {
+		t.Errorf("CheckSchema(missing) = %v", err)
+	}
+
+	cfg, err := file.Load(townRoot)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := SetKey(cfg, "lists.oncall", "mayor/"); err != nil {
+		t.Fatalf("SetKey: %v", err)
+	}
+	if err := file.Save(townRoot, cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := file.CheckSchema(townRoot); err != nil {
+		t.Errorf("CheckSchema(saved) = %v", err)
+	}
+
+	// An unknown field is a schema error.
+	if err := os.WriteFile(file.Path(townRoot), []byte(`{"type": "messaging", "version": 1, "listz": {}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.CheckSchema(townRoot); err == nil {
+		t.Error("CheckSchema accepted an unknown field")
+	}
+
+	if _, _, err := LookupTownConfigKey("nosuchfile.key"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("LookupTownConfigKey(unknown) error = %v, want ErrInvalidKey", err)
+	}
+}
@@ -759,11 +759,8 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 
 // SaveTownSettings saves town settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
-	if settings.Type != "town-settings" && settings.Type != "" {
-		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, settings.Type)
-	}
-	if settings.Version > CurrentTownSettingsVersion {
-		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, settings.Version, CurrentTownSettingsVersion)
+	if err := validateTownSettings(settings); err != nil {
+		return err
 	}
 
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
@@ -782,6 +779,61 @@ func SaveTownSettings(path string, settings *TownSettings) error {
 	return nil
 }
 
+func validateTownSettings(c *TownSettings) error {
+	if c.Type != "town-settings" && c.Type != "" {
+		return fmt.Errorf("%w: expected type 'town-settings', got '%s'", ErrInvalidType, c.Type)
+	}
+	if c.Version > CurrentTownSettingsVersion {
+		return fmt.Errorf("%w: got %d, max supported %d", ErrInvalidVersion, c.Version, CurrentTownSettingsVersion)
+	}
+	for name, profile := range c.BootProfiles {
+		if name == "" || profile == nil {
+			return fmt.Errorf("%w: boot profile %q is empty", ErrMissingField, name)
+		}
+		if err := ValidateBootRoles(profile.Roles); err != nil {
+			return fmt.Errorf("boot profile %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// LookupBootProfile returns the named boot profile, or an error listing
+// the profiles there are.
+func (c *TownSettings) LookupBootProfile(name string) (*BootProfile, error) {
+	if profile, ok := c.BootProfiles[name]; ok && profile != nil {
+		if err := ValidateBootRoles(profile.Roles); err != nil {
+			return nil, fmt.Errorf("boot profile %q: %w", name, err)
+		}
+		return profile, nil
+	}
+	if name == BootProfileFull {
+		return &BootProfile{Roles: BootRoles}, nil
+	}
+
+	names := []string{BootProfileFull}
+	for n := range c.BootProfiles {
+		if n != BootProfileFull {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown boot profile %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ValidateBootRoles checks that roles are all BootRoles.
+func ValidateBootRoles(roles []string) error {
+	for _, role := range roles {
+		known := false
+		for _, r := range BootRoles {
+			known = known || r == role
+		}
+		if !known {
+			return fmt.Errorf("unknown role %q (roles: %s)", role, strings.Join(BootRoles, ", "))
+		}
+	}
+	return nil
+}
+
 // ResolveAgentConfig resolves the agent configuration for a rig.
 // It looks up the agent by name in town settings (custom agents) and built-in presets.
 //
@@ -1621,6 +1673,28 @@ func (c *EscalationConfig) GetStaleThreshold() time.Duration {
 	return d
 }
 
+// GetThreshold returns the number of deaths that trips mass-death detection.
+// Returns DefaultMassDeathThreshold if c is nil or the threshold is unset.
+func (c *MassDeathConfig) GetThreshold() int {
+	if c == nil || c.Threshold <= 0 {
+		return DefaultMassDeathThreshold
+	}
+	return c.Threshold
+}
+
+// GetWindow returns the mass-death detection window as a time.Duration.
+// Returns DefaultMassDeathWindow if c is nil or the window is unset or invalid.
+func (c *MassDeathConfig) GetWindow() time.Duration {
+	if c == nil || c.Window == "" {
+		return DefaultMassDeathWindow
+	}
+	d, err := time.ParseDuration(c.Window)
+	if err != nil || d <= 0 {
+		return DefaultMassDeathWindow
+	}
+	return d
+}
+
 // GetRouteForSeverity returns the escalation route actions for a given severity.
 // Falls back to ["bead", "mail:mayor"] if no specific route is configured.
 func (c *EscalationConfig) GetRouteForSeverity(severity string) []string {
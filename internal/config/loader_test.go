@@ -2347,6 +2347,33 @@ func TestEscalationConfigValidation(t *testing.T) {
 	}
 }
 
+func TestMassDeathConfigGetters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		config    *MassDeathConfig
+		threshold int
+		window    time.Duration
+	}{
+		{"nil uses defaults", nil, DefaultMassDeathThreshold, DefaultMassDeathWindow},
+		{"empty uses defaults", &MassDeathConfig{}, DefaultMassDeathThreshold, DefaultMassDeathWindow},
+		{"configured", &MassDeathConfig{Threshold: 3, Window: "2m"}, 3, 2 * time.Minute},
+		{"invalid falls back", &MassDeathConfig{Threshold: -1, Window: "soon"}, DefaultMassDeathThreshold, DefaultMassDeathWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.GetThreshold(); got != tt.threshold {
+				t.Errorf("GetThreshold() = %d, want %d", got, tt.threshold)
+			}
+			if got := tt.config.GetWindow(); got != tt.window {
+				t.Errorf("GetWindow() = %v, want %v", got, tt.window)
+			}
+		})
+	}
+}
+
 func TestEscalationConfigGetStaleThreshold(t *testing.T) {
 	t.Parallel()
 
@@ -2596,3 +2623,40 @@ func TestBuildStartupCommandWithAgentOverride_IncludesGTRoot(t *testing.T) {
 		t.Errorf("expected GT_ROOT=%s in command, got: %q", townRoot, cmd)
 	}
 }
+
+func TestLookupBootProfile(t *testing.T) {
+	t.Parallel()
+	settings := NewTownSettings()
+	settings.BootProfiles = map[string]*BootProfile{
+		"dev":    {Rigs: []string{"gongshow"}, Roles: []string{"mayor", "witness", "crew"}},
+		"broken": {Roles: []string{"mayor", "janitor"}},
+	}
+
+	dev, err := settings.LookupBootProfile("dev")
+	if err != nil || strings.Join(dev.Rigs, ",") != "gongshow" || len(dev.Roles) != 3 {
+		t.Errorf("LookupBootProfile(dev) = %+v, %v", dev, err)
+	}
+
+	full, err := settings.LookupBootProfile(BootProfileFull)
+	if err != nil || len(full.Rigs) != 0 || strings.Join(full.Roles, ",") != strings.Join(BootRoles, ",") {
+		t.Errorf("LookupBootProfile(full) = %+v, %v; want every rig and role", full, err)
+	}
+
+	if _, err := settings.LookupBootProfile("broken"); err == nil || !strings.Contains(err.Error(), `unknown role "janitor"`) {
+		t.Errorf("LookupBootProfile(broken) error = %v, want unknown role", err)
+	}
+
+	_, err = settings.LookupBootProfile("laptop")
+	if err == nil || !strings.Contains(err.Error(), "available: broken, dev, full") {
+		t.Errorf("LookupBootProfile(laptop) error = %v, want the available profiles", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := SaveTownSettings(path, settings); err == nil {
+		t.Error("SaveTownSettings() accepted a profile with an unknown role")
+	}
+	delete(settings.BootProfiles, "broken")
+	if err := SaveTownSettings(path, settings); err != nil {
+		t.Errorf("SaveTownSettings() error = %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// TownConfigFile is a town-level JSON config file that can be read and
+// edited by key (see GetKey). Keys for the file start with its Name, e.g.
+// "messaging.queues.work/gongshow.max_claims".
+type TownConfigFile struct {
+	Name string
+	Path func(townRoot string) string
+
+	// New returns the config used when the file doesn't exist.
+	New func() any
+
+	// Validate checks a decoded config, filling in nil maps.
+	Validate func(cfg any) error
+}
+
+// TownConfigFiles returns the town config files, in key order.
+func TownConfigFiles() []TownConfigFile {
+	return []TownConfigFile{
+		{
+			Name:     "daemon",
+			Path:     DaemonPatrolConfigPath,
+			New:      func() any { return NewDaemonPatrolConfig() },
+			Validate: func(cfg any) error { return validateDaemonPatrolConfig(cfg.(*DaemonPatrolConfig)) },
+		},
+		{
+			Name:     "escalation",
+			Path:     EscalationConfigPath,
+			New:      func() any { return NewEscalationConfig() },
+			Validate: func(cfg any) error { return validateEscalationConfig(cfg.(*EscalationConfig)) },
+		},
+		{
+			Name:     "messaging",
+			Path:     MessagingConfigPath,
+			New:      func() any { return NewMessagingConfig() },
+			Validate: func(cfg any) error { return validateMessagingConfig(cfg.(*MessagingConfig)) },
+		},
+		{
+			Name:     "settings",
+			Path:     TownSettingsPath,
+			New:      func() any { return NewTownSettings() },
+			Validate: func(cfg any) error { return validateTownSettings(cfg.(*TownSettings)) },
+		},
+	}
+}
+
+// LookupTownConfigKey splits key into the town config file it belongs to
+// and the key within that file ("" for the whole file).
+func LookupTownConfigKey(key string) (TownConfigFile, string, error) {
+	name, rest, _ := strings.Cut(key, ".")
+	var names []string
+	for _, f := range TownConfigFiles() {
+		if f.Name == name {
+			return f, rest, nil
+		}
+		names = append(names, f.Name)
+	}
+	return TownConfigFile{}, "", fmt.Errorf("%w: %q (keys start with one of: %s)", ErrInvalidKey, key, strings.Join(names, ", "))
+}
+
+// Load reads and validates the file, or returns New() if it doesn't exist.
+func (f TownConfigFile) Load(townRoot string) (any, error) {
+	data, err := os.ReadFile(f.Path(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return f.New(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s config: %w", f.Name, err)
+	}
+	cfg := f.zero()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s config: %w", f.Name, err)
+	}
+	if err := f.Validate(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Save validates cfg and writes it to the file atomically.
+func (f TownConfigFile) Save(townRoot string, cfg any) error {
+	if err := f.Validate(cfg); err != nil {
+		return err
+	}
+	path := f.Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s config: %w", f.Name, err)
+	}
+	if err := util.AtomicWriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing %s config: %w", f.Name, err)
+	}
+	return nil
+}
+
+// CheckSchema checks the file against its schema: it must parse, have no
+// fields the config type doesn't define (typos are otherwise silently
+// ignored), and pass validation. A missing file is valid.
+func (f TownConfigFile) CheckSchema(townRoot string) error {
+	data, err := os.ReadFile(f.Path(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	cfg := f.zero()
+	if err := dec.Decode(cfg); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after the JSON object")
+	}
+	return f.Validate(cfg)
+}
+
+// zero returns a pointer to an empty config of the file's type.
+func (f TownConfigFile) zero() any {
+	return reflect.New(reflect.TypeOf(f.New()).Elem()).Interface()
+}
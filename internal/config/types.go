@@ -61,6 +61,137 @@ type TownSettings struct {
 	// Agent addresses like "gongshow/crew/jack" become "gongshow.crew.jack@{domain}".
 	// Default: "gongshow.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Hooks run actions when matching events are appended to the events log.
+	// Example: {"match": {"types": ["session_death"], "actor": "*/witness"},
+	//           "notify": {"targets": [{"channel": "sms", "address": "+15551234567"}]}}
+	Hooks []EventHook `json:"hooks,omitempty"`
+
+	// PerRigEvents writes events from rig actors (e.g. "gongshow/witness")
+	// to <rig>/.events.jsonl instead of the town events log; town-level
+	// events stay at the root. Readers merge all the files.
+	// Migrate an existing log with "gt events migrate".
+	PerRigEvents bool `json:"per_rig_events,omitempty"`
+
+	// MassDeath tunes the daemon's mass-death detection: when Threshold
+	// sessions die within Window, it logs one mass_death event and raises a
+	// critical escalation.
+	// Example: {"threshold": 5, "window": "30s"}
+	MassDeath *MassDeathConfig `json:"mass_death,omitempty"`
+
+	// BeadsReplication has the daemon replicate the town's beads to a
+	// remote store, for towns spread over several machines.
+	// Example: {"url": "http://hq.example:8400", "interval": "1m"}
+	BeadsReplication *BeadsReplicationConfig `json:"beads_replication,omitempty"`
+
+	// BootProfiles are named parts of the town for 'gt up --profile' to
+	// start. The built-in "full" profile is the whole town, crew and
+	// polecats included, unless redefined here.
+	// Example: {"dev": {"rigs": ["gongshow"], "roles": ["mayor", "witness", "crew"]}}
+	BootProfiles map[string]*BootProfile `json:"boot_profiles,omitempty"`
+}
+
+// BootProfileFull is the built-in boot profile that starts everything.
+const BootProfileFull = "full"
+
+// BootRoles are the roles a boot profile can name, in start order.
+var BootRoles = []string{"daemon", "deacon", "mayor", "witness", "refinery", "crew", "polecat"}
+
+// BootProfile selects the agents a profile boot starts.
+type BootProfile struct {
+	// Rigs are the rigs whose agents start. Empty means every rig.
+	Rigs []string `json:"rigs,omitempty"`
+
+	// Roles are the roles that start, from BootRoles. Empty means the
+	// roles 'gt up' starts by default. Naming crew or polecat starts them
+	// as 'gt up --restore' does.
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Mass-death detection defaults.
+const (
+	DefaultMassDeathThreshold = 5
+	DefaultMassDeathWindow    = 30 * time.Second
+)
+
+// MassDeathConfig sets when session deaths count as a mass death.
+type MassDeathConfig struct {
+	// Threshold is how many session deaths trip the detector.
+	// Default: 5
+	Threshold int `json:"threshold,omitempty"`
+
+	// Window is how close together the deaths must be.
+	// Format: Go duration string (e.g., "30s", "2m")
+	// Default: "30s"
+	Window string `json:"window,omitempty"`
+}
+
+// DefaultBeadsReplicationInterval is how often the daemon pushes changed
+// beads to the replication server, by default.
+const DefaultBeadsReplicationInterval = time.Minute
+
+// BeadsReplicationConfig sets where the daemon replicates beads to.
+type BeadsReplicationConfig struct {
+	// URL is the replication server (see beads.HTTPReplicationTarget).
+	URL string `json:"url"`
+
+	// Interval is how often changed beads are pushed.
+	// Format: Go duration string (e.g., "30s", "5m")
+	// Default: "1m"
+	Interval string `json:"interval,omitempty"`
+}
+
+// EventHook maps an event filter to the actions to run for matching events.
+// A hook may set any combination of Command, Mail and Notify.
+type EventHook struct {
+	// Name identifies the hook in logs (default: its position in the list).
+	Name string `json:"name,omitempty"`
+
+	// Match selects the events that trigger the hook.
+	Match EventHookMatch `json:"match"`
+
+	// Command is run with "sh -c" in the town root. Event fields are exported
+	// as GT_EVENT_* environment variables.
+	Command string `json:"command,omitempty"`
+
+	// Mail sends gt mail about the event.
+	Mail *EventHookMail `json:"mail,omitempty"`
+
+	// Notify sends a notification through the notify channels.
+	Notify *EventHookNotify `json:"notify,omitempty"`
+
+	// Timeout bounds each run of the hook.
+	// Format: Go duration string (e.g., "10s", "2m")
+	// Default: "30s"
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// EventHookMatch is an event filter. Empty fields match everything.
+type EventHookMatch struct {
+	Types   []string          `json:"types,omitempty"`   // event types (any of)
+	Actor   string            `json:"actor,omitempty"`   // glob, e.g. "*/witness"
+	Payload map[string]string `json:"payload,omitempty"` // payload key -> glob
+}
+
+// EventHookMail describes the mail sent by a hook.
+// Subject may reference $GT_EVENT_* variables.
+type EventHookMail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// EventHookNotify describes the notification sent by a hook.
+// Title may reference $GT_EVENT_* variables.
+type EventHookNotify struct {
+	Severity string            `json:"severity,omitempty"` // default: "high"
+	Title    string            `json:"title,omitempty"`
+	Targets  []EventHookTarget `json:"targets"`
+}
+
+// EventHookTarget is one notification delivery (see notify.Target).
+type EventHookTarget struct {
+	Channel string `json:"channel"`           // email, sms, slack, teams, log
+	Address string `json:"address,omitempty"` // unused for log
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -808,6 +939,9 @@ type EscalationConfig struct {
 	//   - "email:human" → Send email to contacts.human_email
 	//   - "sms:human"   → Send SMS to contacts.human_sms
 	//   - "slack"       → Post to contacts.slack_webhook
+	//   - "teams"       → Post to contacts.teams_webhook
+	//   - "opsgenie"    → Create an OpsGenie alert (GT_OPSGENIE_API_KEY), closed
+	//                     with the escalation
 	//   - "log"         → Write to escalation log file
 	Routes map[string][]string `json:"routes"`
 
@@ -830,6 +964,7 @@ type EscalationContacts struct {
 	HumanEmail   string `json:"human_email,omitempty"`   // email address for email:human action
 	HumanSMS     string `json:"human_sms,omitempty"`     // phone number for sms:human action
 	SlackWebhook string `json:"slack_webhook,omitempty"` // webhook URL for slack action
+	TeamsWebhook string `json:"teams_webhook,omitempty"` // incoming webhook URL for teams action
 }
 
 // CurrentEscalationVersion is the current schema version for EscalationConfig.
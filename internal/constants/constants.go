@@ -183,7 +183,7 @@ func RoleEmoji(role string) string {
 
 // SupportedShells lists shell binaries that GongShow can detect and work with.
 // Used to identify if a tmux pane is at a shell prompt vs running a command.
-var SupportedShells = []string{"bash", "zsh", "sh", "fish", "tcsh", "ksh"}
+var SupportedShells = []string{"bash", "zsh", "sh", "fish", "tcsh", "ksh", "nu"}
 
 // Path helpers construct common paths.
 
@@ -94,7 +94,40 @@ func NewManager(r *rig.Rig, g *git.Git) *Manager {
 
 // crewDir returns the directory for a crew worker.
 func (m *Manager) crewDir(name string) string {
-	return filepath.Join(m.rig.Path, "crew", name)
+	return WorkDir(m.rig.Path, name)
+}
+
+// WorkDir returns the working directory of crew member name of the rig at
+// rigPath: its clone, crew/<name>/.
+func WorkDir(rigPath, name string) string {
+	return filepath.Join(rigPath, "crew", name)
+}
+
+// BuildStartCommand returns the command crew member name of the rig at
+// rigPath is started with, which carries its startup beacon.
+func BuildStartCommand(rigPath, rigName, name string) (string, error) {
+	return buildStartCommand(rigPath, rigName, name, "", "")
+}
+
+func buildStartCommand(rigPath, rigName, name, topic, agentOverride string) (string, error) {
+	// Build the startup beacon for predecessor discovery via /resume
+	// Pass it as Claude's initial prompt - processed when Claude is ready
+	address := fmt.Sprintf("%s/crew/%s", rigName, name)
+	if topic == "" {
+		topic = "start"
+	}
+	beacon := session.FormatStartupNudge(session.StartupNudgeConfig{
+		Recipient: address,
+		Sender:    "human",
+		Topic:     topic,
+	})
+
+	// SessionStart hook handles context loading (gt prime --hook)
+	command, err := config.BuildCrewStartupCommandWithAgentOverride(rigName, name, rigPath, beacon, agentOverride)
+	if err != nil {
+		return "", fmt.Errorf("building startup command: %w", err)
+	}
+	return command, nil
 }
 
 // stateFile returns the state file path for a crew worker.
@@ -489,24 +522,10 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		return fmt.Errorf("ensuring Claude settings: %w", err)
 	}
 
-	// Build the startup beacon for predecessor discovery via /resume
-	// Pass it as Claude's initial prompt - processed when Claude is ready
-	address := fmt.Sprintf("%s/crew/%s", m.rig.Name, name)
-	topic := opts.Topic
-	if topic == "" {
-		topic = "start"
-	}
-	beacon := session.FormatStartupNudge(session.StartupNudgeConfig{
-		Recipient: address,
-		Sender:    "human",
-		Topic:     topic,
-	})
-
-	// Build startup command first
-	// SessionStart hook handles context loading (gt prime --hook)
-	claudeCmd, err := config.BuildCrewStartupCommandWithAgentOverride(m.rig.Name, name, m.rig.Path, beacon, opts.AgentOverride)
+	// Build startup command first, with the startup beacon
+	claudeCmd, err := buildStartCommand(m.rig.Path, m.rig.Name, name, opts.Topic, opts.AgentOverride)
 	if err != nil {
-		return fmt.Errorf("building startup command: %w", err)
+		return err
 	}
 
 	// For interactive/refresh mode, remove --dangerously-skip-permissions
@@ -523,14 +542,15 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	townRoot := filepath.Dir(m.rig.Path)
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	roleConfig, _ := beads.LoadRoleConfig(townRoot, "crew")
+	envVars := beads.AgentEnv(config.AgentEnvConfig{
 		Role:             "crew",
 		Rig:              m.rig.Name,
 		AgentName:        name,
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.ClaudeConfigDir,
 		BeadsNoDaemon:    true,
-	})
+	}, sessionID, roleConfig)
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
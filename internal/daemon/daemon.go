@@ -23,6 +23,8 @@ import (
 	"github.com/KeithWyatt/gongshow/internal/deacon"
 	"github.com/KeithWyatt/gongshow/internal/events"
 	"github.com/KeithWyatt/gongshow/internal/feed"
+	"github.com/KeithWyatt/gongshow/internal/mail"
+	"github.com/KeithWyatt/gongshow/internal/notify"
 	"github.com/KeithWyatt/gongshow/internal/polecat"
 	"github.com/KeithWyatt/gongshow/internal/refinery"
 	"github.com/KeithWyatt/gongshow/internal/rig"
@@ -38,33 +40,30 @@ import (
 // The daemon is the safety net for dead sessions, GUPP violations, and orphaned work.
 type Daemon struct {
 	config        *Config
-	tmux          *tmux.Tmux
+	tmux          *tmux.CachedTmux
 	logger        *log.Logger
 	ctx           context.Context
 	cancel        context.CancelFunc
 	curator       *feed.Curator
 	convoyWatcher *ConvoyWatcher
 
-	// Mass death detection: track recent session deaths
-	deathsMu     sync.Mutex
-	recentDeaths []sessionDeath
+	// Mass death detection over the session_death events in the log
+	massDeath *MassDeathDetector
+
+	// Beads replication, if the town settings enable it
+	replicator *beads.Replicator
 
 	// GUPP violation recovery tracking: agentID -> first recovery attempt time
 	guppRecoveryMu       sync.Mutex
 	guppRecoveryAttempts map[string]time.Time
-}
 
-// sessionDeath records a detected session death for mass death analysis.
-type sessionDeath struct {
-	sessionName string
-	timestamp   time.Time
-}
+	// Delegation GC throttling: last time a GC pass ran
+	lastDelegationGC time.Time
 
-// Mass death detection parameters
-const (
-	massDeathWindow    = 30 * time.Second // Time window to detect mass death
-	massDeathThreshold = 3                // Number of deaths to trigger alert
-)
+	// tmux session-closed hook: the hook name once tmux supports hooks
+	sessionHookName     string
+	sessionHooksSkipped bool
+}
 
 // New creates a new daemon instance.
 func New(config *Config) (*Daemon, error) {
@@ -85,7 +84,7 @@ func New(config *Config) (*Daemon, error) {
 
 	return &Daemon{
 		config:               config,
-		tmux:                 tmux.NewTmux(),
+		tmux:                 tmux.NewCachedTmux(tmux.NewTmux(), tmux.DefaultSessionSetTTL),
 		logger:               logger,
 		ctx:                  ctx,
 		cancel:               cancel,
@@ -157,6 +156,19 @@ func (d *Daemon) Run() error {
 		d.logger.Println("Convoy watcher started")
 	}
 
+	// Watch session deaths for mass deaths, with thresholds from town settings
+	var massDeathConfig *config.MassDeathConfig
+	var replicationConfig *config.BeadsReplicationConfig
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot)); err == nil {
+		massDeathConfig = settings.MassDeath
+		replicationConfig = settings.BeadsReplication
+	}
+	d.massDeath = NewMassDeathDetector(massDeathConfig)
+	go d.watchSessionDeaths()
+
+	// Replicate the town's beads, if the town settings name a server
+	d.startBeadsReplication(replicationConfig)
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -185,6 +197,26 @@ func (d *Daemon) Run() error {
 	}
 }
 
+// startBeadsReplication starts replicating the town's beads to the
+// server cfg names; nil or no URL leaves replication off.
+func (d *Daemon) startBeadsReplication(cfg *config.BeadsReplicationConfig) {
+	if cfg == nil || cfg.URL == "" {
+		return
+	}
+	interval := config.DefaultBeadsReplicationInterval
+	if cfg.Interval != "" {
+		parsed, err := time.ParseDuration(cfg.Interval)
+		if err != nil || parsed <= 0 {
+			d.logger.Printf("Warning: invalid beads_replication interval %q, using %v", cfg.Interval, interval)
+		} else {
+			interval = parsed
+		}
+	}
+	store := beads.New(d.config.TownRoot).Store()
+	d.replicator = store.EnableReplication(beads.NewHTTPReplicationTarget(cfg.URL), interval)
+	d.logger.Printf("Replicating beads to %s every %v", cfg.URL, interval)
+}
+
 // recoveryHeartbeatInterval is the fixed interval for recovery-focused daemon.
 // Normal wake is handled by feed subscription (bd activity --follow).
 // The daemon is a safety net for dead sessions, GUPP violations, and orphaned work.
@@ -238,6 +270,22 @@ func (d *Daemon) heartbeat(state *State) {
 	// This validates tmux sessions are still alive for polecats with work-on-hook
 	d.checkPolecatSessionHealth()
 
+	// 12. Garbage-collect orphaned delegations once the store grows large
+	d.collectDelegationGarbage()
+
+	// 13. Retry notifications deferred by quiet hours or rate limits
+	d.flushNotifySpool()
+
+	// 14. Keep the tmux session-closed hook installed
+	d.ensureSessionHooks()
+
+	// 15. Report beads replication failing
+	if d.replicator != nil {
+		if err := d.replicator.Err(); err != nil {
+			d.logger.Printf("Warning: beads replication: %v", err)
+		}
+	}
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -248,6 +296,47 @@ func (d *Daemon) heartbeat(state *State) {
 	d.logger.Printf("Heartbeat complete (#%d)", state.HeartbeatCount)
 }
 
+// collectDelegationGarbage removes delegations orphaned by deleted beads.
+// Throttled to DelegationGCInterval because each scan reads all of
+// issues.jsonl; the pass itself only runs above DelegationGCThreshold.
+func (d *Daemon) collectDelegationGarbage() {
+	if d.config.DelegationGCInterval > 0 && time.Since(d.lastDelegationGC) < d.config.DelegationGCInterval {
+		return
+	}
+	d.lastDelegationGC = time.Now()
+
+	bd := beads.New(d.config.TownRoot)
+	result, err := bd.GarbageCollectIfNeeded(d.config.DelegationGCThreshold)
+	if err != nil {
+		d.logger.Printf("Warning: delegation GC failed: %v", err)
+		return
+	}
+	for _, w := range result.Warnings {
+		d.logger.Printf("Warning: delegation GC: %s", w)
+	}
+	if result.Collected > 0 {
+		d.logger.Printf("Delegation GC removed %d orphaned delegation(s)", result.Collected)
+	}
+}
+
+// flushNotifySpool sends spooled notifications whose not-before time has passed.
+func (d *Daemon) flushNotifySpool() {
+	results, err := notify.FlushSpool(d.config.TownRoot)
+	if err != nil {
+		d.logger.Printf("Warning: notify spool flush failed: %v", err)
+	}
+	for _, r := range results {
+		switch {
+		case r.Deferred:
+			// Still blocked; stays in the spool
+		case r.Success:
+			d.logger.Printf("Spooled %s notification sent: %s", r.Channel, r.Message)
+		default:
+			d.logger.Printf("Warning: spooled %s notification failed: %s", r.Channel, r.Message)
+		}
+	}
+}
+
 // DeaconRole is the role name for the Deacon's handoff bead.
 const DeaconRole = "deacon"
 
@@ -317,7 +406,7 @@ func (d *Daemon) runDegradedBootTriage(b *boot.Boot) {
 	status.Running = false
 	status.CompletedAt = time.Now()
 
-	if err := b.SaveStatus(status); err != nil {
+	if err := b.SaveTriageStatus(status); err != nil {
 		d.logger.Printf("Warning: failed to save Boot status: %v", err)
 	}
 }
@@ -599,6 +688,15 @@ func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return
 		d.logger.Println("Convoy watcher stopped")
 	}
 
+	// Stop beads replication
+	if d.replicator != nil {
+		d.replicator.Stop()
+		d.logger.Println("Beads replication stopped")
+	}
+
+	// Stop the session death watcher
+	d.cancel()
+
 	state.Running = false
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save final state: %v", err)
@@ -677,7 +775,10 @@ func StopDaemon(townRoot string) error {
 	// Check if still running
 	if err := process.Signal(syscall.Signal(0)); err == nil {
 		// Still running, force kill
-		_ = process.Signal(syscall.SIGKILL)
+		if process.Signal(syscall.SIGKILL) == nil {
+			_ = events.LogAudit(events.TypeKill, "gt",
+				events.KillPayload("", fmt.Sprintf("daemon (PID %d)", pid), "did not stop on SIGTERM"))
+		}
 	}
 
 	// Clean up PID file
@@ -743,15 +844,24 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	// Build the expected tmux session name
 	sessionName := fmt.Sprintf("gt-%s-%s", rigName, polecatName)
 
-	// Check if tmux session exists
+	// Check if tmux session exists. The cached listing is shared by every
+	// polecat checked this tick; a miss is confirmed directly, since the
+	// session may have been started since the listing was taken.
+	sessions, err := d.tmux.GetSessionSet()
+	if err != nil {
+		d.logger.Printf("Error listing sessions: %v", err)
+		return
+	}
+	if sessions.Has(sessionName) {
+		// Session is alive - nothing to do
+		return
+	}
 	sessionAlive, err := d.tmux.HasSession(sessionName)
 	if err != nil {
 		d.logger.Printf("Error checking session %s: %v", sessionName, err)
 		return
 	}
-
 	if sessionAlive {
-		// Session is alive - nothing to do
 		return
 	}
 
@@ -776,8 +886,8 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	d.logger.Printf("CRASH DETECTED: polecat %s/%s has hook_bead=%s but session %s is dead",
 		rigName, polecatName, info.HookBead, sessionName)
 
-	// Track this death for mass death detection
-	d.recordSessionDeath(sessionName)
+	// Log the death; the mass death watcher picks it up from the events log
+	d.recordSessionDeath(sessionName, fmt.Sprintf("%s/polecats/%s", rigName, polecatName))
 
 	// Auto-restart the polecat
 	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
@@ -789,54 +899,34 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 	}
 }
 
-// recordSessionDeath records a session death and checks for mass death pattern.
-func (d *Daemon) recordSessionDeath(sessionName string) {
-	d.deathsMu.Lock()
-	defer d.deathsMu.Unlock()
-
-	now := time.Now()
-
-	// Add this death
-	d.recentDeaths = append(d.recentDeaths, sessionDeath{
-		sessionName: sessionName,
-		timestamp:   now,
-	})
-
-	// Prune deaths outside the window
-	cutoff := now.Add(-massDeathWindow)
-	var recent []sessionDeath
-	for _, death := range d.recentDeaths {
-		if death.timestamp.After(cutoff) {
-			recent = append(recent, death)
-		}
+// invalidateGroupsForSession drops the town's cached @group expansions that
+// could include the agent owning sessionName. Rig-scoped groups (@rig/,
+// @crew/, @polecats/) are matched by rig; town-level and unparseable
+// sessions clear the whole town.
+func invalidateGroupsForSession(townRoot, sessionName string) {
+	cache := mail.SharedGroupCache()
+	identity, err := session.ParseSessionName(sessionName)
+	if err != nil || identity.Rig == "" {
+		cache.Invalidate(townRoot, "")
+		return
 	}
-	d.recentDeaths = recent
-
-	// Check for mass death
-	if len(d.recentDeaths) >= massDeathThreshold {
-		d.emitMassDeathEvent()
+	cache.Invalidate(townRoot, "@*/"+identity.Rig)
+	switch identity.Role {
+	case session.RoleWitness:
+		cache.Invalidate(townRoot, "@witnesses")
+	case session.RoleRefinery:
+		cache.Invalidate(townRoot, "@refineries")
 	}
 }
 
-// emitMassDeathEvent logs a mass death event when multiple sessions die in a short window.
-func (d *Daemon) emitMassDeathEvent() {
-	// Collect session names
-	var sessions []string
-	for _, death := range d.recentDeaths {
-		sessions = append(sessions, death.sessionName)
-	}
-
-	count := len(sessions)
-	window := massDeathWindow.String()
-
-	d.logger.Printf("MASS DEATH DETECTED: %d sessions died in %s: %v", count, window, sessions)
-
-	// Emit feed event
-	_ = events.LogFeed(events.TypeMassDeath, "daemon",
-		events.MassDeathPayload(count, window, sessions, ""))
+// recordSessionDeath logs a crashed session's death as a session_death
+// event, which the mass death watcher counts like any other.
+func (d *Daemon) recordSessionDeath(sessionName, agent string) {
+	_ = events.LogFeed(events.TypeSessionDeath, agent,
+		events.SessionDeathPayload(sessionName, agent, "crashed with work on hook", "daemon"))
 
-	// Clear the deaths to avoid repeated alerts
-	d.recentDeaths = nil
+	// Drop cached @group expansions that may still list the dead agent
+	invalidateGroupsForSession(d.config.TownRoot, sessionName)
 }
 
 // restartPolecatSession restarts a crashed polecat session.
@@ -7,6 +7,8 @@ import (
 	"slices"
 	"testing"
 	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -274,3 +276,19 @@ func TestLifecycleRequest_Serialization(t *testing.T) {
 		t.Errorf("Action mismatch: got %q, want %q", loaded.Action, request.Action)
 	}
 }
+
+func TestStartBeadsReplication(t *testing.T) {
+	d := testDaemon()
+	d.startBeadsReplication(nil)
+	d.startBeadsReplication(&config.BeadsReplicationConfig{Interval: "1m"})
+	if d.replicator != nil {
+		t.Fatal("replication started without a server URL")
+	}
+
+	d.config.TownRoot = t.TempDir()
+	d.startBeadsReplication(&config.BeadsReplicationConfig{URL: "http://127.0.0.1:1", Interval: "bogus"})
+	if d.replicator == nil {
+		t.Fatal("replication not started with a server URL")
+	}
+	d.replicator.Stop()
+}
@@ -378,7 +378,7 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 	d.applySessionTheme(sessionName, parsed)
 
 	// Get and send startup command
-	startCmd := d.getStartCommand(config, parsed)
+	startCmd := d.getStartCommand(config, parsed, sessionName, identityToBDActor(identity))
 	if err := d.tmux.SendKeys(sessionName, startCmd); err != nil {
 		return fmt.Errorf("sending startup command: %w", err)
 	}
@@ -394,7 +394,7 @@ func (d *Daemon) restartSession(sessionName, identity string) error {
 	// GUPP: GongShow Universal Propulsion Principle
 	// Send startup nudge for predecessor discovery via /resume
 	recipient := identityToBDActor(identity)
-	_ = session.StartupNudge(d.tmux, sessionName, session.StartupNudgeConfig{
+	_ = session.StartupNudge(d.tmux.Tmux, sessionName, session.StartupNudgeConfig{
 		Recipient: recipient,
 		Sender:    "deacon",
 		Topic:     "lifecycle-restart",
@@ -460,18 +460,32 @@ func (d *Daemon) getNeedsPreSync(config *beads.RoleConfig, parsed *ParsedIdentit
 
 // getStartCommand determines the startup command for an agent.
 // Uses role bead config if available, then role-based agent selection, then hardcoded defaults.
-func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIdentity) string {
-	// If role bead has explicit config, use it
-	if roleConfig != nil && roleConfig.StartCommand != "" {
-		// Expand any patterns in the command
-		return beads.ExpandRolePattern(roleConfig.StartCommand, d.config.TownRoot, parsed.RigName, parsed.AgentName, parsed.RoleType)
-	}
-
+func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIdentity, sessionName, actor string) string {
 	rigPath := ""
 	if parsed != nil && parsed.RigName != "" {
 		rigPath = filepath.Join(d.config.TownRoot, parsed.RigName)
 	}
 
+	// If role bead has explicit config, use it
+	if roleConfig != nil && roleConfig.StartCommand != "" {
+		// Expand any patterns in the command
+		beadsDir := beads.ResolveBeadsDir(d.config.TownRoot)
+		if rigPath != "" {
+			beadsDir = beads.ResolveBeadsDir(rigPath)
+		}
+		return beads.ExpandRoleVars(roleConfig.StartCommand, beads.RoleVars{
+			Town:        d.config.TownRoot,
+			Rig:         parsed.RigName,
+			Name:        parsed.AgentName,
+			Role:        parsed.RoleType,
+			RigDir:      rigPath,
+			ConfigDir:   filepath.Join(d.config.TownRoot, "config"),
+			BeadsDir:    beadsDir,
+			SessionName: sessionName,
+			Actor:       actor,
+		})
+	}
+
 	// Use role-based agent resolution for per-role model selection
 	runtimeConfig := config.ResolveRoleAgentConfig(parsed.RoleType, d.config.TownRoot, rigPath)
 
@@ -518,24 +532,15 @@ func (d *Daemon) getStartCommand(roleConfig *beads.RoleConfig, parsed *ParsedIde
 // setSessionEnvironment sets environment variables for the tmux session.
 // Uses centralized AgentEnv for consistency, plus role bead custom env vars if available.
 func (d *Daemon) setSessionEnvironment(sessionName string, roleConfig *beads.RoleConfig, parsed *ParsedIdentity) {
-	// Use centralized AgentEnv for base environment variables
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	envVars := beads.AgentEnv(config.AgentEnvConfig{
 		Role:      parsed.RoleType,
 		Rig:       parsed.RigName,
 		AgentName: parsed.AgentName,
 		TownRoot:  d.config.TownRoot,
-	})
+	}, sessionName, roleConfig)
 	for k, v := range envVars {
 		_ = d.tmux.SetEnvironment(sessionName, k, v)
 	}
-
-	// Set any custom env vars from role config (bead-defined overrides)
-	if roleConfig != nil {
-		for k, v := range roleConfig.EnvVars {
-			expanded := beads.ExpandRolePattern(v, d.config.TownRoot, parsed.RigName, parsed.AgentName, parsed.RoleType)
-			_ = d.tmux.SetEnvironment(sessionName, k, expanded)
-		}
-	}
 }
 
 // applySessionTheme applies tmux theming to the session.
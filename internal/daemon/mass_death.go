@@ -0,0 +1,184 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/notify"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+// Best-guess causes reported in mass_death events.
+const (
+	massDeathCauseServerGone = "tmux server gone"
+	massDeathCauseKills      = "individual kills"
+)
+
+// MassDeathDetector watches session deaths for a mass death: threshold or
+// more deaths within window. It is debounced so one incident fires once:
+// after it trips, further deaths join the incident silently until a whole
+// window passes without one.
+type MassDeathDetector struct {
+	threshold int
+	window    time.Duration
+	now       func() time.Time // a field for tests
+
+	mu         sync.Mutex
+	deaths     []sessionDeath // deaths within the window, oldest first
+	inIncident bool           // tripped, and deaths are still coming
+	lastDeath  time.Time
+}
+
+// sessionDeath records a detected session death for mass death analysis.
+type sessionDeath struct {
+	sessionName string
+	timestamp   time.Time
+}
+
+// MassDeath describes a tripped mass-death incident.
+type MassDeath struct {
+	Sessions []string      // the sessions that died, in order
+	Window   time.Duration // the detection window they died within
+}
+
+// NewMassDeathDetector creates a detector with the thresholds from cfg
+// (defaults if cfg is nil).
+func NewMassDeathDetector(cfg *config.MassDeathConfig) *MassDeathDetector {
+	return &MassDeathDetector{
+		threshold: cfg.GetThreshold(),
+		window:    cfg.GetWindow(),
+		now:       time.Now,
+	}
+}
+
+// Observe records a session death. It returns the incident when this death
+// trips the threshold, and nil otherwise, including for deaths that belong
+// to an incident that has already fired.
+func (d *MassDeathDetector) Observe(sessionName string) *MassDeath {
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.inIncident && now.Sub(d.lastDeath) > d.window {
+		d.inIncident = false // quiet for a whole window: that incident is over
+	}
+	d.lastDeath = now
+	if d.inIncident {
+		return nil
+	}
+
+	d.deaths = append(d.deaths, sessionDeath{sessionName: sessionName, timestamp: now})
+	cutoff := now.Add(-d.window)
+	for len(d.deaths) > 0 && d.deaths[0].timestamp.Before(cutoff) {
+		d.deaths = d.deaths[1:]
+	}
+	if len(d.deaths) < d.threshold {
+		return nil
+	}
+
+	md := &MassDeath{Window: d.window}
+	for _, death := range d.deaths {
+		md.Sessions = append(md.Sessions, death.sessionName)
+	}
+	d.deaths = nil
+	d.inIncident = true
+	return md
+}
+
+// watchSessionDeaths feeds every session_death event logged in the town,
+// by any process, to the mass-death detector until the daemon stops.
+func (d *Daemon) watchSessionDeaths() {
+	opts := events.StreamOptions{
+		Filter: events.Filter{Types: []string{events.TypeSessionDeath}},
+		Follow: true,
+	}
+	_, err := events.Stream(d.ctx, d.config.TownRoot, opts, func(_ string, e events.Event) error {
+		// The tmux hook records every session close, including deaths the
+		// daemon or doctor also log; counting both would halve the threshold.
+		if caller, _ := e.Payload["caller"].(string); caller == SessionHookCaller {
+			return nil
+		}
+		sessionName, _ := e.Payload["session"].(string)
+		if sessionName == "" {
+			sessionName = e.Actor
+		}
+		if md := d.massDeath.Observe(sessionName); md != nil {
+			d.handleMassDeath(md)
+		}
+		return nil
+	})
+	if err != nil {
+		d.logger.Printf("Warning: session death watcher stopped: %v", err)
+	}
+}
+
+// handleMassDeath reports a mass death: a mass_death event, a critical
+// escalation bead, and notifications along the critical escalation route.
+func (d *Daemon) handleMassDeath(md *MassDeath) {
+	townRoot := d.config.TownRoot
+	cause := d.massDeathCause()
+	window := md.Window.String()
+
+	d.logger.Printf("MASS DEATH DETECTED: %d sessions died within %s (%s): %v", len(md.Sessions), window, cause, md.Sessions)
+	_ = events.LogFeed(events.TypeMassDeath, "daemon",
+		events.MassDeathPayload(len(md.Sessions), window, md.Sessions, cause))
+
+	title := fmt.Sprintf("Mass death: %d sessions died within %s (%s)", len(md.Sessions), window, cause)
+	var escalationID string
+	bd := beads.New(beads.ResolveBeadsDir(townRoot))
+	issue, err := bd.CreateEscalationBead(title, &beads.EscalationFields{
+		Severity:    config.SeverityCritical,
+		Reason:      "Sessions: " + strings.Join(md.Sessions, ", "),
+		Source:      "daemon:mass_death",
+		EscalatedBy: "daemon",
+		EscalatedAt: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		d.logger.Printf("Warning: failed to create mass death escalation: %v", err)
+	} else {
+		escalationID = issue.ID
+	}
+
+	escalationConfig, err := config.LoadOrCreateEscalationConfig(config.EscalationConfigPath(townRoot))
+	if err != nil {
+		d.logger.Printf("Warning: failed to load escalation config: %v", err)
+		return
+	}
+	targets, warnings := notify.EscalationTargets(escalationConfig.GetRouteForSeverity(config.SeverityCritical), escalationConfig.Contacts)
+	for _, w := range warnings {
+		d.logger.Printf("Warning: %s", w)
+	}
+	if len(targets) == 0 {
+		return
+	}
+	n := &notify.Notification{
+		ID:        escalationID,
+		Severity:  config.SeverityCritical,
+		Title:     title,
+		Body:      "Sessions: " + strings.Join(md.Sessions, ", "),
+		Source:    "daemon",
+		Timestamp: time.Now(),
+	}
+	for _, result := range notify.SendAll(townRoot, targets, n) {
+		if !result.Success {
+			d.logger.Printf("Warning: mass death notification via %s failed: %s", result.Channel, result.Message)
+		}
+	}
+}
+
+// massDeathCause guesses why the sessions died by probing the tmux server:
+// if it is gone it took every session with it, otherwise they were killed
+// one by one.
+func (d *Daemon) massDeathCause() string {
+	if _, err := d.tmux.GetTmuxServerPID(); errors.Is(err, tmux.ErrNoServer) {
+		return massDeathCauseServerGone
+	}
+	return massDeathCauseKills
+}
@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// fakeClock is a settable clock for the detector.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// newTestDetector returns a detector on a fake clock.
+func newTestDetector(threshold int, window string) (*MassDeathDetector, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	d := NewMassDeathDetector(&config.MassDeathConfig{Threshold: threshold, Window: window})
+	d.now = clock.now
+	return d, clock
+}
+
+func TestMassDeathDetectorDefaults(t *testing.T) {
+	d := NewMassDeathDetector(nil)
+	if d.threshold != 5 || d.window != 30*time.Second {
+		t.Errorf("defaults = %d / %v, want 5 / 30s", d.threshold, d.window)
+	}
+}
+
+func TestMassDeathDetectorFiresOncePerIncident(t *testing.T) {
+	d, clock := newTestDetector(5, "30s")
+
+	var fired []*MassDeath
+	for i := 0; i < 12; i++ {
+		if md := d.Observe(fmt.Sprintf("gt-gongshow-p%d", i)); md != nil {
+			fired = append(fired, md)
+		}
+		clock.advance(2 * time.Second)
+	}
+
+	if len(fired) != 1 {
+		t.Fatalf("fired %d times for one incident, want 1", len(fired))
+	}
+	md := fired[0]
+	if got := strings.Join(md.Sessions, ","); got != "gt-gongshow-p0,gt-gongshow-p1,gt-gongshow-p2,gt-gongshow-p3,gt-gongshow-p4" {
+		t.Errorf("sessions = %s", got)
+	}
+	if md.Window != 30*time.Second {
+		t.Errorf("window = %v, want 30s", md.Window)
+	}
+}
+
+func TestMassDeathDetectorIgnoresSpreadOutDeaths(t *testing.T) {
+	d, clock := newTestDetector(5, "30s")
+	for i := 0; i < 20; i++ {
+		if md := d.Observe(fmt.Sprintf("s%d", i)); md != nil {
+			t.Fatalf("fired on death %d: %+v", i, md)
+		}
+		clock.advance(10 * time.Second) // at most 3 deaths in any 30s window
+	}
+}
+
+func TestMassDeathDetectorRearmsAfterQuietWindow(t *testing.T) {
+	d, clock := newTestDetector(3, "10s")
+
+	burst := func(prefix string) int {
+		fired := 0
+		for i := 0; i < 6; i++ {
+			if d.Observe(fmt.Sprintf("%s%d", prefix, i)) != nil {
+				fired++
+			}
+			clock.advance(time.Second)
+		}
+		return fired
+	}
+
+	if n := burst("a"); n != 1 {
+		t.Fatalf("first burst fired %d times, want 1", n)
+	}
+	// Still inside the incident: a death 5s later doesn't fire anew.
+	clock.advance(5 * time.Second)
+	if d.Observe("late") != nil {
+		t.Error("death during the incident fired again")
+	}
+
+	// A full quiet window ends the incident; the next burst is a new one.
+	clock.advance(11 * time.Second)
+	if n := burst("b"); n != 1 {
+		t.Errorf("second burst fired %d times, want 1", n)
+	}
+}
+
+func TestSessionClosedHook(t *testing.T) {
+	if got := sessionClosedHookName(3); got != "session-closed[42]" {
+		t.Errorf("sessionClosedHookName(3) = %q", got)
+	}
+	if got := sessionClosedHookName(2); got != "session-closed" {
+		t.Errorf("sessionClosedHookName(2) = %q", got)
+	}
+
+	cmd := sessionClosedHookCommand("/home/me/it's gt")
+	for _, want := range []string{`run-shell -b "`, `cd '/home/me/it'\''s gt'`, "gt events log session-death --session '#{hook_session_name}'"} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("hook command %q missing %q", cmd, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SessionHookCaller is the caller recorded on session_death events logged
+// by the tmux session-closed hook.
+const SessionHookCaller = "tmux"
+
+// sessionClosedHookIndex is the daemon's slot in tmux's session-closed hook
+// array (tmux 3.0+), high enough to leave users' own hooks alone.
+const sessionClosedHookIndex = 42
+
+// sessionClosedHookName returns the hook to set on tmux major.minor: an
+// indexed slot where hooks are arrays, otherwise the whole hook.
+func sessionClosedHookName(major int) string {
+	if major >= 3 {
+		return fmt.Sprintf("session-closed[%d]", sessionClosedHookIndex)
+	}
+	return "session-closed"
+}
+
+// sessionClosedHookCommand returns the tmux command the session-closed hook
+// runs: 'gt events log session-death' for the closed session, from the town
+// root so the event lands in this town's log. It runs in the background so
+// tmux doesn't wait on gt.
+func sessionClosedHookCommand(townRoot string) string {
+	townRoot = strings.ReplaceAll(townRoot, "'", "'\\''")
+	return fmt.Sprintf(`run-shell -b "cd '%s' && gt events log session-death --session '#{hook_session_name}'"`, townRoot)
+}
+
+// ensureSessionHooks installs the session-closed hook that logs a
+// session_death event whenever a GongShow session closes, so deaths are
+// recorded even between heartbeats or while the daemon is down. It runs
+// every heartbeat because global hooks are lost when the tmux server
+// restarts; tmux older than 2.2 has no hooks and is skipped.
+func (d *Daemon) ensureSessionHooks() {
+	if d.sessionHookName == "" {
+		if !d.tmux.SupportsHooks() {
+			if !d.sessionHooksSkipped {
+				d.logger.Println("tmux < 2.2 has no hooks; session deaths are only seen by polling")
+				d.sessionHooksSkipped = true
+			}
+			return
+		}
+		major, _, _ := d.tmux.Version()
+		d.sessionHookName = sessionClosedHookName(major)
+	}
+
+	if err := d.tmux.SetHook(d.sessionHookName, sessionClosedHookCommand(d.config.TownRoot)); err != nil {
+		d.logger.Printf("Warning: failed to set tmux %s hook: %v", d.sessionHookName, err)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/KeithWyatt/gongshow/internal/beads"
 	"github.com/KeithWyatt/gongshow/internal/util"
 )
 
@@ -30,6 +31,13 @@ type Config struct {
 
 	// PidFile is the path to the PID file.
 	PidFile string `json:"pid_file"`
+
+	// DelegationGCThreshold is the delegation count above which the daemon
+	// garbage-collects orphaned delegations in the town beads store.
+	DelegationGCThreshold int `json:"delegation_gc_threshold"`
+
+	// DelegationGCInterval is the minimum time between delegation GC passes.
+	DelegationGCInterval time.Duration `json:"delegation_gc_interval"`
 }
 
 // DefaultConfig returns the default daemon configuration.
@@ -40,6 +48,9 @@ func DefaultConfig(townRoot string) *Config {
 		TownRoot:          townRoot,
 		LogFile:           filepath.Join(daemonDir, "daemon.log"),
 		PidFile:           filepath.Join(daemonDir, "daemon.pid"),
+
+		DelegationGCThreshold: beads.DefaultDelegationGCThreshold,
+		DelegationGCInterval:  time.Hour,
 	}
 }
 
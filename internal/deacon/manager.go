@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/KeithWyatt/gongshow/internal/beads"
 	"github.com/KeithWyatt/gongshow/internal/claude"
 	"github.com/KeithWyatt/gongshow/internal/config"
 	"github.com/KeithWyatt/gongshow/internal/constants"
@@ -45,7 +46,27 @@ func (m *Manager) SessionName() string {
 
 // deaconDir returns the working directory for the deacon.
 func (m *Manager) deaconDir() string {
-	return filepath.Join(m.townRoot, "deacon")
+	return WorkDir(m.townRoot)
+}
+
+// WorkDir returns the working directory of the deacon of the town at
+// townRoot.
+func WorkDir(townRoot string) string {
+	return filepath.Join(townRoot, "deacon")
+}
+
+// BuildStartCommand returns the command the deacon of the town at townRoot
+// is started with.
+func BuildStartCommand(townRoot string) (string, error) {
+	return buildStartCommand(townRoot, "")
+}
+
+func buildStartCommand(townRoot, agentOverride string) (string, error) {
+	command, err := config.BuildAgentStartupCommandWithAgentOverride("deacon", "", townRoot, "", "", agentOverride)
+	if err != nil {
+		return "", fmt.Errorf("building startup command: %w", err)
+	}
+	return command, nil
 }
 
 // Start starts the deacon session.
@@ -81,9 +102,9 @@ func (m *Manager) Start(agentOverride string) error {
 
 	// Build startup command first
 	// Restarts are handled by daemon via ensureDeaconRunning on each heartbeat
-	startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("deacon", "", m.townRoot, "", "", agentOverride)
+	startupCmd, err := buildStartCommand(m.townRoot, agentOverride)
 	if err != nil {
-		return fmt.Errorf("building startup command: %w", err)
+		return err
 	}
 
 	// Create session with command directly to avoid send-keys race condition.
@@ -94,10 +115,11 @@ func (m *Manager) Start(agentOverride string) error {
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	roleConfig, _ := beads.LoadRoleConfig(m.townRoot, "deacon")
+	envVars := beads.AgentEnv(config.AgentEnvConfig{
 		Role:     "deacon",
 		TownRoot: m.townRoot,
-	})
+	}, sessionID, roleConfig)
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
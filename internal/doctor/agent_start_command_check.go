@@ -0,0 +1,229 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/session"
+	"github.com/KeithWyatt/gongshow/internal/witness"
+)
+
+// Roles whose role beads may set a start_command, by where their agents run.
+var (
+	townAgentRoles = []string{"mayor", "deacon", "dog"}
+	rigAgentRoles  = []string{"witness", "refinery", "polecat", "crew"}
+)
+
+// startCommandAgentName stands in for the polecat or crew member name when
+// expanding their start commands.
+const startCommandAgentName = "doctor"
+
+// shellBuiltins are command words that don't need to be in $PATH.
+var shellBuiltins = map[string]bool{
+	"cd": true, "export": true, "source": true, ".": true, "set": true,
+	"unset": true, "true": true, "false": true, ":": true, "echo": true,
+	"printf": true, "test": true, "[": true,
+}
+
+// AgentStartCommandCheck verifies that each role bead's start_command
+// expands without unknown placeholders and runs a command that exists.
+// Agents are started with the command as expanded, so a typo such as
+// {rigg} or a missing binary makes the session exit before the agent comes
+// up, with nothing to say why.
+type AgentStartCommandCheck struct {
+	BaseCheck
+
+	// roleConfig loads a role's config (nil if it has none) and lookPath
+	// finds a command; both are replaced in tests.
+	roleConfig func(townRoot, role string) (*beads.RoleConfig, error)
+	lookPath   func(file string) (string, error)
+}
+
+// NewAgentStartCommandCheck creates a new agent start command check.
+func NewAgentStartCommandCheck() *AgentStartCommandCheck {
+	return &AgentStartCommandCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "agent-start-command",
+			CheckDescription: "Verify role start_command templates expand and name an installed command",
+			CheckCategory:    CategoryConfig,
+		},
+		roleConfig: loadRoleConfig,
+		lookPath:   exec.LookPath,
+	}
+}
+
+// loadRoleConfig reads a role bead's config. It doesn't use
+// Beads.GetRoleConfig, which warns about unknown placeholders on stderr;
+// this check reports them itself.
+func loadRoleConfig(townRoot, role string) (*beads.RoleConfig, error) {
+	bd := beads.New(beads.GetTownBeadsPath(townRoot))
+	issue, err := bd.Show(beads.RoleBeadIDTown(role))
+	if errors.Is(err, beads.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return beads.ParseRoleConfig(issue.Description), nil
+}
+
+// Run expands every configured start_command with the inputs its agents
+// would be started with. With --rig, only that rig's agents are checked.
+func (c *AgentStartCommandCheck) Run(ctx *CheckContext) *CheckResult {
+	roles := rigAgentRoles
+	rigs := []string{ctx.RigName}
+	if ctx.RigName == "" {
+		roles = append(append([]string{}, townAgentRoles...), rigAgentRoles...)
+		discovered, err := discoverRigs(ctx.TownRoot)
+		if err != nil {
+			return &CheckResult{
+				Name:     c.Name(),
+				Status:   StatusWarning,
+				Message:  "Could not read rigs.json",
+				Details:  []string{err.Error()},
+				Category: c.Category(),
+			}
+		}
+		sort.Strings(discovered)
+		rigs = discovered
+	}
+
+	var problems []string
+	configured := 0
+	for _, role := range roles {
+		cfg, err := c.roleConfig(ctx.TownRoot, role)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: loading role config: %v", role, err))
+			continue
+		}
+		if cfg == nil || cfg.StartCommand == "" {
+			continue
+		}
+		configured++
+
+		if unknown := (&beads.RoleConfig{StartCommand: cfg.StartCommand}).UnknownPlaceholders(); len(unknown) > 0 {
+			problems = append(problems, fmt.Sprintf("%s: start_command has unknown placeholder(s) %s", role, strings.Join(unknown, ", ")))
+			continue
+		}
+
+		if !slices.Contains(rigAgentRoles, role) {
+			problems = append(problems, c.checkCommand(role, roleStartCommand(ctx.TownRoot, "", role, cfg))...)
+			continue
+		}
+		for _, rig := range rigs {
+			command, err := rigRoleStartCommand(ctx.TownRoot, rig, role, cfg)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s (%s): %v", role, rig, err))
+				continue
+			}
+			problems = append(problems, c.checkCommand(fmt.Sprintf("%s (%s)", role, rig), command)...)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusError,
+			Message:  fmt.Sprintf("%d start_command problem(s); affected agents will fail to start", len(problems)),
+			Details:  problems,
+			FixHint:  "Fix start_command in the role bead (bd edit hq-<role>-role) or install the missing command",
+			Category: c.Category(),
+		}
+	}
+	if configured == 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusOK,
+			Message:  "No role sets a start_command (agents use the configured runtime)",
+			Category: c.Category(),
+		}
+	}
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusOK,
+		Message:  fmt.Sprintf("%d role start_command(s) expand cleanly", configured),
+		Category: c.Category(),
+	}
+}
+
+// checkCommand reports the commands in an expanded start command that
+// aren't in $PATH. who names the agent in the messages.
+func (c *AgentStartCommandCheck) checkCommand(who, command string) []string {
+	var problems []string
+	for _, name := range commandNames(command) {
+		if _, err := c.lookPath(name); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %q not found in $PATH", who, name))
+		}
+	}
+	return problems
+}
+
+// rigRoleStartCommand expands a rig-level role's start_command for rig.
+// The witness goes through the same builder its manager starts it with.
+func rigRoleStartCommand(townRoot, rig, role string, cfg *beads.RoleConfig) (string, error) {
+	if role == "witness" {
+		return witness.BuildStartCommand(filepath.Join(townRoot, rig), rig, townRoot, cfg)
+	}
+	return roleStartCommand(townRoot, rig, role, cfg), nil
+}
+
+// roleStartCommand expands a start_command with the values the daemon
+// starts the role's agents with, using a stand-in name for polecat and crew.
+func roleStartCommand(townRoot, rig, role string, cfg *beads.RoleConfig) string {
+	vars := beads.RoleVars{
+		Town:      townRoot,
+		Rig:       rig,
+		Role:      role,
+		ConfigDir: filepath.Join(townRoot, "config"),
+		BeadsDir:  beads.ResolveBeadsDir(townRoot),
+		Actor:     role,
+	}
+	if rig != "" {
+		vars.RigDir = filepath.Join(townRoot, rig)
+		vars.BeadsDir = beads.ResolveBeadsDir(vars.RigDir)
+		vars.Actor = rig + "/" + role
+	}
+	switch role {
+	case "mayor":
+		vars.SessionName = session.MayorSessionName()
+	case "deacon":
+		vars.SessionName = session.DeaconSessionName()
+	case "refinery":
+		vars.SessionName = session.RefinerySessionName(rig)
+	case "polecat":
+		vars.Name = startCommandAgentName
+		vars.SessionName = session.PolecatSessionName(rig, vars.Name)
+		vars.Actor = rig + "/polecats/" + vars.Name
+	case "crew":
+		vars.Name = startCommandAgentName
+		vars.SessionName = session.CrewSessionName(rig, vars.Name)
+		vars.Actor = rig + "/crew/" + vars.Name
+	}
+	return beads.ExpandRoleVars(cfg.StartCommand, vars)
+}
+
+// commandNames returns the command word of each command in a shell command
+// list (split on &&, || and ;), skipping leading exec/env and VAR=value
+// assignments, and shell builtins.
+func commandNames(command string) []string {
+	var names []string
+	for _, part := range strings.FieldsFunc(strings.NewReplacer("&&", ";", "||", ";").Replace(command), func(r rune) bool { return r == ';' }) {
+		for _, word := range strings.Fields(part) {
+			word = strings.Trim(word, `"'`)
+			if word == "exec" || word == "env" || word == "command" || (strings.Contains(word, "=") && !strings.HasPrefix(word, "=")) {
+				continue
+			}
+			if !shellBuiltins[word] {
+				names = append(names, word)
+			}
+			break
+		}
+	}
+	return names
+}
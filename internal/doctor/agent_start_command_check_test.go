@@ -0,0 +1,122 @@
+package doctor
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+)
+
+// newTestStartCommandCheck returns a check whose roles have the given
+// start commands and whose $PATH holds only installed.
+func newTestStartCommandCheck(startCommands map[string]string, installed ...string) *AgentStartCommandCheck {
+	check := NewAgentStartCommandCheck()
+	check.roleConfig = func(_, role string) (*beads.RoleConfig, error) {
+		if cmd, ok := startCommands[role]; ok {
+			return &beads.RoleConfig{StartCommand: cmd}, nil
+		}
+		return nil, nil
+	}
+	check.lookPath = func(file string) (string, error) {
+		for _, name := range installed {
+			if name == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+	return check
+}
+
+func TestAgentStartCommandCheck_OK(t *testing.T) {
+	townRoot := t.TempDir()
+	setupRigConfig(t, townRoot, []string{"gongshow"})
+
+	check := newTestStartCommandCheck(map[string]string{
+		"witness": "cd {rigdir} && exec claude --actor {actor}",
+		"mayor":   "GT_ROLE={role} exec claude",
+	}, "claude")
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestAgentStartCommandCheck_UnknownPlaceholder(t *testing.T) {
+	townRoot := t.TempDir()
+	setupRigConfig(t, townRoot, []string{"gongshow"})
+
+	check := newTestStartCommandCheck(map[string]string{
+		"refinery": "exec claude --dir {undefined_var} --home ${HOME}",
+	}, "claude")
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError {
+		t.Fatalf("Status = %v, want Error", result.Status)
+	}
+	want := []string{"refinery: start_command has unknown placeholder(s) {undefined_var}"}
+	if !reflect.DeepEqual(result.Details, want) {
+		t.Errorf("Details = %v, want %v", result.Details, want)
+	}
+}
+
+func TestAgentStartCommandCheck_MissingBinary(t *testing.T) {
+	townRoot := t.TempDir()
+	setupRigConfig(t, townRoot, []string{"gongshow", "other"})
+
+	check := newTestStartCommandCheck(map[string]string{
+		"witness": "cd {rigdir} && exec {rigdir}/bin/agent --rig {rig}",
+		"deacon":  "exec claude",
+	}, "claude")
+	result := check.Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusError {
+		t.Fatalf("Status = %v, want Error", result.Status)
+	}
+	want := []string{
+		`witness (gongshow): "` + townRoot + `/gongshow/bin/agent" not found in $PATH`,
+		`witness (other): "` + townRoot + `/other/bin/agent" not found in $PATH`,
+	}
+	if !reflect.DeepEqual(result.Details, want) {
+		t.Errorf("Details = %v, want %v", result.Details, want)
+	}
+}
+
+func TestAgentStartCommandCheck_RigFilter(t *testing.T) {
+	townRoot := t.TempDir()
+	setupRigConfig(t, townRoot, []string{"gongshow", "other"})
+
+	// Only the filtered rig's agents are checked: not the other rig, and
+	// not town-level roles.
+	check := newTestStartCommandCheck(map[string]string{
+		"crew":  "exec agent-{rig}",
+		"mayor": "exec missing-mayor-agent",
+	}, "agent-gongshow")
+	result := check.Run(&CheckContext{TownRoot: townRoot, RigName: "gongshow"})
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK: %v", result.Status, result.Details)
+	}
+
+	result = check.Run(&CheckContext{TownRoot: townRoot, RigName: "other"})
+	if result.Status != StatusError || len(result.Details) != 1 || !strings.Contains(result.Details[0], `crew (other): "agent-other"`) {
+		t.Errorf("other rig: %v %v, want agent-other reported", result.Status, result.Details)
+	}
+}
+
+func TestCommandNames(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"exec claude --resume", []string{"claude"}},
+		{"cd /town/rig && exec claude", []string{"claude"}},
+		{"GT_ROLE=witness BD_ACTOR=x env claude", []string{"claude"}},
+		{"export A=1; tmux-helper start || true", []string{"tmux-helper"}},
+		{`"/opt/agent bin/run"`, []string{"/opt/agent"}},
+	}
+	for _, tt := range tests {
+		if got := commandNames(tt.command); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("commandNames(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// ConfigSchemaCheck verifies the town config files (config/messaging.json,
+// settings/config.json, ...) match their schemas. Unknown fields are
+// reported: Go's JSON decoding ignores them, so a misspelled key in a
+// hand-edited file otherwise has no effect and no warning.
+type ConfigSchemaCheck struct {
+	BaseCheck
+}
+
+// NewConfigSchemaCheck creates a new config schema check.
+func NewConfigSchemaCheck() *ConfigSchemaCheck {
+	return &ConfigSchemaCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "config-schema",
+			CheckDescription: "Verify town config files match their schemas",
+			CheckCategory:    CategoryConfig,
+		},
+	}
+}
+
+// Run checks each town config file that exists.
+func (c *ConfigSchemaCheck) Run(ctx *CheckContext) *CheckResult {
+	files := config.TownConfigFiles()
+	var problems []string
+	for _, f := range files {
+		if err := f.CheckSchema(ctx.TownRoot); err != nil {
+			rel, _ := filepath.Rel(ctx.TownRoot, f.Path(ctx.TownRoot))
+			problems = append(problems, fmt.Sprintf("%s: %v", rel, err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &CheckResult{
+			Name:     c.Name(),
+			Status:   StatusError,
+			Message:  fmt.Sprintf("%d config file(s) don't match their schema", len(problems)),
+			Details:  problems,
+			FixHint:  "Fix the files by hand, or set values with 'gt config set <key> <value>'",
+			Category: c.Category(),
+		}
+	}
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusOK,
+		Message:  fmt.Sprintf("%d town config file(s) match their schemas", len(files)),
+		Category: c.Category(),
+	}
+}
@@ -1,5 +1,7 @@
 package doctor
 
+import "github.com/KeithWyatt/gongshow/internal/events"
+
 // Doctor manages and executes health checks.
 type Doctor struct {
 	checks []Check
@@ -70,6 +72,7 @@ func (d *Doctor) Fix(ctx *CheckContext) *Report {
 		// Attempt fix if check failed and is fixable
 		if result.Status != StatusOK && check.CanFix() {
 			err := check.Fix(ctx)
+			_ = events.LogAudit(events.TypeDoctorFix, "doctor", events.DoctorFixPayload(check.Name(), err))
 			if err == nil {
 				// Re-run check to verify fix worked
 				result = check.Run(ctx)
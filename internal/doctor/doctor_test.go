@@ -2,7 +2,12 @@ package doctor
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
 )
 
 // mockCheck is a test check that can be configured to return any status.
@@ -54,6 +59,7 @@ func TestCheckStatus_String(t *testing.T) {
 		{StatusOK, "OK"},
 		{StatusWarning, "Warning"},
 		{StatusError, "Error"},
+		{StatusUnavailable, "SKIP"},
 		{CheckStatus(99), "Unknown"},
 	}
 
@@ -127,6 +133,13 @@ func TestReport_Add(t *testing.T) {
 	if r.Summary.Total != 3 || r.Summary.Errors != 1 {
 		t.Errorf("After adding Error: Total=%d, Errors=%d", r.Summary.Total, r.Summary.Errors)
 	}
+
+	// Add an unavailable check: skipped, neither a warning nor an error
+	r.Add(&CheckResult{Name: "test4", Status: StatusUnavailable})
+	if r.Summary.Total != 4 || r.Summary.Skipped != 1 || r.Summary.Warnings != 1 || r.Summary.Errors != 1 {
+		t.Errorf("After adding Unavailable: Total=%d, Skipped=%d, Warnings=%d, Errors=%d",
+			r.Summary.Total, r.Summary.Skipped, r.Summary.Warnings, r.Summary.Errors)
+	}
 }
 
 func TestReport_HasErrors(t *testing.T) {
@@ -292,6 +305,15 @@ func TestDoctor_Run(t *testing.T) {
 }
 
 func TestDoctor_Fix(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
 	d := NewDoctor()
 
 	okCheck := newMockCheck("ok", StatusOK)
@@ -305,7 +327,7 @@ func TestDoctor_Fix(t *testing.T) {
 	unfixableCheck.fixable = false
 	d.Register(unfixableCheck)
 
-	ctx := &CheckContext{TownRoot: "/test"}
+	ctx := &CheckContext{TownRoot: townRoot}
 	report := d.Fix(ctx)
 
 	// OK check should remain OK
@@ -328,6 +350,65 @@ func TestDoctor_Fix(t *testing.T) {
 	if report.Checks[2].Status != StatusError {
 		t.Error("Unfixable check should remain Error")
 	}
+
+	// The fix is recorded in the audit log, and only there.
+	audit, err := os.ReadFile(filepath.Join(townRoot, events.AuditFile))
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(audit)), "\n"); len(lines) != 1 ||
+		!strings.Contains(lines[0], `"type":"doctor_fix"`) || !strings.Contains(lines[0], `"check":"fixable"`) {
+		t.Errorf("audit log = %s, want one doctor_fix for the fixable check", audit)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, events.EventsFile)); !os.IsNotExist(err) {
+		t.Errorf("doctor fix reached the events log: %v", err)
+	}
+}
+
+func TestReport_PrintUnavailable(t *testing.T) {
+	r := NewReport()
+	r.Add(&CheckResult{Name: "orphan-sessions", Status: StatusUnavailable, Message: "tmux is not installed", Category: CategoryCleanup})
+
+	var buf bytes.Buffer
+	r.Print(&buf, false)
+	output := buf.String()
+	for _, want := range []string{"orphan-sessions", "SKIP: tmux is not installed", "1 skipped", "All checks passed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Print() output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "WARNINGS") {
+		t.Errorf("Print() listed an unavailable check as a warning:\n%s", output)
+	}
+	if !r.IsHealthy() {
+		t.Error("IsHealthy() = false for a report with only an unavailable check")
+	}
+}
+
+func TestFailingCheck(t *testing.T) {
+	c := &FailingCheck{
+		BaseCheck: BaseCheck{CheckName: "needs-tmux", CheckCategory: CategoryCleanup},
+		Reason:    "tmux is not installed",
+	}
+
+	result := c.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusUnavailable || result.Message != "tmux is not installed" {
+		t.Errorf("Run() = %+v, want StatusUnavailable with the reason", result)
+	}
+	if result.Name != "needs-tmux" || result.Category != CategoryCleanup {
+		t.Errorf("Run() name, category = %q, %q; want the check's", result.Name, result.Category)
+	}
+	if c.CanFix() {
+		t.Error("FailingCheck.CanFix() = true, want false")
+	}
+
+	// Fix mode leaves it alone too.
+	d := NewDoctor()
+	d.Register(c)
+	report := d.Fix(&CheckContext{TownRoot: t.TempDir()})
+	if report.Summary.Skipped != 1 || report.HasErrors() {
+		t.Errorf("Fix() summary = %+v, want one skipped check", report.Summary)
+	}
 }
 
 func TestBaseCheck(t *testing.T) {
@@ -0,0 +1,121 @@
+package doctor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// maxEventIssueDetails caps the invalid events listed in the check details.
+const maxEventIssueDetails = 10
+
+// EventsCheck audits the events logs (active files and rotated archives,
+// town and per-rig) against the event payload schemas, and reports the invalid events that
+// were recorded at write time as event_invalid warnings.
+type EventsCheck struct {
+	BaseCheck
+}
+
+// NewEventsCheck creates a new events log check.
+func NewEventsCheck() *EventsCheck {
+	return &EventsCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "events",
+			CheckDescription: "Validate the events log against event schemas",
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run validates every events log file, and counts the corrupt lines that
+// gt events repair would move aside (a dry run).
+func (c *EventsCheck) Run(ctx *CheckContext) *CheckResult {
+	files, err := events.LogFiles(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not list events log files",
+			Details: []string{err.Error()},
+		}
+	}
+	if len(files) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No events logged yet",
+		}
+	}
+
+	var total, invalid, malformed, warnings int
+	var details []string
+	for _, path := range files {
+		name := path
+		if rel, err := filepath.Rel(ctx.TownRoot, path); err == nil {
+			name = rel // e.g. gongshow/.events.jsonl for a rig log
+		}
+		report, err := events.ValidateFile(path)
+		if err != nil {
+			details = append(details, fmt.Sprintf("%s: %v", name, err))
+			if report == nil {
+				continue
+			}
+		}
+		total += report.Events
+		invalid += report.Invalid
+		malformed += report.Malformed
+		warnings += report.Warnings
+		for _, issue := range report.Issues {
+			if len(details) >= maxEventIssueDetails {
+				break
+			}
+			details = append(details, fmt.Sprintf("%s:%d: %s: %s",
+				name, issue.Line, issue.Type, strings.Join(issue.Problems, "; ")))
+		}
+	}
+	repair, err := events.Repair(ctx.TownRoot, true)
+	if err != nil {
+		details = append(details, fmt.Sprintf("scanning for corrupt lines: %v", err))
+	} else if repair.Corrupt > 0 {
+		details = append(details, fmt.Sprintf("%d corrupt line(s) would be moved to %s by 'gt events repair'", repair.Corrupt, events.CorruptFile))
+	}
+	if n := events.InvalidEventCount(); n > 0 {
+		details = append(details, fmt.Sprintf("%d invalid event(s) logged by this process", n))
+	}
+
+	if invalid == 0 && malformed == 0 && warnings == 0 && len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("%d event(s) valid across %d file(s)", total, len(files)),
+		}
+	}
+
+	var parts []string
+	if invalid > 0 {
+		parts = append(parts, fmt.Sprintf("%d invalid", invalid))
+	}
+	if malformed > 0 {
+		parts = append(parts, fmt.Sprintf("%d malformed", malformed))
+	}
+	if warnings > 0 {
+		parts = append(parts, fmt.Sprintf("%d validation warning(s)", warnings))
+	}
+	message := fmt.Sprintf("%d event(s) checked", total)
+	if len(parts) > 0 {
+		message += ": " + strings.Join(parts, ", ")
+	}
+	fixHint := "Check the payloads emitted for the listed event types; set GT_EVENTS_STRICT=true to reject invalid events"
+	if repair != nil && repair.Corrupt > 0 {
+		fixHint = "Run 'gt events repair' to move corrupt lines aside; " + fixHint
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: message,
+		Details: details,
+		FixHint: fixHint,
+	}
+}
@@ -0,0 +1,77 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+func TestEventsCheck_Run(t *testing.T) {
+	writeLog := func(t *testing.T, lines ...string) string {
+		t.Helper()
+		tmpDir := t.TempDir()
+		data := strings.Join(lines, "\n") + "\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, events.EventsFile), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return tmpDir
+	}
+
+	t.Run("no events returns OK", func(t *testing.T) {
+		result := NewEventsCheck().Run(&CheckContext{TownRoot: t.TempDir()})
+		if result.Status != StatusOK {
+			t.Errorf("expected StatusOK, got %v: %s", result.Status, result.Message)
+		}
+	})
+
+	t.Run("valid events return OK", func(t *testing.T) {
+		tmpDir := writeLog(t,
+			`{"ts":"2024-01-15T10:00:00Z","type":"spawn","actor":"mayor","payload":{"rig":"gongshow","polecat":"Toast"},"schema_version":1}`,
+			`{"ts":"2024-01-15T10:00:01Z","type":"custom","actor":"mayor","schema_version":1}`,
+		)
+		result := NewEventsCheck().Run(&CheckContext{TownRoot: tmpDir})
+		if result.Status != StatusOK {
+			t.Errorf("expected StatusOK, got %v: %s %v", result.Status, result.Message, result.Details)
+		}
+	})
+
+	t.Run("invalid events warn", func(t *testing.T) {
+		tmpDir := writeLog(t,
+			`{"ts":"2024-01-15T10:00:00Z","type":"spawn","actor":"mayor","payload":{"rig":"gongshow"},"schema_version":1}`,
+			`{"ts":"2024-01-15T10:00:00Z","type":"event_invalid","actor":"mayor","payload":{"event_type":"spawn","problems":["missing \"polecat\""]},"schema_version":1}`,
+		)
+		result := NewEventsCheck().Run(&CheckContext{TownRoot: tmpDir})
+		if result.Status != StatusWarning {
+			t.Fatalf("expected StatusWarning, got %v: %s", result.Status, result.Message)
+		}
+		if !strings.Contains(result.Message, "1 invalid") || !strings.Contains(result.Message, "1 validation warning") {
+			t.Errorf("unexpected message: %s", result.Message)
+		}
+		if len(result.Details) == 0 || !strings.Contains(result.Details[0], `missing "polecat"`) {
+			t.Errorf("unexpected details: %v", result.Details)
+		}
+	})
+
+	t.Run("corrupt lines suggest repair without moving them", func(t *testing.T) {
+		tmpDir := writeLog(t,
+			`{"ts":"2024-01-15T10:00:00Z","type":"custom","actor":"mayor","schema_version":1}`,
+			`{"ts":"2024-01-15T10:00:01Z","type":"cus`,
+		)
+		result := NewEventsCheck().Run(&CheckContext{TownRoot: tmpDir})
+		if result.Status != StatusWarning {
+			t.Fatalf("expected StatusWarning, got %v: %s", result.Status, result.Message)
+		}
+		if !strings.Contains(strings.Join(result.Details, "\n"), "1 corrupt line(s)") {
+			t.Errorf("details don't report the corrupt line: %v", result.Details)
+		}
+		if !strings.Contains(result.FixHint, "gt events repair") {
+			t.Errorf("fix hint doesn't suggest repair: %s", result.FixHint)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, events.CorruptFile)); !os.IsNotExist(err) {
+			t.Errorf("doctor moved corrupt lines; it should only count them")
+		}
+	})
+}
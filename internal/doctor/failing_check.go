@@ -0,0 +1,24 @@
+package doctor
+
+import "os/exec"
+
+// lookPath is exec.LookPath; a var for tests.
+var lookPath = exec.LookPath
+
+// FailingCheck stands in for a check that can't run on this machine, e.g.
+// one that needs tmux where tmux isn't installed. Its Run reports
+// StatusUnavailable with Reason, without attempting the check.
+type FailingCheck struct {
+	BaseCheck
+	Reason string
+}
+
+// Run reports the check as unavailable.
+func (c *FailingCheck) Run(ctx *CheckContext) *CheckResult {
+	return &CheckResult{
+		Name:     c.Name(),
+		Status:   StatusUnavailable,
+		Message:  c.Reason,
+		Category: c.Category(),
+	}
+}
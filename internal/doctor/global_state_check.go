@@ -4,9 +4,8 @@
 package doctor
 
 import (
+	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/KeithWyatt/gongshow/internal/shell"
 	"github.com/KeithWyatt/gongshow/internal/state"
@@ -65,19 +64,18 @@ func (c *GlobalStateCheck) Run(ctx *CheckContext) *CheckResult {
 		details = append(details, "Machine ID: "+s.MachineID)
 	}
 
-	rcPath := shell.RCFilePath(shell.DetectShell())
-	if hasShellIntegration(rcPath) {
-		details = append(details, "Shell integration: installed ("+rcPath+")")
-	} else {
+	status, err := shell.Verify()
+	switch {
+	case err != nil:
+		warnings = append(warnings, "Cannot verify shell integration: "+err.Error())
+	case !status.Installed():
 		warnings = append(warnings, "Shell integration not installed")
-	}
-
-	hookPath := filepath.Join(state.ConfigDir(), "shell-hook.sh")
-	if _, err := os.Stat(hookPath); err == nil {
-		details = append(details, "Hook script: present")
-	} else {
-		if hasShellIntegration(rcPath) {
-			errors = append(errors, "Hook script missing but shell integration installed")
+	default:
+		details = append(details, fmt.Sprintf("Shell integration: %s (%s)", status.Block, status.RCFile))
+		details = append(details, fmt.Sprintf("Hook script: %s", status.Hook))
+		errors = append(errors, status.Problems...)
+		if status.Hook == shell.HookStale {
+			warnings = append(warnings, "Hook script out of date")
 		}
 	}
 
@@ -86,7 +84,10 @@ func (c *GlobalStateCheck) Run(ctx *CheckContext) *CheckResult {
 	if len(errors) > 0 {
 		result.Status = StatusError
 		result.Message = errors[0]
-		result.FixHint = "Run: gt install --shell"
+		result.FixHint = "Run: gt install --shell --repair"
+		if len(errors) > 1 {
+			result.Details = append(result.Details, errors[1:]...)
+		}
 	} else if len(warnings) > 0 {
 		result.Status = StatusWarning
 		result.Message = warnings[0]
@@ -101,11 +102,3 @@ func (c *GlobalStateCheck) Run(ctx *CheckContext) *CheckResult {
 
 	return result
 }
-
-func hasShellIntegration(rcPath string) bool {
-	data, err := os.ReadFile(rcPath)
-	if err != nil {
-		return false
-	}
-	return strings.Contains(string(data), "GongShow Integration")
-}
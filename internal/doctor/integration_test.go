@@ -86,7 +86,7 @@ func TestIntegrationOrphanSessionDetection(t *testing.T) {
 	createTestRig(t, townRoot, "gongshow")
 	createTestRig(t, townRoot, "niflheim")
 
-	check := NewOrphanSessionCheck()
+	check := newOrphanSessionCheck()
 	ctx := &CheckContext{TownRoot: townRoot}
 
 	for _, tt := range tests {
@@ -654,6 +654,9 @@ issue-prefix: "hq"
 	// Initialize git repo
 	initGitRepoForIntegration(t, townRoot)
 
+	// Doctor records its fixes in the town it is run from.
+	t.Chdir(townRoot)
+
 	return townRoot
 }
 
@@ -10,6 +10,7 @@ import (
 	"syscall"
 
 	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/proc"
 	"github.com/KeithWyatt/gongshow/internal/session"
 	"github.com/KeithWyatt/gongshow/internal/tmux"
 )
@@ -35,8 +36,17 @@ func (r *realSessionLister) ListSessions() ([]string, error) {
 	return r.t.ListSessions()
 }
 
-// NewOrphanSessionCheck creates a new orphan session check.
-func NewOrphanSessionCheck() *OrphanSessionCheck {
+// NewOrphanSessionCheck creates a new orphan session check, or a
+// FailingCheck in its place when tmux isn't installed.
+func NewOrphanSessionCheck() Check {
+	check := newOrphanSessionCheck()
+	if _, err := lookPath("tmux"); err != nil {
+		return &FailingCheck{BaseCheck: check.BaseCheck, Reason: "tmux is not installed"}
+	}
+	return check
+}
+
+func newOrphanSessionCheck() *OrphanSessionCheck {
 	return &OrphanSessionCheck{
 		FixableCheck: FixableCheck{
 			BaseCheck: BaseCheck{
@@ -50,7 +60,7 @@ func NewOrphanSessionCheck() *OrphanSessionCheck {
 
 // NewOrphanSessionCheckWithSessionLister creates a check with a custom session lister (for testing).
 func NewOrphanSessionCheckWithSessionLister(lister SessionLister) *OrphanSessionCheck {
-	check := NewOrphanSessionCheck()
+	check := newOrphanSessionCheck()
 	check.sessionLister = lister
 	return check
 }
@@ -278,27 +288,68 @@ type ProcessLister interface {
 	GetParentPID(pid int) (int, error)
 }
 
-// realProcessLister implements ProcessLister using actual system commands.
-type realProcessLister struct{}
+// realProcessLister implements ProcessLister from one snapshot of the
+// process table, rather than a scan for each question, and from tmux.
+type realProcessLister struct {
+	table *proc.Table
+}
+
+// refresher is a ProcessLister answering from a snapshot, which is retaken
+// for each Run and Fix so that Fix sees processes as they are now.
+type refresher interface {
+	refresh()
+}
+
+// refresh drops the snapshot; the next question takes another.
+func (r *realProcessLister) refresh() {
+	r.table = nil
+}
+
+// snapshot returns the process table, taking a snapshot if there is none.
+// Zombies are left out: they have exited already, and are the
+// zombie-processes check's to report.
+func (r *realProcessLister) snapshot() (*proc.Table, error) {
+	if r.table == nil {
+		table, err := proc.Snapshot()
+		if err != nil {
+			return nil, fmt.Errorf("reading process table: %w", err)
+		}
+		r.table = table.WithoutZombies()
+	}
+	return r.table, nil
+}
 
 func (r *realProcessLister) ListTmuxServerPIDs() ([]int, error) {
 	var pids []int
-	// Find tmux server processes using ps.
-	// Match "tmux", "tmux: server", or paths ending in /tmux.
-	// On Linux, long-running tmux servers show as "tmux: server" in comm field.
-	out, err := exec.Command("sh", "-c", `ps ax -o pid,comm | awk '$2 == "tmux" || $2 ~ /^tmux:/ || $2 ~ /\/tmux$/ { print $1 }'`).Output()
+	seen := make(map[int]bool)
+
+	// Ask the default server for its PID over its socket. This works however
+	// tmux was started and whatever its process is called.
+	if pid, err := tmux.NewTmux().GetTmuxServerPID(); err == nil {
+		pids = append(pids, pid)
+		seen[pid] = true
+	}
+
+	// Also find servers on other sockets (tmux -L/-S) in the process table.
+	table, err := r.snapshot()
 	if err != nil {
-		return pids, nil // No tmux server running
+		return pids, nil
 	}
-	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
-		var pid int
-		if _, err := fmt.Sscanf(line, "%d", &pid); err == nil {
-			pids = append(pids, pid)
+	for _, p := range table.Processes() {
+		if isTmuxCommand(p.Comm) && !seen[p.PID] {
+			pids = append(pids, p.PID)
+			seen[p.PID] = true
 		}
 	}
 	return pids, nil
 }
 
+// isTmuxCommand reports whether a command name is tmux's: "tmux", "tmux:
+// server" as long-running servers show on Linux, or a path ending in /tmux.
+func isTmuxCommand(comm string) bool {
+	return comm == "tmux" || strings.HasPrefix(comm, "tmux:") || strings.HasSuffix(comm, "/tmux")
+}
+
 func (r *realProcessLister) ListPanePIDs() ([]int, error) {
 	var pids []int
 	// Use -a flag to get ALL pane PIDs across ALL sessions in one command.
@@ -322,50 +373,60 @@ func (r *realProcessLister) ListPanePIDs() ([]int, error) {
 }
 
 func (r *realProcessLister) ListRuntimeProcesses() ([]processInfo, error) {
-	var procs []processInfo
-	out, err := exec.Command("ps", "-eo", "pid,ppid,comm").Output()
+	table, err := r.snapshot()
 	if err != nil {
 		return nil, err
 	}
 
-	// Regex to match runtime CLI processes (not Claude.app)
-	runtimePattern := regexp.MustCompile(`(?i)(^claude$|/claude$|^claude-code$|/claude-code$|^codex$|/codex$)`)
-	excludePattern := regexp.MustCompile(`(?i)(Claude\.app|claude-native|chrome-native)`)
-
-	for _, line := range strings.Split(string(out), "\n") {
-		fields := strings.Fields(line)
-		if len(fields) < 3 {
-			continue
-		}
-		cmd := strings.Join(fields[2:], " ")
-		if excludePattern.MatchString(cmd) {
-			continue
-		}
-		if !runtimePattern.MatchString(cmd) {
+	var procs []processInfo
+	for _, p := range table.Processes() {
+		cmd, ok := runtimeCommand(p)
+		if !ok {
 			continue
 		}
-		var pid, ppid int
-		if _, err := fmt.Sscanf(fields[0], "%d", &pid); err != nil {
-			continue
+		// Other users' environments can't be read: those are listed unattributed.
+		agent, _ := proc.Identity(p.PID)
+		// No start time (0) just means Fix can't check for PID reuse.
+		procs = append(procs, processInfo{pid: p.PID, ppid: p.PPID, cmd: cmd, startTime: p.StartTime, agent: agent})
+	}
+	return procs, nil
+}
+
+// runtimePattern matches runtime CLI processes (not Claude.app), and
+// runtimeExcludePattern the apps and helpers with similar names.
+var (
+	runtimePattern        = regexp.MustCompile(`(?i)(^claude$|/claude$|^claude-code$|/claude-code$|^codex$|/codex$)`)
+	runtimeExcludePattern = regexp.MustCompile(`(?i)(Claude\.app|claude-native|chrome-native)`)
+)
+
+// runtimeCommand returns the name p is listed by if it is a runtime CLI
+// process: its command name or, failing that, the executable it was run
+// as. On macOS the command name is short, and only the path tells the
+// Claude app from the CLI.
+func runtimeCommand(p proc.Process) (string, bool) {
+	names := []string{p.Comm}
+	if len(p.Argv) > 0 {
+		names = append(names, p.Argv[0])
+	}
+	for _, name := range names {
+		if runtimeExcludePattern.MatchString(name) {
+			return "", false
 		}
-		if _, err := fmt.Sscanf(fields[1], "%d", &ppid); err != nil {
-			continue
+	}
+	for _, name := range names {
+		if runtimePattern.MatchString(name) {
+			return name, true
 		}
-		procs = append(procs, processInfo{pid: pid, ppid: ppid, cmd: cmd})
 	}
-	return procs, nil
+	return "", false
 }
 
 func (r *realProcessLister) GetParentPID(pid int) (int, error) {
-	out, err := exec.Command("ps", "-p", fmt.Sprintf("%d", pid), "-o", "ppid=").Output() //nolint:gosec // G204: PID is numeric
+	table, err := r.snapshot()
 	if err != nil {
-		return 0, err
+		return proc.GetParentPID(pid)
 	}
-	var ppid int
-	if _, err := fmt.Sscanf(strings.TrimSpace(string(out)), "%d", &ppid); err != nil {
-		return 0, err
-	}
-	return ppid, nil
+	return table.ParentPID(pid)
 }
 
 // NewOrphanProcessCheck creates a new orphan process check.
@@ -391,6 +452,8 @@ func NewOrphanProcessCheckWithProcessLister(lister ProcessLister) *OrphanProcess
 
 // Run checks for runtime processes running outside tmux.
 func (c *OrphanProcessCheck) Run(ctx *CheckContext) *CheckResult {
+	c.refreshProcesses()
+
 	// Get list of tmux session PIDs
 	tmuxPIDs, err := c.getTmuxSessionPIDs()
 	if err != nil {
@@ -448,7 +511,7 @@ func (c *OrphanProcessCheck) Run(ctx *CheckContext) *CheckResult {
 	details = append(details, "These processes have no tmux pane ancestor (checked 8 levels).")
 	details = append(details, "Orphaned processes detected:")
 	for _, proc := range outsideTmux {
-		details = append(details, fmt.Sprintf("  PID %d: %s (parent: %d)", proc.pid, proc.cmd, proc.ppid))
+		details = append(details, fmt.Sprintf("  PID %d: %s", proc.pid, proc.describe()))
 	}
 
 	return &CheckResult{
@@ -461,9 +524,28 @@ func (c *OrphanProcessCheck) Run(ctx *CheckContext) *CheckResult {
 }
 
 type processInfo struct {
-	pid  int
-	ppid int
-	cmd  string
+	pid       int
+	ppid      int
+	cmd       string
+	startTime uint64             // See proc.StartTime; 0 if unknown
+	agent     proc.AgentIdentity // From its environment; zero if unknown
+}
+
+// describe says what the process is, and which agent it was started for
+// if that is known: "claude (agent gongshow/polecats/Toast, parent 1)".
+func (p processInfo) describe() string {
+	if p.agent.IsZero() {
+		return fmt.Sprintf("%s (parent %d)", p.cmd, p.ppid)
+	}
+	return fmt.Sprintf("%s (agent %s, parent %d)", p.cmd, p.agent, p.ppid)
+}
+
+// refreshProcesses has the lister take a new snapshot of the process
+// table, if it answers from one.
+func (c *OrphanProcessCheck) refreshProcesses() {
+	if r, ok := c.processLister.(refresher); ok {
+		r.refresh()
+	}
 }
 
 // getTmuxSessionPIDs returns PIDs of all tmux server processes and pane shell PIDs.
@@ -498,47 +580,39 @@ func (c *OrphanProcessCheck) getTmuxSessionPIDs() (map[int]bool, error) { //noli
 // process trees (tmux -> shell -> shell -> ... -> claude).
 const maxAncestryDepth = 8
 
-func (c *OrphanProcessCheck) isOrphanProcess(proc processInfo, tmuxPIDs map[int]bool) bool {
-	// Walk up the process tree looking for a tmux pane ancestor.
-	// We check up to maxAncestryDepth levels to avoid infinite loops
-	// while still catching deep process trees.
-
-	// Start by getting the CURRENT parent PID (not the cached one from proc.ppid)
-	// This ensures we catch processes that were reparented between Run() and Fix().
-	currentPPID, err := c.processLister.GetParentPID(proc.pid)
-	if err != nil {
-		// Process may have exited, use cached ppid as fallback
-		currentPPID = proc.ppid
+func (c *OrphanProcessCheck) isOrphanProcess(p processInfo, tmuxPIDs map[int]bool) bool {
+	// Walk up the process tree looking for a tmux pane ancestor, up to
+	// maxAncestryDepth levels to catch deep process trees.
+	//
+	// The walk starts from the CURRENT parent PID (not the cached one from
+	// p.ppid), so processes reparented between Run() and Fix() are caught.
+	// If the process has exited, the cached ppid is the fallback.
+	parentOf := func(pid int) (int, error) {
+		ppid, err := c.processLister.GetParentPID(pid)
+		if err != nil && pid == p.pid {
+			return p.ppid, nil
+		}
+		return ppid, err
 	}
-
-	visited := make(map[int]bool)
-
-	for depth := 0; depth < maxAncestryDepth && currentPPID > 1 && !visited[currentPPID]; depth++ {
-		visited[currentPPID] = true
-
-		// Check if this is a tmux pane PID
-		if tmuxPIDs[currentPPID] {
+	// A failed lookup ends the walk; whatever was found before it still counts.
+	chain, _ := proc.GetParentChainWith(p.pid, maxAncestryDepth, parentOf)
+	for _, ancestor := range chain {
+		if tmuxPIDs[ancestor] {
 			return false // Has tmux pane ancestor, not orphaned
 		}
-
-		// Get parent's parent
-		nextPPID, err := c.processLister.GetParentPID(currentPPID)
-		if err != nil {
-			break
-		}
-		currentPPID = nextPPID
 	}
-
 	return true // No tmux pane ancestor found within maxAncestryDepth levels
 }
 
 // Fix kills all orphaned processes that were detected during Run().
-// Safety: Each process is re-verified to have no tmux pane ancestor before killing.
+// Safety: Each process is re-verified to have no tmux pane ancestor before killing,
+// and skipped if its PID now belongs to a process started since Run().
 // If ctx.DryRun is true, reports what would be killed without actually killing.
 func (c *OrphanProcessCheck) Fix(ctx *CheckContext) error {
 	if len(c.orphanProcesses) == 0 {
 		return nil
 	}
+	c.refreshProcesses()
 
 	// Re-fetch current pane PIDs for safety verification.
 	// This ensures we don't kill a process that became parented by tmux
@@ -563,6 +637,12 @@ func (c *OrphanProcessCheck) Fix(ctx *CheckContext) error {
 	var lastErr error
 
 	for _, proc := range c.orphanProcesses {
+		// The process may have exited and its PID gone to another since Run().
+		if proc.reused() {
+			fmt.Printf("  Warning: PID %d (%s) now belongs to a newer process, not killing it\n", proc.pid, proc.cmd)
+			continue
+		}
+
 		// Re-verify this process is still orphaned before killing.
 		// This is a critical safety check.
 		if !c.isOrphanProcess(proc, panePIDSet) {
@@ -589,6 +669,8 @@ func (c *OrphanProcessCheck) Fix(ctx *CheckContext) error {
 			lastErr = fmt.Errorf("failed to kill PID %d: %w", proc.pid, err)
 			continue
 		}
+		_ = events.LogAudit(events.TypeKill, "gt doctor",
+			events.KillPayload("", fmt.Sprintf("PID %d (%s)", proc.pid, proc.cmd), "orphan cleanup"))
 		killed++
 	}
 
@@ -606,6 +688,20 @@ func (c *OrphanProcessCheck) Fix(ctx *CheckContext) error {
 	return nil
 }
 
+// reused reports whether p's PID now belongs to a process that started
+// after p was listed. An unknown start time, or a process that has exited,
+// is not taken as reuse.
+func (p processInfo) reused() bool {
+	if p.startTime == 0 {
+		return false
+	}
+	start, err := processStartTime(p.pid)
+	return err == nil && start != p.startTime
+}
+
+// processStartTime wraps proc.StartTime for testability.
+var processStartTime = proc.StartTime
+
 // syscallKill wraps syscall.Kill for testability.
 var syscallKill = func(pid int, sig syscall.Signal) error {
 	return syscall.Kill(pid, sig)
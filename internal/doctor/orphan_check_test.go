@@ -2,10 +2,15 @@ package doctor
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"syscall"
 	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/proc"
 )
 
 // mockSessionLister allows deterministic testing of orphan session detection.
@@ -18,8 +23,31 @@ func (m *mockSessionLister) ListSessions() ([]string, error) {
 	return m.sessions, m.err
 }
 
-func TestNewOrphanSessionCheck(t *testing.T) {
+func TestNewOrphanSessionCheckTmuxMissing(t *testing.T) {
+	old := lookPath
+	t.Cleanup(func() { lookPath = old })
+
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
 	check := NewOrphanSessionCheck()
+	if _, ok := check.(*FailingCheck); !ok {
+		t.Fatalf("NewOrphanSessionCheck() without tmux = %T, want *FailingCheck", check)
+	}
+	if check.Name() != "orphan-sessions" {
+		t.Errorf("Name() = %q, want orphan-sessions", check.Name())
+	}
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusUnavailable || result.Message != "tmux is not installed" {
+		t.Errorf("Run() = %+v, want it unavailable because tmux is not installed", result)
+	}
+
+	lookPath = func(string) (string, error) { return "/usr/bin/tmux", nil }
+	if _, ok := NewOrphanSessionCheck().(*OrphanSessionCheck); !ok {
+		t.Errorf("NewOrphanSessionCheck() with tmux = %T, want *OrphanSessionCheck", NewOrphanSessionCheck())
+	}
+}
+
+func TestNewOrphanSessionCheck(t *testing.T) {
+	check := newOrphanSessionCheck()
 
 	if check.Name() != "orphan-sessions" {
 		t.Errorf("expected name 'orphan-sessions', got %q", check.Name())
@@ -141,7 +169,7 @@ func TestIsCrewSession(t *testing.T) {
 }
 
 func TestOrphanSessionCheck_IsValidSession(t *testing.T) {
-	check := NewOrphanSessionCheck()
+	check := newOrphanSessionCheck()
 	validRigs := []string{"gongshow", "beads"}
 	mayorSession := "hq-mayor"
 	deaconSession := "hq-deacon"
@@ -184,7 +212,7 @@ func TestOrphanSessionCheck_IsValidSession(t *testing.T) {
 // TestOrphanSessionCheck_IsValidSession_EdgeCases tests edge cases that have caused
 // false positives in production - sessions incorrectly detected as orphans.
 func TestOrphanSessionCheck_IsValidSession_EdgeCases(t *testing.T) {
-	check := NewOrphanSessionCheck()
+	check := newOrphanSessionCheck()
 	validRigs := []string{"gongshow", "niflheim", "grctool", "7thsense", "pulseflow"}
 	mayorSession := "hq-mayor"
 	deaconSession := "hq-deacon"
@@ -273,7 +301,7 @@ func TestOrphanSessionCheck_IsValidSession_EdgeCases(t *testing.T) {
 
 // TestOrphanSessionCheck_GetValidRigs verifies rig detection from filesystem.
 func TestOrphanSessionCheck_GetValidRigs(t *testing.T) {
-	check := NewOrphanSessionCheck()
+	check := newOrphanSessionCheck()
 	townRoot := t.TempDir()
 
 	// Setup: create mayor directory (required for getValidRigs to proceed)
@@ -324,7 +352,7 @@ func TestOrphanSessionCheck_GetValidRigs(t *testing.T) {
 
 // TestOrphanSessionCheck_FixProtectsCrewSessions verifies that Fix() never kills crew sessions.
 func TestOrphanSessionCheck_FixProtectsCrewSessions(t *testing.T) {
-	check := NewOrphanSessionCheck()
+	check := newOrphanSessionCheck()
 
 	// Simulate cached orphan sessions including a crew session
 	check.orphanSessions = []string{
@@ -635,6 +663,40 @@ func TestOrphanProcessCheck_ScreenSession(t *testing.T) {
 	}
 }
 
+// TestOrphanProcessCheck_AgentAttribution tests that orphans are reported with
+// the agent from their environment, and unreadable ones without it.
+func TestOrphanProcessCheck_AgentAttribution(t *testing.T) {
+	lister := &mockProcessLister{
+		tmuxServerPIDs: []int{100},
+		panePIDs:       []int{200},
+		runtimeProcesses: []processInfo{
+			{pid: 1234, ppid: 1, cmd: "claude", agent: proc.AgentIdentity{Role: "polecat", Rig: "gongshow", Actor: "gongshow/polecats/Toast"}},
+			{pid: 1300, ppid: 1, cmd: "claude", agent: proc.AgentIdentity{Role: "witness", Rig: "gongshow"}},
+			{pid: 1400, ppid: 1, cmd: "codex"}, // Another user's: environment unreadable
+		},
+		parentPIDs: map[int]int{1234: 1, 1300: 1, 1400: 1},
+	}
+
+	result := NewOrphanProcessCheckWithProcessLister(lister).Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusWarning {
+		t.Fatalf("expected StatusWarning, got %v", result.Status)
+	}
+
+	want := []string{
+		"  PID 1234: claude (agent gongshow/polecats/Toast, parent 1)",
+		"  PID 1300: claude (agent gongshow/witness, parent 1)",
+		"  PID 1400: codex (parent 1)",
+	}
+	if len(result.Details) != 2+len(want) {
+		t.Fatalf("details = %q, want 2 info lines and %d processes", result.Details, len(want))
+	}
+	for i, line := range want {
+		if got := result.Details[2+i]; got != line {
+			t.Errorf("details[%d] = %q, want %q", 2+i, got, line)
+		}
+	}
+}
+
 // TestOrphanProcessCheck_Fix tests that Fix() kills orphaned processes.
 func TestOrphanProcessCheck_Fix(t *testing.T) {
 	var killedPIDs []int
@@ -663,8 +725,18 @@ func TestOrphanProcessCheck_Fix(t *testing.T) {
 		},
 	}
 
+	// In a town, so the kills are audited.
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
 	check := NewOrphanProcessCheckWithProcessLister(lister)
-	ctx := &CheckContext{TownRoot: t.TempDir()}
+	ctx := &CheckContext{TownRoot: townRoot}
 
 	// Run to populate orphanProcesses
 	result := check.Run(ctx)
@@ -682,6 +754,16 @@ func TestOrphanProcessCheck_Fix(t *testing.T) {
 	if len(killedPIDs) != 2 {
 		t.Errorf("expected 2 processes killed, got %d: %v", len(killedPIDs), killedPIDs)
 	}
+
+	audit, err := os.ReadFile(filepath.Join(townRoot, events.AuditFile))
+	if err != nil {
+		t.Fatalf("reading audit log: %v", err)
+	}
+	for _, want := range []string{`"target":"PID 1000 (claude)"`, `"target":"PID 2000 (claude)"`} {
+		if !strings.Contains(string(audit), want) || !strings.Contains(string(audit), `"type":"kill"`) {
+			t.Errorf("audit log has no kill event with %s:\n%s", want, audit)
+		}
+	}
 }
 
 // TestOrphanProcessCheck_Fix_DryRun tests that dry-run mode doesn't actually kill.
@@ -691,6 +773,8 @@ func TestOrphanProcessCheck_Fix_DryRun(t *testing.T) {
 	// Mock syscallKill to track what gets killed
 	origSyscallKill := syscallKill
 	defer func() { syscallKill = origSyscallKill }()
+	// Outside a workspace, so the kills aren't logged anywhere.
+	t.Chdir(t.TempDir())
 	syscallKill = func(pid int, sig syscall.Signal) error {
 		if sig == 0 {
 			return nil // Process exists check
@@ -739,6 +823,8 @@ func TestOrphanProcessCheck_Fix_ProcessBecomesNonOrphan(t *testing.T) {
 	// Mock syscallKill
 	origSyscallKill := syscallKill
 	defer func() { syscallKill = origSyscallKill }()
+	// Outside a workspace, so the kills aren't logged anywhere.
+	t.Chdir(t.TempDir())
 	syscallKill = func(pid int, sig syscall.Signal) error {
 		if sig == 0 {
 			return nil
@@ -784,6 +870,65 @@ func TestOrphanProcessCheck_Fix_ProcessBecomesNonOrphan(t *testing.T) {
 	}
 }
 
+// TestOrphanProcessCheck_Fix_PIDReused tests that Fix() skips a process whose
+// PID was reused by another process between Run() and Fix().
+func TestOrphanProcessCheck_Fix_PIDReused(t *testing.T) {
+	var killedPIDs []int
+
+	origSyscallKill := syscallKill
+	defer func() { syscallKill = origSyscallKill }()
+	// Outside a workspace, so the kills aren't logged anywhere.
+	t.Chdir(t.TempDir())
+	syscallKill = func(pid int, sig syscall.Signal) error {
+		if sig == 0 {
+			return nil
+		}
+		killedPIDs = append(killedPIDs, pid)
+		return nil
+	}
+
+	// PID 1000 exited after Run() and a new process got its PID.
+	origStartTime := processStartTime
+	defer func() { processStartTime = origStartTime }()
+	processStartTime = func(pid int) (uint64, error) {
+		if pid == 1000 {
+			return 9999, nil
+		}
+		return 500, nil
+	}
+
+	lister := &mockProcessLister{
+		tmuxServerPIDs: []int{100},
+		panePIDs:       []int{200},
+		runtimeProcesses: []processInfo{
+			{pid: 1000, ppid: 1, cmd: "claude", startTime: 500}, // Reused
+			{pid: 2000, ppid: 1, cmd: "claude", startTime: 500}, // Still the orphan
+			{pid: 3000, ppid: 1, cmd: "claude"},                 // Start time unknown
+		},
+		parentPIDs: map[int]int{
+			1000: 1,
+			2000: 1,
+			3000: 1,
+		},
+	}
+
+	check := NewOrphanProcessCheckWithProcessLister(lister)
+	ctx := &CheckContext{TownRoot: t.TempDir()}
+
+	result := check.Run(ctx)
+	if result.Status != StatusWarning {
+		t.Fatalf("expected StatusWarning, got %v", result.Status)
+	}
+
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+
+	if len(killedPIDs) != 2 || killedPIDs[0] != 2000 || killedPIDs[1] != 3000 {
+		t.Errorf("expected PIDs 2000 and 3000 killed and reused PID 1000 skipped, got %v", killedPIDs)
+	}
+}
+
 // TestOrphanProcessCheck_MaxAncestryDepth verifies the 8-level depth limit.
 func TestOrphanProcessCheck_MaxAncestryDepth(t *testing.T) {
 	// Create a chain that's exactly maxAncestryDepth levels
@@ -853,3 +998,42 @@ func TestOrphanProcessCheck_ExceedsMaxAncestryDepth(t *testing.T) {
 		t.Errorf("expected StatusWarning (pane beyond depth limit), got %v: %s", result.Status, result.Message)
 	}
 }
+
+func TestRuntimeCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		p    proc.Process
+		want string // "" if not a runtime process
+	}{
+		{"linux claude", proc.Process{Comm: "claude", Argv: []string{"claude", "--resume"}}, "claude"},
+		{"codex by path", proc.Process{Comm: "node", Argv: []string{"/usr/local/bin/codex"}}, "/usr/local/bin/codex"},
+		{"claude-code", proc.Process{Comm: "claude-code"}, "claude-code"},
+		// macOS's short command name doesn't tell the app from the CLI.
+		{"claude app", proc.Process{Comm: "Claude", Argv: []string{"/Applications/Claude.app/Contents/MacOS/Claude"}}, ""},
+		{"native host", proc.Process{Comm: "claude-native", Argv: []string{"claude-native"}}, ""},
+		{"editor", proc.Process{Comm: "vim", Argv: []string{"vim", "claude"}}, ""},
+		{"kernel thread", proc.Process{Comm: "kworker/0:1"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := runtimeCommand(tt.p)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("runtimeCommand(%+v) = %q, %v; want %q", tt.p, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTmuxCommand(t *testing.T) {
+	for comm, want := range map[string]bool{
+		"tmux":               true,
+		"tmux: server":       true,
+		"/opt/homebrew/tmux": true,
+		"tmuxinator":         false,
+		"bash":               false,
+	} {
+		if got := isTmuxCommand(comm); got != want {
+			t.Errorf("isTmuxCommand(%q) = %v, want %v", comm, got, want)
+		}
+	}
+}
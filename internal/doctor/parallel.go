@@ -0,0 +1,100 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultCheckTimeout bounds how long RunParallel waits for any single check.
+const DefaultCheckTimeout = 30 * time.Second
+
+// indexedResult carries a check result back with its position in the input.
+type indexedResult struct {
+	index  int
+	result *CheckResult
+}
+
+// RunParallel runs each check in its own goroutine and returns the results
+// in the same order as checks. Each check gets checkCtx.CheckTimeout
+// (DefaultCheckTimeout if zero); a check that overruns is reported as an
+// error. Checks don't take a context, so an overrunning check is abandoned
+// rather than stopped.
+func RunParallel(ctx context.Context, checks []Check, checkCtx *CheckContext) []*CheckResult {
+	timeout := checkCtx.CheckTimeout
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+
+	resultCh := make(chan indexedResult, len(checks))
+	for i, check := range checks {
+		go func(i int, check Check) {
+			resultCh <- indexedResult{index: i, result: runWithTimeout(ctx, check, checkCtx, timeout)}
+		}(i, check)
+	}
+
+	results := make([]*CheckResult, len(checks))
+	for range checks {
+		r := <-resultCh
+		results[r.index] = r.result
+	}
+	return results
+}
+
+// RunParallel executes all registered checks concurrently and returns a report.
+// Results appear in registration order, as with Run.
+func (d *Doctor) RunParallel(ctx context.Context, checkCtx *CheckContext) *Report {
+	report := NewReport()
+	for _, result := range RunParallel(ctx, d.checks, checkCtx) {
+		report.Add(result)
+	}
+	return report
+}
+
+// runWithTimeout runs a single check, giving up once timeout elapses or ctx
+// is cancelled.
+func runWithTimeout(ctx context.Context, check Check, checkCtx *CheckContext, timeout time.Duration) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan *CheckResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- &CheckResult{
+					Status:  StatusError,
+					Message: fmt.Sprintf("check panicked: %v", r),
+				}
+			}
+		}()
+		done <- check.Run(checkCtx)
+	}()
+
+	var result *CheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		msg := fmt.Sprintf("timed out after %s", timeout)
+		if ctx.Err() == context.Canceled {
+			msg = "cancelled"
+		}
+		result = &CheckResult{
+			Status:  StatusError,
+			Message: msg,
+			FixHint: "Run without --parallel to see if the check hangs",
+		}
+	}
+
+	if result == nil {
+		result = &CheckResult{Status: StatusError, Message: "check returned no result"}
+	}
+	// Ensure check name is populated
+	if result.Name == "" {
+		result.Name = check.Name()
+	}
+	// Set category from check if available
+	if cg, ok := check.(categoryGetter); ok && result.Category == "" {
+		result.Category = cg.Category()
+	}
+	return result
+}
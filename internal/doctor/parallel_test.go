@@ -0,0 +1,95 @@
+package doctor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// sleepCheck is a check that takes a fixed time to run.
+type sleepCheck struct {
+	BaseCheck
+	delay    time.Duration
+	finished chan time.Time
+}
+
+func newSleepCheck(name string, delay time.Duration) *sleepCheck {
+	return &sleepCheck{
+		BaseCheck: BaseCheck{CheckName: name, CheckDescription: "sleeps " + delay.String()},
+		delay:     delay,
+		finished:  make(chan time.Time, 1),
+	}
+}
+
+func (c *sleepCheck) Run(ctx *CheckContext) *CheckResult {
+	time.Sleep(c.delay)
+	c.finished <- time.Now()
+	return &CheckResult{Status: StatusOK, Message: "done"}
+}
+
+func TestRunParallel_SlowCheckDoesNotBlockFast(t *testing.T) {
+	slow := newSleepCheck("slow", 500*time.Millisecond)
+	fast := newSleepCheck("fast", 0)
+
+	start := time.Now()
+	results := RunParallel(context.Background(), []Check{slow, fast}, &CheckContext{})
+
+	fastDone := <-fast.finished
+	slowDone := <-slow.finished
+	if !fastDone.Before(slowDone) {
+		t.Error("fast check should finish before slow check")
+	}
+	if fastDone.Sub(start) > 250*time.Millisecond {
+		t.Errorf("fast check was blocked for %v", fastDone.Sub(start))
+	}
+
+	// Results keep input order regardless of completion order.
+	if results[0].Name != "slow" || results[1].Name != "fast" {
+		t.Errorf("results out of order: %s, %s", results[0].Name, results[1].Name)
+	}
+}
+
+func TestRunParallel_Timeout(t *testing.T) {
+	hung := newSleepCheck("hung", 2*time.Second)
+	ok := newMockCheck("ok", StatusOK)
+
+	start := time.Now()
+	results := RunParallel(context.Background(), []Check{hung, ok}, &CheckContext{CheckTimeout: 50 * time.Millisecond})
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("RunParallel waited %v for a timed-out check", elapsed)
+	}
+
+	if results[0].Status != StatusError || results[0].Name != "hung" {
+		t.Errorf("hung check: got %+v, want timeout error", results[0])
+	}
+	if results[1].Status != StatusOK {
+		t.Errorf("ok check: got %v", results[1].Status)
+	}
+}
+
+func TestRunParallel_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := RunParallel(ctx, []Check{newSleepCheck("slow", time.Second)}, &CheckContext{})
+	if results[0].Status != StatusError || results[0].Message != "cancelled" {
+		t.Errorf("got %+v, want cancelled error", results[0])
+	}
+}
+
+func TestDoctorRunParallel(t *testing.T) {
+	d := NewDoctor()
+	d.Register(newMockCheck("a", StatusOK))
+	d.Register(newMockCheck("b", StatusWarning))
+	d.Register(newMockCheck("c", StatusError))
+
+	report := d.RunParallel(context.Background(), &CheckContext{})
+	if report.Summary.Total != 3 || report.Summary.Warnings != 1 || report.Summary.Errors != 1 {
+		t.Errorf("unexpected summary: %+v", report.Summary)
+	}
+	for i, want := range []string{"a", "b", "c"} {
+		if report.Checks[i].Name != want {
+			t.Errorf("report.Checks[%d] = %s, want %s", i, report.Checks[i].Name, want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// RigConfigConsistencyCheck cross-checks the rigs registered in
+// mayor/rigs.json against the rig directories in the town: directories
+// with a polecats/ or crew/ subdirectory. Registered rigs with no directory
+// at all are left to rigs-registry-valid.
+type RigConfigConsistencyCheck struct {
+	FixableCheck
+	incomplete   []string // Registered, directory has no polecats/ or crew/ (cached for Fix)
+	unregistered []string // Rig directories not in rigs.json (cached for Fix)
+
+	// out is where Fix says how to resolve them.
+	out io.Writer
+}
+
+// NewRigConfigConsistencyCheck creates a new rig config consistency check.
+func NewRigConfigConsistencyCheck() *RigConfigConsistencyCheck {
+	return &RigConfigConsistencyCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "rig-config-consistency",
+				CheckDescription: "Check that rigs.json and the town's rig directories agree",
+				CheckCategory:    CategoryCore,
+			},
+		},
+		out: os.Stdout,
+	}
+}
+
+// Run compares the registered rigs with the rig directories.
+func (c *RigConfigConsistencyCheck) Run(ctx *CheckContext) *CheckResult {
+	c.incomplete, c.unregistered = nil, nil
+
+	rigsPath := filepath.Join(ctx.TownRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		// rigs-registry-exists and rigs-registry-valid report on the file.
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No readable rigs.json (skipping)",
+		}
+	}
+
+	rigDirs, err := findRigDirs(ctx.TownRoot)
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "Cannot read town directory",
+			Details: []string{err.Error()},
+		}
+	}
+
+	for name := range rigsConfig.Rigs {
+		if rigDirs[name] {
+			continue
+		}
+		// A registered rig with no directory is rigs-registry-valid's.
+		if _, err := os.Stat(filepath.Join(ctx.TownRoot, name)); err == nil {
+			c.incomplete = append(c.incomplete, name)
+		}
+	}
+	for name := range rigDirs {
+		if _, ok := rigsConfig.Rigs[name]; !ok {
+			c.unregistered = append(c.unregistered, name)
+		}
+	}
+	sort.Strings(c.incomplete)
+	sort.Strings(c.unregistered)
+
+	var details []string
+	for _, name := range c.incomplete {
+		details = append(details, fmt.Sprintf("Registered rig %s/ has no polecats/ or crew/", name))
+	}
+	for _, name := range c.unregistered {
+		details = append(details, fmt.Sprintf("Rig directory %s/ is not in rigs.json", name))
+	}
+
+	if len(details) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: fmt.Sprintf("rigs.json matches the %d rig director(ies)", len(rigDirs)),
+		}
+	}
+
+	var parts []string
+	if n := len(c.incomplete); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d registered rig(s) without polecats/ or crew/", n))
+	}
+	if n := len(c.unregistered); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d unregistered rig director(ies)", n))
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: strings.Join(parts, ", "),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' for how to register or re-add each rig",
+	}
+}
+
+// Fix says how to register each unregistered directory and how to repair
+// each incomplete rig, which it leaves to gt rig add and gt rig remove.
+func (c *RigConfigConsistencyCheck) Fix(ctx *CheckContext) error {
+	for _, name := range c.unregistered {
+		gitURL := rigRemoteURL(ctx.TownRoot, name)
+		if gitURL == "" {
+			gitURL = "<git-url>"
+		}
+		_, _ = fmt.Fprintf(c.out, "  Rig directory %s/ is not registered. To register it, run: gt rig add %s %s\n", name, name, gitURL)
+	}
+	for _, name := range c.incomplete {
+		_, _ = fmt.Fprintf(c.out, "  Rig %s/ has no polecats/ or crew/. Re-add it with 'gt rig add' or drop it with 'gt rig remove %s'\n", name, name)
+	}
+	return nil
+}
+
+// rigRemoteURL returns the origin URL of a rig directory's mayor/rig clone,
+// or "" if there isn't one.
+func rigRemoteURL(townRoot, name string) string {
+	cmd := exec.Command("git", "-C", filepath.Join(townRoot, name, "mayor", "rig"), "remote", "get-url", "origin")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// findRigDirs returns the names of the town's top-level directories that
+// have a polecats/ or crew/ subdirectory.
+func findRigDirs(townRoot string) (map[string]bool, error) {
+	entries, err := os.ReadDir(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	dirs := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		for _, sub := range []string{"polecats", "crew"} {
+			if info, err := os.Stat(filepath.Join(townRoot, entry.Name(), sub)); err == nil && info.IsDir() {
+				dirs[entry.Name()] = true
+				break
+			}
+		}
+	}
+	return dirs, nil
+}
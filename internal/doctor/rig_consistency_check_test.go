@@ -0,0 +1,131 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// setupRigConsistencyTown creates a town registering alpha (a rig with
+// polecats/), ghost (no directory, which rigs-registry-valid reports) and
+// shell (a directory with neither polecats/ nor crew/), and holding beta
+// (crew/ only, unregistered) plus directories that aren't rigs.
+func setupRigConsistencyTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	for _, dir := range []string{
+		"mayor",
+		"deacon/dogs",
+		"alpha/polecats",
+		"shell/settings",
+		"beta/crew/max",
+		".hidden/polecats",
+	} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRigsJSON(t, townRoot, "alpha", "ghost", "shell")
+	return townRoot
+}
+
+func writeRigsJSON(t *testing.T, townRoot string, rigs ...string) {
+	t.Helper()
+	cfg := &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}
+	for _, name := range rigs {
+		cfg.Rigs[name] = config.RigEntry{GitURL: "https://example.com/" + name + ".git", AddedAt: time.Now()}
+	}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func registeredRigs(t *testing.T, townRoot string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cfg config.RigsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for name := range cfg.Rigs {
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestRigConfigConsistencyCheck_Consistent(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{"mayor", "alpha/polecats", "beta/crew"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRigsJSON(t, townRoot, "alpha", "beta")
+
+	result := NewRigConfigConsistencyCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK: %s %v", result.Status, result.Message, result.Details)
+	}
+}
+
+func TestRigConfigConsistencyCheck_NoRegistry(t *testing.T) {
+	result := NewRigConfigConsistencyCheck().Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusOK {
+		t.Errorf("Status = %v, want OK without rigs.json", result.Status)
+	}
+}
+
+func TestRigConfigConsistencyCheck_Run(t *testing.T) {
+	townRoot := setupRigConsistencyTown(t)
+
+	result := NewRigConfigConsistencyCheck().Run(&CheckContext{TownRoot: townRoot})
+	if result.Status != StatusWarning {
+		t.Fatalf("Status = %v, want Warning", result.Status)
+	}
+	want := []string{
+		"Registered rig shell/ has no polecats/ or crew/",
+		"Rig directory beta/ is not in rigs.json",
+	}
+	if strings.Join(result.Details, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Details = %q, want %q", result.Details, want)
+	}
+	if result.Message != "1 registered rig(s) without polecats/ or crew/, 1 unregistered rig director(ies)" {
+		t.Errorf("Message = %q", result.Message)
+	}
+}
+
+func TestRigConfigConsistencyCheck_Fix(t *testing.T) {
+	townRoot := setupRigConsistencyTown(t)
+	ctx := &CheckContext{TownRoot: townRoot}
+	check := NewRigConfigConsistencyCheck()
+	var out bytes.Buffer
+	check.out = &out
+
+	check.Run(ctx)
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+
+	for _, want := range []string{"gt rig add beta <git-url>", "gt rig remove shell"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Fix output doesn't suggest %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "ghost") {
+		t.Errorf("Fix output mentions ghost, which is rigs-registry-valid's:\n%s", out.String())
+	}
+	// Fix only advises; the registry is left alone.
+	if rigs := strings.Join(registeredRigs(t, townRoot), ","); strings.Count(rigs, ",") != 2 {
+		t.Errorf("registered rigs = %s, want all three kept", rigs)
+	}
+}
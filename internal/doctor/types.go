@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/KeithWyatt/gongshow/internal/ui"
@@ -42,6 +43,9 @@ const (
 	StatusWarning
 	// StatusError indicates a critical problem.
 	StatusError
+	// StatusUnavailable indicates the check couldn't run, e.g. because a
+	// tool it needs isn't installed. It is shown as SKIP.
+	StatusUnavailable
 )
 
 // String returns a human-readable status.
@@ -53,6 +57,8 @@ func (s CheckStatus) String() string {
 		return "Warning"
 	case StatusError:
 		return "Error"
+	case StatusUnavailable:
+		return "SKIP"
 	default:
 		return "Unknown"
 	}
@@ -65,6 +71,9 @@ type CheckContext struct {
 	Verbose         bool   // Enable verbose output
 	RestartSessions bool   // Restart patrol sessions when fixing (requires explicit --restart-sessions flag)
 	DryRun          bool   // Report what would be fixed without actually fixing
+
+	// CheckTimeout bounds each check under RunParallel (default: DefaultCheckTimeout).
+	CheckTimeout time.Duration
 }
 
 // RigPath returns the full path to the rig directory.
@@ -111,6 +120,7 @@ type ReportSummary struct {
 	OK       int
 	Warnings int
 	Errors   int
+	Skipped  int // StatusUnavailable
 }
 
 // Report contains all check results and a summary.
@@ -140,6 +150,8 @@ func (r *Report) Add(result *CheckResult) {
 		r.Summary.Warnings++
 	case StatusError:
 		r.Summary.Errors++
+	case StatusUnavailable:
+		r.Summary.Skipped++
 	}
 }
 
@@ -190,7 +202,7 @@ func (r *Report) Print(w io.Writer, verbose bool) {
 		// Print each check in this category
 		for _, check := range checks {
 			r.printCheck(w, check, verbose)
-			if check.Status != StatusOK {
+			if check.Status == StatusWarning || check.Status == StatusError {
 				warnings = append(warnings, check)
 			}
 		}
@@ -202,7 +214,7 @@ func (r *Report) Print(w io.Writer, verbose bool) {
 		_, _ = fmt.Fprintln(w, ui.RenderCategory("Other"))
 		for _, check := range otherChecks {
 			r.printCheck(w, check, verbose)
-			if check.Status != StatusOK {
+			if check.Status == StatusWarning || check.Status == StatusError {
 				warnings = append(warnings, check)
 			}
 		}
@@ -227,12 +239,18 @@ func (r *Report) printCheck(w io.Writer, check *CheckResult, verbose bool) {
 		statusIcon = ui.RenderWarnIcon()
 	case StatusError:
 		statusIcon = ui.RenderFailIcon()
+	case StatusUnavailable:
+		statusIcon = ui.RenderSkipIcon()
 	}
 
 	// Print check line: icon + name + muted message
 	_, _ = fmt.Fprintf(w, "  %s  %s", statusIcon, check.Name)
-	if check.Message != "" {
-		_, _ = fmt.Fprintf(w, "%s", ui.RenderMuted(" "+check.Message))
+	message := check.Message
+	if check.Status == StatusUnavailable {
+		message = strings.TrimSuffix(check.Status.String()+": "+message, ": ")
+	}
+	if message != "" {
+		_, _ = fmt.Fprintf(w, "%s", ui.RenderMuted(" "+message))
 	}
 	_, _ = fmt.Fprintln(w)
 
@@ -251,6 +269,9 @@ func (r *Report) printSummary(w io.Writer) {
 		ui.RenderWarnIcon(), r.Summary.Warnings,
 		ui.RenderFailIcon(), r.Summary.Errors,
 	)
+	if r.Summary.Skipped > 0 {
+		summary += fmt.Sprintf("  %s %d skipped", ui.RenderSkipIcon(), r.Summary.Skipped)
+	}
 	_, _ = fmt.Fprintln(w, summary)
 }
 
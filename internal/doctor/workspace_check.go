@@ -385,6 +385,7 @@ func WorkspaceChecks() []Check {
 		NewTownConfigValidCheck(),
 		NewRigsRegistryExistsCheck(),
 		NewRigsRegistryValidCheck(),
+		NewRigConfigConsistencyCheck(),
 		NewMayorExistsCheck(),
 	}
 }
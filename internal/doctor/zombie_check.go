@@ -0,0 +1,288 @@
+package doctor
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/proc"
+	"github.com/KeithWyatt/gongshow/internal/session"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+// ZombieProcessCheck detects defunct processes in GongShow sessions:
+// children that have exited but were never reaped by their parent, often
+// a shell left behind when an agent was killed abruptly. Each holds on to
+// its PID, and they confuse process counts, until reaped.
+// When --fix is used, each parent is sent SIGCHLD to prompt it to reap
+// them. With --restart-sessions, a patrol session whose parent still
+// hasn't is restarted.
+type ZombieProcessCheck struct {
+	FixableCheck
+	lister  ZombieLister
+	zombies []zombieInfo // Cached during Run for use in Fix
+}
+
+// ZombieLister abstracts the process table and tmux panes for testing.
+type ZombieLister interface {
+	// Snapshot returns the process table, zombies included.
+	Snapshot() (*proc.Table, error)
+	// ListSessionPanes returns the GongShow session of each tmux pane,
+	// by the PID of the pane's process.
+	ListSessionPanes() (map[int]string, error)
+}
+
+type realZombieLister struct{}
+
+func (realZombieLister) Snapshot() (*proc.Table, error) {
+	return proc.Snapshot()
+}
+
+func (realZombieLister) ListSessionPanes() (map[int]string, error) {
+	panes := make(map[int]string)
+	out, err := exec.Command("tmux", "list-panes", "-a", "-F", "#{pane_pid} #{session_name}").Output()
+	if err != nil {
+		// tmux not running or no sessions - no panes
+		return panes, nil
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		pidStr, sess, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil {
+			continue
+		}
+		if _, err := session.ParseSessionName(sess); err != nil {
+			continue // Not a GongShow session
+		}
+		panes[pid] = sess
+	}
+	return panes, nil
+}
+
+// NewZombieProcessCheck creates a new zombie process check.
+func NewZombieProcessCheck() *ZombieProcessCheck {
+	return &ZombieProcessCheck{
+		FixableCheck: FixableCheck{
+			BaseCheck: BaseCheck{
+				CheckName:        "zombie-processes",
+				CheckDescription: "Detect defunct processes in GongShow sessions",
+				CheckCategory:    CategoryCleanup,
+			},
+		},
+		lister: realZombieLister{},
+	}
+}
+
+// NewZombieProcessCheckWithLister creates a check with a custom lister (for testing).
+func NewZombieProcessCheckWithLister(lister ZombieLister) *ZombieProcessCheck {
+	check := NewZombieProcessCheck()
+	check.lister = lister
+	return check
+}
+
+type zombieInfo struct {
+	pid         int
+	comm        string
+	ppid        int
+	parentComm  string
+	parentStart uint64 // See proc.StartTime; 0 if unknown
+	session     string // GongShow session the zombie is in
+}
+
+// describe says what the zombie is and who should reap it:
+// "bd (defunct), parent 420 claude in gt-gongshow-Toast".
+func (z zombieInfo) describe() string {
+	return fmt.Sprintf("%s (defunct), parent %d %s in %s", z.comm, z.ppid, z.parentComm, z.session)
+}
+
+// parentReused reports whether z's parent PID now belongs to a process
+// that started after z was found; see processInfo.reused.
+func (z zombieInfo) parentReused() bool {
+	if z.parentStart == 0 {
+		return false
+	}
+	start, err := processStartTime(z.ppid)
+	return err == nil && start != z.parentStart
+}
+
+// Run checks for zombie processes descended from GongShow tmux panes.
+func (c *ZombieProcessCheck) Run(ctx *CheckContext) *CheckResult {
+	zombies, err := c.findZombies()
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not read process table",
+			Details: []string{err.Error()},
+		}
+	}
+
+	// Cache zombies for Fix
+	c.zombies = zombies
+
+	if len(zombies) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No zombie processes in GongShow sessions",
+		}
+	}
+
+	details := make([]string, 0, len(zombies))
+	for _, z := range zombies {
+		details = append(details, fmt.Sprintf("PID %d: %s", z.pid, z.describe()))
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusWarning,
+		Message: fmt.Sprintf("Found %d zombie process(es) in GongShow sessions", len(zombies)),
+		Details: details,
+		FixHint: "Run 'gt doctor --fix' to have their parents reap them",
+	}
+}
+
+// findZombies lists the zombies in GongShow sessions as they are now.
+func (c *ZombieProcessCheck) findZombies() ([]zombieInfo, error) {
+	table, err := c.lister.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	panes, err := c.lister.ListSessionPanes()
+	if err != nil {
+		return nil, err
+	}
+	return sessionZombies(table, panes), nil
+}
+
+// sessionZombies returns the zombies in table with a pane in panes among
+// their ancestors, within maxAncestryDepth levels.
+func sessionZombies(table *proc.Table, panes map[int]string) []zombieInfo {
+	var zombies []zombieInfo
+	for _, z := range table.Zombies() {
+		// A failed lookup ends the walk; whatever was found before it still counts.
+		chain, _ := proc.GetParentChainWith(z.PID, maxAncestryDepth, table.ParentPID)
+		for _, ancestor := range chain {
+			sess, ok := panes[ancestor]
+			if !ok {
+				continue
+			}
+			parent, _ := table.Get(z.PPID)
+			zombies = append(zombies, zombieInfo{
+				pid:         z.PID,
+				comm:        z.Comm,
+				ppid:        z.PPID,
+				parentComm:  parent.Comm,
+				parentStart: parent.StartTime,
+				session:     sess,
+			})
+			break
+		}
+	}
+	return zombies
+}
+
+// Fix sends SIGCHLD to the parent of each zombie found by Run, prompting
+// it to reap its children. A parent that still hasn't is one of ours, in
+// a GongShow session: with ctx.RestartSessions a patrol session is
+// restarted, by killing it for the daemon to start again; otherwise it
+// is reported. Crew and polecat sessions are never restarted here.
+// If ctx.DryRun is true, reports what would be signaled without signaling.
+func (c *ZombieProcessCheck) Fix(ctx *CheckContext) error {
+	if len(c.zombies) == 0 {
+		return nil
+	}
+
+	var lastErr error
+	nudged := make(map[int]zombieInfo)
+	for _, z := range c.zombies {
+		if _, done := nudged[z.ppid]; done {
+			continue
+		}
+		if z.parentReused() {
+			fmt.Printf("  Warning: PID %d (%s) now belongs to a newer process, not signaling it\n", z.ppid, z.parentComm)
+			continue
+		}
+		if ctx.DryRun {
+			fmt.Printf("[dry-run] Would send SIGCHLD to PID %d (%s) in %s\n", z.ppid, z.parentComm, z.session)
+			nudged[z.ppid] = z
+			continue
+		}
+		if err := syscallKill(z.ppid, syscall.SIGCHLD); err != nil {
+			lastErr = fmt.Errorf("failed to signal PID %d: %w", z.ppid, err)
+			continue
+		}
+		nudged[z.ppid] = z
+	}
+	if ctx.DryRun || len(nudged) == 0 {
+		return lastErr
+	}
+
+	time.Sleep(zombieReapWait)
+	remaining, err := c.findZombies()
+	if err != nil {
+		return fmt.Errorf("re-reading process table: %w", err)
+	}
+
+	restarted := make(map[string]bool)
+	for _, z := range remaining {
+		parent, ok := nudged[z.ppid]
+		if !ok || parent.parentStart != z.parentStart || restarted[z.session] {
+			continue // Not one we signaled, or already dealt with
+		}
+		restarted[z.session] = true
+
+		if !isPatrolSession(z.session) || !ctx.RestartSessions {
+			fmt.Printf("  Warning: PID %d (%s) in %s has not reaped its zombies; restart the session to clear them\n",
+				z.ppid, z.parentComm, z.session)
+			if isPatrolSession(z.session) {
+				fmt.Printf("           (gt doctor --fix --restart-sessions restarts it)\n")
+			}
+			continue
+		}
+
+		// Cycle the agent by killing the session and letting the daemon restart it.
+		agent := "unknown"
+		if id, err := session.ParseSessionName(z.session); err == nil {
+			agent = id.Address()
+		}
+		_ = events.LogFeed(events.TypeSessionDeath, z.session,
+			events.SessionDeathPayload(z.session, agent, "zombie cleanup", "gt doctor"))
+		if err := killTmuxSession(z.session); err != nil {
+			lastErr = fmt.Errorf("failed to restart %s: %w", z.session, err)
+		}
+	}
+
+	return lastErr
+}
+
+// isPatrolSession reports whether sess is a patrol agent's session
+// (mayor, deacon, witness or refinery), which the daemon restarts if it
+// dies.
+func isPatrolSession(sess string) bool {
+	id, err := session.ParseSessionName(sess)
+	if err != nil {
+		return false
+	}
+	switch id.Role {
+	case session.RoleMayor, session.RoleDeacon, session.RoleWitness, session.RoleRefinery:
+		return true
+	}
+	return false
+}
+
+// zombieReapWait is how long Fix gives parents to reap their zombies
+// after SIGCHLD; replaced in tests.
+var zombieReapWait = 200 * time.Millisecond
+
+// killTmuxSession wraps tmux's KillSession for testability.
+var killTmuxSession = func(name string) error {
+	return tmux.NewTmux().KillSession(name)
+}
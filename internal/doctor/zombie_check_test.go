@@ -0,0 +1,167 @@
+package doctor
+
+import (
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/proc"
+)
+
+// mockZombieLister returns its tables in turn, the last one from then on.
+type mockZombieLister struct {
+	tables []*proc.Table
+	panes  map[int]string
+}
+
+func (m *mockZombieLister) Snapshot() (*proc.Table, error) {
+	table := m.tables[0]
+	if len(m.tables) > 1 {
+		m.tables = m.tables[1:]
+	}
+	return table, nil
+}
+
+func (m *mockZombieLister) ListSessionPanes() (map[int]string, error) {
+	return m.panes, nil
+}
+
+// zombieFixture is a witness session whose claude has a bd child it never
+// reaped, a polecat session whose shell has a dead git, and a zombie
+// outside any session. reaped leaves out the polecat's git, as if its
+// shell had reaped it.
+func zombieFixture(reaped bool) *proc.Table {
+	procs := []proc.Process{
+		{PID: 100, PPID: 1, Comm: "tmux: server", State: 'S'},
+		{PID: 200, PPID: 100, Comm: "bash", State: 'S', StartTime: 2000},
+		{PID: 210, PPID: 200, Comm: "claude", State: 'S', StartTime: 2100},
+		{PID: 211, PPID: 210, Comm: "bd", State: proc.StateZombie},
+		{PID: 300, PPID: 100, Comm: "bash", State: 'S', StartTime: 3000},
+		{PID: 500, PPID: 1, Comm: "make", State: 'S'},
+		{PID: 501, PPID: 500, Comm: "cc", State: proc.StateZombie},
+	}
+	if !reaped {
+		procs = append(procs, proc.Process{PID: 301, PPID: 300, Comm: "git", State: proc.StateZombie})
+	}
+	return proc.NewTable(procs)
+}
+
+var zombieFixturePanes = map[int]string{200: "gt-gongshow-witness", 300: "gt-gongshow-Toast"}
+
+func TestZombieProcessCheck_Run(t *testing.T) {
+	check := NewZombieProcessCheckWithLister(&mockZombieLister{
+		tables: []*proc.Table{zombieFixture(false)},
+		panes:  zombieFixturePanes,
+	})
+	if check.Name() != "zombie-processes" || !check.CanFix() {
+		t.Errorf("check = %q, CanFix %v; want a fixable zombie-processes", check.Name(), check.CanFix())
+	}
+
+	result := check.Run(&CheckContext{TownRoot: t.TempDir()})
+	if result.Status != StatusWarning {
+		t.Fatalf("Run() status = %v, want warning: %+v", result.Status, result)
+	}
+	// The zombie under make is in no session, so not ours.
+	want := []string{
+		"PID 211: bd (defunct), parent 210 claude in gt-gongshow-witness",
+		"PID 301: git (defunct), parent 300 bash in gt-gongshow-Toast",
+	}
+	if !reflect.DeepEqual(result.Details, want) {
+		t.Errorf("Run() details = %q, want %q", result.Details, want)
+	}
+	if !strings.Contains(result.Message, "2 zombie") {
+		t.Errorf("Run() message = %q, want 2 zombies", result.Message)
+	}
+}
+
+func TestZombieProcessCheck_RunNone(t *testing.T) {
+	check := NewZombieProcessCheckWithLister(&mockZombieLister{
+		tables: []*proc.Table{zombieFixture(false)},
+		panes:  map[int]string{},
+	})
+	if result := check.Run(&CheckContext{TownRoot: t.TempDir()}); result.Status != StatusOK {
+		t.Errorf("Run() without GongShow sessions = %+v, want OK", result)
+	}
+}
+
+// fixZombies runs the check on zombieFixture, then fixes it with the
+// polecat's git reaped after SIGCHLD and the witness's bd not, and
+// returns the signals sent and the sessions killed.
+func fixZombies(t *testing.T, ctx *CheckContext) (signaled []int, killed []string, err error) {
+	t.Helper()
+	origKill, origKillSession, origWait := syscallKill, killTmuxSession, zombieReapWait
+	t.Cleanup(func() { syscallKill, killTmuxSession, zombieReapWait = origKill, origKillSession, origWait })
+	syscallKill = func(pid int, sig syscall.Signal) error {
+		if sig != syscall.SIGCHLD {
+			t.Errorf("sent signal %v to PID %d, want SIGCHLD", sig, pid)
+		}
+		signaled = append(signaled, pid)
+		return nil
+	}
+	killTmuxSession = func(name string) error {
+		killed = append(killed, name)
+		return nil
+	}
+	zombieReapWait = 0
+	// Outside a workspace, so the session deaths aren't logged anywhere.
+	t.Chdir(t.TempDir())
+
+	check := NewZombieProcessCheckWithLister(&mockZombieLister{
+		tables: []*proc.Table{zombieFixture(false), zombieFixture(true)},
+		panes:  zombieFixturePanes,
+	})
+	check.Run(ctx)
+	err = check.Fix(ctx)
+	return signaled, killed, err
+}
+
+func TestZombieProcessCheck_Fix(t *testing.T) {
+	signaled, killed, err := fixZombies(t, &CheckContext{TownRoot: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Fix() error: %v", err)
+	}
+	if !reflect.DeepEqual(signaled, []int{210, 300}) {
+		t.Errorf("Fix() signaled %v, want the parents 210 and 300", signaled)
+	}
+	// Without --restart-sessions the witness is only reported.
+	if len(killed) != 0 {
+		t.Errorf("Fix() killed sessions %v without RestartSessions", killed)
+	}
+}
+
+func TestZombieProcessCheck_FixRestartSessions(t *testing.T) {
+	_, killed, err := fixZombies(t, &CheckContext{TownRoot: t.TempDir(), RestartSessions: true})
+	if err != nil {
+		t.Fatalf("Fix() error: %v", err)
+	}
+	if !reflect.DeepEqual(killed, []string{"gt-gongshow-witness"}) {
+		t.Errorf("Fix() killed sessions %v, want the witness's, whose claude didn't reap bd", killed)
+	}
+}
+
+func TestZombieProcessCheck_FixDryRun(t *testing.T) {
+	signaled, killed, err := fixZombies(t, &CheckContext{TownRoot: t.TempDir(), RestartSessions: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Fix() error: %v", err)
+	}
+	if len(signaled) != 0 || len(killed) != 0 {
+		t.Errorf("dry-run Fix() signaled %v and killed %v, want nothing", signaled, killed)
+	}
+}
+
+func TestIsPatrolSession(t *testing.T) {
+	for sess, want := range map[string]bool{
+		"hq-mayor":             true,
+		"hq-deacon":            true,
+		"gt-gongshow-witness":  true,
+		"gt-gongshow-refinery": true,
+		"gt-gongshow-crew-max": false,
+		"gt-gongshow-Toast":    false,
+		"my-personal-session":  false,
+	} {
+		if got := isPatrolSession(sess); got != want {
+			t.Errorf("isPatrolSession(%q) = %v, want %v", sess, got, want)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"os"
+)
+
+// CorrelationEnv carries a correlation ID into processes started on behalf
+// of a command, such as the agent sessions it spawns, so the events they log
+// join the same chain.
+const CorrelationEnv = "GT_CORRELATION_ID"
+
+type correlationKey struct{}
+
+// NewCorrelationID returns a fresh correlation ID.
+func NewCorrelationID() string {
+	return newEventID()
+}
+
+// WithCorrelation returns a context whose events carry id. An empty id
+// returns ctx unchanged.
+func WithCorrelation(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or "".
+func CorrelationID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// CorrelationFromEnv returns the correlation ID this process was started
+// with (see CorrelationEnv), or "".
+func CorrelationFromEnv() string {
+	return os.Getenv(CorrelationEnv)
+}
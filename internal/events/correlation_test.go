@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// captureEvents replaces the event sink with one recording events in memory.
+func captureEvents(t *testing.T) *[]Event {
+	t.Helper()
+	var logged []Event
+	orig := sink
+	sink = func(e Event) error {
+		logged = append(logged, e)
+		return nil
+	}
+	t.Cleanup(func() { sink = orig })
+	return &logged
+}
+
+func TestCorrelationThreadsCommandFlow(t *testing.T) {
+	logged := captureEvents(t)
+
+	// gt sling: the command gets an ID, and everything it does carries it.
+	ctx := WithCorrelation(context.Background(), NewCorrelationID())
+	spawn := func(ctx context.Context) {
+		_ = LogFeedContext(ctx, TypeSpawn, "gt", SpawnPayload("gongshow", "Toast"))
+	}
+	spawn(ctx)
+	_ = LogFeedContext(ctx, TypeSling, "mayor", SlingPayload("gt-abc", "gongshow/polecats/Toast"))
+	_ = LogFeedContext(ctx, TypeMail, "mayor", MailPayload("gongshow/Toast", "Work assigned"))
+
+	// The spawned polecat's gt done runs in another process, which picks the
+	// ID up from its environment.
+	t.Setenv(CorrelationEnv, CorrelationID(ctx))
+	child := WithCorrelation(context.Background(), CorrelationFromEnv())
+	_ = LogFeedContext(child, TypeDone, "gongshow/polecats/Toast", DonePayload("gt-abc", "polecat/Toast"))
+
+	if len(*logged) != 4 {
+		t.Fatalf("logged %d events, want 4", len(*logged))
+	}
+	want := CorrelationID(ctx)
+	if want == "" {
+		t.Fatal("context carries no correlation ID")
+	}
+	for _, e := range *logged {
+		if e.CorrelationID != want {
+			t.Errorf("%s event has correlation %q, want %q", e.Type, e.CorrelationID, want)
+		}
+	}
+
+	// Another command gets a chain of its own.
+	other := WithCorrelation(context.Background(), NewCorrelationID())
+	if CorrelationID(other) == want {
+		t.Error("NewCorrelationID repeated an ID")
+	}
+}
+
+func TestLogWithoutCorrelation(t *testing.T) {
+	logged := captureEvents(t)
+
+	_ = LogFeed(TypeNudge, "deacon", NudgePayload("", "mayor", "wake up"))
+	_ = LogFeedContext(WithCorrelation(context.Background(), ""), TypeNudge, "deacon", NudgePayload("", "mayor", "again"))
+
+	for _, e := range *logged {
+		if e.CorrelationID != "" {
+			t.Errorf("event has correlation %q, want none", e.CorrelationID)
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "correlation_id") {
+			t.Errorf("uncorrelated event serialises the field: %s", data)
+		}
+	}
+}
+
+func TestFilterCorrelation(t *testing.T) {
+	f := Filter{Correlation: "3f9a0c1d"}
+	for _, tt := range []struct {
+		id   string
+		want bool
+	}{
+		{"3f9a0c1d2e4b5a67", true},
+		{"3f9a0c1d", true},
+		{"aaaa0c1d2e4b5a67", false},
+		{"", false},
+	} {
+		if got := f.Match(&Event{Type: TypeSling, CorrelationID: tt.id}); got != tt.want {
+			t.Errorf("Match(correlation %q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestHookEnvCorrelation(t *testing.T) {
+	env := strings.Join(HookEnv(Event{Type: TypeSling, CorrelationID: "abc123"}), "\n")
+	if !strings.Contains(env, "GT_EVENT_CORRELATION_ID=abc123") {
+		t.Errorf("hook env lacks correlation:\n%s", env)
+	}
+	env = strings.Join(HookEnv(Event{Type: TypeSling}), "\n")
+	if strings.Contains(env, "GT_EVENT_CORRELATION_ID") {
+		t.Errorf("hook env has correlation for an uncorrelated event:\n%s", env)
+	}
+}
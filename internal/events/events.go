@@ -1,14 +1,19 @@
 // Package events provides event logging for the gt activity feed.
 //
-// Events are written to ~/gt/.events.jsonl (raw audit log) and later
-// curated by the feed daemon into ~/.feed.jsonl (user-facing).
+// Feed-visible events are written to ~/gt/.events.jsonl and later curated
+// by the feed daemon into ~/.feed.jsonl (user-facing). That log is rotated
+// into .events-<timestamp>.jsonl archives once it grows past
+// RotationPolicy.MaxSize; use OpenLog to read across archives.
+// Audit-visible events go to ~/gt/.audit.jsonl instead, which is only ever
+// appended to; events of VisibilityBoth are written to both.
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
+	"os/user"
 	"sync"
 	"time"
 
@@ -23,12 +28,34 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+
+	// SchemaVersion is the payload schema version the event was written
+	// against (see CurrentSchemaVersion); 0 for events written before it.
+	SchemaVersion int `json:"schema_version,omitempty"`
+
+	// ID identifies the event across log files, so readers merging the
+	// town and rig logs can drop duplicates. Empty for older events.
+	ID string `json:"id,omitempty"`
+
+	// CorrelationID threads together the events caused by one user-facing
+	// command (see WithCorrelation). Empty for events logged outside one.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// OSUser and PID identify the process behind a destructive operation
+	// (see destructiveTypes). Only set in the audit log.
+	OSUser string `json:"os_user,omitempty"`
+	PID    int    `json:"pid,omitempty"`
+
+	// Replayed marks an event re-dispatched by Replay rather than logged
+	// live. Handlers should refuse real side effects for it; hooks skip their
+	// mail and notify actions. Replayed events are never written to a log.
+	Replayed bool `json:"replayed,omitempty"`
 }
 
 // Visibility levels for events.
 const (
-	VisibilityAudit = "audit" // Only in raw events log
-	VisibilityFeed  = "feed"  // Appears in curated feed
+	VisibilityAudit = "audit" // Only in the audit log
+	VisibilityFeed  = "feed"  // Only in the events log, which feeds the curated feed
 	VisibilityBoth  = "both"  // Both audit and feed
 )
 
@@ -46,6 +73,9 @@ const (
 	TypeBoot    = "boot"
 	TypeHalt    = "halt"
 
+	// Mail delivery events
+	TypeMailBounce = "mail_bounce" // Undeliverable mail returned to its sender
+
 	// Session events (for seance discovery)
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
@@ -55,40 +85,89 @@ const (
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
 	// Witness patrol events
-	TypePatrolStarted   = "patrol_started"
-	TypePolecatChecked  = "polecat_checked"
-	TypePolecatNudged   = "polecat_nudged"
-	TypeEscalationSent   = "escalation_sent"
-	TypeEscalationAcked  = "escalation_acked"
-	TypeEscalationClosed = "escalation_closed"
-	TypePatrolComplete   = "patrol_complete"
+	TypePatrolStarted      = "patrol_started"
+	TypePolecatChecked     = "polecat_checked"
+	TypePolecatNudged      = "polecat_nudged"
+	TypeEscalationSent     = "escalation_sent"
+	TypeEscalationAcked    = "escalation_acked"
+	TypeEscalationClosed   = "escalation_closed"
+	TypePatrolComplete     = "patrol_complete"
+	TypePatrolPluginResult = "patrol_plugin_result" // Outcome of an external patrol plugin
 
 	// Merge queue events (emitted by refinery)
 	TypeMergeStarted = "merge_started"
 	TypeMerged       = "merged"
 	TypeMergeFailed  = "merge_failed"
 	TypeMergeSkipped = "merge_skipped"
+
+	// Bead lifecycle events
+	TypeBeadTransition = "bead_transition" // Bead state change (e.g., delegation garbage-collected)
+
+	// Crew events
+	TypeCrewHandover = "crew_handover" // A crew member handed their work to another at a shift change
+
+	// Boot watchdog events
+	TypeBootLockReclaimed = "boot_lock_reclaimed" // A crashed boot's lock was removed so a new boot could run
+
+	// Validation events
+	TypeEventInvalid = "event_invalid" // An event was logged with a payload that fails its schema
+
+	// Maintenance events
+	TypeDoctorFix = "doctor_fix" // gt doctor --fix applied a fix
+	TypeMigration = "migration"  // A bead or config schema migration ran
+
+	// Work queue events
+	TypeQueueClaim   = "queue_claim"   // A worker claimed a queue message
+	TypeQueueRelease = "queue_release" // A worker let go of a claimed queue message (see QueueStatus*)
 )
 
-// EventsFile is the name of the raw events log.
+// Queue release statuses: how a worker let go of a claimed queue message.
+const (
+	QueueStatusReleased  = "released"  // Returned to its queue unfinished
+	QueueStatusCompleted = "completed" // Done; the message is closed
+	QueueStatusFailed    = "failed"    // Failed; returned to its queue for another attempt
+)
+
+// destructiveTypes are the event types whose audit records name the OS user
+// and PID that performed them.
+var destructiveTypes = map[string]bool{
+	TypeKill:         true,
+	TypeDoctorFix:    true,
+	TypeQueueRelease: true,
+}
+
+// EventsFile is the name of the events log that feed-visible events go to.
 const EventsFile = ".events.jsonl"
 
+// AuditFile is the name of the audit log that audit-visible events go to.
+// There is one per town, in the town root; it is never rotated or pruned.
+const AuditFile = ".audit.jsonl"
+
 // mutex protects concurrent writes to the events file.
 var mutex sync.Mutex
 
-// Log writes an event to the events log.
-// The event is appended to ~/gt/.events.jsonl.
+// Log writes an event to the events log, the audit log or both, per
+// visibility.
 // Returns nil if logging fails (events are best-effort).
 func Log(eventType, actor string, payload map[string]interface{}, visibility string) error {
+	return LogContext(context.Background(), eventType, actor, payload, visibility)
+}
+
+// LogContext is Log for an event caused by the operation ctx belongs to: the
+// event carries ctx's correlation ID, if any.
+func LogContext(ctx context.Context, eventType, actor string, payload map[string]interface{}, visibility string) error {
 	event := Event{
-		Timestamp:  time.Now().UTC().Format(time.RFC3339),
-		Source:     "gt",
-		Type:       eventType,
-		Actor:      actor,
-		Payload:    payload,
-		Visibility: visibility,
+		Timestamp:     time.Now().UTC().Format(time.RFC3339),
+		Source:        "gt",
+		Type:          eventType,
+		Actor:         actor,
+		Payload:       payload,
+		Visibility:    visibility,
+		SchemaVersion: CurrentSchemaVersion,
+		ID:            newEventID(),
+		CorrelationID: CorrelationID(ctx),
 	}
-	return write(event)
+	return sink(event)
 }
 
 // LogFeed is a convenience wrapper for feed-visible events.
@@ -101,8 +180,38 @@ func LogAudit(eventType, actor string, payload map[string]interface{}) error {
 	return Log(eventType, actor, payload, VisibilityAudit)
 }
 
-// write appends an event to the events file.
+// LogFeedContext is LogFeed carrying ctx's correlation ID.
+func LogFeedContext(ctx context.Context, eventType, actor string, payload map[string]interface{}) error {
+	return LogContext(ctx, eventType, actor, payload, VisibilityFeed)
+}
+
+// LogAuditContext is LogAudit carrying ctx's correlation ID.
+func LogAuditContext(ctx context.Context, eventType, actor string, payload map[string]interface{}) error {
+	return LogContext(ctx, eventType, actor, payload, VisibilityAudit)
+}
+
+// sink receives every logged event; tests replace it to capture events.
+var sink = write
+
+// write appends an event to the events log and/or the audit log, per its
+// visibility, and dispatches its hooks. With per-rig events enabled,
+// feed events from rig actors go to the rig's file; the audit log is always
+// the town's. An event that fails validation is rejected in strict mode;
+// otherwise it is written anyway, followed by an event_invalid audit event.
 func write(event Event) error {
+	if event.Replayed {
+		return nil
+	}
+
+	var invalid *ValidationError
+	if err := Validate(event); err != nil {
+		invalidEvents.Add(1)
+		if StrictValidation() {
+			return err
+		}
+		invalid = err.(*ValidationError)
+	}
+
 	// Find town root
 	townRoot, err := workspace.FindFromCwd()
 	if err != nil || townRoot == "" {
@@ -110,29 +219,128 @@ func write(event Event) error {
 		return nil
 	}
 
-	eventsPath := filepath.Join(townRoot, EventsFile)
+	var feedLines, auditLines [][]byte
+	var audited *Event
+	if inFeed(event.Visibility) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("marshaling event: %w", err)
+		}
+		feedLines = append(feedLines, append(data, '\n'))
+	}
+	if inAudit(event.Visibility) {
+		record := event
+		if destructiveTypes[event.Type] {
+			record.OSUser, record.PID = osUser(), os.Getpid()
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("marshaling event: %w", err)
+		}
+		auditLines = append(auditLines, append(data, '\n'))
+		audited = &record
+	}
+
+	if invalid != nil {
+		warning, err := json.Marshal(Event{
+			Timestamp:     event.Timestamp,
+			Source:        event.Source,
+			Type:          TypeEventInvalid,
+			Actor:         event.Actor,
+			Payload:       invalidEventPayload(invalid),
+			Visibility:    VisibilityAudit,
+			SchemaVersion: CurrentSchemaVersion,
+			ID:            newEventID(),
+			CorrelationID: event.CorrelationID,
+		})
+		if err != nil {
+			return fmt.Errorf("marshaling event: %w", err)
+		}
+		auditLines = append(auditLines, append(warning, '\n'))
+	}
+
+	// Loaded once for both the per-rig option and the hooks.
+	settings, settingsErr := loadTownSettings(townRoot)
 
-	// Marshal event to JSON
-	data, err := json.Marshal(event)
-	if err != nil {
-		return fmt.Errorf("marshaling event: %w", err)
+	if len(feedLines) > 0 {
+		perRig := settingsErr == nil && settings.PerRigEvents
+		dir := logDir(townRoot, event.Actor, perRig)
+		if err := appendLines(dir, feedLines); err != nil {
+			return err
+		}
+	}
+	if len(auditLines) > 0 {
+		if err := appendAuditLines(townRoot, auditLines); err != nil {
+			return err
+		}
 	}
-	data = append(data, '\n')
+	if audited != nil && destructiveTypes[audited.Type] && complianceRecorder != nil {
+		complianceRecorder(townRoot, *audited)
+	}
+
+	// Run event hooks once the event is safely in the log
+	dispatch(townRoot, settings, settingsErr, event)
+	return nil
+}
+
+// complianceRecorder, if set, also receives each destructive event written
+// to the audit log, as it was written there; see SetComplianceRecorder.
+var complianceRecorder func(townRoot string, event Event)
 
-	// Append to file with proper locking
+// SetComplianceRecorder has fn called with every destructive event (a
+// kill, doctor fix or queue release) once it is in the audit log, so that
+// it can be kept in a tamper-evident compliance log too.
+func SetComplianceRecorder(fn func(townRoot string, event Event)) {
+	complianceRecorder = fn
+}
+
+// inFeed reports whether events of this visibility go to the events log.
+// Anything not marked audit-only does, including events written before
+// visibility was recorded.
+func inFeed(visibility string) bool {
+	return visibility != VisibilityAudit
+}
+
+// inAudit reports whether events of this visibility go to the audit log.
+func inAudit(visibility string) bool {
+	return visibility == VisibilityAudit || visibility == VisibilityBoth
+}
+
+// osUser returns the name of the OS user running this process; a var for
+// tests.
+var osUser = func() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// appendLines appends lines to the events file in dir (the town root or a
+// rig directory) with proper locking; the file is rotated once it is too big.
+func appendLines(dir string, lines [][]byte) error {
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	f, err := os.OpenFile(eventsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
-	if err != nil {
-		return fmt.Errorf("opening events file: %w", err)
+	policy := LoadRotationPolicy()
+	for _, line := range lines {
+		if err := appendEvent(dir, line, policy); err != nil {
+			return err
+		}
 	}
-	defer f.Close()
+	return nil
+}
 
-	if _, err := f.Write(data); err != nil {
-		return fmt.Errorf("writing event: %w", err)
-	}
+// appendAuditLines appends lines to the town's audit log with proper
+// locking. The audit log is never rotated.
+func appendAuditLines(townRoot string, lines [][]byte) error {
+	mutex.Lock()
+	defer mutex.Unlock()
 
+	for _, line := range lines {
+		if err := appendAudit(townRoot, line); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -180,6 +388,16 @@ func MailPayload(to, subject string) map[string]interface{} {
 	}
 }
 
+// MailBouncePayload creates a payload for mail bounce events.
+func MailBouncePayload(from, to, subject, reason string) map[string]interface{} {
+	return map[string]interface{}{
+		"from":    from,
+		"to":      to,
+		"subject": subject,
+		"reason":  reason,
+	}
+}
+
 // SpawnPayload creates a payload for spawn events.
 func SpawnPayload(rig, polecat string) map[string]interface{} {
 	return map[string]interface{}{
@@ -225,6 +443,20 @@ func PatrolPayload(rig string, polecatCount int, message string) map[string]inte
 	return p
 }
 
+// PatrolPluginPayload creates a payload for patrol plugin result events.
+// details is the plugin's own structured output and is omitted when empty.
+func PatrolPluginPayload(rig, pluginName string, status string, details map[string]interface{}) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":    rig,
+		"plugin": pluginName,
+		"status": status,
+	}
+	if len(details) > 0 {
+		p["details"] = details
+	}
+	return p
+}
+
 // PolecatCheckPayload creates a payload for polecat check events.
 func PolecatCheckPayload(rig, polecat, status, issue string) map[string]interface{} {
 	p := map[string]interface{}{
@@ -330,3 +562,93 @@ func SessionPayload(sessionID, role, topic, cwd string) map[string]interface{} {
 	}
 	return p
 }
+
+// BeadTransitionPayload creates a payload for bead transition events.
+// beadID: the bead whose state changed
+// from, to: previous and new state (e.g., "delegated", "collected")
+// reason: why the transition happened
+func BeadTransitionPayload(beadID, from, to, reason string) map[string]interface{} {
+	p := map[string]interface{}{
+		"bead": beadID,
+		"from": from,
+		"to":   to,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	return p
+}
+
+// CrewHandoverPayload creates a payload for crew_handover events.
+// fromCrew, toCrew: crew addresses (e.g., "gongshow/crew/max")
+// summary: the outgoing member's context notes
+// pendingBeads: beads reassigned to toCrew
+func CrewHandoverPayload(fromCrew, toCrew, summary string, pendingBeads []string) map[string]interface{} {
+	if pendingBeads == nil {
+		pendingBeads = []string{}
+	}
+	return map[string]interface{}{
+		"from":    fromCrew,
+		"to":      toCrew,
+		"summary": summary,
+		"beads":   pendingBeads,
+	}
+}
+
+// BootLockReclaimedPayload creates a payload for boot_lock_reclaimed events.
+// pid: the process the stale lock named, 0 if it named none
+// startedAt: when that process took the lock, empty if unknown
+func BootLockReclaimedPayload(pid int, startedAt string) map[string]interface{} {
+	p := map[string]interface{}{
+		"pid": pid,
+	}
+	if startedAt != "" {
+		p["started_at"] = startedAt
+	}
+	return p
+}
+
+// DoctorFixPayload creates a payload for doctor_fix events. fixErr is the
+// error the fix returned, if any.
+func DoctorFixPayload(check string, fixErr error) map[string]interface{} {
+	p := map[string]interface{}{
+		"check":   check,
+		"success": fixErr == nil,
+	}
+	if fixErr != nil {
+		p["error"] = fixErr.Error()
+	}
+	return p
+}
+
+// QueueClaimPayload creates a payload for queue_claim events.
+func QueueClaimPayload(queueName, taskID, workerID string) map[string]interface{} {
+	return map[string]interface{}{
+		"queue":  queueName,
+		"task":   taskID,
+		"worker": workerID,
+	}
+}
+
+// QueueReleasePayload creates a payload for queue_release events. status is
+// one of the QueueStatus* values.
+func QueueReleasePayload(queueName, taskID, workerID, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"queue":  queueName,
+		"task":   taskID,
+		"worker": workerID,
+		"status": status,
+	}
+}
+
+// MigrationPayload creates a payload for migration events: the migration
+// that ran, the schema versions it converted between, and how many items
+// (beads, config entries) it changed.
+func MigrationPayload(name, fromVersion, toVersion string, itemsConverted int) map[string]interface{} {
+	return map[string]interface{}{
+		"name":            name,
+		"from":            fromVersion,
+		"to":              toVersion,
+		"items_converted": itemsConverted,
+	}
+}
@@ -2,6 +2,9 @@ package events
 
 import (
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -43,6 +46,7 @@ func TestEventTypes(t *testing.T) {
 		{"TypeNudge", TypeNudge},
 		{"TypeBoot", TypeBoot},
 		{"TypeHalt", TypeHalt},
+		{"TypeMailBounce", TypeMailBounce},
 		{"TypeSessionStart", TypeSessionStart},
 		{"TypeSessionEnd", TypeSessionEnd},
 		{"TypeSessionDeath", TypeSessionDeath},
@@ -54,10 +58,18 @@ func TestEventTypes(t *testing.T) {
 		{"TypeEscalationAcked", TypeEscalationAcked},
 		{"TypeEscalationClosed", TypeEscalationClosed},
 		{"TypePatrolComplete", TypePatrolComplete},
+		{"TypePatrolPluginResult", TypePatrolPluginResult},
 		{"TypeMergeStarted", TypeMergeStarted},
 		{"TypeMerged", TypeMerged},
 		{"TypeMergeFailed", TypeMergeFailed},
 		{"TypeMergeSkipped", TypeMergeSkipped},
+		{"TypeCrewHandover", TypeCrewHandover},
+		{"TypeBootLockReclaimed", TypeBootLockReclaimed},
+		{"TypeEventInvalid", TypeEventInvalid},
+		{"TypeDoctorFix", TypeDoctorFix},
+		{"TypeMigration", TypeMigration},
+		{"TypeQueueClaim", TypeQueueClaim},
+		{"TypeQueueRelease", TypeQueueRelease},
 	}
 
 	for _, tc := range types {
@@ -245,6 +257,34 @@ func TestPatrolPayload(t *testing.T) {
 	})
 }
 
+func TestPatrolPluginPayload(t *testing.T) {
+	t.Run("with details", func(t *testing.T) {
+		details := map[string]interface{}{"free_gb": 3}
+		payload := PatrolPluginPayload("gongshow", "disk-space", "warn", details)
+
+		if payload["rig"] != "gongshow" {
+			t.Errorf("rig = %v, want %q", payload["rig"], "gongshow")
+		}
+		if payload["plugin"] != "disk-space" {
+			t.Errorf("plugin = %v, want %q", payload["plugin"], "disk-space")
+		}
+		if payload["status"] != "warn" {
+			t.Errorf("status = %v, want %q", payload["status"], "warn")
+		}
+		if got, ok := payload["details"].(map[string]interface{}); !ok || got["free_gb"] != 3 {
+			t.Errorf("details = %v, want %v", payload["details"], details)
+		}
+	})
+
+	t.Run("without details", func(t *testing.T) {
+		payload := PatrolPluginPayload("gongshow", "disk-space", "ok", nil)
+
+		if _, exists := payload["details"]; exists {
+			t.Error("details should not be present when empty")
+		}
+	})
+}
+
 func TestPolecatCheckPayload(t *testing.T) {
 	t.Run("with issue", func(t *testing.T) {
 		payload := PolecatCheckPayload("gongshow", "Toast", "working", "go-abc")
@@ -418,3 +458,75 @@ func TestEventsFile(t *testing.T) {
 		t.Errorf("EventsFile = %q, want %q", EventsFile, ".events.jsonl")
 	}
 }
+
+func TestLogRoutesByVisibility(t *testing.T) {
+	townRoot := setupTown(t)
+	if err := Log("load_test", "mayor", map[string]interface{}{"n": "feed"}, VisibilityFeed); err != nil {
+		t.Fatal(err)
+	}
+	if err := Log("load_test", "mayor", map[string]interface{}{"n": "audit"}, VisibilityAudit); err != nil {
+		t.Fatal(err)
+	}
+	if err := Log("load_test", "mayor", map[string]interface{}{"n": "both"}, VisibilityBoth); err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(events []Event) string {
+		var n []string
+		for _, e := range events {
+			n = append(n, e.Payload["n"].(string))
+		}
+		return strings.Join(n, ",")
+	}
+	if got := names(readEvents(t, townRoot)); got != "feed,both" {
+		t.Errorf("events log got %q, want feed,both", got)
+	}
+	if got := names(readEventsFile(t, filepath.Join(townRoot, AuditFile))); got != "audit,both" {
+		t.Errorf("audit log got %q, want audit,both", got)
+	}
+}
+
+func TestAuditRecordsNameTheProcessForDestructiveEvents(t *testing.T) {
+	townRoot := setupTown(t)
+	orig := osUser
+	osUser = func() string { return "alice" }
+	t.Cleanup(func() { osUser = orig })
+
+	if err := Log(TypeQueueRelease, "gongshow/polecats/Toast", QueueReleasePayload("work/gongshow", "hq-msg1", "gongshow/polecats/Toast", QueueStatusReleased), VisibilityBoth); err != nil {
+		t.Fatal(err)
+	}
+	if err := LogAudit(TypeBeadTransition, "mayor", BeadTransitionPayload("gt-abc", "delegated", "collected", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	audit := readEventsFile(t, filepath.Join(townRoot, AuditFile))
+	if len(audit) != 2 {
+		t.Fatalf("got %d audit events, want 2", len(audit))
+	}
+	if release := audit[0]; release.OSUser != "alice" || release.PID != os.Getpid() {
+		t.Errorf("queue_release audit record = user %q pid %d, want alice %d", release.OSUser, release.PID, os.Getpid())
+	}
+	if transition := audit[1]; transition.OSUser != "" || transition.PID != 0 {
+		t.Errorf("non-destructive event recorded user %q pid %d", transition.OSUser, transition.PID)
+	}
+	// The feed copy doesn't carry them.
+	if feed := readEvents(t, townRoot); len(feed) != 1 || feed[0].OSUser != "" || feed[0].PID != 0 {
+		t.Errorf("events log = %+v, want the release without user or pid", feed)
+	}
+}
+
+func TestRotationLeavesAuditLogAlone(t *testing.T) {
+	townRoot := setupTown(t)
+	t.Setenv("GT_EVENTS_MAX_SIZE", "1")
+	for i := 0; i < 3; i++ {
+		if err := Log("load_test", "mayor", map[string]interface{}{"seq": i}, VisibilityBoth); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(readEventsFile(t, filepath.Join(townRoot, AuditFile))); n != 3 {
+		t.Errorf("audit log has %d events after rotation, want 3", n)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, EventsFile)); !os.IsNotExist(err) {
+		t.Errorf("events log should have been rotated away: %v", err)
+	}
+}
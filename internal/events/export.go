@@ -0,0 +1,283 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportRow is one event flattened for export. Payload is the payload as a
+// JSON object ("" when the event has none).
+type ExportRow struct {
+	Timestamp     string
+	Unix          int64 // 0 if the timestamp doesn't parse
+	Type          string
+	Actor         string
+	Source        string
+	Visibility    string
+	SchemaVersion int
+	Payload       string
+}
+
+// HighWater marks how far a previous export got: the timestamp of the last
+// event exported, and how many exported events share that timestamp (event
+// timestamps have one-second resolution).
+type HighWater struct {
+	Timestamp string
+	Count     int
+}
+
+// ExportSink receives exported rows.
+type ExportSink interface {
+	// HighWater returns the mark left by the previous export into this sink
+	// (zero if there is none).
+	HighWater() (HighWater, error)
+
+	// Write stores one row.
+	Write(row ExportRow) error
+
+	// Close finishes the export, recording hw as the new high-water mark.
+	Close(hw HighWater) error
+}
+
+// ExportOptions controls Export.
+type ExportOptions struct {
+	// Since drops events older than this (zero exports everything).
+	Since time.Time
+
+	// Append skips events at or before the sink's high-water mark, so
+	// repeated exports only add new events.
+	Append bool
+}
+
+// ExportResult summarises an export.
+type ExportResult struct {
+	Exported  int // rows written
+	Present   int // events skipped as already exported (Append)
+	Malformed int // log lines that aren't events
+}
+
+// Export streams the events log (archives included) into sink.
+// The sink is closed on success; on error it is left for the caller to
+// discard.
+func Export(townRoot string, opts ExportOptions, sink ExportSink) (*ExportResult, error) {
+	var mark HighWater
+	var markTime time.Time
+	if opts.Append {
+		var err error
+		if mark, err = sink.HighWater(); err != nil {
+			return nil, fmt.Errorf("reading high-water mark: %w", err)
+		}
+		if mark.Timestamp != "" {
+			if markTime, err = time.Parse(time.RFC3339, mark.Timestamp); err != nil {
+				return nil, fmt.Errorf("bad high-water mark %q: %w", mark.Timestamp, err)
+			}
+		}
+	}
+
+	result := &ExportResult{}
+	hw := mark
+	seenAtMark := 0
+	skipped, err := Stream(context.Background(), townRoot, StreamOptions{
+		Filter:  Filter{Since: opts.Since},
+		History: -1,
+	}, func(_ string, e Event) error {
+		ts, tsErr := time.Parse(time.RFC3339, e.Timestamp)
+		if !markTime.IsZero() {
+			switch {
+			case tsErr != nil || ts.Before(markTime):
+				result.Present++
+				return nil
+			case ts.Equal(markTime) && seenAtMark < mark.Count:
+				seenAtMark++
+				result.Present++
+				return nil
+			}
+		}
+
+		row := ExportRow{
+			Timestamp:     e.Timestamp,
+			Type:          e.Type,
+			Actor:         e.Actor,
+			Source:        e.Source,
+			Visibility:    e.Visibility,
+			SchemaVersion: e.SchemaVersion,
+		}
+		if tsErr == nil {
+			row.Unix = ts.Unix()
+		}
+		if len(e.Payload) > 0 {
+			data, err := json.Marshal(e.Payload)
+			if err != nil {
+				return fmt.Errorf("encoding payload: %w", err)
+			}
+			row.Payload = string(data)
+		}
+		if err := sink.Write(row); err != nil {
+			return err
+		}
+		result.Exported++
+
+		if tsErr == nil {
+			if e.Timestamp == hw.Timestamp {
+				hw.Count++
+			} else if hwTime, err := time.Parse(time.RFC3339, hw.Timestamp); err != nil || ts.After(hwTime) {
+				hw = HighWater{Timestamp: e.Timestamp, Count: 1}
+			}
+		}
+		return nil
+	})
+	result.Malformed = skipped
+	if err != nil {
+		return result, err
+	}
+	return result, sink.Close(hw)
+}
+
+// ExportColumns are the exported fields, in CSV column order.
+var ExportColumns = []string{"ts", "ts_unix", "type", "actor", "source", "visibility", "schema_version", "payload"}
+
+// CSVSink writes rows as CSV with a header line. It has no high-water mark.
+type CSVSink struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVSink returns a sink writing CSV to w.
+func NewCSVSink(w io.Writer) *CSVSink {
+	return &CSVSink{w: csv.NewWriter(w)}
+}
+
+// HighWater implements ExportSink; CSV output is never appended to.
+func (s *CSVSink) HighWater() (HighWater, error) {
+	return HighWater{}, nil
+}
+
+// Write implements ExportSink.
+func (s *CSVSink) Write(row ExportRow) error {
+	if !s.header {
+		s.header = true
+		if err := s.w.Write(ExportColumns); err != nil {
+			return err
+		}
+	}
+	return s.w.Write([]string{
+		row.Timestamp,
+		strconv.FormatInt(row.Unix, 10),
+		row.Type,
+		row.Actor,
+		row.Source,
+		row.Visibility,
+		strconv.Itoa(row.SchemaVersion),
+		row.Payload,
+	})
+}
+
+// Close implements ExportSink.
+func (s *CSVSink) Close(HighWater) error {
+	if !s.header {
+		s.header = true
+		if err := s.w.Write(ExportColumns); err != nil {
+			return err
+		}
+	}
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// sqlSchema creates the export tables. It sticks to SQL that SQLite accepts.
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS events (
+		id INTEGER PRIMARY KEY,
+		ts TEXT NOT NULL,
+		ts_unix INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		actor TEXT NOT NULL,
+		source TEXT NOT NULL,
+		visibility TEXT NOT NULL,
+		schema_version INTEGER NOT NULL,
+		payload TEXT
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_events_type ON events(type)`,
+	`CREATE INDEX IF NOT EXISTS idx_events_ts ON events(ts_unix)`,
+	`CREATE TABLE IF NOT EXISTS export_state (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		last_ts TEXT NOT NULL,
+		last_ts_count INTEGER NOT NULL,
+		updated_at TEXT NOT NULL
+	)`,
+}
+
+// SQLSink writes rows into an SQL database (SQLite in practice) in a single
+// transaction, recording the high-water mark in the export_state table.
+type SQLSink struct {
+	db     *sql.DB
+	tx     *sql.Tx
+	insert *sql.Stmt
+}
+
+// NewSQLSink creates the export tables in db if needed and starts the
+// export transaction.
+func NewSQLSink(db *sql.DB) (*SQLSink, error) {
+	for _, stmt := range sqlSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, fmt.Errorf("creating schema: %w", err)
+		}
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	insert, err := tx.Prepare(`INSERT INTO events
+		(ts, ts_unix, type, actor, source, visibility, schema_version, payload)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	return &SQLSink{db: db, tx: tx, insert: insert}, nil
+}
+
+// HighWater implements ExportSink.
+func (s *SQLSink) HighWater() (HighWater, error) {
+	var hw HighWater
+	err := s.tx.QueryRow(`SELECT last_ts, last_ts_count FROM export_state WHERE id = 1`).Scan(&hw.Timestamp, &hw.Count)
+	if err == sql.ErrNoRows {
+		return HighWater{}, nil
+	}
+	return hw, err
+}
+
+// Write implements ExportSink.
+func (s *SQLSink) Write(row ExportRow) error {
+	var payload interface{}
+	if row.Payload != "" {
+		payload = row.Payload
+	}
+	_, err := s.insert.Exec(row.Timestamp, row.Unix, row.Type, row.Actor, row.Source, row.Visibility, row.SchemaVersion, payload)
+	return err
+}
+
+// Close implements ExportSink: it records hw and commits.
+func (s *SQLSink) Close(hw HighWater) error {
+	_ = s.insert.Close()
+	if hw.Timestamp != "" {
+		if _, err := s.tx.Exec(`INSERT OR REPLACE INTO export_state (id, last_ts, last_ts_count, updated_at) VALUES (1, ?, ?, ?)`,
+			hw.Timestamp, hw.Count, time.Now().UTC().Format(time.RFC3339)); err != nil {
+			_ = s.tx.Rollback()
+			return fmt.Errorf("recording high-water mark: %w", err)
+		}
+	}
+	return s.tx.Commit()
+}
+
+// Abort discards everything written to the sink.
+func (s *SQLSink) Abort() error {
+	_ = s.insert.Close()
+	return s.tx.Rollback()
+}
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// openExportDB opens a fresh SQLite database for an export test.
+func openExportDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func exportSQL(t *testing.T, db *sql.DB, townRoot string, opts ExportOptions) *ExportResult {
+	t.Helper()
+	sink, err := NewSQLSink(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := Export(townRoot, opts, sink)
+	if err != nil {
+		_ = sink.Abort()
+		t.Fatal(err)
+	}
+	return result
+}
+
+func countRows(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestExportSQL(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	writeCorpus(t, townRoot, start, 10) // 20 events, archives included
+
+	db := openExportDB(t)
+
+	result := exportSQL(t, db, townRoot, ExportOptions{})
+	if result.Exported != 20 || result.Present != 0 {
+		t.Fatalf("first export = %+v, want 20 exported", result)
+	}
+	if n := countRows(t, db, `SELECT count(*) FROM events`); n != 20 {
+		t.Fatalf("events table has %d rows, want 20", n)
+	}
+
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'index' AND tbl_name = 'events' ORDER BY name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var indexes []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		indexes = append(indexes, name)
+	}
+	rows.Close()
+	if strings.Join(indexes, ",") != "idx_events_ts,idx_events_type" {
+		t.Errorf("indexes = %v, want idx_events_ts and idx_events_type", indexes)
+	}
+
+	var ts string
+	var tsUnix int64
+	var typ string
+	if err := db.QueryRow(`SELECT ts, ts_unix, type FROM events ORDER BY ts_unix, rowid LIMIT 1`).Scan(&ts, &tsUnix, &typ); err != nil {
+		t.Fatal(err)
+	}
+	if ts != start.Format(time.RFC3339) || tsUnix != start.Unix() || typ != TypeSessionDeath {
+		t.Errorf("first row = %s %d %s", ts, tsUnix, typ)
+	}
+	// The payload is JSON that json_extract can query.
+	if n := countRows(t, db, `SELECT count(*) FROM events WHERE json_extract(payload, '$.session') = ?`, "gt-gongshow-p0"); n == 0 {
+		t.Error("json_extract found no payload with session gt-gongshow-p0")
+	}
+
+	// Both events of the last minute share a timestamp.
+	var lastTS string
+	var lastCount int
+	if err := db.QueryRow(`SELECT last_ts, last_ts_count FROM export_state WHERE id = 1`).Scan(&lastTS, &lastCount); err != nil {
+		t.Fatal(err)
+	}
+	if want := start.Add(9 * time.Minute).Format(time.RFC3339); lastTS != want || lastCount != 2 {
+		t.Errorf("high-water mark = %s x%d, want %s x2", lastTS, lastCount, want)
+	}
+
+	// Appending with nothing new adds nothing.
+	result = exportSQL(t, db, townRoot, ExportOptions{Append: true})
+	if result.Exported != 0 || result.Present != 20 {
+		t.Errorf("idempotent append = %+v", result)
+	}
+
+	// New events, including one sharing the high-water timestamp, are added once.
+	writeCorpus(t, townRoot, start.Add(9*time.Minute), 3)
+	result = exportSQL(t, db, townRoot, ExportOptions{Append: true})
+	if n := countRows(t, db, `SELECT count(*) FROM events`); result.Exported != 6 || n != 26 {
+		t.Errorf("incremental append = %+v, table has %d rows", result, n)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	writeCorpus(t, townRoot, start, 5)
+
+	var buf bytes.Buffer
+	result, err := Export(townRoot, ExportOptions{Since: start.Add(3 * time.Minute)}, NewCSVSink(&buf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Exported != 4 {
+		t.Errorf("exported %d, want 4", result.Exported)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 5 || strings.Join(records[0], ",") != strings.Join(ExportColumns, ",") {
+		t.Fatalf("records = %v", records)
+	}
+	if records[1][2] != TypeSessionDeath || !strings.Contains(records[1][7], `"seq":3`) {
+		t.Errorf("first row = %v", records[1])
+	}
+}
@@ -0,0 +1,394 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/notify"
+	"github.com/KeithWyatt/gongshow/internal/workspace"
+)
+
+// DefaultHookTimeout bounds a hook run when its config sets no timeout.
+const DefaultHookTimeout = 30 * time.Second
+
+// HookEnvVar is set in the environment of hook commands. Events logged while
+// it is set don't trigger hooks, so a hook can't set itself off.
+const HookEnvVar = "GT_EVENT_HOOK"
+
+// hookLogFile records hook failures, relative to the town root.
+const hookLogFile = "logs/event-hooks.log"
+
+// MailSender sends mail on behalf of a hook. The events package can't import
+// mail (mail logs events), so the mail action is wired in with SetMailSender.
+// The mail should carry ctx's correlation ID, as should any events logged
+// while sending it.
+type MailSender func(ctx context.Context, townRoot, to, subject, body string) error
+
+var (
+	mailSenderMu sync.RWMutex
+	mailSender   MailSender
+)
+
+// SetMailSender sets the function hooks use to send mail.
+func SetMailSender(fn MailSender) {
+	mailSenderMu.Lock()
+	defer mailSenderMu.Unlock()
+	mailSender = fn
+}
+
+// sendNotifications is notify.SendAll; a var for tests.
+var sendNotifications = notify.SendAll
+
+// loadTownSettings reads the town settings, which hold the hooks and the
+// per-rig events option; a var for tests.
+var loadTownSettings = func(townRoot string) (*config.TownSettings, error) {
+	return config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+}
+
+// mailingChains holds the correlation IDs of the hook mail being sent by
+// this process. Events in those chains, such as the bounce of an
+// undeliverable hook mail, don't dispatch hooks: the in-process
+// counterpart of HookEnvVar.
+var mailingChains = struct {
+	sync.Mutex
+	m map[string]int
+}{m: make(map[string]int)}
+
+// mailing reports whether e was logged while sending a hook's mail.
+func mailing(e Event) bool {
+	if e.CorrelationID == "" {
+		return false
+	}
+	mailingChains.Lock()
+	defer mailingChains.Unlock()
+	return mailingChains.m[e.CorrelationID] > 0
+}
+
+// pendingHooks tracks asynchronous hook runs so WaitForHooks can let them
+// finish before the process exits.
+var pendingHooks sync.WaitGroup
+
+// HookName returns the hook's name, or "hook-<n>" for the nth hook.
+func HookName(h config.EventHook, index int) string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return fmt.Sprintf("hook-%d", index+1)
+}
+
+// HookFilter converts a hook's match config to a Filter.
+func HookFilter(m config.EventHookMatch) Filter {
+	f := Filter{Types: m.Types, Payload: m.Payload}
+	if m.Actor != "" {
+		pattern := m.Actor
+		f.Actor = func(actor string) bool { return MatchGlob(pattern, actor) }
+	}
+	return f
+}
+
+// MatchingHooks returns the indexes of the hooks that e triggers.
+func MatchingHooks(hooks []config.EventHook, e Event) []int {
+	var matched []int
+	for i, h := range hooks {
+		if HookFilter(h.Match).Match(&e) {
+			matched = append(matched, i)
+		}
+	}
+	return matched
+}
+
+// HookTimeout returns the hook's run timeout.
+func HookTimeout(h config.EventHook) time.Duration {
+	if d, err := time.ParseDuration(h.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultHookTimeout
+}
+
+// HookEnv returns the GT_EVENT_* variables describing e: the event fields,
+// the raw event as JSON, and one GT_EVENT_PAYLOAD_<KEY> per payload key
+// (lists are comma-separated). GT_EVENT_REPLAYED=1 marks a replayed event.
+// Sorted by name.
+func HookEnv(e Event) []string {
+	env := []string{
+		"GT_EVENT_TYPE=" + e.Type,
+		"GT_EVENT_ACTOR=" + e.Actor,
+		"GT_EVENT_TS=" + e.Timestamp,
+		"GT_EVENT_SOURCE=" + e.Source,
+		"GT_EVENT_VISIBILITY=" + e.Visibility,
+	}
+	if e.CorrelationID != "" {
+		env = append(env, "GT_EVENT_CORRELATION_ID="+e.CorrelationID)
+	}
+	if e.Replayed {
+		env = append(env, "GT_EVENT_REPLAYED=1")
+	}
+	if data, err := json.Marshal(e); err == nil {
+		env = append(env, "GT_EVENT_JSON="+string(data))
+	}
+	for key, v := range e.Payload {
+		env = append(env, "GT_EVENT_PAYLOAD_"+envKey(key)+"="+envValue(v))
+	}
+	sort.Strings(env)
+	return env
+}
+
+// envKey upper-cases a payload key and replaces characters that aren't
+// valid in environment variable names.
+func envKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+func envValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []string:
+		return strings.Join(v, ",")
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, p := range v {
+			parts[i] = fmt.Sprint(p)
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v)
+}
+
+// Dispatch runs the town's hooks that match e, asynchronously. It never
+// blocks on or fails because of a hook: failures are written to
+// logs/event-hooks.log in the town root.
+func Dispatch(e Event) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	settings, err := loadTownSettings(townRoot)
+	dispatch(townRoot, settings, err, e)
+}
+
+// dispatch runs the hooks in settings that match e; settingsErr is the
+// error loading them, if any.
+func dispatch(townRoot string, settings *config.TownSettings, settingsErr error, e Event) {
+	if os.Getenv(HookEnvVar) != "" || mailing(e) {
+		return
+	}
+	if settingsErr != nil {
+		logHookFailure(townRoot, "config", e, settingsErr)
+		return
+	}
+	hooks := settings.Hooks
+	for _, i := range MatchingHooks(hooks, e) {
+		hook, name := hooks[i], HookName(hooks[i], i)
+		pendingHooks.Add(1)
+		go func() {
+			defer pendingHooks.Done()
+			if err := RunHook(context.Background(), townRoot, hook, e); err != nil {
+				logHookFailure(townRoot, name, e, err)
+			}
+		}()
+	}
+}
+
+// WaitForHooks waits for hooks started by Dispatch to finish. Each run is
+// bounded by its timeout, so this returns within the longest hook timeout.
+func WaitForHooks() {
+	pendingHooks.Wait()
+}
+
+// RunHook runs all of a hook's actions for e, within the hook's timeout.
+// Every action is attempted; the errors are joined. For a replayed event the
+// mail and notify actions are skipped: only the command runs, and it can
+// check GT_EVENT_REPLAYED itself.
+func RunHook(ctx context.Context, townRoot string, hook config.EventHook, e Event) error {
+	ctx, cancel := context.WithTimeout(WithCorrelation(ctx, e.CorrelationID), HookTimeout(hook))
+	defer cancel()
+
+	env := HookEnv(e)
+	expand := func(s string) string {
+		return os.Expand(s, func(name string) string {
+			for _, kv := range env {
+				if k, v, _ := strings.Cut(kv, "="); k == name {
+					return v
+				}
+			}
+			return ""
+		})
+	}
+
+	var errs []string
+	if hook.Command != "" {
+		if err := runHookCommand(ctx, townRoot, hook.Command, env); err != nil {
+			errs = append(errs, fmt.Sprintf("command: %v", err))
+		}
+	}
+	if hook.Mail != nil && !e.Replayed {
+		if err := sendHookMail(ctx, townRoot, hook.Mail, e, expand); err != nil {
+			errs = append(errs, fmt.Sprintf("mail: %v", err))
+		}
+	}
+	if hook.Notify != nil && !e.Replayed {
+		if err := sendHookNotify(ctx, townRoot, hook.Notify, e, expand); err != nil {
+			errs = append(errs, fmt.Sprintf("notify: %v", err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func runHookCommand(ctx context.Context, townRoot, command string, env []string) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // G204: command comes from the town's own settings
+	cmd.Dir = townRoot
+	cmd.Env = append(append(os.Environ(), env...), HookEnvVar+"=1")
+	if id := CorrelationID(ctx); id != "" {
+		// gt commands the hook runs join the triggering event's chain.
+		cmd.Env = append(cmd.Env, CorrelationEnv+"="+id)
+	}
+	cmd.WaitDelay = time.Second // don't hang on pipes held by orphaned children
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out")
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// hookSubject is the default mail subject and notification title.
+func hookSubject(e Event) string {
+	return fmt.Sprintf("[gt event] %s from %s", e.Type, e.Actor)
+}
+
+// hookBody describes e for mail and notification bodies.
+func hookBody(e Event) string {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return hookSubject(e)
+	}
+	return string(data)
+}
+
+// runWithContext runs fn, giving up when ctx is done. fn keeps running in
+// the background if it overruns; senders have their own network timeouts.
+func runWithContext(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out")
+	}
+}
+
+func sendHookMail(ctx context.Context, townRoot string, m *config.EventHookMail, e Event, expand func(string) string) error {
+	mailSenderMu.RLock()
+	send := mailSender
+	mailSenderMu.RUnlock()
+	if send == nil {
+		return fmt.Errorf("mail is not available")
+	}
+	if m.To == "" {
+		return fmt.Errorf("no recipient")
+	}
+	subject := hookSubject(e)
+	if m.Subject != "" {
+		subject = expand(m.Subject)
+	}
+
+	// Mark the chain for the whole send, which can outlive ctx.
+	chain := CorrelationID(ctx)
+	if chain == "" {
+		chain = NewCorrelationID()
+		ctx = WithCorrelation(ctx, chain)
+	}
+	mailingChains.Lock()
+	mailingChains.m[chain]++
+	mailingChains.Unlock()
+	return runWithContext(ctx, func() error {
+		defer func() {
+			mailingChains.Lock()
+			if mailingChains.m[chain]--; mailingChains.m[chain] <= 0 {
+				delete(mailingChains.m, chain)
+			}
+			mailingChains.Unlock()
+		}()
+		return send(ctx, townRoot, m.To, subject, hookBody(e))
+	})
+}
+
+func sendHookNotify(ctx context.Context, townRoot string, n *config.EventHookNotify, e Event, expand func(string) string) error {
+	if len(n.Targets) == 0 {
+		return fmt.Errorf("no targets")
+	}
+	notification := &notify.Notification{
+		ID:        e.Type,
+		Severity:  n.Severity,
+		Title:     hookSubject(e),
+		Body:      hookBody(e),
+		Source:    e.Actor,
+		Timestamp: time.Now(),
+	}
+	if notification.Severity == "" {
+		notification.Severity = "high"
+	}
+	if n.Title != "" {
+		notification.Title = expand(n.Title)
+	}
+	targets := make([]notify.Target, len(n.Targets))
+	for i, t := range n.Targets {
+		targets[i] = notify.Target{Channel: t.Channel, Address: t.Address}
+	}
+
+	return runWithContext(ctx, func() error {
+		var failed []string
+		for _, r := range sendNotifications(townRoot, targets, notification) {
+			if !r.Success && !r.Deferred {
+				failed = append(failed, fmt.Sprintf("%s: %s", r.Channel, r.Message))
+			}
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%s", strings.Join(failed, "; "))
+		}
+		return nil
+	})
+}
+
+// logHookFailure appends a line to the hook failure log. Errors writing it
+// are ignored: hooks are best-effort, like events.
+func logHookFailure(townRoot, hook string, e Event, err error) {
+	path := filepath.Join(townRoot, hookLogFile)
+	if mkErr := os.MkdirAll(filepath.Dir(path), 0755); mkErr != nil {
+		return
+	}
+	f, openErr := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644) //nolint:gosec // G302: log file is non-sensitive operational data
+	if openErr != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = fmt.Fprintf(f, "%s hook=%s event=%s actor=%s error=%q\n",
+		time.Now().UTC().Format(time.RFC3339), hook, e.Type, e.Actor, err.Error())
+}
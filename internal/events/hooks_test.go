@@ -0,0 +1,214 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/notify"
+)
+
+func witnessDeath() Event {
+	return Event{
+		Timestamp:  "2024-01-15T10:00:00Z",
+		Source:     "gt",
+		Type:       TypeSessionDeath,
+		Actor:      "gongshow/witness",
+		Payload:    SessionDeathPayload("gt-gongshow-witness", "gongshow/witness", "zombie cleanup", "daemon"),
+		Visibility: VisibilityFeed,
+	}
+}
+
+func TestMatchingHooks(t *testing.T) {
+	hooks := []config.EventHook{
+		{Name: "any-witness-death", Match: config.EventHookMatch{Types: []string{TypeSessionDeath}, Actor: "*/witness"}},
+		{Name: "polecat-death", Match: config.EventHookMatch{Types: []string{TypeSessionDeath}, Actor: "*/polecats/*"}},
+		{Name: "by-payload", Match: config.EventHookMatch{Payload: map[string]string{"caller": "dae*"}}},
+		{Name: "spawn-or-kill", Match: config.EventHookMatch{Types: []string{TypeSpawn, TypeKill}}},
+		{Name: "everything"},
+	}
+
+	if got, want := MatchingHooks(hooks, witnessDeath()), []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchingHooks = %v, want %v", got, want)
+	}
+
+	spawn := Event{Type: TypeSpawn, Actor: "mayor", Payload: SpawnPayload("gongshow", "Toast")}
+	if got, want := MatchingHooks(hooks, spawn), []int{3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MatchingHooks = %v, want %v", got, want)
+	}
+
+	if got := HookName(hooks[4], 4); got != "everything" {
+		t.Errorf("HookName = %q", got)
+	}
+	if got := HookName(config.EventHook{}, 2); got != "hook-3" {
+		t.Errorf("HookName = %q, want hook-3", got)
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	e := witnessDeath()
+	e.Payload["sessions"] = []interface{}{"a", "b"}
+	e.Payload["odd-key"] = 3.0
+
+	env := make(map[string]string)
+	for _, kv := range HookEnv(e) {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+	want := map[string]string{
+		"GT_EVENT_TYPE":             TypeSessionDeath,
+		"GT_EVENT_ACTOR":            "gongshow/witness",
+		"GT_EVENT_TS":               "2024-01-15T10:00:00Z",
+		"GT_EVENT_PAYLOAD_SESSION":  "gt-gongshow-witness",
+		"GT_EVENT_PAYLOAD_CALLER":   "daemon",
+		"GT_EVENT_PAYLOAD_SESSIONS": "a,b",
+		"GT_EVENT_PAYLOAD_ODD_KEY":  "3",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+	if !strings.Contains(env["GT_EVENT_JSON"], `"type":"session_death"`) {
+		t.Errorf("GT_EVENT_JSON = %q", env["GT_EVENT_JSON"])
+	}
+}
+
+func TestRunHookCommandEnv(t *testing.T) {
+	townRoot := t.TempDir()
+	hook := config.EventHook{
+		Command: `printf '%s|%s|%s|%s' "$GT_EVENT_TYPE" "$GT_EVENT_ACTOR" "$GT_EVENT_PAYLOAD_SESSION" "$GT_EVENT_HOOK" > out.txt`,
+	}
+	if err := RunHook(context.Background(), townRoot, hook, witnessDeath()); err != nil {
+		t.Fatalf("RunHook: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), "session_death|gongshow/witness|gt-gongshow-witness|1"; got != want {
+		t.Errorf("command saw %q, want %q", got, want)
+	}
+}
+
+func TestRunHookTimeout(t *testing.T) {
+	hook := config.EventHook{Command: "sleep 10", Timeout: "100ms"}
+	start := time.Now()
+	err := RunHook(context.Background(), t.TempDir(), hook, witnessDeath())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("RunHook = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("RunHook took %v, timeout not enforced", elapsed)
+	}
+	if got := HookTimeout(config.EventHook{}); got != DefaultHookTimeout {
+		t.Errorf("default timeout = %v", got)
+	}
+}
+
+func TestRunHookMailAndNotify(t *testing.T) {
+	var mailed []string
+	SetMailSender(func(_ context.Context, townRoot, to, subject, body string) error {
+		mailed = append(mailed, to+": "+subject)
+		return nil
+	})
+	t.Cleanup(func() { SetMailSender(nil) })
+
+	var notified []*notify.Notification
+	orig := sendNotifications
+	sendNotifications = func(townRoot string, targets []notify.Target, n *notify.Notification) []*notify.Result {
+		notified = append(notified, n)
+		return []*notify.Result{{Channel: targets[0].Channel, Success: true}}
+	}
+	t.Cleanup(func() { sendNotifications = orig })
+
+	hook := config.EventHook{
+		Mail: &config.EventHookMail{To: "overseer", Subject: "$GT_EVENT_PAYLOAD_SESSION died"},
+		Notify: &config.EventHookNotify{
+			Targets: []config.EventHookTarget{{Channel: notify.ChannelSMS, Address: "+15551234567"}},
+		},
+	}
+	if err := RunHook(context.Background(), t.TempDir(), hook, witnessDeath()); err != nil {
+		t.Fatalf("RunHook: %v", err)
+	}
+	if want := []string{"overseer: gt-gongshow-witness died"}; !reflect.DeepEqual(mailed, want) {
+		t.Errorf("mailed = %v, want %v", mailed, want)
+	}
+	if len(notified) != 1 || notified[0].Severity != "high" || !strings.Contains(notified[0].Title, "session_death") {
+		t.Errorf("notified = %+v", notified)
+	}
+}
+
+func TestDispatchRunsMatchingHooksAndLogsFailures(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv(HookEnvVar, "")
+
+	settings := &config.TownSettings{Hooks: []config.EventHook{
+		{Name: "touch", Match: config.EventHookMatch{Actor: "*/witness"}, Command: "touch ran.txt"},
+		{Name: "broken", Match: config.EventHookMatch{Actor: "*/witness"}, Command: "echo boom; exit 3"},
+		{Name: "other", Match: config.EventHookMatch{Actor: "mayor"}, Command: "touch other.txt"},
+	}}
+
+	dispatch(townRoot, settings, nil, witnessDeath())
+	WaitForHooks()
+
+	if _, err := os.Stat(filepath.Join(townRoot, "ran.txt")); err != nil {
+		t.Errorf("matching hook did not run: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, "other.txt")); err == nil {
+		t.Error("non-matching hook ran")
+	}
+	log, err := os.ReadFile(filepath.Join(townRoot, hookLogFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(log), "hook=broken event=session_death") || !strings.Contains(string(log), "boom") {
+		t.Errorf("failure log = %q", log)
+	}
+	if strings.Contains(string(log), "hook=touch") {
+		t.Errorf("successful hook logged as failure: %q", log)
+	}
+
+	// Events logged from inside a hook don't dispatch.
+	t.Setenv(HookEnvVar, "1")
+	_ = os.Remove(filepath.Join(townRoot, "ran.txt"))
+	dispatch(townRoot, settings, nil, witnessDeath())
+	WaitForHooks()
+	if _, err := os.Stat(filepath.Join(townRoot, "ran.txt")); err == nil {
+		t.Error("hook ran for an event logged by a hook")
+	}
+}
+
+func TestHookMailDoesNotRetriggerHooks(t *testing.T) {
+	townRoot := t.TempDir()
+	t.Setenv(HookEnvVar, "")
+
+	// A hook that mails on every bounce, whose mail itself bounces.
+	settings := &config.TownSettings{Hooks: []config.EventHook{{
+		Name:  "bounces",
+		Match: config.EventHookMatch{Types: []string{TypeMailBounce}},
+		Mail:  &config.EventHookMail{To: "nobody/"},
+	}}}
+	var sends atomic.Int32
+	SetMailSender(func(ctx context.Context, _, to, subject, _ string) error {
+		if sends.Add(1) > 5 {
+			return nil
+		}
+		bounce := Event{Type: TypeMailBounce, Actor: "mailer-daemon", CorrelationID: CorrelationID(ctx)}
+		dispatch(townRoot, settings, nil, bounce)
+		return nil
+	})
+	t.Cleanup(func() { SetMailSender(nil) })
+
+	dispatch(townRoot, settings, nil, Event{Type: TypeMailBounce, Actor: "mailer-daemon"})
+	WaitForHooks()
+	if n := sends.Load(); n != 1 {
+		t.Errorf("hook mail sent %d times, want once", n)
+	}
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// townActors are actor address roots that belong to the town, not a rig.
+var townActors = map[string]bool{
+	"mayor":    true,
+	"deacon":   true,
+	"overseer": true,
+	"daemon":   true,
+	"settings": true,
+	"logs":     true,
+}
+
+// ActorRig returns the rig an actor address belongs to ("gongshow" for
+// "gongshow/polecats/Toast"), or "" for town-level actors such as "mayor/"
+// and for rigs that have no directory in the town root.
+func ActorRig(townRoot, actor string) string {
+	rig, rest, ok := strings.Cut(actor, "/")
+	if !ok || rest == "" || rig == "" || townActors[rig] || strings.HasPrefix(rig, ".") {
+		return ""
+	}
+	if info, err := os.Stat(filepath.Join(townRoot, rig)); err != nil || !info.IsDir() {
+		return ""
+	}
+	return rig
+}
+
+// logDir returns the directory whose events log an event from actor is
+// appended to: the actor's rig directory when perRig is set, otherwise the
+// town root.
+func logDir(townRoot, actor string, perRig bool) string {
+	if perRig {
+		if rig := ActorRig(townRoot, actor); rig != "" {
+			return filepath.Join(townRoot, rig)
+		}
+	}
+	return townRoot
+}
+
+// LogDirs returns the directories holding an events log: the town root
+// first, then each rig directory with one, by name.
+func LogDirs(townRoot string) ([]string, error) {
+	entries, err := os.ReadDir(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	dirs := []string{townRoot}
+	var rigs []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || townActors[e.Name()] {
+			continue
+		}
+		if hasLog(filepath.Join(townRoot, e.Name())) {
+			rigs = append(rigs, e.Name())
+		}
+	}
+	sort.Strings(rigs)
+	for _, rig := range rigs {
+		dirs = append(dirs, filepath.Join(townRoot, rig))
+	}
+	return dirs, nil
+}
+
+// hasLog reports whether dir has an active events file or an archive.
+func hasLog(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, EventsFile)); err == nil {
+		return true
+	}
+	archives, _ := listArchives(dir)
+	return len(archives) > 0
+}
+
+// newEventID returns a random event ID, used to deduplicate events that
+// appear in more than one log file.
+func newEventID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,296 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// appendTo appends e to the events log in dir (created if needed).
+func appendTo(t *testing.T, dir string, e Event, policy RotationPolicy) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := appendEvent(dir, append(data, '\n'), policy); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeInterleaved spreads n events, one a minute, round-robin over the town
+// log and two rig logs. The "gongshow" rig rotates often, so its events span
+// several archives.
+func writeInterleaved(t *testing.T, townRoot string, start time.Time, n int) {
+	t.Helper()
+	dirs := []string{townRoot, filepath.Join(townRoot, "gongshow"), filepath.Join(townRoot, "other")}
+	actors := []string{"mayor/", "gongshow/witness", "other/refinery"}
+	for i := 0; i < n; i++ {
+		policy := RotationPolicy{}
+		if i%3 == 1 {
+			policy.MaxSize = 300
+		}
+		appendTo(t, dirs[i%3], Event{
+			Timestamp: start.Add(time.Duration(i) * time.Minute).Format(time.RFC3339),
+			Type:      TypeNudge,
+			Actor:     actors[i%3],
+			Payload:   map[string]interface{}{"seq": i},
+			ID:        fmt.Sprintf("ev%d", i),
+		}, policy)
+	}
+}
+
+func seqs(evs []Event) string {
+	var s []string
+	for _, e := range evs {
+		s = append(s, fmt.Sprint(e.Payload["seq"]))
+	}
+	return strings.Join(s, ",")
+}
+
+func wantSeqs(from, to int) string {
+	var s []string
+	for i := from; i < to; i++ {
+		s = append(s, fmt.Sprint(i))
+	}
+	return strings.Join(s, ",")
+}
+
+func TestMergedLogOrdering(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	writeInterleaved(t, townRoot, start, 30)
+
+	if archives, _ := listArchives(filepath.Join(townRoot, "gongshow")); len(archives) == 0 {
+		t.Fatal("expected the gongshow log to have rotated")
+	}
+
+	t.Run("query", func(t *testing.T) {
+		var got []Event
+		if _, err := Query(townRoot, QueryOptions{}, func(_ string, e Event) error {
+			got = append(got, e)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if seqs(got) != wantSeqs(0, 30) {
+			t.Errorf("query order = %s", seqs(got))
+		}
+	})
+
+	t.Run("query reverse with limit", func(t *testing.T) {
+		var got []Event
+		if _, err := Query(townRoot, QueryOptions{Reverse: true, Limit: 4}, func(_ string, e Event) error {
+			got = append(got, e)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if seqs(got) != "29,28,27,26" {
+			t.Errorf("reverse order = %s", seqs(got))
+		}
+	})
+
+	t.Run("open log", func(t *testing.T) {
+		r, err := OpenLog(townRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []Event
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var e Event
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatalf("bad line %q: %v", line, err)
+			}
+			got = append(got, e)
+		}
+		if seqs(got) != wantSeqs(0, 30) {
+			t.Errorf("OpenLog order = %s", seqs(got))
+		}
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		stats, err := CollectStats(townRoot, StatsOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Total != 30 {
+			t.Errorf("stats total = %d, want 30", stats.Total)
+		}
+	})
+
+	t.Run("log files", func(t *testing.T) {
+		files, err := LogFiles(townRoot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events := 0
+		for _, f := range files {
+			report, err := ValidateFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			events += report.Events
+		}
+		if events != 30 || filepath.Dir(files[0]) != townRoot {
+			t.Errorf("LogFiles = %v (%d events)", files, events)
+		}
+	})
+}
+
+func TestMergedLogDeduplicates(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	writeInterleaved(t, townRoot, start, 6)
+
+	// A split interrupted halfway leaves copies in both logs.
+	dup := Event{Timestamp: start.Add(time.Minute).Format(time.RFC3339), Type: TypeNudge, Actor: "gongshow/witness",
+		Payload: map[string]interface{}{"seq": 1}, ID: "ev1"}
+	appendTo(t, townRoot, dup, RotationPolicy{})
+
+	var got []Event
+	if _, err := Query(townRoot, QueryOptions{}, func(_ string, e Event) error {
+		got = append(got, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if seqs(got) != wantSeqs(0, 6) {
+		t.Errorf("order = %s, want each event once", seqs(got))
+	}
+}
+
+func TestSeenIDsForgetsOutsideWindow(t *testing.T) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	seen := newSeenIDs()
+	for i := 0; i < 1000; i++ {
+		if !seen.add(fmt.Sprintf("ev%d", i), start.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("ev%d reported as a duplicate", i)
+		}
+	}
+	// Copies arrive at the same timestamp, so recent IDs are still known.
+	if seen.add("ev999", start.Add(999*time.Second)) {
+		t.Error("duplicate of the newest event not dropped")
+	}
+	if n := len(seen.ids); n > int(2*seenWindow/time.Second)+1 {
+		t.Errorf("remembering %d IDs, want at most two windows' worth", n)
+	}
+}
+
+func TestTailerMergesRigLogs(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	writeInterleaved(t, townRoot, start, 3)
+
+	tailer, err := NewTailer(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tailer.Close()
+
+	at := func(seq int, dir, actor string) {
+		appendTo(t, dir, Event{
+			Timestamp: start.Add(time.Duration(seq) * time.Minute).Format(time.RFC3339),
+			Type:      TypeNudge,
+			Actor:     actor,
+			Payload:   map[string]interface{}{"seq": seq},
+			ID:        fmt.Sprintf("ev%d", seq),
+		}, RotationPolicy{})
+	}
+	// Written out of order across files, including a rig that had no log
+	// when the tailer started.
+	at(5, townRoot, "mayor/")
+	at(3, filepath.Join(townRoot, "gongshow"), "gongshow/witness")
+	at(4, filepath.Join(townRoot, "newrig"), "newrig/witness")
+
+	var got []Event
+	for _, line := range tailer.Lines() {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e)
+	}
+	if seqs(got) != "3,4,5" {
+		t.Errorf("tailed order = %s, want 3,4,5", seqs(got))
+	}
+}
+
+func TestActorRig(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{"gongshow", "mayor"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		actor string
+		want  string
+	}{
+		{"gongshow/witness", "gongshow"},
+		{"gongshow/polecats/Toast", "gongshow"},
+		{"gongshow/crew/joe", "gongshow"},
+		{"mayor/", ""},
+		{"mayor", ""},
+		{"deacon/", ""},
+		{"gongshow", ""},
+		{"missing/witness", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ActorRig(townRoot, tt.actor); got != tt.want {
+			t.Errorf("ActorRig(%q) = %q, want %q", tt.actor, got, tt.want)
+		}
+	}
+}
+
+func TestWriteRoutesRigEvents(t *testing.T) {
+	townRoot := setupTown(t)
+	if err := os.MkdirAll(filepath.Join(townRoot, "gongshow"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	orig := loadTownSettings
+	t.Cleanup(func() { loadTownSettings = orig })
+
+	loadTownSettings = func(string) (*config.TownSettings, error) { return &config.TownSettings{}, nil }
+	if err := LogFeed(TypeNudge, "gongshow/witness", NudgePayload("gongshow", "Toast", "idle")); err != nil {
+		t.Fatal(err)
+	}
+	loadTownSettings = func(string) (*config.TownSettings, error) { return &config.TownSettings{PerRigEvents: true}, nil }
+	if err := LogFeed(TypeNudge, "gongshow/witness", NudgePayload("gongshow", "Nux", "idle")); err != nil {
+		t.Fatal(err)
+	}
+	if err := LogFeed(TypeNudge, "mayor/", NudgePayload("", "deacon", "wake up")); err != nil {
+		t.Fatal(err)
+	}
+
+	town := readEvents(t, townRoot)
+	rig := readEvents(t, filepath.Join(townRoot, "gongshow"))
+	if len(town) != 2 || len(rig) != 1 {
+		t.Fatalf("town log has %d events, rig log %d; want 2 and 1", len(town), len(rig))
+	}
+	if rig[0].Payload["target"] != "Nux" || town[1].Actor != "mayor/" {
+		t.Errorf("town = %+v\nrig = %+v", town, rig)
+	}
+	for _, e := range append(town, rig...) {
+		if e.ID == "" {
+			t.Errorf("event %s has no ID", e.Type)
+		}
+	}
+}
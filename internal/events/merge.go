@@ -0,0 +1,246 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// seenWindow is how far behind the newest event read an event ID is still
+// remembered for deduplication. Copies of an event share its timestamp and
+// are read in timestamp order, so a copy is never further behind than the
+// clock skew between the logs.
+const seenWindow = 5 * time.Minute
+
+// seenIDs remembers the IDs of recently read events, forgetting those more
+// than seenWindow older than the newest, so that following or reading a
+// long log doesn't grow it without bound.
+type seenIDs struct {
+	ids    map[string]time.Time
+	newest time.Time
+	pruned time.Time // newest as of the last prune
+}
+
+func newSeenIDs() *seenIDs {
+	return &seenIDs{ids: make(map[string]time.Time)}
+}
+
+// add records the ID of an event at ts, reporting false if it was already
+// seen.
+func (s *seenIDs) add(id string, ts time.Time) bool {
+	if _, dup := s.ids[id]; dup {
+		return false
+	}
+	s.ids[id] = ts
+	if ts.After(s.newest) {
+		s.newest = ts
+	}
+	if s.newest.Sub(s.pruned) > seenWindow {
+		cutoff := s.newest.Add(-seenWindow)
+		for id, t := range s.ids {
+			if t.Before(cutoff) {
+				delete(s.ids, id)
+			}
+		}
+		s.pruned = s.newest
+	}
+	return true
+}
+
+// logSource reads one directory's events log line by line.
+type logSource struct {
+	dir     string
+	log     *logReader
+	br      *bufio.Reader
+	keyed   bool      // parse each line's timestamp and ID
+	line    string    // current line, valid until done
+	ts      time.Time // its timestamp (the previous line's if it has none)
+	id      string    // its event ID, if any
+	done    bool
+	partial []byte // trailing incomplete line, once done
+}
+
+// advance moves to the next non-empty line.
+func (s *logSource) advance() error {
+	for {
+		chunk, err := s.br.ReadBytes('\n')
+		if err == io.EOF {
+			s.partial, s.done = chunk, true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(chunk) == 1 {
+			continue
+		}
+		s.line = string(chunk[:len(chunk)-1])
+		if s.keyed {
+			s.id = ""
+			if key, ok := lineKey(chunk); ok {
+				if !key.ts.IsZero() {
+					s.ts = key.ts
+				}
+				s.id = key.id
+			}
+		}
+		return nil
+	}
+}
+
+// eventKey is what merging needs from an event line.
+type eventKey struct {
+	ts time.Time
+	id string
+}
+
+// lineKey parses the timestamp and ID of an event line.
+func lineKey(line []byte) (eventKey, bool) {
+	var e struct {
+		Timestamp string `json:"ts"`
+		ID        string `json:"id"`
+	}
+	if err := json.Unmarshal(line, &e); err != nil {
+		return eventKey{}, false
+	}
+	ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+	return eventKey{ts: ts, id: e.ID}, true
+}
+
+// mergedLog reads the town and rig events logs as one log in timestamp
+// order, dropping events whose ID was already read. Each file is assumed to
+// be in order already (it is appended to as events happen), so this is a
+// streaming k-way merge; ties go to the town log, then rigs by name.
+type mergedLog struct {
+	sources []*logSource
+	seen    *seenIDs
+	buf     []byte // unread rest of the current line, for Read
+}
+
+// openMerged opens the events log of the town root and every rig directory.
+// With create, a missing town active file is created so the caller can
+// follow it. If there is nothing to read, the error satisfies os.IsNotExist.
+func openMerged(townRoot string, create bool) (*mergedLog, error) {
+	dirs, err := LogDirs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &mergedLog{seen: newSeenIDs()}
+	var notExist error
+	for i, dir := range dirs {
+		l, err := openLog(dir, create && i == 0)
+		if err != nil {
+			if os.IsNotExist(err) {
+				notExist = err
+				continue // nothing logged there yet, or rotated away since listing
+			}
+			_ = m.Close()
+			return nil, err
+		}
+		m.sources = append(m.sources, &logSource{dir: dir, log: l, br: bufio.NewReader(l)})
+	}
+	if len(m.sources) == 0 {
+		return nil, notExist
+	}
+
+	// A single file needs no merging, so skip parsing its lines here.
+	keyed := len(m.sources) > 1
+	for _, s := range m.sources {
+		s.keyed = keyed
+		if err := s.advance(); err != nil {
+			_ = m.Close()
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// next returns the next line in merged order, or io.EOF.
+func (m *mergedLog) next() (string, error) {
+	for {
+		var best *logSource
+		for _, s := range m.sources {
+			if !s.done && (best == nil || s.ts.Before(best.ts)) {
+				best = s
+			}
+		}
+		if best == nil {
+			return "", io.EOF
+		}
+		line, ts, id := best.line, best.ts, best.id
+		if err := best.advance(); err != nil {
+			return "", err
+		}
+		if id != "" && !m.seen.add(id, ts) {
+			continue
+		}
+		return line, nil
+	}
+}
+
+// Read implements io.Reader over the merged lines.
+func (m *mergedLog) Read(p []byte) (int, error) {
+	for len(m.buf) == 0 {
+		line, err := m.next()
+		if err != nil {
+			return 0, err
+		}
+		m.buf = append(m.buf[:0], line...)
+		m.buf = append(m.buf, '\n')
+	}
+	n := copy(p, m.buf)
+	m.buf = m.buf[n:]
+	return n, nil
+}
+
+// Close closes every file.
+func (m *mergedLog) Close() error {
+	var firstErr error
+	for _, s := range m.sources {
+		if err := s.log.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// mergeLines merges the lines read from each file into timestamp order
+// (stable, so same-second lines keep their order) and drops those whose ID
+// is in seen, recording the others. An unparseable line sorts with the line
+// before it in its file.
+func mergeLines(batches [][]string, seen *seenIDs) []string {
+	type keyed struct {
+		line string
+		ts   time.Time
+		id   string
+	}
+	var all []keyed
+	for _, lines := range batches {
+		var last time.Time
+		for _, line := range lines {
+			var id string
+			if key, ok := lineKey([]byte(line)); ok {
+				if !key.ts.IsZero() {
+					last = key.ts
+				}
+				id = key.id
+			}
+			all = append(all, keyed{line, last, id})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].ts.Before(all[j].ts) })
+
+	// Deduplicate in timestamp order, which is what seen's window assumes.
+	result := make([]string, 0, len(all))
+	for _, k := range all {
+		if k.id != "" && !seen.add(k.id, k.ts) {
+			continue
+		}
+		result = append(result, k.line)
+	}
+	return result
+}
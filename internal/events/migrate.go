@@ -0,0 +1,216 @@
+package events
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// SplitResult summarises SplitByRig.
+type SplitResult struct {
+	Files int            // town log files that held rig events
+	Kept  int            // lines left in the town log
+	Moved map[string]int // events moved, by rig
+}
+
+// SplitByRig moves rig events out of the town events log into per-rig logs,
+// for towns switching to per-rig event files. The active town file is
+// rotated first so that only archives, which nobody appends to or follows,
+// are rewritten. Rig events from each town file land in a rig archive named
+// after the last of them, so they sort before anything the rig logged since.
+//
+// Events without an ID are given one before anything is moved, so a split
+// interrupted halfway leaves duplicates that readers drop, never gaps.
+// With dryRun, nothing is changed and the active file is included in the
+// counts.
+func SplitByRig(townRoot string, dryRun bool) (*SplitResult, error) {
+	if !dryRun {
+		if err := Rotate(townRoot, RotationPolicy{}); err != nil {
+			return nil, err
+		}
+	}
+
+	lock := flock.New(filepath.Join(townRoot, lockFile))
+	if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking events file: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	files, err := listArchives(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		if _, err := os.Stat(filepath.Join(townRoot, EventsFile)); err == nil {
+			files = append(files, filepath.Join(townRoot, EventsFile))
+		}
+	}
+
+	result := &SplitResult{Moved: make(map[string]int)}
+	for _, path := range files {
+		if err := splitFile(townRoot, path, dryRun, result); err != nil {
+			return result, fmt.Errorf("splitting %s: %w", filepath.Base(path), err)
+		}
+	}
+	return result, nil
+}
+
+// splitFile moves the rig events in one town log file.
+func splitFile(townRoot, path string, dryRun bool, result *SplitResult) error {
+	lines, err := readLogLines(path)
+	if err != nil {
+		return err
+	}
+
+	var keep []string
+	moved := make(map[string][]string)
+	lastTS := make(map[string]string)
+	assigned := false
+	for i, line := range lines {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil || e.Type == "" {
+			keep = append(keep, line)
+			continue
+		}
+		if e.ID == "" {
+			line = withEventID(line, newEventID())
+			lines[i] = line
+			assigned = true
+		}
+		rig := ActorRig(townRoot, e.Actor)
+		if rig == "" {
+			keep = append(keep, line)
+			continue
+		}
+		moved[rig] = append(moved[rig], line)
+		lastTS[rig] = e.Timestamp
+	}
+
+	result.Kept += len(keep)
+	if len(moved) == 0 {
+		return nil
+	}
+	result.Files++
+	for rig, rigLines := range moved {
+		result.Moved[rig] += len(rigLines)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if assigned {
+		if err := writeLogLines(path, lines); err != nil {
+			return err
+		}
+	}
+	for rig, rigLines := range moved {
+		name := filepath.Base(path)
+		if ts, err := time.Parse(time.RFC3339, lastTS[rig]); err == nil {
+			name = archiveName(ts)
+			if filepath.Ext(path) == ".gz" {
+				name += ".gz"
+			}
+		}
+		if err := mergeIntoLog(filepath.Join(townRoot, rig), name, rigLines); err != nil {
+			return fmt.Errorf("writing %s events: %w", rig, err)
+		}
+	}
+	if len(keep) == 0 {
+		return os.Remove(path)
+	}
+	return writeLogLines(path, keep)
+}
+
+// withEventID adds an ID to an event line that has none, leaving the rest
+// of the line untouched. line must be a non-empty JSON object.
+func withEventID(line, id string) string {
+	line = strings.TrimSpace(line)
+	return `{"id":"` + id + `",` + line[1:]
+}
+
+// mergeIntoLog adds lines to the named archive in dir, merging them with
+// what it already holds.
+func mergeIntoLog(dir, name string, lines []string) error {
+	lock := flock.New(filepath.Join(dir, lockFile))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking events file: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	path := filepath.Join(dir, name)
+	existing, err := readLogLines(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return writeLogLines(path, mergeLines([][]string{existing, lines}, newSeenIDs()))
+}
+
+// readLogLines returns the non-empty lines of a plain or gzipped log file.
+func readLogLines(path string) ([]string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is an events file in the town
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	if filepath.Ext(path) == ".gz" {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = zr.Close() }()
+		r = zr
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// writeLogLines atomically replaces path with lines, gzipped if path ends
+// in .gz.
+func writeLogLines(path string, lines []string) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return err
+	}
+	var w io.Writer = f
+	var zw *gzip.Writer
+	if filepath.Ext(path) == ".gz" {
+		zw = gzip.NewWriter(f)
+		w = zw
+	}
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		_, _ = bw.WriteString(line)
+		_ = bw.WriteByte('\n')
+	}
+	err = bw.Flush()
+	if zw != nil && err == nil {
+		err = zw.Close()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
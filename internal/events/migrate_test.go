@@ -0,0 +1,121 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitByRig(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, rig := range []string{"gongshow", "other"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, rig), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A monolithic town log spanning archives; half the events predate IDs.
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	actors := []string{"mayor/", "gongshow/witness", "other/polecats/Toast", "gongshow/refinery"}
+	for i := 0; i < 20; i++ {
+		e := Event{
+			Timestamp: start.Add(time.Duration(i) * time.Minute).Format(time.RFC3339),
+			Type:      TypeNudge,
+			Actor:     actors[i%len(actors)],
+			Payload:   map[string]interface{}{"seq": i},
+		}
+		if i%2 == 0 {
+			e.ID = fmt.Sprintf("ev%d", i)
+		}
+		appendTo(t, townRoot, e, RotationPolicy{MaxSize: 600})
+	}
+	// A malformed line must stay in the town log.
+	if _, err := appendLine(filepath.Join(townRoot, EventsFile), []byte("not json\n")); err != nil {
+		t.Fatal(err)
+	}
+	appendTo(t, townRoot, Event{Timestamp: start.Add(30 * time.Minute).Format(time.RFC3339), Type: TypeNudge,
+		Actor: "gongshow/witness", Payload: map[string]interface{}{"seq": 20}}, RotationPolicy{})
+
+	var before []Event
+	if _, err := Query(townRoot, QueryOptions{}, func(_ string, e Event) error {
+		before = append(before, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dry, err := SplitByRig(townRoot, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if dry.Moved["gongshow"] == 0 || dry.Moved["other"] == 0 {
+		t.Fatalf("dry run = %+v", dry)
+	}
+	if hasLog(filepath.Join(townRoot, "gongshow")) {
+		t.Fatal("dry run wrote rig files")
+	}
+
+	result, err := SplitByRig(townRoot, false)
+	if err != nil {
+		t.Fatalf("SplitByRig: %v", err)
+	}
+	if result.Moved["gongshow"] != 11 || result.Moved["other"] != 5 {
+		t.Errorf("moved = %v, want gongshow 11, other 5", result.Moved)
+	}
+	if result.Kept != 6 { // 5 mayor events and the malformed line
+		t.Errorf("kept = %d, want 6", result.Kept)
+	}
+
+	// Only town events remain at the root.
+	townFiles, err := listArchives(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range townFiles {
+		lines, err := readLogLines(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range lines {
+			if strings.Contains(line, `"actor":"gongshow/`) || strings.Contains(line, `"actor":"other/`) {
+				t.Errorf("%s still holds rig event %s", filepath.Base(f), line)
+			}
+		}
+	}
+
+	// The merged view is unchanged, and every event now has an ID.
+	var after []Event
+	if _, err := Query(townRoot, QueryOptions{}, func(_ string, e Event) error {
+		after = append(after, e)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if seqs(after) != seqs(before) || len(after) != 21 {
+		t.Errorf("after split = %s\nbefore = %s", seqs(after), seqs(before))
+	}
+	for _, e := range after {
+		if e.ID == "" {
+			t.Errorf("event %v has no ID", e.Payload["seq"])
+		}
+	}
+
+	// Running it again moves nothing.
+	again, err := SplitByRig(townRoot, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Moved) != 0 {
+		t.Errorf("second split moved %v", again.Moved)
+	}
+}
+
+func TestWithEventID(t *testing.T) {
+	got := withEventID(`{"ts":"2024-01-15T00:00:00Z","type":"nudge"}`, "abc")
+	if got != `{"id":"abc","ts":"2024-01-15T00:00:00Z","type":"nudge"}` {
+		t.Errorf("withEventID = %s", got)
+	}
+}
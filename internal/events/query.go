@@ -0,0 +1,69 @@
+package events
+
+import (
+	"context"
+	"errors"
+)
+
+// QueryOptions controls Query.
+type QueryOptions struct {
+	Filter Filter
+
+	// Limit caps the number of events delivered (0 means no limit).
+	Limit int
+
+	// Reverse delivers newest first. Combined with Limit, this yields the
+	// last Limit matching events.
+	Reverse bool
+
+	// Source is the log to search: SourceFeed (the default) or SourceAudit.
+	Source string
+}
+
+// errQueryDone stops Stream once a forward query has reached its limit.
+var errQueryDone = errors.New("query limit reached")
+
+// Query delivers the matching events in the whole events log (archives
+// included) to emit, reading the log as a stream. Only a reverse query holds
+// matches in memory: the last Limit of them, or all of them without a limit.
+// Returns the number of malformed lines skipped.
+func Query(townRoot string, opts QueryOptions, emit func(raw string, e Event) error) (skipped int, err error) {
+	stream := StreamOptions{Filter: opts.Filter, History: -1, Source: opts.Source}
+
+	if !opts.Reverse {
+		delivered := 0
+		skipped, err = Stream(context.Background(), townRoot, stream, func(raw string, e Event) error {
+			if opts.Limit > 0 && delivered >= opts.Limit {
+				return errQueryDone
+			}
+			delivered++
+			return emit(raw, e)
+		})
+		if errors.Is(err, errQueryDone) {
+			err = nil
+		}
+		return skipped, err
+	}
+
+	if opts.Limit > 0 {
+		stream.History = opts.Limit
+	}
+	type match struct {
+		raw string
+		e   Event
+	}
+	var matches []match
+	skipped, err = Stream(context.Background(), townRoot, stream, func(raw string, e Event) error {
+		matches = append(matches, match{raw, e})
+		return nil
+	})
+	if err != nil {
+		return skipped, err
+	}
+	for i := len(matches) - 1; i >= 0; i-- {
+		if err := emit(matches[i].raw, matches[i].e); err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"gt-gongshow-*", "gt-gongshow-Toast", true},
+		{"gt-gongshow-*", "gt-other-Toast", false},
+		{"*", "", true},
+		{"gongshow/*", "gongshow/crew/max", true}, // '*' crosses slashes
+		{"*/witness", "gongshow/witness", true},
+		{"gt-?", "gt-1", true},
+		{"gt-?", "gt-12", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"exact", "exact", true},
+		{"exact", "exactly", false},
+	}
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+// writeCorpus writes n session_death events one minute apart, alternating
+// between two rigs, interleaved with unrelated nudges.
+func writeCorpus(t *testing.T, townRoot string, start time.Time, n int) {
+	t.Helper()
+	rigs := []string{"gongshow", "other"}
+	for i := 0; i < n; i++ {
+		ts := start.Add(time.Duration(i) * time.Minute).UTC().Format(time.RFC3339)
+		for _, e := range []Event{
+			{Timestamp: ts, Type: TypeSessionDeath, Actor: "daemon", Payload: map[string]interface{}{
+				"session": fmt.Sprintf("gt-%s-p%d", rigs[i%2], i),
+				"seq":     i,
+			}},
+			{Timestamp: ts, Type: TypeNudge, Actor: "mayor", Payload: map[string]interface{}{"session": "gt-gongshow-x"}},
+		} {
+			data, _ := json.Marshal(e)
+			if err := appendEvent(townRoot, append(data, '\n'), RotationPolicy{MaxSize: 2048}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func querySeqs(t *testing.T, townRoot string, opts QueryOptions) []string {
+	t.Helper()
+	var seqs []string
+	if _, err := Query(townRoot, opts, func(raw string, e Event) error {
+		seqs = append(seqs, fmt.Sprint(e.Payload["seq"]))
+		return nil
+	}); err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	return seqs
+}
+
+func TestQuery(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	writeCorpus(t, townRoot, start, 40)
+
+	if archives, _ := listArchives(townRoot); len(archives) == 0 {
+		t.Fatal("corpus should span rotated archives")
+	}
+
+	deaths := Filter{
+		Types:   []string{TypeSessionDeath},
+		Payload: map[string]string{"session": "gt-gongshow-*"},
+	}
+
+	tests := []struct {
+		name string
+		opts QueryOptions
+		want string
+	}{
+		{"payload glob", QueryOptions{Filter: deaths, Limit: 3}, "0 2 4"},
+		{"reverse limit", QueryOptions{Filter: deaths, Limit: 3, Reverse: true}, "38 36 34"},
+		{"reverse all", QueryOptions{Filter: Filter{Types: []string{TypeSessionDeath}, Until: start.Add(3 * time.Minute)}, Reverse: true}, "3 2 1 0"},
+		{"time window", QueryOptions{Filter: Filter{
+			Types: []string{TypeSessionDeath},
+			Since: start.Add(10 * time.Minute),
+			Until: start.Add(12 * time.Minute),
+		}}, "10 11 12"},
+		{"missing payload key", QueryOptions{Filter: Filter{Payload: map[string]string{"nope": "*"}}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(querySeqs(t, townRoot, tt.opts), " "); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
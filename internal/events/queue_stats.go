@@ -0,0 +1,125 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// QueueWorkerStats counts one worker's activity on a work queue.
+type QueueWorkerStats struct {
+	Worker    string `json:"worker"`
+	Claims    int    `json:"claims"`
+	Completed int    `json:"completed"`
+	Failed    int    `json:"failed"`
+	Released  int    `json:"released"`
+}
+
+// QueueStats summarises the claims and releases on one work queue.
+type QueueStats struct {
+	Queue   string             `json:"queue"`
+	Since   time.Time          `json:"since,omitempty"`
+	Workers []QueueWorkerStats `json:"workers"`           // most claims first, ties by worker
+	Skipped int                `json:"skipped,omitempty"` // malformed lines
+}
+
+// Total returns the counts summed over all workers.
+func (s *QueueStats) Total() QueueWorkerStats {
+	var t QueueWorkerStats
+	for _, w := range s.Workers {
+		t.Claims += w.Claims
+		t.Completed += w.Completed
+		t.Failed += w.Failed
+		t.Released += w.Released
+	}
+	return t
+}
+
+// QueueStatsCollector aggregates queue_claim and queue_release events for
+// one queue, one event at a time.
+type QueueStatsCollector struct {
+	queue    string
+	byWorker map[string]*QueueWorkerStats
+}
+
+// NewQueueStatsCollector returns an empty collector for queueName.
+func NewQueueStatsCollector(queueName string) *QueueStatsCollector {
+	return &QueueStatsCollector{
+		queue:    queueName,
+		byWorker: make(map[string]*QueueWorkerStats),
+	}
+}
+
+// Add counts one event. Events for other queues, and of other types, are
+// ignored. Releases logged before release statuses existed name the worker
+// as claimed_by and count as plain releases.
+func (c *QueueStatsCollector) Add(e Event) {
+	if e.Type != TypeQueueClaim && e.Type != TypeQueueRelease {
+		return
+	}
+	if queue, _ := e.Payload["queue"].(string); queue != c.queue {
+		return
+	}
+	worker, _ := e.Payload["worker"].(string)
+	if worker == "" {
+		worker, _ = e.Payload["claimed_by"].(string)
+	}
+	if worker == "" {
+		worker = e.Actor
+	}
+	w := c.byWorker[worker]
+	if w == nil {
+		w = &QueueWorkerStats{Worker: worker}
+		c.byWorker[worker] = w
+	}
+
+	if e.Type == TypeQueueClaim {
+		w.Claims++
+		return
+	}
+	switch status, _ := e.Payload["status"].(string); status {
+	case QueueStatusCompleted:
+		w.Completed++
+	case QueueStatusFailed:
+		w.Failed++
+	default:
+		w.Released++
+	}
+}
+
+// Result returns the per-worker counts.
+func (c *QueueStatsCollector) Result() *QueueStats {
+	s := &QueueStats{Queue: c.queue, Workers: make([]QueueWorkerStats, 0, len(c.byWorker))}
+	for _, w := range c.byWorker {
+		s.Workers = append(s.Workers, *w)
+	}
+	sort.Slice(s.Workers, func(i, j int) bool {
+		if s.Workers[i].Claims != s.Workers[j].Claims {
+			return s.Workers[i].Claims > s.Workers[j].Claims
+		}
+		return s.Workers[i].Worker < s.Workers[j].Worker
+	})
+	return s
+}
+
+// CollectQueueStats aggregates a queue's claims and releases from the audit
+// log, which keeps every queue event (the feed log is pruned). since drops
+// older events; zero keeps everything.
+func CollectQueueStats(townRoot, queueName string, since time.Time) (*QueueStats, error) {
+	c := NewQueueStatsCollector(queueName)
+	skipped, err := Stream(context.Background(), townRoot, StreamOptions{
+		Filter:  Filter{Types: []string{TypeQueueClaim, TypeQueueRelease}, Since: since},
+		History: -1,
+		Source:  SourceAudit,
+	}, func(_ string, e Event) error {
+		c.Add(e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := c.Result()
+	s.Since = since
+	s.Skipped = skipped
+	return s, nil
+}
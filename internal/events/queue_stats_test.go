@@ -0,0 +1,83 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueueStatsCollector(t *testing.T) {
+	const queue = "work/gongshow"
+	toast, nux := "gongshow/polecats/Toast", "gongshow/polecats/Nux"
+
+	c := NewQueueStatsCollector(queue)
+	for _, e := range []Event{
+		{Type: TypeQueueClaim, Actor: toast, Payload: QueueClaimPayload(queue, "hq-1", toast)},
+		{Type: TypeQueueRelease, Actor: toast, Payload: QueueReleasePayload(queue, "hq-1", toast, QueueStatusCompleted)},
+		{Type: TypeQueueClaim, Actor: toast, Payload: QueueClaimPayload(queue, "hq-2", toast)},
+		{Type: TypeQueueRelease, Actor: toast, Payload: QueueReleasePayload(queue, "hq-2", toast, QueueStatusFailed)},
+		{Type: TypeQueueClaim, Actor: nux, Payload: QueueClaimPayload(queue, "hq-2", nux)},
+		{Type: TypeQueueRelease, Actor: nux, Payload: QueueReleasePayload(queue, "hq-2", nux, QueueStatusReleased)},
+		// A release from before statuses counts as a plain release.
+		{Type: TypeQueueRelease, Actor: nux, Payload: map[string]interface{}{"message": "hq-0", "queue": queue, "claimed_by": nux}},
+		// Other queues and types are ignored.
+		{Type: TypeQueueClaim, Actor: nux, Payload: QueueClaimPayload("work/other", "hq-9", nux)},
+		{Type: TypeSling, Actor: "mayor", Payload: SlingPayload("gt-abc", nux)},
+	} {
+		c.Add(e)
+	}
+
+	s := c.Result()
+	want := []QueueWorkerStats{
+		{Worker: toast, Claims: 2, Completed: 1, Failed: 1},
+		{Worker: nux, Claims: 1, Released: 2},
+	}
+	if !reflect.DeepEqual(s.Workers, want) {
+		t.Errorf("Workers = %+v, want %+v", s.Workers, want)
+	}
+	if total := s.Total(); total != (QueueWorkerStats{Claims: 3, Completed: 1, Failed: 1, Released: 2}) {
+		t.Errorf("Total = %+v", total)
+	}
+}
+
+func TestQueueEventsReachQueueStats(t *testing.T) {
+	townRoot := setupTown(t)
+	const queue = "work/gongshow"
+	toast := "gongshow/polecats/Toast"
+
+	for _, ev := range []struct {
+		typ     string
+		payload map[string]interface{}
+	}{
+		{TypeQueueClaim, QueueClaimPayload(queue, "hq-1", toast)},
+		{TypeQueueRelease, QueueReleasePayload(queue, "hq-1", toast, QueueStatusCompleted)},
+		{TypeQueueClaim, QueueClaimPayload(queue, "hq-2", toast)},
+		{TypeQueueRelease, QueueReleasePayload(queue, "hq-2", toast, QueueStatusFailed)},
+	} {
+		if err := Log(ev.typ, toast, ev.payload, VisibilityBoth); err != nil {
+			t.Fatalf("Log(%s): %v", ev.typ, err)
+		}
+	}
+
+	// Both event types land in the feed as well as the audit log.
+	if got := len(readEvents(t, townRoot)); got != 4 {
+		t.Errorf("events log has %d events, want 4", got)
+	}
+
+	s, err := CollectQueueStats(townRoot, queue, time.Time{})
+	if err != nil {
+		t.Fatalf("CollectQueueStats: %v", err)
+	}
+	want := []QueueWorkerStats{{Worker: toast, Claims: 2, Completed: 1, Failed: 1}}
+	if !reflect.DeepEqual(s.Workers, want) {
+		t.Errorf("Workers = %+v, want %+v", s.Workers, want)
+	}
+
+	s, err = CollectQueueStats(townRoot, queue, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CollectQueueStats: %v", err)
+	}
+	if len(s.Workers) != 0 {
+		t.Errorf("Workers since the future = %+v, want none", s.Workers)
+	}
+}
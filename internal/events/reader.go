@@ -0,0 +1,300 @@
+package events
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+)
+
+// OpenLog returns a reader over the whole events log: for the town root and
+// each rig directory, rotated archives oldest first (decompressing gzipped
+// ones) then the active file, merged into timestamp order with duplicate
+// events dropped. If nothing has been logged, the error satisfies
+// os.IsNotExist.
+func OpenLog(townRoot string) (io.ReadCloser, error) {
+	m, err := openMerged(townRoot, false)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// openLog opens the archives and the active file in dir. With create, a
+// missing active file is created so the caller can keep following it
+// afterwards.
+func openLog(dir string, create bool) (*logReader, error) {
+	// Open everything under the shared lock so a concurrent rotation can't
+	// make us miss or double-read the file being renamed.
+	lock := flock.New(filepath.Join(dir, lockFile))
+	if err := lock.RLock(); err != nil {
+		return nil, fmt.Errorf("locking events file: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	archives, err := listArchives(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &logReader{}
+	for _, path := range archives {
+		f, err := os.Open(path) //nolint:gosec // G304: path is an events archive in the town root
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // pruned since listing
+			}
+			_ = l.Close()
+			return nil, err
+		}
+		l.closers = append(l.closers, f)
+		var r io.Reader = f
+		if filepath.Ext(path) == ".gz" {
+			zr, err := gzip.NewReader(f)
+			if err != nil {
+				_ = l.Close()
+				return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+			}
+			r = zr
+		}
+		l.readers = append(l.readers, r)
+	}
+
+	flags := os.O_RDONLY
+	if create {
+		flags |= os.O_CREATE
+	}
+	active, err := os.OpenFile(filepath.Join(dir, EventsFile), flags, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	switch {
+	case err == nil:
+		l.active = active
+		l.readers = append(l.readers, active)
+	case !os.IsNotExist(err) || len(archives) == 0:
+		_ = l.Close()
+		return nil, err
+	}
+
+	l.Reader = io.MultiReader(l.readers...)
+	return l, nil
+}
+
+// openAudit opens the town's audit log as a merged log with a single
+// source, so it can be read like the events logs. With create, a missing
+// file is created so the caller can follow it. If there is nothing to read,
+// the error satisfies os.IsNotExist.
+func openAudit(townRoot string, create bool) (*mergedLog, error) {
+	flags := os.O_RDONLY
+	if create {
+		flags |= os.O_CREATE
+	}
+	f, err := os.OpenFile(filepath.Join(townRoot, AuditFile), flags, 0644) //nolint:gosec // G302: audit file is non-sensitive operational data
+	if err != nil {
+		return nil, err
+	}
+	l := &logReader{Reader: f, readers: []io.Reader{f}, active: f}
+	s := &logSource{dir: townRoot, log: l, br: bufio.NewReader(l)}
+	m := &mergedLog{sources: []*logSource{s}, seen: newSeenIDs()}
+	if err := s.advance(); err != nil {
+		_ = m.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// logReader concatenates the events log files.
+type logReader struct {
+	io.Reader
+	readers []io.Reader
+	closers []io.Closer // archives
+	active  *os.File    // nil if there is no active file
+}
+
+func (l *logReader) Close() error {
+	var firstErr error
+	for _, c := range l.closers {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if l.active != nil {
+		if err := l.active.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Tailer follows the active events logs of the town root and every rig
+// directory, merging what each poll finds into timestamp order. Rig logs
+// created after the tailer started are picked up from their start.
+type Tailer struct {
+	townRoot string
+	files    []*fileTailer
+	seen     *seenIDs // IDs delivered recently, for deduplication
+	audit    bool     // following the audit log, not the events logs
+}
+
+// NewTailer starts following the town's events logs from their current
+// end, creating the town file if needed.
+func NewTailer(townRoot string) (*Tailer, error) {
+	t := &Tailer{townRoot: townRoot, seen: newSeenIDs()}
+	dirs, err := LogDirs(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing events logs: %w", err)
+	}
+	for i, dir := range dirs {
+		ft, err := newFileTailer(dir, i == 0)
+		if err != nil {
+			if i > 0 && os.IsNotExist(err) {
+				continue // only archives so far; picked up once written to
+			}
+			_ = t.Close()
+			return nil, err
+		}
+		if _, err := ft.file.Seek(0, io.SeekEnd); err != nil {
+			_ = t.Close()
+			return nil, fmt.Errorf("seeking to end: %w", err)
+		}
+		t.files = append(t.files, ft)
+	}
+	return t, nil
+}
+
+// Lines returns the complete lines appended since the last call, without
+// trailing newlines, in timestamp order and without events already
+// delivered. When a log has been rotated, the rest of the old file is
+// returned first and the new active file is followed from its start.
+func (t *Tailer) Lines() []string {
+	t.discover()
+
+	var batches [][]string
+	for _, ft := range t.files {
+		if lines := ft.lines(); len(lines) > 0 {
+			batches = append(batches, lines)
+		}
+	}
+	switch len(batches) {
+	case 0:
+		return nil
+	case 1:
+		if len(t.files) == 1 {
+			return batches[0] // nothing to merge or deduplicate against
+		}
+	}
+	return mergeLines(batches, t.seen)
+}
+
+// discover starts following rig logs that appeared since the last poll.
+func (t *Tailer) discover() {
+	if t.audit {
+		return // there is only the town's audit log
+	}
+	following := make(map[string]bool, len(t.files))
+	for _, ft := range t.files {
+		following[ft.dir] = true
+	}
+	dirs, err := LogDirs(t.townRoot)
+	if err != nil {
+		return
+	}
+	for _, dir := range dirs {
+		if following[dir] {
+			continue
+		}
+		if ft, err := newFileTailer(dir, false); err == nil {
+			t.files = append(t.files, ft)
+		}
+	}
+}
+
+// Close stops following the logs.
+func (t *Tailer) Close() error {
+	var firstErr error
+	for _, ft := range t.files {
+		if err := ft.file.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// fileTailer follows the active events file in one directory, reopening it
+// when it is rotated.
+type fileTailer struct {
+	dir    string
+	path   string
+	file   *os.File
+	reader *bufio.Reader
+	buf    []byte // partial line carried over between reads
+}
+
+// newFileTailer opens the active events file in dir at its start, creating
+// it if create is set.
+func newFileTailer(dir string, create bool) (*fileTailer, error) {
+	path := filepath.Join(dir, EventsFile)
+	flags := os.O_RDONLY
+	if create {
+		flags |= os.O_CREATE
+	}
+	f, err := os.OpenFile(path, flags, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	return &fileTailer{dir: dir, path: path, file: f, reader: bufio.NewReader(f)}, nil
+}
+
+// lines returns the complete lines appended since the last call, following
+// the file across rotation.
+func (t *fileTailer) lines() []string {
+	lines := t.drain()
+
+	// Rotated away? Only switch once the old file is fully drained: the
+	// rotation lock guarantees nothing is still writing to it.
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return lines
+	}
+	current, err := t.file.Stat()
+	if err != nil || os.SameFile(info, current) {
+		return lines
+	}
+
+	f, err := os.Open(t.path)
+	if err != nil {
+		return lines
+	}
+	lines = append(lines, t.drain()...)
+	if len(t.buf) > 0 {
+		// The old file ended mid-line; don't glue it to the new file's first line.
+		lines = append(lines, string(t.buf))
+		t.buf = nil
+	}
+	_ = t.file.Close()
+	t.file = f
+	t.reader = bufio.NewReader(f)
+	return append(lines, t.drain()...)
+}
+
+// drain reads all complete lines currently available.
+func (t *fileTailer) drain() []string {
+	var lines []string
+	for {
+		chunk, err := t.reader.ReadBytes('\n')
+		t.buf = append(t.buf, chunk...)
+		if err != nil {
+			return lines
+		}
+		line := t.buf[:len(t.buf)-1]
+		if len(line) > 0 {
+			lines = append(lines, string(line))
+		}
+		t.buf = nil
+	}
+}
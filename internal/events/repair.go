@@ -0,0 +1,113 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+)
+
+// CorruptFile is the sidecar, next to each events log, that Repair moves
+// corrupt lines to.
+const CorruptFile = ".events.corrupt"
+
+// FileRepair is the outcome of repairing one events log file.
+type FileRepair struct {
+	Path    string
+	Lines   int // non-empty lines scanned
+	Corrupt int // lines that are not a JSON event
+}
+
+// RepairResult summarises a Repair run.
+type RepairResult struct {
+	Files   []*FileRepair // every file scanned
+	Lines   int
+	Corrupt int
+}
+
+// Repair scans every events log file in the town (active files and
+// archives, town and per-rig) for corrupt lines, such as the half-lines left
+// by interleaved writes, and moves them to the CorruptFile sidecar in the
+// same directory. With dryRun set it only counts them. The audit log is
+// append-only and left as it is.
+func Repair(townRoot string, dryRun bool) (*RepairResult, error) {
+	files, err := LogFiles(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	result := &RepairResult{}
+	for _, path := range files {
+		if filepath.Base(path) == AuditFile {
+			continue
+		}
+		fr, err := RepairFile(path, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, fr)
+		result.Lines += fr.Lines
+		result.Corrupt += fr.Corrupt
+	}
+	return result, nil
+}
+
+// RepairFile moves the corrupt lines in one events log file to the
+// CorruptFile sidecar, holding the events lock so no append is lost while
+// the file is rewritten. With dryRun set it only counts them.
+func RepairFile(path string, dryRun bool) (*FileRepair, error) {
+	dir := filepath.Dir(path)
+	lock := flock.New(filepath.Join(dir, lockFile))
+	if dryRun {
+		if err := lock.RLock(); err != nil {
+			return nil, fmt.Errorf("locking events file: %w", err)
+		}
+	} else if err := lock.Lock(); err != nil {
+		return nil, fmt.Errorf("locking events file: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	lines, err := readLogLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+
+	fr := &FileRepair{Path: path, Lines: len(lines)}
+	var good, corrupt []string
+	for _, line := range lines {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			corrupt = append(corrupt, line)
+			continue
+		}
+		good = append(good, line)
+	}
+	fr.Corrupt = len(corrupt)
+	if dryRun || len(corrupt) == 0 {
+		return fr, nil
+	}
+
+	// Save the corrupt lines before dropping them from the log.
+	sidecar := filepath.Join(dir, CorruptFile)
+	f, err := os.OpenFile(sidecar, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", CorruptFile, err)
+	}
+	var buf []byte
+	for _, line := range corrupt {
+		buf = append(append(buf, line...), '\n')
+	}
+	_, err = f.Write(buf)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("writing %s: %w", CorruptFile, err)
+	}
+
+	if err := writeLogLines(path, good); err != nil {
+		return nil, fmt.Errorf("rewriting %s: %w", filepath.Base(path), err)
+	}
+	return fr, nil
+}
@@ -0,0 +1,152 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// checkParsesCleanly fails the test on any line of path that isn't an event,
+// and returns how many lines there were.
+func checkParsesCleanly(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		n++
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %d is corrupt (%v): %.80q", n, err, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestConcurrentAppendsStayWhole(t *testing.T) {
+	townRoot := setupTown(t)
+	const workers, perWorker = 16, 40
+
+	// Lines well past the pipe-buffer size, so unlocked writers would
+	// interleave. appendEvent takes the file lock on every call, as separate
+	// gt processes would; Log goes through the in-process path as well.
+	big := strings.Repeat("x", 32*1024)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				if i%2 == 0 {
+					_ = LogFeed("load_test", fmt.Sprintf("w%d", w), map[string]interface{}{"seq": i, "pad": big})
+					continue
+				}
+				data, _ := json.Marshal(Event{Type: "load_test", Actor: fmt.Sprintf("w%d", w), Payload: map[string]interface{}{"seq": i, "pad": big}})
+				if err := appendEvent(townRoot, append(data, '\n'), RotationPolicy{}); err != nil {
+					t.Errorf("appendEvent: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if n := checkParsesCleanly(t, filepath.Join(townRoot, EventsFile)); n != workers*perWorker {
+		t.Errorf("log has %d lines, want %d", n, workers*perWorker)
+	}
+}
+
+func TestAppendFallsBackWhenLockIsStuck(t *testing.T) {
+	dir := t.TempDir()
+	orig := appendLockTimeout
+	appendLockTimeout = 50 * time.Millisecond
+	t.Cleanup(func() { appendLockTimeout = orig })
+
+	// Another process holds the lock and never lets go.
+	holder := flock.New(filepath.Join(dir, lockFile))
+	if err := holder.Lock(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = holder.Unlock() }()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- appendEvent(dir, []byte(`{"ts":"2024-01-15T10:00:00Z","type":"custom","actor":"mayor"}`+"\n"), RotationPolicy{})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("appendEvent: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("appendEvent blocked on a stuck lock")
+	}
+	if n := checkParsesCleanly(t, filepath.Join(dir, EventsFile)); n != 1 {
+		t.Errorf("log has %d lines, want 1", n)
+	}
+}
+
+func TestRepairMovesCorruptLines(t *testing.T) {
+	townRoot := setupTown(t)
+	good1 := `{"ts":"2024-01-15T10:00:00Z","type":"custom","actor":"mayor"}`
+	good2 := `{"ts":"2024-01-15T10:00:02Z","type":"custom","actor":"deacon"}`
+	half := `{"ts":"2024-01-15T10:00:01Z","type":"cus`
+	junk := `tom","actor":"mayor"}`
+	path := filepath.Join(townRoot, EventsFile)
+	if err := os.WriteFile(path, []byte(good1+"\n"+half+"\n"+junk+"\n"+good2+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A dry run counts without touching anything.
+	result, err := Repair(townRoot, true)
+	if err != nil {
+		t.Fatalf("Repair(dry run): %v", err)
+	}
+	if result.Lines != 4 || result.Corrupt != 2 || len(result.Files) != 1 {
+		t.Errorf("dry run = %d lines, %d corrupt in %d files; want 4, 2 in 1", result.Lines, result.Corrupt, len(result.Files))
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, CorruptFile)); !os.IsNotExist(err) {
+		t.Error("dry run wrote the corrupt sidecar")
+	}
+
+	result, err = Repair(townRoot, false)
+	if err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if result.Corrupt != 2 {
+		t.Errorf("repaired %d corrupt lines, want 2", result.Corrupt)
+	}
+	if n := checkParsesCleanly(t, path); n != 2 {
+		t.Errorf("repaired log has %d lines, want 2", n)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, CorruptFile))
+	if err != nil {
+		t.Fatalf("reading sidecar: %v", err)
+	}
+	if string(data) != half+"\n"+junk+"\n" {
+		t.Errorf("sidecar = %q", data)
+	}
+
+	// A clean log is left alone.
+	result, err = Repair(townRoot, false)
+	if err != nil || result.Corrupt != 0 {
+		t.Errorf("second Repair = %+v, %v; want nothing to do", result, err)
+	}
+}
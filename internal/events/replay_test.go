@@ -0,0 +1,165 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/notify"
+)
+
+const replayFixture = `{"ts":"2024-01-15T10:00:00Z","source":"gt","type":"polecat_checked","actor":"gongshow/witness","visibility":"feed"}
+{"ts":"2024-01-15T10:00:10Z","source":"gt","type":"sling","actor":"mayor","visibility":"feed"}
+not json
+{"ts":"2024-01-15T10:00:30Z","source":"gt","type":"polecat_checked","actor":"gongshow/witness","visibility":"feed"}
+{"ts":"2024-01-15T10:01:30Z","source":"gt","type":"polecat_checked","actor":"gongshow/witness","visibility":"feed"}
+`
+
+// recordSleeps replaces replaySleep with one that records the waits.
+func recordSleeps(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var sleeps []time.Duration
+	orig := replaySleep
+	replaySleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	t.Cleanup(func() { replaySleep = orig })
+	return &sleeps
+}
+
+func TestReplayScalesTiming(t *testing.T) {
+	sleeps := recordSleeps(t)
+
+	var got []Event
+	opts := ReplayOptions{Filter: Filter{Types: []string{"polecat_checked"}}, Speed: 10}
+	skipped, err := ReplayWithOptions(context.Background(), strings.NewReader(replayFixture), opts, func(e Event) {
+		got = append(got, e)
+	})
+	if err != nil {
+		t.Fatalf("ReplayWithOptions: %v", err)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+	if len(got) != 3 {
+		t.Fatalf("replayed %d events, want 3", len(got))
+	}
+	// Gaps between matches are 30s and 60s; the sling in between is ignored.
+	if want := []time.Duration{3 * time.Second, 6 * time.Second}; !reflect.DeepEqual(*sleeps, want) {
+		t.Errorf("sleeps = %v, want %v", *sleeps, want)
+	}
+}
+
+func TestReplayWithoutSpeedDoesNotWait(t *testing.T) {
+	sleeps := recordSleeps(t)
+
+	n := 0
+	if _, err := Replay(strings.NewReader(replayFixture), func(Event) { n++ }); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if n != 4 {
+		t.Errorf("replayed %d events, want 4", n)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("sleeps = %v, want none", *sleeps)
+	}
+}
+
+func TestReplayMarksEvents(t *testing.T) {
+	townRoot := setupTown(t)
+
+	var got []Event
+	if _, err := Replay(strings.NewReader(replayFixture), func(e Event) {
+		got = append(got, e)
+		// A handler that logs what it was given must not re-append it.
+		if err := write(e); err != nil {
+			t.Errorf("write: %v", err)
+		}
+	}); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	for _, e := range got {
+		if !e.Replayed {
+			t.Errorf("event %s not marked replayed", e.Type)
+		}
+	}
+	if logged := readEvents(t, townRoot); len(logged) != 0 {
+		t.Errorf("replayed events were written to the log: %+v", logged)
+	}
+
+	env := strings.Join(HookEnv(got[0]), "\n")
+	if !strings.Contains(env, "GT_EVENT_REPLAYED=1") {
+		t.Errorf("hook env missing replay marker:\n%s", env)
+	}
+	if env := strings.Join(HookEnv(witnessDeath()), "\n"); strings.Contains(env, "GT_EVENT_REPLAYED") {
+		t.Errorf("live event carries replay marker:\n%s", env)
+	}
+}
+
+func TestRunHookReplayedSkipsMailAndNotify(t *testing.T) {
+	mailed := 0
+	SetMailSender(func(_ context.Context, townRoot, to, subject, body string) error {
+		mailed++
+		return nil
+	})
+	t.Cleanup(func() { SetMailSender(nil) })
+
+	notified := 0
+	orig := sendNotifications
+	sendNotifications = func(townRoot string, targets []notify.Target, n *notify.Notification) []*notify.Result {
+		notified++
+		return nil
+	}
+	t.Cleanup(func() { sendNotifications = orig })
+
+	e := witnessDeath()
+	e.Replayed = true
+	hook := config.EventHook{
+		Command: `printf '%s' "$GT_EVENT_REPLAYED" > replayed.txt`,
+		Mail:    &config.EventHookMail{To: "overseer"},
+		Notify: &config.EventHookNotify{
+			Targets: []config.EventHookTarget{{Channel: notify.ChannelSMS, Address: "+15551234567"}},
+		},
+	}
+	townRoot := t.TempDir()
+	if err := RunHook(context.Background(), townRoot, hook, e); err != nil {
+		t.Fatalf("RunHook: %v", err)
+	}
+	if mailed != 0 || notified != 0 {
+		t.Errorf("replayed event sent mail %d time(s) and notified %d time(s)", mailed, notified)
+	}
+	data, err := os.ReadFile(filepath.Join(townRoot, "replayed.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "1" {
+		t.Errorf("command saw GT_EVENT_REPLAYED=%q, want 1", got)
+	}
+}
+
+func TestParseReplaySpeed(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{"10x", 10, false},
+		{"0.5x", 0.5, false},
+		{"2", 2, false},
+		{"max", 0, false},
+		{"fast", 0, true},
+		{"-1x", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReplaySpeed(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseReplaySpeed(%q) = %v, %v; want %v (err %v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package events
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Rotation defaults.
+const (
+	DefaultMaxLogSize     = 50 * 1024 * 1024 // 50MB
+	DefaultMaxLogArchives = 10
+)
+
+// lockFile coordinates appenders, readers and rotation across gt processes.
+// Appenders, rotation and pruning hold it exclusively, so appends never
+// interleave and no process can still be writing to a file once it has been
+// renamed away. Readers hold it shared while they open the files.
+const lockFile = ".events.lock"
+
+// appendLockTimeout bounds how long an append waits for the events lock.
+// After that the event is appended anyway, with a warning, so a stuck lock
+// holder can't hang every gt command. A var for tests.
+var appendLockTimeout = 2 * time.Second
+
+// appendLockRetry is how often a waiting append retries the lock.
+const appendLockRetry = 5 * time.Millisecond
+
+// archiveTimeFormat sorts lexically in chronological order.
+const archiveTimeFormat = "20060102T150405.000000000Z"
+
+// RotationPolicy controls when the events log is rotated.
+// Loaded from environment variables:
+//   - GT_EVENTS_MAX_SIZE: Rotate once the active file exceeds this many bytes
+//     (default: 50MB, 0 disables rotation)
+//   - GT_EVENTS_MAX_ARCHIVES: Archives to keep; older ones are deleted
+//     (default: 10, 0 keeps all)
+//   - GT_EVENTS_GZIP: Set to "true" to gzip archives
+type RotationPolicy struct {
+	MaxSize     int64
+	MaxArchives int
+	Gzip        bool
+}
+
+// LoadRotationPolicy loads the rotation policy from environment variables.
+func LoadRotationPolicy() RotationPolicy {
+	p := RotationPolicy{
+		MaxSize:     DefaultMaxLogSize,
+		MaxArchives: DefaultMaxLogArchives,
+	}
+	if n, err := strconv.ParseInt(os.Getenv("GT_EVENTS_MAX_SIZE"), 10, 64); err == nil && n >= 0 {
+		p.MaxSize = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("GT_EVENTS_MAX_ARCHIVES")); err == nil && n >= 0 {
+		p.MaxArchives = n
+	}
+	switch strings.ToLower(os.Getenv("GT_EVENTS_GZIP")) {
+	case "1", "true", "yes", "on":
+		p.Gzip = true
+	}
+	return p
+}
+
+// appendEvent appends one line to the events log in dir, rotating it
+// afterwards if it has grown past policy.MaxSize.
+func appendEvent(dir string, line []byte, policy RotationPolicy) error {
+	unlock, err := lockForAppend(dir)
+	if err != nil {
+		return err
+	}
+	size, err := appendLine(filepath.Join(dir, EventsFile), line)
+	unlock()
+	if err != nil {
+		return err
+	}
+
+	if policy.MaxSize > 0 && size > policy.MaxSize {
+		// Best-effort: a failed rotation must not fail the event write.
+		_ = Rotate(dir, policy)
+	}
+	return nil
+}
+
+// appendAudit appends one line to the audit log in townRoot. It shares the
+// town's events lock, so the two logs can be read consistently.
+func appendAudit(townRoot string, line []byte) error {
+	unlock, err := lockForAppend(townRoot)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	_, err = appendLine(filepath.Join(townRoot, AuditFile), line)
+	return err
+}
+
+// lockForAppend takes the events lock in dir for an append, waiting at most
+// appendLockTimeout. If the lock can't be had by then, it warns and returns
+// a no-op unlock so the caller appends anyway.
+func lockForAppend(dir string) (unlock func(), err error) {
+	lock := flock.New(filepath.Join(dir, lockFile))
+	ctx, cancel := context.WithTimeout(context.Background(), appendLockTimeout)
+	locked, err := lock.TryLockContext(ctx, appendLockRetry)
+	cancel()
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("locking events file: %w", err)
+	}
+	if !locked {
+		fmt.Fprintf(os.Stderr, "Warning: events lock in %s still held after %s; appending without it\n", dir, appendLockTimeout)
+		return func() {}, nil
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
+
+// appendLine appends line with O_APPEND and returns the file size afterwards.
+// The line goes out in a single Write, so even an append made without the
+// lock lands whole rather than in pieces.
+func appendLine(path string, line []byte) (int64, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return 0, fmt.Errorf("opening events file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(line); err != nil {
+		return 0, fmt.Errorf("writing event: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return 0, nil
+	}
+	return info.Size(), nil
+}
+
+// Rotate renames the active events log in dir (the town root or a rig
+// directory) to a timestamped archive if it exceeds policy.MaxSize, then
+// compresses and prunes archives per policy.
+// Appenders reopen the active file by name, so the next event starts a
+// fresh file.
+func Rotate(dir string, policy RotationPolicy) error {
+	lock := flock.New(filepath.Join(dir, lockFile))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking events file: %w", err)
+	}
+
+	activePath := filepath.Join(dir, EventsFile)
+	info, err := os.Stat(activePath)
+	if err != nil || info.Size() <= policy.MaxSize {
+		// Another process rotated first, or there's nothing to rotate.
+		_ = lock.Unlock()
+		return nil
+	}
+
+	archive := filepath.Join(dir, archiveName(time.Now()))
+	if err := os.Rename(activePath, archive); err != nil {
+		_ = lock.Unlock()
+		return fmt.Errorf("rotating events file: %w", err)
+	}
+	_ = lock.Unlock()
+
+	// Compression runs unlocked: nothing writes to an archive, and readers
+	// prefer the plain file while both exist.
+	if policy.Gzip {
+		if err := gzipFile(archive); err != nil {
+			return fmt.Errorf("compressing %s: %w", filepath.Base(archive), err)
+		}
+	}
+	return pruneArchives(dir, policy.MaxArchives)
+}
+
+// archiveName returns the file name for an archive rotated at t.
+func archiveName(t time.Time) string {
+	return ".events-" + t.UTC().Format(archiveTimeFormat) + ".jsonl"
+}
+
+// gzipFile compresses path to path.gz and removes path.
+func gzipFile(path string) error {
+	src, err := os.Open(path) //nolint:gosec // G304: path is an events archive we just created
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// pruneArchives deletes the oldest archives beyond keep (0 keeps all).
+func pruneArchives(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	lock := flock.New(filepath.Join(dir, lockFile))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking events file: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	archives, err := listArchives(dir)
+	if err != nil {
+		return err
+	}
+	for len(archives) > keep {
+		if err := os.Remove(archives[0]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("pruning events archive: %w", err)
+		}
+		archives = archives[1:]
+	}
+	return nil
+}
+
+// listArchives returns the events archives in dir, oldest first.
+// When an archive exists both plain and gzipped (compression in progress),
+// only the plain file is returned.
+func listArchives(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byStem := make(map[string]string)
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, ".events-") {
+			continue
+		}
+		switch {
+		case strings.HasSuffix(name, ".jsonl"):
+			byStem[strings.TrimSuffix(name, ".jsonl")] = name
+		case strings.HasSuffix(name, ".jsonl.gz"):
+			stem := strings.TrimSuffix(name, ".jsonl.gz")
+			if _, ok := byStem[stem]; !ok {
+				byStem[stem] = name
+			}
+		}
+	}
+
+	stems := make([]string, 0, len(byStem))
+	for stem := range byStem {
+		stems = append(stems, stem)
+	}
+	sort.Strings(stems)
+
+	paths := make([]string, len(stems))
+	for i, stem := range stems {
+		paths[i] = filepath.Join(dir, byStem[stem])
+	}
+	return paths, nil
+}
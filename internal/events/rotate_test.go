@@ -0,0 +1,213 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeEvents appends n numbered events from each of workers goroutines.
+func writeEvents(t *testing.T, townRoot string, workers, n int, policy RotationPolicy) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				data, _ := json.Marshal(Event{Type: TypeNudge, Actor: fmt.Sprintf("w%d", w), Payload: map[string]interface{}{"seq": i}})
+				if err := appendEvent(townRoot, append(data, '\n'), policy); err != nil {
+					t.Errorf("appendEvent: %v", err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// readAllEvents reads the whole log through OpenLog and returns the events
+// as "actor/seq" keys in log order.
+func readAllEvents(t *testing.T, townRoot string) []string {
+	t.Helper()
+	r, err := OpenLog(townRoot)
+	if err != nil {
+		t.Fatalf("OpenLog: %v", err)
+	}
+	defer r.Close()
+
+	var keys []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("corrupt line %q: %v", scanner.Text(), err)
+		}
+		keys = append(keys, fmt.Sprintf("%s/%v", e.Actor, e.Payload["seq"]))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+// checkNoLossOrDuplicates verifies every worker's events appear exactly once
+// and in the order each worker wrote them.
+func checkNoLossOrDuplicates(t *testing.T, keys []string, workers, n int) {
+	t.Helper()
+	if len(keys) != workers*n {
+		t.Errorf("read %d events, want %d", len(keys), workers*n)
+	}
+	seen := make(map[string]bool)
+	next := make(map[string]int)
+	for _, k := range keys {
+		if seen[k] {
+			t.Errorf("duplicate event %s", k)
+		}
+		seen[k] = true
+
+		var actor string
+		var seq int
+		if _, err := fmt.Sscanf(strings.Replace(k, "/", " ", 1), "%s %d", &actor, &seq); err != nil {
+			t.Fatalf("bad key %q", k)
+		}
+		if seq != next[actor] {
+			t.Errorf("%s: got seq %d, want %d (out of order or lost)", actor, seq, next[actor])
+		}
+		next[actor] = seq + 1
+	}
+}
+
+func TestRotationNoLossOrDuplicates(t *testing.T) {
+	townRoot := t.TempDir()
+	policy := RotationPolicy{MaxSize: 512}
+
+	writeEvents(t, townRoot, 4, 100, policy)
+
+	archives, err := listArchives(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archives) < 2 {
+		t.Fatalf("expected several rotations with a 512-byte limit, got %d archives", len(archives))
+	}
+	checkNoLossOrDuplicates(t, readAllEvents(t, townRoot), 4, 100)
+}
+
+func TestRotationGzip(t *testing.T) {
+	townRoot := t.TempDir()
+	policy := RotationPolicy{MaxSize: 256, Gzip: true}
+
+	writeEvents(t, townRoot, 2, 50, policy)
+
+	archives, err := listArchives(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range archives {
+		if !strings.HasSuffix(a, ".jsonl.gz") {
+			t.Errorf("archive %s not compressed", filepath.Base(a))
+		}
+	}
+	checkNoLossOrDuplicates(t, readAllEvents(t, townRoot), 2, 50)
+}
+
+func TestRotationPrunesArchives(t *testing.T) {
+	townRoot := t.TempDir()
+	policy := RotationPolicy{MaxSize: 128, MaxArchives: 3}
+
+	writeEvents(t, townRoot, 1, 100, policy)
+
+	archives, err := listArchives(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archives) != 3 {
+		t.Errorf("kept %d archives, want 3", len(archives))
+	}
+
+	// The surviving events are the newest ones, still in order.
+	keys := readAllEvents(t, townRoot)
+	if len(keys) == 0 || keys[len(keys)-1] != "w0/99" {
+		t.Errorf("newest event missing after pruning: %v", keys)
+	}
+}
+
+func TestRotationDisabled(t *testing.T) {
+	townRoot := t.TempDir()
+	writeEvents(t, townRoot, 1, 20, RotationPolicy{})
+
+	if archives, _ := listArchives(townRoot); len(archives) != 0 {
+		t.Errorf("MaxSize 0 should never rotate, got %d archives", len(archives))
+	}
+}
+
+func TestOpenLogMissing(t *testing.T) {
+	if _, err := OpenLog(t.TempDir()); !os.IsNotExist(err) {
+		t.Errorf("OpenLog on empty town: got %v, want not-exist error", err)
+	}
+}
+
+func TestListArchivesPrefersPlainDuringCompression(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, name := range []string{
+		".events-20260101T000000.000000000Z.jsonl.gz",
+		".events-20260102T000000.000000000Z.jsonl",
+		".events-20260102T000000.000000000Z.jsonl.gz",
+		".events-20260103T000000.000000000Z.jsonl.gz.tmp",
+		EventsFile,
+		lockFile,
+	} {
+		if err := os.WriteFile(filepath.Join(townRoot, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archives, err := listArchives(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, a := range archives {
+		names = append(names, filepath.Base(a))
+	}
+	want := []string{".events-20260101T000000.000000000Z.jsonl.gz", ".events-20260102T000000.000000000Z.jsonl"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("listArchives = %v, want %v", names, want)
+	}
+}
+
+func TestTailerFollowsRotation(t *testing.T) {
+	townRoot := t.TempDir()
+	policy := RotationPolicy{MaxSize: 1 << 20}
+
+	tailer, err := NewTailer(townRoot)
+	if err != nil {
+		t.Fatalf("NewTailer: %v", err)
+	}
+	defer tailer.Close()
+
+	writeEvents(t, townRoot, 1, 3, policy)
+	before := tailer.Lines()
+
+	// Force a rotation, then keep writing to the new active file.
+	if err := Rotate(townRoot, RotationPolicy{MaxSize: 1}); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	for i := 3; i < 6; i++ {
+		data, _ := json.Marshal(Event{Type: TypeNudge, Actor: "w0", Payload: map[string]interface{}{"seq": i}})
+		if err := appendEvent(townRoot, append(data, '\n'), policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	after := tailer.Lines()
+
+	if len(before) != 3 || len(after) != 3 {
+		t.Errorf("tailer saw %d lines before and %d after rotation, want 3 and 3", len(before), len(after))
+	}
+}
@@ -0,0 +1,359 @@
+package events
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// CurrentSchemaVersion is stamped on every event written by Log.
+// Events from before versioning have no version (0).
+const CurrentSchemaVersion = 1
+
+// Kind is the expected kind of a payload value.
+type Kind int
+
+// Payload value kinds. Numbers and lists are checked loosely so that values
+// pass both as written (int, []string) and after a JSON round-trip
+// (float64, []interface{}).
+const (
+	KindString Kind = iota
+	KindNumber
+	KindBool
+	KindList
+	KindObject
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindNumber:
+		return "number"
+	case KindBool:
+		return "bool"
+	case KindList:
+		return "list"
+	case KindObject:
+		return "object"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// matches reports whether v is of kind k.
+func (k Kind) matches(v interface{}) bool {
+	switch k {
+	case KindString:
+		_, ok := v.(string)
+		return ok
+	case KindNumber:
+		switch v.(type) {
+		case int, int32, int64, uint, uint32, uint64, float32, float64, json.Number:
+			return true
+		}
+	case KindBool:
+		_, ok := v.(bool)
+		return ok
+	case KindList:
+		switch v.(type) {
+		case []string, []interface{}:
+			return true
+		}
+	case KindObject:
+		_, ok := v.(map[string]interface{})
+		return ok
+	}
+	return false
+}
+
+// Schema describes the payload of one shape of event. Required keys must be
+// present with the given kind; optional keys are only kind-checked when
+// present. Keys the schema doesn't mention are allowed.
+type Schema struct {
+	Required map[string]Kind
+	Optional map[string]Kind
+}
+
+// check returns the problems with payload against s.
+func (s Schema) check(payload map[string]interface{}) []string {
+	var problems []string
+	for _, key := range sortedKeys(s.Required) {
+		v, ok := payload[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("missing %q", key))
+			continue
+		}
+		if !s.Required[key].matches(v) {
+			problems = append(problems, fmt.Sprintf("%q is %T, want %s", key, v, s.Required[key]))
+		}
+	}
+	for _, key := range sortedKeys(s.Optional) {
+		if v, ok := payload[key]; ok && !s.Optional[key].matches(v) {
+			problems = append(problems, fmt.Sprintf("%q is %T, want %s", key, v, s.Optional[key]))
+		}
+	}
+	return problems
+}
+
+func sortedKeys(m map[string]Kind) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// strs builds a key set where every key is a string.
+func strs(keys ...string) map[string]Kind {
+	m := make(map[string]Kind, len(keys))
+	for _, k := range keys {
+		m[k] = KindString
+	}
+	return m
+}
+
+// with adds key of kind k to m and returns m.
+func with(m map[string]Kind, key string, k Kind) map[string]Kind {
+	m[key] = k
+	return m
+}
+
+// mergeSchema covers MergePayload as well as the free-form merge events
+// emitted through `gt activity emit`, so nothing is required.
+var mergeSchema = Schema{Optional: strs("mr", "worker", "branch", "reason", "rig", "message")}
+
+// schemas lists the accepted payload shapes per event type; an event is
+// valid if it matches any of them. Types without an entry (e.g. custom
+// types emitted by `gt activity emit`) are not validated.
+var schemas = map[string][]Schema{
+	TypeSling:      {{Required: strs("bead", "target"), Optional: strs("formula")}},
+	TypeHook:       {{Required: strs("bead")}},
+	TypeUnhook:     {{Required: strs("bead")}},
+	TypeHandoff:    {{Required: map[string]Kind{"to_session": KindBool}, Optional: strs("subject")}},
+	TypeDone:       {{Required: strs("bead", "branch")}},
+	TypeMail:       {{Required: strs("to", "subject")}},
+	TypeMailBounce: {{Required: strs("from", "to", "subject", "reason")}},
+	TypeSpawn:      {{Required: strs("rig", "polecat")}},
+	TypeKill:       {{Required: strs("rig", "target", "reason")}},
+	TypeNudge:      {{Required: strs("rig", "target", "reason")}},
+	TypeBoot:       {{Required: with(strs("rig"), "agents", KindList)}},
+	TypeHalt:       {{Required: map[string]Kind{"services": KindList}}},
+
+	TypeSessionStart: {{Required: strs("session_id", "role", "actor_pid"), Optional: strs("topic", "cwd")}},
+	TypeSessionEnd:   {{Required: strs("session_id", "role", "actor_pid"), Optional: strs("topic", "cwd")}},
+	TypeSessionDeath: {{Required: strs("session", "agent", "reason", "caller")}},
+	TypeMassDeath: {{
+		Required: with(with(strs("window"), "count", KindNumber), "sessions", KindList),
+		Optional: strs("possible_cause"),
+	}},
+
+	TypePatrolStarted:      {{Required: with(strs("rig"), "polecat_count", KindNumber), Optional: strs("message")}},
+	TypePatrolComplete:     {{Required: with(strs("rig"), "polecat_count", KindNumber), Optional: strs("message")}},
+	TypePatrolPluginResult: {{Required: strs("rig", "plugin", "status"), Optional: map[string]Kind{"details": KindObject}}},
+	TypePolecatChecked:     {{Required: strs("rig", "polecat", "status"), Optional: strs("issue")}},
+	TypePolecatNudged:      {{Required: strs("rig", "target", "reason")}},
+	TypeEscalationSent: {
+		{Required: strs("rig", "target", "to", "reason"), Optional: strs("severity", "actions", "source")},
+		{ // re-escalation
+			Required: with(strs("escalation_id", "new_severity"), "reescalated", KindBool),
+			Optional: with(strs("old_severity", "targets"), "reescalation_num", KindNumber),
+		},
+	},
+	TypeEscalationAcked:  {{Required: strs("escalation_id", "acked_by")}},
+	TypeEscalationClosed: {{Required: strs("escalation_id", "closed_by", "reason")}},
+
+	TypeMergeStarted: {mergeSchema},
+	TypeMerged:       {mergeSchema},
+	TypeMergeFailed:  {mergeSchema},
+	TypeMergeSkipped: {mergeSchema},
+
+	TypeBeadTransition: {{Required: strs("bead", "from", "to"), Optional: strs("reason")}},
+
+	TypeCrewHandover: {{Required: with(strs("from", "to", "summary"), "beads", KindList)}},
+
+	TypeBootLockReclaimed: {{Required: map[string]Kind{"pid": KindNumber}, Optional: strs("started_at")}},
+
+	TypeEventInvalid: {{Required: with(strs("event_type"), "problems", KindList)}},
+
+	TypeDoctorFix:  {{Required: with(strs("check"), "success", KindBool), Optional: strs("error")}},
+	TypeMigration:  {{Required: with(strs("name", "from", "to"), "items_converted", KindNumber)}},
+	TypeQueueClaim: {{Required: strs("queue", "task", "worker")}},
+	TypeQueueRelease: {
+		{Required: strs("queue", "task", "worker", "status")},
+		{Required: strs("message", "queue", "claimed_by")}, // written before release statuses
+	},
+}
+
+// ValidationError describes why an event doesn't match its schema.
+type ValidationError struct {
+	Type     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s event: %s", e.Type, strings.Join(e.Problems, "; "))
+}
+
+// Validate checks an event's payload against the schema for its type.
+// Returns a *ValidationError if it matches none of the accepted shapes.
+func Validate(e Event) error {
+	shapes, ok := schemas[e.Type]
+	if !ok {
+		return nil
+	}
+	var problems []string
+	for i, s := range shapes {
+		p := s.check(e.Payload)
+		if len(p) == 0 {
+			return nil
+		}
+		if i == 0 {
+			problems = p // report against the primary shape
+		}
+	}
+	return &ValidationError{Type: e.Type, Problems: problems}
+}
+
+// HasSchema reports whether events of eventType are validated.
+func HasSchema(eventType string) bool {
+	_, ok := schemas[eventType]
+	return ok
+}
+
+// invalidEvents counts invalid events logged by this process.
+var invalidEvents atomic.Int64
+
+// InvalidEventCount returns the number of invalid events this process has
+// tried to log since it started.
+func InvalidEventCount() int64 {
+	return invalidEvents.Load()
+}
+
+// StrictValidation reports whether invalid events are rejected.
+// Set GT_EVENTS_STRICT to "true" to make Log return a *ValidationError
+// instead of writing the event. By default invalid events are written,
+// followed by an event_invalid audit event describing the problems.
+func StrictValidation() bool {
+	switch strings.ToLower(os.Getenv("GT_EVENTS_STRICT")) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// invalidEventPayload creates a payload for event_invalid events.
+func invalidEventPayload(verr *ValidationError) map[string]interface{} {
+	return map[string]interface{}{
+		"event_type": verr.Type,
+		"problems":   verr.Problems,
+	}
+}
+
+// maxReportedIssues caps the issues kept in a FileReport.
+const maxReportedIssues = 100
+
+// ValidationIssue is one invalid event found by ValidateFile.
+type ValidationIssue struct {
+	Line     int
+	Type     string
+	Problems []string
+}
+
+// FileReport is the result of auditing one events file.
+type FileReport struct {
+	Path        string
+	Events      int               // well-formed events read
+	Malformed   int               // lines that aren't JSON events
+	Invalid     int               // events that fail validation
+	Warnings    int               // event_invalid events recorded at write time
+	Unversioned int               // events written before schema versioning
+	Issues      []ValidationIssue // the first invalid events (capped)
+}
+
+// ValidateFile validates every event in an events file, plain or gzipped.
+func ValidateFile(path string) (*FileReport, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is an events file chosen by the caller
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var r io.Reader = f
+	if filepath.Ext(path) == ".gz" {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+		}
+		defer func() { _ = zr.Close() }()
+		r = zr
+	}
+
+	report := &FileReport{Path: path}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(text), &e); err != nil || e.Type == "" {
+			report.Malformed++
+			continue
+		}
+		report.Events++
+		if e.SchemaVersion == 0 {
+			report.Unversioned++
+		}
+		if e.Type == TypeEventInvalid {
+			report.Warnings++
+		}
+		if err := Validate(e); err != nil {
+			report.Invalid++
+			if len(report.Issues) < maxReportedIssues {
+				verr := err.(*ValidationError)
+				report.Issues = append(report.Issues, ValidationIssue{Line: line, Type: e.Type, Problems: verr.Problems})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	return report, nil
+}
+
+// LogFiles returns the paths of the events log files in the town root and
+// every rig directory: for each, rotated archives oldest first, then the
+// active file if it exists. The town's audit log, if any, comes last.
+func LogFiles(townRoot string) ([]string, error) {
+	dirs, err := LogDirs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, dir := range dirs {
+		archives, err := listArchives(dir)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, archives...)
+		active := filepath.Join(dir, EventsFile)
+		if _, err := os.Stat(active); err == nil {
+			files = append(files, active)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, AuditFile)); err == nil {
+		files = append(files, filepath.Join(townRoot, AuditFile))
+	}
+	return files, nil
+}
@@ -0,0 +1,276 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPayloadHelpersMatchSchemas catches drift between the payload helpers
+// (and the inline payloads used by gt escalate) and the schemas: each must be
+// valid for every type it is used with, as written and after a JSON round-trip.
+func TestPayloadHelpersMatchSchemas(t *testing.T) {
+	reescalation := map[string]interface{}{
+		"escalation_id":    "hq-abc",
+		"reescalated":      true,
+		"old_severity":     "medium",
+		"new_severity":     "high",
+		"reescalation_num": 2,
+		"targets":          "mayor,overseer",
+	}
+
+	tests := []struct {
+		name    string
+		typ     string
+		payload map[string]interface{}
+	}{
+		{"SlingPayload", TypeSling, SlingPayload("gt-abc", "gongshow/polecats/Toast")},
+		{"HookPayload", TypeHook, HookPayload("gt-abc")},
+		{"UnhookPayload", TypeUnhook, UnhookPayload("gt-abc")},
+		{"HandoffPayload", TypeHandoff, HandoffPayload("", true)},
+		{"HandoffPayload with subject", TypeHandoff, HandoffPayload("context full", false)},
+		{"DonePayload", TypeDone, DonePayload("gt-abc", "polecat/Toast")},
+		{"MailPayload", TypeMail, MailPayload("mayor/", "Status")},
+		{"MailBouncePayload", TypeMailBounce, MailBouncePayload("mayor/", "nobody/", "Hi", "no such address")},
+		{"SpawnPayload", TypeSpawn, SpawnPayload("gongshow", "Toast")},
+		{"KillPayload", TypeKill, KillPayload("gongshow", "Toast", "zombie")},
+		{"NudgePayload", TypeNudge, NudgePayload("", "mayor", "wake up")},
+		{"BootPayload", TypeBoot, BootPayload("gongshow", []string{"witness", "refinery"})},
+		{"HaltPayload", TypeHalt, HaltPayload([]string{"daemon"})},
+		{"SessionPayload start", TypeSessionStart, SessionPayload("uuid", "deacon", "", "")},
+		{"SessionPayload end", TypeSessionEnd, SessionPayload("uuid", "gongshow/crew/joe", "topic", "/tmp")},
+		{"SessionDeathPayload", TypeSessionDeath, SessionDeathPayload("gt-gongshow-Toast", "gongshow/polecats/Toast", "zombie cleanup", "daemon")},
+		{"MassDeathPayload", TypeMassDeath, MassDeathPayload(3, "5s", []string{"a", "b", "c"}, "tmux crash")},
+		{"PatrolPayload started", TypePatrolStarted, PatrolPayload("gongshow", 3, "")},
+		{"PatrolPayload complete", TypePatrolComplete, PatrolPayload("gongshow", 3, "all good")},
+		{"PatrolPluginPayload", TypePatrolPluginResult, PatrolPluginPayload("gongshow", "disk-space", "ok", map[string]interface{}{"free_gb": 12})},
+		{"PolecatCheckPayload", TypePolecatChecked, PolecatCheckPayload("gongshow", "Toast", "working", "gt-abc")},
+		{"NudgePayload polecat", TypePolecatNudged, NudgePayload("gongshow", "Toast", "idle")},
+		{"EscalationPayload", TypeEscalationSent, EscalationPayload("gongshow", "Toast", "mayor", "stuck")},
+		{"re-escalation", TypeEscalationSent, reescalation},
+		{"escalation ack", TypeEscalationAcked, map[string]interface{}{"escalation_id": "hq-abc", "acked_by": "mayor"}},
+		{"escalation close", TypeEscalationClosed, map[string]interface{}{"escalation_id": "hq-abc", "closed_by": "mayor", "reason": "fixed"}},
+		{"MergePayload started", TypeMergeStarted, MergePayload("mr-1", "Toast", "polecat/Toast", "")},
+		{"MergePayload merged", TypeMerged, MergePayload("mr-1", "Toast", "polecat/Toast", "")},
+		{"MergePayload failed", TypeMergeFailed, MergePayload("mr-1", "Toast", "polecat/Toast", "conflict")},
+		{"MergePayload skipped", TypeMergeSkipped, MergePayload("mr-1", "Toast", "polecat/Toast", "superseded")},
+		{"BeadTransitionPayload", TypeBeadTransition, BeadTransitionPayload("gt-abc", "delegated", "collected", "gc")},
+		{"CrewHandoverPayload", TypeCrewHandover, CrewHandoverPayload("gongshow/crew/max", "gongshow/crew/joe", "auth half done", []string{"gt-abc"})},
+		{"CrewHandoverPayload no beads", TypeCrewHandover, CrewHandoverPayload("gongshow/crew/max", "gongshow/crew/joe", "", nil)},
+		{"BootLockReclaimedPayload", TypeBootLockReclaimed, BootLockReclaimedPayload(4242, "2026-01-02T15:04:05Z")},
+		{"BootLockReclaimedPayload legacy marker", TypeBootLockReclaimed, BootLockReclaimedPayload(0, "")},
+		{"invalidEventPayload", TypeEventInvalid, invalidEventPayload(&ValidationError{Type: TypeSpawn, Problems: []string{`missing "rig"`}})},
+		{"DoctorFixPayload", TypeDoctorFix, DoctorFixPayload("stale-locks", nil)},
+		{"DoctorFixPayload failed", TypeDoctorFix, DoctorFixPayload("stale-locks", errors.New("permission denied"))},
+		{"MigrationPayload", TypeMigration, MigrationPayload("agent-fields", "v1", "v2", 12)},
+		{"QueueClaimPayload", TypeQueueClaim, QueueClaimPayload("work/gongshow", "hq-msg1", "gongshow/polecats/Toast")},
+		{"QueueReleasePayload", TypeQueueRelease, QueueReleasePayload("work/gongshow", "hq-msg1", "gongshow/polecats/Toast", QueueStatusFailed)},
+		{"queue release before statuses", TypeQueueRelease, map[string]interface{}{"message": "hq-msg1", "queue": "work/gongshow", "claimed_by": "gongshow/polecats/Toast"}},
+	}
+
+	covered := make(map[string]bool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !HasSchema(tt.typ) {
+				t.Fatalf("no schema for %s", tt.typ)
+			}
+			e := Event{Type: tt.typ, Payload: tt.payload}
+			if err := Validate(e); err != nil {
+				t.Errorf("as written: %v", err)
+			}
+
+			data, err := json.Marshal(e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded Event
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if err := Validate(decoded); err != nil {
+				t.Errorf("after JSON round-trip: %v", err)
+			}
+		})
+		covered[tt.typ] = true
+	}
+
+	// Every schema must be exercised above.
+	for typ := range schemas {
+		if !covered[typ] {
+			t.Errorf("schema for %s is not covered by a payload helper test", typ)
+		}
+	}
+}
+
+func TestValidateRejectsDrift(t *testing.T) {
+	tests := []struct {
+		name    string
+		e       Event
+		problem string
+	}{
+		{"missing key", Event{Type: TypeSpawn, Payload: map[string]interface{}{"rig": "gongshow"}}, `missing "polecat"`},
+		{"nil payload", Event{Type: TypeHook}, `missing "bead"`},
+		{"wrong kind", Event{Type: TypeHandoff, Payload: map[string]interface{}{"to_session": "yes"}}, `"to_session" is string, want bool`},
+		{"wrong optional kind", Event{Type: TypeSling, Payload: map[string]interface{}{"bead": "a", "target": "b", "formula": 3}}, `"formula" is int, want string`},
+		{"number as string", Event{Type: TypeMassDeath, Payload: map[string]interface{}{"count": "3", "window": "5s", "sessions": []string{}}}, `"count" is string, want number`},
+		{"no shape matches", Event{Type: TypeEscalationSent, Payload: map[string]interface{}{"escalation_id": "hq-1"}}, `missing "rig"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.e)
+			verr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Validate = %v, want *ValidationError", err)
+			}
+			if !strings.Contains(verr.Error(), tt.problem) {
+				t.Errorf("error %q does not mention %q", verr.Error(), tt.problem)
+			}
+		})
+	}
+
+	// Unknown types and extra keys are allowed.
+	if err := Validate(Event{Type: "custom", Payload: map[string]interface{}{"x": 1}}); err != nil {
+		t.Errorf("custom type: %v", err)
+	}
+	if err := Validate(Event{Type: TypeHook, Payload: map[string]interface{}{"bead": "a", "extra": 1}}); err != nil {
+		t.Errorf("extra key: %v", err)
+	}
+}
+
+// setupTown creates a minimal town and makes it the working directory.
+func setupTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	return townRoot
+}
+
+func readEvents(t *testing.T, townRoot string) []Event {
+	t.Helper()
+	return readEventsFile(t, filepath.Join(townRoot, EventsFile))
+}
+
+// readEventsFile returns the events in one log file (none if it is missing).
+func readEventsFile(t *testing.T, path string) []Event {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	var events []Event
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestLogInvalidEventDefaultMode(t *testing.T) {
+	townRoot := setupTown(t)
+	t.Setenv("GT_EVENTS_STRICT", "")
+
+	before := InvalidEventCount()
+	if err := LogFeed(TypeSpawn, "mayor", SpawnPayload("gongshow", "Toast")); err != nil {
+		t.Fatal(err)
+	}
+	if err := LogFeed(TypeSpawn, "mayor", map[string]interface{}{"rig": "gongshow"}); err != nil {
+		t.Fatalf("default mode should not reject: %v", err)
+	}
+
+	got := readEvents(t, townRoot)
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2", len(got))
+	}
+	for _, e := range got {
+		if e.SchemaVersion != CurrentSchemaVersion {
+			t.Errorf("%s event has schema version %d", e.Type, e.SchemaVersion)
+		}
+	}
+	// The warning is audit-only, so it goes to the audit log.
+	audit := readEventsFile(t, filepath.Join(townRoot, AuditFile))
+	if len(audit) != 1 {
+		t.Fatalf("got %d audit events, want 1", len(audit))
+	}
+	warning := audit[0]
+	if warning.Type != TypeEventInvalid || warning.Visibility != VisibilityAudit {
+		t.Fatalf("audit event = %s/%s, want %s/%s", warning.Type, warning.Visibility, TypeEventInvalid, VisibilityAudit)
+	}
+	if warning.Payload["event_type"] != TypeSpawn {
+		t.Errorf("event_type = %v", warning.Payload["event_type"])
+	}
+	if n := InvalidEventCount() - before; n != 1 {
+		t.Errorf("InvalidEventCount grew by %d, want 1", n)
+	}
+}
+
+func TestLogInvalidEventStrictMode(t *testing.T) {
+	townRoot := setupTown(t)
+	t.Setenv("GT_EVENTS_STRICT", "true")
+
+	err := LogFeed(TypeSpawn, "mayor", map[string]interface{}{"rig": "gongshow"})
+	if _, ok := err.(*ValidationError); !ok {
+		t.Fatalf("LogFeed = %v, want *ValidationError", err)
+	}
+	if got := readEvents(t, townRoot); len(got) != 0 {
+		t.Errorf("strict mode wrote %d events", len(got))
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	dir := t.TempDir()
+	lines := []string{
+		`{"ts":"2024-01-15T10:00:00Z","type":"spawn","actor":"mayor","payload":{"rig":"gongshow","polecat":"Toast"}}`,
+		`{"ts":"2024-01-15T10:00:01Z","type":"spawn","actor":"mayor","payload":{"rig":"gongshow"},"schema_version":1}`,
+		`not json`,
+		`{"ts":"2024-01-15T10:00:01Z","type":"event_invalid","actor":"mayor","payload":{"event_type":"spawn","problems":["missing \"polecat\""]},"schema_version":1}`,
+		`{"ts":"2024-01-15T10:00:02Z","type":"custom","actor":"mayor","schema_version":1}`,
+	}
+	path := filepath.Join(dir, EventsFile)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(t *testing.T, path string) {
+		r, err := ValidateFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Events != 4 || r.Malformed != 1 || r.Invalid != 1 || r.Warnings != 1 || r.Unversioned != 1 {
+			t.Errorf("report = %+v", r)
+		}
+		if len(r.Issues) != 1 || r.Issues[0].Line != 2 || r.Issues[0].Type != TypeSpawn {
+			t.Errorf("issues = %+v", r.Issues)
+		}
+	}
+	t.Run("plain", func(t *testing.T) { check(t, path) })
+	t.Run("gzip", func(t *testing.T) {
+		gz := filepath.Join(dir, ".events-x.jsonl")
+		data, _ := os.ReadFile(path)
+		if err := os.WriteFile(gz, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := gzipFile(gz); err != nil {
+			t.Fatal(err)
+		}
+		check(t, gz+".gz")
+	})
+
+	if _, err := ValidateFile(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("missing file: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// DefaultStatsTopN is how many actors are listed per event type.
+const DefaultStatsTopN = 3
+
+// StatsOptions controls CollectStats.
+type StatsOptions struct {
+	// Since drops events older than this (zero keeps everything).
+	Since time.Time
+
+	// TopN is how many actors to list per type (default: DefaultStatsTopN).
+	TopN int
+
+	// PolecatSession maps a spawn event's rig and polecat to the tmux session
+	// name that its session_death event will carry. Without it, polecat
+	// lifetimes are not computed.
+	PolecatSession func(rig, polecat string) string
+}
+
+// Stats summarises the events log.
+type Stats struct {
+	Since   time.Time `json:"since,omitempty"`
+	Total   int       `json:"total"`
+	Skipped int       `json:"skipped,omitempty"` // malformed lines
+
+	ByType    map[string]int          `json:"by_type"`
+	ByActor   map[string]int          `json:"by_actor"`
+	Days      []DayCount              `json:"days"`
+	TopActors map[string][]ActorCount `json:"top_actors"`
+
+	PolecatLifetime       LifetimeStats  `json:"polecat_lifetime"`
+	EscalationsBySeverity map[string]int `json:"escalations_by_severity"`
+}
+
+// DayCount is the number of events on one (UTC) day.
+type DayCount struct {
+	Day   string `json:"day"` // 2006-01-02
+	Count int    `json:"count"`
+}
+
+// ActorCount is the number of events by one actor.
+type ActorCount struct {
+	Actor string `json:"actor"`
+	Count int    `json:"count"`
+}
+
+// LifetimeStats describes polecat lifetimes, measured from spawn to the
+// session_death of the same session. Polecats spawned before the stats
+// window, or still alive, are not counted.
+type LifetimeStats struct {
+	Count   int           `json:"count"`
+	Average time.Duration `json:"average_ns"`
+}
+
+// StatsCollector aggregates events one at a time. Memory is bounded by the
+// number of distinct types, actors and days, plus polecats spawned but not
+// yet dead.
+type StatsCollector struct {
+	opts StatsOptions
+
+	total       int
+	byType      map[string]int
+	byActor     map[string]int
+	byTypeActor map[string]map[string]int
+	byDay       map[string]int
+	bySeverity  map[string]int
+
+	spawned       map[string]time.Time // session -> spawn time
+	lifetimeCount int
+	lifetimeSum   time.Duration
+}
+
+// NewStatsCollector returns an empty collector.
+func NewStatsCollector(opts StatsOptions) *StatsCollector {
+	if opts.TopN <= 0 {
+		opts.TopN = DefaultStatsTopN
+	}
+	return &StatsCollector{
+		opts:        opts,
+		byType:      make(map[string]int),
+		byActor:     make(map[string]int),
+		byTypeActor: make(map[string]map[string]int),
+		byDay:       make(map[string]int),
+		bySeverity:  make(map[string]int),
+		spawned:     make(map[string]time.Time),
+	}
+}
+
+// Add counts one event.
+func (c *StatsCollector) Add(e Event) {
+	c.total++
+	c.byType[e.Type]++
+	c.byActor[e.Actor]++
+	if c.byTypeActor[e.Type] == nil {
+		c.byTypeActor[e.Type] = make(map[string]int)
+	}
+	c.byTypeActor[e.Type][e.Actor]++
+
+	ts, err := time.Parse(time.RFC3339, e.Timestamp)
+	if err == nil {
+		c.byDay[ts.UTC().Format("2006-01-02")]++
+	}
+
+	switch e.Type {
+	case TypeSpawn:
+		rig, _ := e.Payload["rig"].(string)
+		polecat, _ := e.Payload["polecat"].(string)
+		if err == nil && c.opts.PolecatSession != nil && rig != "" && polecat != "" {
+			c.spawned[c.opts.PolecatSession(rig, polecat)] = ts
+		}
+	case TypeSessionDeath:
+		session, _ := e.Payload["session"].(string)
+		if start, ok := c.spawned[session]; ok && err == nil {
+			delete(c.spawned, session)
+			if d := ts.Sub(start); d >= 0 {
+				c.lifetimeCount++
+				c.lifetimeSum += d
+			}
+		}
+	case TypeEscalationSent:
+		severity, _ := e.Payload["severity"].(string)
+		if s, ok := e.Payload["new_severity"].(string); ok {
+			severity = s // re-escalation
+		}
+		if severity == "" {
+			severity = "unknown"
+		}
+		c.bySeverity[severity]++
+	}
+}
+
+// Result returns the aggregated statistics. Days between the first and last
+// day seen (or Since, if set) are filled in with zero counts.
+func (c *StatsCollector) Result() *Stats {
+	s := &Stats{
+		Since:                 c.opts.Since,
+		Total:                 c.total,
+		ByType:                c.byType,
+		ByActor:               c.byActor,
+		TopActors:             make(map[string][]ActorCount),
+		EscalationsBySeverity: c.bySeverity,
+	}
+
+	for typ, actors := range c.byTypeActor {
+		s.TopActors[typ] = topActors(actors, c.opts.TopN)
+	}
+
+	if c.lifetimeCount > 0 {
+		s.PolecatLifetime = LifetimeStats{
+			Count:   c.lifetimeCount,
+			Average: c.lifetimeSum / time.Duration(c.lifetimeCount),
+		}
+	}
+
+	s.Days = fillDays(c.byDay, c.opts.Since)
+	return s
+}
+
+// topActors returns the n busiest actors, ties broken by name.
+func topActors(counts map[string]int, n int) []ActorCount {
+	list := make([]ActorCount, 0, len(counts))
+	for actor, count := range counts {
+		list = append(list, ActorCount{actor, count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Actor < list[j].Actor
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// fillDays returns per-day counts in order, with empty days included.
+func fillDays(byDay map[string]int, since time.Time) []DayCount {
+	if len(byDay) == 0 {
+		return nil
+	}
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	first, _ := time.Parse("2006-01-02", days[0])
+	last, _ := time.Parse("2006-01-02", days[len(days)-1])
+	if !since.IsZero() {
+		if s := since.UTC().Truncate(24 * time.Hour); s.Before(first) {
+			first = s
+		}
+	}
+
+	var result []DayCount
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		day := d.Format("2006-01-02")
+		result = append(result, DayCount{day, byDay[day]})
+	}
+	return result
+}
+
+// CollectStats aggregates the whole events log (archives included) in a
+// single streaming pass.
+func CollectStats(townRoot string, opts StatsOptions) (*Stats, error) {
+	c := NewStatsCollector(opts)
+	skipped, err := Stream(context.Background(), townRoot, StreamOptions{
+		Filter:  Filter{Since: opts.Since},
+		History: -1,
+	}, func(_ string, e Event) error {
+		c.Add(e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s := c.Result()
+	s.Skipped = skipped
+	return s, nil
+}
@@ -0,0 +1,106 @@
+package events
+
+import (
+	"testing"
+	"time"
+)
+
+func testPolecatSession(rig, polecat string) string {
+	return "gt-" + rig + "-" + polecat
+}
+
+func TestStatsCollector(t *testing.T) {
+	base := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return base.Add(d).Format(time.RFC3339) }
+
+	c := NewStatsCollector(StatsOptions{TopN: 2, PolecatSession: testPolecatSession})
+	for _, e := range []Event{
+		{Timestamp: at(0), Type: TypeSpawn, Actor: "gt", Payload: SpawnPayload("gongshow", "Toast")},
+		{Timestamp: at(time.Minute), Type: TypeSpawn, Actor: "gt", Payload: SpawnPayload("gongshow", "Nux")},
+		{Timestamp: at(2 * time.Minute), Type: TypeSling, Actor: "mayor"},
+		{Timestamp: at(3 * time.Minute), Type: TypeSling, Actor: "mayor"},
+		{Timestamp: at(4 * time.Minute), Type: TypeSling, Actor: "gongshow/crew/max"},
+		{Timestamp: at(5 * time.Minute), Type: TypeSling, Actor: "deacon"},
+		// Toast lives 1h, Nux 3h; an unknown session death doesn't pair.
+		{Timestamp: at(time.Hour), Type: TypeSessionDeath, Actor: "gt", Payload: SessionDeathPayload("gt-gongshow-Toast", "gongshow/Toast", "done", "gt done")},
+		{Timestamp: at(time.Minute + 3*time.Hour), Type: TypeSessionDeath, Actor: "gt", Payload: SessionDeathPayload("gt-gongshow-Nux", "gongshow/Nux", "done", "gt done")},
+		{Timestamp: at(4 * time.Hour), Type: TypeSessionDeath, Actor: "gt", Payload: SessionDeathPayload("gt-other-Ghost", "other/Ghost", "orphan cleanup", "gt doctor")},
+		// A second death for Toast must not pair again.
+		{Timestamp: at(5 * time.Hour), Type: TypeSessionDeath, Actor: "gt", Payload: SessionDeathPayload("gt-gongshow-Toast", "gongshow/Toast", "again", "gt down")},
+		{Timestamp: at(48 * time.Hour), Type: TypeEscalationSent, Actor: "gongshow/witness", Payload: map[string]interface{}{"severity": "high"}},
+		{Timestamp: at(48 * time.Hour), Type: TypeEscalationSent, Actor: "gongshow/witness", Payload: map[string]interface{}{"severity": "high"}},
+		{Timestamp: at(48 * time.Hour), Type: TypeEscalationSent, Actor: "deacon", Payload: map[string]interface{}{"reescalated": true, "old_severity": "high", "new_severity": "critical"}},
+		{Timestamp: at(48 * time.Hour), Type: TypeEscalationSent, Actor: "deacon"},
+	} {
+		c.Add(e)
+	}
+	s := c.Result()
+
+	if s.Total != 14 {
+		t.Errorf("Total = %d, want 14", s.Total)
+	}
+	for typ, want := range map[string]int{TypeSpawn: 2, TypeSling: 4, TypeSessionDeath: 4, TypeEscalationSent: 4} {
+		if s.ByType[typ] != want {
+			t.Errorf("ByType[%s] = %d, want %d", typ, s.ByType[typ], want)
+		}
+	}
+	if s.ByActor["gt"] != 6 || s.ByActor["mayor"] != 2 {
+		t.Errorf("ByActor = %v", s.ByActor)
+	}
+
+	top := s.TopActors[TypeSling]
+	if len(top) != 2 || top[0] != (ActorCount{"mayor", 2}) || top[1] != (ActorCount{"deacon", 1}) {
+		t.Errorf("TopActors[sling] = %v, want mayor:2 then deacon:1 (ties by name)", top)
+	}
+
+	if s.PolecatLifetime.Count != 2 {
+		t.Errorf("paired %d lifetimes, want 2", s.PolecatLifetime.Count)
+	}
+	if s.PolecatLifetime.Average != 2*time.Hour {
+		t.Errorf("average lifetime = %v, want 2h", s.PolecatLifetime.Average)
+	}
+
+	for sev, want := range map[string]int{"high": 2, "critical": 1, "unknown": 1} {
+		if s.EscalationsBySeverity[sev] != want {
+			t.Errorf("EscalationsBySeverity[%s] = %d, want %d", sev, s.EscalationsBySeverity[sev], want)
+		}
+	}
+
+	want := []DayCount{{"2024-01-15", 10}, {"2024-01-16", 0}, {"2024-01-17", 4}}
+	if len(s.Days) != len(want) {
+		t.Fatalf("Days = %v, want %v", s.Days, want)
+	}
+	for i := range want {
+		if s.Days[i] != want[i] {
+			t.Errorf("Days[%d] = %v, want %v", i, s.Days[i], want[i])
+		}
+	}
+}
+
+func TestStatsCollectorWithoutSessionMapper(t *testing.T) {
+	c := NewStatsCollector(StatsOptions{})
+	ts := time.Now().UTC().Format(time.RFC3339)
+	c.Add(Event{Timestamp: ts, Type: TypeSpawn, Payload: SpawnPayload("gongshow", "Toast")})
+	c.Add(Event{Timestamp: ts, Type: TypeSessionDeath, Payload: SessionDeathPayload("gt-gongshow-Toast", "", "", "")})
+	if got := c.Result().PolecatLifetime.Count; got != 0 {
+		t.Errorf("paired %d lifetimes without a session mapper, want 0", got)
+	}
+}
+
+func TestCollectStats(t *testing.T) {
+	townRoot := t.TempDir()
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	writeCorpus(t, townRoot, start, 10)
+	appendRaw(t, townRoot, "garbage\n")
+
+	s, err := CollectStats(townRoot, StatsOptions{Since: start.Add(5 * time.Minute)})
+	if err != nil {
+		t.Fatalf("CollectStats: %v", err)
+	}
+	if s.ByType[TypeSessionDeath] != 5 || s.ByType[TypeNudge] != 5 {
+		t.Errorf("ByType = %v, want 5 deaths and 5 nudges since the cutoff", s.ByType)
+	}
+	if s.Skipped != 1 {
+		t.Errorf("Skipped = %d, want 1", s.Skipped)
+	}
+}
@@ -0,0 +1,275 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultPollInterval is how often Stream checks for new events when following.
+const DefaultPollInterval = 250 * time.Millisecond
+
+// Filter selects events by type, actor, age and correlation. Zero fields
+// match everything.
+type Filter struct {
+	Types   []string                // event types to keep
+	Actor   func(actor string) bool // actor predicate
+	Since   time.Time               // drop events older than this
+	Until   time.Time               // drop events newer than this
+	Payload map[string]string       // payload key -> glob the value must match
+
+	Correlation string // correlation ID (or a prefix of it) the event must carry
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e *Event) bool {
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			if t == e.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Actor != nil && !f.Actor(e.Actor) {
+		return false
+	}
+	if f.Correlation != "" && (e.CorrelationID == "" || !strings.HasPrefix(e.CorrelationID, f.Correlation)) {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			return false
+		}
+		if !f.Since.IsZero() && ts.Before(f.Since) {
+			return false
+		}
+		if !f.Until.IsZero() && ts.After(f.Until) {
+			return false
+		}
+	}
+	for key, glob := range f.Payload {
+		v, ok := e.Payload[key]
+		if !ok || !MatchGlob(glob, fmt.Sprint(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchGlob reports whether s matches a shell-style glob where '*' matches
+// any run of characters (including '/') and '?' matches exactly one.
+func MatchGlob(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	// Iterative match with backtracking to the most recent '*'.
+	pi, si, star, mark := 0, 0, -1, 0
+	for si < len(str) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			pi = star + 1
+			mark++
+			si = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// StreamOptions controls Stream.
+type StreamOptions struct {
+	Filter Filter
+
+	// History is how many matching events already in the log (including
+	// archives) are delivered first: 0 delivers none, negative delivers all.
+	History int
+
+	// Follow keeps delivering new events until the context is cancelled.
+	Follow bool
+
+	// PollInterval is how often the log is checked when following
+	// (default: DefaultPollInterval).
+	PollInterval time.Duration
+
+	// Source is the log to read: SourceFeed (the default) or SourceAudit.
+	Source string
+}
+
+// Log sources for StreamOptions.Source.
+const (
+	SourceFeed  = "feed"  // the events log (town and rig files, with archives)
+	SourceAudit = "audit" // the town's audit log
+)
+
+// Stream delivers matching events from the town's events log to emit, with
+// the raw JSON line alongside the decoded event. When following, rotation is
+// handled by reopening the active file. Malformed lines are skipped and
+// counted rather than treated as errors; the count is returned.
+//
+// Stream returns when the history has been delivered (or, when following,
+// when ctx is cancelled), or when emit returns an error.
+func Stream(ctx context.Context, townRoot string, opts StreamOptions, emit func(raw string, e Event) error) (skipped int, err error) {
+	switch opts.Source {
+	case "", SourceFeed, SourceAudit:
+	default:
+		return 0, fmt.Errorf("unknown events source %q: want %s or %s", opts.Source, SourceFeed, SourceAudit)
+	}
+
+	match := func(line string) (Event, bool) {
+		var e Event
+		if line == "" {
+			return e, false
+		}
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			skipped++
+			return e, false
+		}
+		return e, opts.Filter.Match(&e)
+	}
+
+	tailer, err := replayHistory(townRoot, opts, match, emit)
+	if err != nil || tailer == nil {
+		return skipped, err
+	}
+	defer tailer.Close()
+
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, line := range tailer.Lines() {
+			if e, ok := match(line); ok {
+				if err := emit(line, e); err != nil {
+					return skipped, err
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return skipped, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// replayHistory delivers matching events already in the logs per
+// opts.History. When following, it returns a Tailer positioned exactly where
+// the history ended in each file, so no event is missed or repeated in
+// between.
+func replayHistory(townRoot string, opts StreamOptions, match func(string) (Event, bool), emit func(string, Event) error) (*Tailer, error) {
+	open := openMerged
+	if opts.Source == SourceAudit {
+		open = openAudit
+	}
+	m, err := open(townRoot, opts.Follow)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // nothing logged yet
+		}
+		return nil, err
+	}
+
+	if opts.History != 0 {
+		if err := readHistory(m, opts.History, match, emit); err != nil {
+			_ = m.Close()
+			return nil, err
+		}
+	} else {
+		for _, s := range m.sources {
+			if s.log.active == nil {
+				continue
+			}
+			if _, err := s.log.active.Seek(0, io.SeekEnd); err != nil {
+				_ = m.Close()
+				return nil, err
+			}
+			s.partial = nil
+		}
+	}
+
+	if !opts.Follow {
+		return nil, m.Close()
+	}
+
+	// Hand each active file over to the tailer, along with any line still
+	// being written when the history ran out.
+	t := &Tailer{townRoot: townRoot, seen: m.seen, audit: opts.Source == SourceAudit}
+	for _, s := range m.sources {
+		if s.log.active == nil {
+			continue
+		}
+		t.files = append(t.files, &fileTailer{
+			dir:    s.dir,
+			path:   s.log.active.Name(),
+			file:   s.log.active,
+			reader: bufio.NewReader(s.log.active),
+			buf:    s.partial,
+		})
+		s.log.active = nil
+	}
+	_ = m.Close()
+	return t, nil
+}
+
+// readHistory emits the last n matching events from the merged log (all of
+// them if n is negative).
+func readHistory(m *mergedLog, n int, match func(string) (Event, bool), emit func(string, Event) error) error {
+	type entry struct {
+		raw string
+		e   Event
+	}
+	var ring []entry
+
+	for {
+		line, err := m.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		e, ok := match(line)
+		if !ok {
+			continue
+		}
+		if n < 0 {
+			if err := emit(line, e); err != nil {
+				return err
+			}
+			continue
+		}
+		ring = append(ring, entry{line, e})
+		if len(ring) > n {
+			ring = ring[1:]
+		}
+	}
+	for _, en := range ring {
+		if err := emit(en.raw, en.e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// appendTestEvent appends an event with the given type and actor.
+func appendTestEvent(t *testing.T, townRoot, eventType, actor string, ts time.Time) {
+	t.Helper()
+	data, _ := json.Marshal(Event{Timestamp: ts.UTC().Format(time.RFC3339), Type: eventType, Actor: actor})
+	if err := appendEvent(townRoot, append(data, '\n'), RotationPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// appendRaw appends raw bytes to the active events file.
+func appendRaw(t *testing.T, townRoot, raw string) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(townRoot, EventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(raw); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Now()
+	f := Filter{
+		Types: []string{TypeSling, TypeMail},
+		Actor: func(a string) bool { return strings.HasPrefix(a, "gongshow/") },
+		Since: now.Add(-10 * time.Minute),
+	}
+	ts := now.UTC().Format(time.RFC3339)
+	old := now.Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		e    Event
+		want bool
+	}{
+		{Event{Type: TypeSling, Actor: "gongshow/Toast", Timestamp: ts}, true},
+		{Event{Type: TypeNudge, Actor: "gongshow/Toast", Timestamp: ts}, false},
+		{Event{Type: TypeMail, Actor: "mayor", Timestamp: ts}, false},
+		{Event{Type: TypeMail, Actor: "gongshow/Toast", Timestamp: old}, false},
+		{Event{Type: TypeMail, Actor: "gongshow/Toast", Timestamp: "garbage"}, false},
+	}
+	for _, tt := range tests {
+		if got := f.Match(&tt.e); got != tt.want {
+			t.Errorf("Match(%+v) = %v, want %v", tt.e, got, tt.want)
+		}
+	}
+	if !(Filter{}).Match(&Event{}) {
+		t.Error("zero Filter should match everything")
+	}
+}
+
+func TestStreamHistoryLast(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		appendTestEvent(t, townRoot, TypeSling, "gongshow/Toast", now)
+		appendTestEvent(t, townRoot, TypeNudge, "gongshow/Toast", now)
+	}
+	appendRaw(t, townRoot, "{not json\n")
+
+	var got []string
+	skipped, err := Stream(context.Background(), townRoot, StreamOptions{
+		Filter:  Filter{Types: []string{TypeSling}},
+		History: 3,
+	}, func(raw string, e Event) error {
+		got = append(got, e.Type)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("delivered %d events, want last 3 slings: %v", len(got), got)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+}
+
+func TestStreamAuditSource(t *testing.T) {
+	townRoot := setupTown(t)
+	_ = LogFeed("load_test", "mayor", map[string]interface{}{"n": "feed"})
+	_ = LogAudit("load_test", "mayor", map[string]interface{}{"n": "audit"})
+
+	read := func(source string) []string {
+		var got []string
+		_, err := Stream(context.Background(), townRoot, StreamOptions{History: -1, Source: source}, func(raw string, e Event) error {
+			got = append(got, e.Payload["n"].(string))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Stream(%s): %v", source, err)
+		}
+		return got
+	}
+	if got := read(SourceFeed); len(got) != 1 || got[0] != "feed" {
+		t.Errorf("feed source = %v, want [feed]", got)
+	}
+	if got := read(SourceAudit); len(got) != 1 || got[0] != "audit" {
+		t.Errorf("audit source = %v, want [audit]", got)
+	}
+	if _, err := Stream(context.Background(), townRoot, StreamOptions{Source: "bogus"}, func(string, Event) error { return nil }); err == nil {
+		t.Error("unknown source should be an error")
+	}
+}
+
+func TestStreamMissingLog(t *testing.T) {
+	skipped, err := Stream(context.Background(), t.TempDir(), StreamOptions{History: -1},
+		func(string, Event) error { t.Error("unexpected event"); return nil })
+	if err != nil || skipped != 0 {
+		t.Errorf("Stream on empty town = (%d, %v), want (0, nil)", skipped, err)
+	}
+}
+
+func TestStreamFollowFiltersNewEvents(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now()
+	appendTestEvent(t, townRoot, TypeSling, "gongshow/old", now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	delivered := make(chan Event, 16)
+	done := make(chan int, 1)
+	go func() {
+		skipped, err := Stream(ctx, townRoot, StreamOptions{
+			Filter:       Filter{Types: []string{TypeSling, TypeMail}, Actor: func(a string) bool { return strings.HasPrefix(a, "gongshow/") }},
+			Follow:       true,
+			PollInterval: 5 * time.Millisecond,
+		}, func(raw string, e Event) error {
+			delivered <- e
+			return nil
+		})
+		if err != nil {
+			t.Errorf("Stream: %v", err)
+		}
+		done <- skipped
+	}()
+
+	// Give the stream time to open the log before appending.
+	time.Sleep(50 * time.Millisecond)
+	appendTestEvent(t, townRoot, TypeNudge, "gongshow/Toast", now) // wrong type
+	appendTestEvent(t, townRoot, TypeSling, "mayor", now)          // wrong actor
+	appendRaw(t, townRoot, "not json at all\n")
+	appendTestEvent(t, townRoot, TypeSling, "gongshow/Toast", now)
+
+	// Rotate mid-tail; the stream must pick up the new active file.
+	if err := Rotate(townRoot, RotationPolicy{MaxSize: 1}); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	appendTestEvent(t, townRoot, TypeMail, "gongshow/Nux", now)
+
+	var got []string
+	timeout := time.After(5 * time.Second)
+	for len(got) < 2 {
+		select {
+		case e := <-delivered:
+			got = append(got, e.Type+" "+e.Actor)
+		case <-timeout:
+			t.Fatalf("timed out; delivered %v", got)
+		}
+	}
+	cancel()
+	skipped := <-done
+
+	want := []string{"sling gongshow/Toast", "mail gongshow/Nux"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("delivered %v, want %v", got, want)
+	}
+	select {
+	case e := <-delivered:
+		t.Errorf("unexpected extra event %+v", e)
+	default:
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+}
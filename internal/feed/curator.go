@@ -1,7 +1,7 @@
 // Package feed provides the feed daemon that curates raw events into a user-facing feed.
 //
 // The curator:
-// 1. Tails ~/gt/.events.jsonl (raw events)
+// 1. Tails the town and rig events logs, merged (raw events)
 // 2. Filters by visibility tag (drops audit-only events)
 // 3. Deduplicates repeated updates (5 molecule updates → "agent active")
 // 4. Aggregates related events (3 issues closed → "batch complete")
@@ -9,11 +9,9 @@
 package feed
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,6 +33,8 @@ type FeedEvent struct {
 	Summary   string                 `json:"summary"`
 	Payload   map[string]interface{} `json:"payload,omitempty"`
 	Count     int                    `json:"count,omitempty"` // For aggregated events
+
+	CorrelationID string `json:"correlation_id,omitempty"` // Carried over from the raw event
 }
 
 // Curator manages the feed curation process.
@@ -73,22 +73,14 @@ func NewCurator(townRoot string) *Curator {
 
 // Start begins the curator goroutine.
 func (c *Curator) Start() error {
-	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
-
-	// Open events file, creating if needed
-	file, err := os.OpenFile(eventsPath, os.O_RDONLY|os.O_CREATE, 0644) //nolint:gosec // G302: events file is non-sensitive operational data
+	// Follow the events file from its end, across rotations
+	tailer, err := events.NewTailer(c.townRoot)
 	if err != nil {
-		return fmt.Errorf("opening events file: %w", err)
-	}
-
-	// Seek to end to only process new events
-	if _, err := file.Seek(0, io.SeekEnd); err != nil {
-		_ = file.Close() //nolint:gosec // G104: best effort cleanup on error
-		return fmt.Errorf("seeking to end: %w", err)
+		return err
 	}
 
 	c.wg.Add(1)
-	go c.run(file)
+	go c.run(tailer)
 
 	return nil
 }
@@ -101,11 +93,10 @@ func (c *Curator) Stop() {
 
 // run is the main curator loop.
 // ZFC: No in-memory state to clean up - state is derived from the events file.
-func (c *Curator) run(file *os.File) {
+func (c *Curator) run(tailer *events.Tailer) {
 	defer c.wg.Done()
-	defer file.Close()
+	defer tailer.Close()
 
-	reader := bufio.NewReader(file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -116,19 +107,33 @@ func (c *Curator) run(file *os.File) {
 
 		case <-ticker.C:
 			// Read available lines
-			for {
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					break // No more data available
-				}
-				c.processLine(line)
+			pass := &curationPass{}
+			for _, line := range tailer.Lines() {
+				c.processLine(line, pass)
 			}
 		}
 	}
 }
 
+// curationPass is shared by the lines curated in one tick, so the events
+// logs are read at most once however many slings the tick holds.
+type curationPass struct {
+	recent []events.Event
+	loaded bool
+}
+
+// recentEvents returns the events of the sling aggregation window, reading
+// them on first use in the pass.
+func (c *Curator) recentEvents(pass *curationPass) []events.Event {
+	if !pass.loaded {
+		pass.recent = c.readRecentEvents(slingAggregateWindow)
+		pass.loaded = true
+	}
+	return pass.recent
+}
+
 // processLine processes a single line from the events file.
-func (c *Curator) processLine(line string) {
+func (c *Curator) processLine(line string, pass *curationPass) {
 	if line == "" || line == "\n" {
 		return
 	}
@@ -149,7 +154,7 @@ func (c *Curator) processLine(line string) {
 	}
 
 	// Write to feed
-	c.writeFeedEvent(&rawEvent)
+	c.writeFeedEvent(&rawEvent, pass)
 }
 
 // shouldDedupe checks if an event should be deduplicated.
@@ -217,58 +222,60 @@ func (c *Curator) readRecentFeedEvents(window time.Duration) []FeedEvent {
 	return result
 }
 
-// readRecentEvents reads events from the events file within the given time window.
+// readRecentEvents reads events within the given time window from the
+// active events files of the town and every rig. Rotated archives are older
+// than any window the curator uses, so they aren't opened, and each file is
+// read from its end back to the window's start.
 // ZFC: This is the observable state that replaces in-memory caching.
-// Uses tail-like reading for performance (reads last N lines).
 func (c *Curator) readRecentEvents(window time.Duration) []events.Event {
-	eventsPath := filepath.Join(c.townRoot, events.EventsFile)
-
-	// Read the file (for small files, this is fine; for large files, consider tail-like reading)
-	data, err := os.ReadFile(eventsPath)
+	dirs, err := events.LogDirs(c.townRoot)
 	if err != nil {
 		return nil
 	}
 
-	now := time.Now()
-	cutoff := now.Add(-window)
+	cutoff := time.Now().Add(-window)
+	seen := make(map[string]bool)
 	var result []events.Event
-
-	// Parse lines from the end (most recent first) for efficiency
-	lines := strings.Split(string(data), "\n")
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		var event events.Event
-		if err := json.Unmarshal([]byte(line), &event); err != nil {
-			continue
-		}
-
-		// Parse timestamp
-		ts, err := time.Parse(time.RFC3339, event.Timestamp)
+	for _, dir := range dirs {
+		data, err := os.ReadFile(filepath.Join(dir, events.EventsFile)) //nolint:gosec // G304: path is constructed internally
 		if err != nil {
 			continue
 		}
-
-		// Stop if we've gone past the window
-		if ts.Before(cutoff) {
-			break
+		lines := strings.Split(string(data), "\n")
+		for i := len(lines) - 1; i >= 0; i-- {
+			line := strings.TrimSpace(lines[i])
+			if line == "" {
+				continue
+			}
+			var e events.Event
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				continue
+			}
+			ts, err := time.Parse(time.RFC3339, e.Timestamp)
+			if err != nil {
+				continue
+			}
+			if ts.Before(cutoff) {
+				break
+			}
+			// The same event can be in more than one log file
+			if e.ID != "" {
+				if seen[e.ID] {
+					continue
+				}
+				seen[e.ID] = true
+			}
+			result = append(result, e)
 		}
-
-		result = append(result, event)
 	}
-
 	return result
 }
 
-// countRecentSlings counts sling events from an actor within the given window.
-// ZFC: Derives count from the events file, not in-memory cache.
-func (c *Curator) countRecentSlings(actor string, window time.Duration) int {
-	recentEvents := c.readRecentEvents(window)
+// countRecentSlings counts sling events from an actor among recent.
+// ZFC: Derives count from the events logs, not in-memory cache.
+func (c *Curator) countRecentSlings(actor string, recent []events.Event) int {
 	count := 0
-	for _, e := range recentEvents {
+	for _, e := range recent {
 		if e.Type == events.TypeSling && e.Actor == actor {
 			count++
 		}
@@ -278,7 +285,7 @@ func (c *Curator) countRecentSlings(actor string, window time.Duration) int {
 
 // writeFeedEvent writes a curated event to the feed file.
 // ZFC: Aggregation is derived from the events file, not in-memory cache.
-func (c *Curator) writeFeedEvent(event *events.Event) {
+func (c *Curator) writeFeedEvent(event *events.Event, pass *curationPass) {
 	feedEvent := FeedEvent{
 		Timestamp: event.Timestamp,
 		Source:    event.Source,
@@ -286,11 +293,13 @@ func (c *Curator) writeFeedEvent(event *events.Event) {
 		Actor:     event.Actor,
 		Summary:   c.generateSummary(event),
 		Payload:   event.Payload,
+
+		CorrelationID: event.CorrelationID,
 	}
 
 	// Check for aggregation opportunity (ZFC: derive from events file)
 	if event.Type == events.TypeSling {
-		slingCount := c.countRecentSlings(event.Actor, slingAggregateWindow)
+		slingCount := c.countRecentSlings(event.Actor, c.recentEvents(pass))
 		if slingCount >= minAggregateCount {
 			feedEvent.Count = slingCount
 			feedEvent.Summary = fmt.Sprintf("%s dispatching work to %d agents", event.Actor, slingCount)
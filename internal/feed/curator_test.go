@@ -194,3 +194,42 @@ func TestCurator_GeneratesSummary(t *testing.T) {
 		}
 	}
 }
+
+func TestCurator_CountsSlingsAcrossRigLogs(t *testing.T) {
+	townRoot := t.TempDir()
+	now := time.Now().UTC()
+	write := func(dir string, e events.Event) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(e)
+		f, err := os.OpenFile(filepath.Join(dir, events.EventsFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sling := func(ago time.Duration, id string) events.Event {
+		return events.Event{
+			Timestamp:  now.Add(-ago).Format(time.RFC3339),
+			Source:     "gt",
+			Type:       events.TypeSling,
+			Actor:      "gongshow/crew/max",
+			Visibility: events.VisibilityFeed,
+			ID:         id,
+		}
+	}
+	write(townRoot, sling(5*time.Second, "ev1"))
+	write(filepath.Join(townRoot, "gongshow"), sling(2*time.Hour, "ev2")) // outside the window
+	write(filepath.Join(townRoot, "gongshow"), sling(3*time.Second, "ev3"))
+	write(townRoot, sling(3*time.Second, "ev3")) // the same event in both logs
+
+	c := NewCurator(townRoot)
+	if n := c.countRecentSlings("gongshow/crew/max", c.readRecentEvents(time.Minute)); n != 2 {
+		t.Errorf("countRecentSlings = %d, want 2 (town and rig log)", n)
+	}
+}
@@ -39,7 +39,8 @@ func (e *bdError) ContainsError(substr string) bool {
 // beadsDir is the BEADS_DIR environment variable value.
 // extraEnv contains additional environment variables to set (e.g., "BD_IDENTITY=...").
 // Returns stdout bytes on success, or a *bdError on failure.
-func runBdCommand(args []string, workDir, beadsDir string, extraEnv ...string) ([]byte, error) {
+// A variable so tests can stub out bd.
+var runBdCommand = func(args []string, workDir, beadsDir string, extraEnv ...string) ([]byte, error) {
 	cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
 	cmd.Dir = workDir
 
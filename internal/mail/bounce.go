@@ -0,0 +1,71 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// BounceSender is the From address on bounce messages.
+const BounceSender = "mailer-daemon"
+
+// BounceSubjectPrefix is prepended to the original subject of a bounce.
+const BounceSubjectPrefix = "BOUNCE: "
+
+// ErrNoBounceAddress is returned when a message can't be bounced because it
+// has no sender, or is itself a bounce (bouncing it could loop forever).
+var ErrNoBounceAddress = errors.New("message has no bounce address")
+
+// Bounce returns an undeliverable message to its sender. The bounce is
+// addressed to original.From, threaded with the original, and carries the
+// failure reason followed by the original message. The bounce and its event
+// keep the original's correlation ID.
+func (r *Router) Bounce(original *Message, reason string) error {
+	if original.From == "" || original.From == BounceSender {
+		return ErrNoBounceAddress
+	}
+
+	bounce := NewReplyMessage(BounceSender, original.From,
+		BounceSubjectPrefix+original.Subject, bounceBody(original, reason), original)
+	bounce.Priority = PriorityHigh
+	bounce.CorrelationID = original.CorrelationID
+
+	if err := r.sendToSingle(bounce); err != nil {
+		return fmt.Errorf("bouncing to %s: %w", original.From, err)
+	}
+
+	ctx := events.WithCorrelation(context.Background(), original.CorrelationID)
+	_ = events.LogFeedContext(ctx, events.TypeMailBounce, BounceSender,
+		events.MailBouncePayload(original.From, original.To, original.Subject, reason))
+	return nil
+}
+
+// SendOrBounce sends msg and, if delivery fails, bounces it to the sender
+// instead of returning the delivery error. bounced reports whether a bounce
+// was sent; err is non-nil only if the bounce itself failed.
+func (r *Router) SendOrBounce(msg *Message) (bounced bool, err error) {
+	sendErr := r.Send(msg)
+	if sendErr == nil {
+		return false, nil
+	}
+	if err := r.Bounce(msg, sendErr.Error()); err != nil {
+		return false, fmt.Errorf("%v (bounce failed: %w)", sendErr, err)
+	}
+	return true, nil
+}
+
+// bounceBody formats the reason and the original message for a bounce.
+func bounceBody(original *Message, reason string) string {
+	var sb strings.Builder
+	sb.WriteString("Your message could not be delivered.\n\n")
+	fmt.Fprintf(&sb, "Reason: %s\n\n", reason)
+	sb.WriteString("----- Original message -----\n")
+	fmt.Fprintf(&sb, "From: %s\n", original.From)
+	fmt.Fprintf(&sb, "To: %s\n", original.To)
+	fmt.Fprintf(&sb, "Subject: %s\n\n", original.Subject)
+	sb.WriteString(original.Body)
+	return sb.String()
+}
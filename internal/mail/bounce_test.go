@@ -0,0 +1,120 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// stubBdCreate replaces runBdCommand, failing creates assigned to missing and
+// recording the args of every other create.
+func stubBdCreate(t *testing.T, missing string) *[][]string {
+	t.Helper()
+	var created [][]string
+	orig := runBdCommand
+	runBdCommand = func(args []string, workDir, beadsDir string, extraEnv ...string) ([]byte, error) {
+		if len(args) > 0 && args[0] == "create" {
+			if flagValue(args, "--assignee") == missing {
+				return nil, &bdError{Err: errors.New("exit status 1"), Stderr: "no such agent: " + missing}
+			}
+			created = append(created, args)
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { runBdCommand = orig })
+	return &created
+}
+
+func flagValue(args []string, flag string) string {
+	for i := 0; i < len(args)-1; i++ {
+		if args[i] == flag {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+func TestSendOrBounceUnknownAddress(t *testing.T) {
+	created := stubBdCreate(t, "gongshow/ghost")
+	r := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+	t.Chdir(t.TempDir()) // keep the bounce event out of the source tree
+
+	msg := NewMessage("gongshow/Toast", "gongshow/ghost", "Build results", "All green.")
+	bounced, err := r.SendOrBounce(msg)
+	if err != nil {
+		t.Fatalf("SendOrBounce: %v", err)
+	}
+	if !bounced {
+		t.Fatal("expected message to bounce")
+	}
+
+	if len(*created) != 1 {
+		t.Fatalf("created %d messages, want 1 bounce", len(*created))
+	}
+	args := (*created)[0]
+	if got := args[1]; got != "BOUNCE: Build results" {
+		t.Errorf("subject = %q, want %q", got, "BOUNCE: Build results")
+	}
+	if got := flagValue(args, "--assignee"); got != "gongshow/Toast" {
+		t.Errorf("bounce delivered to %q, want sender gongshow/Toast", got)
+	}
+	if got := flagValue(args, "--actor"); got != BounceSender {
+		t.Errorf("actor = %q, want %q", got, BounceSender)
+	}
+	body := flagValue(args, "-d")
+	for _, want := range []string{"no such agent: gongshow/ghost", "To: gongshow/ghost", "All green."} {
+		if !strings.Contains(body, want) {
+			t.Errorf("bounce body missing %q:\n%s", want, body)
+		}
+	}
+	if labels := flagValue(args, "--labels"); !strings.Contains(labels, "reply-to:"+msg.ID) {
+		t.Errorf("labels = %q, want reply-to original", labels)
+	}
+}
+
+func TestBounceRefusesLoops(t *testing.T) {
+	created := stubBdCreate(t, "")
+	r := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+
+	for _, from := range []string{"", BounceSender} {
+		msg := NewMessage(from, "gongshow/ghost", "x", "y")
+		if err := r.Bounce(msg, "undeliverable"); !errors.Is(err, ErrNoBounceAddress) {
+			t.Errorf("Bounce from %q: got %v, want ErrNoBounceAddress", from, err)
+		}
+	}
+	if len(*created) != 0 {
+		t.Errorf("created %d bounces, want none", len(*created))
+	}
+}
+
+func TestSendContextCarriesCorrelation(t *testing.T) {
+	created := stubBdCreate(t, "gongshow/ghost")
+	r := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+	t.Chdir(t.TempDir())
+
+	ctx := events.WithCorrelation(context.Background(), "3f9a0c1d2e4b5a67")
+	sent := NewMessage("mayor/", "gongshow/Toast", "Work assigned", "gt-abc")
+	if err := r.SendContext(ctx, sent); err != nil {
+		t.Fatalf("SendContext: %v", err)
+	}
+	// The bounce of an undeliverable message stays in the sender's chain.
+	lost := NewMessage("mayor/", "gongshow/ghost", "Work assigned", "gt-abc")
+	if err := r.SendContext(ctx, lost); err == nil {
+		t.Fatal("expected delivery to gongshow/ghost to fail")
+	}
+	if err := r.Bounce(lost, "undeliverable"); err != nil {
+		t.Fatalf("Bounce: %v", err)
+	}
+
+	if len(*created) != 2 {
+		t.Fatalf("created %d messages, want the message and a bounce", len(*created))
+	}
+	for _, args := range *created {
+		if labels := flagValue(args, "--labels"); !strings.Contains(labels, "correlation:3f9a0c1d2e4b5a67") {
+			t.Errorf("%s: labels = %q, want the sender's correlation", args[1], labels)
+		}
+	}
+}
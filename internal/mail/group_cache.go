@@ -0,0 +1,118 @@
+package mail
+
+import (
+	"path"
+	"sync"
+	"time"
+)
+
+// DefaultGroupCacheTTL is how long a resolved @group expansion stays valid.
+// Short enough that new agents show up promptly, long enough to absorb a
+// broadcast loop that resolves the same group many times.
+const DefaultGroupCacheTTL = 5 * time.Second
+
+// cacheEntry is a resolved group expansion and when it stops being valid.
+type cacheEntry struct {
+	addresses []string
+	expires   time.Time
+}
+
+// groupCacheKey identifies a group expansion: the same group address names
+// different agents in different towns.
+type groupCacheKey struct {
+	townRoot string
+	address  string
+}
+
+// GroupCache caches @group address expansions so repeated sends to the same
+// group don't re-query agent beads every time. Entries are kept per town.
+type GroupCache struct {
+	mu      sync.RWMutex
+	entries map[groupCacheKey]cacheEntry
+	ttl     time.Duration
+	now     func() time.Time // injectable clock for tests
+}
+
+// NewGroupCache creates a cache whose entries expire after ttl.
+// A ttl <= 0 uses DefaultGroupCacheTTL.
+func NewGroupCache(ttl time.Duration) *GroupCache {
+	if ttl <= 0 {
+		ttl = DefaultGroupCacheTTL
+	}
+	return &GroupCache{
+		entries: make(map[groupCacheKey]cacheEntry),
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// sharedGroupCache is used by all routers in the process.
+var sharedGroupCache = NewGroupCache(DefaultGroupCacheTTL)
+
+// SharedGroupCache returns the process-wide group cache used by routers.
+func SharedGroupCache() *GroupCache {
+	return sharedGroupCache
+}
+
+// Get returns the cached expansion for a group address in the town at
+// townRoot, if present and fresh. The returned slice is a copy and may be
+// modified by the caller.
+func (c *GroupCache) Get(townRoot, address string) ([]string, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[groupCacheKey{townRoot, address}]
+	c.mu.RUnlock()
+	if !ok || !c.now().Before(entry.expires) {
+		return nil, false
+	}
+	return append([]string(nil), entry.addresses...), true
+}
+
+// Set stores the expansion for a group address in the town at townRoot.
+func (c *GroupCache) Set(townRoot, address string, addresses []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[groupCacheKey{townRoot, address}] = cacheEntry{
+		addresses: append([]string(nil), addresses...),
+		expires:   c.now().Add(c.ttl),
+	}
+}
+
+// Resolve returns the cached expansion for address in the town at
+// townRoot, calling resolve and caching its result on a miss or expiry.
+// Errors are not cached.
+func (c *GroupCache) Resolve(townRoot, address string, resolve func() ([]string, error)) ([]string, error) {
+	if addrs, ok := c.Get(townRoot, address); ok {
+		return addrs, nil
+	}
+	addrs, err := resolve()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(townRoot, address, addrs)
+	return addrs, nil
+}
+
+// Invalidate drops the town's cached entries whose group address matches
+// pattern. Pattern uses path.Match syntax, so "@*/gongshow" matches
+// @rig/gongshow, @crew/gongshow and @polecats/gongshow. An empty pattern
+// clears the whole town, and an empty townRoot matches every town.
+// Returns the number of entries removed.
+func (c *GroupCache) Invalidate(townRoot, pattern string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key := range c.entries {
+		if townRoot != "" && key.townRoot != townRoot {
+			continue
+		}
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, key.address); !ok {
+				continue
+			}
+		}
+		delete(c.entries, key)
+		removed++
+	}
+	return removed
+}
@@ -0,0 +1,152 @@
+package mail
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestGroupCacheResolve(t *testing.T) {
+	cache := NewGroupCache(time.Minute)
+	calls := 0
+	resolve := func() ([]string, error) {
+		calls++
+		return []string{"gongshow/witness", "gongshow/Toast"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		addrs, err := cache.Resolve("/town", "@rig/gongshow", resolve)
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if len(addrs) != 2 {
+			t.Fatalf("expected 2 addresses, got %v", addrs)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("resolver called %d times, want 1", calls)
+	}
+}
+
+func TestGroupCacheExpiry(t *testing.T) {
+	cache := NewGroupCache(5 * time.Second)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.Set("/town", "@town", []string{"mayor/"})
+	if _, ok := cache.Get("/town", "@town"); !ok {
+		t.Fatal("expected fresh entry")
+	}
+
+	now = now.Add(5 * time.Second)
+	if _, ok := cache.Get("/town", "@town"); ok {
+		t.Error("expected entry to expire after TTL")
+	}
+}
+
+func TestGroupCacheErrorsNotCached(t *testing.T) {
+	cache := NewGroupCache(time.Minute)
+	calls := 0
+	failing := func() ([]string, error) {
+		calls++
+		return nil, errors.New("bd unavailable")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Resolve("/town", "@dogs", failing); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("resolver called %d times, want 2", calls)
+	}
+}
+
+func TestGroupCacheReturnsCopy(t *testing.T) {
+	cache := NewGroupCache(time.Minute)
+	cache.Set("/town", "@town", []string{"mayor/", "deacon/"})
+
+	addrs, _ := cache.Get("/town", "@town")
+	addrs[0] = "mutated"
+
+	again, _ := cache.Get("/town", "@town")
+	if again[0] != "mayor/" {
+		t.Errorf("cache entry was mutated through returned slice: %v", again)
+	}
+}
+
+func TestGroupCacheInvalidate(t *testing.T) {
+	cache := NewGroupCache(time.Minute)
+	for _, addr := range []string{"@rig/gongshow", "@crew/gongshow", "@polecats/gongshow", "@rig/beads", "@town"} {
+		cache.Set("/town", addr, []string{"x"})
+	}
+
+	if n := cache.Invalidate("/town", "@*/gongshow"); n != 3 {
+		t.Errorf("Invalidate(@*/gongshow) removed %d, want 3", n)
+	}
+	if _, ok := cache.Get("/town", "@rig/beads"); !ok {
+		t.Error("@rig/beads should survive rig-scoped invalidation")
+	}
+	if _, ok := cache.Get("/town", "@crew/gongshow"); ok {
+		t.Error("@crew/gongshow should be invalidated")
+	}
+
+	if n := cache.Invalidate("/town", ""); n != 2 {
+		t.Errorf("Invalidate(\"\") removed %d, want 2", n)
+	}
+}
+
+func TestGroupCacheKeyedByTown(t *testing.T) {
+	cache := NewGroupCache(time.Minute)
+	cache.Set("/town-a", "@town", []string{"mayor/"})
+
+	if _, ok := cache.Get("/town-b", "@town"); ok {
+		t.Error("another town's @town expansion was served from the cache")
+	}
+	cache.Set("/town-b", "@town", []string{"mayor/", "deacon/"})
+	if addrs, _ := cache.Get("/town-a", "@town"); len(addrs) != 1 {
+		t.Errorf("/town-a @town = %v, want its own expansion", addrs)
+	}
+
+	if n := cache.Invalidate("/town-a", ""); n != 1 {
+		t.Errorf("Invalidate(/town-a) removed %d, want 1", n)
+	}
+	if _, ok := cache.Get("/town-b", "@town"); !ok {
+		t.Error("invalidating /town-a dropped /town-b's entry")
+	}
+	if n := cache.Invalidate("", "@town"); n != 1 {
+		t.Errorf("Invalidate across towns removed %d, want 1", n)
+	}
+}
+
+// BenchmarkGroupCache compares a cache hit against a miss that pays for a
+// subprocess, which is what a live bd lookup costs.
+func BenchmarkGroupCache(b *testing.B) {
+	if _, err := exec.LookPath("true"); err != nil {
+		b.Skip("true not available")
+	}
+	resolve := func() ([]string, error) {
+		if err := exec.Command("true").Run(); err != nil {
+			return nil, err
+		}
+		return []string{"gongshow/witness", "gongshow/refinery", "gongshow/Toast"}, nil
+	}
+
+	b.Run("hit", func(b *testing.B) {
+		cache := NewGroupCache(time.Hour)
+		_, _ = cache.Resolve("/town", "@rig/gongshow", resolve)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, _ = cache.Resolve("/town", "@rig/gongshow", resolve)
+		}
+	})
+
+	b.Run("miss", func(b *testing.B) {
+		cache := NewGroupCache(time.Hour)
+		for i := 0; i < b.N; i++ {
+			cache.Invalidate("/town", "")
+			_, _ = cache.Resolve("/town", "@rig/gongshow", resolve)
+		}
+	})
+}
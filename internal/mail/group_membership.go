@@ -0,0 +1,175 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofrs/flock"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// ErrUnknownGroup indicates a @group address is neither a dynamic group nor
+// has static members.
+var ErrUnknownGroup = errors.New("unknown group")
+
+// groupsDir holds static group memberships, relative to the beads directory.
+const groupsDir = "groups"
+
+// groupsLockFile serializes membership updates within groupsDir.
+const groupsLockFile = ".groups.lock"
+
+// GroupMembership is the static member list of a @group, persisted in
+// .beads/groups/<groupName>.json. Static members are added to whatever the
+// group resolves to dynamically, so an address can stay subscribed to
+// @rig/gongshow whether or not it has a session in the rig.
+type GroupMembership struct {
+	Group   string   `json:"group"`   // group name without the @ (e.g., rig/gongshow)
+	Members []string `json:"members"` // subscribed addresses, in subscription order
+}
+
+// normalizeGroupName strips the @ from a group name and rejects names that
+// would escape the groups directory.
+func normalizeGroupName(groupName string) (string, error) {
+	name := strings.TrimPrefix(groupName, "@")
+	if name == "" {
+		return "", fmt.Errorf("empty group name")
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("invalid group name %q", groupName)
+		}
+	}
+	return name, nil
+}
+
+// groupMembershipPath returns the file holding a group's static members.
+func (r *Router) groupMembershipPath(name string) string {
+	return filepath.Join(r.resolveBeadsDir(""), groupsDir, filepath.FromSlash(name)+".json")
+}
+
+// loadGroupMembership reads a group's static members. A group without a
+// membership file has none; the bool reports whether the file exists.
+func (r *Router) loadGroupMembership(name string) (*GroupMembership, bool, error) {
+	data, err := os.ReadFile(r.groupMembershipPath(name))
+	if os.IsNotExist(err) {
+		return &GroupMembership{Group: name}, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading group membership: %w", err)
+	}
+	var m GroupMembership
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, false, fmt.Errorf("parsing group membership for @%s: %w", name, err)
+	}
+	m.Group = name
+	return &m, true, nil
+}
+
+// updateGroupMembership applies fn to a group's static members under the
+// groups lock and saves the result.
+func (r *Router) updateGroupMembership(groupName string, fn func(m *GroupMembership) error) error {
+	name, err := normalizeGroupName(groupName)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(r.resolveBeadsDir(""), groupsDir)
+	if err := os.MkdirAll(filepath.Dir(r.groupMembershipPath(name)), 0755); err != nil {
+		return fmt.Errorf("creating groups directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(dir, groupsLockFile))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking groups: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	m, _, err := r.loadGroupMembership(name)
+	if err != nil {
+		return err
+	}
+	if err := fn(m); err != nil {
+		return err
+	}
+	if err := util.AtomicWriteJSON(r.groupMembershipPath(name), m); err != nil {
+		return fmt.Errorf("saving group membership: %w", err)
+	}
+	return nil
+}
+
+// SubscribeGroup adds address to the static members of a group. The group
+// name may be given with or without the @. Subscribing twice is a no-op.
+func (r *Router) SubscribeGroup(groupName, address string) error {
+	if address == "" {
+		return fmt.Errorf("empty address")
+	}
+	return r.updateGroupMembership(groupName, func(m *GroupMembership) error {
+		for _, member := range m.Members {
+			if addressToIdentity(member) == addressToIdentity(address) {
+				return nil
+			}
+		}
+		m.Members = append(m.Members, address)
+		return nil
+	})
+}
+
+// UnsubscribeGroup removes address from the static members of a group.
+// It only affects static membership: an address the group resolves to
+// dynamically still receives its mail.
+func (r *Router) UnsubscribeGroup(groupName, address string) error {
+	return r.updateGroupMembership(groupName, func(m *GroupMembership) error {
+		kept := m.Members[:0]
+		found := false
+		for _, member := range m.Members {
+			if addressToIdentity(member) == addressToIdentity(address) {
+				found = true
+				continue
+			}
+			kept = append(kept, member)
+		}
+		if !found {
+			return fmt.Errorf("%s is not subscribed to @%s", address, m.Group)
+		}
+		m.Members = kept
+		return nil
+	})
+}
+
+// ExpandGroup returns the members of a @group: the addresses it resolves to
+// dynamically (for a known group pattern such as @rig/<name>) followed by
+// its static members, without duplicates. A name that is neither a known
+// pattern nor has a membership file is ErrUnknownGroup.
+func (r *Router) ExpandGroup(groupName string) ([]string, error) {
+	name, err := normalizeGroupName(groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var dynamic []string
+	parsed := parseGroupAddress("@" + name)
+	if parsed != nil {
+		if dynamic, err = r.resolveGroup(parsed); err != nil {
+			return nil, err
+		}
+	}
+	static, exists, err := r.loadGroupMembership(name)
+	if err != nil {
+		return nil, err
+	}
+	if parsed == nil && !exists {
+		return nil, fmt.Errorf("%w: @%s", ErrUnknownGroup, name)
+	}
+
+	seen := make(map[string]bool)
+	var members []string
+	for _, addr := range append(dynamic, static.Members...) {
+		if id := addressToIdentity(addr); !seen[id] {
+			seen[id] = true
+			members = append(members, addr)
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newGroupTestRouter returns a router for a temp town whose bd lists agents
+// (as JSON) and records the assignee of every create.
+func newGroupTestRouter(t *testing.T, agents string) (*Router, *[]string) {
+	t.Helper()
+	townRoot := t.TempDir()
+	var sentTo []string
+	orig := runBdCommand
+	runBdCommand = func(args []string, workDir, beadsDir string, extraEnv ...string) ([]byte, error) {
+		switch args[0] {
+		case "list":
+			return []byte(agents), nil
+		case "create":
+			sentTo = append(sentTo, flagValue(args, "--assignee"))
+		}
+		return nil, nil
+	}
+	t.Cleanup(func() { runBdCommand = orig })
+
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+	r.groupCache = nil
+	return r, &sentTo
+}
+
+const gongshowAgents = `[
+	{"id": "gt-gongshow-witness", "status": "open"},
+	{"id": "gt-gongshow-polecat-Toast", "status": "open"}
+]`
+
+func TestSubscribeGroupPersists(t *testing.T) {
+	r, _ := newGroupTestRouter(t, "[]")
+
+	if err := r.SubscribeGroup("@rig/gongshow", "mayor/"); err != nil {
+		t.Fatalf("SubscribeGroup: %v", err)
+	}
+	if err := r.SubscribeGroup("rig/gongshow", "overseer"); err != nil {
+		t.Fatalf("SubscribeGroup: %v", err)
+	}
+	// Same identity, different spelling: not added twice.
+	if err := r.SubscribeGroup("rig/gongshow", "mayor"); err != nil {
+		t.Fatalf("SubscribeGroup: %v", err)
+	}
+
+	path := filepath.Join(r.townRoot, ".beads", "groups", "rig", "gongshow.json")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("membership file not written: %v", err)
+	}
+	m, exists, err := r.loadGroupMembership("rig/gongshow")
+	if err != nil || !exists {
+		t.Fatalf("loadGroupMembership = %v, %v", exists, err)
+	}
+	if want := []string{"mayor/", "overseer"}; !reflect.DeepEqual(m.Members, want) {
+		t.Errorf("members = %v, want %v", m.Members, want)
+	}
+
+	if err := r.UnsubscribeGroup("@rig/gongshow", "mayor"); err != nil {
+		t.Fatalf("UnsubscribeGroup: %v", err)
+	}
+	if err := r.UnsubscribeGroup("@rig/gongshow", "mayor"); err == nil {
+		t.Error("expected error unsubscribing a non-member")
+	}
+	m, _, _ = r.loadGroupMembership("rig/gongshow")
+	if want := []string{"overseer"}; !reflect.DeepEqual(m.Members, want) {
+		t.Errorf("members after unsubscribe = %v, want %v", m.Members, want)
+	}
+}
+
+func TestExpandGroupUnionsStaticAndDynamic(t *testing.T) {
+	r, _ := newGroupTestRouter(t, gongshowAgents)
+
+	// gongshow/witness is also a dynamic member: listed once.
+	for _, addr := range []string{"mayor/", "gongshow/witness"} {
+		if err := r.SubscribeGroup("@rig/gongshow", addr); err != nil {
+			t.Fatalf("SubscribeGroup: %v", err)
+		}
+	}
+	got, err := r.ExpandGroup("@rig/gongshow")
+	if err != nil {
+		t.Fatalf("ExpandGroup: %v", err)
+	}
+	if want := []string{"gongshow/witness", "gongshow/Toast", "mayor/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandGroup = %v, want %v", got, want)
+	}
+}
+
+func TestExpandGroupStaticOnly(t *testing.T) {
+	r, _ := newGroupTestRouter(t, "[]")
+
+	if _, err := r.ExpandGroup("@oncall"); !errors.Is(err, ErrUnknownGroup) {
+		t.Errorf("ExpandGroup(@oncall) = %v, want ErrUnknownGroup", err)
+	}
+	if err := r.SubscribeGroup("oncall", "gongshow/crew/max"); err != nil {
+		t.Fatalf("SubscribeGroup: %v", err)
+	}
+	got, err := r.ExpandGroup("@oncall")
+	if err != nil {
+		t.Fatalf("ExpandGroup: %v", err)
+	}
+	if want := []string{"gongshow/crew/max"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandGroup = %v, want %v", got, want)
+	}
+}
+
+func TestSendToGroupReachesStaticAndDynamicMembers(t *testing.T) {
+	r, sentTo := newGroupTestRouter(t, gongshowAgents)
+
+	if err := r.SubscribeGroup("@rig/gongshow", "mayor/"); err != nil {
+		t.Fatalf("SubscribeGroup: %v", err)
+	}
+	if err := r.Send(NewMessage("deacon/", "@rig/gongshow", "heads up", "rig maintenance at noon")); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if want := []string{"gongshow/witness", "gongshow/Toast", "mayor/"}; !reflect.DeepEqual(*sentTo, want) {
+		t.Errorf("delivered to %v, want %v", *sentTo, want)
+	}
+}
+
+func TestGroupNameValidation(t *testing.T) {
+	r, _ := newGroupTestRouter(t, "[]")
+	for _, name := range []string{"", "@", "../escape", "rig//gongshow", "rig/.."} {
+		if err := r.SubscribeGroup(name, "mayor/"); err == nil {
+			t.Errorf("SubscribeGroup(%q) succeeded, want error", name)
+		}
+	}
+}
@@ -31,6 +31,8 @@ type Mailbox struct {
 	beadsDir string // explicit .beads directory path (set via BEADS_DIR)
 	path     string // for legacy JSONL mode (crew workers)
 	legacy   bool   // true = use JSONL files, false = use beads
+
+	suppressWispArchive bool // archive wisps by deleting them, without writing the archive
 }
 
 // NewMailbox creates a mailbox for the given JSONL path (legacy mode).
@@ -501,6 +503,8 @@ func (m *Mailbox) deleteLegacy(id string) error {
 }
 
 // Archive moves a message to the archive file and removes it from inbox.
+// Protocol wisps (NUDGE etc.) from a mailbox whose router suppresses them
+// are only removed.
 func (m *Mailbox) Archive(id string) error {
 	// Get the message first
 	msg, err := m.Get(id)
@@ -508,6 +512,10 @@ func (m *Mailbox) Archive(id string) error {
 		return fmt.Errorf("getting message %s: %w", id, err)
 	}
 
+	if m.suppressWispArchive && isLifecycleMessage(msg) {
+		return m.Delete(id)
+	}
+
 	// Append to archive file
 	if err := m.appendToArchive(msg); err != nil {
 		return fmt.Errorf("appending message %s to archive: %w", id, err)
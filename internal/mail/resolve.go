@@ -345,6 +345,12 @@ func agentBeadIDToAddress(id string) string {
 	}
 }
 
+// MatchPattern reports whether address matches a wildcard pattern such as
+// "gongshow/*" or "*/witness". '*' matches any single path segment.
+func MatchPattern(pattern, address string) bool {
+	return matchPattern(pattern, address)
+}
+
 // matchPattern checks if an address matches a wildcard pattern.
 // '*' matches any single path segment (no slashes).
 func matchPattern(pattern, address string) bool {
@@ -1,15 +1,19 @@
 package mail
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/KeithWyatt/gongshow/internal/beads"
 	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/events"
 	"github.com/KeithWyatt/gongshow/internal/session"
 	"github.com/KeithWyatt/gongshow/internal/tmux"
 )
@@ -23,6 +27,11 @@ var ErrUnknownQueue = errors.New("unknown queue")
 // ErrUnknownAnnounce indicates an announce channel name was not found in configuration.
 var ErrUnknownAnnounce = errors.New("unknown announce channel")
 
+// ErrInvalidName indicates a list, queue or announce channel name that is
+// not safe to use, such as one that could escape a directory if joined to
+// a path.
+var ErrInvalidName = errors.New("invalid name")
+
 // Router handles message delivery via beads.
 // It routes messages to the correct beads database based on address:
 // - Town-level (mayor/, deacon/) -> {townRoot}/.beads
@@ -31,8 +40,25 @@ type Router struct {
 	workDir  string // fallback directory to run bd commands in
 	townRoot string // town root directory (e.g., ~/gt)
 	tmux     *tmux.Tmux
+
+	groupCache *GroupCache // caches @group expansions across sends
+	workerPool *WorkerPool // round-robin state for queue workers
+
+	suppressWispLog bool // keep wisps out of the mail archive and events log
+
+	handlersMu       sync.Mutex
+	deliveryHandlers []DeliveryHandler // see OnDelivery
+	failureHandlers  []DeliveryHandler // see OnFailure
 }
 
+// DeliveryHandler is called after a Send with the message and, for a
+// failed send, the error.
+type DeliveryHandler func(msg *Message, err error)
+
+// EnvSuppressWispLog, when true, makes new routers suppress wisp logging
+// (see WithWispFilter).
+const EnvSuppressWispLog = "GT_SUPPRESS_WISP_LOG"
+
 // NewRouter creates a new mail router.
 // workDir should be a directory containing a .beads database.
 // The town root is auto-detected from workDir if possible.
@@ -41,27 +67,100 @@ func NewRouter(workDir string) *Router {
 	townRoot := detectTownRoot(workDir)
 
 	return &Router{
-		workDir:  workDir,
-		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		workDir:         workDir,
+		townRoot:        townRoot,
+		tmux:            tmux.NewTmux(),
+		groupCache:      sharedGroupCache,
+		workerPool:      NewWorkerPool(townRoot),
+		suppressWispLog: wispLogSuppressedByEnv(),
 	}
 }
 
 // NewRouterWithTownRoot creates a router with an explicit town root.
 func NewRouterWithTownRoot(workDir, townRoot string) *Router {
 	return &Router{
-		workDir:  workDir,
-		townRoot: townRoot,
-		tmux:     tmux.NewTmux(),
+		workDir:         workDir,
+		townRoot:        townRoot,
+		tmux:            tmux.NewTmux(),
+		groupCache:      sharedGroupCache,
+		workerPool:      NewWorkerPool(townRoot),
+		suppressWispLog: wispLogSuppressedByEnv(),
 	}
 }
 
+// WithWispFilter sets whether wisps (NUDGE, POLECAT_STARTED and other
+// internal protocol messages) are kept out of the mail archive and the
+// events log. They are still delivered. Returns r for chaining.
+func (r *Router) WithWispFilter(suppress bool) *Router {
+	r.suppressWispLog = suppress
+	return r
+}
+
+// FilterWisp reports whether msg should be left out of the mail archive
+// and events log: the router suppresses wisps and msg is an internal
+// protocol message. Messages only flagged Wisp for ephemeral storage (the
+// default for 'gt mail send') are still logged.
+func (r *Router) FilterWisp(msg *Message) bool {
+	return r.suppressWispLog && isLifecycleMessage(msg)
+}
+
+// OnDelivery registers handler to be called after each message is
+// delivered, with a nil error. Handlers run in registration order, in the
+// goroutine that called Send, before Send returns, so they must not block
+// for long. A handler that panics is recovered and skipped.
+//
+// A Send to a list, group, queue or announce address counts as one
+// delivery, however many copies it makes.
+func (r *Router) OnDelivery(handler func(msg *Message, err error)) {
+	r.handlersMu.Lock()
+	defer r.handlersMu.Unlock()
+	r.deliveryHandlers = append(r.deliveryHandlers, handler)
+}
+
+// OnFailure is OnDelivery for sends that fail: handler is called with the
+// message and Send's error.
+func (r *Router) OnFailure(handler func(msg *Message, err error)) {
+	r.handlersMu.Lock()
+	defer r.handlersMu.Unlock()
+	r.failureHandlers = append(r.failureHandlers, handler)
+}
+
+// runHandlers calls the delivery or failure handlers for a send.
+func (r *Router) runHandlers(msg *Message, err error) {
+	r.handlersMu.Lock()
+	handlers := r.deliveryHandlers
+	if err != nil {
+		handlers = r.failureHandlers
+	}
+	r.handlersMu.Unlock()
+
+	for _, handler := range handlers {
+		runHandler(handler, msg, err)
+	}
+}
+
+func runHandler(handler DeliveryHandler, msg *Message, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			fmt.Fprintf(os.Stderr, "warning: mail delivery handler panicked: %v\n", p)
+		}
+	}()
+	handler(msg, err)
+}
+
+// wispLogSuppressedByEnv reports whether GT_SUPPRESS_WISP_LOG is set true.
+func wispLogSuppressedByEnv() bool {
+	suppress, _ := strconv.ParseBool(os.Getenv(EnvSuppressWispLog))
+	return suppress
+}
+
 // isListAddress returns true if the address uses list:name syntax.
 func isListAddress(address string) bool {
 	return strings.HasPrefix(address, "list:")
 }
 
 // parseListName extracts the list name from a list:name address.
+// The name is not validated here; expandList rejects unsafe names.
 func parseListName(address string) string {
 	return strings.TrimPrefix(address, "list:")
 }
@@ -72,6 +171,7 @@ func isQueueAddress(address string) bool {
 }
 
 // parseQueueName extracts the queue name from a queue:name address.
+// The name is not validated here; expandQueue rejects unsafe names.
 func parseQueueName(address string) string {
 	return strings.TrimPrefix(address, "queue:")
 }
@@ -82,6 +182,7 @@ func isAnnounceAddress(address string) bool {
 }
 
 // parseAnnounceName extracts the announce channel name from an announce:name address.
+// The name is not validated here; expandAnnounce rejects unsafe names.
 func parseAnnounceName(address string) string {
 	return strings.TrimPrefix(address, "announce:")
 }
@@ -96,11 +197,44 @@ func parseChannelName(address string) string {
 	return strings.TrimPrefix(address, "channel:")
 }
 
+// validateAddressName checks a list, queue or announce channel name.
+// Names may contain only letters, digits, '_', '-' and '/', the last for
+// namespaced names like cleanup/gongshow; they may not be absolute or
+// contain "..". Returns an error wrapping ErrInvalidName.
+func validateAddressName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: empty name", ErrInvalidName)
+	case strings.ContainsRune(name, 0):
+		return fmt.Errorf("%w %q: contains a null byte", ErrInvalidName, name)
+	case strings.HasPrefix(name, "/"):
+		return fmt.Errorf("%w %q: absolute path", ErrInvalidName, name)
+	case strings.Contains(name, ".."):
+		return fmt.Errorf("%w %q: contains \"..\"", ErrInvalidName, name)
+	}
+	for _, c := range name {
+		if !isAddressNameChar(c) {
+			return fmt.Errorf("%w %q: contains %q", ErrInvalidName, name, c)
+		}
+	}
+	return nil
+}
+
+// isAddressNameChar reports whether c is allowed in a list, queue or
+// announce channel name: [a-zA-Z0-9/_-].
+func isAddressNameChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		c == '/' || c == '_' || c == '-'
+}
+
 // expandFromConfig is a generic helper for config-based expansion.
-// It loads the messaging config and calls the getter to extract the desired value.
+// It validates the name, loads the messaging config and calls the getter to extract the desired value.
 // This consolidates the common pattern of: check townRoot, load config, lookup in map.
 func expandFromConfig[T any](r *Router, name string, getter func(*config.MessagingConfig) (T, bool), errType error) (T, error) {
 	var zero T
+	if err := validateAddressName(name); err != nil {
+		return zero, err
+	}
 	if r.townRoot == "" {
 		return zero, fmt.Errorf("%w: %s (no town root)", errType, name)
 	}
@@ -120,7 +254,8 @@ func expandFromConfig[T any](r *Router, name string, getter func(*config.Messagi
 }
 
 // expandList returns the recipients for a mailing list.
-// Returns ErrUnknownList if the list is not found.
+// Returns ErrUnknownList if the list is not found, or ErrInvalidName if
+// the name is unsafe (see validateAddressName).
 func (r *Router) expandList(listName string) ([]string, error) {
 	recipients, err := expandFromConfig(r, listName, func(cfg *config.MessagingConfig) ([]string, bool) {
 		r, ok := cfg.Lists[listName]
@@ -138,7 +273,8 @@ func (r *Router) expandList(listName string) ([]string, error) {
 }
 
 // expandQueue returns the QueueConfig for a queue name.
-// Returns ErrUnknownQueue if the queue is not found.
+// Returns ErrUnknownQueue if the queue is not found, or ErrInvalidName if
+// the name is unsafe (see validateAddressName).
 func (r *Router) expandQueue(queueName string) (*config.QueueConfig, error) {
 	return expandFromConfig(r, queueName, func(cfg *config.MessagingConfig) (*config.QueueConfig, bool) {
 		qc, ok := cfg.Queues[queueName]
@@ -150,7 +286,8 @@ func (r *Router) expandQueue(queueName string) (*config.QueueConfig, error) {
 }
 
 // expandAnnounce returns the AnnounceConfig for an announce channel name.
-// Returns ErrUnknownAnnounce if the channel is not found.
+// Returns ErrUnknownAnnounce if the channel is not found, or ErrInvalidName if
+// the name is unsafe (see validateAddressName).
 func (r *Router) expandAnnounce(announceName string) (*config.AnnounceConfig, error) {
 	return expandFromConfig(r, announceName, func(cfg *config.MessagingConfig) (*config.AnnounceConfig, bool) {
 		ac, ok := cfg.Announces[announceName]
@@ -332,24 +469,34 @@ func agentBeadToAddress(bead *agentBead) string {
 	}
 }
 
-// ResolveGroupAddress resolves a @group address to individual recipient addresses.
+// ResolveGroupAddress resolves a @group address to individual recipient addresses,
+// static members included (see ExpandGroup).
 // Returns the list of resolved addresses and any error.
 // This is the public entry point for group resolution.
 func (r *Router) ResolveGroupAddress(address string) ([]string, error) {
-	group := parseGroupAddress(address)
-	if group == nil {
+	if !isGroupAddress(address) {
 		return nil, fmt.Errorf("invalid group address: %s", address)
 	}
-	return r.resolveGroup(group)
+	return r.ExpandGroup(address)
 }
 
 // resolveGroup resolves a @group address to individual recipient addresses.
+// Expansions are served from the group cache when fresh (see GroupCache).
 // Returns the list of resolved addresses and any error.
 func (r *Router) resolveGroup(group *ParsedGroup) ([]string, error) {
 	if group == nil {
 		return nil, errors.New("nil group")
 	}
+	if r.groupCache == nil || group.Original == "" {
+		return r.resolveGroupUncached(group)
+	}
+	return r.groupCache.Resolve(r.townRoot, group.Original, func() ([]string, error) {
+		return r.resolveGroupUncached(group)
+	})
+}
 
+// resolveGroupUncached performs a live @group lookup against agent beads.
+func (r *Router) resolveGroupUncached(group *ParsedGroup) ([]string, error) {
 	switch group.Type {
 	case GroupTypeOverseer:
 		return r.resolveOverseer()
@@ -483,10 +630,12 @@ func (r *Router) queryAgents(descContains string) ([]*agentBead, error) {
 // - Message.Wisp is explicitly set
 // - Subject matches lifecycle message patterns (POLECAT_*, NUDGE, etc.)
 func (r *Router) shouldBeWisp(msg *Message) bool {
-	if msg.Wisp {
-		return true
-	}
-	// Auto-detect lifecycle messages by subject prefix
+	return msg.Wisp || isLifecycleMessage(msg)
+}
+
+// isLifecycleMessage reports whether msg is an internal protocol message,
+// detected by subject prefix.
+func isLifecycleMessage(msg *Message) bool {
 	subjectLower := strings.ToLower(msg.Subject)
 	wispPrefixes := []string{
 		"polecat_started",
@@ -506,11 +655,29 @@ func (r *Router) shouldBeWisp(msg *Message) bool {
 // Routes the message to the correct beads database based on recipient address.
 // Supports fan-out for:
 // - Mailing lists (list:name) - fans out to all list members
-// - @group addresses - resolves and fans out to matching agents
+// - @group addresses - resolves and fans out to matching agents and static members
 // Supports single-copy delivery for:
 // - Queues (queue:name) - stores single message for worker claiming
 // - Announces (announce:name) - bulletin board, no claiming, retention-limited
 func (r *Router) Send(msg *Message) error {
+	return r.SendContext(context.Background(), msg)
+}
+
+// SendContext is Send for a message sent as part of the operation ctx
+// belongs to: unless msg already has one, it is stamped with ctx's
+// correlation ID.
+func (r *Router) SendContext(ctx context.Context, msg *Message) error {
+	if msg.CorrelationID == "" {
+		msg.CorrelationID = events.CorrelationID(ctx)
+	}
+
+	err := r.route(msg)
+	r.runHandlers(msg, err)
+	return err
+}
+
+// route delivers msg by the kind of address it is sent to.
+func (r *Router) route(msg *Message) error {
 	// Check for mailing list address
 	if isListAddress(msg.To) {
 		return r.sendToList(msg)
@@ -540,14 +707,10 @@ func (r *Router) Send(msg *Message) error {
 	return r.sendToSingle(msg)
 }
 
-// sendToGroup resolves a @group address and sends individual messages to each member.
+// sendToGroup resolves a @group address and sends individual messages to each
+// member, dynamic and static (see ExpandGroup).
 func (r *Router) sendToGroup(msg *Message) error {
-	group := parseGroupAddress(msg.To)
-	if group == nil {
-		return fmt.Errorf("invalid group address: %s", msg.To)
-	}
-
-	recipients, err := r.resolveGroup(group)
+	recipients, err := r.ExpandGroup(msg.To)
 	if err != nil {
 		return fmt.Errorf("resolving group %s: %w", msg.To, err)
 	}
@@ -589,6 +752,12 @@ func (r *Router) sendToSingle(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
+	if label := metadataLabel(msg.Metadata); label != "" {
+		labels = append(labels, label)
+	}
 	// Add CC labels (one per recipient)
 	for _, cc := range msg.CC {
 		ccIdentity := addressToIdentity(cc)
@@ -652,7 +821,7 @@ func (r *Router) sendToList(msg *Message) error {
 		copy := *msg
 		copy.To = recipient
 
-		if err := r.Send(&copy); err != nil {
+		if err := r.route(&copy); err != nil {
 			lastErr = err
 			continue
 		}
@@ -685,11 +854,18 @@ func (r *Router) sendToQueue(msg *Message) error {
 	queueName := parseQueueName(msg.To)
 
 	// Validate queue exists in messaging config
-	_, err := r.expandQueue(queueName)
+	qc, err := r.expandQueue(queueName)
 	if err != nil {
 		return err
 	}
 
+	// Offer the message to the next available worker (round-robin, skipping
+	// workers at max_claims). Any eligible worker can still claim it.
+	var worker string
+	if r.workerPool != nil {
+		worker = r.selectQueueWorker(queueName, qc.Workers, qc.MaxClaims)
+	}
+
 	// Build labels for from/thread/reply-to/cc plus queue metadata
 	var labels []string
 	labels = append(labels, "from:"+msg.From)
@@ -700,10 +876,19 @@ func (r *Router) sendToQueue(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
+	if label := metadataLabel(msg.Metadata); label != "" {
+		labels = append(labels, label)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	if worker != "" {
+		labels = append(labels, "offered-to:"+addressToIdentity(worker))
+	}
 
 	// Build command: bd create <subject> --type=message --assignee=queue:<name> -d <body>
 	// Use queue:<name> as assignee so inbox queries can filter by queue
@@ -735,11 +920,29 @@ func (r *Router) sendToQueue(msg *Message) error {
 		return fmt.Errorf("sending to queue %s: %w", queueName, err)
 	}
 
-	// No notification for queue messages - workers poll or check on their own schedule
+	// Only the selected worker is nudged; the others poll on their own schedule
+	if worker != "" {
+		_ = r.notifyQueueWorker(worker, queueName, msg)
+	}
 
 	return nil
 }
 
+// notifyQueueWorker nudges the worker a queue message was offered to, if it
+// has an active session (best-effort).
+func (r *Router) notifyQueueWorker(worker, queueName string, msg *Message) error {
+	sessionID := addressToSessionID(worker)
+	if sessionID == "" {
+		return nil
+	}
+	hasSession, err := r.tmux.HasSession(sessionID)
+	if err != nil || !hasSession {
+		return nil
+	}
+	notification := fmt.Sprintf("📥 New work on queue %s from %s. Subject: %s. Run 'gt mail claim %s' to take it.", queueName, msg.From, msg.Subject, queueName)
+	return r.tmux.NudgeSession(sessionID, notification)
+}
+
 // sendToAnnounce delivers a message to an announce channel (bulletin board).
 // Unlike sendToQueue, no claiming is supported - messages persist until retention limit.
 // ONE copy is stored in town-level beads with announce_channel metadata.
@@ -771,6 +974,12 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
+	if label := metadataLabel(msg.Metadata); label != "" {
+		labels = append(labels, label)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
@@ -843,6 +1052,12 @@ func (r *Router) sendToChannel(msg *Message) error {
 	if msg.ReplyTo != "" {
 		labels = append(labels, "reply-to:"+msg.ReplyTo)
 	}
+	if msg.CorrelationID != "" {
+		labels = append(labels, "correlation:"+msg.CorrelationID)
+	}
+	if label := metadataLabel(msg.Metadata); label != "" {
+		labels = append(labels, label)
+	}
 	for _, cc := range msg.CC {
 		ccIdentity := addressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
@@ -950,7 +1165,9 @@ func isSelfMail(from, to string) bool {
 func (r *Router) GetMailbox(address string) (*Mailbox, error) {
 	beadsDir := r.resolveBeadsDir(address)
 	workDir := filepath.Dir(beadsDir) // Parent of .beads
-	return NewMailboxFromAddress(address, workDir), nil
+	mailbox := NewMailboxFromAddress(address, workDir)
+	mailbox.suppressWispArchive = r.suppressWispLog
+	return mailbox, nil
 }
 
 // notifyRecipient sends a notification to a recipient's tmux session.
@@ -970,9 +1187,30 @@ func (r *Router) notifyRecipient(msg *Message) error {
 
 	// Send notification to the agent's conversation history
 	notification := fmt.Sprintf("📬 You have new mail from %s. Subject: %s. Run 'gt mail inbox' to read.", msg.From, msg.Subject)
+	if meta := nudgeMetadata(msg.Metadata); meta != "" {
+		notification += " Metadata: " + meta
+	}
 	return r.tmux.NudgeSession(sessionID, notification)
 }
 
+// maxNudgeMetadata is the most metadata JSON a mail notification carries;
+// beyond it the notification only says how many keys there are, and the
+// recipient reads them with the message.
+const maxNudgeMetadata = 512
+
+// nudgeMetadata returns metadata as JSON for a mail notification, or a
+// count of its keys when the JSON would be too long. Returns "" for none.
+func nudgeMetadata(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil || len(data) > maxNudgeMetadata {
+		return fmt.Sprintf("%d keys (see 'gt mail read')", len(metadata))
+	}
+	return string(data)
+}
+
 // addressToSessionID converts a mail address to a tmux session ID.
 // Returns empty string if address format is not recognized.
 func addressToSessionID(address string) string {
@@ -999,3 +1237,27 @@ func addressToSessionID(address string) string {
 	// Refinery: gt-rig-refinery (if refinery has its own session)
 	return fmt.Sprintf("gt-%s-%s", rig, target)
 }
+
+// sessionIDToAddress converts a tmux session ID back to a mail address,
+// the inverse of addressToSessionID: "hq-mayor" is "mayor/" and
+// "gt-gongshow-witness" is "gongshow/witness". Rig names can't contain
+// hyphens, so the first one after the prefix ends the rig.
+// Returns empty string if the session ID format is not recognized.
+func sessionIDToAddress(sessionID string) string {
+	switch sessionID {
+	case session.MayorSessionName():
+		return "mayor/"
+	case session.DeaconSessionName():
+		return "deacon/"
+	}
+
+	rest, ok := strings.CutPrefix(sessionID, session.Prefix)
+	if !ok {
+		return ""
+	}
+	rig, target, ok := strings.Cut(rest, "-")
+	if !ok || rig == "" || target == "" {
+		return ""
+	}
+	return rig + "/" + target
+}
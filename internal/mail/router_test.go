@@ -1,9 +1,16 @@
 package mail
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/quick"
+	"time"
 )
 
 func TestDetectTownRoot(t *testing.T) {
@@ -112,6 +119,78 @@ func TestAddressToSessionID(t *testing.T) {
 	}
 }
 
+func TestSessionIDToAddress(t *testing.T) {
+	tests := []struct {
+		sessionID string
+		want      string
+	}{
+		{"hq-mayor", "mayor/"},
+		{"hq-deacon", "deacon/"},
+		{"gt-gongshow-refinery", "gongshow/refinery"},
+		{"gt-gongshow-Toast", "gongshow/Toast"},
+		{"gt-beads-witness", "beads/witness"},
+		{"gt-gongshow-crew-max", "gongshow/crew-max"}, // Only the rig ends at a hyphen
+		{"gt-gongshow-", ""},                          // Empty target
+		{"gt-gongshow", ""},                           // No target
+		{"gt--witness", ""},                           // Empty rig
+		{"hq-boot", ""},                               // Not a mail address
+		{"gongshow-witness", ""},                      // No prefix
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sessionID, func(t *testing.T) {
+			got := sessionIDToAddress(tt.sessionID)
+			if got != tt.want {
+				t.Errorf("sessionIDToAddress(%q) = %q, want %q", tt.sessionID, got, tt.want)
+			}
+		})
+	}
+}
+
+// quickAddress is a random valid mail address for testing/quick: a
+// town-level address, or a rig (which can't contain hyphens, nor begin
+// like a town-level address) and a target.
+type quickAddress string
+
+func (quickAddress) Generate(r *rand.Rand, size int) reflect.Value {
+	switch r.Intn(10) {
+	case 0:
+		return reflect.ValueOf(quickAddress("mayor/"))
+	case 1:
+		return reflect.ValueOf(quickAddress("deacon/"))
+	}
+	rig := "r" + randomString(r, size, "abcdefghijklmnopqrstuvwxyz0123456789_")
+	target := "t" + randomString(r, size, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-/")
+	return reflect.ValueOf(quickAddress(rig + "/" + target))
+}
+
+func randomString(r *rand.Rand, size int, alphabet string) string {
+	b := make([]byte, r.Intn(size+1))
+	for i := range b {
+		b[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+func TestSessionIDToAddressRoundTrip(t *testing.T) {
+	toSessionAndBack := func(address quickAddress) bool {
+		return sessionIDToAddress(addressToSessionID(string(address))) == string(address)
+	}
+	if err := quick.Check(toSessionAndBack, nil); err != nil {
+		t.Error(err)
+	}
+
+	// And the other way, from the session IDs of those addresses.
+	toAddressAndBack := func(address quickAddress) bool {
+		sessionID := addressToSessionID(string(address))
+		return addressToSessionID(sessionIDToAddress(sessionID)) == sessionID
+	}
+	if err := quick.Check(toAddressAndBack, nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestIsSelfMail(t *testing.T) {
 	tests := []struct {
 		from string
@@ -193,6 +272,63 @@ func TestShouldBeWisp(t *testing.T) {
 	}
 }
 
+func TestWispFilterKeepsNudgeOutOfArchive(t *testing.T) {
+	r := NewRouterWithTownRoot(t.TempDir(), "").WithWispFilter(true)
+	m := NewMailbox(t.TempDir())
+	m.suppressWispArchive = r.suppressWispLog
+
+	nudge := &Message{ID: "msg-nudge", From: "gongshow/witness", To: "gongshow/Toast", Subject: "NUDGE: check your hook", Wisp: true, Timestamp: time.Now()}
+	note := &Message{ID: "msg-note", From: "mayor/", To: "gongshow/Toast", Subject: "Please review this PR", Wisp: true, Timestamp: time.Now()}
+	for _, msg := range []*Message{nudge, note} {
+		if err := m.Append(msg); err != nil {
+			t.Fatalf("Append %s: %v", msg.ID, err)
+		}
+	}
+
+	if !r.FilterWisp(nudge) {
+		t.Error("FilterWisp(NUDGE) = false, want true")
+	}
+	if r.FilterWisp(note) {
+		t.Error("FilterWisp(regular wisp) = true, want false")
+	}
+
+	for _, id := range []string{nudge.ID, note.ID} {
+		if err := m.Archive(id); err != nil {
+			t.Fatalf("Archive %s: %v", id, err)
+		}
+	}
+
+	// Both left the inbox, but only the regular message was archived.
+	if inbox, _ := m.List(); len(inbox) != 0 {
+		t.Errorf("inbox has %d messages, want 0", len(inbox))
+	}
+	archived, err := m.ListArchived()
+	if err != nil {
+		t.Fatalf("ListArchived: %v", err)
+	}
+	if len(archived) != 1 || archived[0].ID != note.ID {
+		t.Errorf("archived = %v, want only %s", archived, note.ID)
+	}
+
+	// Without suppression the NUDGE is archived as before.
+	r.WithWispFilter(false)
+	if r.FilterWisp(nudge) {
+		t.Error("FilterWisp(NUDGE) with suppression off = true, want false")
+	}
+}
+
+func TestWispFilterFromEnv(t *testing.T) {
+	t.Setenv(EnvSuppressWispLog, "true")
+	if r := NewRouter(t.TempDir()); !r.FilterWisp(&Message{Subject: "POLECAT_STARTED: Toast"}) {
+		t.Errorf("%s=true: FilterWisp(POLECAT_STARTED) = false, want true", EnvSuppressWispLog)
+	}
+
+	t.Setenv(EnvSuppressWispLog, "")
+	if r := NewRouter(t.TempDir()); r.FilterWisp(&Message{Subject: "POLECAT_STARTED: Toast"}) {
+		t.Errorf("%s unset: FilterWisp(POLECAT_STARTED) = true, want false", EnvSuppressWispLog)
+	}
+}
+
 func TestResolveBeadsDir(t *testing.T) {
 	// With town root set
 	r := NewRouterWithTownRoot("/work/dir", "/home/user/gt")
@@ -494,6 +630,58 @@ func TestExpandQueueNoTownRoot(t *testing.T) {
 	}
 }
 
+func TestValidateAddressName(t *testing.T) {
+	valid := []string{"oncall", "cleanup/gongshow", "gongshow/polecats", "priority-high", "on_call", "a/b/c", "v2"}
+	for _, name := range valid {
+		if err := validateAddressName(name); err != nil {
+			t.Errorf("validateAddressName(%q) = %v, want nil", name, err)
+		}
+	}
+
+	invalid := []struct {
+		name  string
+		input string
+	}{
+		{"empty", ""},
+		{"parent dir", ".."},
+		{"traversal", "../../../etc/passwd"},
+		{"traversal mid-name", "cleanup/../secrets"},
+		{"absolute path", "/etc/passwd"},
+		{"null byte", "oncall\x00"},
+		{"dot", "on.call"},
+		{"space", "on call"},
+		{"backslash", "cleanup\\gongshow"},
+		{"colon", "list:oncall"},
+		{"non-ascii", "oncäll"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAddressName(tt.input); !errors.Is(err, ErrInvalidName) {
+				t.Errorf("validateAddressName(%q) = %v, want ErrInvalidName", tt.input, err)
+			}
+		})
+	}
+}
+
+func TestExpandRejectsInvalidNames(t *testing.T) {
+	// Validation comes before the config is read, so no town is needed.
+	r := &Router{workDir: "/tmp", townRoot: ""}
+	for _, name := range []string{"../../../etc/passwd", "/etc/passwd", "oncall\x00", "on.call"} {
+		if _, err := r.expandList(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expandList(%q) error = %v, want ErrInvalidName", name, err)
+		}
+		if _, err := r.expandQueue(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expandQueue(%q) error = %v, want ErrInvalidName", name, err)
+		}
+		if _, err := r.expandAnnounce(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expandAnnounce(%q) error = %v, want ErrInvalidName", name, err)
+		}
+	}
+	if _, err := r.ExpandListAddress("list:../oncall"); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("ExpandListAddress(list:../oncall) error = %v, want ErrInvalidName", err)
+	}
+}
+
 // ============ Announce Address Tests ============
 
 func TestIsAnnounceAddress(t *testing.T) {
@@ -813,3 +1001,79 @@ func TestExpandAnnounceNoTownRoot(t *testing.T) {
 		t.Errorf("expandAnnounce error = %v, want containing 'no town root'", err)
 	}
 }
+
+func TestNudgeMetadata(t *testing.T) {
+	if got := nudgeMetadata(nil); got != "" {
+		t.Errorf("nudgeMetadata(nil) = %q, want empty", got)
+	}
+	if got := nudgeMetadata(map[string]string{"git-sha": "abc123"}); got != `{"git-sha":"abc123"}` {
+		t.Errorf("nudgeMetadata = %q, want the JSON", got)
+	}
+
+	large := make(map[string]string)
+	for i := 0; i < 60; i++ {
+		large[fmt.Sprintf("key-%d", i)] = "value"
+	}
+	if got := nudgeMetadata(large); got != "60 keys (see 'gt mail read')" {
+		t.Errorf("nudgeMetadata(60 keys) = %q, want a key count", got)
+	}
+}
+
+func TestRouterDeliveryHandlers(t *testing.T) {
+	stubBdCreate(t, "gongshow/ghost")
+	r := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+
+	var calls []string
+	r.OnDelivery(func(msg *Message, err error) {
+		calls = append(calls, fmt.Sprintf("delivered 1: %s %v", msg.To, err))
+	})
+	r.OnDelivery(func(msg *Message, err error) {
+		calls = append(calls, fmt.Sprintf("delivered 2: %s %v", msg.To, err))
+	})
+	r.OnFailure(func(msg *Message, err error) {
+		calls = append(calls, fmt.Sprintf("failed: %s %v", msg.To, err != nil))
+	})
+
+	delivered := NewMessage("mayor/", "gongshow/Toast", "Build results", "All green.")
+	if err := r.Send(delivered); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	failed := NewMessage("mayor/", "gongshow/ghost", "Build results", "All green.")
+	if err := r.Send(failed); err == nil {
+		t.Fatal("Send to a missing agent succeeded")
+	}
+
+	want := []string{
+		"delivered 1: gongshow/Toast <nil>",
+		"delivered 2: gongshow/Toast <nil>",
+		"failed: gongshow/ghost true",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("handler calls = %q, want %q", calls, want)
+	}
+}
+
+func TestRouterDeliveryHandlerPanic(t *testing.T) {
+	created := stubBdCreate(t, "")
+	r := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+
+	var got *Message
+	r.OnDelivery(func(*Message, error) { panic("handler bug") })
+	r.OnDelivery(func(msg *Message, _ error) { got = msg })
+
+	msg := NewMessage("mayor/", "gongshow/Toast", "Build results", "All green.")
+	if err := r.Send(msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got != msg {
+		t.Errorf("handler after the panicking one got %v, want the sent message", got)
+	}
+
+	// The router still works.
+	if err := r.Send(NewMessage("mayor/", "gongshow/Nux", "Again", "")); err != nil {
+		t.Fatalf("second Send: %v", err)
+	}
+	if len(*created) != 2 {
+		t.Errorf("created %d messages, want 2", len(*created))
+	}
+}
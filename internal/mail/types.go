@@ -3,7 +3,9 @@ package mail
 
 import (
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -123,6 +125,14 @@ type Message struct {
 	// ClaimedAt is when the queue message was claimed.
 	// Only set for queue messages after claiming.
 	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+
+	// CorrelationID ties the message to the command that sent it, so events
+	// about it (bounces, replies) join that command's event chain.
+	CorrelationID string `json:"correlation_id,omitempty"`
+
+	// Metadata holds free-form annotations (e.g. "git-sha", "pr-url") that
+	// travel with the message through delivery and archiving.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // NewMessage creates a new message with a generated ID and thread ID.
@@ -212,6 +222,22 @@ func (m *Message) IsClaimed() bool {
 	return m.ClaimedBy != ""
 }
 
+// SetMeta sets a metadata annotation and returns the message, so calls can
+// be chained.
+func (m *Message) SetMeta(key, value string) *Message {
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]string)
+	}
+	m.Metadata[key] = value
+	return m
+}
+
+// GetMeta returns a metadata annotation and whether it is set.
+func (m *Message) GetMeta(key string) (string, bool) {
+	value, ok := m.Metadata[key]
+	return value, ok
+}
+
 // Validate checks that the message has a valid routing configuration.
 // Returns an error if to, queue, and channel are not mutually exclusive.
 func (m *Message) Validate() error {
@@ -290,6 +316,40 @@ type BeadsMessage struct {
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+
+	correlationID string            // Correlation ID of the sending command
+	metadata      map[string]string // Decoded from the meta: label
+}
+
+// metadataLabelPrefix marks the label carrying a message's metadata.
+const metadataLabelPrefix = "meta:"
+
+// metadataLabel encodes metadata as a single label: JSON, base64url-encoded
+// so that commas and colons in keys or values survive bd's --labels list.
+// Returns "" for empty metadata.
+func metadataLabel(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return ""
+	}
+	return metadataLabelPrefix + base64.RawURLEncoding.EncodeToString(data)
+}
+
+// parseMetadataLabel decodes the value of a meta: label, returning nil if
+// it is damaged.
+func parseMetadataLabel(encoded string) map[string]string {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil
+	}
+	var metadata map[string]string
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil
+	}
+	return metadata
 }
 
 // ParseLabels extracts metadata from the labels array.
@@ -309,6 +369,10 @@ func (bm *BeadsMessage) ParseLabels() {
 			bm.queue = strings.TrimPrefix(label, "queue:")
 		} else if strings.HasPrefix(label, "channel:") {
 			bm.channel = strings.TrimPrefix(label, "channel:")
+		} else if strings.HasPrefix(label, "correlation:") {
+			bm.correlationID = strings.TrimPrefix(label, "correlation:")
+		} else if strings.HasPrefix(label, metadataLabelPrefix) {
+			bm.metadata = parseMetadataLabel(strings.TrimPrefix(label, metadataLabelPrefix))
 		} else if strings.HasPrefix(label, "claimed-by:") {
 			bm.claimedBy = strings.TrimPrefix(label, "claimed-by:")
 		} else if strings.HasPrefix(label, "claimed-at:") {
@@ -384,6 +448,9 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		Channel:   bm.channel,
 		ClaimedBy: bm.claimedBy,
 		ClaimedAt: bm.claimedAt,
+
+		CorrelationID: bm.correlationID,
+		Metadata:      bm.metadata,
 	}
 }
 
@@ -1,6 +1,10 @@
 package mail
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -281,6 +285,88 @@ func TestBeadsMessageToMessageWithReplyTo(t *testing.T) {
 	}
 }
 
+func TestBeadsMessageToMessageWithCorrelation(t *testing.T) {
+	bm := BeadsMessage{
+		ID:        "hq-corr",
+		Title:     "Work assigned",
+		Status:    "open",
+		Assignee:  "gongshow/Toast",
+		Labels:    []string{"from:mayor/", "correlation:3f9a0c1d2e4b5a67"},
+		CreatedAt: time.Now(),
+	}
+
+	if got := bm.ToMessage().CorrelationID; got != "3f9a0c1d2e4b5a67" {
+		t.Errorf("CorrelationID = %q, want '3f9a0c1d2e4b5a67'", got)
+	}
+}
+
+func TestMessageMeta(t *testing.T) {
+	msg := NewMessage("mayor/", "gongshow/Toast", "Review", "").
+		SetMeta("git-sha", "abc123").
+		SetMeta("pr-url", "https://example.com/pr/1")
+
+	if v, ok := msg.GetMeta("git-sha"); !ok || v != "abc123" {
+		t.Errorf("GetMeta(git-sha) = %q, %v, want abc123, true", v, ok)
+	}
+	if _, ok := msg.GetMeta("missing"); ok {
+		t.Error("GetMeta(missing) ok = true")
+	}
+	if _, ok := (&Message{}).GetMeta("git-sha"); ok {
+		t.Error("GetMeta on a message without metadata ok = true")
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	small := map[string]string{"git-sha": "abc123", "pr-url": "https://example.com/pr/1?a=1,b=2"}
+	large := make(map[string]string)
+	for i := 0; i < 75; i++ {
+		large[fmt.Sprintf("key:%d", i)] = strings.Repeat(fmt.Sprintf("v,%d ", i), 10)
+	}
+
+	for name, metadata := range map[string]map[string]string{"small": small, "large": large} {
+		t.Run(name, func(t *testing.T) {
+			label := metadataLabel(metadata)
+			if strings.Contains(label, ",") {
+				t.Fatalf("label %q contains a comma, which splits bd's --labels", label)
+			}
+
+			// Through beads, as delivery stores it.
+			bm := BeadsMessage{
+				ID:        "hq-meta",
+				Assignee:  "gongshow/Toast",
+				Labels:    []string{"from:mayor/", label},
+				CreatedAt: time.Now(),
+			}
+			if got := bm.ToMessage().Metadata; !reflect.DeepEqual(got, metadata) {
+				t.Errorf("metadata through labels = %v, want %v", got, metadata)
+			}
+
+			// Through JSON, as archiving stores it.
+			data, err := json.Marshal(&Message{ID: "hq-meta", Metadata: metadata})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var archived Message
+			if err := json.Unmarshal(data, &archived); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(archived.Metadata, metadata) {
+				t.Errorf("metadata through JSON = %v, want %v", archived.Metadata, metadata)
+			}
+		})
+	}
+}
+
+func TestMetadataLabelEmptyAndDamaged(t *testing.T) {
+	if label := metadataLabel(nil); label != "" {
+		t.Errorf("metadataLabel(nil) = %q, want empty", label)
+	}
+	bm := BeadsMessage{Labels: []string{"from:mayor/", metadataLabelPrefix + "not base64!"}}
+	if got := bm.ToMessage().Metadata; got != nil {
+		t.Errorf("damaged metadata label decoded to %v, want nil", got)
+	}
+}
+
 func TestBeadsMessageToMessagePriorities(t *testing.T) {
 	tests := []struct {
 		priority int
@@ -0,0 +1,241 @@
+package mail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/flock"
+	"github.com/KeithWyatt/gongshow/internal/session"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// ErrNoWorkerAvailable is returned by SelectWorker when every worker in the
+// queue is at max_claims (or the queue has no workers).
+var ErrNoWorkerAvailable = errors.New("no queue worker available")
+
+// WorkerPool distributes queue messages across a queue's workers in
+// round-robin order, skipping workers that already hold max_claims claims.
+// Every gt mail send is its own process, so for a town the rotation is
+// kept in <town>/.runtime/queue-workers.json, under a file lock.
+type WorkerPool struct {
+	mu     sync.Mutex
+	dir    string // runtime dir for the state file; "" keeps it in memory
+	queues map[string]*poolQueue
+}
+
+type poolQueue struct {
+	Workers []string `json:"workers"`
+	Next    int      `json:"next"` // round-robin cursor
+}
+
+// NewWorkerPool creates a worker pool for a town. With no townRoot the
+// rotation only lasts as long as the pool.
+func NewWorkerPool(townRoot string) *WorkerPool {
+	wp := &WorkerPool{queues: make(map[string]*poolQueue)}
+	if townRoot != "" {
+		wp.dir = filepath.Join(townRoot, ".runtime")
+	}
+	return wp
+}
+
+// SelectWorker returns the next of workers (already expanded) for
+// queueName in round-robin order, skipping workers whose claim count in
+// currentClaims (keyed by address or beads identity) has reached
+// maxClaims (0 = unlimited). The rotation restarts when the worker list
+// changes.
+// Returns ErrNoWorkerAvailable if no worker can take more work.
+func (wp *WorkerPool) SelectWorker(queueName string, workers []string, maxClaims int, currentClaims map[string]int) (string, error) {
+	if len(workers) == 0 {
+		return "", fmt.Errorf("queue %s: %w", queueName, ErrNoWorkerAvailable)
+	}
+
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+
+	if wp.dir != "" {
+		if err := os.MkdirAll(wp.dir, 0755); err != nil {
+			return "", fmt.Errorf("creating runtime dir: %w", err)
+		}
+		lock := flock.New(filepath.Join(wp.dir, "queue-workers.lock"))
+		if err := lock.Lock(); err != nil {
+			return "", fmt.Errorf("locking queue worker state: %w", err)
+		}
+		defer func() { _ = lock.Unlock() }()
+		wp.load()
+	}
+
+	q, ok := wp.queues[queueName]
+	if !ok || strings.Join(q.Workers, "\x00") != strings.Join(workers, "\x00") {
+		q = &poolQueue{Workers: append([]string(nil), workers...)}
+		wp.queues[queueName] = q
+	}
+
+	for i := 0; i < len(q.Workers); i++ {
+		idx := (q.Next + i) % len(q.Workers)
+		worker := q.Workers[idx]
+		if maxClaims > 0 && claimsFor(currentClaims, worker) >= maxClaims {
+			continue
+		}
+		q.Next = idx + 1
+		return worker, wp.save()
+	}
+	return "", fmt.Errorf("queue %s: all workers at max_claims (%d): %w", queueName, maxClaims, ErrNoWorkerAvailable)
+}
+
+func (wp *WorkerPool) statePath() string {
+	return filepath.Join(wp.dir, "queue-workers.json")
+}
+
+// load replaces the in-memory rotation with the town's. A missing or
+// corrupt state file starts every queue from its first worker.
+func (wp *WorkerPool) load() {
+	wp.queues = make(map[string]*poolQueue)
+	data, err := os.ReadFile(wp.statePath())
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &wp.queues); err != nil || wp.queues == nil {
+		wp.queues = make(map[string]*poolQueue)
+	}
+}
+
+func (wp *WorkerPool) save() error {
+	if wp.dir == "" {
+		return nil
+	}
+	if err := util.AtomicWriteJSON(wp.statePath(), wp.queues); err != nil {
+		return fmt.Errorf("saving queue worker state: %w", err)
+	}
+	return nil
+}
+
+// claimsFor looks up a worker's claims by address, falling back to its
+// beads identity (claims are recorded under whatever form the claimant used).
+func claimsFor(claims map[string]int, worker string) int {
+	if n, ok := claims[worker]; ok {
+		return n
+	}
+	return claims[addressToIdentity(worker)]
+}
+
+// MatchWildcardWorkers expands worker patterns such as "gongshow/polecats/*"
+// against the live tmux sessions. Patterns without '*' are kept as-is.
+// The result is de-duplicated and keeps the order of the patterns.
+func (wp *WorkerPool) MatchWildcardWorkers(workers []string, sessions []*tmux.SessionInfo) []string {
+	var addresses []string
+	for _, s := range sessions {
+		if id, err := session.ParseSessionName(s.Name); err == nil {
+			addresses = append(addresses, id.Address())
+		}
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	add := func(addr string) {
+		if !seen[addr] {
+			seen[addr] = true
+			result = append(result, addr)
+		}
+	}
+	for _, pattern := range workers {
+		if !strings.Contains(pattern, "*") {
+			add(pattern)
+			continue
+		}
+		for _, addr := range addresses {
+			if matchPattern(pattern, addr) {
+				add(addr)
+			}
+		}
+	}
+	return result
+}
+
+// LiveMatches returns the addresses of the live sessions that match any of
+// the patterns, exact addresses included, in session order.
+func LiveMatches(patterns []string, sessions []*tmux.SessionInfo) []string {
+	var matched []string
+	for _, s := range sessions {
+		id, err := session.ParseSessionName(s.Name)
+		if err != nil {
+			continue
+		}
+		addr := id.Address()
+		for _, pattern := range patterns {
+			// Town-level addresses are written "mayor/" in config.
+			if strings.TrimSuffix(pattern, "/") == addr || matchPattern(pattern, addr) {
+				matched = append(matched, addr)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// selectQueueWorker picks the worker to offer a new queue message to.
+// Returns "" if the queue has no workers or none is available.
+func (r *Router) selectQueueWorker(queueName string, workers []string, maxClaims int) string {
+	if len(workers) == 0 {
+		return ""
+	}
+
+	var sessions []*tmux.SessionInfo
+	if names, err := r.tmux.ListSessions(); err == nil {
+		for _, name := range names {
+			sessions = append(sessions, &tmux.SessionInfo{Name: name})
+		}
+	}
+	expanded := r.workerPool.MatchWildcardWorkers(workers, sessions)
+
+	var claims map[string]int
+	if maxClaims > 0 {
+		var err error
+		if claims, err = r.queueClaims(queueName); err != nil {
+			return "" // can't enforce max_claims; leave it to workers to claim
+		}
+	}
+	worker, err := r.workerPool.SelectWorker(queueName, expanded, maxClaims, claims)
+	if err != nil {
+		return ""
+	}
+	return worker
+}
+
+// queueClaims counts open claimed messages in a queue per claimant.
+func (r *Router) queueClaims(queueName string) (map[string]int, error) {
+	beadsDir := r.resolveBeadsDir("")
+	out, err := runBdCommand([]string{"list",
+		"--label", "queue:" + queueName,
+		"--status", "open",
+		"--type", "message",
+		"--json",
+	}, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []struct {
+		Labels []string `json:"labels"`
+	}
+	if trimmed := strings.TrimSpace(string(out)); trimmed != "" {
+		if err := json.Unmarshal(out, &issues); err != nil {
+			return nil, fmt.Errorf("parsing bd output: %w", err)
+		}
+	}
+
+	claims := make(map[string]int)
+	for _, issue := range issues {
+		for _, label := range issue.Labels {
+			if claimant, ok := strings.CutPrefix(label, "claimed-by:"); ok {
+				claims[addressToIdentity(claimant)]++
+			}
+		}
+	}
+	return claims, nil
+}
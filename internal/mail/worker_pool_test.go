@@ -0,0 +1,105 @@
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+func TestSelectWorkerSkipsWorkerAtMaxClaims(t *testing.T) {
+	wp := NewWorkerPool(t.TempDir())
+	workers := []string{"gongshow/polecats/Toast", "gongshow/polecats/Nux", "gongshow/polecats/Slit"}
+
+	// Nux is at max_claims (recorded under its beads identity); Toast and Slit aren't.
+	claims := map[string]int{"gongshow/polecats/Toast": 1, "gongshow/Nux": 2}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		w, err := wp.SelectWorker("work", workers, 2, claims)
+		if err != nil {
+			t.Fatalf("SelectWorker: %v", err)
+		}
+		got = append(got, w)
+	}
+	want := []string{"gongshow/polecats/Toast", "gongshow/polecats/Slit", "gongshow/polecats/Toast", "gongshow/polecats/Slit"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("selection order = %v, want %v", got, want)
+	}
+}
+
+func TestSelectWorkerAllBusy(t *testing.T) {
+	wp := NewWorkerPool(t.TempDir())
+	workers := []string{"a/polecats/x", "a/polecats/y"}
+
+	_, err := wp.SelectWorker("work", workers, 1, map[string]int{"a/x": 1, "a/y": 3})
+	if !errors.Is(err, ErrNoWorkerAvailable) {
+		t.Errorf("got %v, want ErrNoWorkerAvailable", err)
+	}
+	if _, err := wp.SelectWorker("empty", nil, 0, nil); !errors.Is(err, ErrNoWorkerAvailable) {
+		t.Errorf("no workers: got %v, want ErrNoWorkerAvailable", err)
+	}
+
+	// Unlimited claims never skips.
+	if w, err := wp.SelectWorker("work", []string{"a/polecats/x"}, 0, map[string]int{"a/x": 100}); err != nil || w != "a/polecats/x" {
+		t.Errorf("unlimited: got (%q, %v)", w, err)
+	}
+}
+
+func TestSelectWorkerRotationSharedAcrossPools(t *testing.T) {
+	// Each gt process builds its own pool; the town's rotation carries over.
+	townRoot := t.TempDir()
+	workers := []string{"a/polecats/x", "a/polecats/y", "a/polecats/z"}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		w, err := NewWorkerPool(townRoot).SelectWorker("work", workers, 0, nil)
+		if err != nil {
+			t.Fatalf("SelectWorker: %v", err)
+		}
+		got = append(got, w)
+	}
+	want := []string{"a/polecats/x", "a/polecats/y", "a/polecats/z", "a/polecats/x"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("selection order = %v, want %v", got, want)
+	}
+
+	// A changed worker list starts over.
+	if w, _ := NewWorkerPool(townRoot).SelectWorker("work", workers[1:], 0, nil); w != "a/polecats/y" {
+		t.Errorf("after the worker list changed got %q, want a/polecats/y", w)
+	}
+}
+
+func TestMatchWildcardWorkers(t *testing.T) {
+	sessions := []*tmux.SessionInfo{
+		{Name: "gt-gongshow-Toast"},
+		{Name: "gt-gongshow-Nux"},
+		{Name: "gt-gongshow-witness"},
+		{Name: "gt-gongshow-crew-max"},
+		{Name: "gt-other-Slit"},
+		{Name: "hq-mayor"},
+		{Name: "unrelated"},
+	}
+	got := NewWorkerPool("").MatchWildcardWorkers(
+		[]string{"gongshow/polecats/*", "mayor/", "gongshow/polecats/Toast"}, sessions)
+	want := []string{"gongshow/polecats/Toast", "gongshow/polecats/Nux", "mayor/"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("MatchWildcardWorkers = %v, want %v", got, want)
+	}
+}
+
+func TestLiveMatches(t *testing.T) {
+	sessions := []*tmux.SessionInfo{
+		{Name: "gt-gongshow-Toast"},
+		{Name: "gt-gongshow-witness"},
+		{Name: "gt-other-Slit"},
+		{Name: "hq-mayor"},
+		{Name: "unrelated"},
+	}
+	got := LiveMatches([]string{"gongshow/polecats/*", "*/polecats/*", "mayor/", "gongshow/crew/max"}, sessions)
+	want := []string{"gongshow/polecats/Toast", "other/polecats/Slit", "mayor"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("LiveMatches = %v, want %v", got, want)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/KeithWyatt/gongshow/internal/beads"
 	"github.com/KeithWyatt/gongshow/internal/claude"
 	"github.com/KeithWyatt/gongshow/internal/config"
 	"github.com/KeithWyatt/gongshow/internal/constants"
@@ -43,11 +44,41 @@ func (m *Manager) SessionName() string {
 	return SessionName()
 }
 
-// mayorDir returns the working directory for the mayor.
+// mayorDir returns the mayor's directory, which holds its settings.
 func (m *Manager) mayorDir() string {
 	return filepath.Join(m.townRoot, "mayor")
 }
 
+// WorkDir returns the working directory of the mayor of the town at
+// townRoot: the town root itself, not mayor/, to match gt handoff.
+func WorkDir(townRoot string) string {
+	return townRoot
+}
+
+// BuildStartCommand returns the command the mayor of the town at townRoot
+// is started with, which carries its cold-start beacon.
+func BuildStartCommand(townRoot string) (string, error) {
+	return buildStartCommand(townRoot, "")
+}
+
+func buildStartCommand(townRoot, agentOverride string) (string, error) {
+	// Build startup beacon with explicit instructions (matches gt handoff behavior)
+	// This ensures the agent has clear context immediately, not after nudges arrive
+	beacon := session.FormatStartupNudge(session.StartupNudgeConfig{
+		Recipient: "mayor",
+		Sender:    "human",
+		Topic:     "cold-start",
+	})
+
+	// Build startup command WITH the beacon prompt - the startup hook handles 'gt prime' automatically
+	// Export GT_ROLE and BD_ACTOR in the command since tmux SetEnvironment only affects new panes
+	command, err := config.BuildAgentStartupCommandWithAgentOverride("mayor", "", townRoot, "", beacon, agentOverride)
+	if err != nil {
+		return "", fmt.Errorf("building startup command: %w", err)
+	}
+	return command, nil
+}
+
 // Start starts the mayor session.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
@@ -78,34 +109,25 @@ func (m *Manager) Start(agentOverride string) error {
 		return fmt.Errorf("ensuring Claude settings: %w", err)
 	}
 
-	// Build startup beacon with explicit instructions (matches gt handoff behavior)
-	// This ensures the agent has clear context immediately, not after nudges arrive
-	beacon := session.FormatStartupNudge(session.StartupNudgeConfig{
-		Recipient: "mayor",
-		Sender:    "human",
-		Topic:     "cold-start",
-	})
-
-	// Build startup command WITH the beacon prompt - the startup hook handles 'gt prime' automatically
-	// Export GT_ROLE and BD_ACTOR in the command since tmux SetEnvironment only affects new panes
-	startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("mayor", "", m.townRoot, "", beacon, agentOverride)
+	startupCmd, err := buildStartCommand(m.townRoot, agentOverride)
 	if err != nil {
-		return fmt.Errorf("building startup command: %w", err)
+		return err
 	}
 
 	// Create session in townRoot (not mayorDir) to match gt handoff behavior
 	// This ensures Mayor works from the town root where all tools work correctly
 	// See: https://github.com/anthropics/gongshow/issues/280
-	if err := t.NewSessionWithCommand(sessionID, m.townRoot, startupCmd); err != nil {
+	if err := t.NewSessionWithCommand(sessionID, WorkDir(m.townRoot), startupCmd); err != nil {
 		return fmt.Errorf("creating tmux session: %w", err)
 	}
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	roleConfig, _ := beads.LoadRoleConfig(m.townRoot, "mayor")
+	envVars := beads.AgentEnv(config.AgentEnvConfig{
 		Role:     "mayor",
 		TownRoot: m.townRoot,
-	})
+	}, sessionID, roleConfig)
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
@@ -0,0 +1,292 @@
+// Package metrics exports town health in the Prometheus text exposition
+// format, computed on each scrape.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/config"
+	"github.com/KeithWyatt/gongshow/internal/session"
+)
+
+// DefaultBeadsTimeout is how long a scrape waits for bd before exporting the
+// last values it got, flagged as stale.
+const DefaultBeadsTimeout = 500 * time.Millisecond
+
+// ContentType is the Prometheus text exposition format content type.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Source supplies the town state behind the metrics.
+type Source interface {
+	// Sessions returns the names of the live tmux sessions.
+	Sessions() ([]string, error)
+	// NewEvents returns the events logged since the last call, counted by type.
+	NewEvents() (map[string]int, error)
+
+	// The rest ask bd, which may be slow or down.
+
+	// Escalations returns open escalations counted by severity.
+	Escalations() (map[string]int, error)
+	// QueueDepths returns unclaimed messages counted by mail queue.
+	QueueDepths() (map[string]int, error)
+	// BdDaemonHealth returns bd daemon health, or nil if it can't be checked.
+	BdDaemonHealth() (*beads.BdDaemonHealth, error)
+}
+
+// beadsState is what the last successful bd refresh found.
+type beadsState struct {
+	escalations map[string]int
+	queues      map[string]int
+	health      *beads.BdDaemonHealth
+	at          time.Time
+}
+
+// Handler serves /metrics.
+type Handler struct {
+	source       Source
+	beadsTimeout time.Duration
+
+	scrapeMu    sync.Mutex         // one scrape at a time
+	eventsTotal map[string]float64 // events seen since the handler started
+
+	mu         sync.Mutex // guards the fields below
+	beads      beadsState
+	refreshing chan struct{} // closed when the running bd refresh ends
+}
+
+// NewHandler creates a metrics handler reading from source. bd is given
+// beadsTimeout per scrape (DefaultBeadsTimeout if <= 0).
+func NewHandler(source Source, beadsTimeout time.Duration) *Handler {
+	if beadsTimeout <= 0 {
+		beadsTimeout = DefaultBeadsTimeout
+	}
+	return &Handler{
+		source:       source,
+		beadsTimeout: beadsTimeout,
+		eventsTotal:  make(map[string]float64),
+	}
+}
+
+// ServeHTTP writes the current metrics.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.scrapeMu.Lock()
+	defer h.scrapeMu.Unlock()
+
+	start := time.Now()
+	e := &exposition{}
+
+	// Start bd first; the local metrics are gathered while it runs.
+	done := h.refreshBeads()
+
+	h.writeSessions(e)
+	h.writeEvents(e)
+
+	select {
+	case <-done:
+	case <-time.After(h.beadsTimeout):
+	}
+	h.writeBeads(e, start)
+
+	e.family("gt_scrape_duration_seconds", "gauge", "Time taken to compute these metrics.")
+	e.sample("gt_scrape_duration_seconds", nil, time.Since(start).Seconds())
+
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = io.WriteString(w, e.String())
+}
+
+// roles lists every session role, so each is exported even at zero.
+var roles = []session.Role{
+	session.RoleMayor, session.RoleDeacon, session.RoleWitness,
+	session.RoleRefinery, session.RoleCrew, session.RolePolecat,
+}
+
+func (h *Handler) writeSessions(e *exposition) {
+	names, err := h.source.Sessions()
+	e.family("gt_tmux_up", "gauge", "Whether tmux sessions could be listed.")
+	e.sample("gt_tmux_up", nil, boolValue(err == nil))
+	if err != nil {
+		return
+	}
+
+	byRole := make(map[string]int)
+	for _, r := range roles {
+		byRole[string(r)] = 0
+	}
+	for _, name := range names {
+		if id, err := session.ParseSessionName(name); err == nil {
+			byRole[string(id.Role)]++
+		}
+	}
+	e.family("gt_sessions", "gauge", "Live agent sessions by role.")
+	e.samples("gt_sessions", "role", byRole)
+}
+
+func (h *Handler) writeEvents(e *exposition) {
+	counts, err := h.source.NewEvents()
+	e.family("gt_events_up", "gauge", "Whether the events log could be read.")
+	e.sample("gt_events_up", nil, boolValue(err == nil))
+	if err != nil {
+		counts = nil
+	}
+
+	for typ, n := range counts {
+		h.eventsTotal[typ] += float64(n)
+	}
+	interval := make(map[string]int, len(h.eventsTotal))
+	for typ := range h.eventsTotal {
+		interval[typ] = counts[typ]
+	}
+
+	e.family("gt_events_total", "counter", "Events logged since the exporter started, by type.")
+	for _, typ := range sortedKeys(h.eventsTotal) {
+		e.sample("gt_events_total", []string{"type", typ}, h.eventsTotal[typ])
+	}
+	e.family("gt_events_last_interval", "gauge", "Events logged since the previous scrape, by type.")
+	e.samples("gt_events_last_interval", "type", interval)
+}
+
+// refreshBeads starts a bd refresh unless one is already running, and
+// returns a channel closed when it ends. A refresh outliving its scrape
+// keeps going, so a slow bd still lands for the next scrape.
+func (h *Handler) refreshBeads() <-chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.refreshing != nil {
+		return h.refreshing
+	}
+
+	done := make(chan struct{})
+	h.refreshing = done
+	go func() {
+		defer close(done)
+		state, err := h.collectBeads()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if err == nil {
+			h.beads = state
+		}
+		h.refreshing = nil
+	}()
+	return done
+}
+
+func (h *Handler) collectBeads() (beadsState, error) {
+	escalations, err := h.source.Escalations()
+	if err != nil {
+		return beadsState{}, err
+	}
+	queues, err := h.source.QueueDepths()
+	if err != nil {
+		return beadsState{}, err
+	}
+	health, err := h.source.BdDaemonHealth()
+	if err != nil {
+		return beadsState{}, err
+	}
+	return beadsState{escalations: escalations, queues: queues, health: health, at: time.Now()}, nil
+}
+
+// writeBeads exports the last bd values, flagged stale unless they were
+// fetched during this scrape.
+func (h *Handler) writeBeads(e *exposition, scrapeStart time.Time) {
+	h.mu.Lock()
+	state := h.beads
+	h.mu.Unlock()
+
+	e.family("gt_beads_stale", "gauge", "Whether the bd-derived metrics are from an earlier scrape (or missing) because bd failed or timed out.")
+	e.sample("gt_beads_stale", nil, boolValue(state.at.Before(scrapeStart)))
+	if state.at.IsZero() {
+		return
+	}
+	e.family("gt_beads_last_success_timestamp_seconds", "gauge", "When the bd-derived metrics were last fetched.")
+	e.sample("gt_beads_last_success_timestamp_seconds", nil, float64(state.at.UnixMilli())/1000)
+
+	severities := make(map[string]int)
+	for _, sev := range config.ValidSeverities() {
+		severities[sev] = 0
+	}
+	for sev, n := range state.escalations {
+		severities[sev] = n
+	}
+	e.family("gt_escalations_open", "gauge", "Open escalations by severity.")
+	e.samples("gt_escalations_open", "severity", severities)
+
+	e.family("gt_queue_depth", "gauge", "Unclaimed messages by mail queue.")
+	e.samples("gt_queue_depth", "queue", state.queues)
+
+	if hl := state.health; hl != nil {
+		e.family("gt_bd_daemons", "gauge", "bd daemons by health status.")
+		e.samples("gt_bd_daemons", "status", map[string]int{
+			"healthy":      hl.Healthy,
+			"stale":        hl.Stale,
+			"mismatched":   hl.Mismatched,
+			"unresponsive": hl.Unresponsive,
+		})
+	}
+}
+
+// exposition builds a text exposition document.
+type exposition struct {
+	b strings.Builder
+}
+
+func (e *exposition) family(name, typ, help string) {
+	fmt.Fprintf(&e.b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes one sample; labels alternate names and values.
+func (e *exposition) sample(name string, labels []string, value float64) {
+	e.b.WriteString(name)
+	if len(labels) > 0 {
+		e.b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				e.b.WriteByte(',')
+			}
+			fmt.Fprintf(&e.b, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+		}
+		e.b.WriteByte('}')
+	}
+	fmt.Fprintf(&e.b, " %g\n", value)
+}
+
+// samples writes one sample per key of values, labelled label=key, in key order.
+func (e *exposition) samples(name, label string, values map[string]int) {
+	for _, k := range sortedKeys(values) {
+		e.sample(name, []string{label, k}, float64(values[k]))
+	}
+}
+
+func (e *exposition) String() string {
+	return e.b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
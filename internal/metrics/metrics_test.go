@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+)
+
+var errBdDown = errors.New("bd down")
+
+// MockSource is a fixture town. Setting BdDelay or BdError simulates a slow
+// or failing bd.
+type MockSource struct {
+	mu          sync.Mutex
+	SessionList []string
+	Events      []map[string]int // returned one per NewEvents call
+	Escalated   map[string]int
+	Queues      map[string]int
+	Health      *beads.BdDaemonHealth
+	BdError     error
+	BdDelay     time.Duration
+}
+
+func (m *MockSource) Sessions() ([]string, error) {
+	return m.SessionList, nil
+}
+
+func (m *MockSource) NewEvents() (map[string]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.Events) == 0 {
+		return nil, nil
+	}
+	counts := m.Events[0]
+	m.Events = m.Events[1:]
+	return counts, nil
+}
+
+func (m *MockSource) bd() error {
+	m.mu.Lock()
+	delay, err := m.BdDelay, m.BdError
+	m.mu.Unlock()
+	time.Sleep(delay)
+	return err
+}
+
+func (m *MockSource) Escalations() (map[string]int, error) {
+	if err := m.bd(); err != nil {
+		return nil, err
+	}
+	return m.Escalated, nil
+}
+
+func (m *MockSource) QueueDepths() (map[string]int, error) {
+	return m.Queues, nil
+}
+
+func (m *MockSource) BdDaemonHealth() (*beads.BdDaemonHealth, error) {
+	return m.Health, nil
+}
+
+func (m *MockSource) setBd(delay time.Duration, err error) {
+	m.mu.Lock()
+	m.BdDelay, m.BdError = delay, err
+	m.mu.Unlock()
+}
+
+func fixtureSource() *MockSource {
+	return &MockSource{
+		SessionList: []string{
+			"hq-mayor", "hq-deacon",
+			"gt-gongshow-witness", "gt-gongshow-refinery",
+			"gt-gongshow-Toast", "gt-gongshow-Nux", "gt-gongshow-crew-joe",
+			"scratch", // not an agent session
+		},
+		Events: []map[string]int{
+			{"sling": 3, "done": 1},
+			{"sling": 2},
+		},
+		Escalated: map[string]int{"critical": 1, "medium": 2},
+		Queues:    map[string]int{"reviews": 4},
+		Health:    &beads.BdDaemonHealth{Total: 2, Healthy: 1, Stale: 1},
+	}
+}
+
+func scrape(t *testing.T, h *Handler) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func assertSamples(t *testing.T, body string, want ...string) {
+	t.Helper()
+	lines := make(map[string]bool)
+	for _, line := range strings.Split(body, "\n") {
+		lines[line] = true
+	}
+	for _, w := range want {
+		if !lines[w] {
+			t.Errorf("missing %q in:\n%s", w, body)
+		}
+	}
+}
+
+func TestHandler_Metrics(t *testing.T) {
+	h := NewHandler(fixtureSource(), time.Second)
+	body := scrape(t, h)
+
+	assertSamples(t, body,
+		"# TYPE gt_sessions gauge",
+		`gt_sessions{role="mayor"} 1`,
+		`gt_sessions{role="deacon"} 1`,
+		`gt_sessions{role="witness"} 1`,
+		`gt_sessions{role="refinery"} 1`,
+		`gt_sessions{role="polecat"} 2`,
+		`gt_sessions{role="crew"} 1`,
+		"gt_tmux_up 1",
+
+		"# TYPE gt_escalations_open gauge",
+		`gt_escalations_open{severity="critical"} 1`,
+		`gt_escalations_open{severity="medium"} 2`,
+		`gt_escalations_open{severity="high"} 0`,
+		`gt_escalations_open{severity="low"} 0`,
+
+		"# TYPE gt_queue_depth gauge",
+		`gt_queue_depth{queue="reviews"} 4`,
+
+		"# TYPE gt_events_total counter",
+		`gt_events_total{type="sling"} 3`,
+		`gt_events_total{type="done"} 1`,
+		"# TYPE gt_events_last_interval gauge",
+		`gt_events_last_interval{type="sling"} 3`,
+
+		`gt_bd_daemons{status="healthy"} 1`,
+		`gt_bd_daemons{status="stale"} 1`,
+		"gt_beads_stale 0",
+	)
+
+	// The next scrape counts only the new events; the counter accumulates.
+	body = scrape(t, h)
+	assertSamples(t, body,
+		`gt_events_total{type="sling"} 5`,
+		`gt_events_total{type="done"} 1`,
+		`gt_events_last_interval{type="sling"} 2`,
+		`gt_events_last_interval{type="done"} 0`,
+	)
+}
+
+func TestHandler_BdDown(t *testing.T) {
+	src := fixtureSource()
+	h := NewHandler(src, time.Second)
+	scrape(t, h)
+
+	// bd failing keeps the last values, flagged stale.
+	src.setBd(0, errBdDown)
+	body := scrape(t, h)
+	assertSamples(t, body,
+		"gt_beads_stale 1",
+		`gt_escalations_open{severity="critical"} 1`,
+		`gt_sessions{role="polecat"} 2`,
+	)
+}
+
+func TestHandler_BdDownFromStart(t *testing.T) {
+	src := fixtureSource()
+	src.BdError = errBdDown
+	body := scrape(t, NewHandler(src, time.Second))
+
+	assertSamples(t, body, "gt_beads_stale 1", "gt_tmux_up 1")
+	if strings.Contains(body, "gt_escalations_open") {
+		t.Errorf("exported escalations without ever reaching bd:\n%s", body)
+	}
+}
+
+func TestHandler_SlowBdDoesNotBlockScrape(t *testing.T) {
+	src := fixtureSource()
+	h := NewHandler(src, 50*time.Millisecond)
+	scrape(t, h)
+
+	src.setBd(300*time.Millisecond, nil)
+	start := time.Now()
+	body := scrape(t, h)
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("scrape took %v waiting on bd", elapsed)
+	}
+	assertSamples(t, body, "gt_beads_stale 1", `gt_queue_depth{queue="reviews"} 4`)
+
+	// Once the slow refresh lands, the next scrape is fresh again.
+	src.setBd(0, nil)
+	time.Sleep(350 * time.Millisecond)
+	assertSamples(t, scrape(t, h), "gt_beads_stale 0")
+}
+
+func TestEscapeLabel(t *testing.T) {
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Errorf("escapeLabel = %s", got)
+	}
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+// LiveSource reads metrics from a running town.
+type LiveSource struct {
+	tmux   *tmux.Tmux
+	beads  *beads.Beads
+	tailer *events.Tailer
+}
+
+// NewLiveSource creates a source for the town at townRoot. Event counts
+// start from the current end of the events logs.
+func NewLiveSource(townRoot string) (*LiveSource, error) {
+	tailer, err := events.NewTailer(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("following events: %w", err)
+	}
+	return &LiveSource{
+		tmux:   tmux.NewTmux(),
+		beads:  beads.NewWithBeadsDir(townRoot, beads.ResolveBeadsDir(townRoot)),
+		tailer: tailer,
+	}, nil
+}
+
+// Close stops following the events logs.
+func (s *LiveSource) Close() error {
+	return s.tailer.Close()
+}
+
+// Sessions lists the live tmux sessions.
+func (s *LiveSource) Sessions() ([]string, error) {
+	set, err := s.tmux.GetSessionSet()
+	if err != nil {
+		return nil, err
+	}
+	return set.Names(), nil
+}
+
+// NewEvents counts, by type, the events appended since the last call.
+func (s *LiveSource) NewEvents() (map[string]int, error) {
+	return countEventTypes(s.tailer.Lines()), nil
+}
+
+// Escalations counts open escalation beads by severity.
+func (s *LiveSource) Escalations() (map[string]int, error) {
+	issues, err := s.beads.ListEscalations()
+	if err != nil {
+		return nil, err
+	}
+	return countBySeverity(issues), nil
+}
+
+// QueueDepths counts unclaimed open messages in each mail queue.
+func (s *LiveSource) QueueDepths() (map[string]int, error) {
+	out, err := s.beads.Run("list", "--type", "message", "--status", "open", "--json")
+	if err != nil {
+		return nil, err
+	}
+	var issues []*beads.Issue
+	if trimmed := strings.TrimSpace(string(out)); trimmed != "" {
+		if err := json.Unmarshal(out, &issues); err != nil {
+			return nil, fmt.Errorf("parsing bd list output: %w", err)
+		}
+	}
+	return countQueueDepths(issues), nil
+}
+
+// BdDaemonHealth reports bd daemon health.
+func (s *LiveSource) BdDaemonHealth() (*beads.BdDaemonHealth, error) {
+	return beads.CheckBdDaemonHealth()
+}
+
+// countEventTypes counts event lines by type, skipping unparseable ones.
+func countEventTypes(lines []string) map[string]int {
+	counts := make(map[string]int)
+	for _, line := range lines {
+		var e struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal([]byte(line), &e) == nil && e.Type != "" {
+			counts[e.Type]++
+		}
+	}
+	return counts
+}
+
+// countBySeverity counts escalations by their severity label, falling back
+// to the severity recorded in the description.
+func countBySeverity(issues []*beads.Issue) map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range issues {
+		severity := ""
+		for _, label := range issue.Labels {
+			if sev, ok := strings.CutPrefix(label, "severity:"); ok {
+				severity = sev
+				break
+			}
+		}
+		if severity == "" {
+			severity = beads.ParseEscalationFields(issue.Description).Severity
+		}
+		if severity == "" {
+			severity = "unknown"
+		}
+		counts[severity]++
+	}
+	return counts
+}
+
+// countQueueDepths counts messages by their queue label, skipping those
+// already claimed.
+func countQueueDepths(issues []*beads.Issue) map[string]int {
+	depths := make(map[string]int)
+	for _, issue := range issues {
+		queue, claimed := "", false
+		for _, label := range issue.Labels {
+			if name, ok := strings.CutPrefix(label, "queue:"); ok {
+				queue = name
+			} else if strings.HasPrefix(label, "claimed-by:") {
+				claimed = true
+			}
+		}
+		if queue != "" && !claimed {
+			depths[queue]++
+		}
+	}
+	return depths
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/beads"
+)
+
+func TestCountBySeverity(t *testing.T) {
+	issues := []*beads.Issue{
+		{Labels: []string{"gt:escalation", "severity:critical"}},
+		{Labels: []string{"gt:escalation", "severity:high"}},
+		{Labels: []string{"gt:escalation"}, Description: "Disk full\n\nseverity: high\nreason: df"},
+		{Labels: []string{"gt:escalation"}},
+	}
+	want := map[string]int{"critical": 1, "high": 2, "unknown": 1}
+	if got := countBySeverity(issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("countBySeverity = %v, want %v", got, want)
+	}
+}
+
+func TestCountQueueDepths(t *testing.T) {
+	issues := []*beads.Issue{
+		{Labels: []string{"from:mayor", "queue:reviews"}},
+		{Labels: []string{"queue:reviews", "claimed-by:gongshow/Toast"}},
+		{Labels: []string{"queue:reviews"}},
+		{Labels: []string{"queue:triage"}},
+		{Labels: []string{"from:mayor"}}, // direct mail, not queued
+	}
+	want := map[string]int{"reviews": 2, "triage": 1}
+	if got := countQueueDepths(issues); !reflect.DeepEqual(got, want) {
+		t.Errorf("countQueueDepths = %v, want %v", got, want)
+	}
+}
+
+func TestCountEventTypes(t *testing.T) {
+	lines := []string{
+		`{"ts":"2024-01-15T00:00:00Z","type":"sling","actor":"mayor/"}`,
+		`{"ts":"2024-01-15T00:01:00Z","type":"sling","actor":"mayor/"}`,
+		`{"ts":"2024-01-15T00:02:00Z","type":"done","actor":"gongshow/polecats/Toast"}`,
+		`not json`,
+	}
+	want := map[string]int{"sling": 2, "done": 1}
+	if got := countEventTypes(lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("countEventTypes = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,255 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+)
+
+// Channel names accepted in Target.Channel and notify.json.
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelSlack    = "slack"
+	ChannelTeams    = "teams"
+	ChannelOpsGenie = "opsgenie"
+	ChannelLog      = "log"
+)
+
+// Target is one delivery: a channel and the address to send to on it.
+// Address is unused for the log and opsgenie channels.
+type Target struct {
+	Channel string `json:"channel"`
+	Address string `json:"address,omitempty"`
+}
+
+// Dispatcher sends notifications subject to the town's notify.json policy.
+type Dispatcher struct {
+	townRoot string
+	config   *Config
+	limiter  *rateLimiter
+	spool    *Spool
+
+	now  func() time.Time
+	send func(townRoot string, t Target, n *Notification) *Result
+}
+
+// NewDispatcher creates a dispatcher for a town, loading settings/notify.json.
+func NewDispatcher(townRoot string) (*Dispatcher, error) {
+	cfg, err := LoadConfig(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return &Dispatcher{
+		townRoot: townRoot,
+		config:   cfg,
+		limiter:  newRateLimiter(townRoot),
+		spool:    NewSpool(townRoot),
+		now:      time.Now,
+		send:     sendTarget,
+	}, nil
+}
+
+// SendAll delivers n to every target, enforcing per-channel rate limits and
+// quiet hours. Sends that are deferred go into the retry spool and are
+// reported with Result.Deferred set.
+func SendAll(townRoot string, targets []Target, n *Notification) []*Result {
+	d, err := NewDispatcher(townRoot)
+	if err != nil {
+		return []*Result{{
+			Channel: "config",
+			Success: false,
+			Error:   err,
+			Message: err.Error(),
+		}}
+	}
+	return d.SendAll(targets, n)
+}
+
+// SendNow delivers n to every target straight away, ignoring quiet hours
+// and rate limits, and never spools. It is for checks a person is waiting
+// on, such as "gt notify test"; deliveries are still recorded in history.
+func SendNow(townRoot string, targets []Target, n *Notification) []*Result {
+	d, err := NewDispatcher(townRoot)
+	if err != nil {
+		return []*Result{{
+			Channel: "config",
+			Success: false,
+			Error:   err,
+			Message: err.Error(),
+		}}
+	}
+	return d.SendNow(targets, n)
+}
+
+// FlushSpool retries the town's spooled sends whose not-before time has passed.
+func FlushSpool(townRoot string) ([]*Result, error) {
+	d, err := NewDispatcher(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return d.FlushSpool()
+}
+
+// SendAll delivers n to every target. See the package-level SendAll.
+func (d *Dispatcher) SendAll(targets []Target, n *Notification) []*Result {
+	results := make([]*Result, 0, len(targets))
+	for _, t := range targets {
+		results = append(results, d.Send(t, n))
+	}
+	return results
+}
+
+// Send delivers n to a single target, spooling it if policy defers the send.
+func (d *Dispatcher) Send(t Target, n *Notification) *Result {
+	result, deferred := d.dispatch(t, n)
+	if deferred != nil {
+		if err := d.spool.Add(deferred); err != nil {
+			return &Result{
+				Channel: t.Channel,
+				Success: false,
+				Error:   err,
+				Message: fmt.Sprintf("Deferred (%s) but could not spool: %v", deferred.Reason, err),
+			}
+		}
+	}
+	return result
+}
+
+// SendNow delivers n to every target without applying policy. See the
+// package-level SendNow.
+func (d *Dispatcher) SendNow(targets []Target, n *Notification) []*Result {
+	results := make([]*Result, 0, len(targets))
+	for _, t := range targets {
+		start := time.Now()
+		result := d.send(d.townRoot, t, n)
+		d.record(t, n, result, time.Since(start))
+		results = append(results, result)
+	}
+	return results
+}
+
+// FlushSpool sends every due spool entry that policy now allows. Entries that
+// are still blocked are re-deferred; everything else leaves the spool whether
+// or not the send succeeded.
+func (d *Dispatcher) FlushSpool() ([]*Result, error) {
+	due, err := d.spool.Due(d.now())
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*Result
+	for _, entry := range due {
+		entry.Attempts++
+		result, deferred := d.dispatch(entry.Target, entry.Notification)
+		if deferred != nil {
+			entry.NotBefore = deferred.NotBefore
+			entry.Reason = deferred.Reason
+			if err := d.spool.Add(entry); err != nil {
+				return results, err
+			}
+		} else if err := d.spool.Remove(entry); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// dispatch applies quiet hours then rate limits, and sends if both allow it.
+// When the send must wait, it returns the spool entry to enqueue.
+func (d *Dispatcher) dispatch(t Target, n *Notification) (*Result, *SpoolEntry) {
+	now := d.now()
+	policy := d.config.policy(t.Channel)
+
+	if policy != nil && policy.QuietHours != nil && !policy.QuietHours.Breaks(n.Severity) {
+		if until, quiet := policy.QuietHours.QuietUntil(now); quiet {
+			return d.deferTo(t, n, until, "quiet hours")
+		}
+	}
+
+	if policy != nil && policy.RateLimit != nil {
+		ok, retryAt, err := d.limiter.reserve(t.Channel, policy.RateLimit, now)
+		if err != nil {
+			return &Result{
+				Channel: t.Channel,
+				Success: false,
+				Error:   err,
+				Message: fmt.Sprintf("Rate limit check failed: %v", err),
+			}, nil
+		}
+		if !ok {
+			return d.deferTo(t, n, retryAt, "rate limited")
+		}
+	}
+
+	start := time.Now()
+	result := d.send(d.townRoot, t, n)
+	d.record(t, n, result, time.Since(start))
+	return result, nil
+}
+
+// record appends a delivery attempt to the town's notify history.
+// History is best-effort: a failure to record never fails the send.
+func (d *Dispatcher) record(t Target, n *Notification, result *Result, latency time.Duration) {
+	entry := &HistoryEntry{
+		Timestamp:  d.now(),
+		Escalation: n.ID,
+		Severity:   n.Severity,
+		Channel:    t.Channel,
+		Recipient:  maskRecipient(t.Channel, t.Address),
+		Success:    result.Success,
+		Deferred:   result.Deferred,
+		LatencyMs:  latency.Milliseconds(),
+	}
+	if result.Error != nil {
+		entry.Error = maskAddress(t.Channel, t.Address, result.Error.Error())
+	} else if !result.Success {
+		entry.Error = maskAddress(t.Channel, t.Address, result.Message)
+	}
+	_ = RecordHistory(d.townRoot, entry)
+}
+
+func (d *Dispatcher) deferTo(t Target, n *Notification, notBefore time.Time, reason string) (*Result, *SpoolEntry) {
+	entry := &SpoolEntry{
+		Target:       t,
+		Notification: n,
+		NotBefore:    notBefore,
+		Reason:       reason,
+		CreatedAt:    d.now(),
+	}
+	result := &Result{
+		Channel:   t.Channel,
+		Success:   true,
+		Deferred:  true,
+		NotBefore: notBefore,
+		Message:   fmt.Sprintf("Deferred until %s (%s)", notBefore.Local().Format("Mon 15:04"), reason),
+	}
+	d.record(t, n, result, 0)
+	return result, entry
+}
+
+// sendTarget delivers to a single target on its channel.
+func sendTarget(townRoot string, t Target, n *Notification) *Result {
+	switch t.Channel {
+	case ChannelEmail:
+		return SendEmail(t.Address, n)
+	case ChannelSMS:
+		return SendSMS(t.Address, n)
+	case ChannelSlack:
+		return SendSlack(t.Address, n)
+	case ChannelTeams:
+		return SendTeams(t.Address, n)
+	case ChannelOpsGenie:
+		return SendOpsGenie(LoadOpsGenieConfig(), n)
+	case ChannelLog:
+		return WriteLog(townRoot, n)
+	default:
+		err := fmt.Errorf("unknown channel %q", t.Channel)
+		return &Result{
+			Channel: t.Channel,
+			Success: false,
+			Error:   err,
+			Message: err.Error(),
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestDispatcher returns a dispatcher over a temp town that records sends
+// instead of delivering them.
+func newTestDispatcher(t *testing.T, configJSON string, now time.Time) (*Dispatcher, *[]Target) {
+	t.Helper()
+	townRoot := t.TempDir()
+	if configJSON != "" {
+		path := ConfigPath(townRoot)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(configJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := NewDispatcher(townRoot)
+	if err != nil {
+		t.Fatalf("NewDispatcher: %v", err)
+	}
+	var sent []Target
+	d.now = func() time.Time { return now }
+	d.send = func(_ string, target Target, n *Notification) *Result {
+		sent = append(sent, target)
+		return &Result{Channel: target.Channel, Success: true, Message: "sent"}
+	}
+	return d, &sent
+}
+
+const quietSMSConfig = `{
+  "type": "notify",
+  "version": 1,
+  "channels": {
+    "sms": {
+      "quiet_hours": {
+        "timezone": "UTC",
+        "schedule": [{"days": ["mon", "tue", "wed", "thu", "fri", "sat", "sun"], "start": "22:00", "end": "07:00"}]
+      }
+    }
+  }
+}`
+
+// 03:00 UTC on a Wednesday: inside the overnight window that started Tuesday.
+var threeAM = time.Date(2026, 3, 4, 3, 0, 0, 0, time.UTC)
+
+func TestSendAll_QuietHoursCriticalBreaksThrough(t *testing.T) {
+	d, sent := newTestDispatcher(t, quietSMSConfig, threeAM)
+
+	n := &Notification{ID: "hq-1", Severity: "critical", Title: "Refinery down"}
+	results := d.SendAll([]Target{{Channel: ChannelSMS, Address: "+15551234567"}}, n)
+
+	if len(results) != 1 || !results[0].Success || results[0].Deferred {
+		t.Fatalf("critical should send during quiet hours, got %+v", results[0])
+	}
+	if len(*sent) != 1 {
+		t.Errorf("expected 1 send, got %d", len(*sent))
+	}
+	if entries, _ := d.spool.List(); len(entries) != 0 {
+		t.Errorf("nothing should be spooled, got %d entries", len(entries))
+	}
+}
+
+func TestSendAll_QuietHoursSpoolsMedium(t *testing.T) {
+	d, sent := newTestDispatcher(t, quietSMSConfig, threeAM)
+
+	n := &Notification{ID: "hq-2", Severity: "medium", Title: "Stale polecat"}
+	results := d.SendAll([]Target{{Channel: ChannelSMS, Address: "+15551234567"}}, n)
+
+	if len(results) != 1 || !results[0].Deferred {
+		t.Fatalf("medium should be deferred during quiet hours, got %+v", results[0])
+	}
+	if len(*sent) != 0 {
+		t.Errorf("deferred notification should not be sent, got %d sends", len(*sent))
+	}
+
+	entries, err := d.spool.List()
+	if err != nil {
+		t.Fatalf("spool.List: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 spooled entry, got %d", len(entries))
+	}
+	want := time.Date(2026, 3, 4, 7, 0, 0, 0, time.UTC)
+	if !entries[0].NotBefore.Equal(want) {
+		t.Errorf("NotBefore = %v, want %v", entries[0].NotBefore, want)
+	}
+	if entries[0].Notification.ID != "hq-2" || entries[0].Target.Channel != ChannelSMS {
+		t.Errorf("unexpected spool entry: %+v", entries[0])
+	}
+
+	// Not yet due: flushing is a no-op.
+	if results, _ := d.FlushSpool(); len(results) != 0 {
+		t.Errorf("flush before NotBefore sent %d", len(results))
+	}
+
+	// After quiet hours end the flush delivers it and empties the spool.
+	d.now = func() time.Time { return want.Add(time.Minute) }
+	results, err = d.FlushSpool()
+	if err != nil {
+		t.Fatalf("FlushSpool: %v", err)
+	}
+	if len(results) != 1 || !results[0].Success || results[0].Deferred {
+		t.Fatalf("expected spooled send to succeed, got %+v", results)
+	}
+	if entries, _ := d.spool.List(); len(entries) != 0 {
+		t.Errorf("spool should be empty after flush, got %d", len(entries))
+	}
+}
+
+func TestSendNow_IgnoresQuietHours(t *testing.T) {
+	d, sent := newTestDispatcher(t, quietSMSConfig, threeAM)
+
+	n := &Notification{ID: "notify-test-1", Severity: "medium", Title: "TEST"}
+	results := d.SendNow([]Target{{Channel: ChannelSMS, Address: "+15551234567"}}, n)
+
+	if len(results) != 1 || !results[0].Success || results[0].Deferred {
+		t.Fatalf("SendNow should send during quiet hours, got %+v", results[0])
+	}
+	if len(*sent) != 1 {
+		t.Errorf("expected 1 send, got %d", len(*sent))
+	}
+	if entries, _ := d.spool.List(); len(entries) != 0 {
+		t.Errorf("nothing should be spooled, got %d entries", len(entries))
+	}
+}
+
+func TestSendAll_RateLimitPersists(t *testing.T) {
+	cfg := `{"channels": {"slack": {"rate_limit": {"per_minute": 2}}}}`
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	d, sent := newTestDispatcher(t, cfg, now)
+	target := []Target{{Channel: ChannelSlack, Address: "https://hooks.example/x"}}
+	n := &Notification{ID: "hq-3", Severity: "high"}
+
+	d.SendAll(target, n)
+	d.SendAll(target, n)
+
+	// A fresh dispatcher (new gt invocation) sees the persisted sends.
+	d2, err := NewDispatcher(d.townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2.now, d2.send = d.now, d.send
+	results := d2.SendAll(target, n)
+
+	if len(*sent) != 2 {
+		t.Errorf("expected 2 sends within the limit, got %d", len(*sent))
+	}
+	if !results[0].Deferred {
+		t.Fatalf("third send should be rate limited, got %+v", results[0])
+	}
+	if want := now.Add(time.Minute); !results[0].NotBefore.Equal(want) {
+		t.Errorf("NotBefore = %v, want %v", results[0].NotBefore, want)
+	}
+}
+
+func TestSendAll_NoConfigSendsImmediately(t *testing.T) {
+	d, sent := newTestDispatcher(t, "", threeAM)
+	d.SendAll([]Target{{Channel: ChannelSMS}, {Channel: ChannelLog}}, &Notification{Severity: "low"})
+	if len(*sent) != 2 {
+		t.Errorf("expected 2 sends, got %d", len(*sent))
+	}
+}
+
+func TestQuietUntil(t *testing.T) {
+	q := &QuietHours{
+		Timezone: "UTC",
+		Schedule: []QuietWindow{
+			{Days: []string{"mon"}, Start: "22:00", End: "07:00"},
+			{Days: []string{"sat"}, Start: "00:00", End: "24:00"},
+		},
+	}
+	if err := q.compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	mon := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) // Monday
+	tests := []struct {
+		name  string
+		at    time.Time
+		quiet bool
+		until time.Time
+	}{
+		{"monday evening before window", mon.Add(21 * time.Hour), false, time.Time{}},
+		{"monday late", mon.Add(23 * time.Hour), true, mon.Add(31 * time.Hour)},
+		{"tuesday early (wrapped)", mon.Add(30 * time.Hour), true, mon.Add(31 * time.Hour)},
+		{"tuesday after end", mon.Add(31 * time.Hour), false, time.Time{}},
+		{"monday early (sunday not scheduled)", mon.Add(3 * time.Hour), false, time.Time{}},
+		{"saturday all day", mon.AddDate(0, 0, 5).Add(15 * time.Hour), true, mon.AddDate(0, 0, 6)},
+	}
+	for _, tt := range tests {
+		until, quiet := q.QuietUntil(tt.at)
+		if quiet != tt.quiet || !until.Equal(tt.until) {
+			t.Errorf("%s: QuietUntil = (%v, %v), want (%v, %v)", tt.name, until, quiet, tt.until, tt.quiet)
+		}
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad day":          `{"channels": {"sms": {"quiet_hours": {"schedule": [{"days": ["funday"], "start": "22:00", "end": "07:00"}]}}}}`,
+		"bad clock":        `{"channels": {"sms": {"quiet_hours": {"schedule": [{"days": ["mon"], "start": "25:00", "end": "07:00"}]}}}}`,
+		"bad breakthrough": `{"channels": {"sms": {"quiet_hours": {"breakthrough": "urgent", "schedule": []}}}}`,
+		"negative limit":   `{"channels": {"slack": {"rate_limit": {"per_minute": -1}}}}`,
+	}
+	for name, data := range tests {
+		townRoot := t.TempDir()
+		path := ConfigPath(townRoot)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+		_ = os.WriteFile(path, []byte(data), 0644)
+		if _, err := LoadConfig(townRoot); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
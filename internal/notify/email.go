@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// emailHTMLTemplate renders the HTML alternative of an escalation email.
+// Inline styles only: most mail clients strip <style> blocks.
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:0;font-family:-apple-system,Helvetica,Arial,sans-serif;color:#222;">
+<div style="background:{{.Color}};color:#ffffff;padding:14px 18px;font-size:18px;font-weight:bold;">{{.Emoji}} {{.Severity}} &middot; {{.Title}}</div>
+<div style="padding:16px 18px;">
+<table style="border-collapse:collapse;font-size:14px;">
+<tr><th style="text-align:left;padding:4px 12px 4px 0;color:#666;">ID</th><td style="padding:4px 0;">{{.ID}}</td></tr>
+<tr><th style="text-align:left;padding:4px 12px 4px 0;color:#666;">Severity</th><td style="padding:4px 0;">{{.Severity}}</td></tr>
+<tr><th style="text-align:left;padding:4px 12px 4px 0;color:#666;">Time</th><td style="padding:4px 0;">{{.Time}}</td></tr>
+<tr><th style="text-align:left;padding:4px 12px 4px 0;color:#666;">Source</th><td style="padding:4px 0;">{{.Source}}</td></tr>
+{{- if .RelatedBead}}
+<tr><th style="text-align:left;padding:4px 12px 4px 0;color:#666;">Related bead</th><td style="padding:4px 0;">{{.RelatedBead}}</td></tr>
+{{- end}}
+</table>
+{{- if .Body}}
+<pre style="white-space:pre-wrap;font-family:inherit;font-size:14px;margin:16px 0;">{{.Body}}</pre>
+{{- end}}
+<p style="margin:16px 0 4px;font-size:14px;">To acknowledge:</p>
+<pre style="background:#f4f4f4;padding:8px 12px;border-radius:4px;margin:0;"><code>gt escalate ack {{.ID}}</code></pre>
+<p style="margin:16px 0 4px;font-size:14px;">To close:</p>
+<pre style="background:#f4f4f4;padding:8px 12px;border-radius:4px;margin:0;"><code>gt escalate close {{.ID}} --reason "resolution"</code></pre>
+</div>
+</body>
+</html>
+`))
+
+// emailHTMLData is the view model for emailHTMLTemplate.
+type emailHTMLData struct {
+	ID          string
+	Severity    string
+	Title       string
+	Body        string
+	Source      string
+	RelatedBead string
+	Time        string
+	Color       string
+	Emoji       string
+}
+
+// buildEmailHTML renders the HTML body for a notification. All user-supplied
+// fields are escaped by html/template.
+func buildEmailHTML(n *Notification) (string, error) {
+	var buf bytes.Buffer
+	err := emailHTMLTemplate.Execute(&buf, emailHTMLData{
+		ID:          n.ID,
+		Severity:    strings.ToUpper(n.Severity),
+		Title:       n.Title,
+		Body:        n.Body,
+		Source:      n.Source,
+		RelatedBead: n.RelatedBead,
+		Time:        n.Timestamp.Format(time.RFC1123),
+		Color:       severityColor(n.Severity),
+		Emoji:       severityEmoji(n.Severity),
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering HTML email: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// buildEmailSubject returns the subject line for an escalation email.
+func buildEmailSubject(n *Notification) string {
+	return fmt.Sprintf("[%s] Escalation: %s", strings.ToUpper(n.Severity), n.Title)
+}
+
+// buildEmailMessage assembles the full RFC 5322 message for a notification.
+// Unless cfg.PlainTextOnly is set, the body is multipart/alternative with
+// the plain-text part first and the HTML part second, so clients that
+// cannot render HTML fall back to text.
+func buildEmailMessage(cfg *SMTPConfig, to string, n *Notification) ([]byte, error) {
+	subject := buildEmailSubject(n)
+
+	var msg bytes.Buffer
+	writeHeader := func(key, value string) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", key, sanitizeHeader(value))
+	}
+	writeHeader("From", cfg.From)
+	writeHeader("To", to)
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", sanitizeHeader(subject)))
+	writeHeader("Date", n.Timestamp.Format(time.RFC1123Z))
+	writeHeader("MIME-Version", "1.0")
+	writeHeader("X-GongShow-Escalation", n.ID)
+	writeHeader("X-GongShow-Severity", n.Severity)
+
+	text := buildEmailBody(n)
+
+	if cfg.PlainTextOnly {
+		writeHeader("Content-Type", "text/plain; charset=utf-8")
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		msg.WriteString("\r\n")
+		if err := writeQuotedPrintable(&msg, text); err != nil {
+			return nil, err
+		}
+		return msg.Bytes(), nil
+	}
+
+	html, err := buildEmailHTML(n)
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	writeHeader("Content-Type", "multipart/alternative; boundary="+mw.Boundary())
+	msg.WriteString("\r\n")
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	}
+	for _, p := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating MIME part: %w", err)
+		}
+		if err := writeQuotedPrintable(w, p.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, fmt.Errorf("closing MIME writer: %w", err)
+	}
+
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// writeQuotedPrintable writes s to w with quoted-printable encoding, which
+// keeps lines under the SMTP length limit and is safe for any UTF-8 content.
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(s, "\n", "\r\n"))); err != nil {
+		return fmt.Errorf("encoding body: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("encoding body: %w", err)
+	}
+	return nil
+}
+
+// sanitizeHeader strips CR and LF so user content cannot inject headers.
+func sanitizeHeader(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
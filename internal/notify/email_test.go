@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildEmailMessageMultipart(t *testing.T) {
+	n := &Notification{
+		ID:          "esc-007",
+		Severity:    "critical",
+		Title:       "Refinery <script>alert(1)</script> stuck",
+		Body:        "Merge queue blocked for 2h.\nLine with ünïcödé and a very long line that goes past the seventy-six character quoted-printable limit.",
+		Source:      "gongshow/witness",
+		RelatedBead: "gt-abc",
+		Timestamp:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	cfg := &SMTPConfig{From: "gongshow@example.com"}
+
+	raw, err := buildEmailMessage(cfg, "oncall@example.com", n)
+	if err != nil {
+		t.Fatalf("buildEmailMessage: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parsing message: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decoding subject: %v", err)
+	}
+	if subject != "[CRITICAL] Escalation: "+n.Title {
+		t.Errorf("subject = %q", subject)
+	}
+	if got := msg.Header.Get("X-GongShow-Escalation"); got != "esc-007" {
+		t.Errorf("X-GongShow-Escalation = %q", got)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parsing content type: %v", err)
+	}
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q, want multipart/alternative", mediaType)
+	}
+
+	// multipart.Reader transparently decodes quoted-printable parts.
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	var types []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading part: %v", err)
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatalf("reading part body: %v", err)
+		}
+		types = append(types, p.Header.Get("Content-Type"))
+		parts = append(parts, strings.ReplaceAll(string(data), "\r\n", "\n"))
+	}
+
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 parts, got %d", len(parts))
+	}
+	if !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Errorf("part types = %v, want text/plain then text/html", types)
+	}
+
+	if parts[0] != buildEmailBody(n) {
+		t.Errorf("text part mismatch:\n got: %q\nwant: %q", parts[0], buildEmailBody(n))
+	}
+
+	html := parts[1]
+	for _, want := range []string{
+		"#FF0000", // critical banner color
+		"Refinery &lt;script&gt;alert(1)&lt;/script&gt; stuck",
+		"ünïcödé",
+		"gt-abc",
+		"gt escalate ack esc-007",
+		`gt escalate close esc-007 --reason "resolution"`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML part missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("HTML part contains unescaped user content")
+	}
+}
+
+func TestBuildEmailMessagePlainTextOnly(t *testing.T) {
+	n := &Notification{
+		ID:        "esc-008",
+		Severity:  "low",
+		Title:     "FYI",
+		Timestamp: time.Now(),
+	}
+	cfg := &SMTPConfig{From: "gongshow@example.com", PlainTextOnly: true}
+
+	raw, err := buildEmailMessage(cfg, "oncall@example.com", n)
+	if err != nil {
+		t.Fatalf("buildEmailMessage: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parsing message: %v", err)
+	}
+	if ct := msg.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("content type = %q, want text/plain", ct)
+	}
+	if strings.Contains(string(raw), "text/html") {
+		t.Error("plain-text-only message should not contain an HTML part")
+	}
+}
+
+func TestBuildEmailMessageHeaderInjection(t *testing.T) {
+	n := &Notification{
+		ID:        "esc-009",
+		Severity:  "high",
+		Title:     "evil\r\nBcc: attacker@example.com",
+		Timestamp: time.Now(),
+	}
+	cfg := &SMTPConfig{From: "gongshow@example.com"}
+
+	raw, err := buildEmailMessage(cfg, "oncall@example.com", n)
+	if err != nil {
+		t.Fatalf("buildEmailMessage: %v", err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parsing message: %v", err)
+	}
+	if bcc := msg.Header.Get("Bcc"); bcc != "" {
+		t.Errorf("title injected a Bcc header: %q", bcc)
+	}
+}
@@ -0,0 +1,209 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// History retention: once notify-history.jsonl exceeds MaxHistorySize it is
+// rotated to .1 (shifting older files up), keeping HistoryBackups old files.
+const (
+	MaxHistorySize = 5 * 1024 * 1024
+	HistoryBackups = 3
+)
+
+// HistoryEntry records one delivery attempt on one channel.
+type HistoryEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Escalation string    `json:"escalation,omitempty"`
+	Severity   string    `json:"severity,omitempty"`
+	Channel    string    `json:"channel"`
+	Recipient  string    `json:"recipient,omitempty"` // masked
+	Success    bool      `json:"success"`
+	Deferred   bool      `json:"deferred,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	LatencyMs  int64     `json:"latency_ms"`
+}
+
+// HistoryFilter selects history entries. Zero values match everything.
+type HistoryFilter struct {
+	Escalation string
+	Channel    string
+	FailedOnly bool
+	Since      time.Time
+}
+
+// HistoryPath returns the delivery history file for a town.
+func HistoryPath(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "notify-history.jsonl")
+}
+
+// RecordHistory appends an entry to the town's delivery history, rotating the
+// file first if it has grown past MaxHistorySize.
+func RecordHistory(townRoot string, entry *HistoryEntry) error {
+	return recordHistory(HistoryPath(townRoot), entry, MaxHistorySize)
+}
+
+func recordHistory(path string, entry *HistoryEntry, maxSize int64) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= maxSize {
+		rotateHistory(path)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+	return nil
+}
+
+// rotateHistory shifts path.N-1 → path.N ... path → path.1, dropping the oldest.
+func rotateHistory(path string) {
+	_ = os.Remove(fmt.Sprintf("%s.%d", path, HistoryBackups))
+	for i := HistoryBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+	}
+	_ = os.Rename(path, path+".1")
+}
+
+// ReadHistory returns matching history entries, oldest first, including
+// entries in rotated files.
+func ReadHistory(townRoot string, filter HistoryFilter) ([]*HistoryEntry, error) {
+	path := HistoryPath(townRoot)
+
+	var entries []*HistoryEntry
+	for i := HistoryBackups; i >= 0; i-- {
+		file := path
+		if i > 0 {
+			file = fmt.Sprintf("%s.%d", path, i)
+		}
+		fileEntries, err := readHistoryFile(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return FilterHistory(entries, filter), nil
+}
+
+func readHistoryFile(path string) ([]*HistoryEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+
+	var entries []*HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip malformed lines
+		}
+		entries = append(entries, &e)
+	}
+	return entries, scanner.Err()
+}
+
+// FilterHistory returns the entries matching filter.
+func FilterHistory(entries []*HistoryEntry, filter HistoryFilter) []*HistoryEntry {
+	var out []*HistoryEntry
+	for _, e := range entries {
+		if filter.Escalation != "" && e.Escalation != filter.Escalation {
+			continue
+		}
+		if filter.Channel != "" && e.Channel != filter.Channel {
+			continue
+		}
+		if filter.FailedOnly && (e.Success || e.Deferred) {
+			continue
+		}
+		if !filter.Since.IsZero() && e.Timestamp.Before(filter.Since) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// maskRecipient redacts a recipient address for the history file.
+func maskRecipient(channel, address string) string {
+	if address == "" {
+		return ""
+	}
+	switch channel {
+	case ChannelSMS:
+		return maskPhoneNumber(address)
+	case ChannelSlack, ChannelTeams:
+		return maskWebhookURL(address)
+	case ChannelEmail:
+		return maskEmail(address)
+	default:
+		return address
+	}
+}
+
+// maskAddress replaces address in text, as it appears in an error message,
+// with its masked form. A failed webhook post's *url.Error quotes the whole
+// URL, secret path included.
+func maskAddress(channel, address, text string) string {
+	if address == "" {
+		return text
+	}
+	masked := maskRecipient(channel, address)
+	text = strings.ReplaceAll(text, address, masked)
+	if quoted := strconv.Quote(address); quoted[1:len(quoted)-1] != address {
+		text = strings.ReplaceAll(text, quoted[1:len(quoted)-1], masked)
+	}
+	return text
+}
+
+// urlPattern matches a URL as quoted in a net/http error.
+var urlPattern = regexp.MustCompile(`https?://[^\s"]+`)
+
+// maskURLs masks every http(s) URL in text, for errors whose recipient is
+// no longer known: any of them may be a webhook.
+func maskURLs(text string) string {
+	return urlPattern.ReplaceAllStringFunc(text, maskWebhookURL)
+}
+
+// maskWebhookURL keeps the scheme and host of a webhook URL and masks the
+// secret-bearing path, leaving the last 4 characters for identification.
+func maskWebhookURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return maskPhoneNumber(raw)
+	}
+	return u.Scheme + "://" + u.Host + "/" + maskPhoneNumber(strings.TrimPrefix(u.EscapedPath(), "/"))
+}
+
+// maskEmail keeps the first character of the local part and the domain.
+func maskEmail(addr string) string {
+	local, domain, ok := strings.Cut(addr, "@")
+	if !ok || local == "" {
+		return maskPhoneNumber(addr)
+	}
+	return local[:1] + strings.Repeat("*", len(local)-1) + "@" + domain
+}
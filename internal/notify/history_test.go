@@ -0,0 +1,142 @@
+package notify
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeSyntheticHistory(t *testing.T, townRoot string, base time.Time) {
+	t.Helper()
+	entries := []*HistoryEntry{
+		{Timestamp: base.Add(-48 * time.Hour), Escalation: "hq-old", Channel: ChannelEmail, Success: true},
+		{Timestamp: base.Add(-2 * time.Hour), Escalation: "hq-1", Channel: ChannelSlack, Success: false, Error: "HTTP 429"},
+		{Timestamp: base.Add(-time.Hour), Escalation: "hq-1", Channel: ChannelSMS, Success: true},
+		{Timestamp: base.Add(-time.Hour), Escalation: "hq-2", Channel: ChannelSlack, Success: true, Deferred: true},
+		{Timestamp: base, Escalation: "hq-2", Channel: ChannelSlack, Success: true},
+	}
+	for _, e := range entries {
+		if err := RecordHistory(townRoot, e); err != nil {
+			t.Fatalf("RecordHistory: %v", err)
+		}
+	}
+}
+
+func TestReadHistoryFilters(t *testing.T) {
+	townRoot := t.TempDir()
+	base := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	writeSyntheticHistory(t, townRoot, base)
+
+	tests := []struct {
+		name   string
+		filter HistoryFilter
+		want   int
+	}{
+		{"no filter", HistoryFilter{}, 5},
+		{"escalation", HistoryFilter{Escalation: "hq-1"}, 2},
+		{"channel", HistoryFilter{Channel: ChannelSlack}, 3},
+		{"failed excludes deferred", HistoryFilter{FailedOnly: true}, 1},
+		{"since", HistoryFilter{Since: base.Add(-24 * time.Hour)}, 4},
+		{"combined", HistoryFilter{Channel: ChannelSlack, Escalation: "hq-2", Since: base.Add(-30 * time.Minute)}, 1},
+	}
+	for _, tt := range tests {
+		got, err := ReadHistory(townRoot, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: ReadHistory: %v", tt.name, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("%s: got %d entries, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+func TestMaskRecipient(t *testing.T) {
+	tests := []struct {
+		channel, address, want string
+	}{
+		{ChannelSMS, "+15551234567", "********4567"},
+		{ChannelSlack, "https://hooks.slack.com/services/T000/B000/XXXXabcd", "https://hooks.slack.com/" + strings.Repeat("*", 23) + "abcd"},
+		{ChannelEmail, "oncall@example.com", "o*****@example.com"},
+		{ChannelLog, "", ""},
+	}
+	for _, tt := range tests {
+		if got := maskRecipient(tt.channel, tt.address); got != tt.want {
+			t.Errorf("maskRecipient(%s, %q) = %q, want %q", tt.channel, tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestDispatcherRecordsMaskedHistory(t *testing.T) {
+	d, _ := newTestDispatcher(t, "", threeAM)
+	d.SendAll([]Target{
+		{Channel: ChannelSMS, Address: "+15551234567"},
+		{Channel: ChannelSlack, Address: "https://hooks.slack.com/services/T000/B000/secret"},
+	}, &Notification{ID: "hq-9", Severity: "high"})
+
+	data, err := os.ReadFile(HistoryPath(d.townRoot))
+	if err != nil {
+		t.Fatalf("reading history: %v", err)
+	}
+	if strings.Contains(string(data), "5551234567") || strings.Contains(string(data), "secret") {
+		t.Errorf("history leaked an unmasked recipient:\n%s", data)
+	}
+
+	entries, _ := ReadHistory(d.townRoot, HistoryFilter{Escalation: "hq-9"})
+	if len(entries) != 2 || !entries[0].Success {
+		t.Errorf("expected 2 successful entries, got %+v", entries)
+	}
+}
+
+func TestDispatcherMasksWebhookInErrors(t *testing.T) {
+	t.Setenv("GT_SLACK_BOT_TOKEN", "")
+	t.Setenv("GT_SLACK_CHANNEL", "")
+	// A closed server refuses the post, and the *url.Error quotes the URL.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	webhook := srv.URL + "/services/T000/B000/secret"
+	srv.Close()
+
+	d, _ := newTestDispatcher(t, "", threeAM)
+	d.send = sendTarget
+	results := d.SendAll([]Target{{Channel: ChannelSlack, Address: webhook}}, &Notification{ID: "hq-9", Severity: "high"})
+	if len(results) != 1 || results[0].Success || !strings.Contains(results[0].Error.Error(), "secret") {
+		t.Fatalf("expected a failed post whose error quotes the webhook, got %+v", results)
+	}
+
+	data, err := os.ReadFile(HistoryPath(d.townRoot))
+	if err != nil {
+		t.Fatalf("reading history: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("history leaked the webhook URL:\n%s", data)
+	}
+	entries, _ := ReadHistory(d.townRoot, HistoryFilter{Escalation: "hq-9"})
+	if len(entries) != 1 || entries[0].Error == "" {
+		t.Errorf("expected 1 failed entry with an error, got %+v", entries)
+	}
+}
+
+func TestHistoryRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "notify-history.jsonl")
+	for i := 0; i < 20; i++ {
+		if err := recordHistory(path, &HistoryEntry{Escalation: fmt.Sprintf("hq-%d", i)}, 200); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 1; i <= HistoryBackups; i++ {
+		if _, err := os.Stat(fmt.Sprintf("%s.%d", path, i)); err != nil {
+			t.Errorf("expected rotated file .%d: %v", i, err)
+		}
+	}
+	if _, err := os.Stat(fmt.Sprintf("%s.%d", path, HistoryBackups+1)); !os.IsNotExist(err) {
+		t.Errorf("more than %d backups kept", HistoryBackups)
+	}
+	if info, _ := os.Stat(path); info.Size() > 400 {
+		t.Errorf("active history not capped: %d bytes", info.Size())
+	}
+}
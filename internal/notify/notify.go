@@ -1,18 +1,22 @@
 // Package notify provides external notification channels for escalations.
-// Channels include email (SMTP), SMS (Twilio), Slack (webhook), and log files.
+// Channels include email (SMTP), SMS (Twilio), Slack and Microsoft Teams
+// (webhooks), OpsGenie alerts, and log files.
 package notify
 
 import (
 	"bytes"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"net/smtp"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Notification contains the data to send through notification channels.
@@ -28,10 +32,19 @@ type Notification struct {
 
 // Result captures the outcome of a notification attempt.
 type Result struct {
-	Channel string // email, sms, slack, log
+	Channel string // email, sms, slack, teams, opsgenie, log
 	Success bool
 	Error   error
 	Message string // Human-readable status
+
+	// Deferred is set when policy (quiet hours or a rate limit) held the
+	// send back; it was spooled and will be retried at NotBefore.
+	Deferred  bool
+	NotBefore time.Time
+
+	// Slack identifies the posted message when Slack was sent through the
+	// Web API with a bot token. Nil for webhook posts, which return no ID.
+	Slack *SlackRef
 }
 
 // SMTPConfig holds SMTP server configuration.
@@ -41,22 +54,65 @@ type Result struct {
 //   - GT_SMTP_USER: SMTP username (optional)
 //   - GT_SMTP_PASS: SMTP password (optional)
 //   - GT_SMTP_FROM: From address (default: gongshow@localhost)
+//   - GT_SMTP_TLS: TLS mode: none, opportunistic, starttls, or implicit
+//     (default: implicit on port 465, opportunistic on port 25, starttls otherwise)
+//   - GT_SMTP_TLS_SKIP_VERIFY: Set to "true" to skip certificate verification
+//   - GT_SMTP_DIAL_TIMEOUT: Connection timeout (default: 10s)
+//   - GT_SMTP_TIMEOUT: Timeout for the whole SMTP session (default: 30s)
+//   - GT_SMTP_PLAIN_TEXT_ONLY: Set to "true" to send text/plain without an HTML part
 type SMTPConfig struct {
 	Host     string
 	Port     string
 	Username string
 	Password string
 	From     string
+
+	TLSMode            string
+	InsecureSkipVerify bool
+	DialTimeout        time.Duration
+	Timeout            time.Duration
+	PlainTextOnly      bool
+
+	// rootCAs overrides the system certificate pool (used by tests).
+	rootCAs *x509.CertPool
 }
 
+// SMTP TLS modes.
+const (
+	SMTPTLSNone          = "none"          // Plain connection, no TLS
+	SMTPTLSOpportunistic = "opportunistic" // STARTTLS if the server advertises it, else plain
+	SMTPTLSStartTLS      = "starttls"      // Upgrade a plain connection with STARTTLS (required)
+	SMTPTLSImplicit      = "implicit"      // TLS from the first byte (SMTPS, port 465)
+)
+
 // LoadSMTPConfig loads SMTP configuration from environment variables.
 func LoadSMTPConfig() *SMTPConfig {
+	port := getEnvOrDefault("GT_SMTP_PORT", "25")
 	return &SMTPConfig{
-		Host:     getEnvOrDefault("GT_SMTP_HOST", "localhost"),
-		Port:     getEnvOrDefault("GT_SMTP_PORT", "25"),
-		Username: os.Getenv("GT_SMTP_USER"),
-		Password: os.Getenv("GT_SMTP_PASS"),
-		From:     getEnvOrDefault("GT_SMTP_FROM", "gongshow@localhost"),
+		Host:               getEnvOrDefault("GT_SMTP_HOST", "localhost"),
+		Port:               port,
+		Username:           os.Getenv("GT_SMTP_USER"),
+		Password:           os.Getenv("GT_SMTP_PASS"),
+		From:               getEnvOrDefault("GT_SMTP_FROM", "gongshow@localhost"),
+		TLSMode:            strings.ToLower(getEnvOrDefault("GT_SMTP_TLS", defaultSMTPTLSMode(port))),
+		InsecureSkipVerify: envBool("GT_SMTP_TLS_SKIP_VERIFY"),
+		DialTimeout:        envDuration("GT_SMTP_DIAL_TIMEOUT", 10*time.Second),
+		Timeout:            envDuration("GT_SMTP_TIMEOUT", 30*time.Second),
+		PlainTextOnly:      envBool("GT_SMTP_PLAIN_TEXT_ONLY"),
+	}
+}
+
+// defaultSMTPTLSMode picks a TLS mode from the well-known submission ports.
+// Port 25 relays often support STARTTLS without requiring it, so it is
+// used when offered.
+func defaultSMTPTLSMode(port string) string {
+	switch port {
+	case "465":
+		return SMTPTLSImplicit
+	case "25":
+		return SMTPTLSOpportunistic
+	default:
+		return SMTPTLSStartTLS
 	}
 }
 
@@ -65,25 +121,46 @@ func LoadSMTPConfig() *SMTPConfig {
 //   - TWILIO_ACCOUNT_SID: Twilio account SID
 //   - TWILIO_AUTH_TOKEN: Twilio auth token
 //   - TWILIO_FROM_NUMBER: Phone number to send from
+//   - GT_SMS_MAX_CHARS: Longest message body to send, in characters
+//     (default: 1600, Twilio's limit). Twilio bills per segment: 153
+//     characters for plain text, 67 once the body contains emoji or other
+//     non-GSM characters, so e.g. 306 caps a plain message at two segments.
 type TwilioConfig struct {
 	AccountSID string
 	AuthToken  string
 	FromNumber string
+	MaxChars   int
+
+	// apiBase overrides the Twilio API base URL (used by tests).
+	apiBase string
 }
 
+// DefaultSMSMaxChars is Twilio's maximum message body length.
+const DefaultSMSMaxChars = 1600
+
+const twilioAPIBase = "https://api.twilio.com"
+
 // LoadTwilioConfig loads Twilio configuration from environment variables.
 func LoadTwilioConfig() *TwilioConfig {
+	maxChars := DefaultSMSMaxChars
+	if n, err := strconv.Atoi(os.Getenv("GT_SMS_MAX_CHARS")); err == nil && n > 0 {
+		maxChars = n
+	}
 	return &TwilioConfig{
 		AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
 		AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
 		FromNumber: os.Getenv("TWILIO_FROM_NUMBER"),
+		MaxChars:   maxChars,
 	}
 }
 
 // SendEmail sends an email notification via SMTP.
 func SendEmail(to string, n *Notification) *Result {
-	cfg := LoadSMTPConfig()
+	return sendEmail(LoadSMTPConfig(), to, n)
+}
 
+// sendEmail sends an email notification using the given SMTP configuration.
+func sendEmail(cfg *SMTPConfig, to string, n *Notification) *Result {
 	if to == "" {
 		return &Result{
 			Channel: "email",
@@ -93,35 +170,22 @@ func SendEmail(to string, n *Notification) *Result {
 		}
 	}
 
-	// Build email message
-	subject := fmt.Sprintf("[%s] Escalation: %s", strings.ToUpper(n.Severity), n.Title)
-	body := buildEmailBody(n)
-
-	msg := fmt.Sprintf("From: %s\r\n"+
-		"To: %s\r\n"+
-		"Subject: %s\r\n"+
-		"MIME-Version: 1.0\r\n"+
-		"Content-Type: text/plain; charset=utf-8\r\n"+
-		"X-GongShow-Escalation: %s\r\n"+
-		"X-GongShow-Severity: %s\r\n"+
-		"\r\n"+
-		"%s",
-		cfg.From, to, subject, n.ID, n.Severity, body)
-
-	addr := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
-
-	var auth smtp.Auth
-	if cfg.Username != "" && cfg.Password != "" {
-		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	msg, err := buildEmailMessage(cfg, to, n)
+	if err != nil {
+		return &Result{
+			Channel: "email",
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("Failed to build email: %v", err),
+		}
 	}
 
-	err := smtp.SendMail(addr, auth, cfg.From, []string{to}, []byte(msg))
-	if err != nil {
+	if err := sendMail(cfg, to, msg); err != nil {
 		return &Result{
 			Channel: "email",
 			Success: false,
 			Error:   err,
-			Message: fmt.Sprintf("Failed to send email to %s: %v", to, err),
+			Message: fmt.Sprintf("Failed to send email to %s: %s", to, describeSMTPError(err)),
 		}
 	}
 
@@ -159,8 +223,11 @@ func buildEmailBody(n *Notification) string {
 
 // SendSMS sends an SMS notification via Twilio.
 func SendSMS(to string, n *Notification) *Result {
-	cfg := LoadTwilioConfig()
+	return sendSMS(LoadTwilioConfig(), to, n)
+}
 
+// sendSMS sends an SMS notification using the given Twilio configuration.
+func sendSMS(cfg *TwilioConfig, to string, n *Notification) *Result {
 	if cfg.AccountSID == "" || cfg.AuthToken == "" {
 		return &Result{
 			Channel: "sms",
@@ -189,22 +256,18 @@ func SendSMS(to string, n *Notification) *Result {
 	}
 
 	// Twilio Messages API endpoint
-	url := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", cfg.AccountSID)
-
-	// Build SMS message (keep it short for SMS)
-	body := fmt.Sprintf("[%s] %s - %s\nID: %s\nAck: gt escalate ack %s",
-		strings.ToUpper(n.Severity), n.Title, n.Source, n.ID, n.ID)
-
-	// Truncate if too long for SMS
-	if len(body) > 1600 {
-		body = body[:1597] + "..."
+	base := cfg.apiBase
+	if base == "" {
+		base = twilioAPIBase
 	}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", base, url.PathEscape(cfg.AccountSID))
 
-	// Build form data
-	data := fmt.Sprintf("To=%s&From=%s&Body=%s",
-		urlEncode(to), urlEncode(cfg.FromNumber), urlEncode(body))
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", cfg.FromNumber)
+	form.Set("Body", buildSMSBody(n, cfg.MaxChars))
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(data))
+	req, err := http.NewRequest("POST", endpoint, strings.NewReader(form.Encode()))
 	if err != nil {
 		return &Result{
 			Channel: "sms",
@@ -229,8 +292,8 @@ func SendSMS(to string, n *Notification) *Result {
 	}
 	defer resp.Body.Close()
 
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
 	if resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
 		return &Result{
 			Channel: "sms",
 			Success: false,
@@ -239,15 +302,52 @@ func SendSMS(to string, n *Notification) *Result {
 		}
 	}
 
+	message := fmt.Sprintf("SMS sent to %s", maskPhoneNumber(to))
+	var twilioResp struct {
+		SID string `json:"sid"`
+	}
+	if json.Unmarshal(respBody, &twilioResp) == nil && twilioResp.SID != "" {
+		message += fmt.Sprintf(" (SID %s)", twilioResp.SID)
+	}
+
 	return &Result{
 		Channel: "sms",
 		Success: true,
-		Message: fmt.Sprintf("SMS sent to %s", maskPhoneNumber(to)),
+		Message: message,
 	}
 }
 
-// SendSlack posts a notification to a Slack webhook.
+// buildSMSBody builds the SMS text, kept short and truncated to maxChars
+// characters (runes, since Twilio counts characters, not bytes).
+func buildSMSBody(n *Notification, maxChars int) string {
+	body := fmt.Sprintf("[%s] %s - %s\nID: %s\nAck: gt escalate ack %s",
+		strings.ToUpper(n.Severity), n.Title, n.Source, n.ID, n.ID)
+
+	// Truncate if too long for SMS
+	if maxChars <= 0 {
+		maxChars = DefaultSMSMaxChars
+	}
+	if utf8.RuneCountInString(body) > maxChars {
+		if maxChars <= 3 {
+			return string([]rune(body)[:maxChars])
+		}
+		body = string([]rune(body)[:maxChars-3]) + "..."
+	}
+	return body
+}
+
+// SendSlack posts a notification to Slack. When GT_SLACK_BOT_TOKEN and
+// GT_SLACK_CHANNEL are set it posts with chat.postMessage and returns the
+// message reference in Result.Slack; otherwise it posts to the webhook.
 func SendSlack(webhookURL string, n *Notification) *Result {
+	return sendSlack(LoadSlackConfig(), webhookURL, n)
+}
+
+func sendSlack(cfg *SlackConfig, webhookURL string, n *Notification) *Result {
+	if cfg.BotEnabled() {
+		return postSlackMessage(cfg, n)
+	}
+
 	if webhookURL == "" {
 		return &Result{
 			Channel: "slack",
@@ -258,8 +358,11 @@ func SendSlack(webhookURL string, n *Notification) *Result {
 	}
 
 	// Build Slack message with blocks for rich formatting
-	payload := buildSlackPayload(n)
+	return postSlackWebhook(webhookURL, buildSlackPayload(n), "Posted to Slack")
+}
 
+// postSlackWebhook posts payload to an incoming webhook.
+func postSlackWebhook(webhookURL string, payload map[string]interface{}, okMessage string) *Result {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return &Result{
@@ -307,7 +410,7 @@ func SendSlack(webhookURL string, n *Notification) *Result {
 	return &Result{
 		Channel: "slack",
 		Success: true,
-		Message: "Posted to Slack",
+		Message: okMessage,
 	}
 }
 
@@ -432,6 +535,22 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+func envBool(key string) bool {
+	switch strings.ToLower(os.Getenv(key)) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(key)); err == nil && d > 0 {
+		return d
+	}
+	return defaultValue
+}
+
 func severityEmoji(severity string) string {
 	switch strings.ToLower(severity) {
 	case "critical":
@@ -462,25 +581,6 @@ func severityColor(severity string) string {
 	}
 }
 
-// urlEncode encodes a string for URL form data.
-func urlEncode(s string) string {
-	// Simple URL encoding for form data
-	var result strings.Builder
-	for _, c := range s {
-		switch {
-		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9'):
-			result.WriteRune(c)
-		case c == '-' || c == '_' || c == '.' || c == '~':
-			result.WriteRune(c)
-		case c == ' ':
-			result.WriteRune('+')
-		default:
-			result.WriteString(fmt.Sprintf("%%%02X", c))
-		}
-	}
-	return result.String()
-}
-
 // maskPhoneNumber masks most digits of a phone number for privacy in logs.
 func maskPhoneNumber(phone string) string {
 	if len(phone) <= 4 {
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestLoadSMTPConfig(t *testing.T) {
@@ -117,6 +119,17 @@ func TestLoadTwilioConfig(t *testing.T) {
 			t.Errorf("expected FromNumber=+15551234567, got %s", cfg.FromNumber)
 		}
 	})
+
+	t.Run("max chars", func(t *testing.T) {
+		t.Setenv("GT_SMS_MAX_CHARS", "")
+		if cfg := LoadTwilioConfig(); cfg.MaxChars != DefaultSMSMaxChars {
+			t.Errorf("expected default MaxChars=%d, got %d", DefaultSMSMaxChars, cfg.MaxChars)
+		}
+		t.Setenv("GT_SMS_MAX_CHARS", "306")
+		if cfg := LoadTwilioConfig(); cfg.MaxChars != 306 {
+			t.Errorf("expected MaxChars=306, got %d", cfg.MaxChars)
+		}
+	})
 }
 
 func TestSendEmailNoRecipient(t *testing.T) {
@@ -406,22 +419,89 @@ func TestSeverityColor(t *testing.T) {
 	}
 }
 
-func TestURLEncode(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected string
-	}{
-		{"hello", "hello"},
-		{"hello world", "hello+world"},
-		{"test@example.com", "test%40example.com"},
-		{"+1 555-123-4567", "%2B1+555-123-4567"},
+func TestSendSMSFormEncoding(t *testing.T) {
+	var form url.Values
+	var user, pass, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm: %v", err)
+		}
+		form = r.PostForm
+		user, pass, _ = r.BasicAuth()
+		path = r.URL.Path
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid":"SM0123456789abcdef","status":"queued"}`))
+	}))
+	defer server.Close()
+
+	cfg := &TwilioConfig{
+		AccountSID: "AC123",
+		AuthToken:  "token123",
+		FromNumber: "+15550000000",
+		MaxChars:   DefaultSMSMaxChars,
+		apiBase:    server.URL,
+	}
+	n := &Notification{
+		ID:        "esc-001",
+		Severity:  "critical",
+		Title:     "Disk 100% full & build=broken 🚨\nsecond line",
+		Source:    "gongshow/witness",
+		Timestamp: time.Now(),
 	}
 
-	for _, tt := range tests {
-		got := urlEncode(tt.input)
-		if got != tt.expected {
-			t.Errorf("urlEncode(%s) = %s, want %s", tt.input, got, tt.expected)
-		}
+	result := sendSMS(cfg, "+15551234567", n)
+	if !result.Success {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+	if !strings.Contains(result.Message, "SM0123456789abcdef") {
+		t.Errorf("result message should include the message SID, got %q", result.Message)
+	}
+
+	if path != "/2010-04-01/Accounts/AC123/Messages.json" {
+		t.Errorf("path = %q", path)
+	}
+	if user != "AC123" || pass != "token123" {
+		t.Errorf("basic auth = %q:%q", user, pass)
+	}
+	if got := form.Get("To"); got != "+15551234567" {
+		t.Errorf("To = %q", got)
+	}
+	if got := form.Get("From"); got != "+15550000000" {
+		t.Errorf("From = %q", got)
+	}
+	if got, want := form.Get("Body"), buildSMSBody(n, DefaultSMSMaxChars); got != want {
+		t.Errorf("Body = %q, want %q", got, want)
+	}
+	if len(form) != 3 {
+		t.Errorf("stray form fields from unescaped & or =: %v", form)
+	}
+}
+
+func TestBuildSMSBodyTruncatesRunes(t *testing.T) {
+	n := &Notification{
+		ID:       "esc-001",
+		Severity: "high",
+		Title:    strings.Repeat("🚨", 200),
+		Source:   "gongshow/witness",
+	}
+
+	body := buildSMSBody(n, 70)
+	if !utf8.ValidString(body) {
+		t.Fatal("truncation split a multi-byte character")
+	}
+	if got := utf8.RuneCountInString(body); got != 70 {
+		t.Errorf("body has %d characters, want 70", got)
+	}
+	if !strings.HasSuffix(body, "...") {
+		t.Errorf("truncated body should end with ..., got %q", body)
+	}
+
+	short := &Notification{ID: "esc-002", Severity: "low", Title: "ok", Source: "mayor"}
+	if body := buildSMSBody(short, 70); strings.HasSuffix(body, "...") {
+		t.Errorf("short body should not be truncated: %q", body)
 	}
 }
 
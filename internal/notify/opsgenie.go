@@ -0,0 +1,220 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// OpsGenieConfig holds OpsGenie Alert API configuration.
+// Loaded from environment variables:
+//   - GT_OPSGENIE_API_KEY: API key of an API integration
+//   - GT_OPSGENIE_TEAM: Team to route alerts to (optional; without it the
+//     integration's own routing applies)
+//   - GT_OPSGENIE_API_URL: API base URL (default: https://api.opsgenie.com;
+//     https://api.eu.opsgenie.com for accounts in the EU)
+type OpsGenieConfig struct {
+	APIKey string
+	Team   string
+	APIURL string
+}
+
+const opsGenieAPIBase = "https://api.opsgenie.com"
+
+// OpsGenie's limits on alert fields, in characters.
+const (
+	opsGenieMaxMessage     = 130
+	opsGenieMaxDescription = 15000
+)
+
+// LoadOpsGenieConfig loads OpsGenie configuration from environment variables.
+func LoadOpsGenieConfig() *OpsGenieConfig {
+	return &OpsGenieConfig{
+		APIKey: os.Getenv("GT_OPSGENIE_API_KEY"),
+		Team:   os.Getenv("GT_OPSGENIE_TEAM"),
+		APIURL: getEnvOrDefault("GT_OPSGENIE_API_URL", opsGenieAPIBase),
+	}
+}
+
+// SendOpsGenie creates an OpsGenie alert for the notification. The alias is
+// the escalation ID, so OpsGenie folds repeat sends (re-escalations) into
+// the open alert, and CloseOpsGenieAlert can find it.
+func SendOpsGenie(cfg *OpsGenieConfig, n *Notification) *Result {
+	if cfg.APIKey == "" {
+		return &Result{
+			Channel: ChannelOpsGenie,
+			Success: false,
+			Error:   fmt.Errorf("OpsGenie API key not configured"),
+			Message: "OpsGenie skipped: GT_OPSGENIE_API_KEY required",
+		}
+	}
+
+	requestID, err := callOpsGenie(cfg, "/v2/alerts", buildOpsGeniePayload(cfg, n))
+	if err != nil {
+		return &Result{
+			Channel: ChannelOpsGenie,
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("OpsGenie alert failed: %v", err),
+		}
+	}
+	return &Result{
+		Channel: ChannelOpsGenie,
+		Success: true,
+		Message: opsGenieMessage("OpsGenie alert created", requestID),
+	}
+}
+
+// CloseOpsGenieAlert closes the OpsGenie alert that SendOpsGenie created
+// for the notification, found by its alias.
+func CloseOpsGenieAlert(cfg *OpsGenieConfig, n *Notification) *Result {
+	if cfg.APIKey == "" {
+		return &Result{
+			Channel: ChannelOpsGenie,
+			Success: false,
+			Error:   fmt.Errorf("OpsGenie API key not configured"),
+			Message: "OpsGenie close skipped: GT_OPSGENIE_API_KEY required",
+		}
+	}
+
+	path := "/v2/alerts/" + url.PathEscape(n.ID) + "/close?identifierType=alias"
+	payload := map[string]interface{}{
+		"source": "gongshow",
+		"note":   fmt.Sprintf("Escalation %s closed in GongShow", n.ID),
+	}
+	requestID, err := callOpsGenie(cfg, path, payload)
+	if err != nil {
+		return &Result{
+			Channel: ChannelOpsGenie,
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("OpsGenie close failed: %v", err),
+		}
+	}
+	return &Result{
+		Channel: ChannelOpsGenie,
+		Success: true,
+		Message: opsGenieMessage("OpsGenie alert closed", requestID),
+	}
+}
+
+// callOpsGenie POSTs payload to an Alert API path and returns the request
+// ID of the accepted request. OpsGenie processes alert requests
+// asynchronously, answering 202 Accepted.
+func callOpsGenie(cfg *OpsGenieConfig, path string, payload map[string]interface{}) (string, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("building OpsGenie payload: %w", err)
+	}
+
+	base := strings.TrimRight(cfg.APIURL, "/")
+	if base == "" {
+		base = opsGenieAPIBase
+	}
+	req, err := http.NewRequest("POST", base+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+cfg.APIKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var ogResp struct {
+		Message   string `json:"message"`
+		RequestID string `json:"requestId"`
+	}
+	_ = json.Unmarshal(respBody, &ogResp)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail := ogResp.Message
+		if detail == "" {
+			detail = strings.TrimSpace(string(respBody))
+		}
+		return "", fmt.Errorf("OpsGenie API error: %s - %s", resp.Status, detail)
+	}
+	return ogResp.RequestID, nil
+}
+
+func opsGenieMessage(message, requestID string) string {
+	if requestID != "" {
+		message += fmt.Sprintf(" (request %s)", requestID)
+	}
+	return message
+}
+
+// buildOpsGeniePayload creates the Alert API create-alert request.
+func buildOpsGeniePayload(cfg *OpsGenieConfig, n *Notification) map[string]interface{} {
+	details := map[string]string{}
+	if rig := opsGenieRig(n.Source); rig != "" {
+		details["rig"] = rig
+	}
+	if n.RelatedBead != "" {
+		details["bead"] = n.RelatedBead
+	}
+
+	tags := []string{"gongshow"}
+	if n.Source != "" {
+		tags = append(tags, n.Source)
+	}
+
+	payload := map[string]interface{}{
+		"message":     truncateRunes(n.Title, opsGenieMaxMessage),
+		"alias":       n.ID,
+		"description": truncateRunes(buildEmailBody(n), opsGenieMaxDescription),
+		"priority":    opsGeniePriority(n.Severity),
+		"tags":        tags,
+		"details":     details,
+		"source":      "gongshow",
+		"entity":      n.Source,
+	}
+	if cfg.Team != "" {
+		payload["responders"] = []map[string]string{{"type": "team", "name": cfg.Team}}
+	}
+	return payload
+}
+
+// opsGeniePriority maps an escalation severity to an OpsGenie priority,
+// P1 (critical) to P5 (informational).
+func opsGeniePriority(severity string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "P1"
+	case "high":
+		return "P2"
+	case "low":
+		return "P4"
+	default:
+		return "P3" // medium, and OpsGenie's own default
+	}
+}
+
+// opsGenieRig returns the rig of an agent address like "gongshow/witness",
+// or "" for town-level agents like "mayor/".
+func opsGenieRig(source string) string {
+	rig, _, ok := strings.Cut(source, "/")
+	if !ok || rig == "mayor" || rig == "deacon" {
+		return ""
+	}
+	return rig
+}
+
+// truncateRunes cuts s to at most max characters, ending in "..." if cut.
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-3]) + "..."
+}
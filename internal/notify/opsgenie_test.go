@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testOpsGenieNotification() *Notification {
+	return &Notification{
+		ID:          "hq-esc42",
+		Severity:    "high",
+		Title:       "Refinery stuck",
+		Body:        "Merge queue has not advanced in 2h",
+		Source:      "gongshow/witness",
+		RelatedBead: "gt-123",
+		Timestamp:   time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+// opsGenieRequest is a request as the test server received it.
+type opsGenieRequest struct {
+	Method, Path, Query, Auth string
+	Body                      map[string]interface{}
+}
+
+func newOpsGenieServer(t *testing.T, status int, response string) (*httptest.Server, *[]opsGenieRequest) {
+	t.Helper()
+	var requests []opsGenieRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := opsGenieRequest{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Auth: r.Header.Get("Authorization")}
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req.Body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		requests = append(requests, req)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestSendOpsGenie(t *testing.T) {
+	server, requests := newOpsGenieServer(t, http.StatusAccepted,
+		`{"result":"Request will be processed","took":0.302,"requestId":"43a29c5c-3dbf-4fa4-9c26-f4f71023e120"}`)
+	cfg := &OpsGenieConfig{APIKey: "key-123", Team: "platform", APIURL: server.URL + "/"}
+
+	result := SendOpsGenie(cfg, testOpsGenieNotification())
+	if !result.Success {
+		t.Fatalf("SendOpsGenie() failed: %v", result.Error)
+	}
+	if result.Channel != ChannelOpsGenie || !strings.Contains(result.Message, "43a29c5c") {
+		t.Errorf("result = %+v, want an opsgenie result with the request ID", result)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(*requests))
+	}
+	req := (*requests)[0]
+	if req.Method != "POST" || req.Path != "/v2/alerts" || req.Auth != "GenieKey key-123" {
+		t.Errorf("request = %s %s (Authorization %q), want POST /v2/alerts with the GenieKey", req.Method, req.Path, req.Auth)
+	}
+
+	body := req.Body
+	for key, want := range map[string]interface{}{
+		"message":    "Refinery stuck",
+		"alias":      "hq-esc42",
+		"priority":   "P2",
+		"tags":       []interface{}{"gongshow", "gongshow/witness"},
+		"details":    map[string]interface{}{"rig": "gongshow", "bead": "gt-123"},
+		"responders": []interface{}{map[string]interface{}{"type": "team", "name": "platform"}},
+		"source":     "gongshow",
+	} {
+		if !reflect.DeepEqual(body[key], want) {
+			t.Errorf("payload %s = %#v, want %#v", key, body[key], want)
+		}
+	}
+	if desc, _ := body["description"].(string); !strings.Contains(desc, "Merge queue has not advanced") || !strings.Contains(desc, "gt escalate ack hq-esc42") {
+		t.Errorf("description = %q, want the body and how to ack", desc)
+	}
+}
+
+func TestSendOpsGenieTownAgentWithoutTeam(t *testing.T) {
+	server, requests := newOpsGenieServer(t, http.StatusAccepted, `{"requestId":"r1"}`)
+	n := testOpsGenieNotification()
+	n.Source, n.RelatedBead, n.Title = "mayor/", "", strings.Repeat("x", 200)
+
+	if result := SendOpsGenie(&OpsGenieConfig{APIKey: "k", APIURL: server.URL}, n); !result.Success {
+		t.Fatalf("SendOpsGenie() failed: %v", result.Error)
+	}
+	body := (*requests)[0].Body
+	if details := body["details"].(map[string]interface{}); len(details) != 0 {
+		t.Errorf("details = %v, want none for a town agent with no bead", details)
+	}
+	if _, ok := body["responders"]; ok {
+		t.Error("responders set without a team")
+	}
+	if msg := body["message"].(string); len(msg) != opsGenieMaxMessage || !strings.HasSuffix(msg, "...") {
+		t.Errorf("message is %d characters, want it cut to %d", len(msg), opsGenieMaxMessage)
+	}
+}
+
+func TestCloseOpsGenieAlert(t *testing.T) {
+	server, requests := newOpsGenieServer(t, http.StatusAccepted, `{"result":"Request will be processed","requestId":"r2"}`)
+	cfg := &OpsGenieConfig{APIKey: "key-123", APIURL: server.URL}
+
+	result := CloseOpsGenieAlert(cfg, testOpsGenieNotification())
+	if !result.Success {
+		t.Fatalf("CloseOpsGenieAlert() failed: %v", result.Error)
+	}
+	if result.Message != "OpsGenie alert closed (request r2)" {
+		t.Errorf("message = %q", result.Message)
+	}
+
+	req := (*requests)[0]
+	if req.Method != "POST" || req.Path != "/v2/alerts/hq-esc42/close" || req.Query != "identifierType=alias" {
+		t.Errorf("request = %s %s?%s, want the close of alias hq-esc42", req.Method, req.Path, req.Query)
+	}
+	if req.Auth != "GenieKey key-123" {
+		t.Errorf("Authorization = %q", req.Auth)
+	}
+	if note, _ := req.Body["note"].(string); !strings.Contains(note, "hq-esc42") {
+		t.Errorf("note = %q, want the escalation ID", note)
+	}
+}
+
+func TestOpsGenieAPIError(t *testing.T) {
+	server, _ := newOpsGenieServer(t, http.StatusUnauthorized,
+		`{"message":"Key format is not valid!","took":0.001,"requestId":"r3"}`)
+	cfg := &OpsGenieConfig{APIKey: "bad", APIURL: server.URL}
+
+	for name, result := range map[string]*Result{
+		"create": SendOpsGenie(cfg, testOpsGenieNotification()),
+		"close":  CloseOpsGenieAlert(cfg, testOpsGenieNotification()),
+	} {
+		if result.Success || result.Error == nil || !strings.Contains(result.Error.Error(), "Key format is not valid") {
+			t.Errorf("%s result = %+v, want the API's error", name, result)
+		}
+	}
+}
+
+func TestOpsGenieNotConfigured(t *testing.T) {
+	for name, result := range map[string]*Result{
+		"create": SendOpsGenie(&OpsGenieConfig{}, testOpsGenieNotification()),
+		"close":  CloseOpsGenieAlert(&OpsGenieConfig{}, testOpsGenieNotification()),
+	} {
+		if result.Success || result.Channel != ChannelOpsGenie || !strings.Contains(result.Message, "GT_OPSGENIE_API_KEY") {
+			t.Errorf("%s result = %+v, want it skipped for want of a key", name, result)
+		}
+	}
+}
+
+func TestLoadOpsGenieConfig(t *testing.T) {
+	t.Setenv("GT_OPSGENIE_API_KEY", "key-123")
+	t.Setenv("GT_OPSGENIE_TEAM", "platform")
+	t.Setenv("GT_OPSGENIE_API_URL", "")
+
+	cfg := LoadOpsGenieConfig()
+	if cfg.APIKey != "key-123" || cfg.Team != "platform" || cfg.APIURL != "https://api.opsgenie.com" {
+		t.Errorf("LoadOpsGenieConfig() = %+v", cfg)
+	}
+}
+
+func TestOpsGeniePriority(t *testing.T) {
+	for severity, want := range map[string]string{
+		"critical": "P1",
+		"HIGH":     "P2",
+		"medium":   "P3",
+		"low":      "P4",
+		"":         "P3",
+	} {
+		if got := opsGeniePriority(severity); got != want {
+			t.Errorf("opsGeniePriority(%q) = %s, want %s", severity, got, want)
+		}
+	}
+}
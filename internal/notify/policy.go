@@ -0,0 +1,286 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CurrentConfigVersion is the current schema version for settings/notify.json.
+const CurrentConfigVersion = 1
+
+// DefaultBreakthroughSeverity is the lowest severity that is still delivered
+// during quiet hours when a schedule doesn't set one.
+const DefaultBreakthroughSeverity = "critical"
+
+// Config is the per-channel delivery policy (settings/notify.json).
+//
+// Example:
+//
+//	{
+//	  "type": "notify",
+//	  "version": 1,
+//	  "channels": {
+//	    "slack": {"rate_limit": {"per_minute": 5, "per_hour": 30}},
+//	    "sms": {
+//	      "quiet_hours": {
+//	        "timezone": "America/Los_Angeles",
+//	        "schedule": [
+//	          {"days": ["mon", "tue", "wed", "thu", "fri"], "start": "22:00", "end": "07:00"},
+//	          {"days": ["sat", "sun"], "start": "00:00", "end": "24:00"}
+//	        ],
+//	        "breakthrough": "critical"
+//	      }
+//	    }
+//	  }
+//	}
+type Config struct {
+	Type    string `json:"type"`    // "notify"
+	Version int    `json:"version"` // schema version
+
+	// Channels maps a channel name (email, sms, slack, log) to its policy.
+	// Channels without an entry are delivered immediately and unlimited.
+	Channels map[string]*ChannelPolicy `json:"channels,omitempty"`
+}
+
+// ChannelPolicy controls when and how often a channel may be sent to.
+type ChannelPolicy struct {
+	RateLimit  *RateLimit  `json:"rate_limit,omitempty"`
+	QuietHours *QuietHours `json:"quiet_hours,omitempty"`
+}
+
+// RateLimit caps the number of sends on a channel. Zero means no limit.
+type RateLimit struct {
+	PerMinute int `json:"per_minute,omitempty"`
+	PerHour   int `json:"per_hour,omitempty"`
+}
+
+// QuietHours defers non-urgent notifications during scheduled windows.
+type QuietHours struct {
+	// Timezone is an IANA zone name (default: local time).
+	Timezone string `json:"timezone,omitempty"`
+
+	// Schedule lists the quiet windows.
+	Schedule []QuietWindow `json:"schedule"`
+
+	// Breakthrough is the lowest severity delivered despite quiet hours
+	// (default: critical).
+	Breakthrough string `json:"breakthrough,omitempty"`
+
+	loc *time.Location
+}
+
+// QuietWindow is a daily time range on the given weekdays.
+// If End is not after Start the window runs past midnight into the next day,
+// and belongs to the day it starts on.
+type QuietWindow struct {
+	Days  []string `json:"days"`  // mon, tue, wed, thu, fri, sat, sun
+	Start string   `json:"start"` // HH:MM
+	End   string   `json:"end"`   // HH:MM, 24:00 allowed
+
+	days       [7]bool
+	start, end int // minutes since midnight
+}
+
+// ConfigPath returns the standard path for the notify config in a town.
+func ConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "notify.json")
+}
+
+// NewConfig returns an empty config: every channel immediate and unlimited.
+func NewConfig() *Config {
+	return &Config{
+		Type:     "notify",
+		Version:  CurrentConfigVersion,
+		Channels: make(map[string]*ChannelPolicy),
+	}
+}
+
+// LoadConfig loads settings/notify.json for a town.
+// A missing file yields an empty config rather than an error.
+func LoadConfig(townRoot string) (*Config, error) {
+	path := ConfigPath(townRoot)
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewConfig(), nil
+		}
+		return nil, fmt.Errorf("reading notify config: %w", err)
+	}
+
+	cfg := NewConfig()
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing notify config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid notify config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// validate checks the config and precomputes parsed schedule fields.
+func (c *Config) validate() error {
+	if c.Type != "" && c.Type != "notify" {
+		return fmt.Errorf("type must be \"notify\", got %q", c.Type)
+	}
+	if c.Version > CurrentConfigVersion {
+		return fmt.Errorf("unsupported version %d (max %d)", c.Version, CurrentConfigVersion)
+	}
+
+	for name, policy := range c.Channels {
+		if policy == nil {
+			continue
+		}
+		if rl := policy.RateLimit; rl != nil && (rl.PerMinute < 0 || rl.PerHour < 0) {
+			return fmt.Errorf("channels.%s.rate_limit: limits must not be negative", name)
+		}
+		if qh := policy.QuietHours; qh != nil {
+			if err := qh.compile(); err != nil {
+				return fmt.Errorf("channels.%s.quiet_hours: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// policy returns the policy for a channel, or nil if it has none.
+func (c *Config) policy(channel string) *ChannelPolicy {
+	if c == nil {
+		return nil
+	}
+	return c.Channels[channel]
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func (q *QuietHours) compile() error {
+	q.loc = time.Local
+	if q.Timezone != "" {
+		loc, err := time.LoadLocation(q.Timezone)
+		if err != nil {
+			return fmt.Errorf("timezone: %w", err)
+		}
+		q.loc = loc
+	}
+
+	if q.Breakthrough != "" && severityRank(q.Breakthrough) < 0 {
+		return fmt.Errorf("breakthrough: unknown severity %q", q.Breakthrough)
+	}
+
+	for i := range q.Schedule {
+		w := &q.Schedule[i]
+		if len(w.Days) == 0 {
+			return fmt.Errorf("schedule[%d]: days is required", i)
+		}
+		for _, day := range w.Days {
+			wd, ok := weekdays[strings.ToLower(day)]
+			if !ok {
+				return fmt.Errorf("schedule[%d]: unknown day %q", i, day)
+			}
+			w.days[wd] = true
+		}
+
+		var err error
+		if w.start, err = parseClock(w.Start); err != nil || w.start >= 24*60 {
+			return fmt.Errorf("schedule[%d]: invalid start %q", i, w.Start)
+		}
+		if w.end, err = parseClock(w.End); err != nil {
+			return fmt.Errorf("schedule[%d]: invalid end %q", i, w.End)
+		}
+	}
+	return nil
+}
+
+// parseClock parses HH:MM into minutes since midnight. 24:00 is accepted.
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, fmt.Errorf("expected HH:MM")
+	}
+	h, err := strconv.Atoi(hh)
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.Atoi(mm)
+	if err != nil {
+		return 0, err
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("out of range")
+	}
+	return h*60 + m, nil
+}
+
+// QuietUntil reports whether t falls in a quiet window and, if so, when that
+// window ends.
+func (q *QuietHours) QuietUntil(t time.Time) (time.Time, bool) {
+	if q == nil || len(q.Schedule) == 0 {
+		return time.Time{}, false
+	}
+	if q.loc == nil {
+		if err := q.compile(); err != nil {
+			return time.Time{}, false
+		}
+	}
+
+	local := t.In(q.loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, q.loc)
+	minute := local.Hour()*60 + local.Minute()
+	yesterday := midnight.AddDate(0, 0, -1).Weekday()
+
+	var until time.Time
+	for _, w := range q.Schedule {
+		wraps := w.end <= w.start
+		switch {
+		case w.days[local.Weekday()] && minute >= w.start && (wraps || minute < w.end):
+			end := midnight.Add(time.Duration(w.end) * time.Minute)
+			if wraps {
+				end = midnight.AddDate(0, 0, 1).Add(time.Duration(w.end) * time.Minute)
+			}
+			if end.After(until) {
+				until = end
+			}
+		case wraps && w.days[yesterday] && minute < w.end:
+			end := midnight.Add(time.Duration(w.end) * time.Minute)
+			if end.After(until) {
+				until = end
+			}
+		}
+	}
+	return until, !until.IsZero()
+}
+
+// Breaks reports whether a notification of the given severity is delivered
+// despite quiet hours.
+func (q *QuietHours) Breaks(severity string) bool {
+	floor := q.Breakthrough
+	if floor == "" {
+		floor = DefaultBreakthroughSeverity
+	}
+	rank := severityRank(severity)
+	return rank >= 0 && rank >= severityRank(floor)
+}
+
+// severityRank orders severities low < medium < high < critical.
+// Unknown severities return -1.
+func severityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case "low":
+		return 0
+	case "medium":
+		return 1
+	case "high":
+		return 2
+	case "critical":
+		return 3
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"encoding/json"
+	"path/filepath"
+)
+
+// Preview is what a send to one target would deliver, rendered without any
+// network calls.
+type Preview struct {
+	Channel   string
+	Recipient string // masked
+	Subject   string // email only
+	Body      string
+}
+
+// Render returns the preview of sending n to t.
+func Render(townRoot string, t Target, n *Notification) *Preview {
+	p := &Preview{
+		Channel:   t.Channel,
+		Recipient: maskRecipient(t.Channel, t.Address),
+	}
+
+	switch t.Channel {
+	case ChannelEmail:
+		p.Subject = buildEmailSubject(n)
+		p.Body = buildEmailBody(n)
+	case ChannelSMS:
+		p.Body = buildSMSBody(n, LoadTwilioConfig().MaxChars)
+	case ChannelSlack:
+		p.Body = renderJSON(buildSlackPayload(n))
+	case ChannelTeams:
+		if data, err := buildTeamsPayload(n, envBool(teamsLegacyCardEnv)); err == nil {
+			var v interface{}
+			_ = json.Unmarshal(data, &v)
+			p.Body = renderJSON(v)
+		}
+	case ChannelOpsGenie:
+		cfg := LoadOpsGenieConfig()
+		p.Recipient = cfg.APIURL
+		p.Body = renderJSON(buildOpsGeniePayload(cfg, n))
+	case ChannelLog:
+		p.Recipient = filepath.Join(townRoot, "logs", "escalations.log")
+		p.Body = buildLogEntry(n)
+	}
+	return p
+}
+
+func renderJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	n := &Notification{
+		ID:        "notify-test-1",
+		Severity:  "high",
+		Title:     "TEST - notification check",
+		Source:    "mayor",
+		Timestamp: time.Now(),
+	}
+
+	email := Render("/town", Target{Channel: ChannelEmail, Address: "oncall@example.com"}, n)
+	if email.Subject != "[HIGH] Escalation: TEST - notification check" {
+		t.Errorf("email subject = %q", email.Subject)
+	}
+	if email.Recipient != "o*****@example.com" {
+		t.Errorf("email recipient should be masked, got %q", email.Recipient)
+	}
+	if !strings.Contains(email.Body, "gt escalate ack notify-test-1") {
+		t.Errorf("email body missing ack hint:\n%s", email.Body)
+	}
+
+	sms := Render("/town", Target{Channel: ChannelSMS, Address: "+15551234567"}, n)
+	if sms.Recipient != "********4567" || !strings.HasPrefix(sms.Body, "[HIGH] TEST") {
+		t.Errorf("unexpected sms preview: %+v", sms)
+	}
+
+	slack := Render("/town", Target{Channel: ChannelSlack, Address: "https://hooks.slack.com/services/secret"}, n)
+	if strings.Contains(slack.Recipient, "secret") {
+		t.Errorf("slack webhook not masked: %q", slack.Recipient)
+	}
+	if !strings.Contains(slack.Body, `"attachments"`) {
+		t.Errorf("slack preview should show payload JSON:\n%s", slack.Body)
+	}
+
+	teams := Render("/town", Target{Channel: ChannelTeams, Address: "https://example.webhook.office.com/x"}, n)
+	if !strings.Contains(teams.Body, "AdaptiveCard") {
+		t.Errorf("teams preview should show the card:\n%s", teams.Body)
+	}
+
+	log := Render("/town", Target{Channel: ChannelLog}, n)
+	if log.Recipient != "/town/logs/escalations.log" || !strings.Contains(log.Body, `"id":"notify-test-1"`) {
+		t.Errorf("unexpected log preview: %+v", log)
+	}
+}
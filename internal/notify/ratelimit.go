@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// rateLimitState is the on-disk record of recent sends per channel.
+// gt is short-lived, so limits are only meaningful if this survives between
+// invocations.
+type rateLimitState struct {
+	Channels map[string][]time.Time `json:"channels"`
+}
+
+// rateLimiter enforces RateLimit against state persisted in the town.
+type rateLimiter struct {
+	statePath string
+	lockPath  string
+}
+
+func newRateLimiter(townRoot string) *rateLimiter {
+	dir := filepath.Join(townRoot, ".runtime")
+	return &rateLimiter{
+		statePath: filepath.Join(dir, "notify-ratelimit.json"),
+		lockPath:  filepath.Join(dir, "notify-ratelimit.lock"),
+	}
+}
+
+// reserve records a send on channel at now if the limit allows it.
+// If the limit is exhausted, it returns false and the earliest time a slot
+// frees up. The check and the record happen under a file lock so concurrent
+// gt processes can't both take the last slot.
+func (r *rateLimiter) reserve(channel string, limit *RateLimit, now time.Time) (bool, time.Time, error) {
+	if limit == nil || (limit.PerMinute == 0 && limit.PerHour == 0) {
+		return true, time.Time{}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.lockPath), 0755); err != nil {
+		return false, time.Time{}, fmt.Errorf("creating runtime dir: %w", err)
+	}
+	lock := flock.New(r.lockPath)
+	if err := lock.Lock(); err != nil {
+		return false, time.Time{}, fmt.Errorf("locking rate limit state: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	state := r.load()
+
+	// Drop sends older than the largest window we track.
+	var recent []time.Time
+	for _, ts := range state.Channels[channel] {
+		if now.Sub(ts) < time.Hour {
+			recent = append(recent, ts)
+		}
+	}
+
+	if retryAt, ok := windowFull(recent, limit.PerMinute, time.Minute, now); ok {
+		state.Channels[channel] = recent
+		return false, retryAt, r.save(state)
+	}
+	if retryAt, ok := windowFull(recent, limit.PerHour, time.Hour, now); ok {
+		state.Channels[channel] = recent
+		return false, retryAt, r.save(state)
+	}
+
+	state.Channels[channel] = append(recent, now)
+	return true, time.Time{}, r.save(state)
+}
+
+// windowFull reports whether sends (oldest first) already hold max slots in
+// the window ending at now, and if so when the oldest of them ages out.
+func windowFull(sends []time.Time, max int, window time.Duration, now time.Time) (time.Time, bool) {
+	if max <= 0 {
+		return time.Time{}, false
+	}
+	var inWindow []time.Time
+	for _, ts := range sends {
+		if now.Sub(ts) < window {
+			inWindow = append(inWindow, ts)
+		}
+	}
+	if len(inWindow) < max {
+		return time.Time{}, false
+	}
+	return inWindow[len(inWindow)-max].Add(window), true
+}
+
+func (r *rateLimiter) load() *rateLimitState {
+	state := &rateLimitState{Channels: make(map[string][]time.Time)}
+	data, err := os.ReadFile(r.statePath)
+	if err != nil {
+		return state
+	}
+	// A corrupt state file just resets the limiter.
+	_ = json.Unmarshal(data, state)
+	if state.Channels == nil {
+		state.Channels = make(map[string][]time.Time)
+	}
+	return state
+}
+
+func (r *rateLimiter) save(state *rateLimitState) error {
+	if err := util.AtomicWriteJSON(r.statePath, state); err != nil {
+		return fmt.Errorf("saving rate limit state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// DeliveryReport records how one notification fared across all its channels.
+type DeliveryReport struct {
+	EscalationID string
+	Results      []*Result
+	SuccessCount int
+	FailureCount int
+	At           time.Time
+}
+
+// Notify runs each channel's send in turn and collects the outcomes in
+// channel order. A deferred send counts as a success: it is spooled and will
+// be retried. A channel that returns no result counts as a failure.
+func Notify(n *Notification, channels []func() *Result) *DeliveryReport {
+	report := &DeliveryReport{
+		EscalationID: n.ID,
+		Results:      make([]*Result, 0, len(channels)),
+		At:           time.Now(),
+	}
+	for _, send := range channels {
+		result := send()
+		if result == nil {
+			result = &Result{Channel: "unknown", Message: "channel returned no result"}
+		}
+		if result.Success || result.Deferred {
+			report.SuccessCount++
+		} else {
+			report.FailureCount++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// AllSucceeded reports whether every channel delivered (or deferred).
+func (r *DeliveryReport) AllSucceeded() bool {
+	return r.FailureCount == 0
+}
+
+// FailedChannels returns the channels that failed, in send order, each once.
+func (r *DeliveryReport) FailedChannels() []string {
+	var failed []string
+	seen := make(map[string]bool)
+	for _, result := range r.Results {
+		if result.Success || result.Deferred || seen[result.Channel] {
+			continue
+		}
+		seen[result.Channel] = true
+		failed = append(failed, result.Channel)
+	}
+	return failed
+}
+
+// reportResult is the on-disk form of a Result, with the error as text.
+type reportResult struct {
+	Channel   string    `json:"channel"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Deferred  bool      `json:"deferred,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	Slack     *SlackRef `json:"slack,omitempty"`
+}
+
+type reportJSON struct {
+	EscalationID string          `json:"escalation_id"`
+	Results      []*reportResult `json:"results"`
+	SuccessCount int             `json:"success_count"`
+	FailureCount int             `json:"failure_count"`
+	At           time.Time       `json:"at"`
+}
+
+// MarshalJSON encodes the report with result errors as strings. URLs in
+// them are masked, as they may be webhooks with a secret path.
+func (r *DeliveryReport) MarshalJSON() ([]byte, error) {
+	out := reportJSON{
+		EscalationID: r.EscalationID,
+		Results:      make([]*reportResult, len(r.Results)),
+		SuccessCount: r.SuccessCount,
+		FailureCount: r.FailureCount,
+		At:           r.At,
+	}
+	for i, res := range r.Results {
+		rr := &reportResult{
+			Channel:   res.Channel,
+			Success:   res.Success,
+			Message:   maskURLs(res.Message),
+			Deferred:  res.Deferred,
+			NotBefore: res.NotBefore,
+			Slack:     res.Slack,
+		}
+		if res.Error != nil {
+			rr.Error = maskURLs(res.Error.Error())
+		}
+		out.Results[i] = rr
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a report written by MarshalJSON.
+func (r *DeliveryReport) UnmarshalJSON(data []byte) error {
+	var in reportJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*r = DeliveryReport{
+		EscalationID: in.EscalationID,
+		Results:      make([]*Result, len(in.Results)),
+		SuccessCount: in.SuccessCount,
+		FailureCount: in.FailureCount,
+		At:           in.At,
+	}
+	for i, rr := range in.Results {
+		res := &Result{
+			Channel:   rr.Channel,
+			Success:   rr.Success,
+			Message:   rr.Message,
+			Deferred:  rr.Deferred,
+			NotBefore: rr.NotBefore,
+			Slack:     rr.Slack,
+		}
+		if rr.Error != "" {
+			res.Error = errors.New(rr.Error)
+		}
+		r.Results[i] = res
+	}
+	return nil
+}
+
+// ReportPath returns where the delivery report for an escalation is kept,
+// under the town's beads directory.
+func ReportPath(beadsDir, escalationID string) string {
+	return filepath.Join(beadsDir, "notify-reports", sanitizeSpoolName(escalationID)+".json")
+}
+
+// SaveReport writes the report to ReportPath, replacing any earlier report
+// for the same escalation.
+func SaveReport(beadsDir string, r *DeliveryReport) error {
+	path := ReportPath(beadsDir, r.EscalationID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating report directory: %w", err)
+	}
+	if err := util.AtomicWriteJSON(path, r); err != nil {
+		return fmt.Errorf("writing delivery report: %w", err)
+	}
+	return nil
+}
+
+// LoadReport reads the delivery report for an escalation. The error wraps
+// os.ErrNotExist when no report was written.
+func LoadReport(beadsDir, escalationID string) (*DeliveryReport, error) {
+	data, err := os.ReadFile(ReportPath(beadsDir, escalationID))
+	if err != nil {
+		return nil, fmt.Errorf("reading delivery report: %w", err)
+	}
+	var r DeliveryReport
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing delivery report: %w", err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,128 @@
+package notify
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sendResult(r *Result) func() *Result {
+	return func() *Result { return r }
+}
+
+func TestNotifyPartialSuccess(t *testing.T) {
+	n := &Notification{ID: "hq-1", Severity: "critical", Title: "Refinery down"}
+	report := Notify(n, []func() *Result{
+		sendResult(&Result{Channel: ChannelEmail, Success: true, Message: "sent"}),
+		sendResult(&Result{Channel: ChannelSlack, Error: errors.New("HTTP 500"), Message: "Slack returned 500"}),
+		sendResult(&Result{Channel: ChannelSMS, Success: true, Deferred: true, Message: "Deferred until Wed 07:00 (quiet hours)"}),
+		sendResult(&Result{Channel: ChannelTeams, Error: errors.New("timeout"), Message: "Teams timed out"}),
+	})
+
+	if report.EscalationID != "hq-1" {
+		t.Errorf("EscalationID = %q, want hq-1", report.EscalationID)
+	}
+	if report.SuccessCount != 2 || report.FailureCount != 2 {
+		t.Errorf("counts = %d ok / %d failed, want 2 / 2", report.SuccessCount, report.FailureCount)
+	}
+	if report.AllSucceeded() {
+		t.Error("AllSucceeded() = true for a partial delivery")
+	}
+	if got, want := report.FailedChannels(), []string{ChannelSlack, ChannelTeams}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FailedChannels() = %v, want %v", got, want)
+	}
+	if len(report.Results) != 4 || report.Results[1].Channel != ChannelSlack {
+		t.Errorf("results out of channel order: %+v", report.Results)
+	}
+}
+
+func TestNotifyAllSucceeded(t *testing.T) {
+	report := Notify(&Notification{ID: "hq-2"}, []func() *Result{
+		sendResult(&Result{Channel: ChannelLog, Success: true}),
+		sendResult(&Result{Channel: ChannelEmail, Success: true}),
+	})
+	if !report.AllSucceeded() || len(report.FailedChannels()) != 0 {
+		t.Errorf("report = %+v, want every channel delivered", report)
+	}
+}
+
+func TestNotifyNilResultFails(t *testing.T) {
+	report := Notify(&Notification{ID: "hq-3"}, []func() *Result{sendResult(nil)})
+	if report.FailureCount != 1 || report.AllSucceeded() {
+		t.Errorf("nil result counted as %d failures, want 1", report.FailureCount)
+	}
+}
+
+func TestSaveLoadReport(t *testing.T) {
+	beadsDir := t.TempDir()
+	at := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)
+	report := &DeliveryReport{
+		EscalationID: "hq-abc123",
+		Results: []*Result{
+			{Channel: ChannelEmail, Success: true, Message: "sent"},
+			{Channel: ChannelSlack, Error: errors.New("HTTP 500"), Message: "Slack returned 500"},
+		},
+		SuccessCount: 1,
+		FailureCount: 1,
+		At:           at,
+	}
+	if err := SaveReport(beadsDir, report); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	if _, err := os.Stat(ReportPath(beadsDir, "hq-abc123")); err != nil {
+		t.Fatalf("report not written: %v", err)
+	}
+
+	got, err := LoadReport(beadsDir, "hq-abc123")
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+	if !got.At.Equal(at) || got.SuccessCount != 1 || got.FailureCount != 1 {
+		t.Errorf("loaded report = %+v", got)
+	}
+	if len(got.Results) != 2 || got.Results[1].Error == nil || got.Results[1].Error.Error() != "HTTP 500" {
+		t.Errorf("loaded results lost the error: %+v", got.Results)
+	}
+	if fc := got.FailedChannels(); len(fc) != 1 || fc[0] != ChannelSlack {
+		t.Errorf("FailedChannels() = %v, want [slack]", fc)
+	}
+
+	if _, err := LoadReport(beadsDir, "hq-missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadReport(missing) = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestSaveReportMasksWebhook(t *testing.T) {
+	t.Setenv("GT_SLACK_BOT_TOKEN", "")
+	t.Setenv("GT_SLACK_CHANNEL", "")
+	// A closed server refuses the post, and the *url.Error quotes the URL.
+	srv := httptest.NewServer(http.NotFoundHandler())
+	webhook := srv.URL + "/services/T000/B000/secret"
+	srv.Close()
+
+	beadsDir := t.TempDir()
+	report := Notify(&Notification{ID: "hq-abc123"}, []func() *Result{
+		func() *Result { return SendSlack(webhook, &Notification{ID: "hq-abc123"}) },
+	})
+	if report.FailureCount != 1 || !strings.Contains(report.Results[0].Error.Error(), "secret") {
+		t.Fatalf("expected a failed post whose error quotes the webhook, got %+v", report.Results[0])
+	}
+	if err := SaveReport(beadsDir, report); err != nil {
+		t.Fatalf("SaveReport: %v", err)
+	}
+	data, err := os.ReadFile(ReportPath(beadsDir, "hq-abc123"))
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("report leaked the webhook URL:\n%s", data)
+	}
+	if !strings.Contains(string(data), srv.Listener.Addr().String()) {
+		t.Errorf("report lost the webhook host:\n%s", data)
+	}
+}
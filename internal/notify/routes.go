@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+// EscalationTargets maps the external actions of an escalation route
+// (email:, sms:, slack, teams, opsgenie, log) to notify targets, addressed from the
+// escalation contacts. Other actions (bead, mail:) are not notify channels
+// and are ignored. Actions whose contact isn't configured are skipped, with
+// one warning per skipped action.
+func EscalationTargets(actions []string, contacts config.EscalationContacts) (targets []Target, warnings []string) {
+	add := func(channel, address, warning string) {
+		if address == "" {
+			warnings = append(warnings, warning)
+			return
+		}
+		targets = append(targets, Target{Channel: channel, Address: address})
+	}
+	for _, action := range actions {
+		switch {
+		case strings.HasPrefix(action, "email:"):
+			add(ChannelEmail, contacts.HumanEmail,
+				fmt.Sprintf("email action '%s' skipped: contacts.human_email not configured in settings/escalation.json", action))
+		case strings.HasPrefix(action, "sms:"):
+			add(ChannelSMS, contacts.HumanSMS,
+				fmt.Sprintf("sms action '%s' skipped: contacts.human_sms not configured in settings/escalation.json", action))
+		case action == "slack":
+			add(ChannelSlack, contacts.SlackWebhook,
+				"slack action skipped: contacts.slack_webhook not configured in settings/escalation.json")
+		case action == "teams":
+			add(ChannelTeams, contacts.TeamsWebhook,
+				"teams action skipped: contacts.teams_webhook not configured in settings/escalation.json")
+		case action == "opsgenie":
+			// Configured from GT_OPSGENIE_API_KEY, not the contacts.
+			targets = append(targets, Target{Channel: ChannelOpsGenie})
+		case action == "log":
+			targets = append(targets, Target{Channel: ChannelLog})
+		}
+	}
+	return targets, warnings
+}
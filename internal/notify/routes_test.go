@@ -0,0 +1,26 @@
+package notify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/config"
+)
+
+func TestEscalationTargets(t *testing.T) {
+	contacts := config.EscalationContacts{HumanEmail: "ops@example.com", SlackWebhook: "https://hooks.slack.com/x"}
+	targets, warnings := EscalationTargets([]string{"bead", "mail:mayor", "email:human", "sms:human", "slack", "opsgenie", "log"}, contacts)
+
+	want := []Target{
+		{Channel: ChannelEmail, Address: "ops@example.com"},
+		{Channel: ChannelSlack, Address: "https://hooks.slack.com/x"},
+		{Channel: ChannelOpsGenie},
+		{Channel: ChannelLog},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Errorf("targets = %+v, want %+v", targets, want)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %q, want one for the unconfigured sms contact", warnings)
+	}
+}
@@ -0,0 +1,219 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// SlackConfig holds Slack Web API configuration.
+// Loaded from environment variables:
+//   - GT_SLACK_BOT_TOKEN: Bot token (xoxb-...) with chat:write scope
+//   - GT_SLACK_CHANNEL: Channel ID to post escalations to
+//
+// Without both, Slack notifications fall back to contacts.slack_webhook.
+type SlackConfig struct {
+	BotToken string
+	Channel  string
+
+	// apiBase overrides the Slack API base URL (used by tests).
+	apiBase string
+}
+
+// SlackRef identifies a message posted through the Slack Web API.
+type SlackRef struct {
+	Channel string // channel ID
+	TS      string // message timestamp, Slack's message ID
+}
+
+// Colors for escalation messages once they leave the open state.
+const (
+	slackAckedColor  = "#439FE0" // Blue
+	slackClosedColor = "#2EB67D" // Green
+)
+
+const slackAPIBase = "https://slack.com/api"
+
+// LoadSlackConfig loads Slack configuration from environment variables.
+func LoadSlackConfig() *SlackConfig {
+	return &SlackConfig{
+		BotToken: os.Getenv("GT_SLACK_BOT_TOKEN"),
+		Channel:  os.Getenv("GT_SLACK_CHANNEL"),
+	}
+}
+
+// BotEnabled reports whether messages should go through the Web API.
+func (c *SlackConfig) BotEnabled() bool {
+	return c.BotToken != "" && c.Channel != ""
+}
+
+// UpdateSlackForAck marks the escalation's Slack message as acknowledged and
+// replies in its thread. With no bot token or no message reference, it posts
+// a follow-up message to the webhook instead, since webhook posts can't be
+// edited.
+func UpdateSlackForAck(webhookURL string, ref *SlackRef, n *Notification, ackedBy string, at time.Time) *Result {
+	status := fmt.Sprintf("Acknowledged by %s at %s", ackedBy, at.Format(time.RFC1123))
+	return updateSlack(LoadSlackConfig(), webhookURL, ref, n, slackAckedColor, status)
+}
+
+// UpdateSlackForClose marks the escalation's Slack message as closed and
+// replies in its thread, falling back to a webhook follow-up like
+// UpdateSlackForAck.
+func UpdateSlackForClose(webhookURL string, ref *SlackRef, n *Notification, closedBy, reason string, at time.Time) *Result {
+	status := fmt.Sprintf("Closed by %s at %s", closedBy, at.Format(time.RFC1123))
+	if reason != "" {
+		status += ": " + reason
+	}
+	return updateSlack(LoadSlackConfig(), webhookURL, ref, n, slackClosedColor, status)
+}
+
+// updateSlack recolors the original message and threads a status reply, or
+// posts the status as a new webhook message when the original can't be edited.
+func updateSlack(cfg *SlackConfig, webhookURL string, ref *SlackRef, n *Notification, color, status string) *Result {
+	text := fmt.Sprintf("✓ *%s* %s", n.ID, status)
+
+	if !cfg.BotEnabled() || ref == nil || ref.TS == "" {
+		if webhookURL == "" {
+			return &Result{
+				Channel: "slack",
+				Success: false,
+				Error:   fmt.Errorf("no Slack webhook URL configured"),
+				Message: "Slack update skipped: no webhook URL configured",
+			}
+		}
+		payload := map[string]interface{}{
+			"text": text,
+			"attachments": []map[string]interface{}{
+				{"color": color, "text": n.Title, "footer": "GongShow Escalation System"},
+			},
+		}
+		return postSlackWebhook(webhookURL, payload, "Posted Slack follow-up")
+	}
+
+	update := buildSlackStatusPayload(n, color, status)
+	update["channel"] = ref.Channel
+	update["ts"] = ref.TS
+	if _, err := callSlackAPI(cfg, "chat.update", update); err != nil {
+		return &Result{
+			Channel: "slack",
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("Slack update failed: %v", err),
+		}
+	}
+
+	reply := map[string]interface{}{
+		"channel":   ref.Channel,
+		"thread_ts": ref.TS,
+		"text":      text,
+	}
+	if _, err := callSlackAPI(cfg, "chat.postMessage", reply); err != nil {
+		return &Result{
+			Channel: "slack",
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("Slack message updated, but thread reply failed: %v", err),
+			Slack:   ref,
+		}
+	}
+
+	return &Result{
+		Channel: "slack",
+		Success: true,
+		Message: "Updated Slack message",
+		Slack:   ref,
+	}
+}
+
+// buildSlackStatusPayload rebuilds the original escalation message with a new
+// color and a status field, dropping the acknowledge button.
+func buildSlackStatusPayload(n *Notification, color, status string) map[string]interface{} {
+	payload := buildSlackPayload(n)
+	attachment := payload["attachments"].([]map[string]interface{})[0]
+	attachment["color"] = color
+	attachment["fields"] = append(attachment["fields"].([]map[string]interface{}), map[string]interface{}{
+		"title": "Status",
+		"value": status,
+		"short": false,
+	})
+	delete(attachment, "actions")
+	return payload
+}
+
+// postSlackMessage posts a notification with chat.postMessage so the message
+// can be updated later.
+func postSlackMessage(cfg *SlackConfig, n *Notification) *Result {
+	payload := buildSlackPayload(n)
+	payload["channel"] = cfg.Channel
+
+	ref, err := callSlackAPI(cfg, "chat.postMessage", payload)
+	if err != nil {
+		return &Result{
+			Channel: "slack",
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("Slack post failed: %v", err),
+		}
+	}
+
+	return &Result{
+		Channel: "slack",
+		Success: true,
+		Message: fmt.Sprintf("Posted to Slack channel %s", ref.Channel),
+		Slack:   ref,
+	}
+}
+
+// slackAPIResponse is the common envelope of Slack Web API responses.
+type slackAPIResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// callSlackAPI calls a Slack Web API method and returns the channel and ts
+// of the affected message. Slack reports most failures as HTTP 200 with
+// "ok": false, so both are checked.
+func callSlackAPI(cfg *SlackConfig, method string, payload map[string]interface{}) (*SlackRef, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("building Slack payload: %w", err)
+	}
+
+	base := cfg.apiBase
+	if base == "" {
+		base = slackAPIBase
+	}
+	req, err := http.NewRequest("POST", base+"/"+method, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+cfg.BotToken)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Slack %s error: %s - %s", method, resp.Status, string(body))
+	}
+
+	var r slackAPIResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, fmt.Errorf("parsing Slack %s response: %w", method, err)
+	}
+	if !r.OK {
+		return nil, fmt.Errorf("Slack %s error: %s", method, r.Error)
+	}
+	return &SlackRef{Channel: r.Channel, TS: r.TS}, nil
+}
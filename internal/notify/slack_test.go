@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slackAPICall is one request received by the fake Slack API.
+type slackAPICall struct {
+	Method  string
+	Auth    string
+	Payload map[string]interface{}
+}
+
+// newFakeSlackAPI returns a Slack Web API stand-in that records calls and
+// answers like Slack does.
+func newFakeSlackAPI(t *testing.T) (*httptest.Server, func() []slackAPICall) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []slackAPICall
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		mu.Lock()
+		calls = append(calls, slackAPICall{
+			Method:  strings.TrimPrefix(r.URL.Path, "/"),
+			Auth:    r.Header.Get("Authorization"),
+			Payload: payload,
+		})
+		mu.Unlock()
+
+		ts, _ := payload["ts"].(string)
+		if ts == "" {
+			ts = "1705312800.000200"
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"ok":      true,
+			"channel": payload["channel"],
+			"ts":      ts,
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []slackAPICall {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]slackAPICall(nil), calls...)
+	}
+}
+
+func TestSendSlackBotPost(t *testing.T) {
+	server, calls := newFakeSlackAPI(t)
+	cfg := &SlackConfig{BotToken: "xoxb-test", Channel: "C0123ABCD", apiBase: server.URL}
+
+	result := sendSlack(cfg, "https://hooks.example.invalid/unused", testTeamsNotification())
+	if !result.Success {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+	if result.Slack == nil || result.Slack.Channel != "C0123ABCD" || result.Slack.TS != "1705312800.000200" {
+		t.Errorf("unexpected Slack ref: %+v", result.Slack)
+	}
+
+	got := calls()
+	if len(got) != 1 || got[0].Method != "chat.postMessage" {
+		t.Fatalf("expected one chat.postMessage call, got %+v", got)
+	}
+	if got[0].Auth != "Bearer xoxb-test" {
+		t.Errorf("Authorization = %q", got[0].Auth)
+	}
+	if got[0].Payload["channel"] != "C0123ABCD" {
+		t.Errorf("channel = %v", got[0].Payload["channel"])
+	}
+}
+
+func TestSendSlackBotAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer server.Close()
+	cfg := &SlackConfig{BotToken: "xoxb-test", Channel: "C0123ABCD", apiBase: server.URL}
+
+	result := sendSlack(cfg, "", testTeamsNotification())
+	if result.Success {
+		t.Fatal("expected failure for ok=false response")
+	}
+	if !strings.Contains(result.Error.Error(), "channel_not_found") {
+		t.Errorf("error should include Slack's error code, got: %v", result.Error)
+	}
+}
+
+func TestSendSlackWebhookHasNoRef(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	result := sendSlack(&SlackConfig{}, server.URL, testTeamsNotification())
+	if !result.Success {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+	if result.Slack != nil {
+		t.Errorf("webhook post should not return a Slack ref, got %+v", result.Slack)
+	}
+}
+
+func TestUpdateSlackBot(t *testing.T) {
+	server, calls := newFakeSlackAPI(t)
+	cfg := &SlackConfig{BotToken: "xoxb-test", Channel: "C0123ABCD", apiBase: server.URL}
+	ref := &SlackRef{Channel: "C0123ABCD", TS: "1705312800.000200"}
+
+	n := testTeamsNotification()
+	result := updateSlack(cfg, "", ref, n, slackClosedColor, "Closed by mayor: fixed")
+	if !result.Success {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+
+	got := calls()
+	if len(got) != 2 {
+		t.Fatalf("expected chat.update and a thread reply, got %d calls", len(got))
+	}
+
+	update := got[0]
+	if update.Method != "chat.update" || update.Payload["ts"] != ref.TS || update.Payload["channel"] != ref.Channel {
+		t.Errorf("unexpected update call: %+v", update)
+	}
+	attachment := update.Payload["attachments"].([]interface{})[0].(map[string]interface{})
+	if attachment["color"] != slackClosedColor {
+		t.Errorf("color = %v, want %s", attachment["color"], slackClosedColor)
+	}
+	if attachment["text"] != n.Body {
+		t.Errorf("attachment text = %v, want the escalation description %q", attachment["text"], n.Body)
+	}
+	if _, ok := attachment["actions"]; ok {
+		t.Error("acknowledge button should be removed once closed")
+	}
+	if !strings.Contains(toJSON(t, attachment["fields"]), "Closed by mayor: fixed") {
+		t.Error("updated message should include the status")
+	}
+
+	reply := got[1]
+	if reply.Method != "chat.postMessage" || reply.Payload["thread_ts"] != ref.TS {
+		t.Errorf("expected threaded reply, got %+v", reply)
+	}
+	if !strings.Contains(reply.Payload["text"].(string), "Closed by mayor") {
+		t.Errorf("reply text = %v", reply.Payload["text"])
+	}
+}
+
+func TestUpdateSlackWebhookFallback(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	// No bot token: the reference can't be used, so a follow-up is posted.
+	ref := &SlackRef{Channel: "C0123ABCD", TS: "1705312800.000200"}
+	result := updateSlack(&SlackConfig{}, server.URL, ref, testTeamsNotification(), slackAckedColor, "Acknowledged by mayor")
+	if !result.Success {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+	if !strings.Contains(payload["text"].(string), "esc-001") || !strings.Contains(payload["text"].(string), "Acknowledged by mayor") {
+		t.Errorf("follow-up text = %v", payload["text"])
+	}
+	if _, threaded := payload["thread_ts"]; threaded {
+		t.Error("webhook follow-up should not be threaded")
+	}
+}
+
+func TestUpdateSlackForAckNoWebhook(t *testing.T) {
+	t.Setenv("GT_SLACK_BOT_TOKEN", "")
+	result := UpdateSlackForAck("", nil, testTeamsNotification(), "mayor", time.Now())
+	if result.Success {
+		t.Error("expected failure with no bot token and no webhook")
+	}
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
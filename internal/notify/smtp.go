@@ -0,0 +1,218 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP failure stages, used to tell misconfigured TLS apart from bad credentials.
+const (
+	smtpStageConnect = "connect"
+	smtpStageTLS     = "tls"
+	smtpStageAuth    = "auth"
+	smtpStageSend    = "send"
+)
+
+// smtpError records which stage of an SMTP session failed.
+type smtpError struct {
+	Stage string
+	Err   error
+}
+
+func (e *smtpError) Error() string {
+	return fmt.Sprintf("smtp %s: %v", e.Stage, e.Err)
+}
+
+func (e *smtpError) Unwrap() error {
+	return e.Err
+}
+
+// describeSMTPError renders an SMTP failure for the Result message, naming
+// the stage that failed so TLS and authentication problems are obvious.
+func describeSMTPError(err error) string {
+	var se *smtpError
+	if !errors.As(err, &se) {
+		return err.Error()
+	}
+	switch se.Stage {
+	case smtpStageTLS:
+		return fmt.Sprintf("TLS error: %v", se.Err)
+	case smtpStageAuth:
+		return fmt.Sprintf("authentication failed: %v", se.Err)
+	case smtpStageConnect:
+		return fmt.Sprintf("connection failed: %v", se.Err)
+	default:
+		return se.Err.Error()
+	}
+}
+
+// sendMail delivers msg to a single recipient, honoring the TLS mode,
+// certificate verification, and timeouts in cfg.
+func sendMail(cfg *SMTPConfig, to string, msg []byte) error {
+	mode := cfg.TLSMode
+	if mode == "" {
+		mode = defaultSMTPTLSMode(cfg.Port)
+	}
+	switch mode {
+	case SMTPTLSNone, SMTPTLSOpportunistic, SMTPTLSStartTLS, SMTPTLSImplicit:
+	default:
+		return &smtpError{Stage: smtpStageTLS, Err: fmt.Errorf("unknown TLS mode %q (want none, opportunistic, starttls, or implicit)", cfg.TLSMode)}
+	}
+
+	dialTimeout := cfg.DialTimeout
+	if dialTimeout <= 0 {
+		dialTimeout = 10 * time.Second
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.Host,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // explicit opt-in via GT_SMTP_TLS_SKIP_VERIFY
+		RootCAs:            cfg.rootCAs,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return &smtpError{Stage: smtpStageConnect, Err: err}
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		_ = conn.Close()
+		return &smtpError{Stage: smtpStageConnect, Err: err}
+	}
+
+	if mode == SMTPTLSImplicit {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return &smtpError{Stage: smtpStageTLS, Err: err}
+		}
+		conn = tlsConn
+	}
+
+	c, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return &smtpError{Stage: smtpStageConnect, Err: err}
+	}
+	defer c.Close()
+
+	if err := c.Hello("localhost"); err != nil {
+		return &smtpError{Stage: smtpStageConnect, Err: err}
+	}
+
+	if mode == SMTPTLSStartTLS || mode == SMTPTLSOpportunistic {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return &smtpError{Stage: smtpStageTLS, Err: err}
+			}
+		} else if mode == SMTPTLSStartTLS {
+			return &smtpError{Stage: smtpStageTLS, Err: fmt.Errorf("server %s does not advertise STARTTLS", addr)}
+		}
+	}
+
+	if cfg.Username != "" && cfg.Password != "" {
+		auth, err := chooseSMTPAuth(c, cfg)
+		if err != nil {
+			return &smtpError{Stage: smtpStageAuth, Err: err}
+		}
+		if err := c.Auth(auth); err != nil {
+			return &smtpError{Stage: smtpStageAuth, Err: err}
+		}
+	}
+
+	if err := c.Mail(cfg.From); err != nil {
+		return &smtpError{Stage: smtpStageSend, Err: err}
+	}
+	if err := c.Rcpt(to); err != nil {
+		return &smtpError{Stage: smtpStageSend, Err: err}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return &smtpError{Stage: smtpStageSend, Err: err}
+	}
+	if _, err := w.Write(msg); err != nil {
+		return &smtpError{Stage: smtpStageSend, Err: err}
+	}
+	if err := w.Close(); err != nil {
+		return &smtpError{Stage: smtpStageSend, Err: err}
+	}
+	if err := c.Quit(); err != nil {
+		return &smtpError{Stage: smtpStageSend, Err: err}
+	}
+	return nil
+}
+
+// chooseSMTPAuth picks PLAIN or LOGIN based on the mechanisms the server
+// advertises, preferring PLAIN.
+func chooseSMTPAuth(c *smtp.Client, cfg *SMTPConfig) (smtp.Auth, error) {
+	ok, params := c.Extension("AUTH")
+	if !ok {
+		return nil, fmt.Errorf("server does not support authentication")
+	}
+	mechs := strings.Fields(strings.ToUpper(params))
+	for _, m := range mechs {
+		if m == "PLAIN" {
+			return smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host), nil
+		}
+	}
+	for _, m := range mechs {
+		if m == "LOGIN" {
+			return &loginAuth{username: cfg.Username, password: cfg.Password, host: cfg.Host}, nil
+		}
+	}
+	return nil, fmt.Errorf("no supported auth mechanism (server offers %q)", params)
+}
+
+// loginAuth implements the non-standard but widely deployed LOGIN mechanism,
+// which net/smtp does not provide.
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	// Same guard as smtp.PlainAuth: never send credentials in the clear
+	// to anything but localhost.
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	prompt := strings.ToLower(strings.TrimSpace(string(fromServer)))
+	switch {
+	case strings.HasPrefix(prompt, "username"):
+		return []byte(a.username), nil
+	case strings.HasPrefix(prompt, "password"):
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
@@ -0,0 +1,429 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSMTPServer is a minimal in-process SMTP server supporting STARTTLS,
+// implicit TLS, and AUTH PLAIN/LOGIN. It records the last message received.
+type testSMTPServer struct {
+	t         *testing.T
+	ln        net.Listener
+	tlsConfig *tls.Config
+	implicit  bool     // Wrap connections in TLS immediately
+	startTLS  bool     // Advertise STARTTLS
+	authMechs []string // Advertised AUTH mechanisms (empty = no auth)
+	username  string
+	password  string
+
+	mu       sync.Mutex
+	messages []string
+	authed   bool
+	upgraded bool // A client used STARTTLS
+}
+
+func newTestSMTPServer(t *testing.T, srv *testSMTPServer) *testSMTPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv.t = t
+	srv.ln = ln
+	t.Cleanup(func() { _ = ln.Close() })
+	go srv.serve()
+	return srv
+}
+
+func (s *testSMTPServer) port() string {
+	_, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	return port
+}
+
+func (s *testSMTPServer) lastMessage() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.messages) == 0 {
+		return ""
+	}
+	return s.messages[len(s.messages)-1]
+}
+
+func (s *testSMTPServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *testSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	isTLS := false
+	if s.implicit {
+		conn = tls.Server(conn, s.tlsConfig)
+		isTLS = true
+	}
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+	readLine := func() (string, bool) {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return "", false
+		}
+		return strings.TrimRight(line, "\r\n"), true
+	}
+
+	reply("220 test ESMTP")
+	for {
+		line, ok := readLine()
+		if !ok {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			var exts []string
+			if s.startTLS && !isTLS {
+				exts = append(exts, "STARTTLS")
+			}
+			if len(s.authMechs) > 0 {
+				exts = append(exts, "AUTH "+strings.Join(s.authMechs, " "))
+			}
+			if len(exts) == 0 {
+				reply("250 test")
+				continue
+			}
+			reply("250-test")
+			for i, ext := range exts {
+				if i == len(exts)-1 {
+					reply("250 " + ext)
+				} else {
+					reply("250-" + ext)
+				}
+			}
+		case "STARTTLS":
+			reply("220 ready")
+			conn = tls.Server(conn, s.tlsConfig)
+			r = bufio.NewReader(conn)
+			isTLS = true
+			s.mu.Lock()
+			s.upgraded = true
+			s.mu.Unlock()
+		case "AUTH":
+			fields := strings.Fields(line)
+			var user, pass string
+			switch strings.ToUpper(fields[1]) {
+			case "PLAIN":
+				var resp string
+				if len(fields) > 2 {
+					resp = fields[2]
+				} else {
+					reply("334 ")
+					resp, _ = readLine()
+				}
+				decoded, _ := base64.StdEncoding.DecodeString(resp)
+				parts := strings.Split(string(decoded), "\x00")
+				if len(parts) == 3 {
+					user, pass = parts[1], parts[2]
+				}
+			case "LOGIN":
+				reply("334 " + base64.StdEncoding.EncodeToString([]byte("Username:")))
+				u, _ := readLine()
+				reply("334 " + base64.StdEncoding.EncodeToString([]byte("Password:")))
+				p, _ := readLine()
+				ub, _ := base64.StdEncoding.DecodeString(u)
+				pb, _ := base64.StdEncoding.DecodeString(p)
+				user, pass = string(ub), string(pb)
+			}
+			if user == s.username && pass == s.password {
+				s.mu.Lock()
+				s.authed = true
+				s.mu.Unlock()
+				reply("235 authenticated")
+			} else {
+				reply("535 authentication credentials invalid")
+			}
+		case "MAIL":
+			s.mu.Lock()
+			authed := s.authed
+			s.mu.Unlock()
+			if len(s.authMechs) > 0 && !authed {
+				reply("530 authentication required")
+				continue
+			}
+			reply("250 ok")
+		case "RCPT":
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, ok := readLine()
+				if !ok || l == "." {
+					break
+				}
+				data.WriteString(l + "\n")
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 unsupported")
+		}
+	}
+}
+
+// testCertificate generates a self-signed certificate for 127.0.0.1.
+func testCertificate(t *testing.T) (*tls.Config, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gongshow test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parsing certificate: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}, pool
+}
+
+func testNotification() *Notification {
+	return &Notification{
+		ID:        "esc-042",
+		Severity:  "high",
+		Title:     "Refinery stuck",
+		Source:    "gongshow/witness",
+		Timestamp: time.Now(),
+	}
+}
+
+func TestSendEmailSTARTTLSWithPlainAuth(t *testing.T) {
+	serverTLS, pool := testCertificate(t)
+	srv := newTestSMTPServer(t, &testSMTPServer{
+		tlsConfig: serverTLS,
+		startTLS:  true,
+		authMechs: []string{"PLAIN", "LOGIN"},
+		username:  "alerts",
+		password:  "s3cret",
+	})
+
+	cfg := &SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     srv.port(),
+		Username: "alerts",
+		Password: "s3cret",
+		From:     "gongshow@example.com",
+		TLSMode:  SMTPTLSStartTLS,
+		rootCAs:  pool,
+	}
+
+	result := sendEmail(cfg, "oncall@example.com", testNotification())
+	if !result.Success {
+		t.Fatalf("expected success, got %s (%v)", result.Message, result.Error)
+	}
+	if msg := srv.lastMessage(); !strings.Contains(msg, "Refinery stuck") {
+		t.Errorf("server did not receive message body, got %q", msg)
+	}
+}
+
+func TestSendEmailImplicitTLSWithLoginAuth(t *testing.T) {
+	serverTLS, pool := testCertificate(t)
+	srv := newTestSMTPServer(t, &testSMTPServer{
+		tlsConfig: serverTLS,
+		implicit:  true,
+		authMechs: []string{"LOGIN"},
+		username:  "alerts",
+		password:  "s3cret",
+	})
+
+	cfg := &SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     srv.port(),
+		Username: "alerts",
+		Password: "s3cret",
+		From:     "gongshow@example.com",
+		TLSMode:  SMTPTLSImplicit,
+		rootCAs:  pool,
+	}
+
+	result := sendEmail(cfg, "oncall@example.com", testNotification())
+	if !result.Success {
+		t.Fatalf("expected success, got %s (%v)", result.Message, result.Error)
+	}
+	if msg := srv.lastMessage(); !strings.Contains(msg, "esc-042") {
+		t.Errorf("server did not receive message, got %q", msg)
+	}
+}
+
+func TestSendEmailAuthFailure(t *testing.T) {
+	serverTLS, pool := testCertificate(t)
+	srv := newTestSMTPServer(t, &testSMTPServer{
+		tlsConfig: serverTLS,
+		startTLS:  true,
+		authMechs: []string{"PLAIN"},
+		username:  "alerts",
+		password:  "s3cret",
+	})
+
+	cfg := &SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     srv.port(),
+		Username: "alerts",
+		Password: "wrong",
+		From:     "gongshow@example.com",
+		TLSMode:  SMTPTLSStartTLS,
+		rootCAs:  pool,
+	}
+
+	result := sendEmail(cfg, "oncall@example.com", testNotification())
+	if result.Success {
+		t.Fatal("expected auth failure")
+	}
+	if !strings.Contains(result.Message, "authentication failed") {
+		t.Errorf("expected auth error in message, got %q", result.Message)
+	}
+}
+
+func TestSendEmailUntrustedCertificate(t *testing.T) {
+	serverTLS, _ := testCertificate(t)
+	srv := newTestSMTPServer(t, &testSMTPServer{
+		tlsConfig: serverTLS,
+		startTLS:  true,
+	})
+
+	cfg := &SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    srv.port(),
+		From:    "gongshow@example.com",
+		TLSMode: SMTPTLSStartTLS,
+	}
+
+	result := sendEmail(cfg, "oncall@example.com", testNotification())
+	if result.Success {
+		t.Fatal("expected certificate verification to fail")
+	}
+	if !strings.Contains(result.Message, "TLS error") {
+		t.Errorf("expected TLS error in message, got %q", result.Message)
+	}
+
+	cfg.InsecureSkipVerify = true
+	result = sendEmail(cfg, "oncall@example.com", testNotification())
+	if !result.Success {
+		t.Errorf("expected success with skip-verify, got %s", result.Message)
+	}
+}
+
+func TestSendEmailSTARTTLSNotAdvertised(t *testing.T) {
+	srv := newTestSMTPServer(t, &testSMTPServer{})
+
+	cfg := &SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    srv.port(),
+		From:    "gongshow@example.com",
+		TLSMode: SMTPTLSStartTLS,
+	}
+
+	result := sendEmail(cfg, "oncall@example.com", testNotification())
+	if result.Success {
+		t.Fatal("expected failure when STARTTLS is required but not offered")
+	}
+	if !strings.Contains(result.Message, "TLS error") {
+		t.Errorf("expected TLS error in message, got %q", result.Message)
+	}
+}
+
+func TestSendEmailPlainConnection(t *testing.T) {
+	srv := newTestSMTPServer(t, &testSMTPServer{})
+
+	cfg := &SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    srv.port(),
+		From:    "gongshow@example.com",
+		TLSMode: SMTPTLSNone,
+	}
+
+	result := sendEmail(cfg, "oncall@example.com", testNotification())
+	if !result.Success {
+		t.Fatalf("expected success, got %s (%v)", result.Message, result.Error)
+	}
+}
+
+func TestSendEmailOpportunisticTLS(t *testing.T) {
+	serverTLS, pool := testCertificate(t)
+	for _, advertise := range []bool{true, false} {
+		srv := newTestSMTPServer(t, &testSMTPServer{tlsConfig: serverTLS, startTLS: advertise})
+
+		cfg := &SMTPConfig{
+			Host:    "127.0.0.1",
+			Port:    srv.port(),
+			From:    "gongshow@example.com",
+			TLSMode: SMTPTLSOpportunistic,
+			rootCAs: pool,
+		}
+
+		result := sendEmail(cfg, "oncall@example.com", testNotification())
+		if !result.Success {
+			t.Fatalf("STARTTLS advertised=%v: expected success, got %s (%v)", advertise, result.Message, result.Error)
+		}
+		srv.mu.Lock()
+		upgraded := srv.upgraded
+		srv.mu.Unlock()
+		if upgraded != advertise {
+			t.Errorf("STARTTLS advertised=%v: connection upgraded = %v", advertise, upgraded)
+		}
+	}
+}
+
+func TestDefaultSMTPTLSMode(t *testing.T) {
+	tests := map[string]string{
+		"465": SMTPTLSImplicit,
+		"25":  SMTPTLSOpportunistic,
+		"587": SMTPTLSStartTLS,
+	}
+	for port, want := range tests {
+		if got := defaultSMTPTLSMode(port); got != want {
+			t.Errorf("defaultSMTPTLSMode(%q) = %q, want %q", port, got, want)
+		}
+	}
+}
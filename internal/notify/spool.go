@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+// SpoolEntry is a deferred send waiting in the retry spool.
+type SpoolEntry struct {
+	Target       Target        `json:"target"`
+	Notification *Notification `json:"notification"`
+	NotBefore    time.Time     `json:"not_before"`
+	Reason       string        `json:"reason"` // why it was deferred
+	CreatedAt    time.Time     `json:"created_at"`
+	Attempts     int           `json:"attempts"`
+
+	path string
+}
+
+// Spool is a directory of deferred sends, one JSON file per entry.
+type Spool struct {
+	dir string
+}
+
+// NewSpool returns the retry spool for a town.
+func NewSpool(townRoot string) *Spool {
+	return &Spool{dir: filepath.Join(townRoot, ".runtime", "notify-spool")}
+}
+
+// Add writes an entry to the spool.
+func (s *Spool) Add(entry *SpoolEntry) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("creating spool dir: %w", err)
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if entry.path == "" {
+		name := fmt.Sprintf("%d-%s.json", entry.CreatedAt.UnixNano(), sanitizeSpoolName(entry.Target.Channel))
+		entry.path = filepath.Join(s.dir, name)
+	}
+	if err := util.AtomicWriteJSON(entry.path, entry); err != nil {
+		return fmt.Errorf("writing spool entry: %w", err)
+	}
+	return nil
+}
+
+// List returns all spooled entries, oldest first.
+func (s *Spool) List() ([]*SpoolEntry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading spool: %w", err)
+	}
+
+	var entries []*SpoolEntry
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(s.dir, f.Name())
+		data, err := os.ReadFile(path) //nolint:gosec // G304: path is within the spool dir
+		if err != nil {
+			continue
+		}
+		var entry SpoolEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.Notification == nil {
+			continue // Skip corrupt entries
+		}
+		entry.path = path
+		entries = append(entries, &entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+// Due returns the entries whose NotBefore has passed.
+func (s *Spool) Due(now time.Time) ([]*SpoolEntry, error) {
+	entries, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var due []*SpoolEntry
+	for _, e := range entries {
+		if !now.Before(e.NotBefore) {
+			due = append(due, e)
+		}
+	}
+	return due, nil
+}
+
+// Remove deletes an entry from the spool.
+func (s *Spool) Remove(entry *SpoolEntry) error {
+	if entry.path == "" {
+		return nil
+	}
+	if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing spool entry: %w", err)
+	}
+	return nil
+}
+
+func sanitizeSpoolName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.ToLower(s))
+}
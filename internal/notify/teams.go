@@ -0,0 +1,248 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxTeamsPayload is the largest message Teams incoming webhooks accept.
+const MaxTeamsPayload = 28 * 1024
+
+// teamsLegacyCardEnv selects the legacy MessageCard format instead of an
+// Adaptive Card, for older Office 365 connectors that don't render the latter.
+const teamsLegacyCardEnv = "GT_TEAMS_MESSAGECARD"
+
+// SendTeams posts a notification to a Microsoft Teams incoming webhook.
+// Sends an Adaptive Card by default; set GT_TEAMS_MESSAGECARD=true to send a
+// legacy MessageCard instead.
+func SendTeams(webhookURL string, n *Notification) *Result {
+	return sendTeams(webhookURL, n, envBool(teamsLegacyCardEnv))
+}
+
+func sendTeams(webhookURL string, n *Notification, legacy bool) *Result {
+	if webhookURL == "" {
+		return &Result{
+			Channel: "teams",
+			Success: false,
+			Error:   fmt.Errorf("no Teams webhook URL configured"),
+			Message: "Teams skipped: no webhook URL configured",
+		}
+	}
+
+	jsonData, err := buildTeamsPayload(n, legacy)
+	if err != nil {
+		return &Result{
+			Channel: "teams",
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("Failed to build Teams payload: %v", err),
+		}
+	}
+
+	req, err := http.NewRequest("POST", webhookURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return &Result{
+			Channel: "teams",
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("Failed to create Teams request: %v", err),
+		}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return &Result{
+			Channel: "teams",
+			Success: false,
+			Error:   err,
+			Message: fmt.Sprintf("Failed to post to Teams: %v", err),
+		}
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &Result{
+			Channel: "teams",
+			Success: false,
+			Error:   fmt.Errorf("Teams webhook error: %s - %s", resp.Status, string(respBody)),
+			Message: fmt.Sprintf("Teams post failed: %s", resp.Status),
+		}
+	}
+
+	// Connector webhooks answer "1" on success, but some failures (throttling,
+	// card rendering errors) come back as 200 with an error message in the body.
+	if body := strings.TrimSpace(string(respBody)); resp.StatusCode == http.StatusOK && body != "" && body != "1" {
+		return &Result{
+			Channel: "teams",
+			Success: false,
+			Error:   fmt.Errorf("Teams webhook error: %s", body),
+			Message: fmt.Sprintf("Teams post failed: %s", body),
+		}
+	}
+
+	return &Result{
+		Channel: "teams",
+		Success: true,
+		Message: "Posted to Teams",
+	}
+}
+
+// teamsTruncatedNote is appended to bodies cut down to fit MaxTeamsPayload.
+const teamsTruncatedNote = "\n\n… (truncated: %d bytes omitted to fit the Teams 28KB limit)"
+
+// buildTeamsPayload marshals the card, truncating the body if needed to fit
+// within MaxTeamsPayload.
+func buildTeamsPayload(n *Notification, legacy bool) ([]byte, error) {
+	build := buildTeamsAdaptiveCard
+	if legacy {
+		build = buildTeamsMessageCard
+	}
+
+	kept := n.Body
+	body := n.Body
+	for {
+		data, err := json.Marshal(build(n, body))
+		if err != nil {
+			return nil, err
+		}
+		over := len(data) - MaxTeamsPayload
+		if over <= 0 {
+			return data, nil
+		}
+		if kept == "" {
+			return nil, fmt.Errorf("payload exceeds the %d byte Teams limit even without a body", MaxTeamsPayload)
+		}
+
+		// JSON escaping can make the body larger on the wire than in Go, so
+		// trim by at least the overflow plus the note and re-check.
+		kept = truncateUTF8(kept, len(kept)-over-len(teamsTruncatedNote)-16)
+		body = kept + fmt.Sprintf(teamsTruncatedNote, len(n.Body)-len(kept))
+	}
+}
+
+// truncateUTF8 cuts s to at most max bytes without splitting a rune.
+func truncateUTF8(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
+
+// teamsFacts returns the ID/source/related facts shown on the card.
+func teamsFacts(n *Notification) [][2]string {
+	facts := [][2]string{
+		{"Severity", strings.ToUpper(n.Severity)},
+		{"ID", n.ID},
+		{"Source", n.Source},
+	}
+	if n.RelatedBead != "" {
+		facts = append(facts, [2]string{"Related", n.RelatedBead})
+	}
+	return facts
+}
+
+// teamsStyle maps severity to Adaptive Card container style and text color.
+func teamsStyle(severity string) (containerStyle, textColor string) {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "attention", "Attention"
+	case "high":
+		return "warning", "Warning"
+	case "medium":
+		return "accent", "Accent"
+	default:
+		return "emphasis", "Default"
+	}
+}
+
+// buildTeamsAdaptiveCard creates an Adaptive Card message for an incoming webhook.
+func buildTeamsAdaptiveCard(n *Notification, body string) map[string]interface{} {
+	containerStyle, textColor := teamsStyle(n.Severity)
+
+	var facts []map[string]interface{}
+	for _, f := range teamsFacts(n) {
+		facts = append(facts, map[string]interface{}{"title": f[0], "value": f[1]})
+	}
+
+	cardBody := []map[string]interface{}{
+		{
+			"type":  "Container",
+			"style": containerStyle,
+			"bleed": true,
+			"items": []map[string]interface{}{
+				{
+					"type":   "TextBlock",
+					"text":   fmt.Sprintf("%s Escalation: %s", severityEmoji(n.Severity), n.Title),
+					"weight": "Bolder",
+					"size":   "Medium",
+					"color":  textColor,
+					"wrap":   true,
+				},
+			},
+		},
+		{
+			"type":  "FactSet",
+			"facts": facts,
+		},
+	}
+	if body != "" {
+		cardBody = append(cardBody, map[string]interface{}{
+			"type": "TextBlock",
+			"text": body,
+			"wrap": true,
+		})
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    cardBody,
+				},
+			},
+		},
+	}
+}
+
+// buildTeamsMessageCard creates a legacy Office 365 connector MessageCard.
+func buildTeamsMessageCard(n *Notification, body string) map[string]interface{} {
+	var facts []map[string]interface{}
+	for _, f := range teamsFacts(n) {
+		facts = append(facts, map[string]interface{}{"name": f[0], "value": f[1]})
+	}
+
+	section := map[string]interface{}{"facts": facts}
+	if body != "" {
+		section["text"] = body
+	}
+
+	return map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    "Escalation: " + n.Title,
+		"themeColor": strings.TrimPrefix(severityColor(n.Severity), "#"),
+		"title":      fmt.Sprintf("%s Escalation: %s", severityEmoji(n.Severity), n.Title),
+		"sections":   []map[string]interface{}{section},
+	}
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testTeamsNotification() *Notification {
+	return &Notification{
+		ID:          "esc-001",
+		Severity:    "critical",
+		Title:       "Refinery stuck",
+		Body:        "Merge queue has not advanced in 2h",
+		Source:      "gongshow/witness",
+		RelatedBead: "gt-123",
+		Timestamp:   time.Now(),
+	}
+}
+
+func TestSendTeamsNoWebhook(t *testing.T) {
+	result := SendTeams("", testTeamsNotification())
+	if result.Success {
+		t.Error("expected failure with no webhook")
+	}
+	if result.Channel != "teams" {
+		t.Errorf("expected channel=teams, got %s", result.Channel)
+	}
+}
+
+func TestSendTeamsAdaptiveCard(t *testing.T) {
+	var payload struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string                   `json:"type"`
+				Body []map[string]interface{} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected Content-Type=application/json")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	result := sendTeams(server.URL, testTeamsNotification(), false)
+	if !result.Success {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+
+	if payload.Type != "message" || len(payload.Attachments) != 1 {
+		t.Fatalf("unexpected envelope: %+v", payload)
+	}
+	att := payload.Attachments[0]
+	if att.ContentType != "application/vnd.microsoft.card.adaptive" || att.Content.Type != "AdaptiveCard" {
+		t.Errorf("unexpected attachment: %s / %s", att.ContentType, att.Content.Type)
+	}
+	if len(att.Content.Body) != 3 {
+		t.Fatalf("expected header, facts and body blocks, got %d", len(att.Content.Body))
+	}
+
+	header := att.Content.Body[0]
+	if header["style"] != "attention" {
+		t.Errorf("critical header style = %v, want attention", header["style"])
+	}
+
+	facts := map[string]string{}
+	for _, f := range att.Content.Body[1]["facts"].([]interface{}) {
+		fm := f.(map[string]interface{})
+		facts[fm["title"].(string)] = fm["value"].(string)
+	}
+	for key, want := range map[string]string{"ID": "esc-001", "Source": "gongshow/witness", "Related": "gt-123"} {
+		if facts[key] != want {
+			t.Errorf("fact %s = %q, want %q", key, facts[key], want)
+		}
+	}
+
+	if text := att.Content.Body[2]["text"]; text != "Merge queue has not advanced in 2h" {
+		t.Errorf("body text = %v", text)
+	}
+}
+
+func TestSendTeamsMessageCard(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	result := sendTeams(server.URL, testTeamsNotification(), true)
+	if !result.Success {
+		t.Fatalf("expected success, got error: %v", result.Error)
+	}
+	if payload["@type"] != "MessageCard" {
+		t.Errorf("@type = %v, want MessageCard", payload["@type"])
+	}
+	if payload["themeColor"] != "FF0000" {
+		t.Errorf("themeColor = %v, want FF0000", payload["themeColor"])
+	}
+}
+
+func TestSendTeamsFake200Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Microsoft Teams endpoint returned HTTP error 429 with ContextId tcid=0"))
+	}))
+	defer server.Close()
+
+	result := sendTeams(server.URL, testTeamsNotification(), false)
+	if result.Success {
+		t.Fatal("expected failure when Teams returns 200 with an error body")
+	}
+	if !strings.Contains(result.Error.Error(), "429") {
+		t.Errorf("error should include the Teams response, got %v", result.Error)
+	}
+}
+
+func TestSendTeamsAcceptedEmptyBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	if result := sendTeams(server.URL, testTeamsNotification(), false); !result.Success {
+		t.Errorf("202 with empty body should succeed, got %v", result.Error)
+	}
+}
+
+func TestSendTeamsServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad card"))
+	}))
+	defer server.Close()
+
+	if result := sendTeams(server.URL, testTeamsNotification(), false); result.Success {
+		t.Error("expected failure on 400")
+	}
+}
+
+func TestBuildTeamsPayloadTruncatesLongBody(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		n := testTeamsNotification()
+		// Characters that JSON escapes grow on the wire; make sure we still fit.
+		n.Body = strings.Repeat("<é>", 20000)
+
+		data, err := buildTeamsPayload(n, legacy)
+		if err != nil {
+			t.Fatalf("legacy=%v: %v", legacy, err)
+		}
+		if len(data) > MaxTeamsPayload {
+			t.Errorf("legacy=%v: payload is %d bytes, over %d", legacy, len(data), MaxTeamsPayload)
+		}
+		if !strings.Contains(string(data), "truncated") {
+			t.Errorf("legacy=%v: truncated payload should carry a note", legacy)
+		}
+		if !json.Valid(data) {
+			t.Errorf("legacy=%v: payload is not valid JSON", legacy)
+		}
+	}
+}
+
+func TestBuildTeamsPayloadShortBodyUntouched(t *testing.T) {
+	data, err := buildTeamsPayload(testTeamsNotification(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "truncated") {
+		t.Error("short body should not be truncated")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/KeithWyatt/gongshow/internal/util"
 )
 
 //go:embed plugin/gongshow.js
@@ -32,7 +34,7 @@ func EnsurePluginAt(workDir, pluginDir, pluginFile string) error {
 		return fmt.Errorf("reading plugin template: %w", err)
 	}
 
-	if err := os.WriteFile(pluginPath, content, 0644); err != nil {
+	if err := util.AtomicWriteFileUnique(pluginPath, content, 0644); err != nil {
 		return fmt.Errorf("writing plugin: %w", err)
 	}
 
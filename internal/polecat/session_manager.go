@@ -10,8 +10,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/KeithWyatt/gongshow/internal/beads"
 	"github.com/KeithWyatt/gongshow/internal/config"
 	"github.com/KeithWyatt/gongshow/internal/constants"
+	"github.com/KeithWyatt/gongshow/internal/events"
 	"github.com/KeithWyatt/gongshow/internal/rig"
 	"github.com/KeithWyatt/gongshow/internal/runtime"
 	"github.com/KeithWyatt/gongshow/internal/session"
@@ -62,6 +64,10 @@ type SessionStartOptions struct {
 	// RuntimeConfigDir is resolved config directory for the runtime account.
 	// If set, this is injected as an environment variable.
 	RuntimeConfigDir string
+
+	// CorrelationID, if set, is passed to the agent as GT_CORRELATION_ID so
+	// the events it logs join the chain of the command that started it.
+	CorrelationID string
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -102,10 +108,11 @@ func (m *SessionManager) polecatDir(polecat string) string {
 	return filepath.Join(m.rig.Path, "polecats", polecat)
 }
 
-// clonePath returns the path where the git worktree lives.
+// ClonePath returns the path where the polecat's git worktree lives, which
+// its session runs in.
 // New structure: polecats/<name>/<rigname>/ - gives LLMs recognizable repo context.
 // Falls back to old structure: polecats/<name>/ for backward compatibility.
-func (m *SessionManager) clonePath(polecat string) string {
+func (m *SessionManager) ClonePath(polecat string) string {
 	// New structure: polecats/<name>/<rigname>/
 	newPath := filepath.Join(m.rig.Path, "polecats", polecat, m.rig.Name)
 	if info, err := os.Stat(newPath); err == nil && info.IsDir() {
@@ -126,6 +133,12 @@ func (m *SessionManager) clonePath(polecat string) string {
 	return newPath
 }
 
+// BuildStartCommand returns the command polecat of the rig at rigPath is
+// started with, unless SessionStartOptions.Command overrides it.
+func BuildStartCommand(rigPath, rigName, polecat string) string {
+	return config.BuildPolecatStartupCommand(rigName, polecat, rigPath, "")
+}
+
 // hasPolecat checks if the polecat exists in this rig.
 func (m *SessionManager) hasPolecat(polecat string) bool {
 	polecatPath := m.polecatDir(polecat)
@@ -158,7 +171,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Determine working directory
 	workDir := opts.WorkDir
 	if workDir == "" {
-		workDir = m.clonePath(polecat)
+		workDir = m.ClonePath(polecat)
 	}
 
 	runtimeConfig := config.LoadRuntimeConfig(m.rig.Path)
@@ -173,12 +186,15 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Build startup command first
 	command := opts.Command
 	if command == "" {
-		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, "")
+		command = BuildStartCommand(m.rig.Path, m.rig.Name, polecat)
 	}
 	// Prepend runtime config dir env if needed
 	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
 		command = config.PrependEnv(command, map[string]string{runtimeConfig.Session.ConfigDirEnv: opts.RuntimeConfigDir})
 	}
+	if opts.CorrelationID != "" {
+		command = config.PrependEnv(command, map[string]string{events.CorrelationEnv: opts.CorrelationID})
+	}
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gongshow/issues/280
@@ -189,14 +205,15 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
 	townRoot := filepath.Dir(m.rig.Path)
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	roleConfig, _ := beads.LoadRoleConfig(townRoot, "polecat")
+	envVars := beads.AgentEnv(config.AgentEnvConfig{
 		Role:             "polecat",
 		Rig:              m.rig.Name,
 		AgentName:        polecat,
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.RuntimeConfigDir,
 		BeadsNoDaemon:    true,
-	})
+	}, sessionID, roleConfig)
 	for k, v := range envVars {
 		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
 	}
@@ -0,0 +1,66 @@
+package proc
+
+import "strings"
+
+// Environ returns the environment pid was started with: on Linux from
+// /proc/<pid>/environ, on macOS from sysctl kern.procargs2. Changes the
+// process has made to its environment since are not seen. Only the
+// process's owner or root can read it; for anyone else's process the
+// error wraps os.ErrPermission on Linux.
+func Environ(pid int) (map[string]string, error) {
+	return environ(pid)
+}
+
+// parseEnviron parses KEY=VALUE entries; others are skipped.
+func parseEnviron(entries []string) map[string]string {
+	env := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if key, value, ok := strings.Cut(e, "="); ok && key != "" {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// AgentIdentity is the agent a process runs as: the GT_ROLE, GT_RIG and
+// BD_ACTOR in its environment, which the startup command exports from the
+// agent's config.AgentEnvConfig (package internal/config).
+type AgentIdentity struct {
+	Role  string `json:"role,omitempty"`  // GT_ROLE
+	Rig   string `json:"rig,omitempty"`   // GT_RIG
+	Actor string `json:"actor,omitempty"` // BD_ACTOR
+}
+
+// IdentityFromEnv returns the agent identity in env; the zero identity
+// if it has none.
+func IdentityFromEnv(env map[string]string) AgentIdentity {
+	return AgentIdentity{Role: env["GT_ROLE"], Rig: env["GT_RIG"], Actor: env["BD_ACTOR"]}
+}
+
+// Identity returns the agent identity in pid's environment (see Environ).
+func Identity(pid int) (AgentIdentity, error) {
+	env, err := Environ(pid)
+	if err != nil {
+		return AgentIdentity{}, err
+	}
+	return IdentityFromEnv(env), nil
+}
+
+// IsZero reports whether the identity is empty: the process isn't an agent.
+func (id AgentIdentity) IsZero() bool {
+	return id == AgentIdentity{}
+}
+
+// String names the agent: BD_ACTOR, or failing that the rig and role.
+func (id AgentIdentity) String() string {
+	switch {
+	case id.Actor != "":
+		return id.Actor
+	case id.Rig != "" && id.Role != "":
+		return id.Rig + "/" + id.Role
+	case id.Role != "":
+		return id.Role
+	default:
+		return id.Rig
+	}
+}
@@ -0,0 +1,115 @@
+package proc
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseEnviron(t *testing.T) {
+	env := parseEnviron([]string{"GT_ROLE=polecat", "EMPTY=", "EQ=a=b", "garbage", "=nokey", ""})
+	want := map[string]string{"GT_ROLE": "polecat", "EMPTY": "", "EQ": "a=b"}
+	if len(env) != len(want) {
+		t.Errorf("parseEnviron() = %q, want %q", env, want)
+	}
+	for k, v := range want {
+		if got, ok := env[k]; !ok || got != v {
+			t.Errorf("parseEnviron()[%s] = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestAgentIdentityString(t *testing.T) {
+	for _, tc := range []struct {
+		id   AgentIdentity
+		want string
+	}{
+		{AgentIdentity{Role: "polecat", Rig: "gongshow", Actor: "gongshow/polecats/Toast"}, "gongshow/polecats/Toast"},
+		{AgentIdentity{Role: "witness", Rig: "gongshow"}, "gongshow/witness"},
+		{AgentIdentity{Role: "mayor"}, "mayor"},
+		{AgentIdentity{}, ""},
+	} {
+		if got := tc.id.String(); got != tc.want {
+			t.Errorf("%+v.String() = %q, want %q", tc.id, got, tc.want)
+		}
+	}
+	if !(AgentIdentity{}).IsZero() || (AgentIdentity{Role: "mayor"}).IsZero() {
+		t.Error("IsZero() is wrong")
+	}
+}
+
+func TestEnvironSelf(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("environments can't be read on this platform")
+	}
+	// The environment read is the one the process started with, so a
+	// variable set now with os.Setenv wouldn't show; this one is inherited.
+	env, err := Environ(os.Getpid())
+	if err != nil {
+		t.Fatalf("Environ(self) error: %v", err)
+	}
+	if path, ok := os.LookupEnv("PATH"); ok && env["PATH"] != path {
+		t.Errorf("Environ(self)[PATH] = %q, want %q", env["PATH"], path)
+	}
+}
+
+func TestIdentityOfChild(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("environments can't be read on this platform")
+	}
+	cmd := exec.Command("sleep", "30")
+	cmd.Env = append(os.Environ(),
+		"GT_ENVIRON_SENTINEL=b5a1c0de",
+		"GT_ROLE=polecat",
+		"GT_RIG=gongshow",
+		"BD_ACTOR=gongshow/polecats/Toast",
+	)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting sleep: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill(); _ = cmd.Wait() })
+
+	// Until the child has exec'd sleep, its environment is the test's.
+	var env map[string]string
+	for i := 0; i < 500 && env["GT_ENVIRON_SENTINEL"] == ""; i++ {
+		env, _ = Environ(cmd.Process.Pid)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if env["GT_ENVIRON_SENTINEL"] != "b5a1c0de" {
+		t.Fatalf("Environ(child) has no sentinel: %q", env)
+	}
+
+	id, err := Identity(cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("Identity(child) error: %v", err)
+	}
+	want := AgentIdentity{Role: "polecat", Rig: "gongshow", Actor: "gongshow/polecats/Toast"}
+	if id != want {
+		t.Errorf("Identity(child) = %+v, want %+v", id, want)
+	}
+}
+
+func TestEnvironPermissionDenied(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("requires /proc")
+	}
+	// Another user's /proc/<pid>/environ is mode 0400.
+	orig := readProcFile
+	t.Cleanup(func() { readProcFile = orig })
+	readProcFile = func(path string) ([]byte, error) {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: syscall.EACCES}
+	}
+
+	if _, err := Environ(1); !errors.Is(err, os.ErrPermission) {
+		t.Errorf("Environ() error = %v, want a permission error", err)
+	}
+	id, err := Identity(1)
+	if !errors.Is(err, os.ErrPermission) || !id.IsZero() {
+		t.Errorf("Identity() = %+v, %v; want no identity and a permission error", id, err)
+	}
+}
@@ -0,0 +1,93 @@
+package proc
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ArgvMatcher reports whether a process, given its argv, is one being
+// looked for. Unlike FindByPattern's substring test, matchers see the
+// arguments one by one, so "bd daemon" in an editor's file name argument
+// is not taken for the bd daemon.
+type ArgvMatcher func(argv []string) bool
+
+// MatchRegexp matches processes whose argv, joined by spaces, matches re.
+// Anchor it (e.g. `^bd daemon( |$)`) to keep arguments from matching.
+func MatchRegexp(re *regexp.Regexp) ArgvMatcher {
+	return func(argv []string) bool {
+		return re.MatchString(strings.Join(argv, " "))
+	}
+}
+
+// MatchExactArgs matches processes whose argv begins with argv0 and then
+// args, each compared whole. Arguments after those, such as flags, are
+// not compared.
+func MatchExactArgs(argv0 string, args ...string) ArgvMatcher {
+	want := append([]string{argv0}, args...)
+	return func(argv []string) bool {
+		if len(argv) < len(want) {
+			return false
+		}
+		for i, w := range want {
+			if argv[i] != w {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchBasename restricts the executable in argv[0] to its base name
+// before m sees it, so "bd" matches however bd was run: as bd,
+// /usr/local/bin/bd or ./bd.
+func MatchBasename(m ArgvMatcher) ArgvMatcher {
+	return func(argv []string) bool {
+		if len(argv) == 0 {
+			return m(argv)
+		}
+		return m(append([]string{filepath.Base(argv[0])}, argv[1:]...))
+	}
+}
+
+// FindMatching returns the PIDs of processes whose argv m matches, from a
+// fresh Snapshot. On Linux argv is read from /proc/<pid>/cmdline, split
+// at its NULs; on macOS it comes from sysctl kern.procargs2, which for
+// other users' processes is only readable as root. Elsewhere it finds
+// nothing. Zombies are left out: they have exited, whatever their argv.
+func FindMatching(m ArgvMatcher) []int {
+	t, err := Snapshot()
+	if err != nil {
+		return nil
+	}
+	return t.WithoutZombies().FindMatching(m)
+}
+
+// CountMatching counts the processes whose argv m matches.
+func CountMatching(m ArgvMatcher) int {
+	return len(FindMatching(m))
+}
+
+// FindByRegexp returns the PIDs of processes whose argv matches re; see
+// MatchRegexp.
+func FindByRegexp(re *regexp.Regexp) []int {
+	return FindMatching(MatchRegexp(re))
+}
+
+// CountByRegexp counts the processes whose argv matches re.
+func CountByRegexp(re *regexp.Regexp) int {
+	return len(FindByRegexp(re))
+}
+
+// FindByExactArgs returns the PIDs of processes whose argv begins with
+// argv0 and args; see MatchExactArgs. Wrap the matcher in MatchBasename,
+// with FindMatching, to match argv0 whatever path it was run by.
+func FindByExactArgs(argv0 string, args ...string) []int {
+	return FindMatching(MatchExactArgs(argv0, args...))
+}
+
+// CountByExactArgs counts the processes whose argv begins with argv0 and
+// args.
+func CountByExactArgs(argv0 string, args ...string) int {
+	return len(FindByExactArgs(argv0, args...))
+}
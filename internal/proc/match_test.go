@@ -0,0 +1,181 @@
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Command lines that mention bd daemon without being it.
+var bdLookalikes = [][]string{
+	{"vim", "/home/max/notes/bd daemon.md"},          // In a file name
+	{"nvim", "bd daemon"},                            // One argument
+	{"grep", "-r", "bd daemon", "."},                 // A search
+	{"bd", "daemonize"},                              // A longer word
+	{"bdx", "daemon"},                                // Another program
+	{"sh", "-c", "bd daemon --start"},                // Not yet exec'd
+	{"/usr/bin/less", "/tmp/bd", "daemon"},           // Split across arguments
+	{"/home/max/bd/bin/tool", "daemon"},              // bd in the path
+	{"python3", "/opt/bd", "daemon", "--foreground"}, // bd as a script
+}
+
+func TestMatchExactArgs(t *testing.T) {
+	m := MatchExactArgs("bd", "daemon")
+	for _, argv := range [][]string{
+		{"bd", "daemon"},
+		{"bd", "daemon", "--start"},
+	} {
+		if !m(argv) {
+			t.Errorf("MatchExactArgs(bd, daemon) doesn't match %q", argv)
+		}
+	}
+	for _, argv := range append(bdLookalikes, []string{"/usr/local/bin/bd", "daemon"}, []string{"bd"}, nil) {
+		if m(argv) {
+			t.Errorf("MatchExactArgs(bd, daemon) matches %q", argv)
+		}
+	}
+}
+
+func TestMatchBasename(t *testing.T) {
+	m := MatchBasename(MatchExactArgs("bd", "daemon"))
+	for _, argv := range [][]string{
+		{"bd", "daemon"},
+		{"/usr/local/bin/bd", "daemon", "--start"},
+		{"./bd", "daemon"},
+	} {
+		if !m(argv) {
+			t.Errorf("MatchBasename(bd daemon) doesn't match %q", argv)
+		}
+	}
+	for _, argv := range append(bdLookalikes, nil) {
+		if m(argv) {
+			t.Errorf("MatchBasename(bd daemon) matches %q", argv)
+		}
+	}
+}
+
+func TestMatchRegexp(t *testing.T) {
+	m := MatchBasename(MatchRegexp(regexp.MustCompile(`^bd daemon( |$)`)))
+	if !m([]string{"/usr/local/bin/bd", "daemon", "--start"}) {
+		t.Error("anchored regexp doesn't match bd daemon --start")
+	}
+	for _, argv := range bdLookalikes {
+		if m(argv) && !slices.Contains(argv, "bd daemon") {
+			t.Errorf("anchored regexp matches %q", argv)
+		}
+	}
+
+	// A regexp sees the arguments joined, so only anchoring keeps an
+	// argument from matching.
+	if !MatchRegexp(regexp.MustCompile(`bd daemon`))([]string{"vim", "/home/max/notes/bd daemon.md"}) {
+		t.Error("unanchored regexp doesn't match the joined arguments")
+	}
+}
+
+// startArgv starts a process that sleeps with the given argv, argv[0]
+// included, and returns its PID once it shows that argv. It is sh with a
+// script: argv[1] names the script, which startArgv writes, relative to
+// the working directory dir.
+func startArgv(t *testing.T, dir string, argv ...string) int {
+	t.Helper()
+	script := argv[1]
+	if !filepath.IsAbs(script) {
+		script = filepath.Join(dir, script)
+	}
+	if err := os.MkdirAll(filepath.Dir(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// The exit stops sh exec'ing sleep in its place.
+	if err := os.WriteFile(script, []byte("sleep 30\nexit 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh")
+	}
+	cmd := &exec.Cmd{Path: sh, Args: argv, Dir: dir}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting %q: %v", argv, err)
+	}
+	go func() { _ = cmd.Wait() }()
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	// Until it execs, the child has the test's argv.
+	pid := cmd.Process.Pid
+	deadline := time.Now().Add(5 * time.Second)
+	for !slices.Contains(FindMatching(func(a []string) bool { return slices.Equal(a, argv) }), pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("pid %d never showed argv %q", pid, argv)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return pid
+}
+
+func TestFindByExactArgsLive(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("requires /proc")
+	}
+	dir := t.TempDir()
+	daemon := startArgv(t, dir, "bd", "daemon")
+	pathDaemon := startArgv(t, dir, "/usr/local/bin/bd", "daemon", "--start")
+	lookalikes := []int{
+		startArgv(t, dir, "sh", filepath.Join(dir, "notes", "bd daemon")),
+		startArgv(t, dir, "bd", "daemonize"),
+		startArgv(t, dir, "bdx", "daemon"),
+	}
+
+	ours := append([]int{daemon, pathDaemon}, lookalikes...)
+	found := func(pids []int) []int {
+		var got []int
+		for _, pid := range ours {
+			if slices.Contains(pids, pid) {
+				got = append(got, pid)
+			}
+		}
+		return got
+	}
+	check := func(name string, pids []int, want ...int) {
+		t.Helper()
+		if got := found(pids); !slices.Equal(got, want) {
+			t.Errorf("%s found %v, want %v (of bd daemon %d, /usr/local/bin/bd daemon %d, lookalikes %v)",
+				name, got, want, daemon, pathDaemon, lookalikes)
+		}
+	}
+
+	// The substring match is fooled by the file name and the longer word.
+	check("FindByPattern", FindByPattern("bd daemon"), daemon, pathDaemon, lookalikes[0], lookalikes[1])
+
+	check("FindByExactArgs", FindByExactArgs("bd", "daemon"), daemon)
+	check("FindMatching(basename)", FindMatching(MatchBasename(MatchExactArgs("bd", "daemon"))), daemon, pathDaemon)
+	check("FindByRegexp", FindByRegexp(regexp.MustCompile(`^(\S*/)?bd daemon( |$)`)), daemon, pathDaemon)
+
+	if n := CountByExactArgs("bd", "daemon"); n < 1 {
+		t.Errorf("CountByExactArgs() = %d, want at least our bd daemon", n)
+	}
+	if n := CountByRegexp(regexp.MustCompile(`^bd daemon`)); n < 1 {
+		t.Errorf("CountByRegexp() = %d, want at least our bd daemon", n)
+	}
+}
+
+func TestSnapshotArgvSelf(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("argv can't be listed on this platform")
+	}
+	table, err := Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error: %v", err)
+	}
+	p, ok := table.Get(os.Getpid())
+	if !ok {
+		t.Fatalf("Snapshot() has no pid %d", os.Getpid())
+	}
+	if !slices.Equal(p.Argv, os.Args) {
+		t.Errorf("argv of self = %q, want %q", p.Argv, os.Args)
+	}
+}
@@ -1,59 +1,158 @@
 // Package proc provides native Go process management via /proc filesystem.
 // This eliminates shell spawning overhead for process tree operations.
+// macOS has no /proc: there the process table comes from sysctl and ps.
 package proc
 
 import (
+	"errors"
+	"fmt"
 	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
 	"syscall"
+	"time"
 )
 
-// GetChildren returns direct child PIDs of a process using /proc/<pid>/task/<tid>/children.
-// Returns nil on error or if process has no children.
-// This is O(1) filesystem reads vs O(1) shell spawn - much faster.
-func GetChildren(pid int) []int {
-	// Read from /proc/<pid>/task/<pid>/children (Linux 3.5+)
-	// This file contains space-separated child PIDs
-	path := filepath.Join("/proc", strconv.Itoa(pid), "task", strconv.Itoa(pid), "children")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil
-	}
+// Process cleanup constants
+const (
+	// SIGTERMGracePeriod is the time to wait after SIGTERM before sending SIGKILL.
+	// 500ms gives processes time to handle cleanup gracefully.
+	SIGTERMGracePeriod = 500 * time.Millisecond
 
-	fields := strings.Fields(string(data))
-	if len(fields) == 0 {
-		return nil
-	}
+	// exitPollInterval is how often KillTree checks whether the tree has exited.
+	exitPollInterval = 20 * time.Millisecond
+
+	// DescendantRescanDelay is the delay between descendant discovery passes.
+	// This helps catch processes that fork during the initial scan.
+	DescendantRescanDelay = 50 * time.Millisecond
+
+	// DescendantRescanAttempts is the number of times to rescan for new descendants.
+	// Multiple passes help catch race conditions where processes fork during cleanup.
+	DescendantRescanAttempts = 3
+
+	// procReadRetries and procReadRetryDelay are how often, and after how
+	// long, a /proc read that found nothing is retried.
+	procReadRetries    = 2
+	procReadRetryDelay = 10 * time.Millisecond
+)
 
-	children := make([]int, 0, len(fields))
-	for _, f := range fields {
-		if cpid, err := strconv.Atoi(f); err == nil {
-			children = append(children, cpid)
+// readProcFile reads a file under /proc; replaced in tests.
+var readProcFile = os.ReadFile
+
+// readProcDir lists a directory under /proc; replaced in tests.
+var readProcDir = os.ReadDir
+
+// RescanWithRetry calls fn until it succeeds, at most maxAttempts times.
+// Only "not exist" errors (os.IsNotExist or syscall.ENOENT) are retried:
+// on a busy system a /proc entry can vanish between listing and reading
+// it. It waits delay*attempt between attempts, and returns fn's last
+// result and error.
+func RescanWithRetry(fn func() (interface{}, error), maxAttempts int, delay time.Duration) (interface{}, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := fn()
+		if err == nil || !isNotExist(err) || attempt >= maxAttempts {
+			return result, err
 		}
+		time.Sleep(delay * time.Duration(attempt))
 	}
-	return children
+}
+
+func isNotExist(err error) bool {
+	return os.IsNotExist(err) || errors.Is(err, syscall.ENOENT)
+}
+
+// readProcFileWithRetry reads a file under /proc with RescanWithRetry.
+func readProcFileWithRetry(path string) ([]byte, error) {
+	data, err := RescanWithRetry(func() (interface{}, error) {
+		return readProcFile(path)
+	}, procReadRetries+1, procReadRetryDelay)
+	if err != nil {
+		return nil, err
+	}
+	return data.([]byte), nil
+}
+
+// GetChildren returns direct child PIDs of a process. On Linux they are
+// read from /proc/<pid>/task/<pid>/children; on macOS they come from the
+// ppid column of ps. Returns nil on error or if process has no children.
+func GetChildren(pid int) []int {
+	return getChildren(pid)
 }
 
 // GetAllDescendants returns all descendant PIDs in depth-first order (deepest first).
 // This is the native Go equivalent of recursive pgrep -P calls.
 // Returns PIDs in kill-safe order: children before parents.
 func GetAllDescendants(pid int) []int {
+	return getAllDescendants(pid)
+}
+
+// descendants returns the descendants of pid, deepest first, with
+// childrenOf listing each process's children.
+func descendants(pid int, childrenOf func(int) []int) []int {
 	var result []int
-	children := GetChildren(pid)
-	for _, child := range children {
+	for _, child := range childrenOf(pid) {
 		// Recursively get grandchildren first (deepest-first order)
-		result = append(result, GetAllDescendants(child)...)
+		result = append(result, descendants(child, childrenOf)...)
 		result = append(result, child)
 	}
 	return result
 }
 
-// ProcessInfo contains basic process information read from /proc.
+// GetParentPID returns the parent PID of a process. On Linux it is read
+// from /proc/<pid>/stat; elsewhere it comes from ps.
+func GetParentPID(pid int) (int, error) {
+	return getParentPID(pid)
+}
+
+// GetParentChain returns the ancestors of pid, nearest first:
+// [parent, grandparent, ...]. The walk stops before PID 1 (init itself is
+// not included), after maxDepth ancestors, or if the chain loops. If a
+// lookup fails partway, the ancestors found so far are returned with the
+// error.
+func GetParentChain(pid int, maxDepth int) ([]int, error) {
+	return GetParentChainWith(pid, maxDepth, GetParentPID)
+}
+
+// GetParentChainWith is GetParentChain with a custom parent lookup, for
+// callers that resolve parents another way.
+func GetParentChainWith(pid int, maxDepth int, parentOf func(int) (int, error)) ([]int, error) {
+	var chain []int
+	visited := map[int]bool{pid: true}
+	current := pid
+	for len(chain) < maxDepth {
+		ppid, err := parentOf(current)
+		if err != nil {
+			return chain, fmt.Errorf("getting parent of pid %d: %w", current, err)
+		}
+		if ppid <= 1 || visited[ppid] {
+			break
+		}
+		visited[ppid] = true
+		chain = append(chain, ppid)
+		current = ppid
+	}
+	return chain, nil
+}
+
+// AnyAncestorMatches reports whether predicate holds for any ancestor of
+// pid within maxDepth levels (see GetParentChain). A match found before a
+// failed lookup still counts; otherwise the lookup error is returned.
+func AnyAncestorMatches(pid int, maxDepth int, predicate func(int) bool) (bool, error) {
+	chain, err := GetParentChain(pid, maxDepth)
+	for _, ancestor := range chain {
+		if predicate(ancestor) {
+			return true, nil
+		}
+	}
+	return false, err
+}
+
+// ProcessInfo contains basic process information.
 type ProcessInfo struct {
 	PID  int
-	Comm string // Process command name (from /proc/<pid>/comm)
+	Comm string // Process command name (see GetComm)
+
+	// StartTime is when the process started (see StartTime), to tell it
+	// from a later process given the same PID; 0 if not recorded.
+	StartTime uint64
 }
 
 // GetChildrenWithComm returns direct children with their command names.
@@ -74,15 +173,11 @@ func GetChildrenWithComm(pid int) []ProcessInfo {
 	return result
 }
 
-// GetComm returns the command name for a process from /proc/<pid>/comm.
-// Returns empty string if process doesn't exist or can't be read.
+// GetComm returns the command name for a process: /proc/<pid>/comm on
+// Linux, the ucomm column of ps on macOS. Returns empty string if process
+// doesn't exist or can't be read.
 func GetComm(pid int) string {
-	path := filepath.Join("/proc", strconv.Itoa(pid), "comm")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
-	}
-	return strings.TrimSpace(string(data))
+	return getComm(pid)
 }
 
 // Signal sends a signal to a process using native syscall.
@@ -110,6 +205,239 @@ func Exists(pid int) bool {
 	return syscall.Kill(pid, 0) == nil
 }
 
+// GetAllDescendantsWithRescan finds all descendant PIDs with multiple
+// passes, up to DescendantRescanAttempts, to catch processes that fork
+// during the scan. Returns PIDs in deepest-first order, deduplicated.
+func GetAllDescendantsWithRescan(pid int) []int {
+	seen := make(map[int]bool)
+
+	for attempt := 0; attempt < DescendantRescanAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(DescendantRescanDelay)
+		}
+
+		newFound := false
+		for _, dpid := range GetAllDescendants(pid) {
+			if !seen[dpid] {
+				seen[dpid] = true
+				newFound = true
+			}
+		}
+
+		// If no new processes found, we've likely caught everything
+		if !newFound && attempt > 0 {
+			break
+		}
+	}
+
+	// Build result in deepest-first order by re-running GetAllDescendants.
+	// The accumulated PIDs are in discovery order across passes, not tree
+	// depth order. PIDs that died between passes are simply absent - they
+	// don't need killing.
+	var result []int
+	for _, dpid := range GetAllDescendants(pid) {
+		if seen[dpid] {
+			result = append(result, dpid)
+		}
+	}
+	return result
+}
+
+// KillTree terminates rootPID and all its descendants. It finds the
+// descendants in one Snapshot and sends SIGTERM to them, deepest first so
+// no process is orphaned before it has been signaled, and then to
+// rootPID. It waits up to grace for them all to exit, sending SIGTERM to
+// children forked meanwhile, and then SIGKILLs the rest. With a grace of
+// 0 everything is sent SIGKILL straight away.
+//
+// Each process's start time is recorded when it is found, and a process
+// whose PID has since been reused is skipped with a warning rather than
+// signaled. KillTree records rootPID's start time on entry; to have it
+// checked against an earlier snapshot, use KillTreeVerified.
+//
+// Returns how many processes exited after SIGTERM and how many had to be
+// sent SIGKILL. Processes that exit along the way are not errors; the
+// error is non-nil only if rootPID is init or could not be signaled.
+func KillTree(rootPID int, grace time.Duration) (terminated, killed int, err error) {
+	if rootPID <= 1 {
+		return 0, 0, fmt.Errorf("refusing to kill process tree rooted at pid %d", rootPID)
+	}
+	start, _ := startTimeOf(rootPID)
+	return KillTreeVerified(ProcessInfo{PID: rootPID, StartTime: start}, grace)
+}
+
+// KillTreeVerified is KillTree for a root recorded earlier with its start
+// time (see Table.Lookup). If root.PID has been reused since, nothing is
+// signaled and the error wraps ErrPIDReused.
+func KillTreeVerified(root ProcessInfo, grace time.Duration) (terminated, killed int, err error) {
+	rootPID := root.PID
+	if rootPID <= 1 {
+		return 0, 0, fmt.Errorf("refusing to kill process tree rooted at pid %d", rootPID)
+	}
+	if err := VerifyStartTime(root); err != nil {
+		warnf("not killing process tree of pid %d: %v", rootPID, err)
+		return 0, 0, err
+	}
+	if err := Signal(rootPID, 0); err != nil {
+		return 0, 0, fmt.Errorf("signaling pid %d: %w", rootPID, err)
+	}
+	starts := startTimes{rootPID: root.StartTime}
+
+	if grace <= 0 {
+		sent := make(map[int]bool)
+		killed = signalTree(starts.descendants(rootPID), syscall.SIGKILL, sent, starts)
+		// Children forked before their parent was killed.
+		killed += signalTree(GetAllDescendants(rootPID), syscall.SIGKILL, sent, starts)
+		if err := signalRoot(root, syscall.SIGKILL); err != nil {
+			return 0, killed, err
+		}
+		return 0, killed + 1, nil
+	}
+
+	// tree is every process sent SIGTERM, deepest first with the root last.
+	termed := make(map[int]bool)
+	tree := starts.descendants(rootPID)
+	signalTree(tree, syscall.SIGTERM, termed, starts)
+	if err := signalRoot(root, syscall.SIGTERM); err != nil {
+		return 0, 0, err
+	}
+	termed[rootPID] = true
+	tree = append(tree, rootPID)
+
+	deadline := time.Now().Add(grace)
+	for starts.anyAlive(tree) && time.Now().Before(deadline) {
+		time.Sleep(exitPollInterval)
+		// Children forked while handling SIGTERM get it too. Once the root
+		// has gone, its PID may be another process's, with other children.
+		if !starts.alive(rootPID) {
+			continue
+		}
+		if forked := GetAllDescendants(rootPID); signalTree(forked, syscall.SIGTERM, termed, starts) > 0 {
+			tree = append(forked, tree...)
+		}
+	}
+
+	// SIGKILL survivors, including any children forked since the last scan.
+	// They are all found before any is killed: a parent waiting on its
+	// children exits once they are killed, and it still had to be.
+	kill := tree
+	if starts.alive(rootPID) {
+		kill = append(GetAllDescendantsWithRescan(rootPID), tree...)
+	}
+	sent := make(map[int]bool)
+	var survivors []int
+	for _, pid := range kill {
+		if pid <= 1 || sent[pid] {
+			continue
+		}
+		starts.record(pid)
+		if !starts.alive(pid) {
+			continue
+		}
+		sent[pid] = true
+		survivors = append(survivors, pid)
+	}
+	for _, pid := range survivors {
+		err := SignalVerified(ProcessInfo{PID: pid, StartTime: starts[pid]}, syscall.SIGKILL)
+		if err == nil || errors.Is(err, syscall.ESRCH) {
+			killed++
+		}
+	}
+
+	for pid := range termed {
+		if !sent[pid] {
+			terminated++
+		}
+	}
+	return terminated, killed, nil
+}
+
+// signalRoot sends sig to the root of a tree whose descendants have just
+// been signaled. A root that has exited since, as a shell waiting on its
+// children does when they die, is not an error.
+func signalRoot(root ProcessInfo, sig syscall.Signal) error {
+	if err := SignalVerified(root, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return fmt.Errorf("signaling pid %d: %w", root.PID, err)
+	}
+	return nil
+}
+
+// startTimes holds the start time of each process in a tree as first
+// seen, so that KillTree can tell when a PID has been reused.
+type startTimes map[int]uint64
+
+// descendants returns rootPID's descendants, deepest first, from one
+// snapshot of the process table, recording their start times as it was
+// taken. Where there is no snapshot to take it walks the tree with
+// rescans instead (see GetAllDescendantsWithRescan), and the start times
+// are read as the processes are signaled.
+func (s startTimes) descendants(rootPID int) []int {
+	table, err := takeSnapshot()
+	if err != nil {
+		return GetAllDescendantsWithRescan(rootPID)
+	}
+	tree := table.Descendants(rootPID)
+	for _, pid := range tree {
+		if _, ok := s[pid]; ok {
+			continue
+		}
+		if p, _ := table.Get(pid); p.StartTime != 0 {
+			s[pid] = p.StartTime
+		}
+	}
+	return tree
+}
+
+// record notes pid's start time unless it is already known.
+func (s startTimes) record(pid int) {
+	if _, ok := s[pid]; !ok {
+		s[pid], _ = startTimeOf(pid)
+	}
+}
+
+// alive reports whether pid is running and is still the process recorded.
+func (s startTimes) alive(pid int) bool {
+	return alive(pid) && VerifyStartTime(ProcessInfo{PID: pid, StartTime: s[pid]}) == nil
+}
+
+// anyAlive reports whether any of pids is still running (see alive).
+func (s startTimes) anyAlive(pids []int) bool {
+	for _, pid := range pids {
+		if s.alive(pid) {
+			return true
+		}
+	}
+	return false
+}
+
+// signalTree sends sig to each pid not already in signaled, never init,
+// and records it there. Returns the number signaled; processes that have
+// already exited are skipped, as are PIDs reused since starts recorded
+// them.
+func signalTree(pids []int, sig syscall.Signal, signaled map[int]bool, starts startTimes) int {
+	sent := 0
+	for _, pid := range pids {
+		if pid <= 1 || signaled[pid] {
+			continue
+		}
+		starts.record(pid)
+		if SignalVerified(ProcessInfo{PID: pid, StartTime: starts[pid]}, sig) == nil {
+			sent++
+			if signaled != nil {
+				signaled[pid] = true
+			}
+		}
+	}
+	return sent
+}
+
+// KillTreeGracefully terminates rootPID and all its descendants, sending
+// SIGTERM first and escalating to SIGKILL after SIGTERMGracePeriod.
+func KillTreeGracefully(rootPID int) error {
+	_, _, err := KillTree(rootPID, SIGTERMGracePeriod)
+	return err
+}
+
 // HasDescendantMatching checks if any descendant's comm matches one of the names.
 // Returns true on first match. This replaces recursive pgrep -P -l calls.
 func HasDescendantMatching(pid int, names []string, visited map[int]bool) bool {
@@ -134,68 +462,24 @@ func HasDescendantMatching(pid int, names []string, visited map[int]bool) bool {
 	return false
 }
 
-// CountByPattern counts processes matching a command pattern.
-// Scans /proc for processes whose comm or cmdline contains the pattern.
-// This replaces `pgrep -f pattern | wc -l` shell pipeline.
+// CountByPattern counts processes matching a command pattern, other than
+// zombies. This replaces `pgrep -f pattern | wc -l` shell pipeline.
 func CountByPattern(pattern string) int {
-	entries, err := os.ReadDir("/proc")
-	if err != nil {
-		return 0
-	}
-
-	count := 0
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		pid, err := strconv.Atoi(entry.Name())
-		if err != nil {
-			continue // Not a PID directory
-		}
-
-		// Check cmdline for pattern (more accurate than comm for multi-word patterns)
-		cmdline := getCmdline(pid)
-		if strings.Contains(cmdline, pattern) {
-			count++
-		}
-	}
-	return count
-}
-
-// getCmdline reads /proc/<pid>/cmdline and returns it as a space-joined string.
-func getCmdline(pid int) string {
-	path := filepath.Join("/proc", strconv.Itoa(pid), "cmdline")
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return ""
-	}
-	// cmdline uses null bytes as separators
-	return strings.ReplaceAll(string(data), "\x00", " ")
+	return len(FindByPattern(pattern))
 }
 
-// FindByPattern returns PIDs of processes matching a command pattern.
-// Scans /proc for processes whose cmdline contains the pattern.
-// This replaces `pgrep -f pattern` shell command.
+// FindByPattern returns PIDs of processes whose command line contains the
+// pattern, from a fresh Snapshot. This replaces `pgrep -f pattern` shell
+// command. Zombies are left out, as they have exited. To make several
+// queries, take one Snapshot and query the Table.
+//
+// The pattern can match anywhere, in a file name argument too: to find
+// processes to signal, match their argv with FindByExactArgs or
+// FindMatching instead.
 func FindByPattern(pattern string) []int {
-	entries, err := os.ReadDir("/proc")
+	t, err := Snapshot()
 	if err != nil {
 		return nil
 	}
-
-	var pids []int
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		pid, err := strconv.Atoi(entry.Name())
-		if err != nil {
-			continue // Not a PID directory
-		}
-
-		cmdline := getCmdline(pid)
-		if strings.Contains(cmdline, pattern) {
-			pids = append(pids, pid)
-		}
-	}
-	return pids
+	return t.WithoutZombies().FindByPattern(pattern)
 }
@@ -0,0 +1,237 @@
+package proc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// loadPSTable lists every process with ps; replaced in tests. stat is
+// the state letter and its flags; ucomm is the short accounting name, like Linux's /proc/<pid>/comm, where comm on
+// macOS is the executable's full path; -ww keeps args from being cut at
+// the terminal width.
+var loadPSTable = func() (*psTable, error) {
+	out, err := exec.Command("ps", "-axww", "-o", "pid,ppid,stat,ucomm,args").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parsePSTable(string(out))
+}
+
+func getChildren(pid int) []int {
+	t, err := loadPSTable()
+	if err != nil {
+		return nil
+	}
+	return t.Children(pid)
+}
+
+// getAllDescendants walks a single ps snapshot rather than running ps for
+// each process in the tree.
+func getAllDescendants(pid int) []int {
+	t, err := loadPSTable()
+	if err != nil {
+		return nil
+	}
+	return t.Descendants(pid)
+}
+
+func getComm(pid int) string {
+	t, err := loadPSTable()
+	if err != nil {
+		return ""
+	}
+	return t.Comm(pid)
+}
+
+// sysctl MIB components for reading a process's arguments.
+const (
+	ctlKern       = 1  // CTL_KERN
+	kernProcArgs2 = 49 // KERN_PROCARGS2
+)
+
+// kinfoProc mirrors the 64-bit darwin struct kinfo_proc returned by
+// kern.proc.all. Only kp_proc.p_starttime, p_pid and p_comm are read;
+// the rest of the 648-byte record is padding.
+type kinfoProc struct {
+	StartSec  int64 // p_starttime, a struct timeval
+	StartUsec int32
+	_         [28]byte
+	Pid       int32
+	_         [199]byte
+	Comm      [17]byte // MAXCOMLEN+1, NUL-terminated
+	_         [388]byte
+}
+
+const sizeofKinfoProc = int(unsafe.Sizeof(kinfoProc{}))
+
+// snapshot runs ps once for the table, then reads start times from
+// sysctl kern.proc.all and each process's argv from kern.procargs2. If
+// sysctl can't be read, argv is the args column of ps split at its
+// spaces, which splits arguments that contain spaces too; otherwise a
+// process whose arguments can't be read, as another user's can't but as
+// root, has none.
+func snapshot() (*Table, error) {
+	ps, err := loadPSTable()
+	if err != nil {
+		return nil, fmt.Errorf("running ps: %w", err)
+	}
+	kprocs, sysctlErr := sysctlProcTable()
+	starts := make(map[int]uint64, len(kprocs))
+	for _, p := range kprocs {
+		starts[p.PID] = p.StartTime
+	}
+
+	argBuf := newProcArgsBuf()
+	procs := make([]Process, 0, len(ps.procs))
+	for _, p := range ps.procs {
+		entry := Process{PID: p.PID, PPID: p.PPID, Comm: p.Comm, Cmdline: p.Args, StartTime: starts[p.PID], State: p.State}
+		if sysctlErr != nil {
+			entry.Argv = strings.Fields(p.Args)
+		} else {
+			entry.Argv = procArgv(p.PID, argBuf)
+		}
+		procs = append(procs, entry)
+	}
+	return NewTable(procs), nil
+}
+
+// sysctlProcTable lists the PIDs, command names and start times in the
+// BSD process table, from sysctl kern.proc.all.
+func sysctlProcTable() ([]psProcess, error) {
+	table, err := syscall.Sysctl("kern.proc.all")
+	if err != nil {
+		return nil, err
+	}
+	// syscall.Sysctl drops a trailing NUL byte, which can clip the last record.
+	data := []byte(table)
+	if r := len(data) % sizeofKinfoProc; r != 0 {
+		data = append(data, make([]byte, sizeofKinfoProc-r)...)
+	}
+
+	var procs []psProcess
+	for off := 0; off+sizeofKinfoProc <= len(data); off += sizeofKinfoProc {
+		kp := (*kinfoProc)(unsafe.Pointer(&data[off]))
+		pid := int(kp.Pid)
+		if pid <= 0 {
+			continue // kernel_task
+		}
+
+		comm := kp.Comm[:]
+		if i := bytes.IndexByte(comm, 0); i >= 0 {
+			comm = comm[:i]
+		}
+		start := uint64(kp.StartSec)*1e6 + uint64(kp.StartUsec)
+		procs = append(procs, psProcess{PID: pid, Comm: string(comm), StartTime: start})
+	}
+	return procs, nil
+}
+
+// startTime finds pid in the process table from sysctl.
+func startTime(pid int) (uint64, error) {
+	procs, err := sysctlProcTable()
+	if err != nil {
+		return 0, fmt.Errorf("start time of pid %d: %w", pid, err)
+	}
+	for _, p := range procs {
+		if p.PID == pid {
+			return p.StartTime, nil
+		}
+	}
+	return 0, fmt.Errorf("start time of pid %d: %w", pid, syscall.ESRCH)
+}
+
+// newProcArgsBuf returns a buffer for procArgv, sized kern.argmax, or nil
+// if that can't be read.
+func newProcArgsBuf() []byte {
+	if argMax, err := syscall.SysctlUint32("kern.argmax"); err == nil {
+		return make([]byte, argMax)
+	}
+	return nil
+}
+
+// procArgv returns a process's argv, read via sysctl kern.procargs2.<pid>
+// into buf. Returns nil if unreadable.
+func procArgv(pid int, buf []byte) []string {
+	data, err := readProcArgs2(pid, buf)
+	if err != nil {
+		return nil
+	}
+	argv, _ := parseProcArgs2(data)
+	return argv
+}
+
+// environ reads the environment from sysctl kern.procargs2.<pid>, which
+// holds it after the arguments. Other users' processes can only be read
+// as root.
+func environ(pid int) (map[string]string, error) {
+	data, err := readProcArgs2(pid, newProcArgsBuf())
+	if err != nil {
+		return nil, fmt.Errorf("reading environment of pid %d: %w", pid, err)
+	}
+	_, env := parseProcArgs2(data)
+	return parseEnviron(env), nil
+}
+
+// readProcArgs2 reads sysctl kern.procargs2.<pid> into buf and returns the
+// part filled.
+func readProcArgs2(pid int, buf []byte) ([]byte, error) {
+	if len(buf) == 0 {
+		return nil, fmt.Errorf("kern.argmax unreadable")
+	}
+	mib := [3]int32{ctlKern, kernProcArgs2, int32(pid)}
+	size := uintptr(len(buf))
+	_, _, errno := syscall.Syscall6(syscall.SYS___SYSCTL,
+		uintptr(unsafe.Pointer(&mib[0])), uintptr(len(mib)),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)),
+		0, 0)
+	if errno != 0 {
+		return nil, errno
+	}
+	if size < 4 {
+		return nil, fmt.Errorf("kern.procargs2 of pid %d: short read", pid)
+	}
+	return buf[:size], nil
+}
+
+// parseProcArgs2 decodes a KERN_PROCARGS2 buffer: a native-endian int32 argc,
+// the NUL-terminated exec path and its NUL padding, then argc NUL-terminated
+// arguments, followed by the NUL-terminated KEY=VALUE environment, which
+// ends at an empty string.
+func parseProcArgs2(data []byte) (argv, env []string) {
+	argc := int(binary.LittleEndian.Uint32(data)) // darwin is little-endian on amd64 and arm64
+	rest := data[4:]
+
+	// Skip the exec path and the padding after it.
+	i := bytes.IndexByte(rest, 0)
+	if i < 0 {
+		return nil, nil
+	}
+	rest = rest[i:]
+	for len(rest) > 0 && rest[0] == 0 {
+		rest = rest[1:]
+	}
+
+	argv = make([]string, 0, argc)
+	for len(rest) > 0 {
+		i := bytes.IndexByte(rest, 0)
+		if i < 0 {
+			i = len(rest)
+		}
+		s := string(rest[:i])
+		rest = rest[min(i+1, len(rest)):]
+		if len(argv) < argc {
+			argv = append(argv, s)
+			continue
+		}
+		if s == "" {
+			break
+		}
+		env = append(env, s)
+	}
+	return argv, env
+}
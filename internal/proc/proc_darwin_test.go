@@ -0,0 +1,79 @@
+package proc
+
+import (
+	"encoding/binary"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestProcessTreeFromPS(t *testing.T) {
+	table, err := parsePSTable(fakePSOutput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := loadPSTable
+	loadPSTable = func() (*psTable, error) { return table, nil }
+	t.Cleanup(func() { loadPSTable = orig })
+
+	if got := strings.Join(pidStrings(GetChildren(413)), " "); got != "420 422" {
+		t.Errorf("GetChildren(413) = %s, want 420 422", got)
+	}
+	if got := strings.Join(pidStrings(GetAllDescendants(412)), " "); got != "421 420 422 413" {
+		t.Errorf("GetAllDescendants(412) = %s, want 421 420 422 413", got)
+	}
+	if got := GetComm(420); got != "node" {
+		t.Errorf("GetComm(420) = %q, want node", got)
+	}
+	if !HasDescendantMatching(412, []string{"bd"}, make(map[int]bool)) {
+		t.Error("HasDescendantMatching(412, bd) = false, want the bd under node found")
+	}
+}
+
+// TestProcessTreeLive checks the live ps output parses: the test binary
+// is a child of its parent, with a command name.
+func TestProcessTreeLive(t *testing.T) {
+	self, parent := os.Getpid(), os.Getppid()
+	found := false
+	for _, pid := range GetChildren(parent) {
+		found = found || pid == self
+	}
+	if !found {
+		t.Errorf("GetChildren(%d) = %v, want it to include pid %d", parent, GetChildren(parent), self)
+	}
+	if comm := GetComm(self); comm == "" {
+		t.Errorf("GetComm(%d) is empty", self)
+	}
+}
+
+func TestFindByPatternLaunchd(t *testing.T) {
+	if pids := FindByPattern("launchd"); len(pids) == 0 {
+		t.Error("FindByPattern(\"launchd\") found nothing")
+	}
+}
+
+func TestFindByPatternSelf(t *testing.T) {
+	// Our own arguments are always readable, so multi-word patterns work.
+	if len(os.Args) < 2 {
+		t.Skip("test binary has no arguments")
+	}
+	self := os.Getpid()
+	for _, pid := range FindByPattern(os.Args[1]) {
+		if pid == self {
+			return
+		}
+	}
+	t.Errorf("FindByPattern(%q) did not find pid %d", os.Args[1], self)
+}
+
+func TestParseProcArgs2(t *testing.T) {
+	data := binary.LittleEndian.AppendUint32(nil, 2)
+	data = append(data, "/usr/local/bin/bd\x00\x00\x00\x00bd\x00daemon\x00HOME=/Users/x\x00GT_ROLE=witness\x00\x00ptr_munge=\x00"...)
+	argv, env := parseProcArgs2(data)
+	if len(argv) != 2 || argv[0] != "bd" || argv[1] != "daemon" {
+		t.Errorf("parseProcArgs2 argv = %q, want [bd daemon]", argv)
+	}
+	if len(env) != 2 || env[0] != "HOME=/Users/x" || env[1] != "GT_ROLE=witness" {
+		t.Errorf("parseProcArgs2 env = %q, want HOME and GT_ROLE", env)
+	}
+}
@@ -0,0 +1,105 @@
+package proc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// snapshot reads the stat and cmdline of every process in /proc. A
+// process that exits between the listing and the reads is left out rather
+// than retried.
+func snapshot() (*Table, error) {
+	entries, err := readProcDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("reading /proc: %w", err)
+	}
+
+	procs := make([]Process, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue // Not a PID directory
+		}
+
+		dir := filepath.Join("/proc", entry.Name())
+		stat, err := readProcFile(filepath.Join(dir, "stat"))
+		if err != nil {
+			continue
+		}
+		p, err := parseStat(pid, string(stat))
+		if err != nil {
+			continue
+		}
+		if cmdline, err := readProcFile(filepath.Join(dir, "cmdline")); err == nil {
+			p.Argv, p.Cmdline = parseCmdline(cmdline)
+		}
+		procs = append(procs, p)
+	}
+	return NewTable(procs), nil
+}
+
+// getParentPID reads the parent PID from /proc/<pid>/stat. The command name
+// there is parenthesised and may itself contain spaces or parentheses, so
+// the fields are taken from after the last ')'.
+func getParentPID(pid int) (int, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, err
+	}
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed /proc/%d/stat", pid)
+	}
+	return strconv.Atoi(fields[1]) // state, then ppid
+}
+
+// alive reports whether pid is running: it exists and, per
+// /proc/<pid>/stat, isn't a zombie waiting to be reaped.
+func alive(pid int) bool {
+	s, err := state(pid)
+	return err == nil && s != StateZombie
+}
+
+// state reads the state from /proc/<pid>/stat. A missing process is
+// the usual answer for one that has exited, so the read isn't retried.
+func state(pid int) (rune, error) {
+	data, err := readProcFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, err
+	}
+	s, err := parseState(string(data))
+	if err != nil {
+		return 0, fmt.Errorf("pid %d: %w", pid, err)
+	}
+	return s, nil
+}
+
+// startTime reads the start time from /proc/<pid>/stat.
+func startTime(pid int) (uint64, error) {
+	data, err := readProcFileWithRetry(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, err
+	}
+	start, err := parseStartTime(string(data))
+	if err != nil {
+		return 0, fmt.Errorf("pid %d: %w", pid, err)
+	}
+	return start, nil
+}
+
+// environ reads /proc/<pid>/environ, which only the process's owner (or
+// root) may read.
+func environ(pid int) (map[string]string, error) {
+	data, err := readProcFileWithRetry(filepath.Join("/proc", strconv.Itoa(pid), "environ"))
+	if err != nil {
+		return nil, fmt.Errorf("reading environment of pid %d: %w", pid, err)
+	}
+	return parseEnviron(strings.Split(string(data), "\x00")), nil
+}
@@ -0,0 +1,20 @@
+//go:build !linux && !darwin
+
+package proc
+
+import "fmt"
+
+// snapshot is not supported on this platform.
+func snapshot() (*Table, error) {
+	return nil, fmt.Errorf("process table: not supported on this platform")
+}
+
+// startTime is not supported on this platform.
+func startTime(pid int) (uint64, error) {
+	return 0, fmt.Errorf("start time of pid %d: not supported on this platform", pid)
+}
+
+// environ is not supported on this platform.
+func environ(pid int) (map[string]string, error) {
+	return nil, fmt.Errorf("environment of pid %d: not supported on this platform", pid)
+}
@@ -0,0 +1,47 @@
+//go:build !darwin
+
+package proc
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// getChildren reads /proc/<pid>/task/<pid>/children (Linux 3.5+).
+// This is O(1) filesystem reads vs O(1) shell spawn - much faster.
+func getChildren(pid int) []int {
+	// This file contains space-separated child PIDs
+	path := filepath.Join("/proc", strconv.Itoa(pid), "task", strconv.Itoa(pid), "children")
+	data, err := readProcFileWithRetry(path)
+	if err != nil {
+		return nil
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return nil
+	}
+
+	children := make([]int, 0, len(fields))
+	for _, f := range fields {
+		if cpid, err := strconv.Atoi(f); err == nil {
+			children = append(children, cpid)
+		}
+	}
+	return children
+}
+
+func getAllDescendants(pid int) []int {
+	return descendants(pid, getChildren)
+}
+
+// getComm reads /proc/<pid>/comm.
+func getComm(pid int) string {
+	path := filepath.Join("/proc", strconv.Itoa(pid), "comm")
+	data, err := readProcFileWithRetry(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
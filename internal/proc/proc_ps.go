@@ -0,0 +1,38 @@
+//go:build !linux
+
+package proc
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// getParentPID asks ps for the parent PID.
+func getParentPID(pid int) (int, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "ppid=").Output() //nolint:gosec // G204: PID is numeric
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+// alive reports whether pid exists. Zombies count as alive here.
+func alive(pid int) bool {
+	return Exists(pid)
+}
+
+// state asks ps for the state of pid, the first letter of its state
+// column; the rest are flags, like '+' for the foreground process group.
+func state(pid int) (rune, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "state=").Output() //nolint:gosec // G204: PID is numeric
+	if err != nil {
+		return 0, fmt.Errorf("state of pid %d: %w", pid, err)
+	}
+	s := strings.TrimSpace(string(out))
+	if s == "" {
+		return 0, fmt.Errorf("state of pid %d: no state from ps", pid)
+	}
+	return rune(s[0]), nil
+}
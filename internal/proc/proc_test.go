@@ -0,0 +1,330 @@
+package proc
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startTree starts a shell with two sleeping children and waits until both
+// children are visible in /proc. The shell is reaped in the background so it
+// does not linger as a zombie after being killed.
+func startTree(t *testing.T, script string) (int, []int) {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("requires /proc")
+	}
+
+	cmd := exec.Command("sh", "-c", script)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting process tree: %v", err)
+	}
+	go func() { _ = cmd.Wait() }()
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+
+	root := cmd.Process.Pid
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if children := GetChildren(root); len(children) == 2 {
+			return root, children
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("children of pid %d never appeared", root)
+	return 0, nil
+}
+
+// running reports whether pid exists and is not a zombie.
+func running(pid int) bool {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return false
+	}
+	// State is the first field after the parenthesised comm.
+	stat := string(data)
+	idx := strings.LastIndex(stat, ")")
+	if idx < 0 || idx+2 >= len(stat) {
+		return false
+	}
+	return stat[idx+2] != 'Z'
+}
+
+func waitGone(t *testing.T, pids ...int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for _, pid := range pids {
+		for running(pid) {
+			if time.Now().After(deadline) {
+				t.Fatalf("pid %d still running", pid)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+func TestKillTree(t *testing.T) {
+	root, children := startTree(t, "sleep 30 & sleep 30 & wait")
+
+	start := time.Now()
+	terminated, killed, err := KillTree(root, 5*time.Second)
+	if err != nil {
+		t.Fatalf("KillTree: %v", err)
+	}
+	if terminated != 3 || killed != 0 {
+		t.Errorf("terminated, killed = %d, %d; want 3, 0", terminated, killed)
+	}
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Errorf("took %v, want the tree gone well within the grace period", elapsed)
+	}
+	waitGone(t, append(children, root)...)
+}
+
+func TestKillTree_EscalatesToSIGKILL(t *testing.T) {
+	// Ignored signals are inherited across exec, so the sleeps ignore SIGTERM too.
+	root, children := startTree(t, `trap "" TERM; sleep 30 & sleep 30 & wait`)
+
+	start := time.Now()
+	terminated, killed, err := KillTree(root, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("KillTree: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("returned after %v, expected to wait out the grace period", elapsed)
+	}
+	if terminated != 0 || killed != 3 {
+		t.Errorf("terminated, killed = %d, %d; want 0, 3", terminated, killed)
+	}
+	waitGone(t, append(children, root)...)
+}
+
+func TestKillTree_SignalsChildrenForkedDuringGrace(t *testing.T) {
+	// On SIGTERM the shell starts another sleep and waits for it.
+	root, children := startTree(t, `trap "sleep 30 & wait" TERM; sleep 30 & sleep 30 & wait`)
+
+	terminated, killed, err := KillTree(root, 5*time.Second)
+	if err != nil {
+		t.Fatalf("KillTree: %v", err)
+	}
+	if terminated != 4 || killed != 0 {
+		t.Errorf("terminated, killed = %d, %d; want the forked sleep terminated too", terminated, killed)
+	}
+	waitGone(t, append(children, root)...)
+}
+
+func TestKillTree_NoGrace(t *testing.T) {
+	root, children := startTree(t, `trap "" TERM; sleep 30 & sleep 30 & wait`)
+
+	terminated, killed, err := KillTree(root, 0)
+	if err != nil {
+		t.Fatalf("KillTree: %v", err)
+	}
+	if terminated != 0 || killed != 3 {
+		t.Errorf("terminated, killed = %d, %d; want 0, 3", terminated, killed)
+	}
+	waitGone(t, append(children, root)...)
+}
+
+func TestKillTreeGracefully(t *testing.T) {
+	root, children := startTree(t, "sleep 30 & sleep 30 & wait")
+	if err := KillTreeGracefully(root); err != nil {
+		t.Fatalf("KillTreeGracefully: %v", err)
+	}
+	waitGone(t, append(children, root)...)
+}
+
+func TestKillTree_MissingRoot(t *testing.T) {
+	if _, _, err := KillTree(1<<22+1, time.Second); err == nil {
+		t.Error("expected error for nonexistent pid")
+	}
+}
+
+func TestKillTree_RefusesInit(t *testing.T) {
+	for _, pid := range []int{-1, 0, 1} {
+		if _, _, err := KillTree(pid, 0); err == nil {
+			t.Errorf("KillTree(%d) should refuse", pid)
+		}
+	}
+}
+
+func TestGetAllDescendantsWithRescan(t *testing.T) {
+	root, children := startTree(t, "sleep 30 & sleep 30 & wait")
+
+	got := GetAllDescendantsWithRescan(root)
+	if strings.Join(pidStrings(got), " ") != strings.Join(pidStrings(children), " ") {
+		t.Errorf("GetAllDescendantsWithRescan() = %v, want %v", got, children)
+	}
+	if got := GetAllDescendantsWithRescan(1<<22 + 1); len(got) != 0 {
+		t.Errorf("GetAllDescendantsWithRescan(nonexistent) = %v, want none", got)
+	}
+}
+
+func TestGetParentPID(t *testing.T) {
+	ppid, err := GetParentPID(os.Getpid())
+	if err != nil {
+		t.Fatalf("GetParentPID: %v", err)
+	}
+	if ppid != os.Getppid() {
+		t.Errorf("GetParentPID(self) = %d, want %d", ppid, os.Getppid())
+	}
+}
+
+func TestGetParentChain(t *testing.T) {
+	chain, err := GetParentChain(os.Getpid(), 64)
+	if err != nil {
+		t.Fatalf("GetParentChain: %v", err)
+	}
+	if os.Getppid() > 1 && (len(chain) == 0 || chain[0] != os.Getppid()) {
+		t.Fatalf("chain = %v, want it to start with our parent %d", chain, os.Getppid())
+	}
+	for _, pid := range chain {
+		if pid <= 1 {
+			t.Errorf("chain %v includes pid %d", chain, pid)
+		}
+	}
+
+	if short, _ := GetParentChain(os.Getpid(), 1); len(short) > 1 {
+		t.Errorf("maxDepth 1 gave %v", short)
+	}
+	if _, err := GetParentChain(1<<22+1, 8); err == nil {
+		t.Error("expected error for nonexistent pid")
+	}
+}
+
+func TestGetParentChainWith(t *testing.T) {
+	parents := map[int]int{10: 9, 9: 8, 8: 1}
+	lookup := func(pid int) (int, error) {
+		if ppid, ok := parents[pid]; ok {
+			return ppid, nil
+		}
+		return 0, os.ErrNotExist
+	}
+
+	if chain, err := GetParentChainWith(10, 8, lookup); err != nil || strings.Join(pidStrings(chain), ",") != "9,8" {
+		t.Errorf("chain = %v, %v; want [9 8] stopping before init", chain, err)
+	}
+	if chain, _ := GetParentChainWith(10, 1, lookup); len(chain) != 1 || chain[0] != 9 {
+		t.Errorf("maxDepth 1 chain = %v, want [9]", chain)
+	}
+
+	// A failed lookup partway keeps what was found.
+	delete(parents, 8)
+	if chain, err := GetParentChainWith(10, 8, lookup); err == nil || len(chain) != 2 {
+		t.Errorf("broken chain = %v, %v; want [9 8] and an error", chain, err)
+	}
+
+	// A cycle ends the walk.
+	parents = map[int]int{10: 9, 9: 10}
+	if chain, err := GetParentChainWith(10, 8, lookup); err != nil || len(chain) != 1 {
+		t.Errorf("cyclic chain = %v, %v; want [9]", chain, err)
+	}
+}
+
+func TestAnyAncestorMatches(t *testing.T) {
+	if os.Getppid() <= 1 {
+		t.Skip("test process was started by init")
+	}
+	parent := os.Getppid()
+	found, err := AnyAncestorMatches(os.Getpid(), 8, func(pid int) bool { return pid == parent })
+	if err != nil || !found {
+		t.Errorf("AnyAncestorMatches(parent) = %v, %v; want true", found, err)
+	}
+	found, _ = AnyAncestorMatches(os.Getpid(), 8, func(pid int) bool { return pid == os.Getpid() })
+	if found {
+		t.Error("a process is not its own ancestor")
+	}
+}
+
+func TestRescanWithRetry(t *testing.T) {
+	permission := &os.PathError{Op: "open", Path: "/proc/1/comm", Err: os.ErrPermission}
+	tests := []struct {
+		name         string
+		errs         []error // Returned by successive calls; then success
+		wantCalls    int
+		wantErr      bool
+		wantMinSleep time.Duration
+	}{
+		{"succeeds first time", nil, 1, false, 0},
+		{"transient not exist", []error{os.ErrNotExist, os.ErrNotExist}, 3, false, 3 * time.Millisecond},
+		{"wrapped ENOENT", []error{fmt.Errorf("reading: %w", syscall.ENOENT)}, 2, false, time.Millisecond},
+		{"gives up", []error{os.ErrNotExist, os.ErrNotExist, os.ErrNotExist, os.ErrNotExist}, 3, true, 3 * time.Millisecond},
+		{"other errors aren't retried", []error{permission}, 1, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			start := time.Now()
+			result, err := RescanWithRetry(func() (interface{}, error) {
+				calls++
+				if calls <= len(tt.errs) {
+					return nil, tt.errs[calls-1]
+				}
+				return "ok", nil
+			}, 3, time.Millisecond)
+
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result != "ok" {
+				t.Errorf("result = %v, want ok", result)
+			}
+			// Backoff is delay*attempt: 1ms, then 2ms.
+			if elapsed := time.Since(start); elapsed < tt.wantMinSleep {
+				t.Errorf("took %s, want at least %s of backoff", elapsed, tt.wantMinSleep)
+			}
+		})
+	}
+}
+
+func TestProcReadsRetryTransientErrors(t *testing.T) {
+	if runtime.GOOS == "darwin" {
+		t.Skip("macOS reads ps, not /proc")
+	}
+	// Each file read fails once as if the entry had vanished, then reads a
+	// fake /proc file.
+	failed := map[string]bool{}
+	readProcFile = func(path string) ([]byte, error) {
+		if !failed[path] {
+			failed[path] = true
+			return nil, &os.PathError{Op: "open", Path: path, Err: syscall.ENOENT}
+		}
+		switch filepath.Base(path) {
+		case "comm":
+			return []byte("claude\n"), nil
+		case "children":
+			return []byte("101 102 "), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	t.Cleanup(func() { readProcFile = os.ReadFile })
+
+	if comm := GetComm(100); comm != "claude" {
+		t.Errorf("GetComm() = %q, want claude", comm)
+	}
+	if children := GetChildren(100); strings.Join(pidStrings(children), " ") != "101 102" {
+		t.Errorf("GetChildren() = %v, want [101 102]", children)
+	}
+
+	// An entry that stays gone is given up on.
+	readProcFile = func(string) ([]byte, error) { return nil, os.ErrNotExist }
+	if comm := GetComm(100); comm != "" {
+		t.Errorf("GetComm() of a vanished process = %q, want empty", comm)
+	}
+}
+
+func pidStrings(pids []int) []string {
+	s := make([]string, len(pids))
+	for i, pid := range pids {
+		s[i] = strconv.Itoa(pid)
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+package proc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// psProcess is one process in a psTable.
+type psProcess struct {
+	PID   int
+	PPID  int
+	Comm  string
+	Args  string
+	State rune // First letter of the STAT column
+
+	// StartTime, in microseconds since the epoch, is only read from
+	// sysctl; ps leaves it 0.
+	StartTime uint64
+}
+
+// psTable is a snapshot of the process table, from ps where there is no
+// /proc to read.
+type psTable struct {
+	procs    []psProcess
+	byPID    map[int]int   // Index into procs
+	children map[int][]int // Child PIDs by parent, in ps order
+}
+
+// parsePSTable parses the output of ps -axww -o pid,ppid,stat,ucomm,args.
+// ucomm may contain spaces as well as args, so the columns are cut where
+// the header puts them.
+func parsePSTable(out string) (*psTable, error) {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	header := lines[0]
+	commAt, argsAt := strings.Index(header, "UCOMM"), strings.Index(header, "ARGS")
+	if commAt < 0 || argsAt < commAt {
+		return nil, fmt.Errorf("unexpected ps header %q", header)
+	}
+
+	t := &psTable{byPID: make(map[int]int), children: make(map[int][]int)}
+	for _, line := range lines[1:] {
+		if len(line) < commAt {
+			continue
+		}
+		ids := strings.Fields(line[:commAt]) // pid, ppid, stat
+		if len(ids) != 3 {
+			continue
+		}
+		pid, err := strconv.Atoi(ids[0])
+		if err != nil {
+			continue
+		}
+		ppid, err := strconv.Atoi(ids[1])
+		if err != nil {
+			continue
+		}
+
+		p := psProcess{PID: pid, PPID: ppid, State: rune(ids[2][0])}
+		if len(line) > argsAt {
+			p.Comm = strings.TrimSpace(line[commAt:argsAt])
+			p.Args = strings.TrimSpace(line[argsAt:])
+		} else {
+			p.Comm = strings.TrimSpace(line[commAt:])
+		}
+		t.byPID[pid] = len(t.procs)
+		t.procs = append(t.procs, p)
+		if pid != ppid {
+			t.children[ppid] = append(t.children[ppid], pid)
+		}
+	}
+	return t, nil
+}
+
+// Children returns the direct children of pid.
+func (t *psTable) Children(pid int) []int {
+	return t.children[pid]
+}
+
+// Descendants returns the descendants of pid, deepest first.
+func (t *psTable) Descendants(pid int) []int {
+	return descendants(pid, t.Children)
+}
+
+// Comm returns the command name of pid, or "" if it isn't in the table.
+func (t *psTable) Comm(pid int) string {
+	if i, ok := t.byPID[pid]; ok {
+		return t.procs[i].Comm
+	}
+	return ""
+}
+
+// FindByPattern returns the PIDs whose arguments contain the pattern.
+func (t *psTable) FindByPattern(pattern string) []int {
+	var pids []int
+	for _, p := range t.procs {
+		if strings.Contains(p.Args, pattern) {
+			pids = append(pids, p.PID)
+		}
+	}
+	return pids
+}
@@ -0,0 +1,109 @@
+package proc
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fakePSOutput is what ps -axww -o pid,ppid,stat,ucomm,args prints on
+// macOS for a tmux session running an agent, laid out the same way. The
+// daemon's git has exited and not been reaped.
+func fakePSOutput() string {
+	var b strings.Builder
+	line := func(pid, ppid, stat, comm, args string) {
+		fmt.Fprintf(&b, "%5s %5s %-4s %-16s %s\n", pid, ppid, stat, comm, args)
+	}
+	line("PID", "PPID", "STAT", "UCOMM", "ARGS")
+	line("1", "0", "Ss", "launchd", "/sbin/launchd")
+	line("412", "1", "Ss", "tmux", "tmux new-session -d -s gt-gongshow-witness")
+	line("413", "412", "Ss", "zsh", "-zsh")
+	line("420", "413", "S+", "node", "node /usr/local/bin/claude --dangerously-skip-permissions")
+	line("421", "420", "S", "bd", "bd daemon --start")
+	line("422", "413", "R", "Google Chrome He", "/Applications/Google Chrome.app/Contents/MacOS/Google Chrome Helper --type=renderer")
+	line("500", "1", "S", "bd", "bd daemon --start")
+	line("501", "500", "Z", "git", "")
+	b.WriteString("  garbage line\n")
+	return b.String()
+}
+
+func TestParsePSTable(t *testing.T) {
+	table, err := parsePSTable(fakePSOutput())
+	if err != nil {
+		t.Fatalf("parsePSTable() error = %v", err)
+	}
+	if len(table.procs) != 8 {
+		t.Fatalf("parsed %d processes, want 8 with the garbage line skipped: %+v", len(table.procs), table.procs)
+	}
+	want := psProcess{PID: 422, PPID: 413, Comm: "Google Chrome He", State: 'R',
+		Args: "/Applications/Google Chrome.app/Contents/MacOS/Google Chrome Helper --type=renderer"}
+	if got := table.procs[table.byPID[422]]; got != want {
+		t.Errorf("process 422 = %+v, want %+v", got, want)
+	}
+	if got := table.procs[table.byPID[501]]; got.Comm != "git" || got.Args != "" || got.State != 'Z' {
+		t.Errorf("process 501 = %+v, want a zombie git with no args", got)
+	}
+	// The state's flags, like + for the foreground, are dropped.
+	if got := table.procs[table.byPID[420]].State; got != 'S' {
+		t.Errorf("process 420 state = %q, want 'S'", got)
+	}
+
+	if _, err := parsePSTable("  PID  PPID STAT COMMAND\n    1     0 Ss   launchd\n"); err == nil {
+		t.Error("parsePSTable() accepted output without the UCOMM and ARGS columns")
+	}
+}
+
+func TestPSTableTree(t *testing.T) {
+	table, err := parsePSTable(fakePSOutput())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(pidStrings(table.Children(413)), " "); got != "420 422" {
+		t.Errorf("Children(413) = %s, want 420 422", got)
+	}
+	if got := table.Children(421); got != nil {
+		t.Errorf("Children(421) = %v, want none", got)
+	}
+	// Deepest first, so each process is listed before its parent.
+	if got := strings.Join(pidStrings(table.Descendants(412)), " "); got != "421 420 422 413" {
+		t.Errorf("Descendants(412) = %s, want 421 420 422 413", got)
+	}
+	if got := len(table.Descendants(1)); got != 7 {
+		t.Errorf("Descendants(1) has %d processes, want 7", got)
+	}
+}
+
+func TestPSTableComm(t *testing.T) {
+	table, err := parsePSTable(fakePSOutput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for pid, want := range map[int]string{420: "node", 422: "Google Chrome He", 999: ""} {
+		if got := table.Comm(pid); got != want {
+			t.Errorf("Comm(%d) = %q, want %q", pid, got, want)
+		}
+	}
+}
+
+func TestPSTableFindByPattern(t *testing.T) {
+	table, err := parsePSTable(fakePSOutput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{"bd daemon", "421 500"},
+		{"claude --dangerously", "420"}, // Matches the arguments, not the command name
+		{"gt-gongshow-witness", "412"},
+		{"git", ""}, // No arguments to match
+		{"nothing like this", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(pidStrings(table.FindByPattern(tt.pattern)), " "); got != tt.want {
+			t.Errorf("FindByPattern(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
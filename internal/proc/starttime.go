@@ -0,0 +1,107 @@
+package proc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// ErrPIDReused is returned, wrapped, when a PID recorded earlier now
+// belongs to a process that started since: the one recorded has exited
+// and its PID has been handed to another.
+var ErrPIDReused = errors.New("pid reused by another process")
+
+// StartTime returns when pid started. On Linux this is field 22 of
+// /proc/<pid>/stat, in clock ticks since boot; on macOS it is
+// p_starttime from sysctl, in microseconds since the epoch. Elsewhere it
+// is an error. The values are only for comparing with one another: two
+// processes with the same PID and start time are the same process.
+func StartTime(pid int) (uint64, error) {
+	return startTime(pid)
+}
+
+// startTimeOf looks up start times for the kill paths; replaced in tests
+// to simulate a PID being reused.
+var startTimeOf = StartTime
+
+// warnf reports a process left alone because its PID was reused;
+// replaced in tests.
+var warnf = func(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "Warning: "+format+"\n", args...)
+}
+
+// parseStartTime returns the start time, field 22, from the contents of
+// /proc/<pid>/stat. As in parseProcStat, the fields are counted from after
+// the last ')'.
+func parseStartTime(stat string) (uint64, error) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed stat: no command name")
+	}
+	// After the name: state (field 3) ... starttime (22).
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("malformed stat: %d fields after the command name", len(fields))
+	}
+	start, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("malformed stat starttime: %w", err)
+	}
+	return start, nil
+}
+
+// RecordStartTimes returns pids with their start times, read one by one,
+// to signal later with SignalVerified or SignalAllVerified. A process
+// whose start time can't be read gets 0, which is never verified. For
+// PIDs found in a Table, its Lookup has the start times already.
+func RecordStartTimes(pids []int) []ProcessInfo {
+	procs := make([]ProcessInfo, 0, len(pids))
+	for _, pid := range pids {
+		start, _ := startTimeOf(pid)
+		procs = append(procs, ProcessInfo{PID: pid, StartTime: start})
+	}
+	return procs
+}
+
+// VerifyStartTime checks that p.PID still belongs to the process p
+// recorded, returning an ErrPIDReused error if it started at another
+// time. It passes if p has no start time or the current one can't be
+// read: the process has exited, which signaling it reports, or start
+// times aren't available here.
+func VerifyStartTime(p ProcessInfo) error {
+	if p.StartTime == 0 {
+		return nil
+	}
+	start, err := startTimeOf(p.PID)
+	if err != nil || start == p.StartTime {
+		return nil
+	}
+	return fmt.Errorf("pid %d started at %d, not %d: %w", p.PID, start, p.StartTime, ErrPIDReused)
+}
+
+// SignalVerified sends sig to p.PID if it is still the process p
+// recorded (see VerifyStartTime). If the PID has been reused it logs a
+// warning and returns the ErrPIDReused error without signaling.
+func SignalVerified(p ProcessInfo, sig syscall.Signal) error {
+	if err := VerifyStartTime(p); err != nil {
+		warnf("not sending %v to pid %d: %v", sig, p.PID, err)
+		return err
+	}
+	return Signal(p.PID, sig)
+}
+
+// SignalAllVerified is SignalAll for processes recorded with their start
+// times (see RecordStartTimes and Table.Lookup): a PID that has been reused since is skipped with
+// a warning. Returns count of successful signals.
+func SignalAllVerified(procs []ProcessInfo, sig syscall.Signal) int {
+	sent := 0
+	for _, p := range procs {
+		if SignalVerified(p, sig) == nil {
+			sent++
+		}
+	}
+	return sent
+}
@@ -0,0 +1,183 @@
+package proc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseStartTime(t *testing.T) {
+	start, err := parseStartTime(fixtureStat)
+	if err != nil || start != 8812345 {
+		t.Errorf("parseStartTime() = %d, %v; want 8812345", start, err)
+	}
+	for _, bad := range []string{"", "4242 claude S 1", "4242 (claude) S 1 2 3", strings.Replace(fixtureStat, "8812345", "x", 1)} {
+		if _, err := parseStartTime(bad); err == nil {
+			t.Errorf("parseStartTime(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestStartTime(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("start times can't be read on this platform")
+	}
+	self, err := StartTime(os.Getpid())
+	if err != nil || self == 0 {
+		t.Fatalf("StartTime(self) = %d, %v", self, err)
+	}
+	again, _ := StartTime(os.Getpid())
+	if again != self {
+		t.Errorf("StartTime(self) changed from %d to %d", self, again)
+	}
+
+	child := startSleep(t)
+	if start, err := StartTime(child); err != nil || start < self {
+		t.Errorf("StartTime(child) = %d, %v; want at least %d", start, err, self)
+	}
+	if _, err := StartTime(1<<22 + 1); err == nil {
+		t.Error("StartTime(missing pid) succeeded")
+	}
+}
+
+// startSleep starts a sleep and returns its PID, reaping it in the
+// background.
+func startSleep(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting sleep: %v", err)
+	}
+	go func() { _ = cmd.Wait() }()
+	t.Cleanup(func() { _ = cmd.Process.Kill() })
+	return cmd.Process.Pid
+}
+
+// captureWarnings returns the warnings logged for the rest of the test.
+func captureWarnings(t *testing.T) *[]string {
+	t.Helper()
+	orig := warnf
+	t.Cleanup(func() { warnf = orig })
+	var warnings []string
+	warnf = func(format string, args ...interface{}) {
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+	}
+	return &warnings
+}
+
+// reuseStartTimes makes the start time of each of pids read differently
+// after the first time, as if the process had exited and its PID been
+// given to another.
+func reuseStartTimes(t *testing.T, pids ...int) {
+	t.Helper()
+	reused := make(map[int]bool)
+	for _, pid := range pids {
+		reused[pid] = true
+	}
+	seen := make(map[int]bool)
+	orig := startTimeOf
+	origSnapshot := takeSnapshot
+	t.Cleanup(func() { startTimeOf, takeSnapshot = orig, origSnapshot })
+	startTimeOf = func(pid int) (uint64, error) {
+		start, err := orig(pid)
+		if reused[pid] && seen[pid] {
+			start++
+		}
+		seen[pid] = true
+		return start, err
+	}
+	// A snapshot reads the start times too.
+	takeSnapshot = func() (*Table, error) {
+		table, err := origSnapshot()
+		if err == nil {
+			for _, p := range table.Processes() {
+				seen[p.PID] = true
+			}
+		}
+		return table, err
+	}
+}
+
+func TestSignalAllVerified(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("requires /proc")
+	}
+	same, reused := startSleep(t), startSleep(t)
+	procs := RecordStartTimes([]int{same, reused})
+	warnings := captureWarnings(t)
+	procs[1].StartTime++ // Recorded before the PID was reused
+
+	if sent := SignalAllVerified(procs, syscall.SIGTERM); sent != 1 {
+		t.Errorf("SignalAllVerified() = %d, want 1", sent)
+	}
+	waitGone(t, same)
+	if !running(reused) {
+		t.Errorf("pid %d was signaled though its start time changed", reused)
+	}
+	if len(*warnings) != 1 || !strings.Contains((*warnings)[0], fmt.Sprint(reused)) {
+		t.Errorf("warnings = %q, want one about pid %d", *warnings, reused)
+	}
+
+	if err := SignalVerified(procs[1], syscall.SIGTERM); !errors.Is(err, ErrPIDReused) {
+		t.Errorf("SignalVerified(reused) = %v, want ErrPIDReused", err)
+	}
+	// With no start time recorded there is nothing to check.
+	if err := SignalVerified(ProcessInfo{PID: reused}, syscall.SIGTERM); err != nil {
+		t.Errorf("SignalVerified(no start time) = %v", err)
+	}
+	waitGone(t, reused)
+}
+
+func TestKillTree_SkipsReusedPID(t *testing.T) {
+	root, children := startTree(t, "sleep 30 & sleep 30 & wait")
+	// children[0]'s start time changes after KillTree records it.
+	reuseStartTimes(t, children[0])
+	warnings := captureWarnings(t)
+
+	terminated, killed, err := KillTree(root, 5*time.Second)
+	if err != nil {
+		t.Fatalf("KillTree: %v", err)
+	}
+	if terminated != 2 || killed != 0 {
+		t.Errorf("terminated, killed = %d, %d; want 2, 0", terminated, killed)
+	}
+	waitGone(t, root, children[1])
+	if !running(children[0]) {
+		t.Errorf("pid %d was killed though its start time changed", children[0])
+	}
+	if len(*warnings) != 1 || !strings.Contains((*warnings)[0], fmt.Sprint(children[0])) {
+		t.Errorf("warnings = %q, want one about pid %d", *warnings, children[0])
+	}
+	_ = Signal(children[0], syscall.SIGKILL)
+}
+
+func TestKillTreeVerified_ReusedRoot(t *testing.T) {
+	root, children := startTree(t, "sleep 30 & sleep 30 & wait")
+	snap := RecordStartTimes([]int{root})[0]
+	snap.StartTime++ // Recorded before the PID was reused
+	warnings := captureWarnings(t)
+
+	if _, _, err := KillTreeVerified(snap, 0); !errors.Is(err, ErrPIDReused) {
+		t.Errorf("KillTreeVerified() = %v, want ErrPIDReused", err)
+	}
+	for _, pid := range append(children, root) {
+		if !running(pid) {
+			t.Errorf("pid %d was killed though the root's PID was reused", pid)
+		}
+	}
+	if len(*warnings) != 1 {
+		t.Errorf("warnings = %q, want one", *warnings)
+	}
+
+	// The root as it is now is killed.
+	if _, _, err := KillTreeVerified(RecordStartTimes([]int{root})[0], 0); err != nil {
+		t.Fatalf("KillTreeVerified: %v", err)
+	}
+	waitGone(t, append(children, root)...)
+}
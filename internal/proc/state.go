@@ -0,0 +1,39 @@
+package proc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StateZombie is the state of a process that has exited but not yet been
+// reaped by its parent, on Linux and macOS alike.
+const StateZombie = 'Z'
+
+// State returns the state letter of pid: field 3 of /proc/<pid>/stat on
+// Linux ('R' running, 'S' sleeping, 'D' in uninterruptible wait, 'Z'
+// zombie, 'T' stopped, ...), the first letter of ps's state column
+// elsewhere.
+func State(pid int) (rune, error) {
+	return state(pid)
+}
+
+// IsZombie reports whether pid has exited and is waiting to be reaped
+// by its parent. A process that can't be found is not a zombie.
+func IsZombie(pid int) bool {
+	s, err := State(pid)
+	return err == nil && s == StateZombie
+}
+
+// parseState returns the state letter, the first field after the command
+// name, from the contents of /proc/<pid>/stat.
+func parseState(stat string) (rune, error) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("malformed stat: no command name")
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) == 0 || len(fields[0]) != 1 {
+		return 0, fmt.Errorf("malformed stat: no state")
+	}
+	return rune(fields[0][0]), nil
+}
@@ -0,0 +1,61 @@
+package proc
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestParseState(t *testing.T) {
+	// Every state letter Linux has used, after a name with spaces and
+	// parentheses in it.
+	for _, want := range "RSDZTtWXxKPI" {
+		stat := "4242 (claude (main)) " + string(want) + " 4200 4242 4200 34817 4242 4194560 0 0 0 0 0 0 0 0 20 0 1 0 8812345 0 0"
+		got, err := parseState(stat)
+		if err != nil || got != want {
+			t.Errorf("parseState(%q) = %q, %v; want %q", stat, got, err, want)
+		}
+		p, err := parseStat(4242, stat)
+		if err != nil || p.State != want || p.IsZombie() != (want == StateZombie) {
+			t.Errorf("parseStat(%q) = %+v, %v; want state %q", stat, p, err, want)
+		}
+	}
+	for _, bad := range []string{"", "4242 claude S 1", "4242 (claude)", "4242 (claude) SS 1"} {
+		if _, err := parseState(bad); err == nil {
+			t.Errorf("parseState(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestIsZombieChild(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("process states can't be read on this platform")
+	}
+	// Until it is waited for, a child that has exited is a zombie.
+	cmd := exec.Command("true")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("starting true: %v", err)
+	}
+	pid := cmd.Process.Pid
+	zombie := false
+	for i := 0; i < 500 && !zombie; i++ {
+		zombie = IsZombie(pid)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !zombie {
+		_ = cmd.Wait()
+		t.Fatalf("IsZombie(%d) = false for an exited child never waited for", pid)
+	}
+	if s, err := State(pid); err != nil || s != StateZombie {
+		t.Errorf("State(%d) = %q, %v; want 'Z'", pid, s, err)
+	}
+
+	_ = cmd.Wait()
+	if IsZombie(pid) {
+		t.Errorf("IsZombie(%d) = true once reaped", pid)
+	}
+	if _, err := State(pid); err == nil {
+		t.Errorf("State(%d) succeeded once reaped", pid)
+	}
+}
@@ -0,0 +1,144 @@
+package proc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of the CPU times in /proc/<pid>/stat.
+// It is 100 on every Linux architecture gt runs on, and reading it with
+// sysconf would need cgo.
+const clockTicks = 100
+
+// ProcStats is a sample of the CPU and memory a process, or with
+// TreeStats a process tree, has used.
+type ProcStats struct {
+	PID        int           `json:"pid"`
+	UserTime   time.Duration `json:"user_time"`   // CPU time in user mode, since start
+	SystemTime time.Duration `json:"system_time"` // CPU time in the kernel, since start
+	RSS        uint64        `json:"rss"`         // Resident memory, in bytes
+	Threads    int           `json:"threads"`
+	Processes  int           `json:"processes"` // 1, or the size of the tree
+	SampledAt  time.Time     `json:"sampled_at"`
+
+	// Supported is false where the platform's stats can't be read (there
+	// is no /proc on macOS); the other fields are then zero.
+	Supported bool `json:"supported"`
+}
+
+// CPUTime returns the user and system CPU time together.
+func (s *ProcStats) CPUTime() time.Duration {
+	return s.UserTime + s.SystemTime
+}
+
+// Stats samples the CPU and memory use of pid: on Linux from
+// /proc/<pid>/stat and /proc/<pid>/status. Elsewhere it only checks that
+// pid exists and returns zeroes with Supported false.
+func Stats(pid int) (*ProcStats, error) {
+	return stats(pid)
+}
+
+// TreeStats samples rootPID and its descendants (see GetAllDescendants)
+// and returns their sum. Descendants that exit before they are sampled
+// are left out; only a failure to sample rootPID is an error.
+func TreeStats(rootPID int) (*ProcStats, error) {
+	total, err := Stats(rootPID)
+	if err != nil {
+		return nil, err
+	}
+	for _, pid := range GetAllDescendants(rootPID) {
+		s, err := Stats(pid)
+		if err != nil {
+			continue
+		}
+		total.UserTime += s.UserTime
+		total.SystemTime += s.SystemTime
+		total.RSS += s.RSS
+		total.Threads += s.Threads
+		total.Processes++
+	}
+	return total, nil
+}
+
+// StatsDelta returns the CPU used between two samples of the same process
+// or tree, as a percentage of one core: 100 is one core busy throughout,
+// and a multi-threaded process can exceed it. It is 0 if either sample is
+// unsupported or cur was not taken after prev. CPU time of processes that
+// left the tree between the samples is lost, so the difference is never
+// taken as negative.
+func StatsDelta(prev, cur *ProcStats) float64 {
+	if prev == nil || cur == nil || !prev.Supported || !cur.Supported {
+		return 0
+	}
+	wall := cur.SampledAt.Sub(prev.SampledAt)
+	cpu := cur.CPUTime() - prev.CPUTime()
+	if wall <= 0 || cpu <= 0 {
+		return 0
+	}
+	return float64(cpu) / float64(wall) * 100
+}
+
+// parseProcStat returns the user and system CPU time from the contents of
+// /proc/<pid>/stat. As in getParentPID, the fields are counted from after
+// the last ')', which closes the command name.
+func parseProcStat(stat string) (user, system time.Duration, err error) {
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, 0, fmt.Errorf("malformed stat: no command name")
+	}
+	// After the name: state (field 3) ... utime (14), stime (15).
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 13 {
+		return 0, 0, fmt.Errorf("malformed stat: %d fields after the command name", len(fields))
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed stat utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("malformed stat stime: %w", err)
+	}
+	return ticksToDuration(utime), ticksToDuration(stime), nil
+}
+
+func ticksToDuration(ticks uint64) time.Duration {
+	return time.Duration(ticks) * time.Second / clockTicks
+}
+
+// parseProcStatus returns the resident memory in bytes and the thread
+// count from the contents of /proc/<pid>/status. Kernel threads and
+// zombies have no VmRSS line; their RSS is 0.
+func parseProcStatus(status string) (rss uint64, threads int, err error) {
+	foundThreads := false
+	for _, line := range strings.Split(status, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		switch key {
+		case "VmRSS": // "VmRSS:	  123456 kB"
+			kb, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("malformed status VmRSS: %w", err)
+			}
+			rss = kb * 1024
+		case "Threads":
+			n, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return 0, 0, fmt.Errorf("malformed status Threads: %w", err)
+			}
+			threads, foundThreads = n, true
+		}
+	}
+	if !foundThreads {
+		return 0, 0, fmt.Errorf("malformed status: no Threads line")
+	}
+	return rss, threads, nil
+}
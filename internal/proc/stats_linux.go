@@ -0,0 +1,40 @@
+package proc
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+func stats(pid int) (*ProcStats, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	stat, err := readProcFileWithRetry(filepath.Join(dir, "stat"))
+	if err != nil {
+		return nil, fmt.Errorf("reading stats of pid %d: %w", pid, err)
+	}
+	status, err := readProcFileWithRetry(filepath.Join(dir, "status"))
+	if err != nil {
+		return nil, fmt.Errorf("reading stats of pid %d: %w", pid, err)
+	}
+	sampledAt := time.Now()
+
+	user, system, err := parseProcStat(string(stat))
+	if err != nil {
+		return nil, fmt.Errorf("pid %d: %w", pid, err)
+	}
+	rss, threads, err := parseProcStatus(string(status))
+	if err != nil {
+		return nil, fmt.Errorf("pid %d: %w", pid, err)
+	}
+	return &ProcStats{
+		PID:        pid,
+		UserTime:   user,
+		SystemTime: system,
+		RSS:        rss,
+		Threads:    threads,
+		Processes:  1,
+		SampledAt:  sampledAt,
+		Supported:  true,
+	}, nil
+}
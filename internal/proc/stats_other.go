@@ -0,0 +1,18 @@
+//go:build !linux
+
+package proc
+
+import (
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// stats has no /proc to read here: it reports zeroes, unsupported, for a
+// process that exists.
+func stats(pid int) (*ProcStats, error) {
+	if err := syscall.Kill(pid, 0); err != nil && err != syscall.EPERM {
+		return nil, fmt.Errorf("reading stats of pid %d: %w", pid, err)
+	}
+	return &ProcStats{PID: pid, Processes: 1, SampledAt: time.Now()}, nil
+}
@@ -0,0 +1,133 @@
+package proc
+
+import (
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// A claude process as /proc shows it; the command name has a space and a
+// parenthesis in it.
+const (
+	fixtureStat = "4242 (claude (main)) S 4200 4242 4200 34817 4242 4194560 " +
+		"98123 0 12 0 1234 567 0 0 20 0 23 0 8812345 7516192768 198656 " +
+		"18446744073709551615 1 1 0 0 0 0 0 16781312 134235650 0 0 0 17 3 0 0 0 0 0\n"
+
+	fixtureStatus = "Name:\tclaude\n" +
+		"Umask:\t0022\n" +
+		"State:\tS (sleeping)\n" +
+		"Tgid:\t4242\n" +
+		"PPid:\t4200\n" +
+		"VmPeak:\t 7340032 kB\n" +
+		"VmRSS:\t  794624 kB\n" +
+		"RssAnon:\t  700000 kB\n" +
+		"Threads:\t23\n" +
+		"voluntary_ctxt_switches:\t150\n"
+
+	// A kernel thread has no memory of its own.
+	fixtureKthreadStatus = "Name:\tkworker/0:1\nState:\tI (idle)\nThreads:\t1\n"
+)
+
+func TestParseProcStat(t *testing.T) {
+	user, system, err := parseProcStat(fixtureStat)
+	if err != nil {
+		t.Fatalf("parseProcStat() error = %v", err)
+	}
+	if user != 12340*time.Millisecond || system != 5670*time.Millisecond {
+		t.Errorf("parseProcStat() = %v, %v; want 12.34s, 5.67s", user, system)
+	}
+
+	for _, bad := range []string{"", "4242 claude S 1", "4242 (claude) S 1 2 3", "4242 (claude) S 4200 4242 4200 34817 4242 4194560 98123 0 12 0 x 567"} {
+		if _, _, err := parseProcStat(bad); err == nil {
+			t.Errorf("parseProcStat(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestParseProcStatus(t *testing.T) {
+	rss, threads, err := parseProcStatus(fixtureStatus)
+	if err != nil {
+		t.Fatalf("parseProcStatus() error = %v", err)
+	}
+	if rss != 794624*1024 || threads != 23 {
+		t.Errorf("parseProcStatus() = %d, %d; want %d, 23", rss, threads, 794624*1024)
+	}
+
+	rss, threads, err = parseProcStatus(fixtureKthreadStatus)
+	if err != nil || rss != 0 || threads != 1 {
+		t.Errorf("parseProcStatus(kernel thread) = %d, %d, %v; want 0, 1, nil", rss, threads, err)
+	}
+
+	if _, _, err := parseProcStatus("Name:\tclaude\nVmRSS:\t10 kB\n"); err == nil {
+		t.Error("parseProcStatus() without Threads succeeded, want an error")
+	}
+}
+
+func TestStatsDelta(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	sample := func(cpu time.Duration, after time.Duration) *ProcStats {
+		return &ProcStats{UserTime: cpu * 3 / 4, SystemTime: cpu / 4, SampledAt: at.Add(after), Supported: true}
+	}
+
+	tests := []struct {
+		name      string
+		prev, cur *ProcStats
+		want      float64
+	}{
+		{"one core busy", sample(10*time.Second, 0), sample(12*time.Second, 2*time.Second), 100},
+		{"a quarter of a core", sample(0, 0), sample(time.Second, 4*time.Second), 25},
+		{"several cores", sample(0, 0), sample(3*time.Second, time.Second), 300},
+		{"idle", sample(5*time.Second, 0), sample(5*time.Second, time.Minute), 0},
+		{"tree shrank", sample(9*time.Second, 0), sample(4*time.Second, time.Second), 0},
+		{"same instant", sample(0, time.Second), sample(time.Second, time.Second), 0},
+		{"out of order", sample(0, time.Second), sample(time.Second, 0), 0},
+		{"unsupported", &ProcStats{SampledAt: at}, &ProcStats{SampledAt: at.Add(time.Second)}, 0},
+		{"missing sample", nil, sample(time.Second, time.Second), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StatsDelta(tt.prev, tt.cur); got != tt.want {
+				t.Errorf("StatsDelta() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsLive(t *testing.T) {
+	s, err := Stats(os.Getpid())
+	if err != nil {
+		t.Fatalf("Stats(self) error = %v", err)
+	}
+	if s.PID != os.Getpid() || s.Processes != 1 {
+		t.Errorf("Stats(self) = %+v, want this process", s)
+	}
+	if runtime.GOOS != "linux" {
+		if s.Supported || s.RSS != 0 {
+			t.Errorf("Stats(self) = %+v, want zeroes, unsupported", s)
+		}
+		return
+	}
+	if !s.Supported || s.RSS == 0 || s.Threads < 1 {
+		t.Errorf("Stats(self) = %+v, want memory and threads", s)
+	}
+
+	if _, err := Stats(1 << 30); err == nil {
+		t.Error("Stats() of a missing process succeeded, want an error")
+	}
+}
+
+func TestTreeStatsLive(t *testing.T) {
+	pid, _ := startTree(t, "sleep 30 & sleep 30 & wait")
+	root, err := Stats(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := TreeStats(pid)
+	if err != nil {
+		t.Fatalf("TreeStats() error = %v", err)
+	}
+	if tree.Processes != 3 || tree.RSS <= root.RSS || tree.Threads <= root.Threads {
+		t.Errorf("TreeStats() = %+v, want more than the root's %+v", tree, root)
+	}
+}
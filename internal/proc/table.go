@@ -0,0 +1,204 @@
+package proc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Process is one process in a Table.
+type Process struct {
+	PID       int
+	PPID      int
+	Comm      string   // Command name (see GetComm)
+	Cmdline   string   // Arguments joined by spaces, as FindByPattern matches them
+	Argv      []string // nil for kernel threads and processes whose arguments can't be read
+	StartTime uint64   // See StartTime; 0 if unknown
+	State     rune     // See State; 0 if unknown
+}
+
+// IsZombie reports whether p had exited, and was waiting to be reaped by
+// its parent, when the table was read.
+func (p Process) IsZombie() bool {
+	return p.State == StateZombie
+}
+
+// Table is a snapshot of the process table, to answer several queries
+// from one scan rather than a scan each. It is not kept up to date:
+// processes started since are missing from it, and ones that have exited
+// are still in it.
+type Table struct {
+	procs    []Process
+	byPID    map[int]int   // Index into procs
+	children map[int][]int // Child PIDs by parent, in table order
+}
+
+// Snapshot reads the process table once. On Linux each process's stat and
+// cmdline are read from /proc; on macOS ps is run once for the table, and
+// sysctl read for start times and arguments. Elsewhere it is an error.
+func Snapshot() (*Table, error) {
+	return snapshot()
+}
+
+// takeSnapshot takes the snapshots KillTree works from; replaced in tests.
+var takeSnapshot = Snapshot
+
+// NewTable builds a Table from procs, in the order given: for processes
+// listed some other way, and for tests.
+func NewTable(procs []Process) *Table {
+	t := &Table{
+		procs:    procs,
+		byPID:    make(map[int]int, len(procs)),
+		children: make(map[int][]int),
+	}
+	for i, p := range procs {
+		t.byPID[p.PID] = i
+		if p.PID != p.PPID {
+			t.children[p.PPID] = append(t.children[p.PPID], p.PID)
+		}
+	}
+	return t
+}
+
+// WithoutZombies returns a table of the processes in t that are not
+// zombies. A zombie has exited and holds only its PID and exit status
+// until its parent reaps it, so it should not count as running.
+func (t *Table) WithoutZombies() *Table {
+	procs := make([]Process, 0, len(t.procs))
+	for _, p := range t.procs {
+		if !p.IsZombie() {
+			procs = append(procs, p)
+		}
+	}
+	return NewTable(procs)
+}
+
+// Zombies returns the processes in t that are zombies.
+func (t *Table) Zombies() []Process {
+	var zombies []Process
+	for _, p := range t.procs {
+		if p.IsZombie() {
+			zombies = append(zombies, p)
+		}
+	}
+	return zombies
+}
+
+// Processes returns every process in the table.
+func (t *Table) Processes() []Process {
+	return t.procs
+}
+
+// Get returns the process pid, and whether it is in the table.
+func (t *Table) Get(pid int) (Process, bool) {
+	if i, ok := t.byPID[pid]; ok {
+		return t.procs[i], true
+	}
+	return Process{}, false
+}
+
+// ParentPID returns the parent PID of pid, as GetParentPID does.
+func (t *Table) ParentPID(pid int) (int, error) {
+	p, ok := t.Get(pid)
+	if !ok {
+		return 0, fmt.Errorf("pid %d: %w", pid, syscall.ESRCH)
+	}
+	return p.PPID, nil
+}
+
+// Children returns the direct children of pid.
+func (t *Table) Children(pid int) []int {
+	return t.children[pid]
+}
+
+// Descendants returns the descendants of pid, deepest first, as
+// GetAllDescendants does.
+func (t *Table) Descendants(pid int) []int {
+	return descendants(pid, t.Children)
+}
+
+// FindByPattern returns the PIDs of processes whose command line contains
+// the pattern; see the FindByPattern function.
+func (t *Table) FindByPattern(pattern string) []int {
+	var pids []int
+	for _, p := range t.procs {
+		if strings.Contains(p.Cmdline, pattern) {
+			pids = append(pids, p.PID)
+		}
+	}
+	return pids
+}
+
+// Count counts the processes whose command line contains the pattern.
+func (t *Table) Count(pattern string) int {
+	return len(t.FindByPattern(pattern))
+}
+
+// FindMatching returns the PIDs of processes whose argv m matches.
+// Processes without one, such as kernel threads, are never matched.
+func (t *Table) FindMatching(m ArgvMatcher) []int {
+	var pids []int
+	for _, p := range t.procs {
+		if len(p.Argv) > 0 && m(p.Argv) {
+			pids = append(pids, p.PID)
+		}
+	}
+	return pids
+}
+
+// CountMatching counts the processes whose argv m matches.
+func (t *Table) CountMatching(m ArgvMatcher) int {
+	return len(t.FindMatching(m))
+}
+
+// Lookup returns pids with their command names and start times from the
+// table, to signal with SignalVerified, SignalAllVerified or
+// KillTreeVerified. PIDs not in the table are left out.
+func (t *Table) Lookup(pids []int) []ProcessInfo {
+	procs := make([]ProcessInfo, 0, len(pids))
+	for _, pid := range pids {
+		if p, ok := t.Get(pid); ok {
+			procs = append(procs, ProcessInfo{PID: p.PID, Comm: p.Comm, StartTime: p.StartTime})
+		}
+	}
+	return procs
+}
+
+// parseStat returns the parent PID, command name, state and start time of
+// pid from the contents of /proc/<pid>/stat. The name is everything
+// between the first '(' and the last ')', spaces and parentheses included.
+func parseStat(pid int, stat string) (Process, error) {
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return Process{}, fmt.Errorf("malformed stat: no command name")
+	}
+	// After the name: state (field 3), ppid (4).
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return Process{}, fmt.Errorf("malformed stat: %d fields after the command name", len(fields))
+	}
+	state, err := parseState(stat)
+	if err != nil {
+		return Process{}, err
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Process{}, fmt.Errorf("malformed stat ppid: %w", err)
+	}
+	start, err := parseStartTime(stat)
+	if err != nil {
+		return Process{}, err
+	}
+	return Process{PID: pid, PPID: ppid, Comm: stat[open+1 : end], StartTime: start, State: state}, nil
+}
+
+// parseCmdline returns the argv and space-joined command line from the
+// contents of /proc/<pid>/cmdline, where each argument ends in a NUL.
+func parseCmdline(data []byte) (argv []string, cmdline string) {
+	if len(data) == 0 {
+		return nil, ""
+	}
+	argv = strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+	return argv, strings.ReplaceAll(string(data), "\x00", " ")
+}
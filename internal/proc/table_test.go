@@ -0,0 +1,304 @@
+package proc
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// fixtureTable is a tmux session running an agent, with a bd daemon
+// under it and another outside, a kernel thread, and an editor with
+// "bd daemon" in a file name.
+func fixtureTable() *Table {
+	return NewTable([]Process{
+		{PID: 1, PPID: 0, Comm: "systemd", Argv: []string{"/sbin/init"}, Cmdline: "/sbin/init ", StartTime: 1},
+		{PID: 2, PPID: 0, Comm: "kthreadd", StartTime: 1},
+		{PID: 412, PPID: 1, Comm: "tmux: server", Argv: []string{"tmux", "new-session", "-d", "-s", "gt-gongshow-witness"},
+			Cmdline: "tmux new-session -d -s gt-gongshow-witness ", StartTime: 5000},
+		{PID: 413, PPID: 412, Comm: "bash", Argv: []string{"-bash"}, Cmdline: "-bash ", StartTime: 5001},
+		{PID: 420, PPID: 413, Comm: "claude", Argv: []string{"claude", "--dangerously-skip-permissions"},
+			Cmdline: "claude --dangerously-skip-permissions ", StartTime: 5002},
+		{PID: 421, PPID: 420, Comm: "bd", Argv: []string{"bd", "daemon", "--start"}, Cmdline: "bd daemon --start ", StartTime: 5003},
+		{PID: 422, PPID: 413, Comm: "git", Argv: []string{"git", "status"}, Cmdline: "git status ", StartTime: 5004},
+		{PID: 500, PPID: 1, Comm: "bd", Argv: []string{"/usr/local/bin/bd", "daemon"}, Cmdline: "/usr/local/bin/bd daemon ", StartTime: 6000},
+		{PID: 600, PPID: 1, Comm: "vim", Argv: []string{"vim", "notes/bd daemon"}, Cmdline: "vim notes/bd daemon ", StartTime: 7000},
+	})
+}
+
+func TestTableTree(t *testing.T) {
+	table := fixtureTable()
+
+	if got := strings.Join(pidStrings(table.Children(413)), " "); got != "420 422" {
+		t.Errorf("Children(413) = %s, want 420 422", got)
+	}
+	if got := table.Children(421); got != nil {
+		t.Errorf("Children(421) = %v, want none", got)
+	}
+	// Deepest first: 421 before its parent 420.
+	if got := strings.Join(pidStrings(table.Descendants(412)), " "); got != "421 420 422 413" {
+		t.Errorf("Descendants(412) = %s, want 421 420 422 413", got)
+	}
+	// init and the kernel's threads have parent 0.
+	if got := table.Children(0); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("Children(0) = %v, want [1 2]", got)
+	}
+
+	if ppid, err := table.ParentPID(421); err != nil || ppid != 420 {
+		t.Errorf("ParentPID(421) = %d, %v; want 420", ppid, err)
+	}
+	if _, err := table.ParentPID(999); err == nil {
+		t.Error("ParentPID(missing) succeeded")
+	}
+	if p, ok := table.Get(412); !ok || p.Comm != "tmux: server" {
+		t.Errorf("Get(412) = %+v, %v", p, ok)
+	}
+	if len(table.Processes()) != 9 {
+		t.Errorf("Processes() has %d, want 9", len(table.Processes()))
+	}
+}
+
+func TestTableQueries(t *testing.T) {
+	table := fixtureTable()
+
+	// The substring match is fooled by the editor's file name.
+	if got := table.FindByPattern("bd daemon"); !slices.Equal(got, []int{421, 500, 600}) {
+		t.Errorf("FindByPattern(bd daemon) = %v, want [421 500 600]", got)
+	}
+	if n := table.Count("tmux"); n != 1 {
+		t.Errorf("Count(tmux) = %d, want 1", n)
+	}
+	if n := table.Count("no such thing"); n != 0 {
+		t.Errorf("Count(no such thing) = %d, want 0", n)
+	}
+
+	daemons := MatchBasename(MatchExactArgs("bd", "daemon"))
+	if got := table.FindMatching(daemons); !slices.Equal(got, []int{421, 500}) {
+		t.Errorf("FindMatching(bd daemon) = %v, want [421 500]", got)
+	}
+	if n := table.CountMatching(daemons); n != 2 {
+		t.Errorf("CountMatching(bd daemon) = %d, want 2", n)
+	}
+	// Kernel threads have no argv to match, even by a pattern that
+	// matches anything.
+	if got := table.FindMatching(MatchRegexp(regexp.MustCompile(`.*`))); slices.Contains(got, 2) {
+		t.Errorf("FindMatching(.*) = %v, includes the kernel thread", got)
+	}
+
+	want := []ProcessInfo{{PID: 421, Comm: "bd", StartTime: 5003}, {PID: 500, Comm: "bd", StartTime: 6000}}
+	if got := table.Lookup([]int{421, 999, 500}); !slices.Equal(got, want) {
+		t.Errorf("Lookup() = %+v, want %+v", got, want)
+	}
+}
+
+func TestTableZombies(t *testing.T) {
+	// A bd daemon killed under the agent, not yet reaped, still matches by
+	// its command name and, on macOS, its arguments.
+	table := NewTable([]Process{
+		{PID: 420, PPID: 413, Comm: "claude", Argv: []string{"claude"}, Cmdline: "claude ", State: 'S'},
+		{PID: 421, PPID: 420, Comm: "bd", Argv: []string{"bd", "daemon"}, Cmdline: "bd daemon ", State: StateZombie},
+		{PID: 500, PPID: 1, Comm: "bd", Argv: []string{"bd", "daemon"}, Cmdline: "bd daemon ", State: 'S'},
+	})
+	daemons := MatchBasename(MatchExactArgs("bd", "daemon"))
+
+	if n := table.CountMatching(daemons); n != 2 {
+		t.Errorf("CountMatching(bd daemon) = %d, want 2 with the zombie", n)
+	}
+	live := table.WithoutZombies()
+	if got := live.FindMatching(daemons); !slices.Equal(got, []int{500}) {
+		t.Errorf("WithoutZombies().FindMatching(bd daemon) = %v, want [500]", got)
+	}
+	if n := live.Count("bd daemon"); n != 1 {
+		t.Errorf("WithoutZombies().Count(bd daemon) = %d, want 1", n)
+	}
+	if got := live.Children(420); got != nil {
+		t.Errorf("WithoutZombies().Children(420) = %v, want none", got)
+	}
+
+	zombies := table.Zombies()
+	if len(zombies) != 1 || zombies[0].PID != 421 || !zombies[0].IsZombie() {
+		t.Errorf("Zombies() = %+v, want 421", zombies)
+	}
+	if live.Zombies() != nil {
+		t.Errorf("WithoutZombies().Zombies() = %+v, want none", live.Zombies())
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	p, err := parseStat(4242, fixtureStat)
+	if err != nil {
+		t.Fatalf("parseStat() error: %v", err)
+	}
+	want := Process{PID: 4242, PPID: 4200, Comm: "claude (main)", StartTime: 8812345, State: 'S'}
+	if p.PID != want.PID || p.PPID != want.PPID || p.Comm != want.Comm || p.StartTime != want.StartTime || p.State != want.State {
+		t.Errorf("parseStat() = %+v, want %+v", p, want)
+	}
+	for _, bad := range []string{"", "4242 claude S 1", "4242 (claude) S x", "4242 (claude) S 1 2 3"} {
+		if _, err := parseStat(4242, bad); err == nil {
+			t.Errorf("parseStat(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestParseCmdline(t *testing.T) {
+	argv, cmdline := parseCmdline([]byte("bd\x00daemon\x00--start\x00"))
+	if !slices.Equal(argv, []string{"bd", "daemon", "--start"}) || cmdline != "bd daemon --start " {
+		t.Errorf("parseCmdline() = %q, %q", argv, cmdline)
+	}
+	// An argument may itself contain spaces.
+	argv, _ = parseCmdline([]byte("vim\x00notes/bd daemon\x00"))
+	if !slices.Equal(argv, []string{"vim", "notes/bd daemon"}) {
+		t.Errorf("parseCmdline() = %q", argv)
+	}
+	if argv, cmdline := parseCmdline(nil); argv != nil || cmdline != "" {
+		t.Errorf("parseCmdline(kernel thread) = %q, %q; want nothing", argv, cmdline)
+	}
+}
+
+// fakeProc serves readProcDir and readProcFile from files, keyed by their
+// path under /proc, for the rest of the test.
+func fakeProc(t *testing.T, files map[string]string) {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	origDir, origFile := readProcDir, readProcFile
+	t.Cleanup(func() { readProcDir, readProcFile = origDir, origFile })
+	readProcDir = func(string) ([]os.DirEntry, error) { return os.ReadDir(root) }
+	readProcFile = func(path string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(root, strings.TrimPrefix(path, "/proc/")))
+	}
+}
+
+func TestSnapshotFakeProc(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("macOS reads ps, not /proc")
+	}
+	fakeProc(t, map[string]string{
+		"uptime":        "12345.67 8910.11\n",
+		"sys/kernel":    "",
+		"100/stat":      "100 (tmux: server) S 1 100 100 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 5000 0 0",
+		"100/cmdline":   "tmux\x00new-session\x00",
+		"101/stat":      "101 (claude) S 100 101 101 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 5001 0 0",
+		"101/cmdline":   "claude\x00",
+		"2/stat":        "2 (kthreadd) S 0 0 0 0 -1 2129984 0 0 0 0 0 0 0 0 20 0 1 0 1 0 0",
+		"2/cmdline":     "",
+		"102/cmdline":   "gone\x00",    // Exited before its stat was read
+		"103/stat":      "103 garbage", // Unparsable
+		"103/cmdline":   "garbage\x00",
+		"104/stat":      "104 (zsh) S 100 104 104 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 5002 0 0",
+		"105/unrelated": "",
+		"106/stat":      "106 (bd) Z 101 106 106 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 5003 0 0",
+		"106/cmdline":   "bd\x00daemon\x00", // Empty for a real zombie
+	})
+
+	table, err := Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error: %v", err)
+	}
+	var pids []int
+	for _, p := range table.Processes() {
+		pids = append(pids, p.PID)
+	}
+	slices.Sort(pids)
+	if !slices.Equal(pids, []int{2, 100, 101, 104, 106}) {
+		t.Errorf("Snapshot() has pids %v, want [2 100 101 104 106]", pids)
+	}
+
+	claude, _ := table.Get(101)
+	if claude.PPID != 100 || claude.Comm != "claude" || claude.StartTime != 5001 || !slices.Equal(claude.Argv, []string{"claude"}) {
+		t.Errorf("claude = %+v", claude)
+	}
+	// A missing cmdline leaves the process with no argv.
+	if zsh, _ := table.Get(104); zsh.Argv != nil || zsh.Comm != "zsh" {
+		t.Errorf("zsh = %+v, want no argv", zsh)
+	}
+	if got := table.Descendants(100); !slices.Equal(got, []int{106, 101, 104}) {
+		t.Errorf("Descendants(100) = %v, want [106 101 104]", got)
+	}
+
+	// The zombie is in the snapshot, but not found by the package's
+	// queries, which count what is running.
+	if bd, _ := table.Get(106); !bd.IsZombie() {
+		t.Errorf("bd = %+v, want a zombie", bd)
+	}
+	if got := table.FindByPattern("bd daemon"); !slices.Equal(got, []int{106}) {
+		t.Errorf("Table.FindByPattern(bd daemon) = %v, want [106]", got)
+	}
+	if got := FindByPattern("bd daemon"); got != nil {
+		t.Errorf("FindByPattern(bd daemon) = %v, want the zombie left out", got)
+	}
+	if n := CountByPattern("bd daemon"); n != 0 {
+		t.Errorf("CountByPattern(bd daemon) = %d, want 0", n)
+	}
+	if n := CountByExactArgs("bd", "daemon"); n != 0 {
+		t.Errorf("CountByExactArgs(bd daemon) = %d, want 0", n)
+	}
+
+	readProcDir = func(string) ([]os.DirEntry, error) { return nil, syscall.EACCES }
+	if _, err := Snapshot(); err == nil {
+		t.Error("Snapshot() succeeded without /proc")
+	}
+}
+
+// BenchmarkDoctorProcessQueries makes the process queries of a gt doctor
+// run, for bd daemons, bd activity processes, tmux servers and agents,
+// each with its own scan and then all from one Snapshot, and reports the
+// /proc reads each way: one open and read of a file, or listing of /proc,
+// is one read.
+func BenchmarkDoctorProcessQueries(b *testing.B) {
+	if runtime.GOOS != "linux" {
+		b.Skip("requires /proc")
+	}
+	reads := 0
+	origDir, origFile := readProcDir, readProcFile
+	b.Cleanup(func() { readProcDir, readProcFile = origDir, origFile })
+	readProcDir = func(path string) ([]os.DirEntry, error) {
+		reads++
+		return origDir(path)
+	}
+	readProcFile = func(path string) ([]byte, error) {
+		reads++
+		return origFile(path)
+	}
+
+	daemons := MatchBasename(MatchExactArgs("bd", "daemon"))
+	activity := MatchBasename(MatchExactArgs("bd", "activity"))
+
+	b.Run("separate-scans", func(b *testing.B) {
+		reads = 0
+		for i := 0; i < b.N; i++ {
+			CountMatching(daemons)
+			CountMatching(activity)
+			FindByPattern("tmux")
+			FindByPattern("claude")
+		}
+		b.ReportMetric(float64(reads)/float64(b.N), "reads/op")
+	})
+
+	b.Run("one-snapshot", func(b *testing.B) {
+		reads = 0
+		for i := 0; i < b.N; i++ {
+			table, err := Snapshot()
+			if err != nil {
+				b.Fatal(err)
+			}
+			table.CountMatching(daemons)
+			table.CountMatching(activity)
+			table.FindByPattern("tmux")
+			table.FindByPattern("claude")
+		}
+		b.ReportMetric(float64(reads)/float64(b.N), "reads/op")
+	})
+}
@@ -100,6 +100,36 @@ func (m *Manager) Status() (*Refinery, error) {
 	return m.loadState()
 }
 
+// WorkDir returns the working directory of the refinery of the rig at
+// rigPath: its worktree, refinery/rig/.
+func WorkDir(rigPath string) string {
+	refineryRigDir := filepath.Join(rigPath, "refinery", "rig")
+	if _, err := os.Stat(refineryRigDir); os.IsNotExist(err) {
+		// Fall back to mayor/rig (legacy architecture) - ensures we use project git, not town git.
+		// Using rig.Path directly would find town's .git with rig-named remotes instead of "origin".
+		refineryRigDir = filepath.Join(rigPath, "mayor", "rig")
+	}
+	return refineryRigDir
+}
+
+// BuildStartCommand returns the command the refinery of the rig at
+// rigPath is started with.
+func BuildStartCommand(rigPath, rigName string) (string, error) {
+	return buildStartCommand(rigPath, rigName, "")
+}
+
+func buildStartCommand(rigPath, rigName, agentOverride string) (string, error) {
+	townRoot := filepath.Dir(rigPath)
+	if agentOverride == "" {
+		return config.BuildAgentStartupCommand("refinery", rigName, townRoot, rigPath, ""), nil
+	}
+	command, err := config.BuildAgentStartupCommandWithAgentOverride("refinery", rigName, townRoot, rigPath, "", agentOverride)
+	if err != nil {
+		return "", fmt.Errorf("building startup command with agent override: %w", err)
+	}
+	return command, nil
+}
+
 // Start starts the refinery.
 // If foreground is true, runs in the current process (blocking) using the Go-based polling loop.
 // Otherwise, spawns a Claude agent in a tmux session to process the merge queue.
@@ -158,12 +188,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	// The Claude agent handles MR processing using git commands and beads
 
 	// Working directory is the refinery worktree (shares .git with mayor/polecats)
-	refineryRigDir := filepath.Join(m.rig.Path, "refinery", "rig")
-	if _, err := os.Stat(refineryRigDir); os.IsNotExist(err) {
-		// Fall back to mayor/rig (legacy architecture) - ensures we use project git, not town git.
-		// Using rig.Path directly would find town's .git with rig-named remotes instead of "origin".
-		refineryRigDir = filepath.Join(m.rig.Path, "mayor", "rig")
-	}
+	refineryRigDir := WorkDir(m.rig.Path)
 
 	// Ensure runtime settings exist in refinery/ (not refinery/rig/) so we don't
 	// write into the source repo. Runtime walks up the tree to find settings.
@@ -174,16 +199,9 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	}
 
 	// Build startup command first
-	townRoot := filepath.Dir(m.rig.Path)
-	var command string
-	if agentOverride != "" {
-		var err error
-		command, err = config.BuildAgentStartupCommandWithAgentOverride("refinery", m.rig.Name, townRoot, m.rig.Path, "", agentOverride)
-		if err != nil {
-			return fmt.Errorf("building startup command with agent override: %w", err)
-		}
-	} else {
-		command = config.BuildAgentStartupCommand("refinery", m.rig.Name, townRoot, m.rig.Path, "")
+	command, err := buildStartCommand(m.rig.Path, m.rig.Name, agentOverride)
+	if err != nil {
+		return err
 	}
 
 	// Create session with command directly to avoid send-keys race condition.
@@ -193,16 +211,16 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	}
 
 	// Set environment variables (non-fatal: session works without these)
-	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
+	// Use centralized AgentEnv for consistency across all role startup paths;
+	// a role config that can't be loaded leaves just the base environment.
+	townRoot := filepath.Dir(m.rig.Path)
+	roleConfig, _ := beads.LoadRoleConfig(townRoot, "refinery")
+	envVars := beads.AgentEnv(config.AgentEnvConfig{
 		Role:          "refinery",
 		Rig:           m.rig.Name,
 		TownRoot:      townRoot,
 		BeadsNoDaemon: true,
-	})
-
-	// Add refinery-specific flag
-	envVars["GT_REFINERY"] = "1"
+	}, sessionID, roleConfig)
 
 	// Set all env vars in tmux session (for debugging) and they'll also be exported to Claude
 	for k, v := range envVars {
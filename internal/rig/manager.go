@@ -646,6 +646,13 @@ func (m *Manager) initBeads(rigPath, prefix string) error {
 		}
 	}
 
+	// Run the registered schema migrations on the new store, recording them
+	// in its migrations.json. None are registered yet, so this does nothing
+	// until one is.
+	if _, err := beads.MigrateBeadsDir(rigPath, beadsDir); err != nil {
+		fmt.Printf("   ⚠ Could not run beads migrations: %v\n", err)
+	}
+
 	// NOTE: We intentionally do NOT create routes.jsonl in rig beads.
 	// bd's routing walks up to find town root (via mayor/town.json) and uses
 	// town-level routes.jsonl for prefix-based routing. Rig-level routes.jsonl
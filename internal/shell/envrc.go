@@ -0,0 +1,127 @@
+// ABOUTME: direnv integration for GongShow rigs.
+// ABOUTME: Manages a marked block in a repo's .envrc that sets the rig env vars.
+
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/KeithWyatt/gongshow/internal/util"
+)
+
+const (
+	envrcMarkerStart = "# --- GongShow direnv (managed by gt) ---"
+	envrcMarkerEnd   = "# --- End GongShow direnv ---"
+)
+
+// EnvrcBlock is the block added to a .envrc. Rather than hard-coding the
+// values, it asks gt for them on every load, so it stays correct when the
+// rig or town is renamed or moved, and reloads when the town's rigs change.
+var EnvrcBlock = envrcMarkerStart + `
+# Sets GT_TOWN_ROOT, GT_RIG and BD_ACTOR. Remove with: gt rig envrc --remove
+if has gt; then
+  eval "$(gt rig detect --quiet --actor "$PWD")"
+  if [[ -n "$GT_TOWN_ROOT" ]]; then
+    watch_file "$GT_TOWN_ROOT/mayor/rigs.json"
+  fi
+fi
+` + envrcMarkerEnd
+
+// EnvrcPath returns the .envrc path for a repository root.
+func EnvrcPath(repoRoot string) string {
+	return filepath.Join(repoRoot, ".envrc")
+}
+
+// WriteEnvrc adds EnvrcBlock to the .envrc at path, or replaces the block
+// already there, leaving the rest of the file as it is. It reports whether
+// the file changed.
+func WriteEnvrc(path string) (bool, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user's repo
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	content := string(data)
+
+	var newContent string
+	if before, after, found, err := cutEnvrcBlock(content, path); err != nil {
+		return false, err
+	} else if found {
+		newContent = before + EnvrcBlock + after
+	} else {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if content != "" {
+			content += "\n"
+		}
+		newContent = content + EnvrcBlock + "\n"
+	}
+
+	if newContent == string(data) {
+		return false, nil
+	}
+	if err := util.AtomicWriteFile(path, []byte(newContent), envrcMode(path)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveEnvrc removes EnvrcBlock from the .envrc at path, along with the
+// blank line WriteEnvrc put before it. The file is deleted if nothing else
+// is left in it. It reports whether the file changed.
+func RemoveEnvrc(path string) (bool, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is the user's repo
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	before, after, found, err := cutEnvrcBlock(string(data), path)
+	if err != nil || !found {
+		return false, err
+	}
+	after = strings.TrimPrefix(after, "\n")
+	if after == "" {
+		before = strings.TrimSuffix(before, "\n")
+	}
+	newContent := before + after
+
+	if strings.TrimSpace(newContent) == "" {
+		if err := os.Remove(path); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err := util.AtomicWriteFile(path, []byte(newContent), envrcMode(path)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// cutEnvrcBlock splits content around the managed block, markers
+// included. found is false if there is no block.
+func cutEnvrcBlock(content, path string) (before, after string, found bool, err error) {
+	startIdx := strings.Index(content, envrcMarkerStart)
+	if startIdx == -1 {
+		return content, "", false, nil
+	}
+	endIdx := strings.Index(content[startIdx:], envrcMarkerEnd)
+	if endIdx == -1 {
+		return "", "", false, fmt.Errorf("malformed GongShow block in %s", path)
+	}
+	endIdx += startIdx + len(envrcMarkerEnd)
+	return content[:startIdx], content[endIdx:], true, nil
+}
+
+// envrcMode returns the mode of the existing file at path, or 0644.
+func envrcMode(path string) os.FileMode {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0644
+}
@@ -0,0 +1,133 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteEnvrcIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".envrc")
+	userContent := "export FOO=bar\nlayout go"
+	if err := os.WriteFile(path, []byte(userContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := WriteEnvrc(path)
+	if err != nil {
+		t.Fatalf("WriteEnvrc() error = %v", err)
+	}
+	if !changed {
+		t.Error("first WriteEnvrc() reported no change")
+	}
+	first, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(first), userContent+"\n\n") {
+		t.Errorf("user content not preserved:\n%s", first)
+	}
+	if !strings.Contains(string(first), "gt rig detect --quiet") {
+		t.Errorf(".envrc doesn't call gt rig detect:\n%s", first)
+	}
+
+	changed, err = WriteEnvrc(path)
+	if err != nil {
+		t.Fatalf("second WriteEnvrc() error = %v", err)
+	}
+	if changed {
+		t.Error("second WriteEnvrc() reported a change")
+	}
+	second, _ := os.ReadFile(path)
+	if string(second) != string(first) {
+		t.Errorf("rewrite changed the file:\n%s\nwant:\n%s", second, first)
+	}
+
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600 kept", info.Mode().Perm())
+	}
+}
+
+func TestWriteEnvrcReplacesOutdatedBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".envrc")
+	old := "use nix\n\n" + envrcMarkerStart + "\nexport GT_RIG=oldname\n" + envrcMarkerEnd + "\ndotenv\n"
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := WriteEnvrc(path); err != nil {
+		t.Fatalf("WriteEnvrc() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	want := "use nix\n\n" + EnvrcBlock + "\ndotenv\n"
+	if string(data) != want {
+		t.Errorf(".envrc =\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestRemoveEnvrc(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+	}{
+		{"no trailing newline", "export FOO=bar"},
+		{"trailing newline", "export FOO=bar\n"},
+		{"multiple lines", "use nix\ndotenv\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".envrc")
+			if err := os.WriteFile(path, []byte(tt.original), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := WriteEnvrc(path); err != nil {
+				t.Fatalf("WriteEnvrc() error = %v", err)
+			}
+
+			removed, err := RemoveEnvrc(path)
+			if err != nil {
+				t.Fatalf("RemoveEnvrc() error = %v", err)
+			}
+			if !removed {
+				t.Error("RemoveEnvrc() reported no change")
+			}
+			data, _ := os.ReadFile(path)
+			if want := strings.TrimSuffix(tt.original, "\n") + "\n"; string(data) != want {
+				t.Errorf(".envrc after removal = %q, want %q", data, want)
+			}
+
+			removed, err = RemoveEnvrc(path)
+			if err != nil || removed {
+				t.Errorf("second RemoveEnvrc() = %v, %v; want false, nil", removed, err)
+			}
+		})
+	}
+}
+
+func TestRemoveEnvrcDeletesManagedOnlyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".envrc")
+	if _, err := WriteEnvrc(path); err != nil {
+		t.Fatalf("WriteEnvrc() error = %v", err)
+	}
+	if removed, err := RemoveEnvrc(path); err != nil || !removed {
+		t.Fatalf("RemoveEnvrc() = %v, %v", removed, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf(".envrc still exists after removing its only block (err = %v)", err)
+	}
+
+	if removed, err := RemoveEnvrc(path); err != nil || removed {
+		t.Errorf("RemoveEnvrc() on missing file = %v, %v; want false, nil", removed, err)
+	}
+}
+
+func TestEnvrcMalformedBlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".envrc")
+	if err := os.WriteFile(path, []byte(envrcMarkerStart+"\nexport GT_RIG=x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteEnvrc(path); err == nil {
+		t.Error("WriteEnvrc() accepted a block without an end marker")
+	}
+	if _, err := RemoveEnvrc(path); err == nil {
+		t.Error("RemoveEnvrc() accepted a block without an end marker")
+	}
+}
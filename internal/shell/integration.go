@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
+	"github.com/KeithWyatt/gongshow/internal/proc"
 	"github.com/KeithWyatt/gongshow/internal/state"
 )
 
@@ -17,74 +19,240 @@ const (
 	markerEnd   = "# --- End GongShow ---"
 )
 
-func hookSourceLine() string {
-	return fmt.Sprintf(`[[ -f "%s/shell-hook.sh" ]] && source "%s/shell-hook.sh"`,
-		state.ConfigDir(), state.ConfigDir())
+// HookScriptPath returns where the hook script for shell is installed.
+func HookScriptPath(shell string) string {
+	name := "shell-hook.sh"
+	switch shell {
+	case "fish":
+		name = "shell-hook.fish"
+	case "nu":
+		name = "shell-hook.nu"
+	}
+	return filepath.Join(state.ConfigDir(), name)
 }
 
+// hookSourceLine returns the RC file line that loads the hook script.
+func hookSourceLine(shell string) string {
+	hookPath := HookScriptPath(shell)
+	switch shell {
+	case "fish":
+		return fmt.Sprintf(`test -f "%s"; and source "%s"`, hookPath, hookPath)
+	case "nu":
+		// nu resolves source at parse time, so the script can't be optional;
+		// Remove takes this line out before deleting it.
+		return fmt.Sprintf(`source "%s"`, hookPath)
+	}
+	return fmt.Sprintf(`[[ -f "%s" ]] && source "%s"`, hookPath, hookPath)
+}
+
+// InstallOptions configures InstallWithOptions.
+type InstallOptions struct {
+	// Shell is the shell to install for ("zsh", "bash", "fish" or "nu")
+	// instead of the detected one.
+	Shell string
+
+	// RCFile is the file to add the integration to instead of the shell's
+	// default (RCFilePath). A leading ~ is expanded.
+	RCFile string
+
+	// AllowOutsideHome allows writing the RC file or hook script outside
+	// the user's home directory.
+	AllowOutsideHome bool
+}
+
+// Install installs the shell integration for the detected shell, in the
+// RC file it was installed in before or else the shell's default.
 func Install() error {
+	return InstallWithOptions(InstallOptions{})
+}
+
+// InstallWithOptions is Install with the given options.
+// The RC file used is recorded in the state, so later installs and Remove
+// use the same file.
+func InstallWithOptions(opts InstallOptions) error {
 	shell := DetectShell()
-	rcPath := RCFilePath(shell)
+	if opts.Shell != "" {
+		if shell = shellFromName(opts.Shell); shell == "" {
+			return fmt.Errorf("unsupported shell %q: use zsh, bash, fish or nu", opts.Shell)
+		}
+	}
+	rcPath := InstalledRCFile(shell)
+	if opts.RCFile != "" {
+		var err error
+		if rcPath, err = expandHome(opts.RCFile); err != nil {
+			return err
+		}
+	}
+	hookPath := HookScriptPath(shell)
 
-	if err := writeHookScript(); err != nil {
+	if !opts.AllowOutsideHome {
+		for _, path := range []string{rcPath, hookPath} {
+			if err := checkInHome(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writeHookScript(shell); err != nil {
 		return fmt.Errorf("writing hook script: %w", err)
 	}
 
-	if err := addToRCFile(rcPath); err != nil {
+	if err := addToRCFile(rcPath, shell); err != nil {
 		return fmt.Errorf("updating %s: %w", rcPath, err)
 	}
 
-	return state.SetShellIntegration(shell)
+	return state.SetShellIntegration(shell, rcPath)
 }
 
+// Remove removes the shell integration from the RC file it was installed
+// in, and deletes the hook script.
 func Remove() error {
-	shell := DetectShell()
-	rcPath := RCFilePath(shell)
+	shell := InstalledShell()
+	rcPath := InstalledRCFile(shell)
 
 	if err := removeFromRCFile(rcPath); err != nil {
 		return fmt.Errorf("updating %s: %w", rcPath, err)
 	}
 
-	hookPath := filepath.Join(state.ConfigDir(), "shell-hook.sh")
-	if err := os.Remove(hookPath); err != nil && !os.IsNotExist(err) {
+	if err := os.Remove(HookScriptPath(shell)); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("removing hook script: %w", err)
 	}
 
+	return state.ClearShellIntegration()
+}
+
+// InstalledShell returns the shell the integration was installed for, or
+// the detected shell if none is recorded.
+func InstalledShell() string {
+	if s, err := state.Load(); err == nil && s.ShellIntegration != "" {
+		return s.ShellIntegration
+	}
+	return DetectShell()
+}
+
+// InstalledRCFile returns the RC file the integration for shell was
+// installed in, or the shell's default RC file if it isn't recorded.
+func InstalledRCFile(shell string) string {
+	if s, err := state.Load(); err == nil && s.ShellIntegration == shell && s.ShellRCFile != "" {
+		return s.ShellRCFile
+	}
+	return RCFilePath(shell)
+}
+
+// expandHome expands a leading ~ in path and makes it absolute.
+func expandHome(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("getting home directory: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return filepath.Abs(path)
+}
+
+// checkInHome returns an error if path is outside the user's home directory.
+func checkInHome(path string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("getting home directory: %w", err)
+	}
+	rel, err := filepath.Rel(home, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside your home directory (%s); use --allow-outside-home to write it anyway", path, home)
+	}
 	return nil
 }
 
+// DetectShell returns the user's shell ("zsh", "bash", "fish" or "nu").
+// $SHELL is checked first; when it is empty or unrecognised (common under
+// system services, Docker, and CI) the parent process's command name from
+// /proc/<ppid>/comm is used. Defaults to zsh.
 func DetectShell() string {
-	shell := os.Getenv("SHELL")
-	if strings.HasSuffix(shell, "zsh") {
+	if shell := shellFromName(os.Getenv("SHELL")); shell != "" {
+		return shell
+	}
+	if shell := shellFromName(proc.GetComm(os.Getppid())); shell != "" {
+		return shell
+	}
+	return "zsh"
+}
+
+// shellFromName maps a shell path or command name to a supported shell,
+// or returns "" if it isn't one. Login shells may be prefixed with "-".
+func shellFromName(name string) string {
+	name = strings.TrimPrefix(filepath.Base(strings.TrimSpace(name)), "-")
+	if strings.HasSuffix(name, "zsh") {
 		return "zsh"
 	}
-	if strings.HasSuffix(shell, "bash") {
+	if strings.HasSuffix(name, "bash") {
 		return "bash"
 	}
-	return "zsh"
+	if strings.HasSuffix(name, "fish") {
+		return "fish"
+	}
+	if name == "nu" || name == "nushell" {
+		return "nu"
+	}
+	return ""
 }
 
+// RCFilePath returns the default file the shell integration is added to.
+// zsh reads its RC file from $ZDOTDIR when set. fish gets a file of its own
+// in conf.d, which fish sources at startup. For nu it is the default
+// $nu.env-path, which is under $XDG_CONFIG_HOME when set and otherwise in
+// the platform's config directory.
 func RCFilePath(shell string) string {
 	home, _ := os.UserHomeDir()
 	switch shell {
+	case "zsh":
+		if zdotdir := os.Getenv("ZDOTDIR"); zdotdir != "" {
+			return filepath.Join(zdotdir, ".zshrc")
+		}
+		return filepath.Join(home, ".zshrc")
 	case "bash":
 		return filepath.Join(home, ".bashrc")
+	case "fish":
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+		return filepath.Join(configHome, "fish", "conf.d", "gongshow.fish")
+	case "nu":
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+			if runtime.GOOS == "darwin" {
+				configHome = filepath.Join(home, "Library", "Application Support")
+			}
+		}
+		return filepath.Join(configHome, "nushell", "env.nu")
 	default:
 		return filepath.Join(home, ".zshrc")
 	}
 }
 
-func writeHookScript() error {
+func writeHookScript(shell string) error {
 	dir := state.ConfigDir()
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	hookPath := filepath.Join(dir, "shell-hook.sh")
-	return os.WriteFile(hookPath, []byte(shellHookScript), 0644)
+	return os.WriteFile(HookScriptPath(shell), []byte(hookScript(shell)), 0644)
+}
+
+// hookScript returns the hook script gt installs for shell.
+func hookScript(shell string) string {
+	switch shell {
+	case "fish":
+		return fishHookScript
+	case "nu":
+		return nuHookScript
+	}
+	return shellHookScript
 }
 
-func addToRCFile(path string) error {
+func addToRCFile(path, shell string) error {
 	data, err := os.ReadFile(path)
 	if err != nil && !os.IsNotExist(err) {
 		return err
@@ -92,10 +260,14 @@ func addToRCFile(path string) error {
 	content := string(data)
 
 	if strings.Contains(content, markerStart) {
-		return updateRCFile(path, content)
+		return updateRCFile(path, content, shell)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
 	}
 
-	block := fmt.Sprintf("\n%s\n%s\n%s\n", markerStart, hookSourceLine(), markerEnd)
+	block := fmt.Sprintf("\n%s\n%s\n%s\n", markerStart, hookSourceLine(shell), markerEnd)
 
 	if len(data) > 0 {
 		backupPath := path + ".gongshow-backup"
@@ -137,15 +309,15 @@ func removeFromRCFile(path string) error {
 	return os.WriteFile(path, []byte(newContent), 0644)
 }
 
-func updateRCFile(path, content string) error {
+func updateRCFile(path, content, shell string) error {
 	startIdx := strings.Index(content, markerStart)
 	endIdx := strings.Index(content[startIdx:], markerEnd)
 	if endIdx == -1 {
-		return fmt.Errorf("malformed GongShow block in %s", path)
+		return fmt.Errorf("malformed GongShow block in %s (run: gt install --shell --repair)", path)
 	}
 	endIdx += startIdx + len(markerEnd)
 
-	block := fmt.Sprintf("%s\n%s\n%s", markerStart, hookSourceLine(), markerEnd)
+	block := fmt.Sprintf("%s\n%s\n%s", markerStart, hookSourceLine(shell), markerEnd)
 	newContent := content[:startIdx] + block + content[endIdx:]
 
 	return os.WriteFile(path, []byte(newContent), 0644)
@@ -154,15 +326,38 @@ func updateRCFile(path, content string) error {
 var shellHookScript = `#!/bin/bash
 # GongShow Shell Integration
 # Installed by: gt install --shell
-# Location: ~/.config/gongshow/shell-hook.sh
+# Location: ${XDG_CONFIG_HOME:-~/.config}/gongshow/shell-hook.sh
 
+# _gongshow_enabled also sets _GONGSHOW_OPT_IN when the hook is in opt-in
+# mode ('gt config set shell.mode opt-in').
 _gongshow_enabled() {
+    _GONGSHOW_OPT_IN=
     [[ -n "$GONGSHOW_DISABLED" ]] && return 1
+    local state_file="${XDG_STATE_HOME:-$HOME/.local/state}/gongshow/state.json"
+    grep -q '"shell_mode":\s*"opt-in"' "$state_file" 2>/dev/null && _GONGSHOW_OPT_IN=1
     [[ -n "$GONGSHOW_ENABLED" ]] && return 0
-    local state_file="$HOME/.local/state/gongshow/state.json"
     [[ -f "$state_file" ]] && grep -q '"enabled":\s*true' "$state_file" 2>/dev/null
 }
 
+# _gongshow_registered succeeds for a repo registered with 'gt rig opt-in'
+# (or below a registered directory) and for repos inside a town.
+_gongshow_registered() {
+    local repo_root="$1" entry
+    local opt_in_file="${XDG_STATE_HOME:-$HOME/.local/state}/gongshow/opt-in-repos"
+    if [[ -f "$opt_in_file" ]]; then
+        while IFS= read -r entry; do
+            [[ -n "$entry" ]] || continue
+            [[ "$repo_root" == "$entry" || "$repo_root" == "$entry"/* ]] && return 0
+        done < "$opt_in_file"
+    fi
+    local dir="$repo_root"
+    while [[ "$dir" != "/" ]]; do
+        [[ -f "$dir/mayor/town.json" ]] && return 0
+        dir="$(dirname "$dir")"
+    done
+    return 1
+}
+
 _gongshow_ignored() {
     local dir="$PWD"
     while [[ "$dir" != "/" ]]; do
@@ -174,13 +369,13 @@ _gongshow_ignored() {
 
 _gongshow_already_asked() {
     local repo_root="$1"
-    local asked_file="$HOME/.cache/gongshow/asked-repos"
+    local asked_file="${XDG_CACHE_HOME:-$HOME/.cache}/gongshow/asked-repos"
     [[ -f "$asked_file" ]] && grep -qF "$repo_root" "$asked_file" 2>/dev/null
 }
 
 _gongshow_mark_asked() {
     local repo_root="$1"
-    local asked_file="$HOME/.cache/gongshow/asked-repos"
+    local asked_file="${XDG_CACHE_HOME:-$HOME/.cache}/gongshow/asked-repos"
     mkdir -p "$(dirname "$asked_file")"
     echo "$repo_root" >> "$asked_file"
 }
@@ -255,24 +450,17 @@ _gongshow_hook() {
         return $previous_exit_status
     }
 
-    local cache_file="$HOME/.cache/gongshow/rigs.cache"
-    if [[ -f "$cache_file" ]]; then
-        local cached
-        cached=$(grep "^${repo_root}:" "$cache_file" 2>/dev/null)
-        if [[ -n "$cached" ]]; then
-            eval "${cached#*:}"
-            return $previous_exit_status
-        fi
+    if [[ -n "$_GONGSHOW_OPT_IN" ]] && ! _gongshow_registered "$repo_root"; then
+        unset GT_TOWN_ROOT GT_RIG _GONGSHOW_OFFER_ADD
+        return $previous_exit_status
     fi
 
+    # gt caches detections and decides when they are stale (gt rig detect
+    # --help), so the hook asks it on every prompt.
     if command -v gt &>/dev/null; then
-        local detect_output
-        detect_output=$(gt rig detect "$repo_root" 2>/dev/null)
-        eval "$detect_output"
-        
-        if [[ -n "$GT_TOWN_ROOT" ]]; then
-            (gt rig detect --cache "$repo_root" &>/dev/null &)
-        elif [[ -n "$_GONGSHOW_OFFER_ADD" ]]; then
+        eval "$(gt rig detect "$repo_root" 2>/dev/null)"
+
+        if [[ -z "$GT_TOWN_ROOT" && -n "$_GONGSHOW_OFFER_ADD" && -z "$_GONGSHOW_OPT_IN" ]]; then
             _gongshow_offer_add "$repo_root"
             unset _GONGSHOW_OFFER_ADD
         fi
@@ -299,5 +487,272 @@ case "${SHELL##*/}" in
         ;;
 esac
 
+# Optional: show the rig, role and hooked bead in your prompt.
+#   bash: PS1='$(gt prompt-segment) '"$PS1"
+#   zsh:  setopt PROMPT_SUBST; PROMPT='$(gt prompt-segment) '"$PROMPT"
+
+_gongshow_hook
+`
+
+// fishHookScript is shellHookScript for fish, which has no PROMPT_COMMAND:
+// a PWD variable handler stands in for chpwd (and may offer to add the
+// repo), a fish_prompt handler for precmd.
+var fishHookScript = `# GongShow Shell Integration (fish)
+# Installed by: gt install --shell
+# Location: ${XDG_CONFIG_HOME:-~/.config}/gongshow/shell-hook.fish
+
+# _gongshow_xdg_dir prints $XDG_<kind>_HOME/gongshow, or its default under
+# $HOME, as gt resolves them.
+function _gongshow_xdg_dir -a kind default
+    set -l var XDG_{$kind}_HOME
+    set -l value $$var
+    if test -n "$value"
+        echo "$value/gongshow"
+    else
+        echo "$HOME/$default/gongshow"
+    end
+end
+
+# _gongshow_enabled also sets _gongshow_opt_in when the hook is in opt-in
+# mode ('gt config set shell.mode opt-in').
+function _gongshow_enabled
+    set -e _gongshow_opt_in
+    test -n "$GONGSHOW_DISABLED"; and return 1
+    set -l state_file (_gongshow_xdg_dir STATE .local/state)/state.json
+    grep -q '"shell_mode":\s*"opt-in"' "$state_file" 2>/dev/null; and set -g _gongshow_opt_in 1
+    test -n "$GONGSHOW_ENABLED"; and return 0
+    test -f "$state_file"; and grep -q '"enabled":\s*true' "$state_file" 2>/dev/null
+end
+
+# _gongshow_registered succeeds for a repo registered with 'gt rig opt-in'
+# (or below a registered directory) and for repos inside a town.
+function _gongshow_registered -a repo_root
+    set -l opt_in_file (_gongshow_xdg_dir STATE .local/state)/opt-in-repos
+    if test -f "$opt_in_file"
+        for entry in (cat "$opt_in_file")
+            test -n "$entry"; or continue
+            if test "$repo_root" = "$entry"; or string match -q -- "$entry/*" "$repo_root"
+                return 0
+            end
+        end
+    end
+    set -l dir "$repo_root"
+    while test "$dir" != "/"
+        test -f "$dir/mayor/town.json"; and return 0
+        set dir (dirname "$dir")
+    end
+    return 1
+end
+
+function _gongshow_ignored
+    set -l dir "$PWD"
+    while test "$dir" != "/"
+        test -f "$dir/.gongshow-ignore"; and return 0
+        set dir (dirname "$dir")
+    end
+    return 1
+end
+
+function _gongshow_already_asked -a repo_root
+    set -l asked_file (_gongshow_xdg_dir CACHE .cache)/asked-repos
+    test -f "$asked_file"; and grep -qF -- "$repo_root" "$asked_file" 2>/dev/null
+end
+
+function _gongshow_mark_asked -a repo_root
+    set -l asked_file (_gongshow_xdg_dir CACHE .cache)/asked-repos
+    mkdir -p (dirname "$asked_file")
+    echo "$repo_root" >> "$asked_file"
+end
+
+function _gongshow_clear
+    set -e GT_TOWN_ROOT
+    set -e GT_RIG
+end
+
+function _gongshow_offer_add -a repo_root
+    _gongshow_already_asked "$repo_root"; and return 0
+
+    isatty stdin; or return 0
+
+    set -l repo_name (basename "$repo_root")
+
+    echo ""
+    read -l -P "Add '$repo_name' to GongShow? [y/N/never] " response </dev/tty
+
+    _gongshow_mark_asked "$repo_root"
+
+    switch "$response"
+        case y Y yes
+            echo "Adding to GongShow..."
+            set -l output (gt rig quick-add "$repo_root" --yes 2>&1)
+            set -l exit_code $status
+            printf '%s\n' $output
+
+            if test $exit_code -eq 0
+                set -l crew_path (string replace -rf '^GT_CREW_PATH=' '' -- $output)
+                if test -n "$crew_path"; and test -d "$crew_path"
+                    echo ""
+                    echo "Switching to crew workspace..."
+                    cd "$crew_path"
+                    # Re-run hook to set GT_TOWN_ROOT and GT_RIG
+                    _gongshow_hook
+                end
+            end
+        case never
+            touch "$repo_root/.gongshow-ignore"
+            echo "Created .gongshow-ignore - won't ask again for this repo."
+        case '*'
+            echo "Skipped. Run 'gt rig quick-add' later to add manually."
+    end
+end
+
+# _gongshow_hook sets GT_TOWN_ROOT and GT_RIG for the current directory.
+# With the argument "offer", it offers to add an unknown repo to GongShow.
+function _gongshow_hook -a offer
+    if not _gongshow_enabled; or _gongshow_ignored
+        _gongshow_clear
+        return
+    end
+
+    if not git rev-parse --git-dir >/dev/null 2>&1
+        _gongshow_clear
+        return
+    end
+
+    set -l repo_root (git rev-parse --show-toplevel 2>/dev/null)
+    if test -z "$repo_root"
+        _gongshow_clear
+        return
+    end
+
+    if set -q _gongshow_opt_in; and not _gongshow_registered "$repo_root"
+        _gongshow_clear
+        return
+    end
+
+    # gt caches detections and decides when they are stale (gt rig detect
+    # --help), so the hook asks it on every prompt.
+    if command -q gt
+        gt rig detect --shell fish "$repo_root" 2>/dev/null | source
+
+        if not set -q GT_TOWN_ROOT; and test "$offer" = offer; and not set -q _gongshow_opt_in
+            _gongshow_offer_add "$repo_root"
+        end
+    end
+end
+
+function _gongshow_chpwd_hook --on-variable PWD
+    _gongshow_hook offer
+end
+
+function _gongshow_prompt_hook --on-event fish_prompt
+    _gongshow_hook
+end
+
+# Optional: show the rig, role and hooked bead in your prompt.
+#   function fish_right_prompt; gt prompt-segment; end
+
+_gongshow_hook
+`
+
+// nuHookScript is the hook for nushell, which can't source the bash hook or
+// eval its exports. An env_change hook on PWD stands in for chpwd; it loads
+// the record 'gt rig detect --format nu' prints. Unlike the other hooks it
+// doesn't offer to add unknown repos.
+var nuHookScript = `# GongShow Shell Integration (nushell)
+# Installed by: gt shell install --shell nu
+# Location: ${XDG_CONFIG_HOME:-~/.config}/gongshow/shell-hook.nu
+
+def _gongshow_state_dir [] {
+    let base = ($env.XDG_STATE_HOME? | default ($env.HOME | path join ".local" "state"))
+    $base | path join "gongshow"
+}
+
+def _gongshow_state [] {
+    let state_file = (_gongshow_state_dir | path join "state.json")
+    if not ($state_file | path exists) {
+        return {}
+    }
+    try { open --raw $state_file | from json } catch { {} }
+}
+
+def _gongshow_enabled [] {
+    if not ($env.GONGSHOW_DISABLED? | default "" | is-empty) {
+        return false
+    }
+    if not ($env.GONGSHOW_ENABLED? | default "" | is-empty) {
+        return true
+    }
+    (_gongshow_state).enabled? | default false
+}
+
+def _gongshow_ignored [] {
+    mut dir = $env.PWD
+    while $dir != "/" {
+        if ($dir | path join ".gongshow-ignore" | path exists) {
+            return true
+        }
+        $dir = ($dir | path dirname)
+    }
+    false
+}
+
+# _gongshow_registered is true for a repo registered with 'gt rig opt-in'
+# (or below a registered directory) and for repos inside a town.
+def _gongshow_registered [repo_root: string] {
+    let opt_in_file = (_gongshow_state_dir | path join "opt-in-repos")
+    if ($opt_in_file | path exists) {
+        let matches = (open --raw $opt_in_file | lines | where {|entry|
+            ($entry | is-empty) == false and ($repo_root == $entry or ($repo_root | str starts-with $"($entry)/"))
+        })
+        if ($matches | is-empty) == false {
+            return true
+        }
+    }
+    mut dir = $repo_root
+    while $dir != "/" {
+        if ($dir | path join "mayor" "town.json" | path exists) {
+            return true
+        }
+        $dir = ($dir | path dirname)
+    }
+    false
+}
+
+# _gongshow_hook sets GT_TOWN_ROOT and GT_RIG for the current directory.
+def --env _gongshow_hook [] {
+    hide-env -i GT_TOWN_ROOT GT_RIG
+
+    if (not (_gongshow_enabled)) or (_gongshow_ignored) {
+        return
+    }
+
+    let git = (^git rev-parse --show-toplevel | complete)
+    if $git.exit_code != 0 {
+        return
+    }
+    let repo_root = ($git.stdout | str trim)
+
+    if ((_gongshow_state).shell_mode? | default "") == "opt-in" and (not (_gongshow_registered $repo_root)) {
+        return
+    }
+
+    if (which gt | is-empty) {
+        return
+    }
+    let detect = (^gt rig detect --format nu $repo_root | complete)
+    if $detect.exit_code == 0 {
+        load-env ($detect.stdout | from json)
+    }
+}
+
+$env.config = ($env.config? | default {})
+$env.config.hooks = ($env.config.hooks? | default {})
+$env.config.hooks.env_change = ($env.config.hooks.env_change? | default {})
+$env.config.hooks.env_change.PWD = ($env.config.hooks.env_change.PWD? | default [] | append {|before, after| _gongshow_hook })
+
+# Optional: show the rig, role and hooked bead in your prompt.
+#   $env.PROMPT_COMMAND_RIGHT = {|| gt prompt-segment }
+
 _gongshow_hook
 `
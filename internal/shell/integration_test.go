@@ -4,10 +4,15 @@
 package shell
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/state"
 )
 
 func TestDetectShell(t *testing.T) {
@@ -19,6 +24,7 @@ func TestDetectShell(t *testing.T) {
 		{"/usr/bin/zsh", "zsh"},
 		{"/bin/bash", "bash"},
 		{"/usr/bin/bash", "bash"},
+		{"/usr/bin/fish", "fish"},
 		{"", "zsh"},
 	}
 
@@ -36,6 +42,57 @@ func TestDetectShell(t *testing.T) {
 	}
 }
 
+func TestShellFromName(t *testing.T) {
+	tests := map[string]string{
+		"zsh":           "zsh",
+		"-zsh":          "zsh",
+		"bash":          "bash",
+		"-bash":         "bash",
+		"/usr/bin/bash": "bash",
+		"fish":          "fish",
+		"-fish":         "fish",
+		"nu":            "nu",
+		"/usr/bin/nu":   "nu",
+		"nushell":       "nu",
+		"menu":          "",
+		"sh":            "",
+		"":              "",
+	}
+	for name, want := range tests {
+		if got := shellFromName(name); got != want {
+			t.Errorf("shellFromName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestDetectShell_ParentComm runs this test binary under bash with $SHELL
+// unset, so DetectShell must fall back to the parent's /proc/<ppid>/comm.
+func TestDetectShell_ParentComm(t *testing.T) {
+	if os.Getenv("GT_TEST_DETECT_SHELL_CHILD") == "1" {
+		os.Stdout.WriteString(DetectShell())
+		return
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc/<pid>/comm is Linux-only")
+	}
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+
+	// The trailing "; true" keeps bash from exec'ing the test binary
+	// directly, so bash stays its parent.
+	cmd := exec.Command(bash, "-c", `"$0" -test.run='^TestDetectShell_ParentComm$'; true`, os.Args[0])
+	cmd.Env = append(os.Environ(), "SHELL=", "GT_TEST_DETECT_SHELL_CHILD=1")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running child: %v", err)
+	}
+	if got := strings.TrimSpace(strings.Split(string(out), "\n")[0]); !strings.HasPrefix(got, "bash") {
+		t.Errorf("DetectShell() with $SHELL=\"\" under bash = %q, want bash", got)
+	}
+}
+
 func TestRCFilePath(t *testing.T) {
 	home, _ := os.UserHomeDir()
 
@@ -45,8 +102,17 @@ func TestRCFilePath(t *testing.T) {
 	}{
 		{"zsh", filepath.Join(home, ".zshrc")},
 		{"bash", filepath.Join(home, ".bashrc")},
+		{"fish", filepath.Join(home, ".config", "fish", "conf.d", "gongshow.fish")},
+	}
+	if runtime.GOOS != "darwin" {
+		tests = append(tests, struct {
+			shell string
+			want  string
+		}{"nu", filepath.Join(home, ".config", "nushell", "env.nu")})
 	}
 
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("ZDOTDIR", "")
 	for _, tt := range tests {
 		t.Run(tt.shell, func(t *testing.T) {
 			got := RCFilePath(tt.shell)
@@ -55,6 +121,11 @@ func TestRCFilePath(t *testing.T) {
 			}
 		})
 	}
+
+	t.Setenv("ZDOTDIR", "/home/me/.config/zsh")
+	if got, want := RCFilePath("zsh"), "/home/me/.config/zsh/.zshrc"; got != want {
+		t.Errorf("RCFilePath(zsh) with ZDOTDIR = %q, want %q", got, want)
+	}
 }
 
 func TestAddRemoveFromRCFile(t *testing.T) {
@@ -66,7 +137,7 @@ func TestAddRemoveFromRCFile(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := addToRCFile(rcPath); err != nil {
+	if err := addToRCFile(rcPath, "zsh"); err != nil {
 		t.Fatalf("addToRCFile() error = %v", err)
 	}
 
@@ -110,15 +181,80 @@ func TestAddRemoveFromRCFile(t *testing.T) {
 	}
 }
 
+// TestAddRemoveNuEnvFile checks the block goes into an env.nu and comes out
+// again leaving the file exactly as it was.
+func TestAddRemoveNuEnvFile(t *testing.T) {
+	rcPath := filepath.Join(t.TempDir(), "env.nu")
+	original := "$env.EDITOR = \"hx\"\n$env.PATH = ($env.PATH | prepend \"~/bin\")\n"
+	if err := os.WriteFile(rcPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := addToRCFile(rcPath, "nu"); err != nil {
+		t.Fatalf("addToRCFile() error = %v", err)
+	}
+	if err := addToRCFile(rcPath, "nu"); err != nil {
+		t.Fatalf("second addToRCFile() error = %v", err)
+	}
+	data, _ := os.ReadFile(rcPath)
+	content := string(data)
+	if n := strings.Count(content, markerStart); n != 1 {
+		t.Errorf("env.nu has %d GongShow blocks, want 1:\n%s", n, content)
+	}
+	if want := `source "` + HookScriptPath("nu") + `"`; !strings.Contains(content, want) {
+		t.Errorf("env.nu missing %q:\n%s", want, content)
+	}
+	if strings.Contains(content, "[[") || strings.Contains(content, "test -f") {
+		t.Errorf("env.nu contains another shell's syntax:\n%s", content)
+	}
+
+	if err := removeFromRCFile(rcPath); err != nil {
+		t.Fatalf("removeFromRCFile() error = %v", err)
+	}
+	data, _ = os.ReadFile(rcPath)
+	if string(data) != original {
+		t.Errorf("env.nu after removal = %q, want %q", data, original)
+	}
+}
+
+func TestInstallRemoveNu(t *testing.T) {
+	home := setupShellHome(t, "bash")
+	rcPath := filepath.Join(home, ".config", "nushell", "env.nu")
+
+	if err := InstallWithOptions(InstallOptions{Shell: "nu"}); err != nil {
+		t.Fatalf("InstallWithOptions(nu) error = %v", err)
+	}
+	assertInstalled(t, rcPath, true)
+	if _, err := os.Stat(HookScriptPath("nu")); err != nil {
+		t.Errorf("nu hook script not written: %v", err)
+	}
+	if got := InstalledShell(); got != "nu" {
+		t.Errorf("InstalledShell() = %q, want nu", got)
+	}
+
+	// Remove uses the recorded shell, not $SHELL.
+	if err := Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	assertInstalled(t, rcPath, false)
+	if _, err := os.Stat(HookScriptPath("nu")); !os.IsNotExist(err) {
+		t.Errorf("nu hook script not removed (err = %v)", err)
+	}
+
+	if err := InstallWithOptions(InstallOptions{Shell: "tcsh"}); err == nil {
+		t.Error("InstallWithOptions accepted an unsupported shell")
+	}
+}
+
 func TestUpdateRCFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	rcPath := filepath.Join(tmpDir, ".zshrc")
 
-	if err := addToRCFile(rcPath); err != nil {
+	if err := addToRCFile(rcPath, "zsh"); err != nil {
 		t.Fatalf("initial addToRCFile() error = %v", err)
 	}
 
-	if err := addToRCFile(rcPath); err != nil {
+	if err := addToRCFile(rcPath, "zsh"); err != nil {
 		t.Fatalf("second addToRCFile() error = %v", err)
 	}
 
@@ -130,3 +266,383 @@ func TestUpdateRCFile(t *testing.T) {
 		t.Errorf("RC file has %d start markers, want 1", startCount)
 	}
 }
+
+// setupShellHome points $HOME and the XDG directories at a temp dir, unsets
+// ZDOTDIR and makes shell the detected shell. Returns the home directory.
+func setupShellHome(t *testing.T, shell string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, ".local", "state"))
+	t.Setenv("ZDOTDIR", "")
+	t.Setenv("SHELL", "/usr/bin/"+shell)
+	return home
+}
+
+// setupFishHome is setupShellHome for fish. Returns the fish config
+// directory.
+func setupFishHome(t *testing.T) string {
+	t.Helper()
+	return filepath.Join(setupShellHome(t, "fish"), ".config", "fish")
+}
+
+// assertInstalled checks that rcPath sources the hook script exactly once,
+// or not at all if want is false.
+func assertInstalled(t *testing.T, rcPath string, want bool) {
+	t.Helper()
+	data, _ := os.ReadFile(rcPath)
+	if n := strings.Count(string(data), markerStart); (n == 1) != want || n > 1 {
+		t.Errorf("%s has %d GongShow blocks, want installed = %v:\n%s", rcPath, n, want, data)
+	}
+}
+
+func TestInstallRemoveZDOTDIR(t *testing.T) {
+	home := setupShellHome(t, "zsh")
+	zdotdir := filepath.Join(home, ".config", "zsh")
+	t.Setenv("ZDOTDIR", zdotdir)
+	rcPath := filepath.Join(zdotdir, ".zshrc")
+
+	if err := Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	assertInstalled(t, rcPath, true)
+	if _, err := os.Stat(filepath.Join(home, ".zshrc")); !os.IsNotExist(err) {
+		t.Errorf("~/.zshrc written with ZDOTDIR set (err = %v)", err)
+	}
+
+	// Remove uses the recorded file, even if ZDOTDIR is gone by then.
+	t.Setenv("ZDOTDIR", "")
+	if err := Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	assertInstalled(t, rcPath, false)
+}
+
+func TestInstallRemoveXDGDirs(t *testing.T) {
+	home := setupShellHome(t, "bash")
+	configHome := filepath.Join(home, "xdg", "config")
+	stateHome := filepath.Join(home, "xdg", "state")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_STATE_HOME", stateHome)
+
+	if err := Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	hookPath := filepath.Join(configHome, "gongshow", "shell-hook.sh")
+	if HookScriptPath("bash") != hookPath {
+		t.Errorf("HookScriptPath = %q, want %q", HookScriptPath("bash"), hookPath)
+	}
+	if _, err := os.Stat(hookPath); err != nil {
+		t.Errorf("hook script not written under XDG_CONFIG_HOME: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(stateHome, "gongshow", "state.json")); err != nil {
+		t.Errorf("state not written under XDG_STATE_HOME: %v", err)
+	}
+	assertInstalled(t, filepath.Join(home, ".bashrc"), true)
+
+	if err := Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	assertInstalled(t, filepath.Join(home, ".bashrc"), false)
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Errorf("hook script not removed (err = %v)", err)
+	}
+}
+
+func TestInstallRemoveExplicitRCFile(t *testing.T) {
+	home := setupShellHome(t, "bash")
+	rcPath := filepath.Join(home, ".config", "bash", "rc")
+
+	if err := InstallWithOptions(InstallOptions{RCFile: "~/.config/bash/rc"}); err != nil {
+		t.Fatalf("InstallWithOptions() error = %v", err)
+	}
+	assertInstalled(t, rcPath, true)
+	if got := InstalledRCFile("bash"); got != rcPath {
+		t.Errorf("InstalledRCFile = %q, want %q", got, rcPath)
+	}
+
+	// A later plain install updates the same file.
+	if err := Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	assertInstalled(t, rcPath, true)
+	if _, err := os.Stat(filepath.Join(home, ".bashrc")); !os.IsNotExist(err) {
+		t.Errorf("~/.bashrc written with --rc-file (err = %v)", err)
+	}
+
+	if err := Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	assertInstalled(t, rcPath, false)
+	if got := InstalledRCFile("bash"); got != filepath.Join(home, ".bashrc") {
+		t.Errorf("InstalledRCFile after Remove = %q, want the default", got)
+	}
+}
+
+func TestInstallOutsideHomeNeedsAllow(t *testing.T) {
+	setupShellHome(t, "bash")
+	rcPath := filepath.Join(t.TempDir(), "rc")
+
+	err := InstallWithOptions(InstallOptions{RCFile: rcPath})
+	if err == nil || !strings.Contains(err.Error(), "--allow-outside-home") {
+		t.Fatalf("InstallWithOptions outside home error = %v, want an --allow-outside-home hint", err)
+	}
+	if _, err := os.Stat(rcPath); !os.IsNotExist(err) {
+		t.Errorf("RC file written without AllowOutsideHome (err = %v)", err)
+	}
+	if _, err := os.Stat(HookScriptPath("bash")); !os.IsNotExist(err) {
+		t.Errorf("hook script written without AllowOutsideHome (err = %v)", err)
+	}
+
+	if err := InstallWithOptions(InstallOptions{RCFile: rcPath, AllowOutsideHome: true}); err != nil {
+		t.Fatalf("InstallWithOptions with AllowOutsideHome error = %v", err)
+	}
+	assertInstalled(t, rcPath, true)
+	if err := Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	assertInstalled(t, rcPath, false)
+}
+
+// TestHookScriptXDGState checks the bash hook finds the state file under
+// XDG_STATE_HOME, as gt writes it.
+func TestHookScriptXDGState(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+	home := setupShellHome(t, "bash")
+	stateHome := filepath.Join(home, "xdg-state")
+	t.Setenv("XDG_STATE_HOME", stateHome)
+	t.Setenv("GONGSHOW_ENABLED", "")
+	t.Setenv("GONGSHOW_DISABLED", "")
+	if err := state.Enable("test"); err != nil {
+		t.Fatal(err)
+	}
+	hook := filepath.Join(home, "hook.sh")
+	if err := os.WriteFile(hook, []byte(shellHookScript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bash, "-c", `source "$0" >/dev/null 2>&1; _gongshow_enabled && echo enabled`, hook)
+	cmd.Dir = home
+	out, _ := cmd.Output()
+	if strings.TrimSpace(string(out)) != "enabled" {
+		t.Errorf("_gongshow_enabled with XDG_STATE_HOME = %q, want enabled", out)
+	}
+}
+
+// TestHookScriptOptInMode checks which repos the bash hook runs gt in, in
+// the default and opt-in modes.
+func TestHookScriptOptInMode(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+	git, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git not installed")
+	}
+	home := setupShellHome(t, "bash")
+	t.Setenv("GONGSHOW_ENABLED", "")
+	t.Setenv("GONGSHOW_DISABLED", "")
+	if err := state.Enable("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stand-in gt that detects every repo as rig "r".
+	binDir := filepath.Join(home, "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	fakeGT := "#!/bin/sh\necho 'export GT_TOWN_ROOT=\"/town\"'\necho 'export GT_RIG=\"r\"'\n"
+	if err := os.WriteFile(filepath.Join(binDir, "gt"), []byte(fakeGT), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	hook := filepath.Join(home, "hook.sh")
+	if err := os.WriteFile(hook, []byte(shellHookScript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	newRepo := func(path string) string {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if out, err := exec.Command(git, "init", "-q", path).CombinedOutput(); err != nil {
+			t.Fatalf("git init: %v\n%s", err, out)
+		}
+		return path
+	}
+	registered := newRepo(filepath.Join(home, "src", "registered"))
+	unregistered := newRepo(filepath.Join(home, "other", "unregistered"))
+	town := filepath.Join(home, "gt")
+	inTown := newRepo(filepath.Join(town, "myrig", "crew", "max"))
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rigIn := func(dir string) string {
+		t.Helper()
+		cmd := exec.Command(bash, "-c", `source "$0" >/dev/null 2>&1; echo "$GT_RIG"`, hook)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("running hook in %s: %v", dir, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	// Default mode: the hook runs everywhere.
+	for _, dir := range []string{registered, unregistered, inTown} {
+		if got := rigIn(dir); got != "r" {
+			t.Errorf("auto mode: GT_RIG in %s = %q, want r", dir, got)
+		}
+	}
+
+	if err := state.SetShellMode(state.ShellModeOptIn); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := state.AddOptInRepo(filepath.Join(home, "src")); err != nil {
+		t.Fatal(err)
+	}
+	for dir, want := range map[string]string{registered: "r", unregistered: "", inTown: "r"} {
+		if got := rigIn(dir); got != want {
+			t.Errorf("opt-in mode: GT_RIG in %s = %q, want %q", dir, got, want)
+		}
+	}
+
+	// Switching back restores the default.
+	if err := state.SetShellMode(state.ShellModeAuto); err != nil {
+		t.Fatal(err)
+	}
+	if got := rigIn(unregistered); got != "r" {
+		t.Errorf("back in auto mode: GT_RIG in %s = %q, want r", unregistered, got)
+	}
+}
+
+func TestInstallRemoveFish(t *testing.T) {
+	fishDir := setupFishHome(t)
+	rcPath := filepath.Join(fishDir, "conf.d", "gongshow.fish")
+	if got := RCFilePath(DetectShell()); got != rcPath {
+		t.Fatalf("RCFilePath = %q, want %q", got, rcPath)
+	}
+
+	if err := Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	data, err := os.ReadFile(rcPath)
+	if err != nil {
+		t.Fatalf("reading fish config: %v", err)
+	}
+	content := string(data)
+	if !strings.Contains(content, markerStart) || !strings.Contains(content, markerEnd) {
+		t.Errorf("fish config missing markers:\n%s", content)
+	}
+	if want := hookSourceLine("fish"); !strings.Contains(content, want) {
+		t.Errorf("fish config missing %q:\n%s", want, content)
+	}
+	if strings.Contains(content, "[[") {
+		t.Errorf("fish config contains bash syntax:\n%s", content)
+	}
+	if _, err := os.Stat(HookScriptPath("fish")); err != nil {
+		t.Errorf("fish hook script not written: %v", err)
+	}
+	if _, err := os.Stat(HookScriptPath("bash")); !os.IsNotExist(err) {
+		t.Errorf("bash hook script written for fish (err = %v)", err)
+	}
+	s, err := state.Load()
+	if err != nil {
+		t.Fatalf("state.Load: %v", err)
+	}
+	if s.ShellIntegration != "fish" {
+		t.Errorf("ShellIntegration = %q, want fish", s.ShellIntegration)
+	}
+
+	if err := Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	data, _ = os.ReadFile(rcPath)
+	if strings.Contains(string(data), markerStart) {
+		t.Errorf("fish config still has the block after Remove:\n%s", data)
+	}
+	if _, err := os.Stat(HookScriptPath("fish")); !os.IsNotExist(err) {
+		t.Errorf("fish hook script not removed (err = %v)", err)
+	}
+}
+
+func TestInstallFishBacksUpExistingConfig(t *testing.T) {
+	fishDir := setupFishHome(t)
+	rcPath := filepath.Join(fishDir, "conf.d", "gongshow.fish")
+	if err := os.MkdirAll(filepath.Dir(rcPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	original := "set -gx EDITOR vim\n"
+	if err := os.WriteFile(rcPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	backup, err := os.ReadFile(rcPath + ".gongshow-backup")
+	if err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v; want %q", backup, err, original)
+	}
+
+	// A second install updates the block in place.
+	if err := Install(); err != nil {
+		t.Fatalf("second Install() error = %v", err)
+	}
+	data, _ := os.ReadFile(rcPath)
+	if n := strings.Count(string(data), markerStart); n != 1 {
+		t.Errorf("fish config has %d blocks, want 1", n)
+	}
+
+	if err := Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	data, _ = os.ReadFile(rcPath)
+	if string(data) != original {
+		t.Errorf("fish config after Remove = %q, want %q", data, original)
+	}
+}
+
+// TestHookScriptSyntax checks the generated hook scripts parse, for each
+// shell that is installed: bash and fish with -n, nu by running nu-check
+// on the script through --commands.
+func TestHookScriptSyntax(t *testing.T) {
+	tests := []struct {
+		shell  string
+		script string
+		args   func(path string) []string
+	}{
+		{"bash", shellHookScript, func(path string) []string { return []string{"-n", path} }},
+		{"fish", fishHookScript, func(path string) []string { return []string{"-n", path} }},
+		{"nu", nuHookScript, func(path string) []string {
+			// --debug makes nu-check fail with the parse error instead
+			// of printing false.
+			return []string{"--no-config-file", "--commands", fmt.Sprintf("nu-check --debug %q", path)}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			bin, err := exec.LookPath(tt.shell)
+			if err != nil {
+				t.Skipf("%s not installed", tt.shell)
+			}
+			path := filepath.Join(t.TempDir(), "hook")
+			if err := os.WriteFile(path, []byte(tt.script), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if out, err := exec.Command(bin, tt.args(path)...).CombinedOutput(); err != nil {
+				t.Errorf("%s %s: %v\n%s", tt.shell, strings.Join(tt.args(path), " "), err, out)
+			}
+		})
+	}
+}
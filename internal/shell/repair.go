@@ -0,0 +1,223 @@
+// ABOUTME: Drift detection and repair for the shell integration.
+// ABOUTME: Verifies the RC file block, hook script and state, and reinstalls cleanly.
+
+package shell
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/state"
+)
+
+// BlockState describes the GongShow block in the RC file.
+type BlockState string
+
+const (
+	BlockAbsent    BlockState = "absent"
+	BlockPresent   BlockState = "present"
+	BlockMalformed BlockState = "malformed" // A marker without its pair, or more than one block
+)
+
+// HookState describes the installed hook script.
+type HookState string
+
+const (
+	HookMissing HookState = "missing"
+	HookStale   HookState = "stale" // Differs from the script this gt installs
+	HookCurrent HookState = "current"
+)
+
+// IntegrationStatus is what Verify found.
+type IntegrationStatus struct {
+	Shell      string
+	RCFile     string
+	Block      BlockState
+	Blocks     int // Complete blocks (start and end marker) in the RC file
+	HookScript string
+	Hook       HookState
+	Recorded   bool     // The state records the integration as installed
+	Problems   []string // Empty when the integration works or isn't installed
+}
+
+// Installed reports whether any part of the integration is in place.
+func (s *IntegrationStatus) Installed() bool {
+	return s.Block != BlockAbsent || s.Recorded
+}
+
+// Verify checks the shell integration for the installed shell: whether its
+// RC file holds exactly one well-formed block, whether the hook script is
+// the one this gt installs, and whether the state agrees with both. A stale
+// hook script, as left by upgrading gt, still works and isn't a problem.
+func Verify() (*IntegrationStatus, error) {
+	shell := InstalledShell()
+	status := &IntegrationStatus{
+		Shell:      shell,
+		RCFile:     InstalledRCFile(shell),
+		HookScript: HookScriptPath(shell),
+	}
+	if s, err := state.Load(); err == nil && s.ShellIntegration != "" {
+		status.Recorded = true
+	}
+
+	data, err := os.ReadFile(status.RCFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", status.RCFile, err)
+	}
+	complete, partial := scanRCBlocks(string(data))
+	status.Blocks = complete
+	switch {
+	case complete == 0 && partial == 0:
+		status.Block = BlockAbsent
+	case complete == 1 && partial == 0:
+		status.Block = BlockPresent
+	default:
+		status.Block = BlockMalformed
+	}
+
+	hook, err := os.ReadFile(status.HookScript)
+	switch {
+	case os.IsNotExist(err):
+		status.Hook = HookMissing
+	case err != nil:
+		return nil, fmt.Errorf("reading hook script: %w", err)
+	case string(hook) != hookScript(shell):
+		status.Hook = HookStale
+	default:
+		status.Hook = HookCurrent
+	}
+
+	if partial > 0 {
+		status.Problems = append(status.Problems, fmt.Sprintf("%s has a GongShow block with a missing start or end marker", status.RCFile))
+	}
+	if complete > 1 {
+		status.Problems = append(status.Problems, fmt.Sprintf("%s has %d GongShow blocks", status.RCFile, complete))
+	}
+	if status.Block == BlockAbsent && status.Recorded {
+		status.Problems = append(status.Problems, fmt.Sprintf("state records shell integration but %s has no GongShow block", status.RCFile))
+	}
+	if status.Block != BlockAbsent && !status.Recorded {
+		status.Problems = append(status.Problems, fmt.Sprintf("%s has a GongShow block but the state doesn't record it", status.RCFile))
+	}
+	if status.Installed() && status.Hook == HookMissing {
+		status.Problems = append(status.Problems, "hook script missing: "+status.HookScript)
+	}
+	return status, nil
+}
+
+// Repair removes every GongShow block from the installed shell's RC file,
+// including partial and duplicated ones, and reinstalls the integration, so
+// the file ends up with exactly one block and a current hook script. The RC
+// file is first copied to a timestamped backup, whose path is returned ("" if
+// there was nothing to back up).
+func Repair() (string, error) {
+	shell := InstalledShell()
+	rcPath := InstalledRCFile(shell)
+
+	data, err := os.ReadFile(rcPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("reading %s: %w", rcPath, err)
+	}
+
+	var backupPath string
+	if len(data) > 0 {
+		backupPath = fmt.Sprintf("%s.gongshow-backup-%s", rcPath, time.Now().Format("20060102-150405"))
+		if err := os.WriteFile(backupPath, data, 0644); err != nil {
+			return "", fmt.Errorf("writing backup: %w", err)
+		}
+		if stripped := stripRCBlocks(string(data)); stripped != string(data) {
+			if err := os.WriteFile(rcPath, []byte(stripped), 0644); err != nil {
+				return backupPath, fmt.Errorf("updating %s: %w", rcPath, err)
+			}
+		}
+	}
+
+	// The RC file is the recorded one, which was already allowed when it
+	// was installed.
+	opts := InstallOptions{Shell: shell, RCFile: rcPath, AllowOutsideHome: true}
+	if err := InstallWithOptions(opts); err != nil {
+		return backupPath, err
+	}
+	return backupPath, nil
+}
+
+// scanRCBlocks counts the complete GongShow blocks in content, and the
+// partial ones: a start marker with no end marker before the next start
+// marker or the end of the file, or an end marker with no start.
+func scanRCBlocks(content string) (complete, partial int) {
+	inBlock := false
+	for _, line := range strings.Split(content, "\n") {
+		switch strings.TrimSpace(line) {
+		case markerStart:
+			if inBlock {
+				partial++
+			}
+			inBlock = true
+		case markerEnd:
+			if inBlock {
+				complete++
+			} else {
+				partial++
+			}
+			inBlock = false
+		}
+	}
+	if inBlock {
+		partial++
+	}
+	return complete, partial
+}
+
+// stripRCBlocks removes every GongShow block from content, along with the
+// blank line addToRCFile puts before one. A start marker without an end
+// marker takes only the hook source lines right after it with it, and an
+// end marker without a start only those right before it, so a mangled
+// block never costs the user lines of their own.
+func stripRCBlocks(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+	var out []string
+	for i := 0; i < len(lines); i++ {
+		switch strings.TrimSpace(lines[i]) {
+		case markerStart:
+			i = blockEnd(lines, i)
+		case markerEnd:
+			for len(out) > 0 && isHookSourceLine(out[len(out)-1]) {
+				out = out[:len(out)-1]
+			}
+		default:
+			out = append(out, lines[i])
+			continue
+		}
+		if len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+			out = out[:len(out)-1]
+		}
+	}
+	return strings.Join(out, "")
+}
+
+// blockEnd returns the index of the last line of the block whose start
+// marker is lines[start]: its end marker, or if it has none before the next
+// start marker, the last hook source line following the start marker.
+func blockEnd(lines []string, start int) int {
+scan:
+	for j := start + 1; j < len(lines); j++ {
+		switch strings.TrimSpace(lines[j]) {
+		case markerEnd:
+			return j
+		case markerStart:
+			break scan
+		}
+	}
+	end := start
+	for end+1 < len(lines) && isHookSourceLine(lines[end+1]) {
+		end++
+	}
+	return end
+}
+
+// isHookSourceLine reports whether line is a line hookSourceLine wrote.
+func isHookSourceLine(line string) bool {
+	return strings.Contains(line, "source") && strings.Contains(line, "shell-hook.")
+}
@@ -0,0 +1,159 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/KeithWyatt/gongshow/internal/state"
+)
+
+// rcBlock is the block addToRCFile writes for zsh.
+func rcBlock() string {
+	return markerStart + "\n" + hookSourceLine("zsh") + "\n" + markerEnd + "\n"
+}
+
+func TestVerifyAndRepair(t *testing.T) {
+	source := hookSourceLine("zsh") + "\n"
+	tests := []struct {
+		name      string
+		rc        string
+		wantBlock BlockState
+	}{
+		{
+			name:      "start without end",
+			rc:        "export A=1\n\n" + markerStart + "\n" + source + "alias ll='ls -l'\n",
+			wantBlock: BlockMalformed,
+		},
+		{
+			name:      "end without start",
+			rc:        "export A=1\n\n" + source + markerEnd + "\nalias ll='ls -l'\n",
+			wantBlock: BlockMalformed,
+		},
+		{
+			name:      "duplicated blocks",
+			rc:        "export A=1\n\n" + rcBlock() + "alias ll='ls -l'\n\n" + rcBlock() + "\n" + rcBlock(),
+			wantBlock: BlockMalformed,
+		},
+		{
+			name:      "start without end before a block",
+			rc:        "export A=1\n\n" + markerStart + "\n" + source + "\n" + rcBlock() + "alias ll='ls -l'\n",
+			wantBlock: BlockMalformed,
+		},
+		{
+			name:      "block without state",
+			rc:        "export A=1\n\n" + rcBlock() + "alias ll='ls -l'\n",
+			wantBlock: BlockPresent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := setupShellHome(t, "zsh")
+			rcPath := filepath.Join(home, ".zshrc")
+			if err := os.WriteFile(rcPath, []byte(tt.rc), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			status, err := Verify()
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if status.Block != tt.wantBlock {
+				t.Errorf("Block = %s, want %s", status.Block, tt.wantBlock)
+			}
+			if status.Hook != HookMissing || len(status.Problems) == 0 {
+				t.Errorf("Verify() = %+v, want problems and a missing hook", status)
+			}
+
+			backup, err := Repair()
+			if err != nil {
+				t.Fatalf("Repair() error = %v", err)
+			}
+			if data, _ := os.ReadFile(backup); string(data) != tt.rc {
+				t.Errorf("backup %s = %q, want the original RC file", backup, data)
+			}
+
+			data, _ := os.ReadFile(rcPath)
+			want := "export A=1\nalias ll='ls -l'\n\n" + rcBlock()
+			if string(data) != want {
+				t.Errorf("repaired RC file =\n%s\nwant\n%s", data, want)
+			}
+
+			status, err = Verify()
+			if err != nil {
+				t.Fatalf("Verify() after Repair error = %v", err)
+			}
+			if status.Block != BlockPresent || status.Blocks != 1 || status.Hook != HookCurrent || !status.Recorded || len(status.Problems) != 0 {
+				t.Errorf("Verify() after Repair = %+v, want one block, a current hook and no problems", status)
+			}
+		})
+	}
+}
+
+func TestMalformedBlockPointsAtRepair(t *testing.T) {
+	rcPath := filepath.Join(t.TempDir(), ".zshrc")
+	rc := "export A=1\n\n" + markerStart + "\n" + hookSourceLine("zsh") + "\n"
+	if err := os.WriteFile(rcPath, []byte(rc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := addToRCFile(rcPath, "zsh"); err == nil || !strings.Contains(err.Error(), "gt install --shell --repair") {
+		t.Errorf("addToRCFile() error = %v, want one pointing at --repair", err)
+	}
+}
+
+func TestVerifyHookScript(t *testing.T) {
+	setupShellHome(t, "zsh")
+	if err := Install(); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+
+	status, err := Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if status.Block != BlockPresent || status.Hook != HookCurrent || len(status.Problems) != 0 {
+		t.Errorf("Verify() after Install = %+v, want healthy", status)
+	}
+
+	if err := os.WriteFile(status.HookScript, []byte("# old hook\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ = Verify(); status.Hook != HookStale || len(status.Problems) != 0 {
+		t.Errorf("Verify() with an old hook = %+v, want stale and no problems", status)
+	}
+
+	if err := os.Remove(status.HookScript); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ = Verify(); status.Hook != HookMissing || len(status.Problems) != 1 {
+		t.Errorf("Verify() without the hook = %+v, want it missing", status)
+	}
+}
+
+func TestVerifyRecordedButAbsent(t *testing.T) {
+	home := setupShellHome(t, "zsh")
+	if err := state.SetShellIntegration("zsh", filepath.Join(home, ".zshrc")); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if status.Block != BlockAbsent || !status.Installed() || len(status.Problems) == 0 {
+		t.Errorf("Verify() = %+v, want the missing block reported", status)
+	}
+}
+
+func TestVerifyNotInstalled(t *testing.T) {
+	setupShellHome(t, "zsh")
+	status, err := Verify()
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if status.Installed() || len(status.Problems) != 0 {
+		t.Errorf("Verify() = %+v, want not installed and no problems", status)
+	}
+}
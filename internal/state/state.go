@@ -4,9 +4,12 @@
 package state
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,9 +23,22 @@ type State struct {
 	InstalledAt      time.Time `json:"installed_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
 	ShellIntegration string    `json:"shell_integration,omitempty"`
+	ShellRCFile      string    `json:"shell_rc_file,omitempty"` // empty means the shell's default RC file
+	ShellMode        string    `json:"shell_mode,omitempty"`    // "" means ShellModeAuto
 	LastDoctorRun    time.Time `json:"last_doctor_run,omitempty"`
 }
 
+// Shell hook modes.
+const (
+	// ShellModeAuto activates the hook in every git repo, offering to add
+	// repos that aren't in GongShow.
+	ShellModeAuto = "auto"
+
+	// ShellModeOptIn activates the hook only in registered repos (see
+	// OptInReposPath) and never prompts.
+	ShellModeOptIn = "opt-in"
+)
+
 // StateDir returns the XDG-compliant state directory.
 // Uses ~/.local/state/gongshow/ (per XDG Base Directory Specification).
 func StateDir() string {
@@ -164,8 +180,9 @@ func GetMachineID() string {
 	return s.MachineID
 }
 
-// SetShellIntegration records which shell integration is installed.
-func SetShellIntegration(shell string) error {
+// SetShellIntegration records which shell integration is installed, and
+// the RC file it was added to if that isn't the shell's default.
+func SetShellIntegration(shell, rcFile string) error {
 	s, err := Load()
 	if err != nil {
 		s = &State{
@@ -174,6 +191,19 @@ func SetShellIntegration(shell string) error {
 		}
 	}
 	s.ShellIntegration = shell
+	s.ShellRCFile = rcFile
+	return Save(s)
+}
+
+// ClearShellIntegration records that no shell integration is installed.
+// It does nothing if there is no state.
+func ClearShellIntegration() error {
+	s, err := Load()
+	if err != nil {
+		return nil
+	}
+	s.ShellIntegration = ""
+	s.ShellRCFile = ""
 	return Save(s)
 }
 
@@ -186,3 +216,129 @@ func RecordDoctorRun() error {
 	s.LastDoctorRun = time.Now()
 	return Save(s)
 }
+
+// Mode returns the shell hook mode, ShellModeAuto if none is set.
+func (s *State) Mode() string {
+	if s.ShellMode == "" {
+		return ShellModeAuto
+	}
+	return s.ShellMode
+}
+
+// GetShellMode returns the shell hook mode, ShellModeAuto if there is no
+// state.
+func GetShellMode() string {
+	s, err := Load()
+	if err != nil {
+		return ShellModeAuto
+	}
+	return s.Mode()
+}
+
+// SetShellMode sets the shell hook mode, ShellModeAuto or ShellModeOptIn.
+func SetShellMode(mode string) error {
+	if mode != ShellModeAuto && mode != ShellModeOptIn {
+		return fmt.Errorf("invalid shell mode %q: use %s or %s", mode, ShellModeAuto, ShellModeOptIn)
+	}
+	s, err := Load()
+	if err != nil {
+		s = &State{
+			InstalledAt: time.Now(),
+			MachineID:   generateMachineID(),
+		}
+	}
+	s.ShellMode = mode
+	if mode == ShellModeAuto {
+		s.ShellMode = ""
+	}
+	return Save(s)
+}
+
+// OptInReposPath returns the file listing the directories the shell hook
+// is active in under ShellModeOptIn, one absolute path per line. A
+// directory covers the repos below it. It is plain text so the hook can
+// read it without starting gt.
+func OptInReposPath() string {
+	return filepath.Join(StateDir(), "opt-in-repos")
+}
+
+// OptInRepos returns the registered directories. A missing file means none.
+func OptInRepos() ([]string, error) {
+	f, err := os.Open(OptInReposPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var repos []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			repos = append(repos, line)
+		}
+	}
+	return repos, scanner.Err()
+}
+
+// AddOptInRepo registers dir for ShellModeOptIn. It reports whether dir
+// was added, false if it was already registered.
+func AddOptInRepo(dir string) (bool, error) {
+	repos, err := OptInRepos()
+	if err != nil {
+		return false, err
+	}
+	for _, repo := range repos {
+		if repo == dir {
+			return false, nil
+		}
+	}
+	return true, writeOptInRepos(append(repos, dir))
+}
+
+// RemoveOptInRepo unregisters dir. It reports whether dir was registered.
+func RemoveOptInRepo(dir string) (bool, error) {
+	repos, err := OptInRepos()
+	if err != nil {
+		return false, err
+	}
+	kept := repos[:0]
+	for _, repo := range repos {
+		if repo != dir {
+			kept = append(kept, repo)
+		}
+	}
+	if len(kept) == len(repos) {
+		return false, nil
+	}
+	return true, writeOptInRepos(kept)
+}
+
+// IsOptedIn reports whether dir is registered or is below a registered
+// directory, as the shell hook checks it.
+func IsOptedIn(dir string) bool {
+	repos, _ := OptInRepos()
+	for _, repo := range repos {
+		if dir == repo || strings.HasPrefix(dir, repo+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeOptInRepos(repos []string) error {
+	if err := os.MkdirAll(StateDir(), 0755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, repo := range repos {
+		b.WriteString(repo + "\n")
+	}
+	tmp := OptInReposPath() + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, OptInReposPath())
+}
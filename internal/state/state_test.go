@@ -129,3 +129,74 @@ func TestGenerateMachineID(t *testing.T) {
 		t.Error("generateMachineID() should generate unique IDs")
 	}
 }
+
+func TestShellMode(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if got := GetShellMode(); got != ShellModeAuto {
+		t.Errorf("GetShellMode() with no state = %q, want %q", got, ShellModeAuto)
+	}
+	if err := SetShellMode(ShellModeOptIn); err != nil {
+		t.Fatalf("SetShellMode(opt-in) failed: %v", err)
+	}
+	if got := GetShellMode(); got != ShellModeOptIn {
+		t.Errorf("GetShellMode() = %q, want %q", got, ShellModeOptIn)
+	}
+	if err := SetShellMode("sometimes"); err == nil {
+		t.Error("SetShellMode accepted an unknown mode")
+	}
+
+	if err := SetShellMode(ShellModeAuto); err != nil {
+		t.Fatalf("SetShellMode(auto) failed: %v", err)
+	}
+	s, err := Load()
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if s.ShellMode != "" || s.Mode() != ShellModeAuto {
+		t.Errorf("ShellMode = %q, Mode() = %q; want the default stored as empty", s.ShellMode, s.Mode())
+	}
+}
+
+func TestOptInRepos(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if IsOptedIn("/src/app") {
+		t.Error("IsOptedIn with no registrations = true")
+	}
+	for _, dir := range []string{"/src", "/work/app", "/src"} {
+		if _, err := AddOptInRepo(dir); err != nil {
+			t.Fatalf("AddOptInRepo(%s) failed: %v", dir, err)
+		}
+	}
+	repos, err := OptInRepos()
+	if err != nil {
+		t.Fatalf("OptInRepos() failed: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Errorf("OptInRepos() = %v, want /src and /work/app once each", repos)
+	}
+
+	for dir, want := range map[string]bool{
+		"/src":          true,
+		"/src/app":      true,
+		"/srcfoo":       false,
+		"/work/app":     true,
+		"/work/app2":    false,
+		"/work/app/sub": true,
+	} {
+		if got := IsOptedIn(dir); got != want {
+			t.Errorf("IsOptedIn(%s) = %v, want %v", dir, got, want)
+		}
+	}
+
+	if removed, err := RemoveOptInRepo("/src"); err != nil || !removed {
+		t.Fatalf("RemoveOptInRepo(/src) = %v, %v", removed, err)
+	}
+	if removed, _ := RemoveOptInRepo("/src"); removed {
+		t.Error("RemoveOptInRepo removed /src twice")
+	}
+	if IsOptedIn("/src/app") {
+		t.Error("IsOptedIn(/src/app) after removal = true")
+	}
+}
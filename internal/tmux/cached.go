@@ -0,0 +1,120 @@
+package tmux
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultSessionSetTTL is how long CachedTmux reuses a session listing.
+const DefaultSessionSetTTL = 2 * time.Second
+
+// CachedTmux wraps a Tmux and caches GetSessionSet for a short TTL, so a
+// patrol loop checking many sessions per tick spawns at most one
+// "tmux list-sessions" per TTL.
+//
+// Methods that create, kill or rename sessions through the CachedTmux
+// invalidate the cache. Changes made elsewhere (another process, or the
+// wrapped Tmux directly) are seen once the TTL expires; call Invalidate to
+// see them sooner.
+type CachedTmux struct {
+	*Tmux
+
+	ttl   time.Duration
+	fetch func() (*SessionSet, error) // t.Tmux.GetSessionSet; a field for tests
+
+	mu        sync.Mutex
+	set       *SessionSet
+	fetchedAt time.Time
+}
+
+// NewCachedTmux wraps t, caching session listings for ttl
+// (DefaultSessionSetTTL if ttl <= 0).
+func NewCachedTmux(t *Tmux, ttl time.Duration) *CachedTmux {
+	if ttl <= 0 {
+		ttl = DefaultSessionSetTTL
+	}
+	return &CachedTmux{Tmux: t, ttl: ttl, fetch: t.GetSessionSet}
+}
+
+// GetSessionSet returns the cached session set if it is younger than the
+// TTL, otherwise lists the sessions again. Errors are not cached.
+func (c *CachedTmux) GetSessionSet() (*SessionSet, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.set != nil && time.Since(c.fetchedAt) < c.ttl {
+		return c.set, nil
+	}
+	set, err := c.fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.set, c.fetchedAt = set, time.Now()
+	return set, nil
+}
+
+// Invalidate drops the cached session set, so the next GetSessionSet lists
+// the sessions again.
+func (c *CachedTmux) Invalidate() {
+	c.mu.Lock()
+	c.set = nil
+	c.mu.Unlock()
+}
+
+// NewSession creates a session and invalidates the cache.
+func (c *CachedTmux) NewSession(name, workDir string) error {
+	defer c.Invalidate()
+	return c.Tmux.NewSession(name, workDir)
+}
+
+// NewSessionWithEnv creates a session with env set and invalidates the
+// cache.
+func (c *CachedTmux) NewSessionWithEnv(name, workDir string, env map[string]string) error {
+	defer c.Invalidate()
+	return c.Tmux.NewSessionWithEnv(name, workDir, env)
+}
+
+// NewSessionWithCommand creates a session running command and invalidates
+// the cache.
+func (c *CachedTmux) NewSessionWithCommand(name, workDir, command string) error {
+	defer c.Invalidate()
+	return c.Tmux.NewSessionWithCommand(name, workDir, command)
+}
+
+// EnsureSessionFresh (re)creates a session and invalidates the cache.
+func (c *CachedTmux) EnsureSessionFresh(name, workDir string) error {
+	defer c.Invalidate()
+	return c.Tmux.EnsureSessionFresh(name, workDir)
+}
+
+// EnsureSessionFreshWithBackoff (re)creates a session until it is ready and
+// invalidates the cache.
+func (c *CachedTmux) EnsureSessionFreshWithBackoff(session, startCmd string, policy BackoffPolicy, isReady func(session string) bool) error {
+	defer c.Invalidate()
+	return c.Tmux.EnsureSessionFreshWithBackoff(session, startCmd, policy, isReady)
+}
+
+// KillSession kills a session and invalidates the cache.
+func (c *CachedTmux) KillSession(name string) error {
+	defer c.Invalidate()
+	return c.Tmux.KillSession(name)
+}
+
+// KillSessionWithProcesses kills a session and its processes and
+// invalidates the cache.
+func (c *CachedTmux) KillSessionWithProcesses(name string) error {
+	defer c.Invalidate()
+	return c.Tmux.KillSessionWithProcesses(name)
+}
+
+// KillServer kills the tmux server and invalidates the cache.
+func (c *CachedTmux) KillServer() error {
+	defer c.Invalidate()
+	return c.Tmux.KillServer()
+}
+
+// RenameSession renames a session and invalidates the cache.
+func (c *CachedTmux) RenameSession(oldName, newName string) error {
+	defer c.Invalidate()
+	return c.Tmux.RenameSession(oldName, newName)
+}
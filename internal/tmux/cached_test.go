@@ -0,0 +1,110 @@
+package tmux
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// countingCache returns a CachedTmux whose listings are counted instead of
+// asking tmux.
+func countingCache(ttl time.Duration, calls *int, err *error) *CachedTmux {
+	c := NewCachedTmux(NewTmux(), ttl)
+	c.fetch = func() (*SessionSet, error) {
+		*calls++
+		if *err != nil {
+			return nil, *err
+		}
+		return &SessionSet{sessions: map[string]struct{}{"gt-mayor": {}}}, nil
+	}
+	return c
+}
+
+func TestCachedTmux_GetSessionSet(t *testing.T) {
+	var calls int
+	var fetchErr error
+	c := countingCache(time.Hour, &calls, &fetchErr)
+
+	for i := 0; i < 3; i++ {
+		set, err := c.GetSessionSet()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !set.Has("gt-mayor") {
+			t.Error("cached set lost gt-mayor")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("listed sessions %d times within the TTL, want 1", calls)
+	}
+
+	c.Invalidate()
+	if _, err := c.GetSessionSet(); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("listed sessions %d times after Invalidate, want 2", calls)
+	}
+}
+
+func TestCachedTmux_TTLExpiry(t *testing.T) {
+	var calls int
+	var fetchErr error
+	c := countingCache(10*time.Millisecond, &calls, &fetchErr)
+
+	_, _ = c.GetSessionSet()
+	time.Sleep(20 * time.Millisecond)
+	_, _ = c.GetSessionSet()
+	if calls != 2 {
+		t.Errorf("listed sessions %d times across expiry, want 2", calls)
+	}
+}
+
+func TestCachedTmux_ErrorsNotCached(t *testing.T) {
+	var calls int
+	fetchErr := errors.New("server gone")
+	c := countingCache(time.Hour, &calls, &fetchErr)
+
+	if _, err := c.GetSessionSet(); err == nil {
+		t.Fatal("expected error")
+	}
+	fetchErr = nil
+	if _, err := c.GetSessionSet(); err != nil {
+		t.Fatalf("error was cached: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("listed sessions %d times, want 2", calls)
+	}
+}
+
+func TestNewCachedTmux_DefaultTTL(t *testing.T) {
+	if c := NewCachedTmux(NewTmux(), 0); c.ttl != DefaultSessionSetTTL {
+		t.Errorf("ttl = %v, want %v", c.ttl, DefaultSessionSetTTL)
+	}
+}
+
+func BenchmarkGetSessionSet_Uncached(b *testing.B) {
+	if !hasTmux() {
+		b.Skip("tmux not installed")
+	}
+	tm := NewTmux()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := tm.GetSessionSet(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetSessionSet_Cached(b *testing.B) {
+	if !hasTmux() {
+		b.Skip("tmux not installed")
+	}
+	c := NewCachedTmux(NewTmux(), DefaultSessionSetTTL)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetSessionSet(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build darwin
+
+package tmux
+
+// psCommandFormat is the ps output column holding the full command line.
+// macOS truncates "comm" to 15 characters but reports the full argv via "command".
+const psCommandFormat = "command="
+
+// useFullPaneCommand reports whether pane command detection should fall back
+// to the full ps command line. tmux's #{pane_current_command} on macOS is
+// derived from the truncated comm, so paths like /usr/local/bin/claude can
+// be misreported.
+const useFullPaneCommand = true
@@ -0,0 +1,12 @@
+//go:build !darwin
+
+package tmux
+
+// psCommandFormat is the ps output column holding the full command line.
+// procps spells it "args"; "command" is an alias but not on every ps.
+const psCommandFormat = "args="
+
+// useFullPaneCommand reports whether pane command detection should fall back
+// to the full ps command line. Linux tmux reads the pane command from /proc,
+// which is reliable, so the fallback is not needed.
+const useFullPaneCommand = false
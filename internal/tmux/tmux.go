@@ -5,17 +5,20 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/KeithWyatt/gongshow/internal/config"
 	"github.com/KeithWyatt/gongshow/internal/constants"
 	"github.com/KeithWyatt/gongshow/internal/proc"
+	"github.com/google/uuid"
 )
 
 // versionPattern matches Claude Code version numbers like "2.0.76"
@@ -25,15 +28,15 @@ var versionPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
 const (
 	// SIGTERMGracePeriod is the time to wait after SIGTERM before sending SIGKILL.
 	// 500ms gives processes time to handle cleanup gracefully.
-	SIGTERMGracePeriod = 500 * time.Millisecond
+	SIGTERMGracePeriod = proc.SIGTERMGracePeriod
 
 	// DescendantRescanDelay is the delay between descendant discovery passes.
 	// This helps catch processes that fork during the initial scan.
-	DescendantRescanDelay = 50 * time.Millisecond
+	DescendantRescanDelay = proc.DescendantRescanDelay
 
 	// DescendantRescanAttempts is the number of times to rescan for new descendants.
 	// Multiple passes help catch race conditions where processes fork during cleanup.
-	DescendantRescanAttempts = 3
+	DescendantRescanAttempts = proc.DescendantRescanAttempts
 )
 
 // validSessionNameRe validates session names to prevent shell injection
@@ -44,9 +47,12 @@ var (
 	ErrNoServer        = errors.New("no tmux server running")
 	ErrSessionExists   = errors.New("session already exists")
 	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionNotReady = errors.New("session not ready")
+	ErrExecTimeout     = errors.New("timeout waiting for command to finish")
 )
 
-// Tmux wraps tmux operations.
+// Tmux wraps tmux operations. It holds no state - each call runs its own
+// tmux client - so one Tmux is safe for concurrent use.
 type Tmux struct{}
 
 // NewTmux creates a new Tmux wrapper.
@@ -102,6 +108,49 @@ func (t *Tmux) NewSession(name, workDir string) error {
 	return err
 }
 
+// NewSessionWithEnv creates a new detached tmux session with env set in its
+// environment (new-session -e, tmux 3.2+). Unlike SetEnvironment after
+// NewSession, the variables are there before the pane's shell starts, so
+// the shell and everything it runs inherit them. Older tmux falls back to
+// SetEnvironment, where only processes started after it, such as a
+// respawned pane's, inherit them.
+func (t *Tmux) NewSessionWithEnv(name, workDir string, env map[string]string) error {
+	if !t.SupportsNewSessionEnv() {
+		if err := t.NewSession(name, workDir); err != nil {
+			return err
+		}
+		for k, v := range env {
+			if err := t.SetEnvironment(name, k, v); err != nil {
+				return fmt.Errorf("setting %s: %w", k, err)
+			}
+		}
+		return nil
+	}
+
+	args := []string{"new-session", "-d", "-s", name}
+	if workDir != "" {
+		args = append(args, "-c", workDir)
+	}
+	args = append(args, envArgs(env)...)
+	_, err := t.run(args...)
+	return err
+}
+
+// envArgs returns a -e KEY=VALUE pair of arguments for each variable in
+// env, sorted by key.
+func envArgs(env map[string]string) []string {
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	args := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		args = append(args, "-e", k+"="+env[k])
+	}
+	return args
+}
+
 // NewSessionWithCommand creates a new detached tmux session that immediately runs a command.
 // Unlike NewSession + SendKeys, this avoids race conditions where the shell isn't ready
 // or the command arrives before the shell prompt. The command runs directly as the
@@ -128,6 +177,14 @@ func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
 //
 // Returns nil if session was created successfully.
 func (t *Tmux) EnsureSessionFresh(name, workDir string) error {
+	return t.ensureFresh(name, func() error {
+		return t.NewSession(name, workDir)
+	})
+}
+
+// ensureFresh kills name if it is a zombie and calls create unless a
+// healthy session is already running.
+func (t *Tmux) ensureFresh(name string, create func() error) error {
 	// Check if session already exists
 	exists, err := t.HasSession(name)
 	if err != nil {
@@ -149,77 +206,140 @@ func (t *Tmux) EnsureSessionFresh(name, workDir string) error {
 	}
 
 	// Create fresh session
-	return t.NewSession(name, workDir)
+	return create()
+}
+
+// BackoffPolicy controls how EnsureSessionFreshWithBackoff retries a session
+// that doesn't become ready: attempt n (from 0) gives the session
+// InitialDelay * Multiplier^n to become ready before the next attempt.
+type BackoffPolicy struct {
+	MaxRetries   int // attempts before giving up
+	InitialDelay time.Duration
+	Multiplier   float64
+}
+
+// DefaultBackoffPolicy gives up to 4 attempts 1s, 2s, 4s and 8s to become ready.
+var DefaultBackoffPolicy = BackoffPolicy{
+	MaxRetries:   4,
+	InitialDelay: time.Second,
+	Multiplier:   2,
+}
+
+// Delay returns how long the given attempt (counting from 0) waits for
+// readiness.
+func (p BackoffPolicy) Delay(attempt int) time.Duration {
+	return time.Duration(float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt)))
+}
+
+// backoffSleep waits between readiness checks in
+// EnsureSessionFreshWithBackoff; a var for tests.
+var backoffSleep = time.Sleep
+
+// readyPollInterval is how often EnsureSessionFreshWithBackoff checks a new
+// session for readiness while it waits out an attempt's delay.
+var readyPollInterval = 250 * time.Millisecond
+
+// EnsureSessionFreshWithBackoff is EnsureSessionFresh for a session running
+// startCmd, retried with backoff until isReady reports the session ready.
+// Each attempt gives the session policy.Delay(attempt) to become ready,
+// checking every readyPollInterval, before it counts as failed. An agent
+// that crashes straight after starting leaves a zombie session, which the
+// next attempt replaces, so a crash loop slows down instead of churning
+// sessions as fast as they die. Returns ErrSessionNotReady once
+// policy.MaxRetries attempts have failed.
+func (t *Tmux) EnsureSessionFreshWithBackoff(session, startCmd string, policy BackoffPolicy, isReady func(session string) bool) error {
+	attempts := max(policy.MaxRetries, 1)
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err := t.ensureFresh(session, func() error {
+			return t.NewSessionWithCommand(session, "", startCmd)
+		}); err != nil {
+			return err
+		}
+		if waitReady(session, policy.Delay(attempt), isReady) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s after %d attempt(s)", ErrSessionNotReady, session, attempts)
+}
+
+// waitReady checks isReady every readyPollInterval until it reports the
+// session ready or window has passed.
+func waitReady(session string, window time.Duration, isReady func(session string) bool) bool {
+	for waited := time.Duration(0); ; {
+		if isReady(session) {
+			return true
+		}
+		if waited >= window {
+			return false
+		}
+		step := min(readyPollInterval, window-waited)
+		backoffSleep(step)
+		waited += step
+	}
+}
+
+// killRecorder, if set, is told about every session a Tmux kills; see
+// SetKillRecorder.
+var killRecorder func(session, reason string)
+
+// SetKillRecorder has fn called with each session killed through
+// KillSession or KillSessionWithProcesses, and why, so that kills can be
+// audited. The tmux package can't tell GongShow sessions or actors apart
+// itself, so the recorder is wired in by the caller.
+func SetKillRecorder(fn func(session, reason string)) {
+	killRecorder = fn
 }
 
 // KillSession terminates a tmux session.
 func (t *Tmux) KillSession(name string) error {
-	_, err := t.run("kill-session", "-t", name)
-	return err
+	return t.killSession(name, "session killed")
+}
+
+func (t *Tmux) killSession(name, reason string) error {
+	if _, err := t.run("kill-session", "-t", name); err != nil {
+		return err
+	}
+	if killRecorder != nil {
+		killRecorder(name, reason)
+	}
+	return nil
 }
 
 // KillSessionWithProcesses explicitly kills all processes in a session before terminating it.
 // This prevents orphan processes that survive tmux kill-session due to SIGHUP being ignored.
 //
-// Process:
-// 1. Get the pane's main process PID
-// 2. Find all descendant processes with retry/rescan to catch forks
-// 3. Send SIGTERM to all descendants (deepest first to avoid orphaning grandchildren)
-// 4. Wait for graceful shutdown (SIGTERMGracePeriod)
-// 5. Rescan for any processes that forked during SIGTERM handling
-// 6. Send SIGKILL to all descendants (deepest first, including newly discovered)
-// 7. Kill the tmux session
-//
-// The retry/rescan approach addresses race conditions where processes fork new children
-// after the initial descendant list is built but before kill signals are sent.
-// SIGKILL is sent in deepest-first order to prevent brief orphaning of grandchildren.
-//
-// Performance: Uses native Go syscalls instead of spawning shell commands for each signal.
+// The pane's main process and its descendants are killed with proc.KillTree:
+// SIGTERM deepest first, up to SIGTERMGracePeriod to exit, with children
+// forked meanwhile rescanned and signaled too, then SIGKILL for the rest.
+// The session is killed after, unless it ended with its process.
 func (t *Tmux) KillSessionWithProcesses(name string) error {
+	const reason = "session killed with its processes"
+
 	// Get the pane PID
 	pidStr, err := t.GetPanePID(name)
 	if err != nil {
 		// Session might not exist or be in bad state, try direct kill
-		return t.KillSession(name)
+		return t.killSession(name, reason)
 	}
 
 	if pidStr != "" {
 		pid, err := strconv.Atoi(pidStr)
 		if err != nil {
-			return t.KillSession(name)
-		}
-
-		// Get all descendant PIDs with multiple passes to catch race conditions
-		// Uses native /proc filesystem - no shell spawning
-		descendants := getAllDescendantsWithRetry(pid)
-
-		// Send SIGTERM to all descendants using native syscalls (no shell spawning)
-		proc.SignalAll(descendants, syscall.SIGTERM)
-
-		// Wait for graceful shutdown - longer period for proper cleanup
-		time.Sleep(SIGTERMGracePeriod)
-
-		// Rescan to catch any processes that may have forked during SIGTERM handling
-		// or were missed in the initial scan
-		finalDescendants := getAllDescendantsWithRetry(pid)
-
-		// Build set of all PIDs to SIGKILL (original + newly discovered)
-		killSet := make(map[int]bool)
-		for _, dpid := range descendants {
-			killSet[dpid] = true
-		}
-		for _, dpid := range finalDescendants {
-			killSet[dpid] = true
+			return t.killSession(name, reason)
 		}
+		// The pane process may already be gone; the session is killed anyway.
+		_, _, _ = proc.KillTree(pid, SIGTERMGracePeriod)
+	}
 
-		// Send SIGKILL to all PIDs in the set using native syscalls
-		for dpid := range killSet {
-			_ = syscall.Kill(dpid, syscall.SIGKILL)
+	// Kill the tmux session, which may have closed when its process exited
+	err = t.killSession(name, reason)
+	if errors.Is(err, ErrSessionNotFound) {
+		if killRecorder != nil {
+			killRecorder(name, reason)
 		}
+		return nil
 	}
-
-	// Kill the tmux session
-	return t.KillSession(name)
+	return err
 }
 
 // getAllDescendants recursively finds all descendant PIDs of a process.
@@ -231,48 +351,9 @@ func getAllDescendants(pid int) []int {
 
 // getAllDescendantsWithRetry finds all descendant PIDs with multiple passes.
 // This addresses race conditions where processes fork during the scan.
-// Each pass may discover new children that were created after the previous pass.
 // Returns PIDs in deepest-first order, deduplicated.
-// Uses native /proc filesystem access - no shell spawning.
 func getAllDescendantsWithRetry(pid int) []int {
-	seen := make(map[int]bool)
-
-	for attempt := 0; attempt < DescendantRescanAttempts; attempt++ {
-		if attempt > 0 {
-			time.Sleep(DescendantRescanDelay)
-		}
-
-		descendants := proc.GetAllDescendants(pid)
-		newFound := false
-
-		for _, dpid := range descendants {
-			if !seen[dpid] {
-				seen[dpid] = true
-				newFound = true
-			}
-		}
-
-		// If no new processes found, we've likely caught everything
-		if !newFound && attempt > 0 {
-			break
-		}
-	}
-
-	// Build result in deepest-first order by re-running GetAllDescendants.
-	// We can't just use the accumulated PIDs because they're added in discovery
-	// order across multiple passes, not in tree-depth order. Re-scanning gives
-	// us the correct deepest-first ordering for safe process termination.
-	// PIDs that died between retry passes will simply be absent - that's fine,
-	// they don't need killing.
-	finalDescendants := proc.GetAllDescendants(pid)
-	var result []int
-	for _, dpid := range finalDescendants {
-		if seen[dpid] {
-			result = append(result, dpid)
-		}
-	}
-
-	return result
+	return proc.GetAllDescendantsWithRescan(pid)
 }
 
 // KillServer terminates the entire tmux server and all sessions.
@@ -605,6 +686,45 @@ func (t *Tmux) GetPaneCommand(session string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// GetPaneCommandFull returns the executable name of the pane's main process,
+// derived from the full command line reported by ps rather than the
+// 15-character comm field. "/usr/local/bin/claude --resume" yields "claude".
+func (t *Tmux) GetPaneCommandFull(session string) (string, error) {
+	pidStr, err := t.GetPanePID(session)
+	if err != nil {
+		return "", err
+	}
+	pid, err := strconv.Atoi(strings.Split(pidStr, "\n")[0])
+	if err != nil {
+		return "", fmt.Errorf("parsing pane PID %q: %w", pidStr, err)
+	}
+	return processCommandName(pid)
+}
+
+// processCommandName returns the executable name for a PID using ps.
+func processCommandName(pid int) (string, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", psCommandFormat).Output()
+	if err != nil {
+		return "", fmt.Errorf("ps -p %d: %w", pid, err)
+	}
+	name := commandNameFromArgs(string(out))
+	if name == "" {
+		return "", fmt.Errorf("no command found for pid %d", pid)
+	}
+	return name, nil
+}
+
+// commandNameFromArgs extracts the executable name from a full command line:
+// the first argument with any directory prefix removed. Login shells report
+// argv[0] with a leading dash ("-zsh"), which is also stripped.
+func commandNameFromArgs(args string) string {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(filepath.Base(fields[0]), "-")
+}
+
 // GetPaneID returns the pane identifier for a session's first pane.
 // Returns a pane ID like "%0" that can be used with RespawnPane.
 func (t *Tmux) GetPaneID(session string) (string, error) {
@@ -637,6 +757,72 @@ func (t *Tmux) GetPanePID(session string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// PaneInfo describes a pane in a session's current window.
+type PaneInfo struct {
+	Index   int    // pane index, as used by KillPane
+	PID     int    // PID of the pane's main process
+	Command string // current foreground command
+	Active  bool   // whether this is the window's active pane
+	Width   int
+	Height  int
+}
+
+// ListPanes returns the panes of a session's current window, in index order.
+func (t *Tmux) ListPanes(session string) ([]PaneInfo, error) {
+	format := "#{pane_index}|#{pane_pid}|#{pane_width}|#{pane_height}|#{pane_active}|#{pane_current_command}"
+	out, err := t.run("list-panes", "-t", session, "-F", format)
+	if err != nil {
+		return nil, err
+	}
+
+	var panes []PaneInfo
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, "|", 6)
+		if len(parts) != 6 {
+			return nil, fmt.Errorf("unexpected pane info format: %s", line)
+		}
+		var nums [4]int // index, pid, width, height
+		for i := range nums {
+			if nums[i], err = strconv.Atoi(parts[i]); err != nil {
+				return nil, fmt.Errorf("unexpected pane info format: %s", line)
+			}
+		}
+		panes = append(panes, PaneInfo{
+			Index:   nums[0],
+			PID:     nums[1],
+			Width:   nums[2],
+			Height:  nums[3],
+			Active:  parts[4] == "1",
+			Command: parts[5],
+		})
+	}
+	return panes, nil
+}
+
+// KillPane terminates one pane in a session's current window, leaving the
+// session's other panes running. Killing the only pane ends the session.
+func (t *Tmux) KillPane(session string, paneIndex int) error {
+	_, err := t.run("kill-pane", "-t", fmt.Sprintf("%s.%d", session, paneIndex))
+	return err
+}
+
+// GetTmuxServerPID returns the PID of the tmux server, asked directly over
+// its socket. Returns ErrNoServer if no server is running.
+func (t *Tmux) GetTmuxServerPID() (int, error) {
+	out, err := t.run("display-message", "-p", "#{pid}")
+	if err != nil {
+		return 0, err
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, fmt.Errorf("parsing tmux server pid %q: %w", out, err)
+	}
+	return pid, nil
+}
+
 // hasClaudeChild checks if a process has a descendant running claude/node.
 // Used when the pane command is a shell (bash, zsh) that launched claude.
 // This recursively checks all descendants, not just direct children, to handle
@@ -708,6 +894,82 @@ func (t *Tmux) CapturePaneLines(session string, lines int) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// ExecInSession runs a one-off command at the shell prompt of a session,
+// in that session's environment (GT_TOWN_ROOT, working directory and so
+// on), and returns its output, stdout and stderr together. Unlike
+// CapturePane, which reads whatever the pane shows, this returns the
+// command's own output and nothing else.
+//
+// The command is typed with SendKeys, its output teed to a temp file, and
+// a unique sentinel echoed to that file after it: the output is what the
+// file holds before the sentinel. The pane must be at the prompt of a
+// POSIX shell (sh, bash, zsh), not running an agent. On timeout the
+// command is left running and ErrExecTimeout returned; the shell removes
+// the file once the command finishes.
+func (t *Tmux) ExecInSession(session, command string, timeout time.Duration) (string, error) {
+	f, err := os.CreateTemp("", "gt-exec-*.out")
+	if err != nil {
+		return "", fmt.Errorf("creating exec output file: %w", err)
+	}
+	outPath := f.Name()
+	_ = f.Close()
+	// Created on timeout: tells the shell to clean up after the command.
+	abandonPath := outPath + ".abandoned"
+
+	sentinel := "gt-exec-done-" + uuid.New().String()
+	quotedPath := shellQuote(outPath)
+	quotedAbandon := shellQuote(abandonPath)
+	// The subshell keeps a cd or export in command from outliving it.
+	line := fmt.Sprintf("( %s ) 2>&1 | tee %s; echo %s >> %s; [ -e %s ] && rm -f %s %s",
+		command, quotedPath, sentinel, quotedPath, quotedAbandon, quotedPath, quotedAbandon)
+	if err := t.SendKeys(session, line); err != nil {
+		_ = os.Remove(outPath)
+		return "", err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		out, done, err := readExecOutput(outPath, sentinel)
+		if err != nil {
+			return "", err
+		}
+		if done {
+			_ = os.Remove(outPath)
+			return out, nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(constants.PollInterval)
+	}
+
+	// Either the shell sees the marker once the command finishes, or the
+	// command finished first and the sentinel is there now.
+	if err := os.WriteFile(abandonPath, nil, 0600); err != nil {
+		_ = os.Remove(outPath)
+	} else if _, done, _ := readExecOutput(outPath, sentinel); done {
+		_ = os.Remove(outPath)
+		_ = os.Remove(abandonPath)
+	}
+	return "", fmt.Errorf("exec in session %s: %w", session, ErrExecTimeout)
+}
+
+// readExecOutput returns what ExecInSession's output file holds before
+// sentinel, and whether the sentinel is there yet.
+func readExecOutput(path, sentinel string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("reading exec output: %w", err)
+	}
+	out, ok := strings.CutSuffix(string(data), sentinel+"\n")
+	return out, ok, nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
 // AttachSession attaches to an existing session.
 // Note: This replaces the current process with tmux attach.
 func (t *Tmux) AttachSession(session string) error {
@@ -861,6 +1123,13 @@ func (t *Tmux) IsClaudeRunning(session string) bool {
 	if versionPattern.MatchString(cmd) {
 		return true
 	}
+	// On macOS the pane command comes from the truncated comm field, so
+	// consult the full command line before giving up.
+	if useFullPaneCommand {
+		if full, err := t.GetPaneCommandFull(session); err == nil && (full == "claude" || full == "node") {
+			return true
+		}
+	}
 	// If pane command is a shell, check for claude/node child processes.
 	// This handles the case where sessions are started with "bash -c 'export ... && claude ...'"
 	for _, shell := range constants.SupportedShells {
@@ -1255,6 +1524,63 @@ func (t *Tmux) SetFeedBinding(session string) error {
 	return err
 }
 
+// Version returns the major and minor version of the tmux binary, from
+// "tmux -V" (e.g. "tmux 3.3a" is 3, 3). Development builds ("tmux master",
+// "tmux next-3.5") report the version they lead up to, or 99 if unknown.
+func (t *Tmux) Version() (major, minor int, err error) {
+	out, err := exec.Command("tmux", "-V").Output()
+	if err != nil {
+		return 0, 0, fmt.Errorf("tmux -V: %w", err)
+	}
+	return parseTmuxVersion(string(out))
+}
+
+var tmuxVersionRe = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+func parseTmuxVersion(out string) (major, minor int, err error) {
+	fields := strings.Fields(out)
+	if len(fields) < 2 || fields[0] != "tmux" {
+		return 0, 0, fmt.Errorf("unrecognized tmux version %q", strings.TrimSpace(out))
+	}
+	m := tmuxVersionRe.FindStringSubmatch(fields[1])
+	if m == nil {
+		if fields[1] == "master" {
+			return 99, 0, nil
+		}
+		return 0, 0, fmt.Errorf("unrecognized tmux version %q", fields[1])
+	}
+	major, _ = strconv.Atoi(m[1])
+	minor, _ = strconv.Atoi(m[2])
+	return major, minor, nil
+}
+
+// SupportsHooks reports whether tmux has set-hook (tmux 2.2+).
+func (t *Tmux) SupportsHooks() bool {
+	major, minor, err := t.Version()
+	return err == nil && (major > 2 || (major == 2 && minor >= 2))
+}
+
+// SupportsNewSessionEnv reports whether tmux has new-session -e (tmux 3.2+).
+func (t *Tmux) SupportsNewSessionEnv() bool {
+	major, minor, err := t.Version()
+	return err == nil && (major > 3 || (major == 3 && minor >= 2))
+}
+
+// SetHook sets a global hook: tmux runs command (a tmux command, e.g.
+// run-shell "...") whenever hookName fires in any session. On tmux 3.0+
+// hooks are arrays, and hookName may carry an index ("session-closed[42]")
+// to add a command without replacing others on the same event.
+func (t *Tmux) SetHook(hookName, command string) error {
+	_, err := t.run("set-hook", "-g", hookName, command)
+	return err
+}
+
+// UnsetHook removes a global hook set with SetHook.
+func (t *Tmux) UnsetHook(hookName string) error {
+	_, err := t.run("set-hook", "-gu", hookName)
+	return err
+}
+
 // SetPaneDiedHook sets a pane-died hook on a session to detect crashes.
 // When the pane exits, tmux runs the hook command with exit status info.
 // The agentID is used to identify the agent in crash logs (e.g., "gongshow/Toast").
@@ -1,11 +1,18 @@
 package tmux
 
 import (
+	"errors"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/proc"
 )
 
 func hasTmux() bool {
@@ -159,6 +166,32 @@ func TestDuplicateSession(t *testing.T) {
 	}
 }
 
+func TestKillSessionRecordsKill(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	type kill struct{ session, reason string }
+	var kills []kill
+	SetKillRecorder(func(session, reason string) { kills = append(kills, kill{session, reason}) })
+	t.Cleanup(func() { SetKillRecorder(nil) })
+
+	tm := NewTmux()
+	sessionName := "gt-test-killrec-" + t.Name()
+	if err := tm.NewSession(sessionName, ""); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if err := tm.KillSession(sessionName); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	// Killing a session that isn't there records nothing.
+	_ = tm.KillSession(sessionName)
+
+	if len(kills) != 1 || kills[0] != (kill{sessionName, "session killed"}) {
+		t.Errorf("recorded kills = %+v, want just %s", kills, sessionName)
+	}
+}
+
 func TestSendKeysAndCapture(t *testing.T) {
 	if !hasTmux() {
 		t.Skip("tmux not installed")
@@ -195,6 +228,59 @@ func TestSendKeysAndCapture(t *testing.T) {
 	}
 }
 
+func TestExecInSession(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+	if !hasTmuxFilterFlag() {
+		t.Skip("tmux < 3.2 does not start the shell with the session's environment")
+	}
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	tm := NewTmux()
+	sessionName := "gt-test-exec-" + t.Name()
+
+	_ = tm.KillSession(sessionName)
+	if err := tm.NewSessionWithEnv(sessionName, "", map[string]string{"GT_EXEC_TEST": "from-session"}); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	out, err := tm.ExecInSession(sessionName, "echo hello", 10*time.Second)
+	if err != nil {
+		t.Fatalf("ExecInSession: %v", err)
+	}
+	if out != "hello\n" {
+		t.Errorf("ExecInSession(echo hello) = %q, want %q", out, "hello\n")
+	}
+
+	// The session's environment, and stderr along with stdout.
+	out, err = tm.ExecInSession(sessionName, `echo "$GT_EXEC_TEST"; echo oops >&2`, 10*time.Second)
+	if err != nil {
+		t.Fatalf("ExecInSession: %v", err)
+	}
+	if out != "from-session\noops\n" {
+		t.Errorf("ExecInSession() = %q, want the session's variable and stderr", out)
+	}
+
+	if _, err := tm.ExecInSession(sessionName, "sleep 1", 300*time.Millisecond); !errors.Is(err, ErrExecTimeout) {
+		t.Errorf("ExecInSession(sleep 1) = %v, want ErrExecTimeout", err)
+	}
+	// The shell cleans up after the abandoned command once it finishes.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		left, _ := os.ReadDir(tmpDir)
+		if len(left) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("exec files left behind: %v", left)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
 func TestGetSessionInfo(t *testing.T) {
 	if !hasTmux() {
 		t.Skip("tmux not installed")
@@ -228,6 +314,156 @@ func TestGetSessionInfo(t *testing.T) {
 	}
 }
 
+func TestGetTmuxServerPID(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	sessionName := "gt-test-serverpid-" + t.Name()
+	_ = tm.KillSession(sessionName)
+
+	// A session guarantees the server is running.
+	if err := tm.NewSession(sessionName, ""); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	pid, err := tm.GetTmuxServerPID()
+	if err != nil {
+		t.Fatalf("GetTmuxServerPID: %v", err)
+	}
+	if pid <= 1 || !proc.Exists(pid) {
+		t.Errorf("GetTmuxServerPID = %d, want a running process", pid)
+	}
+}
+
+func TestEnvArgs(t *testing.T) {
+	got := envArgs(map[string]string{"GT_RIG": "gongshow", "BD_ACTOR": "gongshow/crew/max", "EMPTY": ""})
+	want := []string{"-e", "BD_ACTOR=gongshow/crew/max", "-e", "EMPTY=", "-e", "GT_RIG=gongshow"}
+	if !slices.Equal(got, want) {
+		t.Errorf("envArgs() = %q, want %q", got, want)
+	}
+	if got := envArgs(nil); len(got) != 0 {
+		t.Errorf("envArgs(nil) = %q, want none", got)
+	}
+}
+
+func TestNewSessionWithEnv(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	sessionName := "gt-test-env-" + t.Name()
+	_ = tm.KillSession(sessionName)
+
+	env := map[string]string{
+		"GT_TOWN_ROOT": "/tmp/town with spaces",
+		"GT_RIG":       "gongshow",
+		"BD_ACTOR":     "gongshow/crew/max",
+	}
+	if err := tm.NewSessionWithEnv(sessionName, "", env); err != nil {
+		t.Fatalf("NewSessionWithEnv: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	for k, want := range env {
+		got, err := tm.GetEnvironment(sessionName, k)
+		if err != nil || got != want {
+			t.Errorf("GetEnvironment(%s) = %q, %v; want %q", k, got, err, want)
+		}
+	}
+
+	// The pane's shell was started with them, unlike with SetEnvironment.
+	if !tm.SupportsNewSessionEnv() {
+		t.Skip("tmux < 3.2 sets the environment after the shell starts")
+	}
+	pid, err := tm.GetPanePID(sessionName)
+	if err != nil {
+		t.Fatalf("GetPanePID: %v", err)
+	}
+	environ, err := os.ReadFile(filepath.Join("/proc", pid, "environ"))
+	if err != nil {
+		t.Skipf("can't read the pane's environment: %v", err)
+	}
+	vars := strings.Split(string(environ), "\x00")
+	for k, v := range env {
+		if !slices.Contains(vars, k+"="+v) {
+			t.Errorf("pane shell environment lacks %s=%s", k, v)
+		}
+	}
+}
+
+func TestListPanesAndKillPane(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	sessionName := "gt-test-panes-" + t.Name()
+	_ = tm.KillSession(sessionName)
+
+	if err := tm.NewSessionWithCommand(sessionName, "", "sleep 300"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	// A second pane, as a log tail would run in.
+	if _, err := tm.run("split-window", "-d", "-t", sessionName, "tail -f /dev/null"); err != nil {
+		t.Fatalf("split-window: %v", err)
+	}
+
+	panes, err := tm.ListPanes(sessionName)
+	if err != nil {
+		t.Fatalf("ListPanes: %v", err)
+	}
+	if len(panes) != 2 {
+		t.Fatalf("ListPanes returned %d panes, want 2: %+v", len(panes), panes)
+	}
+	agent, logTail := panes[0], panes[1]
+	if !agent.Active || logTail.Active {
+		t.Errorf("active = %v, %v; want the first pane active (split-window -d)", agent.Active, logTail.Active)
+	}
+	for _, p := range panes {
+		if p.PID <= 1 || !proc.Exists(p.PID) || p.Width <= 0 || p.Height <= 0 {
+			t.Errorf("pane %+v: want a running PID and a size", p)
+		}
+	}
+	if logTail.Command != "tail" {
+		t.Errorf("second pane command = %q, want tail", logTail.Command)
+	}
+
+	if err := tm.KillPane(sessionName, logTail.Index); err != nil {
+		t.Fatalf("KillPane: %v", err)
+	}
+	panes, err = tm.ListPanes(sessionName)
+	if err != nil {
+		t.Fatalf("ListPanes after KillPane: %v", err)
+	}
+	if len(panes) != 1 || panes[0].Index != agent.Index || panes[0].PID != agent.PID {
+		t.Errorf("after KillPane panes = %+v, want only the agent pane %+v", panes, agent)
+	}
+	if has, _ := tm.HasSession(sessionName); !has {
+		t.Error("KillPane ended the session")
+	}
+
+	if err := tm.KillPane(sessionName, logTail.Index); err == nil {
+		t.Error("KillPane of a killed pane succeeded, want an error")
+	}
+}
+
+func TestListPanesNoSession(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	if _, err := tm.ListPanes("gt-test-nonexistent-session-xyz"); err == nil {
+		t.Error("ListPanes of a nonexistent session succeeded, want an error")
+	}
+}
+
 func TestWrapError(t *testing.T) {
 	tm := NewTmux()
 
@@ -350,6 +586,113 @@ func TestEnsureSessionFresh_IdempotentOnZombie(t *testing.T) {
 	}
 }
 
+func TestBackoffPolicyDelay(t *testing.T) {
+	p := BackoffPolicy{MaxRetries: 5, InitialDelay: 100 * time.Millisecond, Multiplier: 2}
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	for attempt, d := range want {
+		if got := p.Delay(attempt); got != d {
+			t.Errorf("Delay(%d) = %v, want %v", attempt, got, d)
+		}
+	}
+}
+
+// recordBackoffSleeps replaces backoffSleep with one that records the waits,
+// and sets readyPollInterval to poll.
+func recordBackoffSleeps(t *testing.T, poll time.Duration) *[]time.Duration {
+	t.Helper()
+	var sleeps []time.Duration
+	origSleep, origPoll := backoffSleep, readyPollInterval
+	backoffSleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	readyPollInterval = poll
+	t.Cleanup(func() { backoffSleep, readyPollInterval = origSleep, origPoll })
+	return &sleeps
+}
+
+func TestEnsureSessionFreshWithBackoff_RetriesUntilReady(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+	sleeps := recordBackoffSleeps(t, 10*time.Millisecond)
+
+	tm := NewTmux()
+	sessionName := "gt-test-backoff-ready"
+	_ = tm.KillSession(sessionName)
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	checks := 0
+	isReady := func(session string) bool {
+		checks++
+		return checks > 3 // not ready within the first attempt's 20ms
+	}
+	policy := BackoffPolicy{MaxRetries: 5, InitialDelay: 20 * time.Millisecond, Multiplier: 3}
+	if err := tm.EnsureSessionFreshWithBackoff(sessionName, "sleep 30", policy, isReady); err != nil {
+		t.Fatalf("EnsureSessionFreshWithBackoff: %v", err)
+	}
+	if checks != 4 {
+		t.Errorf("isReady called %d times, want 4", checks)
+	}
+	if want := []time.Duration{10 * time.Millisecond, 10 * time.Millisecond}; !slices.Equal(*sleeps, want) {
+		t.Errorf("waits = %v, want %v", *sleeps, want)
+	}
+	if has, _ := tm.HasSession(sessionName); !has {
+		t.Error("expected session to exist")
+	}
+}
+
+func TestEnsureSessionFreshWithBackoff_ReadyWithinDelay(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+	recordBackoffSleeps(t, 10*time.Millisecond)
+
+	tm := NewTmux()
+	sessionName := "gt-test-backoff-slow"
+	_ = tm.KillSession(sessionName)
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	checks := 0
+	isReady := func(session string) bool {
+		checks++
+		return checks > 2
+	}
+	// A single attempt: the session counts as ready if it gets there
+	// before the delay is up.
+	policy := BackoffPolicy{MaxRetries: 1, InitialDelay: 50 * time.Millisecond, Multiplier: 2}
+	if err := tm.EnsureSessionFreshWithBackoff(sessionName, "sleep 30", policy, isReady); err != nil {
+		t.Fatalf("EnsureSessionFreshWithBackoff: %v", err)
+	}
+}
+
+func TestEnsureSessionFreshWithBackoff_GivesUp(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+	sleeps := recordBackoffSleeps(t, time.Hour)
+
+	tm := NewTmux()
+	sessionName := "gt-test-backoff-never"
+	_ = tm.KillSession(sessionName)
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	checks := 0
+	isReady := func(session string) bool {
+		checks++
+		return false
+	}
+	policy := BackoffPolicy{MaxRetries: 3, InitialDelay: 10 * time.Millisecond, Multiplier: 2}
+	err := tm.EnsureSessionFreshWithBackoff(sessionName, "sleep 30", policy, isReady)
+	if !errors.Is(err, ErrSessionNotReady) {
+		t.Fatalf("EnsureSessionFreshWithBackoff = %v, want ErrSessionNotReady", err)
+	}
+	// Each attempt is checked at the start and end of its delay.
+	if checks != 6 {
+		t.Errorf("isReady called %d times, want 6", checks)
+	}
+	if want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond}; !slices.Equal(*sleeps, want) {
+		t.Errorf("waits = %v, want %v", *sleeps, want)
+	}
+}
+
 func TestIsAgentRunning(t *testing.T) {
 	if !hasTmux() {
 		t.Skip("tmux not installed")
@@ -704,3 +1047,139 @@ func TestSessionSet(t *testing.T) {
 		t.Errorf("SessionSet.Names() doesn't contain %q", sessionName)
 	}
 }
+
+func TestCommandNameFromArgs(t *testing.T) {
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"/usr/local/bin/claude --resume\n", "claude"},
+		{"claude", "claude"},
+		{"node /opt/homebrew/bin/claude", "node"},
+		{"-zsh", "zsh"},
+		{"/bin/bash -l", "bash"},
+		{"", ""},
+		{"   \n", ""},
+	}
+	for _, tt := range tests {
+		if got := commandNameFromArgs(tt.args); got != tt.want {
+			t.Errorf("commandNameFromArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestProcessCommandName_Self(t *testing.T) {
+	if _, err := exec.LookPath("ps"); err != nil {
+		t.Skip("ps not installed")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Skipf("os.Executable: %v", err)
+	}
+
+	// The test binary's full path is usually longer than the 15-character
+	// comm limit, which is exactly the case GetPaneCommandFull exists for.
+	got, err := processCommandName(os.Getpid())
+	if err != nil {
+		t.Fatalf("processCommandName: %v", err)
+	}
+	if want := filepath.Base(exe); got != want {
+		t.Errorf("processCommandName(self) = %q, want %q", got, want)
+	}
+}
+
+func TestGetPaneCommandFull(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	sessionName := "gt-test-fullcmd-" + strconv.Itoa(os.Getpid())
+	_ = tm.KillSession(sessionName)
+	if err := tm.NewSession(sessionName, ""); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+
+	full, err := tm.GetPaneCommandFull(sessionName)
+	if err != nil {
+		t.Fatalf("GetPaneCommandFull: %v", err)
+	}
+	if strings.Contains(full, "/") || full == "" {
+		t.Errorf("GetPaneCommandFull = %q, want bare executable name", full)
+	}
+}
+
+func TestParseTmuxVersion(t *testing.T) {
+	tests := []struct {
+		out          string
+		major, minor int
+		wantErr      bool
+	}{
+		{"tmux 3.3a\n", 3, 3, false},
+		{"tmux 2.2", 2, 2, false},
+		{"tmux 1.8", 1, 8, false},
+		{"tmux next-3.5", 3, 5, false},
+		{"tmux master", 99, 0, false},
+		{"", 0, 0, true},
+		{"screen 4.0", 0, 0, true},
+	}
+	for _, tt := range tests {
+		major, minor, err := parseTmuxVersion(tt.out)
+		if (err != nil) != tt.wantErr || major != tt.major || minor != tt.minor {
+			t.Errorf("parseTmuxVersion(%q) = %d, %d, %v; want %d, %d, err %v", tt.out, major, minor, err, tt.major, tt.minor, tt.wantErr)
+		}
+	}
+}
+
+func TestSetHookSessionClosed(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+	tm := NewTmux()
+	if major, _, err := tm.Version(); err != nil || major < 3 {
+		t.Skip("needs tmux 3.0+ for indexed hooks")
+	}
+
+	// Keep the server alive after the watched session closes.
+	keeper := "gt-test-hook-keeper-" + strconv.Itoa(os.Getpid())
+	watched := "gt-test-hook-watched-" + strconv.Itoa(os.Getpid())
+	for _, name := range []string{keeper, watched} {
+		if err := tm.NewSession(name, ""); err != nil {
+			t.Fatalf("NewSession(%s): %v", name, err)
+		}
+	}
+	defer func() { _ = tm.KillSession(keeper) }()
+	defer func() { _ = tm.KillSession(watched) }()
+
+	marker := filepath.Join(t.TempDir(), "closed")
+	hook := "session-closed[9917]"
+	cmd := `run-shell "echo '#{hook_session_name}' >> '` + marker + `'"`
+	if err := tm.SetHook(hook, cmd); err != nil {
+		t.Fatalf("SetHook: %v", err)
+	}
+	defer func() { _ = tm.UnsetHook(hook) }()
+
+	if err := tm.KillSession(watched); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(marker)
+		if strings.Contains(string(data), watched) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("session-closed hook did not run for %s (marker: %q)", watched, data)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	if err := tm.UnsetHook(hook); err != nil {
+		t.Fatalf("UnsetHook: %v", err)
+	}
+	out, _ := exec.Command("tmux", "show-hooks", "-g", "session-closed").Output()
+	if strings.Contains(string(out), marker) {
+		t.Errorf("hook still set after UnsetHook:\n%s", out)
+	}
+}
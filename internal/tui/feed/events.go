@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/KeithWyatt/gongshow/internal/beads"
+	"github.com/KeithWyatt/gongshow/internal/events"
 )
 
 // EventSource represents a source of events
@@ -212,9 +213,10 @@ func parseBeadContext(beadID string) (actor, rig, role string) {
 	return
 }
 
-// GtEventsSource reads events from ~/gt/.events.jsonl (gt activity log)
+// GtEventsSource reads events from the gt activity log: the town's
+// .events.jsonl and, with per-rig events, each rig's, merged in time order.
 type GtEventsSource struct {
-	file   *os.File
+	tailer *events.Tailer
 	events chan Event
 	cancel context.CancelFunc
 }
@@ -229,10 +231,11 @@ type GtEvent struct {
 	Visibility string                 `json:"visibility"`
 }
 
-// NewGtEventsSource creates a source that tails ~/gt/.events.jsonl
+// NewGtEventsSource creates a source that tails the town's events logs
+// through events.Tailer, following them across log rotations and picking
+// up rig logs as they appear.
 func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
-	eventsPath := filepath.Join(townRoot, ".events.jsonl")
-	file, err := os.Open(eventsPath)
+	tailer, err := events.NewTailer(townRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -240,7 +243,7 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	source := &GtEventsSource{
-		file:   file,
+		tailer: tailer,
 		events: make(chan Event, 100),
 		cancel: cancel,
 	}
@@ -254,10 +257,6 @@ func NewGtEventsSource(townRoot string) (*GtEventsSource, error) {
 func (s *GtEventsSource) tail(ctx context.Context) {
 	defer close(s.events)
 
-	// Seek to end for live tailing
-	_, _ = s.file.Seek(0, 2)
-
-	scanner := bufio.NewScanner(s.file)
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -266,8 +265,7 @@ func (s *GtEventsSource) tail(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			for scanner.Scan() {
-				line := scanner.Text()
+			for _, line := range s.tailer.Lines() {
 				if event := parseGtEventLine(line); event != nil {
 					select {
 					case s.events <- *event:
@@ -287,7 +285,7 @@ func (s *GtEventsSource) Events() <-chan Event {
 // Close stops the source
 func (s *GtEventsSource) Close() error {
 	s.cancel()
-	return s.file.Close()
+	return s.tailer.Close()
 }
 
 // parseGtEventLine parses a line from .events.jsonl
@@ -4,6 +4,7 @@ package util
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 )
 
 // AtomicWriteJSON writes JSON data to a file atomically.
@@ -39,3 +40,31 @@ func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
 
 	return nil
 }
+
+// AtomicWriteFileUnique is AtomicWriteFile for paths that several
+// processes or goroutines may write at once. Each call writes its own
+// temporary file, so concurrent writers don't truncate each other's
+// output; the last rename wins.
+func AtomicWriteFileUnique(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpFile := tmp.Name()
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpFile, perm)
+	}
+	if err == nil {
+		err = os.Rename(tmpFile, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile)
+		return err
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package util
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -357,3 +359,50 @@ func TestAtomicWriteFileLargeData(t *testing.T) {
 		}
 	}
 }
+
+func TestAtomicWriteFileUniqueConcurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	testFile := filepath.Join(tmpDir, "settings.json")
+	content := []byte(strings.Repeat("x", 64*1024))
+
+	// Every writer must succeed and the file must never be seen partial.
+	const numWriters = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, numWriters)
+	for i := 0; i < numWriters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := AtomicWriteFileUnique(testFile, content, 0600); err != nil {
+				errs <- err
+				return
+			}
+			got, err := os.ReadFile(testFile)
+			if err != nil {
+				errs <- err
+			} else if len(got) != len(content) {
+				errs <- fmt.Errorf("read %d bytes, want %d", len(got), len(content))
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	info, err := os.Stat(testFile)
+	if err != nil {
+		t.Fatalf("Stat error: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("ReadDir error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("entries = %v, want only settings.json", entries)
+	}
+}
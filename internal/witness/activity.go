@@ -0,0 +1,97 @@
+package witness
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/proc"
+	"github.com/KeithWyatt/gongshow/internal/tmux"
+)
+
+// Activity is what two samples of an agent session say it is doing.
+type Activity string
+
+const (
+	// ActivityWorking means the session's output changed between samples.
+	ActivityWorking Activity = "working"
+
+	// ActivityIdle means the output did not change and the session's
+	// processes were nearly idle, over too short a time to call it
+	// stalled: most likely the agent is waiting for input.
+	ActivityIdle Activity = "idle"
+
+	// ActivityStalled means the output did not change for at least
+	// StalledAfter and the session's processes were nearly idle: it is
+	// waiting on something, or nothing.
+	ActivityStalled Activity = "stalled"
+
+	// ActivitySpinning means the output did not change while the session's
+	// processes kept a core busy: most likely a loop. Nudging a spinning
+	// polecat rarely helps; it needs restarting.
+	ActivitySpinning Activity = "spinning"
+
+	// ActivityQuiet means the output did not change and CPU use could not
+	// be measured on this platform.
+	ActivityQuiet Activity = "quiet"
+)
+
+// SpinningCPUPercent is the CPU use, in percent of one core, above which
+// a session whose output isn't changing counts as spinning.
+const SpinningCPUPercent = 80.0
+
+// StalledAfter is how far apart samples with unchanged output and idle
+// processes must be for the session to count as stalled, not idle.
+const StalledAfter = time.Minute
+
+// activityCaptureLines is how much of the pane is compared between samples.
+const activityCaptureLines = 50
+
+// ActivitySample is a session's pane output and process tree stats at one
+// moment.
+type ActivitySample struct {
+	Output string
+	Stats  *proc.ProcStats
+}
+
+// SampleActivity captures a session's pane and samples the CPU and memory
+// of the pane's process tree (see proc.TreeStats).
+func SampleActivity(t *tmux.Tmux, session string) (*ActivitySample, error) {
+	pidStr, err := t.GetPanePID(session)
+	if err != nil {
+		return nil, fmt.Errorf("getting pane pid of %s: %w", session, err)
+	}
+	pid, err := strconv.Atoi(pidStr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing pane pid of %s: %w", session, err)
+	}
+	stats, err := proc.TreeStats(pid)
+	if err != nil {
+		return nil, err
+	}
+	output, err := t.CapturePane(session, activityCaptureLines)
+	if err != nil {
+		return nil, fmt.Errorf("capturing %s: %w", session, err)
+	}
+	return &ActivitySample{Output: output, Stats: stats}, nil
+}
+
+// DiagnoseActivity compares two samples of a session, prev taken first.
+// Changed output is working whatever the CPU; unchanged output is told
+// apart by the CPU used between the samples (see proc.StatsDelta) and,
+// when idle, by how far apart they are.
+func DiagnoseActivity(prev, cur *ActivitySample) Activity {
+	if prev.Output != cur.Output {
+		return ActivityWorking
+	}
+	if !prev.Stats.Supported || !cur.Stats.Supported {
+		return ActivityQuiet
+	}
+	if proc.StatsDelta(prev.Stats, cur.Stats) >= SpinningCPUPercent {
+		return ActivitySpinning
+	}
+	if cur.Stats.SampledAt.Sub(prev.Stats.SampledAt) < StalledAfter {
+		return ActivityIdle
+	}
+	return ActivityStalled
+}
@@ -0,0 +1,43 @@
+package witness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/proc"
+)
+
+func TestDiagnoseActivity(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	sample := func(output string, cpu time.Duration, after time.Duration) *ActivitySample {
+		return &ActivitySample{
+			Output: output,
+			Stats:  &proc.ProcStats{UserTime: cpu, SampledAt: at.Add(after), Supported: true},
+		}
+	}
+	unsupported := func(output string) *ActivitySample {
+		return &ActivitySample{Output: output, Stats: &proc.ProcStats{SampledAt: at}}
+	}
+
+	tests := []struct {
+		name      string
+		prev, cur *ActivitySample
+		want      Activity
+	}{
+		{"output changed", sample("a", 0, 0), sample("ab", 0, 10*time.Second), ActivityWorking},
+		{"output changed, busy", sample("a", 0, 0), sample("ab", 10*time.Second, 10*time.Second), ActivityWorking},
+		{"no output, idle", sample("a", 0, 0), sample("a", 100*time.Millisecond, 10*time.Second), ActivityIdle},
+		{"no output, idle for long", sample("a", 0, 0), sample("a", time.Second, 2*time.Minute), ActivityStalled},
+		{"no output, a core busy", sample("a", 0, 0), sample("a", 10*time.Second, 10*time.Second), ActivitySpinning},
+		{"no output, just under", sample("a", 0, 0), sample("a", 7*time.Second, 10*time.Second), ActivityIdle},
+		{"no output, CPU unknown", unsupported("a"), unsupported("a"), ActivityQuiet},
+		{"output changed, CPU unknown", unsupported("a"), unsupported("b"), ActivityWorking},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiagnoseActivity(tt.prev, tt.cur); got != tt.want {
+				t.Errorf("DiagnoseActivity() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
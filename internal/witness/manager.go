@@ -82,19 +82,24 @@ func (m *Manager) Status() (*Witness, error) {
 }
 
 // witnessDir returns the working directory for the witness.
-// Prefers witness/rig/, falls back to witness/, then rig root.
 func (m *Manager) witnessDir() string {
-	witnessRigDir := filepath.Join(m.rig.Path, "witness", "rig")
+	return WorkDir(m.rig.Path)
+}
+
+// WorkDir returns the working directory of the witness of the rig at rigPath.
+// Prefers witness/rig/, falls back to witness/, then rig root.
+func WorkDir(rigPath string) string {
+	witnessRigDir := filepath.Join(rigPath, "witness", "rig")
 	if _, err := os.Stat(witnessRigDir); err == nil {
 		return witnessRigDir
 	}
 
-	witnessDir := filepath.Join(m.rig.Path, "witness")
+	witnessDir := filepath.Join(rigPath, "witness")
 	if _, err := os.Stat(witnessDir); err == nil {
 		return witnessDir
 	}
 
-	return m.rig.Path
+	return rigPath
 }
 
 // Start starts the witness.
@@ -177,18 +182,10 @@ func (m *Manager) Start(foreground bool, agentOverride string, envOverrides []st
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	envVars := config.AgentEnv(config.AgentEnvConfig{
-		Role:     "witness",
-		Rig:      m.rig.Name,
-		TownRoot: townRoot,
-	})
+	envVars := beads.AgentEnv(witnessEnvConfig(m.rig.Name, townRoot), sessionID, roleConfig)
 	for k, v := range envVars {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
-	// Apply role config env vars if present (non-fatal).
-	for key, value := range roleConfigEnvVars(roleConfig, townRoot, m.rig.Name) {
-		_ = t.SetEnvironment(sessionID, key, value)
-	}
 	// Apply CLI env overrides (highest priority, non-fatal).
 	for _, override := range envOverrides {
 		if key, value, ok := strings.Cut(override, "="); ok {
@@ -257,15 +254,15 @@ func (m *Manager) townRoot() string {
 	return townRoot
 }
 
-func roleConfigEnvVars(roleConfig *beads.RoleConfig, townRoot, rigName string) map[string]string {
-	if roleConfig == nil || len(roleConfig.EnvVars) == 0 {
-		return nil
-	}
-	expanded := make(map[string]string, len(roleConfig.EnvVars))
-	for key, value := range roleConfig.EnvVars {
-		expanded[key] = beads.ExpandRolePattern(value, townRoot, rigName, "", "witness")
-	}
-	return expanded
+// witnessEnvConfig returns the AgentEnv config of a rig's witness.
+func witnessEnvConfig(rigName, townRoot string) config.AgentEnvConfig {
+	return config.AgentEnvConfig{Role: "witness", Rig: rigName, TownRoot: townRoot}
+}
+
+// BuildStartCommand returns the command a rig's witness is started with:
+// the role config's start_command, expanded, or the configured agent.
+func BuildStartCommand(rigPath, rigName, townRoot string, roleConfig *beads.RoleConfig) (string, error) {
+	return buildWitnessStartCommand(rigPath, rigName, townRoot, "", roleConfig)
 }
 
 func buildWitnessStartCommand(rigPath, rigName, townRoot, agentOverride string, roleConfig *beads.RoleConfig) (string, error) {
@@ -273,7 +270,8 @@ func buildWitnessStartCommand(rigPath, rigName, townRoot, agentOverride string,
 		roleConfig = nil
 	}
 	if roleConfig != nil && roleConfig.StartCommand != "" {
-		return beads.ExpandRolePattern(roleConfig.StartCommand, townRoot, rigName, "", "witness"), nil
+		vars := beads.AgentRoleVars(witnessEnvConfig(rigName, townRoot), fmt.Sprintf("gt-%s-witness", rigName))
+		return beads.ExpandRoleVars(roleConfig.StartCommand, vars), nil
 	}
 	command, err := config.BuildAgentStartupCommandWithAgentOverride("witness", rigName, townRoot, rigPath, "", agentOverride)
 	if err != nil {
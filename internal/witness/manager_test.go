@@ -23,6 +23,37 @@ func TestBuildWitnessStartCommand_UsesRoleConfig(t *testing.T) {
 	}
 }
 
+func TestBuildWitnessStartCommand_ExpandsBuiltins(t *testing.T) {
+	t.Setenv("GT_TEST_WITNESS_MODEL", "opus")
+	roleConfig := &beads.RoleConfig{
+		StartCommand: "cd {rigdir} && exec run --config {configdir} --beads {beadsdir} --session {sessionname} --actor {actor} --model {env:GT_TEST_WITNESS_MODEL}",
+	}
+
+	got, err := buildWitnessStartCommand("/town/gongshow", "gongshow", "/town", "", roleConfig)
+	if err != nil {
+		t.Fatalf("buildWitnessStartCommand: %v", err)
+	}
+
+	want := "cd /town/gongshow && exec run --config /town/config --beads /town/gongshow/.beads --session gt-gongshow-witness --actor gongshow/witness --model opus"
+	if got != want {
+		t.Errorf("buildWitnessStartCommand = %q, want %q", got, want)
+	}
+}
+
+func TestBuildWitnessStartCommand_KeepsUnknownPlaceholders(t *testing.T) {
+	roleConfig := &beads.RoleConfig{
+		StartCommand: "exec run --rig {rig} --mystery {nope}",
+	}
+
+	got, err := buildWitnessStartCommand("/town/rig", "gongshow", "/town", "", roleConfig)
+	if err != nil {
+		t.Fatalf("buildWitnessStartCommand: %v", err)
+	}
+	if want := "exec run --rig gongshow --mystery {nope}"; got != want {
+		t.Errorf("buildWitnessStartCommand = %q, want %q", got, want)
+	}
+}
+
 func TestBuildWitnessStartCommand_DefaultsToRuntime(t *testing.T) {
 	got, err := buildWitnessStartCommand("/town/rig", "gongshow", "/town", "", nil)
 	if err != nil {
@@ -0,0 +1,164 @@
+package witness
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// DefaultPluginTimeout bounds a single patrol plugin run.
+const DefaultPluginTimeout = 60 * time.Second
+
+// PluginStatusError is the status recorded for a plugin that failed to run
+// or produced unusable output.
+const PluginStatusError = "error"
+
+// PatrolPlugin is an extra check run by the witness on each patrol.
+type PatrolPlugin interface {
+	Name() string
+	Run(ctx context.Context, rigDir string) (status string, details map[string]interface{}, err error)
+}
+
+// ExecPlugin is a patrol plugin implemented by an external executable.
+//
+// The executable runs in the rig directory with the rig directory as its only
+// argument (also exported as GT_RIG_DIR), and must print a JSON object to
+// stdout:
+//
+//	{"status": "ok", "details": {"free_gb": 12}}
+//
+// A non-zero exit status is a failure; stderr is included in the error.
+type ExecPlugin struct {
+	Path string
+}
+
+// Name returns the executable's base name without its extension.
+func (p *ExecPlugin) Name() string {
+	base := filepath.Base(p.Path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// pluginOutput is what an ExecPlugin prints on stdout.
+type pluginOutput struct {
+	Status  string                 `json:"status"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Run executes the plugin and parses its output.
+func (p *ExecPlugin) Run(ctx context.Context, rigDir string) (string, map[string]interface{}, error) {
+	cmd := exec.CommandContext(ctx, p.Path, rigDir) //nolint:gosec // G204: plugin paths come from town config
+	cmd.Dir = rigDir
+	cmd.Env = append(os.Environ(), "GT_RIG_DIR="+rigDir)
+	cmd.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", nil, fmt.Errorf("timed out: %w", ctx.Err())
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", nil, err
+	}
+
+	var out pluginOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return "", nil, fmt.Errorf("parsing output: %w", err)
+	}
+	if out.Status == "" {
+		return "", nil, errors.New("output has no status")
+	}
+	return out.Status, out.Details, nil
+}
+
+// PatrolPluginsConfigPath returns the path of the patrol plugin list in a town.
+func PatrolPluginsConfigPath(townRoot string) string {
+	return filepath.Join(townRoot, "config", "patrol-plugins.json")
+}
+
+// LoadPatrolPlugins reads the town's patrol plugin list: a JSON array of
+// executable paths, relative paths being resolved against the town root.
+// A missing file means no plugins.
+func LoadPatrolPlugins(townRoot string) ([]PatrolPlugin, error) {
+	data, err := os.ReadFile(PatrolPluginsConfigPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading patrol plugins: %w", err)
+	}
+
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return nil, fmt.Errorf("parsing patrol plugins: %w", err)
+	}
+
+	plugins := make([]PatrolPlugin, 0, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(townRoot, path)
+		}
+		plugins = append(plugins, &ExecPlugin{Path: path})
+	}
+	return plugins, nil
+}
+
+// PatrolPluginResult is the outcome of one plugin run.
+type PatrolPluginResult struct {
+	Plugin  string                 `json:"plugin"`
+	Status  string                 `json:"status"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	Err     error                  `json:"-"`
+}
+
+// RunPatrolPlugins runs each plugin in turn against rigDir, each bounded by
+// DefaultPluginTimeout. A failing plugin is recorded with PluginStatusError
+// and doesn't stop the others.
+func RunPatrolPlugins(ctx context.Context, plugins []PatrolPlugin, rigDir string) []PatrolPluginResult {
+	results := make([]PatrolPluginResult, 0, len(plugins))
+	for _, plugin := range plugins {
+		runCtx, cancel := context.WithTimeout(ctx, DefaultPluginTimeout)
+		status, details, err := plugin.Run(runCtx, rigDir)
+		cancel()
+
+		result := PatrolPluginResult{Plugin: plugin.Name(), Status: status, Details: details, Err: err}
+		if err != nil {
+			result.Status = PluginStatusError
+			result.Details = map[string]interface{}{"error": err.Error()}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// RunPatrolPlugins runs the town's patrol plugins against this rig and logs a
+// patrol_plugin_result event for each.
+func (m *Manager) RunPatrolPlugins(ctx context.Context) ([]PatrolPluginResult, error) {
+	plugins, err := LoadPatrolPlugins(m.townRoot())
+	if err != nil {
+		return nil, err
+	}
+
+	results := RunPatrolPlugins(ctx, plugins, m.rig.Path)
+	actor := m.rig.Name + "/witness"
+	for _, r := range results {
+		_ = events.LogFeed(events.TypePatrolPluginResult, actor,
+			events.PatrolPluginPayload(m.rig.Name, r.Plugin, r.Status, r.Details))
+	}
+	return results, nil
+}
@@ -0,0 +1,213 @@
+package witness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/rig"
+)
+
+// writePlugin writes a mock plugin executable with the given shell body.
+func writePlugin(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecPlugin_Run(t *testing.T) {
+	dir := t.TempDir()
+	rigDir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  string
+		wantDetails map[string]interface{}
+		wantErr     string
+	}{
+		{
+			name:        "ok with details",
+			body:        `echo '{"status": "ok", "details": {"rig_dir": "'"$1"'", "env": "'"$GT_RIG_DIR"'", "cwd": "'"$(pwd)"'"}}'`,
+			wantStatus:  "ok",
+			wantDetails: map[string]interface{}{"rig_dir": rigDir, "env": rigDir, "cwd": rigDir},
+		},
+		{
+			name:       "status only",
+			body:       `echo '{"status": "warn"}'`,
+			wantStatus: "warn",
+		},
+		{
+			name:    "non-zero exit",
+			body:    "echo 'disk unreadable' >&2; exit 3",
+			wantErr: "disk unreadable",
+		},
+		{
+			name:    "not json",
+			body:    "echo all good",
+			wantErr: "parsing output",
+		},
+		{
+			name:    "missing status",
+			body:    `echo '{"details": {}}'`,
+			wantErr: "no status",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &ExecPlugin{Path: writePlugin(t, dir, fmt.Sprintf("plugin%d.sh", i), tt.body)}
+			status, details, err := plugin.Run(context.Background(), rigDir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			for k, want := range tt.wantDetails {
+				if got := details[k]; got != want {
+					t.Errorf("details[%q] = %v, want %v", k, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExecPlugin_Timeout(t *testing.T) {
+	plugin := &ExecPlugin{Path: writePlugin(t, t.TempDir(), "slow.sh", "sleep 10")}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := plugin.Run(ctx, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run took %s after timeout", elapsed)
+	}
+}
+
+func TestExecPlugin_Name(t *testing.T) {
+	if got := (&ExecPlugin{Path: "/opt/plugins/disk-space.sh"}).Name(); got != "disk-space" {
+		t.Errorf("Name = %q, want %q", got, "disk-space")
+	}
+}
+
+func TestLoadPatrolPlugins(t *testing.T) {
+	townRoot := t.TempDir()
+
+	plugins, err := LoadPatrolPlugins(townRoot)
+	if err != nil || plugins != nil {
+		t.Fatalf("missing config: plugins = %v, err = %v", plugins, err)
+	}
+
+	writeConfig(t, townRoot, `["plugins/disk.sh", "/usr/local/bin/ci-check", ""]`)
+	plugins, err = LoadPatrolPlugins(townRoot)
+	if err != nil {
+		t.Fatalf("LoadPatrolPlugins: %v", err)
+	}
+	want := []string{filepath.Join(townRoot, "plugins", "disk.sh"), "/usr/local/bin/ci-check"}
+	if len(plugins) != len(want) {
+		t.Fatalf("got %d plugins, want %d", len(plugins), len(want))
+	}
+	for i, p := range plugins {
+		if got := p.(*ExecPlugin).Path; got != want[i] {
+			t.Errorf("plugin %d path = %q, want %q", i, got, want[i])
+		}
+	}
+
+	writeConfig(t, townRoot, `{"plugins": []}`)
+	if _, err := LoadPatrolPlugins(townRoot); err == nil {
+		t.Error("expected error for non-list config")
+	}
+}
+
+func writeConfig(t *testing.T, townRoot, content string) {
+	t.Helper()
+	path := PatrolPluginsConfigPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestManagerRunPatrolPlugins(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rigDir := filepath.Join(townRoot, "gongshow")
+	if err := os.MkdirAll(rigDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	pluginDir := filepath.Join(townRoot, "plugins")
+	if err := os.MkdirAll(pluginDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writePlugin(t, pluginDir, "disk.sh", `echo '{"status": "ok", "details": {"free_gb": 12}}'`)
+	writePlugin(t, pluginDir, "broken.sh", "exit 1")
+	writePlugin(t, pluginDir, "ci.sh", `echo '{"status": "fail"}'`)
+	writeConfig(t, townRoot, `["plugins/disk.sh", "plugins/broken.sh", "plugins/ci.sh"]`)
+
+	m := NewManager(&rig.Rig{Name: "gongshow", Path: rigDir})
+	results, err := m.RunPatrolPlugins(context.Background())
+	if err != nil {
+		t.Fatalf("RunPatrolPlugins: %v", err)
+	}
+
+	wantStatus := []string{"ok", PluginStatusError, "fail"}
+	if len(results) != len(wantStatus) {
+		t.Fatalf("got %d results, want %d", len(results), len(wantStatus))
+	}
+	for i, r := range results {
+		if r.Status != wantStatus[i] {
+			t.Errorf("%s status = %q, want %q", r.Plugin, r.Status, wantStatus[i])
+		}
+	}
+	if results[1].Err == nil || results[1].Details["error"] == nil {
+		t.Errorf("broken plugin result = %+v, want error", results[1])
+	}
+
+	data, err := os.ReadFile(filepath.Join(townRoot, events.EventsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d events, want 3:\n%s", len(lines), data)
+	}
+	var e events.Event
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != events.TypePatrolPluginResult || e.Actor != "gongshow/witness" ||
+		e.Payload["plugin"] != "disk" || e.Payload["rig"] != "gongshow" {
+		t.Errorf("event = %+v", e)
+	}
+	if details, _ := e.Payload["details"].(map[string]interface{}); details["free_gb"] != float64(12) {
+		t.Errorf("details = %v", e.Payload["details"])
+	}
+}
@@ -0,0 +1,360 @@
+package witness
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+)
+
+// Report formats for GenerateReport.
+const (
+	ReportText     = "text"
+	ReportMarkdown = "markdown"
+	ReportJSON     = "json"
+)
+
+// ReportOptions controls GenerateReport.
+type ReportOptions struct {
+	// Format is text, markdown, or json (default: text).
+	Format string
+
+	// Since limits the report to patrols within this long ago (0 = all).
+	Since time.Duration
+
+	// IncludeNudges lists the nudges sent during each patrol.
+	IncludeNudges bool
+
+	// IncludeEscalations lists the escalations raised during each patrol.
+	IncludeEscalations bool
+}
+
+// PatrolRecord is one witness patrol cycle reconstructed from the events log.
+type PatrolRecord struct {
+	Rig          string         `json:"rig"`
+	StartedAt    time.Time      `json:"started_at"`
+	CompletedAt  *time.Time     `json:"completed_at,omitempty"`
+	PolecatCount int            `json:"polecat_count"`
+	Message      string         `json:"message,omitempty"`
+	Nudges       []PatrolAction `json:"nudges,omitempty"`
+	Escalations  []PatrolAction `json:"escalations,omitempty"`
+}
+
+// PatrolAction is a nudge or escalation issued during a patrol.
+type PatrolAction struct {
+	At     time.Time `json:"at"`
+	Target string    `json:"target"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Duration returns how long the patrol took, or 0 if it never completed.
+func (r *PatrolRecord) Duration() time.Duration {
+	if r.CompletedAt == nil {
+		return 0
+	}
+	return r.CompletedAt.Sub(r.StartedAt)
+}
+
+// PatrolHistory returns this rig's patrols from the town events log, oldest
+// first. Patrols are delimited by patrol_started/patrol_complete events;
+// nudges and escalations are attributed to the patrol in progress when they
+// were logged. since limits how far back to look (0 = all).
+func (m *Manager) PatrolHistory(since time.Duration) ([]PatrolRecord, error) {
+	var cutoff time.Time
+	if since > 0 {
+		cutoff = time.Now().Add(-since)
+	}
+	return readPatrolHistory(m.townRoot(), m.rig.Name, cutoff)
+}
+
+func readPatrolHistory(townRoot, rigName string, cutoff time.Time) ([]PatrolRecord, error) {
+	f, err := events.OpenLog(townRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening events log: %w", err)
+	}
+	defer f.Close()
+
+	var records []PatrolRecord
+	open := -1 // index of the patrol in progress
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // skip malformed lines
+		}
+		if payloadString(e.Payload, "rig") != rigName {
+			continue
+		}
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil || ts.Before(cutoff) {
+			continue
+		}
+
+		// current returns the patrol in progress, opening one if an action
+		// was logged outside a started patrol.
+		current := func() *PatrolRecord {
+			if open < 0 {
+				records = append(records, PatrolRecord{Rig: rigName, StartedAt: ts})
+				open = len(records) - 1
+			}
+			return &records[open]
+		}
+
+		switch e.Type {
+		case events.TypePatrolStarted:
+			records = append(records, PatrolRecord{
+				Rig:          rigName,
+				StartedAt:    ts,
+				PolecatCount: payloadInt(e.Payload, "polecat_count"),
+				Message:      payloadString(e.Payload, "message"),
+			})
+			open = len(records) - 1
+
+		case events.TypePatrolComplete:
+			r := current()
+			completed := ts
+			r.CompletedAt = &completed
+			if n := payloadInt(e.Payload, "polecat_count"); n > 0 {
+				r.PolecatCount = n
+			}
+			if msg := payloadString(e.Payload, "message"); msg != "" {
+				r.Message = msg
+			}
+			open = -1
+
+		case events.TypePolecatNudged:
+			r := current()
+			r.Nudges = append(r.Nudges, PatrolAction{
+				At:     ts,
+				Target: payloadString(e.Payload, "target"),
+				Reason: payloadString(e.Payload, "reason"),
+			})
+
+		case events.TypeEscalationSent:
+			r := current()
+			r.Escalations = append(r.Escalations, PatrolAction{
+				At:     ts,
+				Target: payloadString(e.Payload, "target"),
+				Reason: payloadString(e.Payload, "reason"),
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading events log: %w", err)
+	}
+	return records, nil
+}
+
+// GenerateReport renders the rig's patrol history in the requested format.
+func (m *Manager) GenerateReport(opts ReportOptions) (string, error) {
+	records, err := m.PatrolHistory(opts.Since)
+	if err != nil {
+		return "", err
+	}
+	return formatReport(m.rig.Name, records, opts)
+}
+
+func formatReport(rigName string, records []PatrolRecord, opts ReportOptions) (string, error) {
+	if !opts.IncludeNudges || !opts.IncludeEscalations {
+		trimmed := make([]PatrolRecord, len(records))
+		for i, r := range records {
+			if !opts.IncludeNudges {
+				r.Nudges = nil
+			}
+			if !opts.IncludeEscalations {
+				r.Escalations = nil
+			}
+			trimmed[i] = r
+		}
+		records = trimmed
+	}
+
+	switch opts.Format {
+	case "", ReportText:
+		return formatReportText(rigName, records, opts), nil
+	case ReportMarkdown:
+		return formatReportMarkdown(rigName, records, opts), nil
+	case ReportJSON:
+		if records == nil {
+			records = []PatrolRecord{}
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unknown report format %q: use text, markdown, or json", opts.Format)
+	}
+}
+
+// reportSummary holds the totals shown at the top of text and markdown reports.
+type reportSummary struct {
+	patrols, completed, nudges, escalations int
+	avgDuration                             time.Duration
+}
+
+func summarize(records []PatrolRecord) reportSummary {
+	var s reportSummary
+	var total time.Duration
+	for _, r := range records {
+		s.patrols++
+		if r.CompletedAt != nil {
+			s.completed++
+			total += r.Duration()
+		}
+		s.nudges += len(r.Nudges)
+		s.escalations += len(r.Escalations)
+	}
+	if s.completed > 0 {
+		s.avgDuration = total / time.Duration(s.completed)
+	}
+	return s
+}
+
+func reportPeriod(since time.Duration) string {
+	if since <= 0 {
+		return "all time"
+	}
+	return "last " + formatReportDuration(since)
+}
+
+func formatReportDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d >= 24*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+func formatReportText(rigName string, records []PatrolRecord, opts ReportOptions) string {
+	var b strings.Builder
+	s := summarize(records)
+
+	fmt.Fprintf(&b, "Witness patrol report: %s (%s)\n\n", rigName, reportPeriod(opts.Since))
+	fmt.Fprintf(&b, "Patrols:      %d (%d completed)\n", s.patrols, s.completed)
+	fmt.Fprintf(&b, "Avg duration: %s\n", formatReportDuration(s.avgDuration))
+	if opts.IncludeNudges {
+		fmt.Fprintf(&b, "Nudges:       %d\n", s.nudges)
+	}
+	if opts.IncludeEscalations {
+		fmt.Fprintf(&b, "Escalations:  %d\n", s.escalations)
+	}
+
+	if len(records) == 0 {
+		b.WriteString("\nNo patrols recorded.\n")
+		return b.String()
+	}
+
+	b.WriteString("\nPatrols\n")
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tDURATION\tPOLECATS\tNUDGES\tESCALATIONS\tMESSAGE")
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n",
+			r.StartedAt.Local().Format("2006-01-02 15:04"), formatReportDuration(r.Duration()),
+			r.PolecatCount, len(r.Nudges), len(r.Escalations), r.Message)
+	}
+	_ = w.Flush()
+
+	writeActions := func(title string, pick func(PatrolRecord) []PatrolAction) {
+		fmt.Fprintf(&b, "\n%s\n", title)
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tTARGET\tREASON")
+		for _, r := range records {
+			for _, a := range pick(r) {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", a.At.Local().Format("2006-01-02 15:04"), a.Target, a.Reason)
+			}
+		}
+		_ = w.Flush()
+	}
+	if opts.IncludeNudges && s.nudges > 0 {
+		writeActions("Nudges", func(r PatrolRecord) []PatrolAction { return r.Nudges })
+	}
+	if opts.IncludeEscalations && s.escalations > 0 {
+		writeActions("Escalations", func(r PatrolRecord) []PatrolAction { return r.Escalations })
+	}
+	return b.String()
+}
+
+func formatReportMarkdown(rigName string, records []PatrolRecord, opts ReportOptions) string {
+	var b strings.Builder
+	s := summarize(records)
+
+	fmt.Fprintf(&b, "## Witness patrol report: %s\n\n", rigName)
+	fmt.Fprintf(&b, "- **Period:** %s\n", reportPeriod(opts.Since))
+	fmt.Fprintf(&b, "- **Patrols:** %d (%d completed)\n", s.patrols, s.completed)
+	fmt.Fprintf(&b, "- **Avg duration:** %s\n", formatReportDuration(s.avgDuration))
+	if opts.IncludeNudges {
+		fmt.Fprintf(&b, "- **Nudges:** %d\n", s.nudges)
+	}
+	if opts.IncludeEscalations {
+		fmt.Fprintf(&b, "- **Escalations:** %d\n", s.escalations)
+	}
+
+	if len(records) == 0 {
+		b.WriteString("\n_No patrols recorded._\n")
+		return b.String()
+	}
+
+	b.WriteString("\n### Patrols\n\n")
+	b.WriteString("| Started | Duration | Polecats | Nudges | Escalations | Message |\n")
+	b.WriteString("|---|---|---:|---:|---:|---|\n")
+	for _, r := range records {
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %d | %s |\n",
+			r.StartedAt.UTC().Format(time.RFC3339), formatReportDuration(r.Duration()),
+			r.PolecatCount, len(r.Nudges), len(r.Escalations), markdownCell(r.Message))
+	}
+
+	writeActions := func(title string, pick func(PatrolRecord) []PatrolAction) {
+		fmt.Fprintf(&b, "\n### %s\n\n", title)
+		b.WriteString("| Time | Target | Reason |\n")
+		b.WriteString("|---|---|---|\n")
+		for _, r := range records {
+			for _, a := range pick(r) {
+				fmt.Fprintf(&b, "| %s | %s | %s |\n",
+					a.At.UTC().Format(time.RFC3339), markdownCell(a.Target), markdownCell(a.Reason))
+			}
+		}
+	}
+	if opts.IncludeNudges && s.nudges > 0 {
+		writeActions("Nudges", func(r PatrolRecord) []PatrolAction { return r.Nudges })
+	}
+	if opts.IncludeEscalations && s.escalations > 0 {
+		writeActions("Escalations", func(r PatrolRecord) []PatrolAction { return r.Escalations })
+	}
+	return b.String()
+}
+
+// markdownCell escapes text for a markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func payloadString(p map[string]interface{}, key string) string {
+	s, _ := p[key].(string)
+	return s
+}
+
+func payloadInt(p map[string]interface{}, key string) int {
+	switch v := p[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
@@ -0,0 +1,170 @@
+package witness
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KeithWyatt/gongshow/internal/events"
+	"github.com/KeithWyatt/gongshow/internal/rig"
+)
+
+// newReportManager returns a manager whose events log holds two patrols for
+// "gongshow" (one with a nudge and an escalation), one old patrol, and noise
+// from another rig.
+func newReportManager(t *testing.T) *Manager {
+	t.Helper()
+	dir := t.TempDir()
+	now := time.Now().UTC()
+
+	lines := []struct {
+		ago     time.Duration
+		typ     string
+		payload map[string]interface{}
+	}{
+		{30 * 24 * time.Hour, events.TypePatrolStarted, events.PatrolPayload("gongshow", 2, "")},
+		{30*24*time.Hour - time.Minute, events.TypePatrolComplete, events.PatrolPayload("gongshow", 2, "old patrol")},
+		{2 * time.Hour, events.TypePatrolStarted, events.PatrolPayload("gongshow", 3, "")},
+		{2*time.Hour - 10*time.Second, events.TypePolecatNudged, events.NudgePayload("gongshow", "Toast", "idle 20m")},
+		{2*time.Hour - 20*time.Second, events.TypeEscalationSent, events.EscalationPayload("gongshow", "Nux", "mayor", "stuck | looping")},
+		{2*time.Hour - 90*time.Second, events.TypePatrolComplete, events.PatrolPayload("gongshow", 3, "1 nudged")},
+		{time.Hour, events.TypePatrolStarted, events.PatrolPayload("other", 1, "")},
+		{30 * time.Minute, events.TypePatrolStarted, events.PatrolPayload("gongshow", 3, "")},
+		{30*time.Minute - time.Minute, events.TypePatrolComplete, events.PatrolPayload("gongshow", 3, "All polecats healthy")},
+	}
+
+	var data []byte
+	for _, l := range lines {
+		line, err := json.Marshal(events.Event{
+			Timestamp:  now.Add(-l.ago).Format(time.RFC3339),
+			Source:     "gt",
+			Type:       l.typ,
+			Actor:      "gongshow/witness",
+			Payload:    l.payload,
+			Visibility: events.VisibilityFeed,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	data = append(data, "not json\n"...)
+	if err := os.WriteFile(filepath.Join(dir, events.EventsFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return NewManager(&rig.Rig{Name: "gongshow", Path: dir})
+}
+
+func TestPatrolHistory(t *testing.T) {
+	m := newReportManager(t)
+
+	all, err := m.PatrolHistory(0)
+	if err != nil {
+		t.Fatalf("PatrolHistory: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("got %d patrols, want 3", len(all))
+	}
+
+	recent, err := m.PatrolHistory(7 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("PatrolHistory: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Fatalf("got %d recent patrols, want 2", len(recent))
+	}
+	r := recent[0]
+	if r.PolecatCount != 3 || r.Message != "1 nudged" || r.Duration() != 90*time.Second {
+		t.Errorf("unexpected record: %+v (duration %v)", r, r.Duration())
+	}
+	if len(r.Nudges) != 1 || r.Nudges[0].Target != "Toast" || len(r.Escalations) != 1 {
+		t.Errorf("actions not attributed to patrol: %+v", r)
+	}
+}
+
+func TestGenerateReportText(t *testing.T) {
+	m := newReportManager(t)
+
+	out, err := m.GenerateReport(ReportOptions{Format: ReportText, Since: 7 * 24 * time.Hour, IncludeNudges: true, IncludeEscalations: true})
+	if err != nil {
+		t.Fatalf("GenerateReport: %v", err)
+	}
+	for _, want := range []string{
+		"Witness patrol report: gongshow (last 7d)",
+		"Patrols:      2 (2 completed)",
+		"Nudges:       1",
+		"Escalations:  1",
+		"STARTED", "DURATION", "POLECATS",
+		"All polecats healthy",
+		"\nNudges\n", "idle 20m",
+		"\nEscalations\n", "Nux",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "old patrol") {
+		t.Errorf("text report includes patrol outside --since:\n%s", out)
+	}
+}
+
+func TestGenerateReportMarkdown(t *testing.T) {
+	m := newReportManager(t)
+
+	out, err := m.GenerateReport(ReportOptions{Format: ReportMarkdown, IncludeEscalations: true})
+	if err != nil {
+		t.Fatalf("GenerateReport: %v", err)
+	}
+	for _, want := range []string{
+		"## Witness patrol report: gongshow",
+		"- **Period:** all time",
+		"- **Patrols:** 3 (3 completed)",
+		"### Patrols",
+		"| Started | Duration | Polecats | Nudges | Escalations | Message |",
+		"|---|",
+		"### Escalations",
+		`stuck \| looping`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("markdown report missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "### Nudges") {
+		t.Errorf("markdown report includes nudges without IncludeNudges:\n%s", out)
+	}
+}
+
+func TestGenerateReportJSON(t *testing.T) {
+	m := newReportManager(t)
+
+	out, err := m.GenerateReport(ReportOptions{Format: ReportJSON, Since: 7 * 24 * time.Hour, IncludeNudges: true})
+	if err != nil {
+		t.Fatalf("GenerateReport: %v", err)
+	}
+
+	var records []PatrolRecord
+	if err := json.Unmarshal([]byte(out), &records); err != nil {
+		t.Fatalf("report is not []PatrolRecord JSON: %v\n%s", err, out)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if len(records[0].Nudges) != 1 {
+		t.Errorf("expected nudges in JSON, got %+v", records[0])
+	}
+	if len(records[0].Escalations) != 0 {
+		t.Errorf("escalations should be omitted without IncludeEscalations, got %+v", records[0].Escalations)
+	}
+}
+
+func TestGenerateReportUnknownFormat(t *testing.T) {
+	m := newReportManager(t)
+	if _, err := m.GenerateReport(ReportOptions{Format: "csv"}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}